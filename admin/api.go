@@ -47,6 +47,35 @@ type ConnStatsResp struct {
 	Stats           []*connlimit.HostConnStat
 }
 
+// OperationRecordsResp 操作记录查询结果
+type OperationRecordsResp struct {
+	Total   uint32               `json:"total"`
+	Size    uint32               `json:"size"`
+	Records []*model.RecordEntry `json:"records"`
+}
+
+// XDSTrustBundleResp xDS 内置 CA 的信任包
+type XDSTrustBundleResp struct {
+	// TrustBundle PEM 格式的根证书列表，用于校验内置 CA 签发的工作负载证书
+	TrustBundle string `json:"trust_bundle"`
+	// Signer 当前签发工作负载证书使用的 CA，root 或者 intermediate
+	Signer string `json:"signer"`
+	// Subject 签发 CA 证书的主题
+	Subject string `json:"subject"`
+	// ExpireAt 签发 CA 证书的过期时间
+	ExpireAt string `json:"expire_at"`
+}
+
+// XDSIntermediateCAReq 导入中间 CA 的请求，均为 PEM 格式
+type XDSIntermediateCAReq struct {
+	// Cert 中间 CA 证书
+	Cert string `json:"cert"`
+	// Key 中间 CA 私钥
+	Key string `json:"key"`
+	// Chain 上级证书链，最后一个必须是根证书
+	Chain string `json:"chain"`
+}
+
 // AdminOperateServer Maintain related operation
 type AdminOperateServer interface {
 	// GetServerConnections Get connection count
@@ -75,4 +104,10 @@ type AdminOperateServer interface {
 	ReleaseLeaderElection(ctx context.Context, electKey string) error
 	// GetCMDBInfo get cmdb info
 	GetCMDBInfo(ctx context.Context) ([]model.LocationView, error)
+	// GetOperationRecords Search operation records by operator, resource, namespace and time range
+	GetOperationRecords(ctx context.Context, query map[string]string) (*OperationRecordsResp, error)
+	// GetXDSTrustBundle Get the trust bundle of the xDS built-in CA
+	GetXDSTrustBundle(ctx context.Context) (*XDSTrustBundleResp, error)
+	// ImportXDSIntermediateCA Import an intermediate CA to sign xDS workload certificates
+	ImportXDSIntermediateCA(ctx context.Context, req *XDSIntermediateCAReq) error
 }
@@ -0,0 +1,87 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package job
+
+import (
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/polarismesh/polaris/store"
+)
+
+type CleanOperationRecordsJobConfig struct {
+	// Retention 操作记录的保留时长
+	Retention time.Duration `mapstructure:"retention"`
+	// CleanInterval 清理任务的执行间隔
+	CleanInterval time.Duration `mapstructure:"cleanInterval"`
+}
+
+type cleanOperationRecordsJob struct {
+	cfg     *CleanOperationRecordsJobConfig
+	storage store.Store
+}
+
+func (job *cleanOperationRecordsJob) init(raw map[string]interface{}) error {
+	cfg := &CleanOperationRecordsJobConfig{
+		Retention:     30 * 24 * time.Hour,
+		CleanInterval: time.Hour,
+	}
+	decodeConfig := &mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     cfg,
+	}
+	decoder, err := mapstructure.NewDecoder(decodeConfig)
+	if err != nil {
+		log.Errorf("[Maintain][Job][CleanOperationRecords] new config decoder err: %v", err)
+		return err
+	}
+	err = decoder.Decode(raw)
+	if err != nil {
+		log.Errorf("[Maintain][Job][CleanOperationRecords] parse config err: %v", err)
+		return err
+	}
+	job.cfg = cfg
+
+	return nil
+}
+
+func (job *cleanOperationRecordsJob) execute() {
+	batchSize := uint32(1000)
+	before := time.Now().Add(-job.cfg.Retention)
+	for {
+		count, err := job.storage.BatchCleanOperationRecords(before, batchSize)
+		if err != nil {
+			log.Errorf("[Maintain][Job][CleanOperationRecords] batch clean operation records, err: %v", err)
+			break
+		}
+
+		log.Infof("[Maintain][Job][CleanOperationRecords] clean operation records count %d", count)
+
+		if count < batchSize {
+			break
+		}
+	}
+}
+
+func (job *cleanOperationRecordsJob) clear() {
+}
+
+func (job *cleanOperationRecordsJob) interval() time.Duration {
+	return job.cfg.CleanInterval
+}
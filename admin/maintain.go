@@ -21,6 +21,7 @@ import (
 	"context"
 	"errors"
 	"runtime/debug"
+	"strconv"
 	"time"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
@@ -31,6 +32,8 @@ import (
 	connlimit "github.com/polarismesh/polaris/common/conn/limit"
 	commonlog "github.com/polarismesh/polaris/common/log"
 	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/secure"
+	commontime "github.com/polarismesh/polaris/common/time"
 	"github.com/polarismesh/polaris/common/utils"
 	"github.com/polarismesh/polaris/plugin"
 )
@@ -216,3 +219,108 @@ func (svr *Server) GetCMDBInfo(ctx context.Context) ([]model.LocationView, error
 
 	return ret, nil
 }
+
+// GetOperationRecords 查询操作记录，start_time 以及 end_time 为秒级时间戳
+func (s *Server) GetOperationRecords(ctx context.Context,
+	query map[string]string) (*OperationRecordsResp, error) {
+	filter := make(map[string]string, len(query))
+	for k, v := range query {
+		filter[k] = v
+	}
+	offset, limit, err := utils.ParseOffsetAndLimit(filter)
+	if err != nil {
+		return nil, err
+	}
+	startTime, err := parseUnixTime(filter["start_time"])
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := parseUnixTime(filter["end_time"])
+	if err != nil {
+		return nil, err
+	}
+	delete(filter, "start_time")
+	delete(filter, "end_time")
+
+	total, records, err := s.storage.GetOperationRecords(filter, startTime, endTime, offset, limit)
+	if err != nil {
+		log.Error("[MAINTAIN] get operation records", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return nil, err
+	}
+	if records == nil {
+		records = []*model.RecordEntry{}
+	}
+	return &OperationRecordsResp{
+		Total:   total,
+		Size:    uint32(len(records)),
+		Records: records,
+	}, nil
+}
+
+// GetXDSTrustBundle 获取 xDS 内置 CA 的信任包，导入中间 CA 之后同时保留内置根证书
+func (s *Server) GetXDSTrustBundle(ctx context.Context) (*XDSTrustBundleResp, error) {
+	root, err := s.storage.GetCertificateAuthority(model.CertificateAuthorityRoot)
+	if err != nil {
+		log.Error("[MAINTAIN] get xds root ca", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return nil, err
+	}
+	if root == nil {
+		return nil, errors.New("xds built-in ca is not initialized")
+	}
+	rootAuthority, err := secure.NewAuthority(root.CertPEM, root.KeyPEM, root.ChainPEM)
+	if err != nil {
+		return nil, err
+	}
+	intermediate, err := s.storage.GetCertificateAuthority(model.CertificateAuthorityIntermediate)
+	if err != nil {
+		log.Error("[MAINTAIN] get xds intermediate ca", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return nil, err
+	}
+
+	signerID, signer := model.CertificateAuthorityRoot, rootAuthority
+	if intermediate != nil {
+		if signer, err = secure.NewAuthority(intermediate.CertPEM, intermediate.KeyPEM,
+			intermediate.ChainPEM); err != nil {
+			return nil, err
+		}
+		signerID = model.CertificateAuthorityIntermediate
+	}
+	return &XDSTrustBundleResp{
+		TrustBundle: secure.TrustBundle(signer, rootAuthority),
+		Signer:      signerID,
+		Subject:     signer.Certificate().Subject.String(),
+		ExpireAt:    commontime.Time2String(signer.Certificate().NotAfter),
+	}, nil
+}
+
+// ImportXDSIntermediateCA 导入中间 CA，xDS 节点同步之后使用中间 CA 签发工作负载证书
+func (s *Server) ImportXDSIntermediateCA(ctx context.Context, req *XDSIntermediateCAReq) error {
+	if req == nil || req.Cert == "" || req.Key == "" || req.Chain == "" {
+		return errors.New("cert, key and chain are required")
+	}
+	if _, err := secure.NewAuthority(req.Cert, req.Key, req.Chain); err != nil {
+		return err
+	}
+	if err := s.storage.SaveCertificateAuthority(&model.CertificateAuthority{
+		ID:       model.CertificateAuthorityIntermediate,
+		CertPEM:  req.Cert,
+		KeyPEM:   req.Key,
+		ChainPEM: req.Chain,
+	}); err != nil {
+		log.Error("[MAINTAIN] import xds intermediate ca", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return err
+	}
+	log.Info("[MAINTAIN] import xds intermediate ca", utils.ZapRequestIDByCtx(ctx))
+	return nil
+}
+
+func parseUnixTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.New("invalid time: " + value)
+	}
+	return time.Unix(sec, 0), nil
+}
@@ -178,3 +178,43 @@ func (svr *serverAuthAbility) GetCMDBInfo(ctx context.Context) ([]model.Location
 
 	return svr.targetServer.GetCMDBInfo(ctx)
 }
+
+func (svr *serverAuthAbility) GetOperationRecords(ctx context.Context,
+	query map[string]string) (*OperationRecordsResp, error) {
+	authCtx := svr.collectMaintainAuthContext(ctx, model.Read, "GetOperationRecords")
+	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return svr.targetServer.GetOperationRecords(ctx, query)
+}
+
+func (svr *serverAuthAbility) GetXDSTrustBundle(ctx context.Context) (*XDSTrustBundleResp, error) {
+	authCtx := svr.collectMaintainAuthContext(ctx, model.Read, "GetXDSTrustBundle")
+	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return svr.targetServer.GetXDSTrustBundle(ctx)
+}
+
+func (svr *serverAuthAbility) ImportXDSIntermediateCA(ctx context.Context, req *XDSIntermediateCAReq) error {
+	authCtx := svr.collectMaintainAuthContext(ctx, model.Modify, "ImportXDSIntermediateCA")
+	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
+	if err != nil {
+		return err
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return svr.targetServer.ImportXDSIntermediateCA(ctx, req)
+}
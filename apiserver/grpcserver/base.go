@@ -19,6 +19,7 @@ package grpcserver
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"net"
 	"net/http"
@@ -101,9 +102,10 @@ func (b *BaseGrpcServer) Initialize(ctx context.Context, conf map[string]interfa
 			return err
 		}
 		b.tlsInfo = &secure.TLSInfo{
-			CertFile:      tlsConfig.CertFile,
-			KeyFile:       tlsConfig.KeyFile,
-			TrustedCAFile: tlsConfig.TrustedCAFile,
+			CertFile:       tlsConfig.CertFile,
+			KeyFile:        tlsConfig.KeyFile,
+			TrustedCAFile:  tlsConfig.TrustedCAFile,
+			ClientCertAuth: tlsConfig.ClientCertAuth,
 		}
 	}
 
@@ -159,15 +161,16 @@ func (b *BaseGrpcServer) Run(errCh chan error, protocol string, initServer InitS
 		bz: b.bz,
 	})
 
-	// 指定使用服务端证书创建一个 TLS credentials
+	// 指定使用服务端证书创建一个 TLS credentials，开启 clientCertAuth 时要求客户端提供证书
 	var creds credentials.TransportCredentials
 	if !b.tlsInfo.IsEmpty() {
-		creds, err = credentials.NewServerTLSFromFile(b.tlsInfo.CertFile, b.tlsInfo.KeyFile)
+		tlsConfig, err := b.tlsInfo.ServerConfig()
 		if err != nil {
 			b.log.Error("failed to create credentials: %v", zap.Error(err))
 			errCh <- err
 			return
 		}
+		creds = credentials.NewTLS(tlsConfig)
 	}
 
 	// 设置 grpc server options
@@ -438,8 +441,9 @@ func ConvertContext(ctx context.Context) context.Context {
 	}
 
 	var (
-		clientIP = ""
-		address  = ""
+		clientIP   = ""
+		address    = ""
+		clientCert *x509.Certificate
 	)
 	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
 		address = pr.Addr.String()
@@ -447,6 +451,10 @@ func ConvertContext(ctx context.Context) context.Context {
 		if len(addrSlice) == 2 {
 			clientIP = addrSlice[0]
 		}
+		// 只有经过 CA 校验的客户端证书才会注入，供鉴权映射为北极星的用户/用户组
+		if tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo); ok {
+			clientCert = secure.VerifiedPeerCertificate(&tlsInfo.State)
+		}
 	}
 
 	ctx = context.Background()
@@ -455,6 +463,9 @@ func ConvertContext(ctx context.Context) context.Context {
 	ctx = context.WithValue(ctx, utils.StringContext("client-ip"), clientIP)
 	ctx = context.WithValue(ctx, utils.ContextClientAddress, address)
 	ctx = context.WithValue(ctx, utils.StringContext("user-agent"), userAgent)
+	if clientCert != nil {
+		ctx = context.WithValue(ctx, utils.ContextClientCertKey, clientCert)
+	}
 
 	return ctx
 }
@@ -19,10 +19,19 @@ package config
 
 import (
 	"context"
+	"io"
 
 	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/polarismesh/polaris/apiserver/grpcserver"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
 )
 
 // GetConfigFile 拉取配置
@@ -70,3 +79,69 @@ func (g *ConfigGRPCServer) WatchConfigFiles(ctx context.Context,
 
 	return callback(), nil
 }
+
+// WatchConfigFileStream 基于双向 stream 订阅配置变更，客户端每次发送当前订阅的全部配置文件，
+// 服务端在配置发布后主动推送变更通知
+func (g *ConfigGRPCServer) WatchConfigFileStream(stream grpc.ServerStream) error {
+	ctx := grpcserver.ConvertContext(stream.Context())
+	clientIP, _ := ctx.Value(utils.StringContext("client-ip")).(string)
+	clientAddress, _ := ctx.Value(utils.StringContext("client-address")).(string)
+	method, _ := grpc.MethodFromServerStream(stream)
+
+	watchStream := g.configServer.OpenWatchStream(ctx)
+	defer watchStream.Close()
+
+	// stream 不允许并发发送，接收到的订阅请求交给发送协程统一处理
+	requests := make(chan *apiconfig.ClientWatchConfigFileRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			in := &apiconfig.ClientWatchConfigFileRequest{}
+			if err := stream.RecvMsg(in); err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- in:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		var out *apiconfig.ConfigClientResponse
+		select {
+		case in := <-requests:
+			configLog.Info("receive grpc config watch stream request",
+				zap.String("client-address", clientAddress),
+				zap.Int("watch-files", len(in.GetWatchFiles())))
+			out = g.handleWatchStreamRequest(ctx, watchStream, clientIP, method, in)
+		case out = <-watchStream.Notifications():
+		case <-watchStream.Done():
+			// 推送积压时由服务端关闭 stream，客户端重连后按照版本号重新同步
+			return status.Error(codes.ResourceExhausted, "too many pending config notifications, please resubscribe")
+		case err := <-recvErr:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := stream.SendMsg(out); err != nil {
+			return err
+		}
+	}
+}
+
+func (g *ConfigGRPCServer) handleWatchStreamRequest(ctx context.Context, watchStream *config.WatchStream,
+	clientIP, method string, in *apiconfig.ClientWatchConfigFileRequest) *apiconfig.ConfigClientResponse {
+	// 是否允许访问
+	if ok := g.allowAccess(method); !ok {
+		return api.NewConfigClientResponse(apimodel.Code_ClientAPINotOpen, nil)
+	}
+	// stream模式，需要对每个包进行检测
+	if code := g.enterRateLimit(clientIP, method); code != uint32(apimodel.Code_ExecuteSuccess) {
+		return api.NewConfigClientResponse(apimodel.Code(code), nil)
+	}
+	return watchStream.Subscribe(ctx, in)
+}
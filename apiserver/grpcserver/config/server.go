@@ -36,6 +36,29 @@ var (
 	configLog = commonlog.GetScopeOrDefaultByName(commonlog.ConfigLoggerName)
 )
 
+// PolarisConfigStreamGRPCServer 配置中心基于 stream 推送配置变更的 GRPC 接口
+type PolarisConfigStreamGRPCServer interface {
+	WatchConfigFileStream(stream grpc.ServerStream) error
+}
+
+// configStreamServiceDesc 配置订阅 stream 接口的服务描述，对应 grpc_config_stream_api.proto 中的 PolarisConfigStreamGRPC。
+// specification 中还没有该服务，生成的代码也就没有服务描述，这里手写的描述需要与 proto 定义保持一致，
+// 消息类型与 PolarisConfigGRPC.WatchConfigFiles 相同，来自 specification 的 config_manage 包
+var configStreamServiceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.PolarisConfigStreamGRPC",
+	HandlerType: (*PolarisConfigStreamGRPCServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "WatchConfigFileStream",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(PolarisConfigStreamGRPCServer).WatchConfigFileStream(stream)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
 // ConfigGRPCServer 配置中心 GRPC API 服务器
 type ConfigGRPCServer struct {
 	grpcserver.BaseGrpcServer
@@ -72,7 +95,8 @@ func (g *ConfigGRPCServer) Run(errCh chan error) {
 			case "client":
 				if apiConfig.Enable {
 					apiconfig.RegisterPolarisConfigGRPCServer(server, g)
-					openMethod, getErr := getConfigClientOpenMethod(g.GetProtocol())
+					server.RegisterService(&configStreamServiceDesc, g)
+					openMethod, getErr := getConfigClientOpenMethod(apiConfig.Include, g.GetProtocol())
 					if getErr != nil {
 						return getErr
 					}
@@ -126,15 +150,41 @@ func (g *ConfigGRPCServer) allowAccess(method string) bool {
 	return g.BaseGrpcServer.AllowAccess(method)
 }
 
-func getConfigClientOpenMethod(protocol string) (map[string]bool, error) {
-	openMethods := []string{"GetConfigFile", "CreateConfigFile",
-		"UpdateConfigFile", "PublishConfigFile", "WatchConfigFiles"}
+const (
+	// configAccess 配置文件的查询、写入、发布以及长轮询订阅接口
+	configAccess string = "config"
+	// configStreamAccess 基于 stream 订阅配置变更的接口
+	configStreamAccess string = "stream"
+)
 
-	openMethod := make(map[string]bool)
+// getConfigClientOpenMethod 获取配置中心客户端 openMethod，include 为空时开启全部接口
+func getConfigClientOpenMethod(include []string, protocol string) (map[string]bool, error) {
+	clientAccess := map[string][]string{
+		configAccess: {
+			"/v1.PolarisConfig" + strings.ToUpper(protocol) + "/GetConfigFile",
+			"/v1.PolarisConfig" + strings.ToUpper(protocol) + "/CreateConfigFile",
+			"/v1.PolarisConfig" + strings.ToUpper(protocol) + "/UpdateConfigFile",
+			"/v1.PolarisConfig" + strings.ToUpper(protocol) + "/PublishConfigFile",
+			"/v1.PolarisConfig" + strings.ToUpper(protocol) + "/WatchConfigFiles",
+		},
+		configStreamAccess: {
+			"/v1.PolarisConfigStream" + strings.ToUpper(protocol) + "/WatchConfigFileStream",
+		},
+	}
 
-	for _, item := range openMethods {
-		method := "/v1.PolarisConfig" + strings.ToUpper(protocol) + "/" + item
-		openMethod[method] = true
+	openMethod := make(map[string]bool)
+	if len(include) == 0 {
+		include = []string{configAccess, configStreamAccess}
+	}
+	for _, item := range include {
+		methods, ok := clientAccess[item]
+		if !ok {
+			configLog.Errorf("method %s does not exist in %sserver config client access", item, protocol)
+			return nil, fmt.Errorf("method %s does not exist in %sserver config client access", item, protocol)
+		}
+		for _, method := range methods {
+			openMethod[method] = true
+		}
 	}
 
 	return openMethod, nil
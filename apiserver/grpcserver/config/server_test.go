@@ -0,0 +1,98 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetConfigClientOpenMethod(t *testing.T) {
+	type args struct {
+		include  []string
+		protocol string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    map[string]bool
+		wantErr bool
+	}{
+		{
+			name: "只开启配置接口",
+			args: args{
+				include:  []string{configAccess},
+				protocol: "grpc",
+			},
+			want: map[string]bool{
+				"/v1.PolarisConfigGRPC/GetConfigFile":     true,
+				"/v1.PolarisConfigGRPC/CreateConfigFile":  true,
+				"/v1.PolarisConfigGRPC/UpdateConfigFile":  true,
+				"/v1.PolarisConfigGRPC/PublishConfigFile": true,
+				"/v1.PolarisConfigGRPC/WatchConfigFiles":  true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "只开启stream订阅接口",
+			args: args{
+				include:  []string{configStreamAccess},
+				protocol: "grpc",
+			},
+			want: map[string]bool{
+				"/v1.PolarisConfigStreamGRPC/WatchConfigFileStream": true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "未配置时开启全部接口",
+			args: args{
+				protocol: "grpc",
+			},
+			want: map[string]bool{
+				"/v1.PolarisConfigGRPC/GetConfigFile":               true,
+				"/v1.PolarisConfigGRPC/CreateConfigFile":            true,
+				"/v1.PolarisConfigGRPC/UpdateConfigFile":            true,
+				"/v1.PolarisConfigGRPC/PublishConfigFile":           true,
+				"/v1.PolarisConfigGRPC/WatchConfigFiles":            true,
+				"/v1.PolarisConfigStreamGRPC/WatchConfigFileStream": true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "不存在的接口",
+			args: args{
+				include:  []string{"discover"},
+				protocol: "grpc",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getConfigClientOpenMethod(tt.args.include, tt.args.protocol)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getConfigClientOpenMethod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getConfigClientOpenMethod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -27,8 +27,10 @@ import (
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	"github.com/polarismesh/polaris/apiserver/grpcserver"
 	api "github.com/polarismesh/polaris/common/api/v1"
@@ -91,6 +93,13 @@ func (g *DiscoverServer) Discover(server apiservice.PolarisGRPC_DiscoverServer)
 	userAgent, _ := ctx.Value(utils.StringContext("user-agent")).(string)
 	method, _ := grpc.MethodFromServerStream(server)
 
+	// 客户端声明订阅模式后，服务端在数据变化时主动推送，不再需要客户端轮询
+	if grpcHeader, ok := ctx.Value(utils.ContextGrpcHeader).(metadata.MD); ok {
+		if _, ok := grpcHeader["discover-subscribe"]; ok {
+			return g.subscribeDiscover(ctx, server, clientIP, clientAddress, userAgent, requestID, method)
+		}
+	}
+
 	for {
 		in, err := server.Recv()
 		if err != nil {
@@ -151,6 +160,63 @@ func (g *DiscoverServer) Discover(server apiservice.PolarisGRPC_DiscoverServer)
 	}
 }
 
+// subscribeDiscover 订阅模式的 Discover，每个请求返回当前的数据并订阅后续变更，缓存中数据的版本变化后推送新的数据
+func (g *DiscoverServer) subscribeDiscover(ctx context.Context, server apiservice.PolarisGRPC_DiscoverServer,
+	clientIP, clientAddress, userAgent, requestID, method string) error {
+	discoverStream := g.namingServer.OpenDiscoverStream(ctx)
+	defer discoverStream.Close()
+
+	// stream 不允许并发发送，接收到的请求交给发送协程统一处理
+	requests := make(chan *apiservice.DiscoverRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			in, err := server.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case requests <- in:
+			case <-server.Context().Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		var out *apiservice.DiscoverResponse
+		select {
+		case in := <-requests:
+			namingLog.Info(fmt.Sprintf("receive grpc discover subscribe request: %s", in.Service.String()),
+				zap.String("type", apiservice.DiscoverRequest_DiscoverRequestType_name[int32(in.Type)]),
+				zap.String("client-address", clientAddress),
+				zap.String("user-agent", userAgent),
+				utils.ZapRequestID(requestID),
+			)
+			if ok := g.allowAccess(method); !ok {
+				out = api.NewDiscoverResponse(apimodel.Code_ClientAPINotOpen)
+			} else if code := g.enterRateLimit(clientIP, method); code != uint32(apimodel.Code_ExecuteSuccess) {
+				out = api.NewDiscoverResponse(apimodel.Code(code))
+			} else {
+				out = discoverStream.Subscribe(ctx, in)
+			}
+		case out = <-discoverStream.Notifications():
+		case <-discoverStream.Done():
+			// 推送积压时由服务端关闭 stream，客户端重连后重新订阅
+			return status.Error(codes.ResourceExhausted, "too many pending discover responses, please resubscribe")
+		case err := <-recvErr:
+			if io.EOF == err {
+				return nil
+			}
+			return err
+		}
+		if err := server.Send(out); err != nil {
+			return err
+		}
+	}
+}
+
 // Heartbeat 上报心跳
 func (g *DiscoverServer) Heartbeat(ctx context.Context, in *apiservice.Instance) (*apiservice.Response, error) {
 	return g.healthCheckServer.Report(grpcserver.ConvertContext(ctx), in), nil
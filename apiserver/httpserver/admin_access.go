@@ -53,6 +53,10 @@ func (h *HTTPServer) GetAdminAccessServer() *restful.WebService {
 	ws.Route(docs.EnrichReleaseLeaderElectionApiDocs(ws.POST("/leaders/release").To(h.ReleaseLeaderElection)))
 	ws.Route(docs.EnrichGetCMDBInfoApiDocs(ws.GET("/cmdb/info").To(h.GetCMDBInfo)))
 	ws.Route(docs.EnrichGetReportClientsApiDocs(ws.GET("/report/clients").To(h.GetReportClients)))
+	ws.Route(docs.EnrichGetOperationRecordsApiDocs(ws.GET("/operation/records").To(h.GetOperationRecords)))
+	ws.Route(docs.EnrichGetXDSTrustBundleApiDocs(ws.GET("/xds/ca/bundle").To(h.GetXDSTrustBundle)))
+	ws.Route(docs.EnrichImportXDSIntermediateCAApiDocs(
+		ws.POST("/xds/ca/intermediate").To(h.ImportXDSIntermediateCA)))
 	return ws
 }
 
@@ -280,6 +284,46 @@ func (h *HTTPServer) GetCMDBInfo(req *restful.Request, rsp *restful.Response) {
 	_ = rsp.WriteAsJson(ret)
 }
 
+// GetOperationRecords 查询操作记录
+func (h *HTTPServer) GetOperationRecords(req *restful.Request, rsp *restful.Response) {
+	ctx := initContext(req)
+
+	ret, err := h.maintainServer.GetOperationRecords(ctx, httpcommon.ParseQueryParams(req))
+	if err != nil {
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	_ = rsp.WriteAsJson(ret)
+}
+
+// GetXDSTrustBundle 查询 xDS 内置 CA 的信任包
+func (h *HTTPServer) GetXDSTrustBundle(req *restful.Request, rsp *restful.Response) {
+	ctx := initContext(req)
+
+	ret, err := h.maintainServer.GetXDSTrustBundle(ctx)
+	if err != nil {
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	_ = rsp.WriteAsJson(ret)
+}
+
+// ImportXDSIntermediateCA 导入 xDS 签发工作负载证书使用的中间 CA
+func (h *HTTPServer) ImportXDSIntermediateCA(req *restful.Request, rsp *restful.Response) {
+	ctx := initContext(req)
+
+	var caReq admin.XDSIntermediateCAReq
+	if err := httpcommon.ParseJsonBody(req, &caReq); err != nil {
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := h.maintainServer.ImportXDSIntermediateCA(ctx, &caReq); err != nil {
+		_ = rsp.WriteErrorString(http.StatusBadRequest, err.Error())
+		return
+	}
+	_ = rsp.WriteEntity("ok")
+}
+
 func initContext(req *restful.Request) context.Context {
 	ctx := context.Background()
 
@@ -18,7 +18,9 @@
 package httpserver
 
 import (
+	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/emicklei/go-restful/v3"
 	"github.com/golang/protobuf/proto"
@@ -28,6 +30,7 @@ import (
 
 	"github.com/polarismesh/polaris/apiserver/httpserver/docs"
 	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/utils"
+	"github.com/polarismesh/polaris/auth"
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/utils"
 )
@@ -37,6 +40,8 @@ func (h *HTTPServer) GetAuthServer(ws *restful.WebService) error {
 	ws.Route(docs.EnrichAuthStatusApiDocs(ws.GET("/auth/status").To(h.AuthStatus)))
 	//
 	ws.Route(docs.EnrichLoginApiDocs(ws.POST("/user/login").To(h.Login)))
+	ws.Route(docs.EnrichOIDCLoginApiDocs(ws.GET("/user/oidc/login").To(h.OIDCLogin)))
+	ws.Route(docs.EnrichOIDCCallbackApiDocs(ws.GET("/user/oidc/callback").To(h.OIDCCallback)))
 	ws.Route(docs.EnrichGetUsersApiDocs(ws.GET("/users").To(h.GetUsers)))
 	ws.Route(docs.EnrichCreateUsersApiDocs(ws.POST("/users").To(h.CreateUsers)))
 	ws.Route(docs.EnrichDeleteUsersApiDocs(ws.POST("/users/delete").To(h.DeleteUsers)))
@@ -45,6 +50,8 @@ func (h *HTTPServer) GetAuthServer(ws *restful.WebService) error {
 	ws.Route(docs.EnrichGetUserTokenApiDocs(ws.GET("/user/token").To(h.GetUserToken)))
 	ws.Route(docs.EnrichUpdateUserTokenApiDocs(ws.PUT("/user/token/status").To(h.UpdateUserToken)))
 	ws.Route(docs.EnrichResetUserTokenApiDocs(ws.PUT("/user/token/refresh").To(h.ResetUserToken)))
+	ws.Route(docs.EnrichCreateScopedTokenApiDocs(ws.POST("/user/token/scoped").To(h.CreateScopedToken)))
+	ws.Route(docs.EnrichRevokeScopedTokenApiDocs(ws.POST("/user/token/scoped/revoke").To(h.RevokeScopedToken)))
 	//
 	ws.Route(docs.EnrichCreateGroupApiDocs(ws.POST("/usergroup").To(h.CreateGroup)))
 	ws.Route(docs.EnrichUpdateGroupsApiDocs(ws.PUT("/usergroups").To(h.UpdateGroups)))
@@ -61,6 +68,11 @@ func (h *HTTPServer) GetAuthServer(ws *restful.WebService) error {
 	ws.Route(docs.EnrichDeleteStrategiesApiDocs(ws.POST("/auth/strategies/delete").To(h.DeleteStrategies)))
 	ws.Route(docs.EnrichGetStrategiesApiDocs(ws.GET("/auth/strategies").To(h.GetStrategies)))
 	ws.Route(docs.EnrichGetPrincipalResourcesApiDocs(ws.GET("/auth/principal/resources").To(h.GetPrincipalResources)))
+	ws.Route(docs.EnrichUpdateStrategyActionApiDocs(ws.PUT("/auth/strategy/action").To(h.UpdateStrategyAction)))
+	ws.Route(docs.EnrichGetStrategyActionApiDocs(ws.GET("/auth/strategy/action").To(h.GetStrategyAction)))
+	ws.Route(docs.EnrichGetStrategyConflictsApiDocs(ws.GET("/auth/strategy/conflicts").To(h.GetStrategyConflicts)))
+	ws.Route(docs.EnrichUpdateStrategyRulesApiDocs(ws.PUT("/auth/strategy/rules").To(h.UpdateStrategyRules)))
+	ws.Route(docs.EnrichGetStrategyRulesApiDocs(ws.GET("/auth/strategy/rules").To(h.GetStrategyRules)))
 
 	return nil
 }
@@ -103,6 +115,64 @@ func (h *HTTPServer) Login(req *restful.Request, rsp *restful.Response) {
 	handler.WriteHeaderAndProto(h.userMgn.Login(loginReq))
 }
 
+// oidcCookiePath OIDC nonce cookie 的作用路径
+const oidcCookiePath = "/core/v1/user/oidc"
+
+// OIDCLogin 跳转到 IdP 进行 OIDC 单点登录
+func (h *HTTPServer) OIDCLogin(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	authz, err := h.userMgn.OIDCLoginURL(handler.ParseHeaderContext())
+	if err != nil {
+		handler.WriteHeaderAndProto(api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, err.Error()))
+		return
+	}
+	// nonce 只下发到发起登录的浏览器，回调时必须携带同样的 nonce，避免 state 被其他人重放
+	http.SetCookie(rsp.ResponseWriter, &http.Cookie{
+		Name:     auth.OIDCNonceCookie,
+		Value:    authz.Nonce,
+		Path:     oidcCookiePath,
+		Expires:  authz.ExpireAt,
+		MaxAge:   int(time.Until(authz.ExpireAt) / time.Second),
+		Secure:   req.Request.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(rsp.ResponseWriter, req.Request, authz.URL, http.StatusFound)
+}
+
+// OIDCCallback IdP 授权完成后的回调，返回和 Login 一致的登录信息
+func (h *HTTPServer) OIDCCallback(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	if errCode := req.QueryParameter("error"); errCode != "" {
+		handler.WriteHeaderAndProto(api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess,
+			errCode+": "+req.QueryParameter("error_description")))
+		return
+	}
+	var nonce string
+	if cookie, err := req.Request.Cookie(auth.OIDCNonceCookie); err == nil {
+		nonce = cookie.Value
+	}
+	// nonce 只能使用一次
+	http.SetCookie(rsp.ResponseWriter, &http.Cookie{
+		Name:     auth.OIDCNonceCookie,
+		Path:     oidcCookiePath,
+		MaxAge:   -1,
+		Secure:   req.Request.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	handler.WriteHeaderAndProto(h.userMgn.OIDCCallback(handler.ParseHeaderContext(),
+		req.QueryParameter("code"), req.QueryParameter("state"), nonce))
+}
+
 // CreateUsers 批量创建用户
 func (h *HTTPServer) CreateUsers(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{
@@ -247,6 +317,42 @@ func (h *HTTPServer) ResetUserToken(req *restful.Request, rsp *restful.Response)
 	handler.WriteHeaderAndProto(h.userMgn.ResetUserToken(ctx, user))
 }
 
+// CreateScopedToken 申请带有效期以及授权范围限制的临时 token
+func (h *HTTPServer) CreateScopedToken(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	scopedReq := &auth.ScopedTokenRequest{}
+	if err := httpcommon.ParseJsonBody(req, scopedReq); err != nil {
+		resp := auth.NewScopedTokenResponseWithMsg(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.userMgn.CreateScopedToken(handler.ParseHeaderContext(), scopedReq)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// RevokeScopedToken 吊销临时 token
+func (h *HTTPServer) RevokeScopedToken(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	revokeReq := &auth.RevokeScopedTokenRequest{}
+	if err := httpcommon.ParseJsonBody(req, revokeReq); err != nil {
+		resp := auth.NewScopedTokenResponseWithMsg(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.userMgn.RevokeScopedToken(handler.ParseHeaderContext(), revokeReq)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
 // CreateGroup 创建用户组
 func (h *HTTPServer) CreateGroup(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{
@@ -496,3 +602,72 @@ func (h *HTTPServer) GetPrincipalResources(req *restful.Request, rsp *restful.Re
 
 	handler.WriteHeaderAndProto(h.strategyMgn.GetPrincipalResources(ctx, queryParams))
 }
+
+// UpdateStrategyAction 修改鉴权策略的动作
+func (h *HTTPServer) UpdateStrategyAction(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	action := &auth.StrategyAction{}
+	if err := httpcommon.ParseJsonBody(req, action); err != nil {
+		resp := auth.NewStrategyActionResponseWithMsg(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.strategyMgn.UpdateStrategyAction(handler.ParseHeaderContext(), action)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetStrategyAction 查询鉴权策略的动作
+func (h *HTTPServer) GetStrategyAction(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	resp := h.strategyMgn.GetStrategyAction(handler.ParseHeaderContext(), req.QueryParameter("id"))
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetStrategyConflicts 查询鉴权策略的 ALLOW/DENY 冲突
+func (h *HTTPServer) GetStrategyConflicts(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	resp := h.strategyMgn.GetStrategyConflicts(handler.ParseHeaderContext(), req.QueryParameter("id"))
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// UpdateStrategyRules 修改鉴权策略中的治理规则资源
+func (h *HTTPServer) UpdateStrategyRules(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	rules := &auth.StrategyRuleResources{}
+	if err := httpcommon.ParseJsonBody(req, rules); err != nil {
+		resp := auth.NewStrategyRuleResourcesResponseWithMsg(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.strategyMgn.UpdateStrategyRules(handler.ParseHeaderContext(), rules)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetStrategyRules 查询鉴权策略中的治理规则资源
+func (h *HTTPServer) GetStrategyRules(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	resp := h.strategyMgn.GetStrategyRules(handler.ParseHeaderContext(), req.QueryParameter("id"))
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
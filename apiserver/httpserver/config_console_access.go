@@ -18,6 +18,7 @@
 package httpserver
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 	"strings"
@@ -31,6 +32,7 @@ import (
 	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/utils"
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/config"
 )
 
 // CreateConfigFileGroup 创建配置文件组
@@ -110,6 +112,40 @@ func (h *HTTPServer) UpdateConfigFileGroup(req *restful.Request, rsp *restful.Re
 	handler.WriteHeaderAndProto(h.configServer.UpdateConfigFileGroup(ctx, configFileGroup))
 }
 
+// UpdateConfigFileGroupSchema 设置配置文件组的 JSON Schema
+func (h *HTTPServer) UpdateConfigFileGroupSchema(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	schemaReq := &config.ConfigFileGroupSchema{}
+	if err := httpcommon.ParseJsonBody(req, schemaReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file group schema from request error.",
+			zap.String("error", err.Error()))
+		resp := config.NewConfigFileGroupSchemaResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.configServer.UpdateConfigFileGroupSchema(handler.ParseHeaderContext(), schemaReq)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetConfigFileGroupSchema 获取配置文件组的 JSON Schema
+func (h *HTTPServer) GetConfigFileGroupSchema(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+
+	resp := h.configServer.GetConfigFileGroupSchema(handler.ParseHeaderContext(), namespace, group)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
 // CreateConfigFile 创建配置文件
 func (h *HTTPServer) CreateConfigFile(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{
@@ -349,6 +385,206 @@ func (h *HTTPServer) GetConfigFileRelease(req *restful.Request, rsp *restful.Res
 	handler.WriteHeaderAndProto(response)
 }
 
+// RollbackConfigFileRelease 将配置文件回滚到指定的历史发布
+func (h *HTTPServer) RollbackConfigFileRelease(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	rollbackReq := &config.ConfigFileReleaseRollbackRequest{}
+	if err := httpcommon.ParseJsonBody(req, rollbackReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file rollback from request error.",
+			zap.String("error", err.Error()))
+		handler.WriteHeaderAndProto(api.NewConfigFileReleaseResponseWithMessage(apimodel.Code_ParseException, err.Error()))
+		return
+	}
+
+	handler.WriteHeaderAndProto(h.configServer.RollbackConfigFileRelease(handler.ParseHeaderContext(), rollbackReq))
+}
+
+// PublishConfigFileGray 灰度发布配置文件
+func (h *HTTPServer) PublishConfigFileGray(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	grayReq := &config.ConfigFileGrayReleaseRequest{}
+	if err := httpcommon.ParseJsonBody(req, grayReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file gray release from request error.",
+			zap.String("error", err.Error()))
+		resp := config.NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.configServer.PublishConfigFileGray(handler.ParseHeaderContext(), grayReq)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetConfigFileGrayRelease 获取配置文件的全量发布以及进行中的灰度发布
+func (h *HTTPServer) GetConfigFileGrayRelease(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+	name := handler.Request.QueryParameter("name")
+
+	resp := h.configServer.GetConfigFileGrayRelease(handler.ParseHeaderContext(), namespace, group, name)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// PromoteConfigFileGrayRelease 将灰度发布全量发布给所有客户端
+func (h *HTTPServer) PromoteConfigFileGrayRelease(req *restful.Request, rsp *restful.Response) {
+	h.handleConfigFileGrayRelease(req, rsp, h.configServer.PromoteConfigFileGrayRelease)
+}
+
+// AbandonConfigFileGrayRelease 放弃灰度发布
+func (h *HTTPServer) AbandonConfigFileGrayRelease(req *restful.Request, rsp *restful.Response) {
+	h.handleConfigFileGrayRelease(req, rsp, h.configServer.AbandonConfigFileGrayRelease)
+}
+
+func (h *HTTPServer) handleConfigFileGrayRelease(req *restful.Request, rsp *restful.Response,
+	action func(ctx context.Context, namespace, group, fileName string) *config.ConfigFileGrayReleaseResponse) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	grayReq := &config.ConfigFileGrayReleaseRequest{}
+	if err := httpcommon.ParseJsonBody(req, grayReq); err != nil {
+		configLog.Error("[Config][HttpServer] parse config file gray release from request error.",
+			zap.String("error", err.Error()))
+		resp := config.NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := action(handler.ParseHeaderContext(), grayReq.Namespace, grayReq.Group, grayReq.FileName)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// DiffConfigFileRelease 对比配置文件当前生效的发布与草稿
+func (h *HTTPServer) DiffConfigFileRelease(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+	name := handler.Request.QueryParameter("name")
+
+	resp := h.configServer.DiffConfigFileRelease(handler.ParseHeaderContext(), namespace, group, name)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// UpdateConfigApprovalPolicy 设置配置发布审批策略
+func (h *HTTPServer) UpdateConfigApprovalPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	policy := &config.ConfigApprovalPolicy{}
+	if err := httpcommon.ParseJsonBody(req, policy); err != nil {
+		configLog.Error("[Config][HttpServer] parse config approval policy from request error.",
+			zap.String("error", err.Error()))
+		resp := config.NewConfigApprovalPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.configServer.UpdateConfigApprovalPolicy(handler.ParseHeaderContext(), policy)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetConfigApprovalPolicy 查询配置发布审批策略
+func (h *HTTPServer) GetConfigApprovalPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+
+	resp := h.configServer.GetConfigApprovalPolicy(handler.ParseHeaderContext(), namespace, group)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// DeleteConfigApprovalPolicy 删除配置发布审批策略
+func (h *HTTPServer) DeleteConfigApprovalPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+
+	resp := h.configServer.DeleteConfigApprovalPolicy(handler.ParseHeaderContext(), namespace, group)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// QueryConfigReleaseRequests 查询配置发布申请
+func (h *HTTPServer) QueryConfigReleaseRequests(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+	name := handler.Request.QueryParameter("name")
+	status := handler.Request.QueryParameter("status")
+	offset, _ := strconv.ParseUint(handler.Request.QueryParameter("offset"), 10, 64)
+	limit, _ := strconv.ParseUint(handler.Request.QueryParameter("limit"), 10, 64)
+
+	resp := h.configServer.QueryConfigReleaseRequests(handler.ParseHeaderContext(), namespace, group, name,
+		status, uint32(offset), uint32(limit))
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// ApproveConfigReleaseRequest 同意配置发布申请
+func (h *HTTPServer) ApproveConfigReleaseRequest(req *restful.Request, rsp *restful.Response) {
+	h.handleConfigReleaseRequestReview(req, rsp, h.configServer.ApproveConfigReleaseRequest)
+}
+
+// RejectConfigReleaseRequest 驳回配置发布申请
+func (h *HTTPServer) RejectConfigReleaseRequest(req *restful.Request, rsp *restful.Response) {
+	h.handleConfigReleaseRequestReview(req, rsp, h.configServer.RejectConfigReleaseRequest)
+}
+
+// CancelConfigReleaseRequest 撤销配置发布申请
+func (h *HTTPServer) CancelConfigReleaseRequest(req *restful.Request, rsp *restful.Response) {
+	h.handleConfigReleaseRequestReview(req, rsp, h.configServer.CancelConfigReleaseRequest)
+}
+
+func (h *HTTPServer) handleConfigReleaseRequestReview(req *restful.Request, rsp *restful.Response,
+	action func(ctx context.Context, req *config.ConfigReleaseRequestReview) *config.ConfigReleaseRequestResponse) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	review := &config.ConfigReleaseRequestReview{}
+	if err := httpcommon.ParseJsonBody(req, review); err != nil {
+		configLog.Error("[Config][HttpServer] parse config release request review from request error.",
+			zap.String("error", err.Error()))
+		resp := config.NewConfigReleaseRequestResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := action(handler.ParseHeaderContext(), review)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
 // GetConfigFileReleaseHistory 获取配置文件发布历史，按照发布时间倒序排序
 func (h *HTTPServer) GetConfigFileReleaseHistory(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{
@@ -375,6 +611,24 @@ func (h *HTTPServer) GetConfigFileReleaseHistory(req *restful.Request, rsp *rest
 	handler.WriteHeaderAndProto(response)
 }
 
+// DiffConfigFileReleaseHistory 对比配置文件的两个发布历史
+func (h *HTTPServer) DiffConfigFileReleaseHistory(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	namespace := handler.Request.QueryParameter("namespace")
+	group := handler.Request.QueryParameter("group")
+	name := handler.Request.QueryParameter("name")
+	fromId, _ := strconv.ParseUint(handler.Request.QueryParameter("fromId"), 10, 64)
+	toId, _ := strconv.ParseUint(handler.Request.QueryParameter("toId"), 10, 64)
+
+	resp := h.configServer.DiffConfigFileReleaseHistory(handler.ParseHeaderContext(),
+		namespace, group, name, fromId, toId)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
 // GetAllConfigFileTemplates get all config file template
 func (h *HTTPServer) GetAllConfigFileTemplates(req *restful.Request, rsp *restful.Response) {
 	handler := &httpcommon.Handler{
@@ -66,6 +66,10 @@ func (h *HTTPServer) bindConfigConsoleEndpoint(ws *restful.WebService) {
 	ws.Route(docs.EnrichQueryConfigFileGroupsApiDocs(ws.GET("/configfilegroups").To(h.QueryConfigFileGroups)))
 	ws.Route(docs.EnrichDeleteConfigFileGroupApiDocs(ws.DELETE("/configfilegroups").To(h.DeleteConfigFileGroup)))
 	ws.Route(docs.EnrichUpdateConfigFileGroupApiDocs(ws.PUT("/configfilegroups").To(h.UpdateConfigFileGroup)))
+	ws.Route(docs.EnrichUpdateConfigFileGroupSchemaApiDocs(ws.PUT("/configfilegroups/schema").
+		To(h.UpdateConfigFileGroupSchema)))
+	ws.Route(docs.EnrichGetConfigFileGroupSchemaApiDocs(ws.GET("/configfilegroups/schema").
+		To(h.GetConfigFileGroupSchema)))
 
 	// 配置文件
 	ws.Route(docs.EnrichCreateConfigFileApiDocs(ws.POST("/configfiles").To(h.CreateConfigFile)))
@@ -83,10 +87,40 @@ func (h *HTTPServer) bindConfigConsoleEndpoint(ws *restful.WebService) {
 	// 配置文件发布
 	ws.Route(docs.EnrichPublishConfigFileApiDocs(ws.POST("/configfiles/release").To(h.PublishConfigFile)))
 	ws.Route(docs.EnrichGetConfigFileReleaseApiDocs(ws.GET("/configfiles/release").To(h.GetConfigFileRelease)))
+	ws.Route(docs.EnrichRollbackConfigFileReleaseApiDocs(ws.POST("/configfiles/release/rollback").
+		To(h.RollbackConfigFileRelease)))
+	ws.Route(docs.EnrichPublishConfigFileGrayApiDocs(ws.POST("/configfiles/release/gray").
+		To(h.PublishConfigFileGray)))
+	ws.Route(docs.EnrichGetConfigFileGrayReleaseApiDocs(ws.GET("/configfiles/release/gray").
+		To(h.GetConfigFileGrayRelease)))
+	ws.Route(docs.EnrichPromoteConfigFileGrayReleaseApiDocs(ws.POST("/configfiles/release/gray/promote").
+		To(h.PromoteConfigFileGrayRelease)))
+	ws.Route(docs.EnrichAbandonConfigFileGrayReleaseApiDocs(ws.POST("/configfiles/release/gray/abandon").
+		To(h.AbandonConfigFileGrayRelease)))
+	ws.Route(docs.EnrichDiffConfigFileReleaseApiDocs(ws.GET("/configfiles/release/diff").
+		To(h.DiffConfigFileRelease)))
+
+	// 配置发布审批
+	ws.Route(docs.EnrichUpdateConfigApprovalPolicyApiDocs(ws.PUT("/configfiles/approval/policy").
+		To(h.UpdateConfigApprovalPolicy)))
+	ws.Route(docs.EnrichGetConfigApprovalPolicyApiDocs(ws.GET("/configfiles/approval/policy").
+		To(h.GetConfigApprovalPolicy)))
+	ws.Route(docs.EnrichDeleteConfigApprovalPolicyApiDocs(ws.DELETE("/configfiles/approval/policy").
+		To(h.DeleteConfigApprovalPolicy)))
+	ws.Route(docs.EnrichQueryConfigReleaseRequestsApiDocs(ws.GET("/configfiles/release/requests").
+		To(h.QueryConfigReleaseRequests)))
+	ws.Route(docs.EnrichApproveConfigReleaseRequestApiDocs(ws.POST("/configfiles/release/requests/approve").
+		To(h.ApproveConfigReleaseRequest)))
+	ws.Route(docs.EnrichRejectConfigReleaseRequestApiDocs(ws.POST("/configfiles/release/requests/reject").
+		To(h.RejectConfigReleaseRequest)))
+	ws.Route(docs.EnrichCancelConfigReleaseRequestApiDocs(ws.POST("/configfiles/release/requests/cancel").
+		To(h.CancelConfigReleaseRequest)))
 
 	// 配置文件发布历史
 	ws.Route(docs.EnrichGetConfigFileReleaseHistoryApiDocs(ws.GET("/configfiles/releasehistory").
 		To(h.GetConfigFileReleaseHistory)))
+	ws.Route(docs.EnrichDiffConfigFileReleaseHistoryApiDocs(ws.GET("/configfiles/releasehistory/diff").
+		To(h.DiffConfigFileReleaseHistory)))
 
 	// config file template
 	ws.Route(docs.EnrichGetAllConfigFileTemplatesApiDocs(ws.GET("/configfiletemplates").To(h.GetAllConfigFileTemplates)))
@@ -132,3 +132,33 @@ func EnrichGetReportClientsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilde
 		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
 		Notes(enrichReleaseLeaderElectionApiNotes)
 }
+
+func EnrichGetOperationRecordsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询操作记录").
+		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
+		Param(restful.QueryParameter("resource_type", "资源类型").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("resource_name", "资源名称").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("operator", "操作人").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("operation_type", "操作类型").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("start_time", "开始时间，秒级时间戳").DataType(typeNameInteger).Required(false)).
+		Param(restful.QueryParameter("end_time", "结束时间，秒级时间戳").DataType(typeNameInteger).Required(false)).
+		Param(restful.QueryParameter("offset", "查询偏移量").DataType(typeNameInteger).Required(false)).
+		Param(restful.QueryParameter("limit", "查询条数").DataType(typeNameInteger).Required(false)).
+		Notes(enrichGetOperationRecordsApiNotes)
+}
+
+func EnrichGetXDSTrustBundleApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询xDS内置CA的信任包").
+		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
+		Notes(enrichGetXDSTrustBundleApiNotes)
+}
+
+func EnrichImportXDSIntermediateCAApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("导入xDS签发工作负载证书使用的中间CA").
+		Metadata(restfulspec.KeyOpenAPITags, maintainApiTags).
+		Notes(enrichImportXDSIntermediateCAApiNotes)
+}
@@ -89,9 +89,50 @@ func EnrichGetPrincipalResourcesApiDocs(r *restful.RouteBuilder) *restful.RouteB
 		Param(restful.QueryParameter("principal_type", "Principal类别，user/group").
 			DataType(typeNameString).
 			Required(true)).
+		Param(restful.QueryParameter("effect", "ALLOW 查询可操作的资源，DENY 查询被拒绝的资源，默认为 ALLOW").
+			DataType(typeNameString).
+			Required(false)).
 		Notes(enrichGetPrincipalResourcesApiNotes)
 }
 
+func EnrichUpdateStrategyActionApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("修改鉴权策略动作").
+		Metadata(restfulspec.KeyOpenAPITags, authApiTags).
+		Notes(enrichUpdateStrategyActionApiNotes)
+}
+
+func EnrichGetStrategyActionApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询鉴权策略动作").
+		Metadata(restfulspec.KeyOpenAPITags, authApiTags).
+		Param(restful.QueryParameter("id", "策略ID").DataType(typeNameString).Required(true)).
+		Notes(enrichGetStrategyActionApiNotes)
+}
+
+func EnrichGetStrategyConflictsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询鉴权策略冲突").
+		Metadata(restfulspec.KeyOpenAPITags, authApiTags).
+		Param(restful.QueryParameter("id", "策略ID").DataType(typeNameString).Required(true)).
+		Notes(enrichGetStrategyConflictsApiNotes)
+}
+
+func EnrichUpdateStrategyRulesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("修改鉴权策略治理规则资源").
+		Metadata(restfulspec.KeyOpenAPITags, authApiTags).
+		Notes(enrichUpdateStrategyRulesApiNotes)
+}
+
+func EnrichGetStrategyRulesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询鉴权策略治理规则资源").
+		Metadata(restfulspec.KeyOpenAPITags, authApiTags).
+		Param(restful.QueryParameter("id", "策略ID").DataType(typeNameString).Required(true)).
+		Notes(enrichGetStrategyRulesApiNotes)
+}
+
 func EnrichGetStrategyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("获取鉴权策略详细").
@@ -116,6 +157,26 @@ func EnrichLoginApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 		Notes(enrichLoginApiNotes)
 }
 
+func EnrichOIDCLoginApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("OIDC单点登录").
+		Metadata(restfulspec.KeyOpenAPITags, usersApiTags).
+		Notes(enrichOIDCLoginApiNotes)
+}
+
+func EnrichOIDCCallbackApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("OIDC单点登录回调").
+		Metadata(restfulspec.KeyOpenAPITags, usersApiTags).
+		Param(restful.QueryParameter("code", "IdP下发的授权码").
+			DataType(typeNameString).
+			Required(true)).
+		Param(restful.QueryParameter("state", "发起登录时生成的state").
+			DataType(typeNameString).
+			Required(true)).
+		Notes(enrichOIDCCallbackApiNotes)
+}
+
 func EnrichGetUsersApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("获取用户").
@@ -193,6 +254,20 @@ func EnrichResetUserTokenApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder
 		Notes(enrichResetUserTokenApiNotes)
 }
 
+func EnrichCreateScopedTokenApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("申请临时Token").
+		Metadata(restfulspec.KeyOpenAPITags, usersApiTags).
+		Notes(enrichCreateScopedTokenApiNotes)
+}
+
+func EnrichRevokeScopedTokenApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("吊销临时Token").
+		Metadata(restfulspec.KeyOpenAPITags, usersApiTags).
+		Notes(enrichRevokeScopedTokenApiNotes)
+}
+
 func EnrichCreateGroupApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("创建用户组").
@@ -82,10 +82,13 @@ Header X-Polaris-Token: {访问凭据}
           "id": "Polaris"
       }
     ]
-  }
+  },
+  "action": "READ_WRITE"
 }
 ~~~
 
+action 为策略动作，支持 ONLY_READ、READ_WRITE，不填时为 ONLY_READ；按操作授权需要通过 PUT /core/v1/auth/strategy/action 设置
+
 响应示例：
 
 ~~~json
@@ -169,6 +172,9 @@ Header X-Polaris-Token: {访问凭据}
 `
 
 	enrichGetStrategiesApiNotes = `
+查询结果中的 action 只区分 ONLY_READ 以及 READ_WRITE，按操作授权的策略只要包含写操作即为 READ_WRITE，
+具体的操作列表通过 GET /core/v1/auth/strategy/action 查询
+
 请求示例：
 
 ~~~
@@ -254,10 +260,11 @@ GET /core/v1/auth/principal/resources?principal_id=xxx&principal_type=user
 Header X-Polaris-Token: {访问凭据}
 ~~~
 
-| 参数名         | 类型   | 描述                     | 是否必填 |
-|----------------|--------|------------------------|---------|
-| principal_id   | string | 策略ID                   | 是       |
-| principal_type | string | Principal类别，user/group | 是       |
+| 参数名         | 类型   | 描述                                                          | 是否必填 |
+|----------------|--------|-------------------------------------------------------------|---------|
+| principal_id   | string | 策略ID                                                        | 是       |
+| principal_type | string | Principal类别，user/group                                      | 是       |
+| effect         | string | ALLOW 查询可操作的资源，被拒绝策略禁止全部写操作的资源不会返回；DENY 查询被拒绝的资源 | 否       |
 
 
 响应示例：
@@ -352,6 +359,224 @@ Header X-Polaris-Token: {访问凭据}
 ~~~
 `
 
+	enrichUpdateStrategyActionApiNotes = `
+修改鉴权策略的动作以及生效方式，动作决定了策略成员可以对策略中的资源执行哪些操作
+
+- ONLY_READ：只读，不允许创建、修改、删除资源
+- READ_WRITE：读写，允许执行全部操作
+- READ,CREATE,MODIFY,DELETE 任意组合：按操作授权，读操作总是被允许
+
+生效方式 effect 为 ALLOW 时授予上述操作；为 DENY 时禁止策略成员对资源执行上述操作，并且优先于任何 ALLOW 策略。
+effect 不填时保持原有的生效方式。建议先创建不包含成员的策略并设置为 DENY，再添加成员，避免短暂授予权限。
+修改成功后，conflicts 中会返回被该策略覆盖或者覆盖该策略的 ALLOW/DENY 冲突信息。
+
+默认鉴权策略固定为 READ_WRITE 的 ALLOW 策略，不允许修改
+
+请求示例：
+
+~~~
+PUT /core/v1/auth/strategy/action
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+~~~json
+{
+    "id": "xxx",
+    "action": "READ,CREATE,MODIFY",
+    "effect": "ALLOW"
+}
+~~~
+
+| 参数名 | 类型   | 描述                 | 是否必填 |
+|--------|--------|--------------------|---------|
+| id     | string | 策略ID               | 是       |
+| action | string | 策略动作             | 是       |
+| effect | string | 生效方式，ALLOW/DENY | 否       |
+
+响应示例：
+
+~~~json
+{
+    "code": 200000,
+    "info": "execute success",
+    "action": {
+        "id": "xxx",
+        "name": "xxx",
+        "action": "READ,CREATE,MODIFY",
+        "effect": "ALLOW",
+        "operations": [
+            "READ",
+            "CREATE",
+            "MODIFY"
+        ]
+    }
+}
+~~~
+`
+	enrichGetStrategyActionApiNotes = `
+查询鉴权策略的动作以及展开后允许的操作列表
+
+请求示例：
+
+~~~
+GET /core/v1/auth/strategy/action?id=xxx
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+| 参数名 | 类型   | 描述   | 是否必填 |
+|--------|--------|------|---------|
+| id     | string | 策略ID | 是       |
+
+响应示例：
+
+~~~json
+{
+    "code": 200000,
+    "info": "execute success",
+    "action": {
+        "id": "xxx",
+        "name": "xxx",
+        "action": "ONLY_READ",
+        "effect": "ALLOW",
+        "operations": [
+            "READ"
+        ]
+    }
+}
+~~~
+`
+	enrichGetStrategyConflictsApiNotes = `
+查询与该鉴权策略存在冲突的策略。当同一个 owner 下的 ALLOW 策略与 DENY 策略覆盖了同一个成员（包括用户所在的用户组）、
+同一个资源（包括 * 资源）以及同一个写操作时视为冲突，此时 DENY 策略生效
+
+请求示例：
+
+~~~
+GET /core/v1/auth/strategy/conflicts?id=xxx
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+| 参数名 | 类型   | 描述   | 是否必填 |
+|--------|--------|------|---------|
+| id     | string | 策略ID | 是       |
+
+响应示例：
+
+~~~json
+{
+    "code": 200000,
+    "info": "execute success",
+    "size": 1,
+    "conflicts": [
+        {
+            "allow_strategy_id": "xxx",
+            "allow_strategy_name": "ops",
+            "deny_strategy_id": "xxx",
+            "deny_strategy_name": "contractors-deny-production",
+            "principal_id": "xxx",
+            "principal_type": "group",
+            "res_type": "namespace",
+            "res_id": "Production",
+            "operations": [
+                "CREATE",
+                "MODIFY",
+                "DELETE"
+            ]
+        }
+    ]
+}
+~~~
+`
+	enrichUpdateStrategyRulesApiNotes = `
+修改鉴权策略中关联的治理规则资源（路由规则、限流规则、熔断规则、主动探测规则）。规则可以按照ID授权，也可以按照名称通配授权；
+规则没有关联任何策略时，任何人都可以操作，关联了策略后，只有策略中的成员可以执行策略动作中允许的操作，DENY 策略优先生效
+
+请求示例：
+
+~~~
+PUT /core/v1/auth/strategy/rules
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+~~~json
+{
+    "id": "xxx",
+    "add_rules": [
+        {
+            "type": "route_rule",
+            "id": "xxx"
+        },
+        {
+            "type": "ratelimit_rule",
+            "name": "order-*"
+        }
+    ],
+    "remove_rules": [
+        {
+            "type": "circuitbreaker_rule",
+            "id": "*"
+        }
+    ]
+}
+~~~
+
+| 参数名       | 类型   | 描述                                                                        | 是否必填 |
+|--------------|--------|-----------------------------------------------------------------------------|---------|
+| id           | string | 策略ID                                                                      | 是       |
+| add_rules    | array  | 新增的治理规则资源                                                          | 否       |
+| remove_rules | array  | 移除的治理规则资源                                                          | 否       |
+| type         | string | 规则类型：route_rule、ratelimit_rule、circuitbreaker_rule、faultdetect_rule | 是       |
+| id           | string | 规则ID，* 表示该类型下的全部规则，不能与 name 同时设置                        | 否       |
+| name         | string | 规则名称的通配表达式，支持 * 以及 ? 通配，不能与 id 同时设置                   | 否       |
+
+响应示例：
+
+~~~json
+{
+    "code": 200000,
+    "info": "execute success",
+    "id": "xxx",
+    "rules": [
+        {
+            "type": "route_rule",
+            "id": "xxx"
+        },
+        {
+            "type": "ratelimit_rule",
+            "name": "order-*"
+        }
+    ]
+}
+~~~
+`
+	enrichGetStrategyRulesApiNotes = `
+请求示例：
+
+~~~
+GET /core/v1/auth/strategy/rules?id=xxx
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+| 参数名 | 类型   | 描述   | 是否必填 |
+|--------|--------|------|---------|
+| id     | string | 策略ID | 是       |
+
+响应示例：
+
+~~~json
+{
+    "code": 200000,
+    "info": "execute success",
+    "id": "xxx",
+    "rules": [
+        {
+            "type": "route_rule",
+            "id": "xxx"
+        }
+    ]
+}
+~~~
+`
 	enrichDeleteStrategiesApiNotes = `
 请求示例：
 
@@ -424,6 +649,56 @@ POST /core/v1/user/login
 |               | role    | string | 当前用户角色, (admin:超级账户, main:主账户, sub:子账户) |
 |               | user_id | string | 当前用户ID                                              |
 
+`
+
+	enrichOIDCLoginApiNotes = `
+用于控制台发起 OIDC 单点登录，需要在 auth.user.option.oidc 中开启
+
+请求示例：
+
+~~~
+GET /core/v1/user/oidc/login
+~~~
+
+应答为 302 跳转，跳转到 IdP 的授权地址，授权请求使用授权码模式并携带 PKCE(S256) 参数；
+同时下发 HttpOnly 的 polaris_oidc_nonce cookie，有效期和 stateTimeout 一致，回调时必须携带该 cookie
+`
+
+	enrichOIDCCallbackApiNotes = `
+IdP 完成授权后的回调地址，需要和 auth.user.option.oidc.redirectUrl 保持一致，也可以由控制台将 code 以及 state 转发到该接口；
+请求必须携带发起登录时下发的 polaris_oidc_nonce cookie，cookie 与 state 不匹配时拒绝登录，cookie 使用后即被清除
+
+请求示例：
+
+~~~
+GET /core/v1/user/oidc/callback?code=xxx&state=xxx
+~~~
+
+| 参数名 | 类型   | 描述                     | 是否必填 |
+|--------|--------|------------------------|---------|
+| code   | string | IdP 下发的授权码          | 是       |
+| state  | string | 发起登录时生成的 state    | 是       |
+
+ID Token 中 usernameClaim 对应的用户会映射为 owner 下的同名子账户，不存在时按照 autoCreateUser 自动创建；
+开启 syncGroups 后，会按照 groupsClaim 同步用户在 owner 下的用户组关系
+
+应答中的 token 为临时 token，有效期为 sessionTTL(不超过 scopedTokenMaxTTL)，过期后需要重新登录；
+因此开启 OIDC 时必须配置 scopedTokenSecret
+
+应答示例：
+
+~~~json
+{
+	"code": 200000,
+	"info": "execute success",
+	"loginResponse": {
+		"token": "xxx",
+		"name": "xxx",
+		"user_id": "xxx",
+		"role": "sub"
+	}
+}
+~~~
 `
 
 	enrichGetUsersApiNotes = `
@@ -713,6 +988,81 @@ Header X-Polaris-Token: {访问凭据}
 	"info": "execute success"
 }
 ~~~
+`
+
+	enrichCreateScopedTokenApiNotes = `
+为当前用户申请一个带有效期的临时Token，可以进一步限制Token只能操作指定命名空间下的资源或者只能进行读操作，
+适用于 CI 等只需要短期访问的场景。临时Token的权限不会超过当前用户本身的权限，并且不能用于修改用户、用户组、
+鉴权策略，也不能用于查看静态Token或者再次申请临时Token
+
+请求示例：
+
+~~~
+POST /core/v1/user/token/scoped
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+~~~json
+{
+	"ttl": 3600,
+	"namespaces": ["default"],
+	"read_only": true
+}
+~~~
+
+| 参数名     | 类型     | 描述                                                                  | 是否必填 |
+|------------|----------|---------------------------------------------------------------------|---------|
+| ttl        | int      | 有效期，单位秒，默认 3600，最长不超过 auth.user.option.scopedTokenMaxTTL（默认 86400） | 否       |
+| namespaces | string[] | 允许进行写操作的命名空间，为空表示不限制                                  | 否       |
+| read_only  | bool     | 是否只允许读操作                                                        | 否       |
+
+
+响应示例：
+
+~~~json
+{
+	"code": 200000,
+	"info": "execute success",
+	"id": "3b4c0a3e-8b6e-4a3e-9a4e-0c6d1f7b2a11",
+	"token": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.xxx.xxx",
+	"expire_at": 1672531200,
+	"namespaces": ["default"],
+	"read_only": true
+}
+~~~
+`
+
+	enrichRevokeScopedTokenApiNotes = `
+吊销临时Token，只有Token所属用户本人、其主账户以及超级账户可以吊销，吊销结果会在缓存刷新之后在所有节点生效
+
+请求示例：
+
+~~~
+POST /core/v1/user/token/scoped/revoke
+Header X-Polaris-Token: {访问凭据}
+~~~
+
+~~~json
+{
+	"token": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.xxx.xxx"
+}
+~~~
+
+| 参数名 | 类型   | 描述           | 是否必填 |
+|--------|--------|--------------|---------|
+| token  | string | 需要吊销的临时Token | 是       |
+
+
+响应示例：
+
+~~~json
+{
+	"code": 200000,
+	"info": "execute success",
+	"id": "3b4c0a3e-8b6e-4a3e-9a4e-0c6d1f7b2a11",
+	"expire_at": 1672531200
+}
+~~~
 `
 
 	enrichCreateGroupApiNotes = `
@@ -21,6 +21,8 @@ import (
 	"github.com/emicklei/go-restful/v3"
 	restfulspec "github.com/polarismesh/go-restful-openapi/v2"
 	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+
+	"github.com/polarismesh/polaris/config"
 )
 
 var (
@@ -74,6 +76,24 @@ func EnrichUpdateConfigFileGroupApiDocs(r *restful.RouteBuilder) *restful.RouteB
 			"   \"createBy\":\"ledou\"\n}\n```")
 }
 
+func EnrichUpdateConfigFileGroupSchemaApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("设置配置文件组的 JSON Schema，schema 为空表示清除").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigFileGroupSchema{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n   "+
+			" \"schema\":\"{\\\"type\\\":\\\"object\\\",\\\"required\\\":[\\\"port\\\"]}\"\n}\n```")
+}
+
+func EnrichGetConfigFileGroupSchemaApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取配置文件组的 JSON Schema").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType(typeNameString).Required(true))
+}
+
 func EnrichCreateConfigFileApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("创建配置文件").
@@ -200,6 +220,132 @@ func EnrichGetConfigFileReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBu
 		Param(restful.QueryParameter("name", "配置文件").DataType(typeNameString).Required(true))
 }
 
+func EnrichRollbackConfigFileReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("将配置文件回滚到指定的历史发布版本").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigFileReleaseRollbackRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"file_name\":\"application.properties\",\n   "+
+			" \"history_id\":1,\n    \"comment\":\"回滚原因\"\n}\n```")
+}
+
+func EnrichPublishConfigFileGrayApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("灰度发布配置文件，只有命中灰度规则的客户端能够获取到本次发布的内容").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigFileGrayReleaseRequest{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"file_name\":\"application.properties\",\n   "+
+			" \"comment\":\"灰度发布\",\n    \"rule\":{\n        \"client_ips\":[\"10.0.0.0/24\"],\n   "+
+			"     \"client_ids\":[\"client-a\"],\n        \"labels\":{\"env\":\"gray\"}\n    }\n}\n```")
+}
+
+func EnrichGetConfigFileGrayReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取配置文件的全量发布以及进行中的灰度发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType(typeNameString).Required(true))
+}
+
+func EnrichPromoteConfigFileGrayReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("将灰度发布的内容全量发布给所有客户端").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigFileGrayReleaseRequest{}, "```{\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"file_name\":\"application.properties\"\n}\n```")
+}
+
+func EnrichAbandonConfigFileGrayReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("放弃灰度发布，命中灰度的客户端重新获取全量发布的内容").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigFileGrayReleaseRequest{}, "```{\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"file_name\":\"application.properties\"\n}\n```")
+}
+
+func EnrichDiffConfigFileReleaseApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("对比配置文件当前生效的发布与草稿，json、yaml、toml、properties 格式按配置项对比，其余格式按行对比").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType(typeNameString).Required(true))
+}
+
+func EnrichUpdateConfigApprovalPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("设置配置发布审批策略，group 为空表示对整个命名空间生效，命中策略的发布需要审批通过后才会生效").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigApprovalPolicy{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"required_approvals\":2,\n   "+
+			" \"users\":[\"user-a\",\"user-b\"],\n    \"user_groups\":[\"ops\"]\n}\n```")
+}
+
+func EnrichGetConfigApprovalPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("获取配置发布审批策略").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组，为空表示命名空间级别的策略").
+			DataType(typeNameString).Required(false))
+}
+
+func EnrichDeleteConfigApprovalPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("删除配置发布审批策略").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组，为空表示命名空间级别的策略").
+			DataType(typeNameString).Required(false))
+}
+
+func EnrichQueryConfigReleaseRequestsApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("查询配置发布申请").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("name", "配置文件").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("status", "申请状态，pending、approved、rejected、canceled、failed").
+			DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("offset", "查询偏移量").DataType(typeNameInteger).Required(false)).
+		Param(restful.QueryParameter("limit", "查询条数，最多查询100条").DataType(typeNameInteger).Required(false))
+}
+
+func EnrichApproveConfigReleaseRequestApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("同意配置发布申请，同意人数达到审批策略的要求后自动发布").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigReleaseRequestReview{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"id\":1,\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"file_name\":\"application.properties\",\n   "+
+			" \"reason\":\"some reason\"\n}\n```")
+}
+
+func EnrichRejectConfigReleaseRequestApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("驳回配置发布申请").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigReleaseRequestReview{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"id\":1,\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"file_name\":\"application.properties\",\n   "+
+			" \"reason\":\"some reason\"\n}\n```")
+}
+
+func EnrichCancelConfigReleaseRequestApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("撤销配置发布申请，只有发起人可以撤销").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Reads(config.ConfigReleaseRequestReview{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"id\":1,\n    \"namespace\":\"someNamespace\",\n   "+
+			" \"group\":\"someGroup\",\n    \"file_name\":\"application.properties\",\n   "+
+			" \"reason\":\"some reason\"\n}\n```")
+}
+
 func EnrichGetConfigFileReleaseHistoryApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("获取配置文件发布历史记录").
@@ -213,6 +359,17 @@ func EnrichGetConfigFileReleaseHistoryApiDocs(r *restful.RouteBuilder) *restful.
 			Required(true).DefaultValue("100"))
 }
 
+func EnrichDiffConfigFileReleaseHistoryApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.
+		Doc("对比配置文件的两个发布历史").
+		Metadata(restfulspec.KeyOpenAPITags, configConsoleApiTags).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("group", "配置文件分组").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("name", "配置文件").DataType(typeNameString).Required(true)).
+		Param(restful.QueryParameter("fromId", "对比的起始发布历史ID").DataType(typeNameInteger).Required(true)).
+		Param(restful.QueryParameter("toId", "对比的目标发布历史ID").DataType(typeNameInteger).Required(true))
+}
+
 func EnrichGetAllConfigFileTemplatesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
 	return r.
 		Doc("获取配置模板").
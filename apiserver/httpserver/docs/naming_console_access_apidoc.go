@@ -24,6 +24,8 @@ import (
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris/service"
 )
 
 var (
@@ -35,6 +37,8 @@ var (
 	circuitBreakersApiTags     = []string{"CircuitBreakers"}
 	circuitBreakerRulesApiTags = []string{"CircuitBreakerRules"}
 	faultDetectsApiTags        = []string{"FaultDetects"}
+	trafficPoliciesApiTags     = []string{"TrafficPolicies"}
+	accessPoliciesApiTags      = []string{"AccessPolicies"}
 )
 
 const (
@@ -637,3 +641,76 @@ func EnrichEnableRouterRuleApiDocs(r *restful.RouteBuilder) *restful.RouteBuilde
 		Operation("v2EnableRoutings").
 		Notes(enrichEnableRouterRuleApiNotes)
 }
+
+func EnrichCreateTrafficPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("创建流量策略，route_name 为空时对整个服务生效，否则只对同名的路由规则生效").
+		Metadata(restfulspec.KeyOpenAPITags, trafficPoliciesApiTags).
+		Reads(service.TrafficPolicy{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"name\":\"policy\",\n    \"namespace\":\"default\",\n   "+
+			" \"service\":\"svc\",\n    \"route_name\":\"\",\n    \"enable\":true,\n   "+
+			" \"spec\":{\"timeout\":3000,\"retry\":{\"retry_on\":[\"5xx\"],\"num_retries\":2,"+
+			"\"per_try_timeout\":1000},\"fault\":{\"abort\":{\"percentage\":10,\"http_status\":503}}}\n}\n```")
+}
+
+func EnrichUpdateTrafficPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("更新流量策略").
+		Metadata(restfulspec.KeyOpenAPITags, trafficPoliciesApiTags).
+		Reads(service.TrafficPolicy{}, "update traffic policy")
+}
+
+func EnrichDeleteTrafficPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("删除流量策略").
+		Metadata(restfulspec.KeyOpenAPITags, trafficPoliciesApiTags).
+		Reads(service.TrafficPolicy{}, "delete traffic policy, only id is required")
+}
+
+func EnrichGetTrafficPoliciesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("查询流量策略").
+		Metadata(restfulspec.KeyOpenAPITags, trafficPoliciesApiTags).
+		Param(restful.QueryParameter("offset", "分页的起始位置，默认为0").DataType(typeNameInteger).
+			Required(false).DefaultValue("0")).
+		Param(restful.QueryParameter("limit", "每页行数，默认100").DataType(typeNameInteger).
+			Required(false).DefaultValue("100")).
+		Param(restful.QueryParameter("id", "策略ID").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("name", "策略名称，模糊匹配").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("service", "策略所属服务").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("route_name", "策略所关联的路由规则名称").DataType(typeNameString).
+			Required(false))
+}
+
+func EnrichCreateAccessPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("创建访问控制策略，只允许 sources 中的服务通过 mTLS 访问当前服务，未经 mTLS 认证的明文请求会被拒绝，"+
+		"dry_run 为 true 时只在访问日志中记录匹配结果不拦截").
+		Metadata(restfulspec.KeyOpenAPITags, accessPoliciesApiTags).
+		Reads(service.AccessPolicy{}, "开启北极星服务端针对控制台接口鉴权开关后，需要添加下面的 header\nHeader "+
+			" X-Polaris-Token: {访问凭据}\n```{\n    \"name\":\"allow-frontend\",\n    \"namespace\":\"default\",\n   "+
+			" \"service\":\"svc\",\n    \"enable\":true,\n    \"dry_run\":false,\n   "+
+			" \"spec\":{\"sources\":[{\"namespace\":\"default\",\"service\":\"frontend\"}],"+
+			"\"paths\":[\"/api/*\"],\"methods\":[\"GET\"]}\n}\n```")
+}
+
+func EnrichUpdateAccessPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("更新访问控制策略").
+		Metadata(restfulspec.KeyOpenAPITags, accessPoliciesApiTags).
+		Reads(service.AccessPolicy{}, "update access policy")
+}
+
+func EnrichDeleteAccessPolicyApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("删除访问控制策略").
+		Metadata(restfulspec.KeyOpenAPITags, accessPoliciesApiTags).
+		Reads(service.AccessPolicy{}, "delete access policy, only id is required")
+}
+
+func EnrichGetAccessPoliciesApiDocs(r *restful.RouteBuilder) *restful.RouteBuilder {
+	return r.Doc("查询访问控制策略").
+		Metadata(restfulspec.KeyOpenAPITags, accessPoliciesApiTags).
+		Param(restful.QueryParameter("offset", "分页的起始位置，默认为0").DataType(typeNameInteger).
+			Required(false).DefaultValue("0")).
+		Param(restful.QueryParameter("limit", "每页行数，默认100").DataType(typeNameInteger).
+			Required(false).DefaultValue("100")).
+		Param(restful.QueryParameter("id", "策略ID").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("name", "策略名称，模糊匹配").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("namespace", "命名空间").DataType(typeNameString).Required(false)).
+		Param(restful.QueryParameter("service", "策略所属服务").DataType(typeNameString).Required(false))
+}
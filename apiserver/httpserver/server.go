@@ -132,9 +132,10 @@ func (h *HTTPServer) Initialize(_ context.Context, option map[string]interface{}
 			return err
 		}
 		h.tlsInfo = &secure.TLSInfo{
-			CertFile:      tlsConfig.CertFile,
-			KeyFile:       tlsConfig.KeyFile,
-			TrustedCAFile: tlsConfig.TrustedCAFile,
+			CertFile:       tlsConfig.CertFile,
+			KeyFile:        tlsConfig.KeyFile,
+			TrustedCAFile:  tlsConfig.TrustedCAFile,
+			ClientCertAuth: tlsConfig.ClientCertAuth,
 		}
 	}
 
@@ -250,7 +251,11 @@ func (h *HTTPServer) Run(errCh chan error) {
 	if h.tlsInfo.IsEmpty() {
 		err = server.Serve(ln)
 	} else {
-		err = server.ServeTLS(ln, h.tlsInfo.CertFile, h.tlsInfo.KeyFile)
+		// 证书已经加载到 TLSConfig 中，开启 clientCertAuth 时要求客户端提供证书
+		server.TLSConfig, err = h.tlsInfo.ServerConfig()
+		if err == nil {
+			err = server.ServeTLS(ln, "", "")
+		}
 	}
 	if err != nil {
 		log.Errorf("%+v", err)
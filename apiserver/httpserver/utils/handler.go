@@ -41,6 +41,7 @@ import (
 	"github.com/polarismesh/polaris/apiserver/httpserver/i18n"
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/secure"
 	"github.com/polarismesh/polaris/common/utils"
 )
 
@@ -109,6 +110,9 @@ func (h *Handler) postParseMessage(requestID string) (context.Context, error) {
 		operator = staffName
 	}
 	ctx = context.WithValue(ctx, utils.StringContext("operator"), operator)
+	if cert := secure.VerifiedPeerCertificate(h.Request.Request.TLS); cert != nil {
+		ctx = context.WithValue(ctx, utils.ContextClientCertKey, cert)
+	}
 
 	return ctx, nil
 }
@@ -155,6 +159,9 @@ func (h *Handler) ParseHeaderContext() context.Context {
 		operator = staffName
 	}
 	ctx = context.WithValue(ctx, utils.StringContext("operator"), operator)
+	if cert := secure.VerifiedPeerCertificate(h.Request.Request.TLS); cert != nil {
+		ctx = context.WithValue(ctx, utils.ContextClientCertKey, cert)
+	}
 
 	return ctx
 }
@@ -304,6 +311,22 @@ func (h *Handler) WriteHeader(polarisCode uint32, httpStatus int) {
 	h.Response.WriteHeader(httpStatus)
 }
 
+// WriteHeaderAndJson 返回Code和普通的 JSON 对象，用于没有对应 proto 定义的响应
+func (h *Handler) WriteHeaderAndJson(polarisCode uint32, obj interface{}) {
+	requestID := h.Request.HeaderParameter(utils.PolarisRequestID)
+	h.Request.SetAttribute(utils.PolarisCode, polarisCode)
+	status := int(polarisCode / 1000)
+
+	if polarisCode != api.ExecuteSuccess {
+		h.Response.AddHeader(utils.PolarisCode, fmt.Sprintf("%d", polarisCode))
+		h.Response.AddHeader(utils.PolarisMessage, api.Code2Info(polarisCode))
+	}
+	h.Response.AddHeader(utils.PolarisRequestID, requestID)
+	if err := h.Response.WriteHeaderAndJson(status, obj, restful.MIME_JSON); err != nil {
+		log.Error(err.Error(), utils.ZapRequestID(requestID))
+	}
+}
+
 // WriteHeaderAndProto 返回Code和Proto
 func (h *Handler) WriteHeaderAndProto(obj api.ResponseMessage) {
 	requestID := h.Request.HeaderParameter(utils.PolarisRequestID)
@@ -33,6 +33,7 @@ import (
 	httpcommon "github.com/polarismesh/polaris/apiserver/httpserver/utils"
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/service"
 )
 
 const (
@@ -111,6 +112,10 @@ func (h *HTTPServerV1) addDefaultReadAccess(ws *restful.WebService) {
 	ws.Route(docs.EnrichGetCircuitBreakerRulesApiDocs(ws.GET("/circuitbreaker/rules").To(h.GetCircuitBreakerRules)))
 
 	ws.Route(docs.EnrichGetFaultDetectRulesApiDocs(ws.GET("/faultdetectors").To(h.GetFaultDetectRules)))
+
+	ws.Route(docs.EnrichGetTrafficPoliciesApiDocs(ws.GET("/traffic/policies").To(h.GetTrafficPolicies)))
+
+	ws.Route(docs.EnrichGetAccessPoliciesApiDocs(ws.GET("/access/policies").To(h.GetAccessPolicies)))
 }
 
 // addDefaultAccess 增加默认接口
@@ -198,6 +203,18 @@ func (h *HTTPServerV1) addDefaultAccess(ws *restful.WebService) {
 	ws.Route(docs.EnrichUpdateFaultDetectRulesApiDocs(ws.PUT("/faultdetectors").To(h.UpdateFaultDetectRules)))
 	ws.Route(docs.EnrichDeleteFaultDetectRulesApiDocs(
 		ws.POST("/faultdetectors/delete").To(h.DeleteFaultDetectRules)))
+
+	ws.Route(docs.EnrichGetTrafficPoliciesApiDocs(ws.GET("/traffic/policies").To(h.GetTrafficPolicies)))
+	ws.Route(docs.EnrichCreateTrafficPolicyApiDocs(ws.POST("/traffic/policies").To(h.CreateTrafficPolicy)))
+	ws.Route(docs.EnrichUpdateTrafficPolicyApiDocs(ws.PUT("/traffic/policies").To(h.UpdateTrafficPolicy)))
+	ws.Route(docs.EnrichDeleteTrafficPolicyApiDocs(
+		ws.POST("/traffic/policies/delete").To(h.DeleteTrafficPolicy)))
+
+	ws.Route(docs.EnrichGetAccessPoliciesApiDocs(ws.GET("/access/policies").To(h.GetAccessPolicies)))
+	ws.Route(docs.EnrichCreateAccessPolicyApiDocs(ws.POST("/access/policies").To(h.CreateAccessPolicy)))
+	ws.Route(docs.EnrichUpdateAccessPolicyApiDocs(ws.PUT("/access/policies").To(h.UpdateAccessPolicy)))
+	ws.Route(docs.EnrichDeleteAccessPolicyApiDocs(
+		ws.POST("/access/policies/delete").To(h.DeleteAccessPolicy)))
 }
 
 // CreateNamespaces 创建命名空间
@@ -1354,3 +1371,135 @@ func (h *HTTPServerV1) GetFaultDetectRules(req *restful.Request, rsp *restful.Re
 	ret := h.namingServer.GetFaultDetectRules(handler.ParseHeaderContext(), queryParams)
 	handler.WriteHeaderAndProto(ret)
 }
+
+// CreateTrafficPolicy 创建流量策略
+func (h *HTTPServerV1) CreateTrafficPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	policy := &service.TrafficPolicy{}
+	if err := httpcommon.ParseJsonBody(req, policy); err != nil {
+		resp := service.NewTrafficPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.namingServer.CreateTrafficPolicy(handler.ParseHeaderContext(), policy)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// UpdateTrafficPolicy 更新流量策略
+func (h *HTTPServerV1) UpdateTrafficPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	policy := &service.TrafficPolicy{}
+	if err := httpcommon.ParseJsonBody(req, policy); err != nil {
+		resp := service.NewTrafficPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.namingServer.UpdateTrafficPolicy(handler.ParseHeaderContext(), policy)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// DeleteTrafficPolicy 删除流量策略
+func (h *HTTPServerV1) DeleteTrafficPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	policy := &service.TrafficPolicy{}
+	if err := httpcommon.ParseJsonBody(req, policy); err != nil {
+		resp := service.NewTrafficPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.namingServer.DeleteTrafficPolicy(handler.ParseHeaderContext(), policy.ID)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetTrafficPolicies 查询流量策略
+func (h *HTTPServerV1) GetTrafficPolicies(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	queryParams := httpcommon.ParseQueryParams(req)
+	resp := h.namingServer.GetTrafficPolicies(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// CreateAccessPolicy 创建访问控制策略
+func (h *HTTPServerV1) CreateAccessPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	policy := &service.AccessPolicy{}
+	if err := httpcommon.ParseJsonBody(req, policy); err != nil {
+		resp := service.NewAccessPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.namingServer.CreateAccessPolicy(handler.ParseHeaderContext(), policy)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// UpdateAccessPolicy 更新访问控制策略
+func (h *HTTPServerV1) UpdateAccessPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	policy := &service.AccessPolicy{}
+	if err := httpcommon.ParseJsonBody(req, policy); err != nil {
+		resp := service.NewAccessPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.namingServer.UpdateAccessPolicy(handler.ParseHeaderContext(), policy)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// DeleteAccessPolicy 删除访问控制策略
+func (h *HTTPServerV1) DeleteAccessPolicy(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	policy := &service.AccessPolicy{}
+	if err := httpcommon.ParseJsonBody(req, policy); err != nil {
+		resp := service.NewAccessPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+		handler.WriteHeaderAndJson(resp.Code, resp)
+		return
+	}
+
+	resp := h.namingServer.DeleteAccessPolicy(handler.ParseHeaderContext(), policy.ID)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
+
+// GetAccessPolicies 查询访问控制策略
+func (h *HTTPServerV1) GetAccessPolicies(req *restful.Request, rsp *restful.Response) {
+	handler := &httpcommon.Handler{
+		Request:  req,
+		Response: rsp,
+	}
+
+	queryParams := httpcommon.ParseQueryParams(req)
+	resp := h.namingServer.GetAccessPolicies(handler.ParseHeaderContext(), queryParams)
+	handler.WriteHeaderAndJson(resp.Code, resp)
+}
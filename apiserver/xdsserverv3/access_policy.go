@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"strings"
+
+	rbacv3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	rbacfilter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/secure"
+)
+
+const (
+	// httpRBACFilterName envoy rbac http 过滤器的名称
+	httpRBACFilterName = "envoy.filters.http.rbac"
+	// rbacShadowStatPrefix dry run 策略的统计前缀，被拒绝的请求记录在 {prefix}shadow_denied 中
+	rbacShadowStatPrefix = "polaris_access_policy_"
+	// inboundAccessLogFormat 开启访问控制时的入口访问日志格式，在 envoy 默认格式之后追加 dry run 策略的匹配结果
+	inboundAccessLogFormat = `[%START_TIME%] "%REQ(:METHOD)% %REQ(X-ENVOY-ORIGINAL-PATH?:PATH)% %PROTOCOL%" ` +
+		`%RESPONSE_CODE% %RESPONSE_FLAGS% %BYTES_RECEIVED% %BYTES_SENT% %DURATION% ` +
+		`%RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)% "%REQ(X-FORWARDED-FOR)%" "%REQ(USER-AGENT)%" ` +
+		`"%REQ(X-REQUEST-ID)%" "%REQ(:AUTHORITY)%" "%UPSTREAM_HOST%" "%DOWNSTREAM_PEER_URI_SAN%" ` +
+		`shadow_engine_result=%DYNAMIC_METADATA(` + httpRBACFilterName + `:` +
+		rbacShadowStatPrefix + `shadow_engine_result)% ` +
+		`shadow_effective_policy_id=%DYNAMIC_METADATA(` + httpRBACFilterName + `:` +
+		rbacShadowStatPrefix + `shadow_effective_policy_id)%` + "\n"
+)
+
+// makeRBACFilter 将服务的访问控制策略转换为 envoy rbac 过滤器，没有策略时返回 nil
+// 策略按照 mTLS 证书中的身份匹配主调，明文请求没有可信的身份，存在生效的策略时会被拒绝
+func makeRBACFilter(policies []*model.AccessPolicy) *hcm.HttpFilter {
+	if len(policies) == 0 {
+		return nil
+	}
+	enforced := make(map[string]*rbacv3.Policy, len(policies))
+	shadow := make(map[string]*rbacv3.Policy, len(policies))
+	for _, policy := range policies {
+		if policy.Spec == nil || len(policy.Spec.Sources) == 0 {
+			continue
+		}
+		rbacPolicy := makeRBACPolicy(policy.Spec)
+		shadow[policy.Name] = rbacPolicy
+		if !policy.DryRun {
+			enforced[policy.Name] = rbacPolicy
+		}
+	}
+	if len(shadow) == 0 {
+		return nil
+	}
+
+	conf := &rbacfilter.RBAC{
+		ShadowRules: &rbacv3.RBAC{
+			Action:   rbacv3.RBAC_ALLOW,
+			Policies: shadow,
+		},
+		ShadowRulesStatPrefix: rbacShadowStatPrefix,
+	}
+	if len(enforced) > 0 {
+		conf.Rules = &rbacv3.RBAC{
+			Action:   rbacv3.RBAC_ALLOW,
+			Policies: enforced,
+		}
+	}
+	return &hcm.HttpFilter{
+		Name: httpRBACFilterName,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: mustNewAny(conf),
+		},
+	}
+}
+
+func makeRBACPolicy(spec *model.AccessPolicySpec) *rbacv3.Policy {
+	principals := make([]*rbacv3.Principal, 0, len(spec.Sources))
+	for _, source := range spec.Sources {
+		principals = append(principals, makeSourcePrincipal(source))
+	}
+	return &rbacv3.Policy{
+		Permissions: []*rbacv3.Permission{makePermission(spec)},
+		Principals:  principals,
+	}
+}
+
+// makeSourcePrincipal 主调服务的身份为 mTLS 证书中的 SPIFFE ID，通配符转换为前缀匹配
+func makeSourcePrincipal(source *model.AccessSource) *rbacv3.Principal {
+	var nameMatcher *matcherv3.StringMatcher
+	switch {
+	case source.Namespace == model.MatchAll:
+		nameMatcher = &matcherv3.StringMatcher{
+			MatchPattern: &matcherv3.StringMatcher_Prefix{Prefix: secure.SpiffeScheme + "://" + TrustDomain + "/"},
+		}
+	case source.Service == model.MatchAll:
+		nameMatcher = &matcherv3.StringMatcher{
+			MatchPattern: &matcherv3.StringMatcher_Prefix{
+				Prefix: secure.WorkloadSpiffeID(TrustDomain, source.Namespace, ""),
+			},
+		}
+	default:
+		nameMatcher = &matcherv3.StringMatcher{
+			MatchPattern: &matcherv3.StringMatcher_Exact{
+				Exact: secure.WorkloadSpiffeID(TrustDomain, source.Namespace, source.Service),
+			},
+		}
+	}
+	return &rbacv3.Principal{
+		Identifier: &rbacv3.Principal_Authenticated_{
+			Authenticated: &rbacv3.Principal_Authenticated{PrincipalName: nameMatcher},
+		},
+	}
+}
+
+// makePermission 路径之间、方法之间为或的关系，路径与方法之间为且的关系
+func makePermission(spec *model.AccessPolicySpec) *rbacv3.Permission {
+	rules := make([]*rbacv3.Permission, 0, 2)
+	if len(spec.Paths) > 0 {
+		paths := make([]*rbacv3.Permission, 0, len(spec.Paths))
+		for _, path := range spec.Paths {
+			paths = append(paths, makePathPermission(path))
+		}
+		rules = append(rules, orPermission(paths))
+	}
+	if len(spec.Methods) > 0 {
+		methods := make([]*rbacv3.Permission, 0, len(spec.Methods))
+		for _, method := range spec.Methods {
+			methods = append(methods, &rbacv3.Permission{
+				Rule: &rbacv3.Permission_Header{
+					Header: &route.HeaderMatcher{
+						Name: ":method",
+						HeaderMatchSpecifier: &route.HeaderMatcher_StringMatch{
+							StringMatch: &matcherv3.StringMatcher{
+								MatchPattern: &matcherv3.StringMatcher_Exact{Exact: method},
+							},
+						},
+					},
+				},
+			})
+		}
+		rules = append(rules, orPermission(methods))
+	}
+
+	switch len(rules) {
+	case 0:
+		return &rbacv3.Permission{Rule: &rbacv3.Permission_Any{Any: true}}
+	case 1:
+		return rules[0]
+	default:
+		return &rbacv3.Permission{
+			Rule: &rbacv3.Permission_AndRules{AndRules: &rbacv3.Permission_Set{Rules: rules}},
+		}
+	}
+}
+
+func makePathPermission(path string) *rbacv3.Permission {
+	pathMatcher := &matcherv3.StringMatcher{
+		MatchPattern: &matcherv3.StringMatcher_Exact{Exact: path},
+	}
+	if strings.HasSuffix(path, "*") {
+		pathMatcher = &matcherv3.StringMatcher{
+			MatchPattern: &matcherv3.StringMatcher_Prefix{Prefix: strings.TrimSuffix(path, "*")},
+		}
+	}
+	return &rbacv3.Permission{
+		Rule: &rbacv3.Permission_UrlPath{
+			UrlPath: &matcherv3.PathMatcher{
+				Rule: &matcherv3.PathMatcher_Path{Path: pathMatcher},
+			},
+		},
+	}
+}
+
+func orPermission(rules []*rbacv3.Permission) *rbacv3.Permission {
+	if len(rules) == 1 {
+		return rules[0]
+	}
+	return &rbacv3.Permission{
+		Rule: &rbacv3.Permission_OrRules{OrRules: &rbacv3.Permission_Set{Rules: rules}},
+	}
+}
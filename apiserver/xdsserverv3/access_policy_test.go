@@ -0,0 +1,148 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	filev3 "github.com/envoyproxy/go-control-plane/envoy/extensions/access_loggers/file/v3"
+	rbacfilter "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func buildAccessPolicies() []*model.AccessPolicy {
+	return []*model.AccessPolicy{
+		{
+			ServicePolicy: model.ServicePolicy{Name: "allow-frontend"},
+			Spec: &model.AccessPolicySpec{
+				Sources: []*model.AccessSource{
+					{Namespace: "default", Service: "frontend"},
+					{Namespace: "ops", Service: model.MatchAll},
+				},
+				Paths:   []string{"/api/*", "/health"},
+				Methods: []string{"GET"},
+			},
+		},
+		{
+			ServicePolicy: model.ServicePolicy{Name: "audit-all"},
+			DryRun:        true,
+			Spec: &model.AccessPolicySpec{
+				Sources: []*model.AccessSource{{Namespace: model.MatchAll, Service: model.MatchAll}},
+			},
+		},
+	}
+}
+
+func parseRBACFilter(t *testing.T, filter *hcm.HttpFilter) *rbacfilter.RBAC {
+	assert.Equal(t, httpRBACFilterName, filter.GetName())
+	conf := &rbacfilter.RBAC{}
+	assert.NoError(t, filter.GetTypedConfig().UnmarshalTo(conf))
+	return conf
+}
+
+func TestMakeRBACFilter(t *testing.T) {
+	assert.Nil(t, makeRBACFilter(nil))
+
+	conf := parseRBACFilter(t, makeRBACFilter(buildAccessPolicies()))
+	// dry run 的策略只出现在 shadow rules 中
+	assert.Equal(t, 1, len(conf.GetRules().GetPolicies()))
+	assert.Equal(t, 2, len(conf.GetShadowRules().GetPolicies()))
+	assert.Equal(t, rbacShadowStatPrefix, conf.GetShadowRulesStatPrefix())
+
+	policy := conf.GetRules().GetPolicies()["allow-frontend"]
+	principals := policy.GetPrincipals()
+	assert.Equal(t, 2, len(principals))
+	assert.Equal(t, "spiffe://cluster.local/ns/default/sa/frontend",
+		principals[0].GetAuthenticated().GetPrincipalName().GetExact())
+	assert.Equal(t, "spiffe://cluster.local/ns/ops/sa/",
+		principals[1].GetAuthenticated().GetPrincipalName().GetPrefix())
+
+	// 路径与方法之间为且的关系，多个路径之间为或的关系
+	rules := policy.GetPermissions()[0].GetAndRules().GetRules()
+	assert.Equal(t, 2, len(rules))
+	paths := rules[0].GetOrRules().GetRules()
+	assert.Equal(t, "/api/", paths[0].GetUrlPath().GetPath().GetPrefix())
+	assert.Equal(t, "/health", paths[1].GetUrlPath().GetPath().GetExact())
+	assert.Equal(t, ":method", rules[1].GetHeader().GetName())
+	assert.Equal(t, "GET", rules[1].GetHeader().GetStringMatch().GetExact())
+
+	audit := conf.GetShadowRules().GetPolicies()["audit-all"]
+	assert.True(t, audit.GetPermissions()[0].GetAny())
+	assert.Equal(t, "spiffe://cluster.local/",
+		audit.GetPrincipals()[0].GetAuthenticated().GetPrincipalName().GetPrefix())
+
+	// 只有 dry run 的策略时不拦截
+	conf = parseRBACFilter(t, makeRBACFilter(buildAccessPolicies()[1:]))
+	assert.Nil(t, conf.GetRules())
+	assert.Equal(t, 1, len(conf.GetShadowRules().GetPolicies()))
+}
+
+func TestInboundListenerWithAccessPolicy(t *testing.T) {
+	l := inboundListener(buildAccessPolicies())
+	tlsHCM := &hcm.HttpConnectionManager{}
+	assert.NoError(t, l.GetFilterChains()[0].GetFilters()[0].GetTypedConfig().UnmarshalTo(tlsHCM))
+	assert.Equal(t, 2, len(tlsHCM.GetHttpFilters()))
+	assert.NotNil(t, parseRBACFilter(t, tlsHCM.GetHttpFilters()[0]).GetRules())
+	assert.Equal(t, wellknown.Router, tlsHCM.GetHttpFilters()[1].GetName())
+
+	accessLog := &filev3.FileAccessLog{}
+	assert.NoError(t, tlsHCM.GetAccessLog()[0].GetTypedConfig().UnmarshalTo(accessLog))
+	assert.Contains(t, accessLog.GetLogFormat().GetTextFormatSource().GetInlineString(),
+		"%DYNAMIC_METADATA(envoy.filters.http.rbac:polaris_access_policy_shadow_engine_result)%")
+
+	// 明文请求没有可信的身份，同样需要经过生效的策略，避免绕过 mTLS 访问
+	plainHCM := &hcm.HttpConnectionManager{}
+	assert.NoError(t, l.GetDefaultFilterChain().GetFilters()[0].GetTypedConfig().UnmarshalTo(plainHCM))
+	plainRules := parseRBACFilter(t, plainHCM.GetHttpFilters()[0]).GetRules()
+	assert.Equal(t, 1, len(plainRules.GetPolicies()))
+	assert.NotNil(t, plainRules.GetPolicies()["allow-frontend"].GetPrincipals()[0].GetAuthenticated())
+
+	// 没有访问控制策略时不添加 rbac 过滤器
+	l = inboundStrictListener(nil)
+	assert.Nil(t, l.GetDefaultFilterChain())
+	assert.NoError(t, l.GetFilterChains()[0].GetFilters()[0].GetTypedConfig().UnmarshalTo(tlsHCM))
+	assert.Equal(t, 1, len(tlsHCM.GetHttpFilters()))
+	assert.NoError(t, tlsHCM.GetAccessLog()[0].GetTypedConfig().UnmarshalTo(accessLog))
+	assert.Nil(t, accessLog.GetLogFormat())
+}
+
+func TestXDSClient_NeedAccessControl(t *testing.T) {
+	node := &core.Node{
+		Id: "default/12345~127.0.0.1",
+		Metadata: &structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				TLSModeTag:           structpb.NewStringValue(TLSModeStrict),
+				SidecarServiceName:   structpb.NewStringValue("echo"),
+				SidecarNamespaceName: structpb.NewStringValue("prod"),
+			},
+		},
+	}
+	assert.True(t, parseNodeProxy(node).NeedAccessControl())
+	assert.True(t, parseNodeProxy(node).NeedNodeSnapshot())
+	assert.Equal(t, "default/strict#prod/echo", PolarisNodeHash{}.ID(node))
+
+	node.Metadata.Fields[TLSModeTag] = structpb.NewStringValue(TLSModeNone)
+	assert.False(t, parseNodeProxy(node).NeedAccessControl())
+	assert.Equal(t, "default", PolarisNodeHash{}.ID(node))
+}
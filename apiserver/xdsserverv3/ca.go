@@ -0,0 +1,217 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/secure"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	// TrustDomain 内置 CA 签发的 SPIFFE ID 使用的信任域
+	TrustDomain = "cluster.local"
+
+	defaultWorkloadCertTTL = 24 * time.Hour
+	defaultRootCertTTL     = 10 * 365 * 24 * time.Hour
+)
+
+// CAConfig 内置 CA 配置
+type CAConfig struct {
+	// Enable 是否通过 SDS 下发内置 CA 签发的工作负载证书
+	Enable bool `mapstructure:"enable"`
+	// WorkloadCertTTL 工作负载证书的有效期，剩余有效期不足三分之一时自动轮换
+	WorkloadCertTTL time.Duration `mapstructure:"workloadCertTTL"`
+	// RootCertTTL 首次启动时自动生成的根证书有效期
+	RootCertTTL time.Duration `mapstructure:"rootCertTTL"`
+}
+
+// ParseCAConfig 解析内置 CA 配置
+func ParseCAConfig(raw map[interface{}]interface{}) (*CAConfig, error) {
+	conf := &CAConfig{}
+	if raw != nil {
+		decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+			Result:     conf,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := decoder.Decode(raw); err != nil {
+			return nil, err
+		}
+	}
+	if conf.WorkloadCertTTL <= 0 {
+		conf.WorkloadCertTTL = defaultWorkloadCertTTL
+	}
+	if conf.RootCertTTL <= 0 {
+		conf.RootCertTTL = defaultRootCertTTL
+	}
+	if conf.WorkloadCertTTL >= conf.RootCertTTL {
+		return nil, errors.New("workloadCertTTL must be less than rootCertTTL")
+	}
+	return conf, nil
+}
+
+// certManager 负责加载存储中的 CA，为每个连接的 Envoy 签发以及轮换工作负载证书
+type certManager struct {
+	conf    *CAConfig
+	storage store.Store
+
+	lock      sync.RWMutex
+	authority *secure.Authority
+	bundle    string
+	// revision 当前生效的 CA 证书，用于判断 CA 是否发生了变更
+	revision string
+	// certs node id -> 已经签发的工作负载证书
+	certs map[string]*issuedCert
+}
+
+type issuedCert struct {
+	*secure.WorkloadCert
+	// revision 签发证书时生效的 CA
+	revision string
+}
+
+func newCertManager(conf *CAConfig, storage store.Store) (*certManager, error) {
+	mgr := &certManager{
+		conf:    conf,
+		storage: storage,
+		certs:   map[string]*issuedCert{},
+	}
+	if err := mgr.ensureRootCA(); err != nil {
+		return nil, err
+	}
+	if _, err := mgr.reload(); err != nil {
+		return nil, err
+	}
+	return mgr, nil
+}
+
+// ensureRootCA 存储中没有根 CA 时生成一个，多个节点同时生成时以最先写入的为准
+func (m *certManager) ensureRootCA() error {
+	root, err := m.storage.GetCertificateAuthority(model.CertificateAuthorityRoot)
+	if err != nil {
+		return err
+	}
+	if root != nil {
+		return nil
+	}
+	certPEM, keyPEM, err := secure.GenerateRootCA(TrustDomain, m.conf.RootCertTTL)
+	if err != nil {
+		return err
+	}
+	log.Infof("[XDS][CA] generate root ca for trust domain %s", TrustDomain)
+	return m.storage.CreateCertificateAuthority(&model.CertificateAuthority{
+		ID:      model.CertificateAuthorityRoot,
+		CertPEM: certPEM,
+		KeyPEM:  keyPEM,
+	})
+}
+
+// reload 重新读取存储中的 CA，返回 CA 是否发生了变更，变更之后已经签发的证书需要重新签发
+func (m *certManager) reload() (bool, error) {
+	root, err := m.storage.GetCertificateAuthority(model.CertificateAuthorityRoot)
+	if err != nil {
+		return false, err
+	}
+	if root == nil {
+		return false, errors.New("root ca not found")
+	}
+	intermediate, err := m.storage.GetCertificateAuthority(model.CertificateAuthorityIntermediate)
+	if err != nil {
+		return false, err
+	}
+
+	revision := root.CertPEM
+	if intermediate != nil {
+		revision += intermediate.CertPEM + intermediate.ChainPEM
+	}
+	m.lock.RLock()
+	changed := revision != m.revision
+	m.lock.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	rootAuthority, err := secure.NewAuthority(root.CertPEM, root.KeyPEM, root.ChainPEM)
+	if err != nil {
+		return false, err
+	}
+	signer := rootAuthority
+	if intermediate != nil {
+		if signer, err = secure.NewAuthority(intermediate.CertPEM, intermediate.KeyPEM,
+			intermediate.ChainPEM); err != nil {
+			return false, err
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.authority = signer
+	// 切换到导入的中间 CA 之后仍然信任内置根证书，保证已经签发的证书在轮换完成之前可以继续使用
+	m.bundle = secure.TrustBundle(signer, rootAuthority)
+	m.revision = revision
+	log.Infof("[XDS][CA] load ca %s, expire at %s", signer.Certificate().Subject.String(),
+		signer.Certificate().NotAfter.Format(time.RFC3339))
+	return true, nil
+}
+
+// issue 返回节点的工作负载证书以及信任包，证书不存在、身份或者 CA 变化、需要轮换时重新签发
+func (m *certManager) issue(nodeID, spiffeID string) (*secure.WorkloadCert, string, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if cert, ok := m.certs[nodeID]; ok && cert.SpiffeID == spiffeID && cert.revision == m.revision &&
+		!cert.NeedRotate(time.Now()) {
+		return cert.WorkloadCert, m.bundle, nil
+	}
+	cert, err := m.authority.IssueWorkloadCert(spiffeID, m.conf.WorkloadCertTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	m.certs[nodeID] = &issuedCert{WorkloadCert: cert, revision: m.revision}
+	log.Infof("[XDS][CA] issue workload cert %s serial %s for node %s, expire at %s", spiffeID,
+		cert.SerialNumber, nodeID, cert.NotAfter.Format(time.RFC3339))
+	return cert, m.bundle, nil
+}
+
+// nodeIDs 返回已经签发了证书的节点
+func (m *certManager) nodeIDs() []string {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	ret := make([]string, 0, len(m.certs))
+	for id := range m.certs {
+		ret = append(ret, id)
+	}
+	return ret
+}
+
+func (m *certManager) remove(nodeID string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.certs, nodeID)
+}
@@ -0,0 +1,207 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlstrans "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	authmock "github.com/polarismesh/polaris/auth/mock"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/secure"
+	"github.com/polarismesh/polaris/common/utils"
+	storemock "github.com/polarismesh/polaris/store/mock"
+)
+
+// newMockCAStore 模拟只有内置根 CA 的存储，首次读取时不存在
+func newMockCAStore(t *testing.T) *storemock.MockStore {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cas := map[string]*model.CertificateAuthority{}
+	mockStore := storemock.NewMockStore(ctrl)
+	mockStore.EXPECT().GetCertificateAuthority(gomock.Any()).DoAndReturn(
+		func(id string) (*model.CertificateAuthority, error) {
+			return cas[id], nil
+		}).AnyTimes()
+	mockStore.EXPECT().CreateCertificateAuthority(gomock.Any()).DoAndReturn(
+		func(ca *model.CertificateAuthority) error {
+			if _, ok := cas[ca.ID]; !ok {
+				cas[ca.ID] = ca
+			}
+			return nil
+		}).AnyTimes()
+	return mockStore
+}
+
+func TestParseCAConfig(t *testing.T) {
+	conf, err := ParseCAConfig(nil)
+	assert.NoError(t, err)
+	assert.False(t, conf.Enable)
+	assert.Equal(t, defaultWorkloadCertTTL, conf.WorkloadCertTTL)
+
+	conf, err = ParseCAConfig(map[interface{}]interface{}{
+		"enable":          true,
+		"workloadCertTTL": "1h",
+	})
+	assert.NoError(t, err)
+	assert.True(t, conf.Enable)
+	assert.Equal(t, time.Hour, conf.WorkloadCertTTL)
+
+	_, err = ParseCAConfig(map[interface{}]interface{}{
+		"workloadCertTTL": "2h",
+		"rootCertTTL":     "1h",
+	})
+	assert.Error(t, err)
+}
+
+func TestCertManager_Issue(t *testing.T) {
+	conf, _ := ParseCAConfig(map[interface{}]interface{}{"enable": true})
+	mgr, err := newCertManager(conf, newMockCAStore(t))
+	assert.NoError(t, err)
+
+	spiffeID := secure.WorkloadSpiffeID(TrustDomain, "default", "echo")
+	cert, bundle, err := mgr.issue("node-1", spiffeID)
+	assert.NoError(t, err)
+	assert.Equal(t, "spiffe://cluster.local/ns/default/sa/echo", cert.SpiffeID)
+
+	leafs, err := secure.ParseCertificatesPEM(cert.CertChainPEM)
+	assert.NoError(t, err)
+	roots, err := secure.ParseCertificatesPEM(bundle)
+	assert.NoError(t, err)
+	assert.Len(t, roots, 1)
+	pool := x509.NewCertPool()
+	pool.AddCert(roots[0])
+	_, err = leafs[0].Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, spiffeID, secure.SpiffeID(leafs[0]))
+
+	// 证书未到轮换时间时复用
+	again, _, err := mgr.issue("node-1", spiffeID)
+	assert.NoError(t, err)
+	assert.Equal(t, cert.SerialNumber, again.SerialNumber)
+
+	// 每个节点单独签发
+	other, _, err := mgr.issue("node-2", spiffeID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, cert.SerialNumber, other.SerialNumber)
+	assert.ElementsMatch(t, []string{"node-1", "node-2"}, mgr.nodeIDs())
+
+	// 即将过期时重新签发
+	mgr.certs["node-1"].NotAfter = time.Now().Add(time.Minute)
+	rotated, _, err := mgr.issue("node-1", spiffeID)
+	assert.NoError(t, err)
+	assert.NotEqual(t, cert.SerialNumber, rotated.SerialNumber)
+
+	changed, err := mgr.reload()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestXDSServer_pushWorkloadSecrets(t *testing.T) {
+	conf, _ := ParseCAConfig(map[interface{}]interface{}{"enable": true})
+	mgr, err := newCertManager(conf, newMockCAStore(t))
+	assert.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	checker := authmock.NewMockAuthChecker(ctrl)
+	checker.EXPECT().IsOpenClientAuth().Return(true).AnyTimes()
+	// 只有 echo 服务的 token 可以获取 echo 的证书
+	checker.EXPECT().CheckClientPermission(gomock.Any()).DoAndReturn(
+		func(authCtx *model.AcquireContext) (bool, error) {
+			if utils.ParseAuthToken(authCtx.GetRequestContext()) != "echo-token" {
+				return false, model.ErrorTokenInvalid
+			}
+			return true, nil
+		}).AnyTimes()
+
+	x := &XDSServer{
+		certMgr:     mgr,
+		secretCache: cachev3.NewSnapshotCache(false, cachev3.IDHash{}, nil),
+		authChecker: checker,
+		ServiceGetter: func(name, namespace string) *model.Service {
+			if name != "echo" || namespace != "default" {
+				return nil
+			}
+			return &model.Service{ID: "echo-id", Name: name, Namespace: namespace, Owner: "polaris"}
+		},
+	}
+
+	newNode := func(id, service, token string) *XDSClient {
+		node := parseNodeProxy(&core.Node{
+			Id: id,
+			Metadata: &structpb.Struct{Fields: map[string]*structpb.Value{
+				SidecarServiceName: structpb.NewStringValue(service),
+			}},
+		})
+		node.Token = token
+		return node
+	}
+
+	// 节点自行声明的服务不能作为身份依据，没有 token 或者 token 没有服务权限时不签发
+	for _, node := range []*XDSClient{
+		newNode("sidecar~default/11111~127.0.0.1", "echo", ""),
+		newNode("sidecar~default/22222~127.0.0.1", "echo", "other-token"),
+		newNode("sidecar~default/33333~127.0.0.1", "unknown", "echo-token"),
+	} {
+		assert.Error(t, x.pushWorkloadSecrets(node))
+		_, err := x.secretCache.GetSnapshot(node.Node.Id)
+		assert.Error(t, err)
+	}
+
+	node := newNode("sidecar~default/12345~127.0.0.1", "echo", "echo-token")
+	assert.NoError(t, x.pushWorkloadSecrets(node))
+
+	snapshot, err := x.secretCache.GetSnapshot(node.Node.Id)
+	assert.NoError(t, err)
+	secrets := snapshot.GetResources(resource.SecretType)
+	assert.Len(t, secrets, 2)
+
+	tlsCert := secrets[workloadCertSecretName].(*tlstrans.Secret).GetTlsCertificate()
+	leafs, err := secure.ParseCertificatesPEM(string(tlsCert.GetCertificateChain().GetInlineBytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, "spiffe://cluster.local/ns/default/sa/echo", secure.SpiffeID(leafs[0]))
+	assert.NotEmpty(t, secrets[rootCASecretName].(*tlstrans.Secret).GetValidationContext().
+		GetTrustedCa().GetInlineBytes())
+
+	// 没有声明所属服务的节点不下发证书
+	unknown := parseNodeProxy(&core.Node{Id: "sidecar~default/67890~127.0.0.1"})
+	assert.NoError(t, x.pushWorkloadSecrets(unknown))
+	_, err = x.secretCache.GetSnapshot(unknown.Node.Id)
+	assert.Error(t, err)
+
+	// 没有开启客户端鉴权时不签发证书
+	closedChecker := authmock.NewMockAuthChecker(ctrl)
+	closedChecker.EXPECT().IsOpenClientAuth().Return(false).AnyTimes()
+	x.authChecker = closedChecker
+	other := newNode("sidecar~default/44444~127.0.0.1", "echo", "echo-token")
+	assert.ErrorIs(t, x.pushWorkloadSecrets(other), errSDSAuthDisabled)
+}
@@ -19,8 +19,13 @@ package xdsserverv3
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strings"
 
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/server/stream/v3"
 )
 
@@ -30,6 +35,8 @@ type (
 		// hash is the hashing function for Envoy nodes
 		hash     cachev3.NodeHash
 		xdsCache cachev3.SnapshotCache
+		// secretCache SDS 的证书按照节点维度下发，不能与按照命名空间共享的 snapshot 放在一起
+		secretCache cachev3.SnapshotCache
 	}
 
 	// CacheHook
@@ -80,7 +87,7 @@ func (cache *snapshotCache) CreateWatch(request *cachev3.Request, streamState st
 	if cache.hook != nil {
 		cache.hook.OnCreateWatch(request, streamState, value)
 	}
-	return cache.xdsCache.CreateWatch(request, streamState, value)
+	return cache.selectCache(request.TypeUrl).CreateWatch(request, streamState, value)
 }
 
 // CreateDeltaWatch returns a watch for a delta xDS request which implements the Simple SnapshotCache.
@@ -89,7 +96,7 @@ func (cache *snapshotCache) CreateDeltaWatch(request *cachev3.DeltaRequest, stat
 	if cache.hook != nil {
 		cache.hook.OnCreateDeltaWatch(request, state, value)
 	}
-	return cache.xdsCache.CreateDeltaWatch(request, state, value)
+	return cache.selectCache(request.TypeUrl).CreateDeltaWatch(request, state, value)
 }
 
 // Fetch implements the cache fetch function.
@@ -98,7 +105,14 @@ func (cache *snapshotCache) Fetch(ctx context.Context, request *cachev3.Request)
 	if cache.hook != nil {
 		cache.hook.OnFetch(ctx, request)
 	}
-	return cache.xdsCache.Fetch(ctx, request)
+	return cache.selectCache(request.TypeUrl).Fetch(ctx, request)
+}
+
+func (cache *snapshotCache) selectCache(typeURL string) cachev3.SnapshotCache {
+	if cache.secretCache != nil && typeURL == resource.SecretType {
+		return cache.secretCache
+	}
+	return cache.xdsCache
 }
 
 // GetStatusInfo retrieves the status info for the node.
@@ -110,3 +124,34 @@ func (cache *snapshotCache) GetStatusInfo(node string) cachev3.StatusInfo {
 func (cache *snapshotCache) GetStatusKeys() []string {
 	return cache.xdsCache.GetStatusKeys()
 }
+
+// newSnapshot 按照资源类型生成 snapshot，版本号为该类型资源内容的摘要，
+// 内容没有变化的资源类型版本号保持不变，不会重复推送给 Envoy
+func newSnapshot(resources map[resource.Type][]types.Resource) (*cachev3.Snapshot, error) {
+	snapshot := &cachev3.Snapshot{}
+	for typ, items := range resources {
+		index := cachev3.GetResponseType(typ)
+		if index == types.UnknownType {
+			return nil, errors.New("unknown resource type: " + typ)
+		}
+		version, err := resourcesVersion(items)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Resources[index] = cachev3.NewResources(version, items)
+	}
+	return snapshot, nil
+}
+
+func resourcesVersion(items []types.Resource) (string, error) {
+	hashes := make([]string, 0, len(items))
+	for _, item := range items {
+		data, err := cachev3.MarshalResource(item)
+		if err != nil {
+			return "", err
+		}
+		hashes = append(hashes, cachev3.GetResourceName(item)+"/"+cachev3.HashResource(data))
+	}
+	sort.Strings(hashes)
+	return cachev3.HashResource([]byte(strings.Join(hashes, ","))), nil
+}
@@ -19,40 +19,66 @@ package xdsserverv3
 
 import (
 	"context"
+	"strings"
 
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
 	"github.com/gogo/protobuf/jsonpb"
+	"google.golang.org/grpc/metadata"
 
 	commonlog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/utils"
 )
 
 type Callbacks struct {
 	log     *commonlog.Scope
 	nodeMgr *XDSNodeManager
+	// onNodeRemoved 节点的全部 stream 都已经断开时回调
+	onNodeRemoved func(node *XDSClient)
 }
 
 func (cb *Callbacks) Report() {
 
 }
 
-func (cb *Callbacks) OnStreamOpen(_ context.Context, id int64, typ string) error {
+func (cb *Callbacks) OnStreamOpen(ctx context.Context, id int64, typ string) error {
 	if cb.log.DebugEnabled() {
 		cb.log.Debugf("stream %d open for %s", id, typ)
 	}
+	cb.nodeMgr.SetStreamToken(id, streamToken(ctx))
 	return nil
 }
 
+// streamToken 获取 Envoy 通过 grpc_service.initial_metadata 携带的访问凭据
+func streamToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(strings.ToLower(utils.HeaderAuthTokenKey))
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
 func (cb *Callbacks) OnStreamClosed(id int64) {
 	if cb.log.DebugEnabled() {
 		cb.log.Debugf("stream %d closed", id)
 	}
-	cb.nodeMgr.DelNode(id)
+	cb.delNode(id)
+}
+
+func (cb *Callbacks) delNode(streamId int64) {
+	if node := cb.nodeMgr.DelNode(streamId); node != nil && cb.onNodeRemoved != nil {
+		cb.onNodeRemoved(node)
+	}
 }
 
-func (cb *Callbacks) OnDeltaStreamOpen(_ context.Context, id int64, typ string) error {
+func (cb *Callbacks) OnDeltaStreamOpen(ctx context.Context, id int64, typ string) error {
 	if cb.log.DebugEnabled() {
 		cb.log.Debugf("delta stream %d open for %s", id, typ)
 	}
+	cb.nodeMgr.SetStreamToken(deltaStreamKey(id), streamToken(ctx))
 	return nil
 }
 
@@ -60,6 +86,7 @@ func (cb *Callbacks) OnDeltaStreamClosed(id int64) {
 	if cb.log.DebugEnabled() {
 		cb.log.Debugf("delta stream %d closed", id)
 	}
+	cb.delNode(deltaStreamKey(id))
 }
 
 func (cb *Callbacks) OnStreamRequest(id int64, req *discovery.DiscoveryRequest) error {
@@ -88,10 +115,15 @@ func (cb *Callbacks) OnStreamDeltaRequest(id int64, req *discovery.DeltaDiscover
 		str, _ := marshaler.MarshalToString(req)
 		cb.log.Debugf("on stream %d delta type %s request %s", id, req.TypeUrl, str)
 	}
-	cb.nodeMgr.AddNodeIfAbsent(id, req.GetNode())
+	cb.nodeMgr.AddNodeIfAbsent(deltaStreamKey(id), req.GetNode())
 	return nil
 }
 
+// deltaStreamKey sotw 与 delta 两类 stream 的 ID 各自从 1 开始计数，delta stream 使用负数避免冲突
+func deltaStreamKey(id int64) int64 {
+	return -id
+}
+
 func (cb *Callbacks) OnStreamDeltaResponse(id int64, req *discovery.DeltaDiscoveryRequest,
 	resp *discovery.DeltaDiscoveryResponse) {
 	if cb.log.DebugEnabled() {
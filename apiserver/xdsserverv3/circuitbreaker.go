@@ -0,0 +1,219 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"strings"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const (
+	// defaultFaultDetectInterval 探测规则未设置探测周期时的默认值
+	defaultFaultDetectInterval = 10 * time.Second
+	// defaultFaultDetectTimeout 探测规则未设置超时时间时的默认值
+	defaultFaultDetectTimeout = time.Second
+)
+
+// isAnyMatch 规则中的服务或者命名空间为空或者为 * 时匹配任意值
+func isAnyMatch(val string) bool {
+	return val == "" || val == "*"
+}
+
+// selectCircuitBreakerRule 选出可以转换为 outlier detection 的熔断规则
+// 只有实例级别、不区分主调服务以及接口的规则才能在 envoy cluster 上生效，精确匹配被调服务的规则优先
+func selectCircuitBreakerRule(serviceInfo *ServiceInfo) *apifault.CircuitBreakerRule {
+	var wildcard *apifault.CircuitBreakerRule
+	for _, rule := range serviceInfo.CircuitBreaker.GetRules() {
+		if !rule.GetEnable() || rule.GetLevel() != apifault.Level_INSTANCE {
+			continue
+		}
+		source := rule.GetRuleMatcher().GetSource()
+		if !isAnyMatch(source.GetService()) || !isAnyMatch(source.GetNamespace()) {
+			continue
+		}
+		destination := rule.GetRuleMatcher().GetDestination()
+		// envoy cluster 级别的配置无法区分接口
+		if !isAnyMatch(destination.GetMethod().GetValue().GetValue()) {
+			continue
+		}
+		if destination.GetService() == serviceInfo.Name {
+			return rule
+		}
+		if wildcard == nil && isAnyMatch(destination.GetService()) {
+			wildcard = rule
+		}
+	}
+	return wildcard
+}
+
+// makeOutlierDetectionV2 将 v2 熔断规则转换为 envoy 的 outlier detection
+func makeOutlierDetectionV2(serviceInfo *ServiceInfo) *cluster.OutlierDetection {
+	rule := selectCircuitBreakerRule(serviceInfo)
+	if rule == nil {
+		return nil
+	}
+
+	outlierDetection := &cluster.OutlierDetection{
+		// 北极星的熔断规则中没有成功率的概念，关闭 envoy 默认开启的成功率剔除
+		EnforcingSuccessRate:     &wrappers.UInt32Value{Value: 0},
+		EnforcingConsecutive_5Xx: &wrappers.UInt32Value{Value: 0},
+	}
+	var hasTrigger bool
+	for _, trigger := range rule.GetTriggerCondition() {
+		switch trigger.GetTriggerType() {
+		case apifault.TriggerCondition_CONSECUTIVE_ERROR:
+			if trigger.GetErrorCount() == 0 {
+				continue
+			}
+			hasTrigger = true
+			outlierDetection.Consecutive_5Xx = &wrappers.UInt32Value{Value: uint32(trigger.GetErrorCount())}
+			outlierDetection.EnforcingConsecutive_5Xx = &wrappers.UInt32Value{Value: 100}
+		case apifault.TriggerCondition_ERROR_RATE:
+			if trigger.GetErrorPercent() == 0 {
+				continue
+			}
+			hasTrigger = true
+			outlierDetection.FailurePercentageThreshold = &wrappers.UInt32Value{Value: uint32(trigger.GetErrorPercent())}
+			outlierDetection.EnforcingFailurePercentage = &wrappers.UInt32Value{Value: 100}
+			if trigger.GetMinimumRequest() > 0 {
+				outlierDetection.FailurePercentageRequestVolume = &wrappers.UInt32Value{
+					Value: uint32(trigger.GetMinimumRequest())}
+			}
+			if trigger.GetInterval() > 0 {
+				outlierDetection.Interval = durationpb.New(time.Duration(trigger.GetInterval()) * time.Second)
+			}
+		}
+	}
+	if !hasTrigger {
+		return nil
+	}
+
+	if rule.GetMaxEjectionPercent() > 0 {
+		outlierDetection.MaxEjectionPercent = &wrappers.UInt32Value{Value: uint32(rule.GetMaxEjectionPercent())}
+	}
+	if sleepWindow := rule.GetRecoverCondition().GetSleepWindow(); sleepWindow > 0 {
+		outlierDetection.BaseEjectionTime = durationpb.New(time.Duration(sleepWindow) * time.Second)
+	}
+	return outlierDetection
+}
+
+// selectFaultDetectRule 选出可以转换为 health check 的探测规则，精确匹配被调服务的规则优先
+func selectFaultDetectRule(serviceInfo *ServiceInfo) *apifault.FaultDetectRule {
+	var wildcard *apifault.FaultDetectRule
+	for _, rule := range serviceInfo.FaultDetect.GetRules() {
+		// envoy 不支持 UDP 的主动探测
+		if rule.GetProtocol() != apifault.FaultDetectRule_HTTP && rule.GetProtocol() != apifault.FaultDetectRule_TCP {
+			continue
+		}
+		target := rule.GetTargetService()
+		if !isAnyMatch(target.GetMethod().GetValue().GetValue()) {
+			continue
+		}
+		if target.GetService() == serviceInfo.Name {
+			return rule
+		}
+		if wildcard == nil && isAnyMatch(target.GetService()) {
+			wildcard = rule
+		}
+	}
+	return wildcard
+}
+
+// faultDetectPort 探测规则指定的探测端口，为 0 时使用实例端口
+func faultDetectPort(serviceInfo *ServiceInfo) uint32 {
+	rule := selectFaultDetectRule(serviceInfo)
+	if rule == nil {
+		return 0
+	}
+	return uint32(rule.GetPort())
+}
+
+// makeHealthChecks 将探测规则转换为 envoy 的主动健康检查
+func makeHealthChecks(serviceInfo *ServiceInfo) []*core.HealthCheck {
+	rule := selectFaultDetectRule(serviceInfo)
+	if rule == nil {
+		return nil
+	}
+
+	interval := defaultFaultDetectInterval
+	if rule.GetInterval() > 0 {
+		interval = time.Duration(rule.GetInterval()) * time.Second
+	}
+	timeout := defaultFaultDetectTimeout
+	if rule.GetTimeout() > 0 {
+		timeout = time.Duration(rule.GetTimeout()) * time.Millisecond
+	}
+	healthCheck := &core.HealthCheck{
+		Timeout:            durationpb.New(timeout),
+		Interval:           durationpb.New(interval),
+		UnhealthyThreshold: &wrappers.UInt32Value{Value: 1},
+		HealthyThreshold:   &wrappers.UInt32Value{Value: 1},
+	}
+
+	switch rule.GetProtocol() {
+	case apifault.FaultDetectRule_HTTP:
+		httpConfig := rule.GetHttpConfig()
+		// 当前 envoy API 版本的 HTTP 健康检查固定使用 GET 请求，忽略规则中的请求方法以及请求体
+		httpCheck := &core.HealthCheck_HttpHealthCheck{
+			Path: httpConfig.GetUrl(),
+		}
+		if httpCheck.Path == "" {
+			httpCheck.Path = "/"
+		}
+		for _, header := range httpConfig.GetHeaders() {
+			httpCheck.RequestHeadersToAdd = append(httpCheck.RequestHeadersToAdd, &core.HeaderValueOption{
+				Header: &core.HeaderValue{
+					Key:   header.GetKey(),
+					Value: header.GetValue(),
+				},
+			})
+		}
+		healthCheck.HealthChecker = &core.HealthCheck_HttpHealthCheck_{HttpHealthCheck: httpCheck}
+	case apifault.FaultDetectRule_TCP:
+		tcpConfig := rule.GetTcpConfig()
+		tcpCheck := &core.HealthCheck_TcpHealthCheck{}
+		if send := trimHexPrefix(tcpConfig.GetSend()); send != "" {
+			tcpCheck.Send = &core.HealthCheck_Payload{Payload: &core.HealthCheck_Payload_Text{Text: send}}
+		}
+		for _, receive := range tcpConfig.GetReceive() {
+			if receive = trimHexPrefix(receive); receive == "" {
+				continue
+			}
+			tcpCheck.Receive = append(tcpCheck.Receive, &core.HealthCheck_Payload{
+				Payload: &core.HealthCheck_Payload_Text{Text: receive},
+			})
+		}
+		healthCheck.HealthChecker = &core.HealthCheck_TcpHealthCheck_{TcpHealthCheck: tcpCheck}
+	}
+	return []*core.HealthCheck{healthCheck}
+}
+
+// trimHexPrefix 北极星的 TCP 探测报文为 0x 开头的十六进制字符串，envoy 只需要十六进制部分
+func trimHexPrefix(val string) string {
+	val = strings.TrimSpace(val)
+	if strings.HasPrefix(val, "0x") || strings.HasPrefix(val, "0X") {
+		return val[2:]
+	}
+	return val
+}
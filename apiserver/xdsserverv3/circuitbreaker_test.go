@@ -0,0 +1,169 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"os"
+	"testing"
+
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+	testdata "github.com/polarismesh/polaris/test/data"
+)
+
+func buildFaultToleranceServices() []*ServiceInfo {
+	return []*ServiceInfo{
+		{
+			Name:      "cb-svc",
+			Namespace: "default",
+			Instances: []*apiservice.Instance{
+				{
+					Host:    utils.NewStringValue("127.0.0.1"),
+					Port:    utils.NewUInt32Value(8080),
+					Weight:  utils.NewUInt32Value(100),
+					Healthy: utils.NewBoolValue(true),
+				},
+			},
+			CircuitBreaker: &apifault.CircuitBreaker{
+				Rules: []*apifault.CircuitBreakerRule{
+					{
+						// 非实例级别的规则无法转换为 outlier detection
+						Enable: true,
+						Level:  apifault.Level_GROUP,
+						RuleMatcher: &apifault.RuleMatcher{
+							Source:      &apifault.RuleMatcher_SourceService{Service: "*", Namespace: "*"},
+							Destination: &apifault.RuleMatcher_DestinationService{Service: "cb-svc", Namespace: "default"},
+						},
+						TriggerCondition: []*apifault.TriggerCondition{
+							{TriggerType: apifault.TriggerCondition_CONSECUTIVE_ERROR, ErrorCount: 3},
+						},
+					},
+					{
+						Enable: true,
+						Level:  apifault.Level_INSTANCE,
+						RuleMatcher: &apifault.RuleMatcher{
+							Source: &apifault.RuleMatcher_SourceService{Service: "*", Namespace: "*"},
+							Destination: &apifault.RuleMatcher_DestinationService{
+								Service:   "cb-svc",
+								Namespace: "default",
+								Method:    &apimodel.MatchString{Value: &wrappers.StringValue{Value: "*"}},
+							},
+						},
+						TriggerCondition: []*apifault.TriggerCondition{
+							{TriggerType: apifault.TriggerCondition_CONSECUTIVE_ERROR, ErrorCount: 10},
+							{
+								TriggerType:    apifault.TriggerCondition_ERROR_RATE,
+								ErrorPercent:   50,
+								MinimumRequest: 20,
+								Interval:       30,
+							},
+						},
+						MaxEjectionPercent: 90,
+						RecoverCondition:   &apifault.RecoverCondition{SleepWindow: 60, ConsecutiveSuccess: 3},
+					},
+				},
+			},
+			FaultDetect: &apifault.FaultDetector{
+				Rules: []*apifault.FaultDetectRule{
+					{
+						TargetService: &apifault.FaultDetectRule_DestinationService{Service: "cb-svc", Namespace: "default"},
+						Interval:      30,
+						Timeout:       500,
+						Port:          9090,
+						Protocol:      apifault.FaultDetectRule_HTTP,
+						HttpConfig: &apifault.HttpProtocolConfig{
+							Method: "GET",
+							Url:    "/health",
+							Headers: []*apifault.HttpProtocolConfig_MessageHeader{
+								{Key: "x-check", Value: "polaris"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:      "tcp-svc",
+			Namespace: "default",
+			FaultDetect: &apifault.FaultDetector{
+				Rules: []*apifault.FaultDetectRule{
+					{
+						TargetService: &apifault.FaultDetectRule_DestinationService{Service: "*", Namespace: "default"},
+						Protocol:      apifault.FaultDetectRule_TCP,
+						TcpConfig:     &apifault.TcpProtocolConfig{Send: "0x1111", Receive: []string{"0x2223"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMakeFaultToleranceClusters(t *testing.T) {
+	expect, err := os.ReadFile(testdata.Path("xds/circuitbreaker.dump.yaml"))
+	assert.NoError(t, err)
+
+	x := &XDSServer{}
+	services := buildFaultToleranceServices()
+	snapshot, err := newSnapshot(map[resource.Type][]types.Resource{
+		resource.EndpointType: makeEndpoints(services, nil),
+		resource.ClusterType:  x.makeClusters(services),
+	})
+	assert.NoError(t, err)
+
+	dumpYaml := dumpSnapShot(snapshot)
+	assert.Equalf(t, string(expect), string(dumpYaml), "expect : %s, actual : %s", string(expect), string(dumpYaml))
+}
+
+func Test_makeOutlierDetectionV2(t *testing.T) {
+	// 限定了主调服务的规则无法在 envoy cluster 上生效
+	svc := &ServiceInfo{
+		Name: "svc",
+		CircuitBreaker: &apifault.CircuitBreaker{
+			Rules: []*apifault.CircuitBreakerRule{
+				{
+					Enable: true,
+					Level:  apifault.Level_INSTANCE,
+					RuleMatcher: &apifault.RuleMatcher{
+						Source:      &apifault.RuleMatcher_SourceService{Service: "caller", Namespace: "default"},
+						Destination: &apifault.RuleMatcher_DestinationService{Service: "svc", Namespace: "default"},
+					},
+					TriggerCondition: []*apifault.TriggerCondition{
+						{TriggerType: apifault.TriggerCondition_CONSECUTIVE_ERROR, ErrorCount: 3},
+					},
+				},
+			},
+		},
+	}
+	assert.Nil(t, makeOutlierDetectionV2(svc))
+
+	svc.CircuitBreaker.Rules[0].RuleMatcher.Source = &apifault.RuleMatcher_SourceService{Service: "*", Namespace: "*"}
+	ret := makeOutlierDetectionV2(svc)
+	assert.NotNil(t, ret)
+	assert.Equal(t, uint32(3), ret.GetConsecutive_5Xx().GetValue())
+	assert.Nil(t, ret.GetFailurePercentageThreshold())
+
+	svc.CircuitBreaker.Rules[0].Enable = false
+	assert.Nil(t, makeOutlierDetectionV2(svc))
+}
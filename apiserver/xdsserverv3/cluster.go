@@ -48,8 +48,10 @@ func (x *XDSServer) makeCluster(service *ServiceInfo) *cluster.Cluster {
 			},
 		},
 
-		LbSubsetConfig: makeLbSubsetConfig(service),
-		//OutlierDetection: makeOutlierDetection(circuitBreakerConf),
+		LbSubsetConfig:   makeLbSubsetConfig(service),
+		OutlierDetection: makeOutlierDetectionV2(service),
+		HealthChecks:     makeHealthChecks(service),
+		CircuitBreakers:  makeRetryBudget(service),
 	}
 }
 
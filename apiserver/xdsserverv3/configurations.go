@@ -40,6 +40,8 @@ import (
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/polarismesh/polaris/common/model"
 )
 
 func mustNewAny(src proto.Message) *anypb.Any {
@@ -146,7 +148,7 @@ var inboundCommonTLSContext = &tlstrans.CommonTlsContext{
 				MatchSubjectAltNames: []*matcherv3.StringMatcher{
 					{
 						MatchPattern: &matcherv3.StringMatcher_Prefix{
-							Prefix: "spiffe://cluster.local/",
+							Prefix: "spiffe://" + TrustDomain + "/",
 						},
 					},
 				},
@@ -206,21 +208,36 @@ func makeTLSTransportSocket(ctx proto.Message) *core.TransportSocket {
 	}
 }
 
-func inboundHCM() *hcm.HttpConnectionManager {
-	return &hcm.HttpConnectionManager{
-		StatPrefix: "Inbound",
-		HttpFilters: []*hcm.HttpFilter{
-			{
-				Name: wellknown.Router,
+// inboundHCM 入口流量的 http connection manager，rbac 不为空时在路由之前先进行访问控制
+func inboundHCM(rbac *hcm.HttpFilter) *hcm.HttpConnectionManager {
+	httpFilters := make([]*hcm.HttpFilter, 0, 2)
+	accessLog := &filev3.FileAccessLog{
+		Path: "/dev/stdout",
+	}
+	if rbac != nil {
+		httpFilters = append(httpFilters, rbac)
+		// dry run 策略只记录不拦截，需要在访问日志中输出匹配结果才能观察到会被拒绝的请求
+		accessLog.AccessLogFormat = &filev3.FileAccessLog_LogFormat{
+			LogFormat: &core.SubstitutionFormatString{
+				Format: &core.SubstitutionFormatString_TextFormatSource{
+					TextFormatSource: &core.DataSource{
+						Specifier: &core.DataSource_InlineString{InlineString: inboundAccessLogFormat},
+					},
+				},
 			},
-		},
+		}
+	}
+	httpFilters = append(httpFilters, &hcm.HttpFilter{
+		Name: wellknown.Router,
+	})
+	return &hcm.HttpConnectionManager{
+		StatPrefix:  "Inbound",
+		HttpFilters: httpFilters,
 		AccessLog: []*accesslog.AccessLog{
 			{
 				Name: wellknown.FileAccessLog,
 				ConfigType: &accesslog.AccessLog_TypedConfig{
-					TypedConfig: mustNewAny(&filev3.FileAccessLog{
-						Path: "/dev/stdout",
-					}),
+					TypedConfig: mustNewAny(accessLog),
 				},
 			},
 		},
@@ -257,22 +274,24 @@ func inboundHCM() *hcm.HttpConnectionManager {
 	}
 }
 
-func inboundHCMF() *listener.Filter {
+func inboundHCMF(rbac *hcm.HttpFilter) *listener.Filter {
 	return &listener.Filter{
 		Name: "envoy.filters.network.http_connection_manager",
 		ConfigType: &listener.Filter_TypedConfig{
-			TypedConfig: mustNewAny(inboundHCM()),
+			TypedConfig: mustNewAny(inboundHCM(rbac)),
 		},
 	}
 }
 
-func inboundStrictListener() *listener.Listener {
-	l := inboundListener()
+func inboundStrictListener(policies []*model.AccessPolicy) *listener.Listener {
+	l := inboundListener(policies)
 	l.DefaultFilterChain = nil
 	return l
 }
 
-func inboundListener() *listener.Listener {
+// inboundListener 入口流量的 listener，permissive 模式下同时接收明文与 mTLS 请求
+// 明文请求没有可信的身份，服务存在生效的访问控制策略时会被拒绝，只有 dry run 策略时只记录不拦截
+func inboundListener(policies []*model.AccessPolicy) *listener.Listener {
 	return &listener.Listener{
 		Name:             "virtualInbound",
 		TrafficDirection: core.TrafficDirection_INBOUND,
@@ -288,7 +307,7 @@ func inboundListener() *listener.Listener {
 			},
 		},
 		DefaultFilterChain: &listener.FilterChain{
-			Filters: []*listener.Filter{inboundHCMF()},
+			Filters: []*listener.Filter{inboundHCMF(makeRBACFilter(policies))},
 			Name:    "virtualInbound-catchall",
 		},
 		FilterChains: []*listener.FilterChain{
@@ -302,7 +321,7 @@ func inboundListener() *listener.Listener {
 						Value: true,
 					},
 				}),
-				Filters: []*listener.Filter{inboundHCMF()},
+				Filters: []*listener.Filter{inboundHCMF(makeRBACFilter(policies))},
 				Name:    "virtualInbound-catchall-tls",
 			},
 		},
@@ -20,13 +20,10 @@ package xdsserverv3
 import (
 	"context"
 	"fmt"
-	"strconv"
-	"time"
 
 	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
 	v32 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
-	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/golang/protobuf/ptypes/wrappers"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
@@ -58,22 +55,21 @@ func (x *XDSServer) buildGatewayXDSCache(xdsNode *XDSClient, services []*Service
 		services = registryInfo[xdsNode.Namespace]
 	}
 
-	versionLocal := time.Now().Format(time.RFC3339) + "/" + strconv.FormatUint(x.versionNum.Inc(), 10)
-	_ = x.makeGatewaySnapshot(xdsNode, versionLocal, services)
+	_ = x.makeGatewaySnapshot(xdsNode, services)
 	return nil
 }
 
 // makeGatewaySnapshot nodeId must be like gateway~namespace
-func (x *XDSServer) makeGatewaySnapshot(xdsNode *XDSClient, version string, services []*ServiceInfo) (err error) {
+func (x *XDSServer) makeGatewaySnapshot(xdsNode *XDSClient, services []*ServiceInfo) (err error) {
 	namespace := xdsNode.Namespace
 	nodeId := xdsNode.Node.Id
 
 	resources := make(map[resource.Type][]types.Resource)
-	resources[resource.EndpointType] = makeEndpoints(services)
+	resources[resource.EndpointType] = makeEndpoints(services, xdsNode.Locality)
 	resources[resource.ClusterType] = x.makeClusters(services)
 	resources[resource.RouteType] = x.makeGatewayVirtualHosts(namespace, xdsNode)
 	resources[resource.ListenerType] = makeListeners()
-	snapshot, err := cachev3.NewSnapshot(version, resources)
+	snapshot, err := newSnapshot(resources)
 	if err != nil {
 		log.Errorf("[XDS][Gateway] fail to create snapshot for %s, err is %v", nodeId, err)
 		return err
@@ -132,6 +128,7 @@ func (x *XDSServer) makeGatewayRoutes(namespace string, xdsNode *XDSClient) []*r
 	callerNamespace := xdsNode.Metadata[GatewayNamespaceName]
 
 	routerCache := x.namingServer.Cache().RoutingConfig()
+	policyCache := x.namingServer.Cache().TrafficPolicy()
 	routerCache.IteratorRouterRule(func(_ string, rule *model.ExtendRouterConfig) {
 		if !rule.Enable {
 			return
@@ -180,6 +177,14 @@ func (x *XDSServer) makeGatewayRoutes(namespace string, xdsNode *XDSClient) []*r
 					},
 				},
 			}
+			// 流量策略按照第一个目标服务选取，同一个路由下的多个目标服务通常只是不同的版本分组
+			for _, dest := range subRule.GetDestinations() {
+				if dest.Namespace == namespace && dest.Service != utils.MatchAll {
+					policies, _ := policyCache.GetTrafficPolicies(dest.Service, dest.Namespace)
+					applyTrafficPolicy(route, selectTrafficPolicy(policies, rule.Name))
+					break
+				}
+			}
 			routes = append(routes, route)
 		}
 	})
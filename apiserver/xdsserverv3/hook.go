@@ -21,6 +21,7 @@ import (
 	"context"
 
 	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/server/stream/v3"
 )
 
@@ -28,7 +29,14 @@ import (
 func (x *XDSServer) OnCreateWatch(request *cachev3.Request, streamState stream.StreamState,
 	value chan cachev3.Response) {
 	nodeProxy := x.xdsNodesMgr.GetNode(request.Node.Id)
+	if request.TypeUrl == resource.SecretType {
+		_ = x.pushWorkloadSecrets(nodeProxy)
+	}
 	nodeProxy.RunOnce("OnCreateWatch", func() {
+		if nodeProxy.NeedNodeSnapshot() {
+			_ = x.buildSidecarXDSCache(nodeProxy, nil)
+			return
+		}
 		_ = x.buildGatewayXDSCache(nodeProxy, nil)
 	})
 }
@@ -37,13 +45,24 @@ func (x *XDSServer) OnCreateWatch(request *cachev3.Request, streamState stream.S
 func (x *XDSServer) OnCreateDeltaWatch(request *cachev3.DeltaRequest, state stream.StreamState,
 	value chan cachev3.DeltaResponse) {
 	nodeProxy := x.xdsNodesMgr.GetNode(request.Node.Id)
+	if request.TypeUrl == resource.SecretType {
+		_ = x.pushWorkloadSecrets(nodeProxy)
+	}
 	nodeProxy.RunOnce("OnCreateDeltaWatch", func() {
+		if nodeProxy.NeedNodeSnapshot() {
+			_ = x.buildSidecarXDSCache(nodeProxy, nil)
+			return
+		}
 		_ = x.buildGatewayXDSCache(nodeProxy, nil)
 	})
 }
 
 // OnFetch before call cachev3.SnapshotCache OnFetch
 func (x *XDSServer) OnFetch(ctx context.Context, request *cachev3.Request) {
+	// REST 请求没有 stream 携带的访问凭据，只为已经通过 stream 连接的节点签发证书
+	if request.TypeUrl == resource.SecretType && request.Node != nil {
+		_ = x.pushWorkloadSecrets(x.xdsNodesMgr.GetNode(request.Node.Id))
+	}
 	// nodeProxy := x.xdsNodesMgr.GetNode(request.Node.Id)
 	// x.buildGatewayXDSCache(nodeProxy, nil)
 }
@@ -26,6 +26,8 @@ import (
 	"github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/ptypes"
+
+	"github.com/polarismesh/polaris/common/model"
 )
 
 func makeListeners() []types.Resource {
@@ -43,9 +45,12 @@ func makeListeners() []types.Resource {
 				RouteConfigName: "polaris-router",
 			},
 		},
-		HttpFilters: []*hcm.HttpFilter{{
-			Name: wellknown.Router,
-		}},
+		HttpFilters: []*hcm.HttpFilter{
+			makeFaultFilter(),
+			{
+				Name: wellknown.Router,
+			},
+		},
 	}
 
 	pbst, err := ptypes.MarshalAny(manager)
@@ -112,14 +117,14 @@ func makeListeners() []types.Resource {
 	}
 }
 
-func makePermissiveListeners() []types.Resource {
+func makePermissiveListeners(policies []*model.AccessPolicy) []types.Resource {
 	resources := makeListeners()
-	resources = append(resources, inboundListener())
+	resources = append(resources, inboundListener(policies))
 	return resources
 }
 
-func makeStrictListeners() []types.Resource {
+func makeStrictListeners(policies []*model.AccessPolicy) []types.Resource {
 	resources := makeListeners()
-	resources = append(resources, inboundStrictListener())
+	resources = append(resources, inboundStrictListener(policies))
 	return resources
 }
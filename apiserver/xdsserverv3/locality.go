@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"sort"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris/plugin"
+)
+
+// nodeLocality 获取 envoy 节点的地域信息，优先使用 node.locality，否则根据节点 IP 从 CMDB 中查询
+func nodeLocality(node *core.Node) *core.Locality {
+	if node == nil {
+		return nil
+	}
+	if l := declaredLocality(node); l != nil {
+		return l
+	}
+
+	cmdb := plugin.GetCMDB()
+	if cmdb == nil {
+		return nil
+	}
+	_, _, _, hostIP := parseNodeID(node.Id)
+	if hostIP == "" {
+		return nil
+	}
+	location, err := cmdb.GetLocation(hostIP)
+	if err != nil {
+		log.Errorf("[XDS] get location of node %s from cmdb error %v", node.Id, err)
+		return nil
+	}
+	if location == nil || location.Proto == nil {
+		return nil
+	}
+	return toLocality(location.Proto.GetRegion().GetValue(), location.Proto.GetZone().GetValue(),
+		location.Proto.GetCampus().GetValue())
+}
+
+// declaredLocality 节点自身声明的地域信息，未设置时返回 nil
+func declaredLocality(node *core.Node) *core.Locality {
+	if l := node.GetLocality(); l.GetRegion() != "" || l.GetZone() != "" || l.GetSubZone() != "" {
+		return l
+	}
+	return nil
+}
+
+// instanceLocality 实例的地域信息，未设置时返回 nil
+func instanceLocality(ins *apiservice.Instance) *core.Locality {
+	location := ins.GetLocation()
+	return toLocality(location.GetRegion().GetValue(), location.GetZone().GetValue(),
+		location.GetCampus().GetValue())
+}
+
+func toLocality(region, zone, campus string) *core.Locality {
+	if region == "" && zone == "" && campus == "" {
+		return nil
+	}
+	return &core.Locality{
+		Region:  region,
+		Zone:    zone,
+		SubZone: campus,
+	}
+}
+
+func localityKey(l *core.Locality) string {
+	if l == nil {
+		return ""
+	}
+	return l.GetRegion() + "/" + l.GetZone() + "/" + l.GetSubZone()
+}
+
+// localityLevel 计算实例地域与节点地域的距离，与 SDK 就近路由一致，依次按照 campus、zone、region 匹配
+// 节点未设置的字段视为匹配
+func localityLevel(node, ins *core.Locality) uint32 {
+	if node == nil {
+		return 0
+	}
+	match := func(nodeVal, insVal string) bool {
+		return nodeVal == "" || nodeVal == insVal
+	}
+	if !match(node.GetRegion(), ins.GetRegion()) {
+		return 3
+	}
+	if !match(node.GetZone(), ins.GetZone()) {
+		return 2
+	}
+	if !match(node.GetSubZone(), ins.GetSubZone()) {
+		return 1
+	}
+	return 0
+}
+
+// groupLocalityEndpoints 按照实例的地域对 endpoint 分组，并根据与节点地域的距离设置优先级
+// envoy 要求 priority 从 0 开始连续，因此对距离进行压缩
+func groupLocalityEndpoints(node *core.Locality, localities []*core.Locality,
+	lbEndpoints [][]*endpoint.LbEndpoint) []*endpoint.LocalityLbEndpoints {
+	levels := make(map[uint32]struct{})
+	ret := make([]*endpoint.LocalityLbEndpoints, 0, len(localities))
+	for i := range localities {
+		level := localityLevel(node, localities[i])
+		levels[level] = struct{}{}
+		ret = append(ret, &endpoint.LocalityLbEndpoints{
+			Locality:    localities[i],
+			LbEndpoints: lbEndpoints[i],
+			Priority:    level,
+		})
+	}
+
+	sorted := make([]uint32, 0, len(levels))
+	for level := range levels {
+		sorted = append(sorted, level)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	priorities := make(map[uint32]uint32, len(sorted))
+	for i, level := range sorted {
+		priorities[level] = uint32(i)
+	}
+	for i := range ret {
+		ret[i].Priority = priorities[ret[i].Priority]
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		if ret[i].Priority != ret[j].Priority {
+			return ret[i].Priority < ret[j].Priority
+		}
+		return localityKey(ret[i].Locality) < localityKey(ret[j].Locality)
+	})
+	return ret
+}
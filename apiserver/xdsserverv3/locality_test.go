@@ -0,0 +1,122 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func mockLocalityInstance(host, region, zone, campus string) *apiservice.Instance {
+	return &apiservice.Instance{
+		Host:    utils.NewStringValue(host),
+		Port:    utils.NewUInt32Value(8080),
+		Weight:  utils.NewUInt32Value(100),
+		Healthy: utils.NewBoolValue(true),
+		Location: &apimodel.Location{
+			Region: utils.NewStringValue(region),
+			Zone:   utils.NewStringValue(zone),
+			Campus: utils.NewStringValue(campus),
+		},
+	}
+}
+
+func Test_makeEndpointsWithLocality(t *testing.T) {
+	services := []*ServiceInfo{
+		{
+			Name: "svc",
+			Instances: []*apiservice.Instance{
+				mockLocalityInstance("127.0.0.1", "r1", "z1", "c1"),
+				mockLocalityInstance("127.0.0.2", "r1", "z2", "c1"),
+				mockLocalityInstance("127.0.0.3", "r2", "z3", "c1"),
+				mockLocalityInstance("127.0.0.4", "r1", "z1", "c1"),
+			},
+		},
+	}
+
+	t.Run("node_in_zone", func(t *testing.T) {
+		ret := makeEndpoints(services, &core.Locality{Region: "r1", Zone: "z1"})
+		cla := ret[0].(*endpoint.ClusterLoadAssignment)
+		assert.Len(t, cla.Endpoints, 3)
+		assert.Equal(t, "z1", cla.Endpoints[0].Locality.Zone)
+		assert.Equal(t, uint32(0), cla.Endpoints[0].Priority)
+		assert.Len(t, cla.Endpoints[0].LbEndpoints, 2)
+		assert.Equal(t, "z2", cla.Endpoints[1].Locality.Zone)
+		assert.Equal(t, uint32(1), cla.Endpoints[1].Priority)
+		assert.Equal(t, "r2", cla.Endpoints[2].Locality.Region)
+		assert.Equal(t, uint32(2), cla.Endpoints[2].Priority)
+	})
+
+	t.Run("node_without_locality", func(t *testing.T) {
+		ret := makeEndpoints(services, nil)
+		cla := ret[0].(*endpoint.ClusterLoadAssignment)
+		assert.Len(t, cla.Endpoints, 3)
+		for _, item := range cla.Endpoints {
+			assert.Equal(t, uint32(0), item.Priority)
+		}
+	})
+
+	t.Run("node_in_other_region", func(t *testing.T) {
+		// 优先级需要从 0 开始连续
+		ret := makeEndpoints(services, &core.Locality{Region: "r3", Zone: "z9"})
+		cla := ret[0].(*endpoint.ClusterLoadAssignment)
+		for _, item := range cla.Endpoints {
+			assert.Equal(t, uint32(0), item.Priority)
+		}
+	})
+}
+
+func Test_makeEndpointsWithoutInstanceLocality(t *testing.T) {
+	services := []*ServiceInfo{{Name: "empty"}}
+	ret := makeEndpoints(services, &core.Locality{Region: "r1"})
+	cla := ret[0].(*endpoint.ClusterLoadAssignment)
+	assert.Len(t, cla.Endpoints, 1)
+	assert.Nil(t, cla.Endpoints[0].Locality)
+	assert.Empty(t, cla.Endpoints[0].LbEndpoints)
+}
+
+func TestNodeHashIDWithLocality(t *testing.T) {
+	node := &core.Node{
+		Id:       "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1",
+		Locality: &core.Locality{Region: "r1", Zone: "z1"},
+	}
+	assert.Equal(t, "default@r1/z1/", PolarisNodeHash{}.ID(node))
+	assert.True(t, parseNodeProxy(node).NeedNodeSnapshot())
+}
+
+func TestNodeHashIDUseConnectedNode(t *testing.T) {
+	const nodeID = "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1"
+	mgr := newXDSNodeManager()
+	mgr.AddNodeIfAbsent(1, &core.Node{
+		Id:       nodeID,
+		Locality: &core.Locality{Region: "r1", Zone: "z1"},
+	})
+	assert.True(t, mgr.HasSnapshotKey("default@r1/z1/"))
+
+	// 已经连接的节点直接使用连接时解析的 snapshot key，不再重新获取地域信息
+	hash := PolarisNodeHash{nodes: mgr}
+	assert.Equal(t, "default@r1/z1/", hash.ID(&core.Node{Id: nodeID}))
+	assert.Equal(t, "default", hash.ID(&core.Node{Id: "default/1f6b6d3c-4d5e-4f0a~172.17.1.2"}))
+}
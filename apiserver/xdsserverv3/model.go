@@ -18,6 +18,7 @@
 package xdsserverv3
 
 import (
+	apifault "github.com/polarismesh/specification/source/go/api/v1/fault_tolerance"
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 
@@ -39,17 +40,30 @@ const (
 
 // ServiceInfo 北极星服务结构体
 type ServiceInfo struct {
-	ID                   string
-	Name                 string
-	Namespace            string
-	AliasFor             *model.Service
-	Instances            []*apiservice.Instance
-	SvcInsRevision       string
-	Routing              *traffic_manage.Routing
-	SvcRoutingRevision   string
-	Ports                string
+	ID                 string
+	Name               string
+	Namespace          string
+	AliasFor           *model.Service
+	Instances          []*apiservice.Instance
+	SvcInsRevision     string
+	Routing            *traffic_manage.Routing
+	SvcRoutingRevision string
+	Ports              string
+	// Metadata 服务的元数据
+	Metadata             map[string]string
 	RateLimit            *traffic_manage.RateLimit
 	SvcRateLimitRevision string
+	// CircuitBreaker v2 熔断规则，转换为 envoy cluster 的 outlier detection
+	CircuitBreaker            *apifault.CircuitBreaker
+	SvcCircuitBreakerRevision string
+	// FaultDetect 主动探测规则，转换为 envoy cluster 的 health check
+	FaultDetect            *apifault.FaultDetector
+	SvcFaultDetectRevision string
+	// TrafficPolicies 流量策略，转换为 envoy route 的超时、重试以及故障注入配置
+	TrafficPolicies          []*model.TrafficPolicy
+	SvcTrafficPolicyRevision string
+	// SvcAccessPolicyRevision 访问控制策略的版本，策略变化时需要重新生成服务所属 sidecar 的 snapshot
+	SvcAccessPolicyRevision string
 }
 
 func (s *ServiceInfo) matchService(ns, name string) bool {
@@ -33,6 +33,7 @@ func newXDSNodeManager() *XDSNodeManager {
 		streamTonodes: map[int64]*XDSClient{},
 		sidecarNodes:  map[string]*XDSClient{},
 		gatewayNodes:  map[string]*XDSClient{},
+		streamTokens:  map[int64]string{},
 	}
 }
 
@@ -44,6 +45,19 @@ type XDSNodeManager struct {
 	sidecarNodes map[string]*XDSClient
 	// gatewayNodes The XDS client is the node list of the Gateway run mode
 	gatewayNodes map[string]*XDSClient
+	// streamTokens stream 建立时通过 gRPC metadata 携带的访问凭据
+	streamTokens map[int64]string
+}
+
+// SetStreamToken 记录 stream 携带的访问凭据，在收到节点的第一个请求时绑定到节点上
+func (x *XDSNodeManager) SetStreamToken(streamId int64, token string) {
+	if token == "" {
+		return
+	}
+	x.lock.Lock()
+	defer x.lock.Unlock()
+
+	x.streamTokens[streamId] = token
 }
 
 func (x *XDSNodeManager) AddNodeIfAbsent(streamId int64, node *core.Node) {
@@ -54,6 +68,7 @@ func (x *XDSNodeManager) AddNodeIfAbsent(streamId int64, node *core.Node) {
 	defer x.lock.Unlock()
 
 	p := parseNodeProxy(node)
+	p.Token = x.streamTokens[streamId]
 
 	if _, ok := x.streamTonodes[streamId]; !ok {
 		x.streamTonodes[streamId] = p
@@ -74,14 +89,40 @@ func (x *XDSNodeManager) AddNodeIfAbsent(streamId int64, node *core.Node) {
 	}
 }
 
-func (x *XDSNodeManager) DelNode(streamId int64) {
+// DelNode 移除 stream 关联的节点，节点没有其他 stream 时返回被移除的节点，否则返回 nil
+func (x *XDSNodeManager) DelNode(streamId int64) *XDSClient {
 	x.lock.Lock()
 	defer x.lock.Unlock()
 
-	if p, ok := x.streamTonodes[streamId]; ok {
-		delete(x.nodes, p.Node.Id)
-	}
+	p, ok := x.streamTonodes[streamId]
 	delete(x.streamTonodes, streamId)
+	delete(x.streamTokens, streamId)
+	if !ok {
+		return nil
+	}
+	// 同一个节点可能同时存在多个 stream，例如 SDS 单独建立的 stream 或者重连时新旧 stream 并存
+	for _, other := range x.streamTonodes {
+		if other.Node.Id == p.Node.Id {
+			return nil
+		}
+	}
+	delete(x.nodes, p.Node.Id)
+	delete(x.sidecarNodes, p.Node.Id)
+	delete(x.gatewayNodes, p.Node.Id)
+	return p
+}
+
+// HasSnapshotKey 是否还有已经连接的节点使用该 snapshot key
+func (x *XDSNodeManager) HasSnapshotKey(key string) bool {
+	x.lock.RLock()
+	defer x.lock.RUnlock()
+
+	for _, node := range x.nodes {
+		if node.SnapshotKey == key {
+			return true
+		}
+	}
+	return false
 }
 
 func (x *XDSNodeManager) GetNodeByStreamID(streamId int64) *XDSClient {
@@ -109,6 +150,20 @@ func (x *XDSNodeManager) ListGatewayNodes() []*XDSClient {
 	return ret
 }
 
+// ListSidecarNodes 返回需要单独生成 snapshot 的 sidecar 节点
+func (x *XDSNodeManager) ListSidecarNodes() []*XDSClient {
+	x.lock.RLock()
+	defer x.lock.RUnlock()
+
+	ret := make([]*XDSClient, 0, len(x.sidecarNodes))
+	for i := range x.sidecarNodes {
+		if x.sidecarNodes[i].NeedNodeSnapshot() {
+			ret = append(ret, x.sidecarNodes[i])
+		}
+	}
+	return ret
+}
+
 // ID id 的格式是 ${sidecar|gateway}~namespace/uuid~hostIp
 // case 1: envoy 为 sidecar 模式时，则 NodeID 的格式为以下两种
 //
@@ -116,27 +171,61 @@ func (x *XDSNodeManager) ListGatewayNodes() []*XDSClient {
 //	eg 2. sidecar~namespace/uuid-hostIp
 //
 // case 2: envoy 为 gateway 模式时，则 NodeID 的格式为： gateway~namespace/uuid~hostIp
-func (PolarisNodeHash) ID(node *core.Node) string {
+// case 3: sidecar 声明了依赖服务时，每个节点单独生成 snapshot，直接使用 NodeID
+// case 4: sidecar 带有地域信息时，相同地域的节点共享 snapshot，格式为 namespace[/tlsMode]@region/zone/subZone
+// case 5: proxyless gRPC 时，NodeID 的格式为 proxyless~namespace/uuid~hostIp，同一个命名空间共享 snapshot
+// case 6: sidecar 开启 mTLS 并声明了所属服务时，入口需要按照服务的访问控制策略鉴权，相同服务的节点共享 snapshot，
+// 格式为 namespace/tlsMode#serviceNamespace/service[@region/zone/subZone]
+// snapshot cache 每次请求都会计算 hash，已经连接的节点直接使用连接时解析好的 snapshot key，避免重复查询 CMDB
+func (h PolarisNodeHash) ID(node *core.Node) string {
 	if node == nil {
 		return ""
 	}
+	if h.nodes != nil {
+		if client := h.nodes.GetNode(node.Id); client != nil {
+			return client.SnapshotKey
+		}
+	}
+	return nodeSnapshotKey(node, declaredLocality(node))
+}
 
+// nodeSnapshotKey 根据节点信息以及节点所在的地域计算 snapshot key
+func nodeSnapshotKey(node *core.Node, locality *core.Locality) string {
 	runType, ns, _, _ := parseNodeID(node.Id)
 	if runType == string(RunTypeSidecar) {
 		ret := ns
 		if node.Metadata != nil && node.Metadata.Fields != nil {
+			if node.Metadata.Fields[SidecarDependencies].GetStringValue() != "" {
+				return node.Id
+			}
 			tlsMode := node.Metadata.Fields[TLSModeTag].GetStringValue()
 			if tlsMode == TLSModePermissive || tlsMode == TLSModeStrict {
-				return ret + "/" + tlsMode
+				ret = ret + "/" + tlsMode
+				if svc := node.Metadata.Fields[SidecarServiceName].GetStringValue(); svc != "" {
+					svcNamespace := node.Metadata.Fields[SidecarNamespaceName].GetStringValue()
+					if svcNamespace == "" {
+						svcNamespace = ns
+					}
+					ret = ret + "#" + svcNamespace + "/" + svc
+				}
 			}
 		}
+		if locality != nil {
+			ret = ret + "@" + localityKey(locality)
+		}
 		return ret
 	}
+	if runType == string(RunTypeProxyless) {
+		return ns + "/" + string(RunTypeProxyless)
+	}
 	return node.Id
 }
 
 // PolarisNodeHash 存放 hash 方法
-type PolarisNodeHash struct{}
+type PolarisNodeHash struct {
+	// nodes 已经连接的节点，为空时只使用节点自身声明的地域信息
+	nodes *XDSNodeManager
+}
 
 // node id 的格式是:
 // 1. namespace/uuid~hostIp
@@ -169,6 +258,8 @@ var (
 	RunTypeGateway RunType = "gateway"
 	// RunTypeSidecar xds node run type is sidecar
 	RunTypeSidecar RunType = "sidecar"
+	// RunTypeProxyless xds node run type is proxyless grpc
+	RunTypeProxyless RunType = "proxyless"
 )
 
 const (
@@ -177,6 +268,13 @@ const (
 	GatewayNamespaceName = "gateway_namespace"
 	// GatewayNamespaceName xds metadata key
 	GatewayServiceName = "gateway_service"
+	// SidecarServiceName xds metadata key, sidecar 所属的服务
+	SidecarServiceName = "sidecar.polarismesh.cn/serviceName"
+	// SidecarNamespaceName xds metadata key, sidecar 所属服务的命名空间，为空时使用 node id 中的命名空间
+	SidecarNamespaceName = "sidecar.polarismesh.cn/serviceNamespace"
+	// SidecarDependencies xds metadata key, sidecar 依赖的服务列表，以逗号分隔，只能是 sidecar 所在命名空间下的服务
+	// 声明后只下发依赖服务的配置，未声明时下发整个命名空间的配置
+	SidecarDependencies = "sidecar.polarismesh.cn/dependencies"
 )
 
 // XDSClient 客户端代码结构体
@@ -188,6 +286,12 @@ type XDSClient struct {
 	Metadata  map[string]string
 	Version   string
 	Node      *core.Node
+	// Locality 节点所在的地域，用于计算 endpoint 的优先级
+	Locality *core.Locality
+	// SnapshotKey 节点使用的 snapshot key，在节点连接时计算一次
+	SnapshotKey string
+	// Token 节点建立 stream 时携带的访问凭据，node metadata 由客户端任意声明，不能作为身份依据
+	Token string
 
 	lock sync.Mutex
 	once map[string]*sync.Once
@@ -210,6 +314,55 @@ func (n *XDSClient) IsGateway() bool {
 	return n.RunType == RunTypeGateway && service != "" && namespace != ""
 }
 
+// HasDependencies sidecar 是否声明了依赖服务
+func (n *XDSClient) HasDependencies() bool {
+	return n.RunType == RunTypeSidecar && n.Metadata[SidecarDependencies] != ""
+}
+
+// NeedNodeSnapshot sidecar 是否需要单独生成 snapshot，而不是使用命名空间级别的 snapshot
+func (n *XDSClient) NeedNodeSnapshot() bool {
+	return n.HasDependencies() || n.NeedAccessControl() || (n.RunType == RunTypeSidecar && n.Locality != nil)
+}
+
+// NeedAccessControl sidecar 开启了 mTLS 并且声明了所属服务，入口流量需要按照服务的访问控制策略鉴权
+func (n *XDSClient) NeedAccessControl() bool {
+	if n.RunType != RunTypeSidecar || n.Metadata[SidecarServiceName] == "" {
+		return false
+	}
+	tlsMode := n.Metadata[TLSModeTag]
+	return tlsMode == TLSModePermissive || tlsMode == TLSModeStrict
+}
+
+// Dependencies 解析 sidecar 声明的依赖服务名，sidecar 自身所属的服务总是包含在内
+func (n *XDSClient) Dependencies() map[string]struct{} {
+	ret, _ := n.parseDependencies()
+	return ret
+}
+
+// parseDependencies 支持 namespace/service 的写法，但只接受 sidecar 所在命名空间下的服务，
+// 其他命名空间的服务不会下发，通过 ignored 返回
+func (n *XDSClient) parseDependencies() (map[string]struct{}, []string) {
+	ret := make(map[string]struct{})
+	var ignored []string
+	for _, item := range strings.Split(n.Metadata[SidecarDependencies], ",") {
+		item = strings.TrimSpace(item)
+		if idx := strings.Index(item, "/"); idx >= 0 {
+			if item[:idx] != n.Namespace {
+				ignored = append(ignored, item)
+				continue
+			}
+			item = item[idx+1:]
+		}
+		if item != "" {
+			ret[item] = struct{}{}
+		}
+	}
+	if svc := n.Metadata[SidecarServiceName]; svc != "" {
+		ret[svc] = struct{}{}
+	}
+	return ret, ignored
+}
+
 func parseNodeProxy(node *core.Node) *XDSClient {
 	runType, polarisNamespace, _, hostIP := parseNodeID(node.Id)
 	proxy := &XDSClient{
@@ -220,6 +373,15 @@ func parseNodeProxy(node *core.Node) *XDSClient {
 		once:      make(map[string]*sync.Once),
 	}
 	proxy.Metadata = parseMetadata(node.GetMetadata())
+	proxy.Locality = nodeLocality(node)
+	proxy.SnapshotKey = nodeSnapshotKey(node, proxy.Locality)
+	if proxy.HasDependencies() {
+		if _, ignored := proxy.parseDependencies(); len(ignored) > 0 {
+			log.Warnf("[XDS][Sidecar] node %s declares dependencies %v outside namespace %s, "+
+				"cross-namespace dependencies are not supported and will be ignored",
+				node.Id, ignored, proxy.Namespace)
+		}
+	}
 	return proxy
 }
 
@@ -17,7 +17,17 @@
 
 package xdsserverv3
 
-import "testing"
+import (
+	"context"
+	"testing"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
 
 func Test_parseNodeID(t *testing.T) {
 	type args struct {
@@ -110,3 +120,94 @@ func Test_parseNodeID(t *testing.T) {
 		})
 	}
 }
+
+func TestXDSClient_Dependencies(t *testing.T) {
+	node := parseNodeProxy(&core.Node{
+		Id: "default/12345~127.0.0.1",
+		Metadata: &structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				SidecarServiceName:  structpb.NewStringValue("self"),
+				SidecarDependencies: structpb.NewStringValue("svc-a, default/svc-b,other/svc-c,"),
+			},
+		},
+	})
+	assert.True(t, node.HasDependencies())
+	assert.Equal(t, map[string]struct{}{
+		"self":  {},
+		"svc-a": {},
+		"svc-b": {},
+	}, node.Dependencies())
+
+	_, ignored := node.parseDependencies()
+	assert.Equal(t, []string{"other/svc-c"}, ignored)
+
+	services := []*ServiceInfo{{Name: "self"}, {Name: "svc-a"}, {Name: "svc-c"}, {Name: "svc-d"}}
+	ret := filterDependencies(node, services)
+	assert.Equal(t, []*ServiceInfo{services[0], services[1]}, ret)
+
+	node = parseNodeProxy(&core.Node{Id: "default/12345~127.0.0.1"})
+	assert.False(t, node.HasDependencies())
+}
+
+func TestXDSNodeManager_DelNode(t *testing.T) {
+	mgr := newXDSNodeManager()
+	x := &XDSServer{
+		xdsNodesMgr: mgr,
+		cache:       cachev3.NewSnapshotCache(false, PolarisNodeHash{}, nil),
+	}
+	cb := &Callbacks{nodeMgr: mgr, onNodeRemoved: x.clearNodeSnapshot}
+
+	sidecar := &core.Node{
+		Id: "default/12345~127.0.0.1",
+		Metadata: &structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				SidecarDependencies: structpb.NewStringValue("svc-a"),
+			},
+		},
+	}
+	gateway := &core.Node{
+		Id: "gateway~default/67890~127.0.0.2",
+		Metadata: &structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				GatewayServiceName:   structpb.NewStringValue("gw"),
+				GatewayNamespaceName: structpb.NewStringValue("default"),
+			},
+		},
+	}
+	// sidecar 同时建立了两个 stream
+	mgr.AddNodeIfAbsent(1, sidecar)
+	mgr.AddNodeIfAbsent(2, sidecar)
+	mgr.AddNodeIfAbsent(3, gateway)
+
+	snapshot, err := newSnapshot(map[resource.Type][]types.Resource{})
+	assert.NoError(t, err)
+	for _, node := range []*core.Node{sidecar, gateway} {
+		assert.NoError(t, x.cache.SetSnapshot(context.Background(), PolarisNodeHash{}.ID(node), snapshot))
+	}
+	assert.NoError(t, x.cache.SetSnapshot(context.Background(), "default", snapshot))
+
+	t.Run("节点还有其他stream-不移除", func(t *testing.T) {
+		cb.OnStreamClosed(1)
+		assert.NotNil(t, mgr.GetNode(sidecar.Id))
+		assert.Len(t, mgr.ListSidecarNodes(), 1)
+		_, err := x.cache.GetSnapshot(sidecar.Id)
+		assert.NoError(t, err)
+	})
+
+	t.Run("节点全部stream断开-移除节点以及snapshot", func(t *testing.T) {
+		cb.OnStreamClosed(2)
+		assert.Nil(t, mgr.GetNode(sidecar.Id))
+		assert.Empty(t, mgr.ListSidecarNodes())
+		_, err := x.cache.GetSnapshot(sidecar.Id)
+		assert.Error(t, err)
+
+		cb.OnStreamClosed(3)
+		assert.Empty(t, mgr.ListGatewayNodes())
+		_, err = x.cache.GetSnapshot(gateway.Id)
+		assert.Error(t, err)
+
+		// 命名空间级别的 snapshot 不受影响
+		_, err = x.cache.GetSnapshot("default")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,408 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	router "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/router/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// LBPolicyTag 服务元数据，proxyless gRPC 客户端访问该服务时使用的负载均衡策略
+	LBPolicyTag = "polarismesh.cn/lb-policy"
+	// LBHashHeaderTag 服务元数据，一致性哈希时使用的请求头，未设置时按照 gRPC channel 进行哈希
+	LBHashHeaderTag = "polarismesh.cn/lb-hash-header"
+
+	// LBPolicyRoundRobin 轮询
+	LBPolicyRoundRobin = "roundRobin"
+	// LBPolicyRingHash 一致性哈希
+	LBPolicyRingHash = "ringHash"
+	// LBPolicyLeastRequest 最少请求
+	LBPolicyLeastRequest = "leastRequest"
+
+	// grpcChannelIDKey gRPC 客户端内置的 filter state，取值为 channel 的唯一 ID
+	grpcChannelIDKey = "io.grpc.channel_id"
+)
+
+func (x *XDSServer) pushProxylessInfoToXDSCache(registryInfo map[string][]*ServiceInfo) error {
+	for ns, services := range registryInfo {
+		_ = x.makeProxylessSnapshot(ns, services)
+	}
+	return nil
+}
+
+// makeProxylessSnapshot 为 proxyless gRPC 客户端生成 snapshot，每个命名空间一份
+// gRPC 客户端按照 xds:///{target} 中的 target 订阅 API listener，再通过 RDS、CDS、EDS 获取路由以及实例
+func (x *XDSServer) makeProxylessSnapshot(ns string, services []*ServiceInfo) error {
+	resources := make(map[resource.Type][]types.Resource)
+	resources[resource.ListenerType] = makeProxylessListeners(services)
+	resources[resource.RouteType] = makeProxylessRouteConfigs(services)
+	resources[resource.ClusterType] = makeProxylessClusters(services)
+	resources[resource.EndpointType] = makeProxylessEndpoints(services)
+	snapshot, err := newSnapshot(resources)
+	if err != nil {
+		log.Errorf("[XDS][Proxyless] fail to create snapshot for %s, err is %v", ns, err)
+		return err
+	}
+	// API listener 中通过 RDS 引用的路由不会被 snapshot.Consistent 识别，这里不做一致性校验
+	log.Infof("[XDS][Proxyless] will serve ns: %s ,snapshot: %+v", ns, string(dumpSnapShotJSON(snapshot)))
+	if err := x.cache.SetSnapshot(context.Background(), ns+"/"+string(RunTypeProxyless), snapshot); err != nil {
+		log.Errorf("[XDS][Proxyless] snapshot error %q for %+v", err, snapshot)
+		return err
+	}
+	return nil
+}
+
+// makeProxylessListeners 为服务的每一个可解析域名生成一个 API listener，都指向该服务的路由配置
+func makeProxylessListeners(services []*ServiceInfo) []types.Resource {
+	var listeners []types.Resource
+	for _, serviceInfo := range services {
+		manager := &hcm.HttpConnectionManager{
+			RouteSpecifier: &hcm.HttpConnectionManager_Rds{
+				Rds: &hcm.Rds{
+					ConfigSource: &core.ConfigSource{
+						ResourceApiVersion: resource.DefaultAPIVersion,
+						ConfigSourceSpecifier: &core.ConfigSource_Ads{
+							Ads: &core.AggregatedConfigSource{},
+						},
+					},
+					RouteConfigName: serviceInfo.Name,
+				},
+			},
+			HttpFilters: []*hcm.HttpFilter{{
+				Name: wellknown.Router,
+				// gRPC 要求每个 http filter 都必须带有 typed config
+				ConfigType: &hcm.HttpFilter_TypedConfig{
+					TypedConfig: mustNewAny(&router.Router{}),
+				},
+			}},
+		}
+		apiListener := &listener.ApiListener{ApiListener: mustNewAny(manager)}
+		for _, domain := range generateServiceDomains(serviceInfo) {
+			listeners = append(listeners, &listener.Listener{
+				Name:        domain,
+				ApiListener: apiListener,
+			})
+		}
+	}
+	return listeners
+}
+
+// makeProxylessRouteConfigs 每个服务一份路由配置，按照北极星的路由规则生成带权重的 subset cluster
+func makeProxylessRouteConfigs(services []*ServiceInfo) []types.Resource {
+	var routeConfs []types.Resource
+	for _, serviceInfo := range services {
+		routeConfs = append(routeConfs, &route.RouteConfiguration{
+			Name: serviceInfo.Name,
+			VirtualHosts: []*route.VirtualHost{
+				{
+					Name:    serviceInfo.Name,
+					Domains: generateServiceDomains(serviceInfo),
+					Routes:  makeProxylessRoutes(serviceInfo),
+				},
+			},
+		})
+	}
+	return routeConfs
+}
+
+func makeProxylessRoutes(serviceInfo *ServiceInfo) []*route.Route {
+	var (
+		routes        []*route.Route
+		matchAllRoute *route.Route
+	)
+	// 路由目前只处理 inbounds
+	rules := filterInboundRouterRule(serviceInfo)
+	for _, rule := range rules {
+		var (
+			matchAll     bool
+			destinations []*traffic_manage.DestinationGroup
+		)
+		for _, dest := range rule.GetDestinations() {
+			if !serviceInfo.matchService(dest.GetNamespace(), dest.GetService()) {
+				continue
+			}
+			destinations = append(destinations, dest)
+		}
+		weightedClusters := buildProxylessWeightClusters(serviceInfo, destinations)
+		if weightedClusters == nil {
+			continue
+		}
+
+		routeMatch := &route.RouteMatch{
+			PathSpecifier: &route.RouteMatch_Prefix{Prefix: "/"},
+		}
+		// 使用 sources 生成 routeMatch
+		for _, source := range rule.GetSources() {
+			if len(source.GetArguments()) == 0 {
+				matchAll = true
+				break
+			}
+			for _, arg := range source.GetArguments() {
+				if arg.Key == utils.MatchAll {
+					matchAll = true
+					break
+				}
+			}
+			if matchAll {
+				break
+			}
+			buildSidecarRouteMatch(routeMatch, source)
+		}
+		currentRoute := &route.Route{
+			Match: routeMatch,
+			Action: &route.Route_Route{
+				Route: &route.RouteAction{
+					ClusterSpecifier: &route.RouteAction_WeightedClusters{
+						WeightedClusters: weightedClusters,
+					},
+					HashPolicy: makeProxylessHashPolicy(serviceInfo),
+				},
+			},
+		}
+		if matchAll {
+			matchAllRoute = currentRoute
+		} else {
+			routes = append(routes, currentRoute)
+		}
+	}
+	if matchAllRoute == nil {
+		// 如果没有路由，会进入最后的默认处理
+		matchAllRoute = getDefaultRoute(serviceInfo.Name)
+		matchAllRoute.GetRoute().HashPolicy = makeProxylessHashPolicy(serviceInfo)
+	}
+	return append(routes, matchAllRoute)
+}
+
+// buildProxylessWeightClusters gRPC 不支持 subset 负载均衡，每个路由目标对应一个只包含匹配实例的 cluster
+func buildProxylessWeightClusters(serviceInfo *ServiceInfo,
+	destinations []*traffic_manage.DestinationGroup) *route.WeightedCluster {
+	var (
+		weightedClusters []*route.WeightedCluster_ClusterWeight
+		totalWeight      uint32
+	)
+	for _, destination := range destinations {
+		if destination.GetWeight() == 0 {
+			continue
+		}
+		weightedClusters = append(weightedClusters, &route.WeightedCluster_ClusterWeight{
+			Name:   proxylessClusterName(serviceInfo, destination.GetLabels()),
+			Weight: utils.NewUInt32Value(destination.GetWeight()),
+		})
+		totalWeight += destination.GetWeight()
+	}
+	// gRPC 会拒绝总权重为 0 的路由
+	if totalWeight == 0 {
+		return nil
+	}
+	return &route.WeightedCluster{
+		TotalWeight: &wrappers.UInt32Value{Value: totalWeight},
+		Clusters:    weightedClusters,
+	}
+}
+
+func makeProxylessHashPolicy(serviceInfo *ServiceInfo) []*route.RouteAction_HashPolicy {
+	if serviceInfo.Metadata[LBPolicyTag] != LBPolicyRingHash {
+		return nil
+	}
+	if header := serviceInfo.Metadata[LBHashHeaderTag]; header != "" {
+		return []*route.RouteAction_HashPolicy{{
+			PolicySpecifier: &route.RouteAction_HashPolicy_Header_{
+				Header: &route.RouteAction_HashPolicy_Header{HeaderName: header},
+			},
+		}}
+	}
+	return []*route.RouteAction_HashPolicy{{
+		PolicySpecifier: &route.RouteAction_HashPolicy_FilterState_{
+			FilterState: &route.RouteAction_HashPolicy_FilterState{Key: grpcChannelIDKey},
+		},
+	}}
+}
+
+// proxylessClusterName 路由目标的 cluster 名称，格式为 service|key1=value1,key2=value2，没有标签时为服务名
+func proxylessClusterName(serviceInfo *ServiceInfo, labels map[string]*apimodel.MatchString) string {
+	if len(labels) == 0 {
+		return serviceInfo.Name
+	}
+	items := make([]string, 0, len(labels))
+	for k, v := range labels {
+		items = append(items, k+"="+v.GetValue().GetValue())
+	}
+	sort.Strings(items)
+	return serviceInfo.Name + "|" + strings.Join(items, ",")
+}
+
+// proxylessSubsets 服务的所有 subset cluster，key 为 cluster 名称
+func proxylessSubsets(serviceInfo *ServiceInfo) map[string]map[string]*apimodel.MatchString {
+	subsets := map[string]map[string]*apimodel.MatchString{
+		serviceInfo.Name: nil,
+	}
+	for _, rule := range filterInboundRouterRule(serviceInfo) {
+		for _, dest := range rule.GetDestinations() {
+			if !serviceInfo.matchService(dest.GetNamespace(), dest.GetService()) {
+				continue
+			}
+			subsets[proxylessClusterName(serviceInfo, dest.GetLabels())] = dest.GetLabels()
+		}
+	}
+	return subsets
+}
+
+func sortedSubsetNames(subsets map[string]map[string]*apimodel.MatchString) []string {
+	names := make([]string, 0, len(subsets))
+	for name := range subsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func makeProxylessClusters(services []*ServiceInfo) []types.Resource {
+	var clusters []types.Resource
+	for _, serviceInfo := range services {
+		for _, name := range sortedSubsetNames(proxylessSubsets(serviceInfo)) {
+			clusters = append(clusters, makeProxylessCluster(name, serviceInfo))
+		}
+	}
+	return clusters
+}
+
+func makeProxylessCluster(name string, serviceInfo *ServiceInfo) *cluster.Cluster {
+	c := &cluster.Cluster{
+		Name:                 name,
+		ClusterDiscoveryType: &cluster.Cluster_Type{Type: cluster.Cluster_EDS},
+		EdsClusterConfig: &cluster.Cluster_EdsClusterConfig{
+			ServiceName: name,
+			EdsConfig: &core.ConfigSource{
+				ResourceApiVersion: resource.DefaultAPIVersion,
+				ConfigSourceSpecifier: &core.ConfigSource_Ads{
+					Ads: &core.AggregatedConfigSource{},
+				},
+			},
+		},
+		LbPolicy:         cluster.Cluster_ROUND_ROBIN,
+		OutlierDetection: makeOutlierDetectionV2(serviceInfo),
+	}
+	switch serviceInfo.Metadata[LBPolicyTag] {
+	case LBPolicyRingHash:
+		c.LbPolicy = cluster.Cluster_RING_HASH
+		c.LbConfig = &cluster.Cluster_RingHashLbConfig_{
+			RingHashLbConfig: &cluster.Cluster_RingHashLbConfig{
+				HashFunction: cluster.Cluster_RingHashLbConfig_XX_HASH,
+			},
+		}
+	case LBPolicyLeastRequest:
+		c.LbPolicy = cluster.Cluster_LEAST_REQUEST
+	}
+	return c
+}
+
+func makeProxylessEndpoints(services []*ServiceInfo) []types.Resource {
+	var subsetServices []*ServiceInfo
+	for _, serviceInfo := range services {
+		subsets := proxylessSubsets(serviceInfo)
+		for _, name := range sortedSubsetNames(subsets) {
+			subset := *serviceInfo
+			subset.Name = name
+			subset.Instances = filterSubsetInstances(serviceInfo.Instances, subsets[name])
+			subsetServices = append(subsetServices, &subset)
+		}
+	}
+
+	clusterLoads := makeEndpoints(subsetServices, nil)
+	for _, item := range clusterLoads {
+		cla := item.(*endpoint.ClusterLoadAssignment)
+		// gRPC 会忽略没有权重的 locality
+		for _, localityEndpoints := range cla.GetEndpoints() {
+			var weight uint32
+			for _, ep := range localityEndpoints.GetLbEndpoints() {
+				weight += ep.GetLoadBalancingWeight().GetValue()
+			}
+			if weight == 0 {
+				weight = 1
+			}
+			localityEndpoints.LoadBalancingWeight = utils.NewUInt32Value(weight)
+		}
+	}
+	return clusterLoads
+}
+
+// filterSubsetInstances 过滤出元数据与路由目标标签相匹配的实例
+func filterSubsetInstances(instances []*apiservice.Instance,
+	labels map[string]*apimodel.MatchString) []*apiservice.Instance {
+	if len(labels) == 0 {
+		return instances
+	}
+	// 正则表达式只编译一次，非法的正则表达式没有实例能够匹配
+	regexes := make(map[string]*regexp.Regexp, len(labels))
+	for key, matcher := range labels {
+		if matcher.GetType() != apimodel.MatchString_REGEX {
+			continue
+		}
+		regex, err := regexp.Compile(matcher.GetValue().GetValue())
+		if err != nil {
+			return []*apiservice.Instance{}
+		}
+		regexes[key] = regex
+	}
+	ret := make([]*apiservice.Instance, 0, len(instances))
+	for _, ins := range instances {
+		match := true
+		for key, matcher := range labels {
+			if !matchLabel(ins.GetMetadata()[key], matcher, regexes[key]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			ret = append(ret, ins)
+		}
+	}
+	return ret
+}
+
+func matchLabel(val string, matcher *apimodel.MatchString, regex *regexp.Regexp) bool {
+	expect := matcher.GetValue().GetValue()
+	switch matcher.GetType() {
+	case apimodel.MatchString_NOT_EQUALS:
+		return val != expect
+	case apimodel.MatchString_REGEX:
+		return regex.MatchString(val)
+	default:
+		return val == expect
+	}
+}
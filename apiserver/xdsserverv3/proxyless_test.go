@@ -0,0 +1,171 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpoint "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	listener "github.com/envoyproxy/go-control-plane/envoy/config/listener/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func buildProxylessService() *ServiceInfo {
+	routing := &apitraffic.RuleRoutingConfig{
+		Rules: []*apitraffic.SubRuleRouting{
+			{
+				Sources: []*apitraffic.SourceService{
+					{
+						Service:   "*",
+						Namespace: "*",
+						Arguments: []*apitraffic.SourceMatch{
+							{
+								Type: apitraffic.SourceMatch_HEADER,
+								Key:  "env",
+								Value: &apimodel.MatchString{
+									Type:  apimodel.MatchString_EXACT,
+									Value: &wrappers.StringValue{Value: "gray"},
+								},
+							},
+						},
+					},
+				},
+				Destinations: []*apitraffic.DestinationGroup{
+					{
+						Service:   "grpc-svc",
+						Namespace: "default",
+						Weight:    100,
+						Labels: map[string]*apimodel.MatchString{
+							"version": {
+								Type:  apimodel.MatchString_EXACT,
+								Value: &wrappers.StringValue{Value: "v2"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	return &ServiceInfo{
+		Name:      "grpc-svc",
+		Namespace: "default",
+		Ports:     "8080",
+		Metadata:  map[string]string{LBPolicyTag: LBPolicyRingHash},
+		Routing: &apitraffic.Routing{
+			Rules: []*apitraffic.RouteRule{
+				{
+					RoutingPolicy: apitraffic.RoutingPolicy_RulePolicy,
+					RoutingConfig: mustNewAny(proto.MessageV2(routing)),
+				},
+			},
+		},
+		Instances: []*apiservice.Instance{
+			{
+				Host:     utils.NewStringValue("127.0.0.1"),
+				Port:     utils.NewUInt32Value(8080),
+				Weight:   utils.NewUInt32Value(100),
+				Healthy:  utils.NewBoolValue(true),
+				Metadata: map[string]string{"version": "v1"},
+			},
+			{
+				Host:     utils.NewStringValue("127.0.0.2"),
+				Port:     utils.NewUInt32Value(8080),
+				Weight:   utils.NewUInt32Value(50),
+				Healthy:  utils.NewBoolValue(true),
+				Metadata: map[string]string{"version": "v2"},
+			},
+		},
+	}
+}
+
+func TestMakeProxylessResources(t *testing.T) {
+	services := []*ServiceInfo{buildProxylessService()}
+
+	listeners := makeProxylessListeners(services)
+	names := make([]string, 0, len(listeners))
+	for _, item := range listeners {
+		l := item.(*listener.Listener)
+		assert.NotNil(t, l.GetApiListener())
+		names = append(names, l.GetName())
+	}
+	assert.Contains(t, names, "grpc-svc")
+	assert.Contains(t, names, "grpc-svc.default:8080")
+
+	routeConfs := makeProxylessRouteConfigs(services)
+	assert.Len(t, routeConfs, 1)
+	routes := routeConfs[0].(*route.RouteConfiguration).GetVirtualHosts()[0].GetRoutes()
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "env", routes[0].GetMatch().GetHeaders()[0].GetName())
+	assert.Equal(t, "grpc-svc|version=v2", routes[0].GetRoute().GetWeightedClusters().GetClusters()[0].GetName())
+	assert.Equal(t, grpcChannelIDKey, routes[0].GetRoute().GetHashPolicy()[0].GetFilterState().GetKey())
+	// 默认路由指向整个服务
+	assert.Equal(t, "grpc-svc", routes[1].GetRoute().GetCluster())
+
+	clusters := makeProxylessClusters(services)
+	assert.Len(t, clusters, 2)
+	for _, item := range clusters {
+		c := item.(*cluster.Cluster)
+		assert.Equal(t, cluster.Cluster_RING_HASH, c.GetLbPolicy())
+		assert.Equal(t, c.GetName(), c.GetEdsClusterConfig().GetServiceName())
+	}
+
+	clusterLoads := makeProxylessEndpoints(services)
+	assert.Len(t, clusterLoads, 2)
+	all := clusterLoads[0].(*endpoint.ClusterLoadAssignment)
+	assert.Equal(t, "grpc-svc", all.GetClusterName())
+	assert.Len(t, all.GetEndpoints()[0].GetLbEndpoints(), 2)
+	assert.Equal(t, uint32(150), all.GetEndpoints()[0].GetLoadBalancingWeight().GetValue())
+	subset := clusterLoads[1].(*endpoint.ClusterLoadAssignment)
+	assert.Equal(t, "grpc-svc|version=v2", subset.GetClusterName())
+	assert.Len(t, subset.GetEndpoints()[0].GetLbEndpoints(), 1)
+	assert.Equal(t, uint32(50), subset.GetEndpoints()[0].GetLoadBalancingWeight().GetValue())
+}
+
+func TestProxylessNodeHashID(t *testing.T) {
+	node := &core.Node{Id: "proxyless~default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1"}
+	assert.Equal(t, "default/proxyless", PolarisNodeHash{}.ID(node))
+	assert.Equal(t, RunTypeProxyless, parseNodeProxy(node).RunType)
+}
+
+func TestFilterSubsetInstancesByRegex(t *testing.T) {
+	instances := []*apiservice.Instance{
+		{Host: utils.NewStringValue("127.0.0.1"), Metadata: map[string]string{"version": "v1"}},
+		{Host: utils.NewStringValue("127.0.0.2"), Metadata: map[string]string{"version": "v2"}},
+		{Host: utils.NewStringValue("127.0.0.3"), Metadata: map[string]string{"version": "canary"}},
+	}
+	ret := filterSubsetInstances(instances, map[string]*apimodel.MatchString{
+		"version": {Type: apimodel.MatchString_REGEX, Value: utils.NewStringValue("^v[0-9]+$")},
+	})
+	assert.Len(t, ret, 2)
+
+	// 非法的正则表达式没有实例能够匹配
+	ret = filterSubsetInstances(instances, map[string]*apimodel.MatchString{
+		"version": {Type: apimodel.MatchString_REGEX, Value: utils.NewStringValue("(")},
+	})
+	assert.Empty(t, ret)
+}
@@ -0,0 +1,198 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tlstrans "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/secure"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// workloadCertSecretName 工作负载证书的 secret 名称，与 TlsCertificateSdsSecretConfigs 保持一致
+	workloadCertSecretName = "default"
+	// rootCASecretName 信任包的 secret 名称，与 ValidationContextSdsSecretConfig 保持一致
+	rootCASecretName = "ROOTCA"
+
+	certRotateInterval = time.Minute
+)
+
+var (
+	errSDSAuthDisabled = errors.New("client auth is not open, workload cert can not be issued")
+	errSDSNoToken      = errors.New("stream does not carry access token")
+	errSDSNoService    = errors.New("service of workload not found")
+)
+
+// workloadIdentity 获取节点所属的服务，网关使用网关服务，sidecar 使用节点 metadata 中声明的服务
+func workloadIdentity(node *XDSClient) (string, string) {
+	if node.IsGateway() {
+		return node.Metadata[GatewayNamespaceName], node.Metadata[GatewayServiceName]
+	}
+	namespace := node.Metadata[SidecarNamespaceName]
+	if namespace == "" {
+		namespace = node.Namespace
+	}
+	return namespace, node.Metadata[SidecarServiceName]
+}
+
+// pushWorkloadSecrets 为节点签发工作负载证书，并通过 SDS 下发证书以及信任包
+func (x *XDSServer) pushWorkloadSecrets(node *XDSClient) error {
+	if x.certMgr == nil || node == nil {
+		return nil
+	}
+	namespace, service := workloadIdentity(node)
+	if namespace == "" || service == "" {
+		log.Warnf("[XDS][SDS] node %s does not declare its service, skip issuing workload cert", node.Node.Id)
+		return nil
+	}
+	if err := x.authorizeWorkload(node, namespace, service); err != nil {
+		log.Warnf("[XDS][SDS] node %s is not allowed to get workload cert of %s/%s: %v",
+			node.Node.Id, namespace, service, err)
+		return err
+	}
+	cert, bundle, err := x.certMgr.issue(node.Node.Id, secure.WorkloadSpiffeID(TrustDomain, namespace, service))
+	if err != nil {
+		log.Errorf("[XDS][SDS] issue workload cert for node %s error %v", node.Node.Id, err)
+		return err
+	}
+
+	resources := map[resource.Type][]types.Resource{
+		resource.SecretType: makeSecrets(cert, bundle),
+	}
+	// 证书序列号在每次签发以及 CA 变更之后都会变化，可以直接作为版本号
+	snapshot, err := cachev3.NewSnapshot(cert.SerialNumber, resources)
+	if err != nil {
+		return err
+	}
+	// secret 中包含私钥，不能打印 snapshot 的内容
+	if err := x.secretCache.SetSnapshot(context.Background(), node.Node.Id, snapshot); err != nil {
+		log.Errorf("[XDS][SDS] set secret snapshot for node %s error %v", node.Node.Id, err)
+		return err
+	}
+	return nil
+}
+
+// authorizeWorkload 工作负载证书代表服务身份，只签发给 stream 携带的 token 对服务有写权限的节点
+func (x *XDSServer) authorizeWorkload(node *XDSClient, namespace, service string) error {
+	if x.authChecker == nil || !x.authChecker.IsOpenClientAuth() {
+		return errSDSAuthDisabled
+	}
+	if node.Token == "" {
+		return errSDSNoToken
+	}
+	serviceGetter := x.ServiceGetter
+	if serviceGetter == nil {
+		serviceGetter = x.namingServer.Cache().Service().GetServiceByName
+	}
+	svc := serviceGetter(service, namespace)
+	if svc == nil {
+		return errSDSNoService
+	}
+	ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, node.Token)
+	authCtx := model.NewAcquireContext(
+		model.WithRequestContext(ctx),
+		model.WithModule(model.DiscoverModule),
+		model.WithOperation(model.Modify),
+		model.WithMethod("IssueWorkloadCert"),
+		model.WithAccessResources(map[apisecurity.ResourceType][]model.ResourceEntry{
+			apisecurity.ResourceType_Services: {
+				{
+					ID:        svc.ID,
+					Owner:     svc.Owner,
+					Namespace: svc.Namespace,
+				},
+			},
+		}),
+	)
+	_, err := x.authChecker.CheckClientPermission(authCtx)
+	return err
+}
+
+func makeSecrets(cert *secure.WorkloadCert, bundle string) []types.Resource {
+	return []types.Resource{
+		&tlstrans.Secret{
+			Name: workloadCertSecretName,
+			Type: &tlstrans.Secret_TlsCertificate{
+				TlsCertificate: &tlstrans.TlsCertificate{
+					CertificateChain: &core.DataSource{
+						Specifier: &core.DataSource_InlineBytes{InlineBytes: []byte(cert.CertChainPEM)},
+					},
+					PrivateKey: &core.DataSource{
+						Specifier: &core.DataSource_InlineBytes{InlineBytes: []byte(cert.KeyPEM)},
+					},
+				},
+			},
+		},
+		&tlstrans.Secret{
+			Name: rootCASecretName,
+			Type: &tlstrans.Secret_ValidationContext{
+				ValidationContext: &tlstrans.CertificateValidationContext{
+					TrustedCa: &core.DataSource{
+						Specifier: &core.DataSource_InlineBytes{InlineBytes: []byte(bundle)},
+					},
+				},
+			},
+		},
+	}
+}
+
+// startCertRotateTask 定期同步存储中的 CA，并为已经连接的节点轮换即将过期的证书
+func (x *XDSServer) startCertRotateTask(ctx context.Context) {
+	if x.certMgr == nil {
+		return
+	}
+	rotateFunc := func() {
+		if _, err := x.certMgr.reload(); err != nil {
+			log.Errorf("[XDS][CA] reload ca error %v", err)
+		}
+		for _, id := range x.certMgr.nodeIDs() {
+			node := x.xdsNodesMgr.GetNode(id)
+			if node == nil {
+				// 节点已经断开连接
+				x.certMgr.remove(id)
+				x.secretCache.ClearSnapshot(id)
+				continue
+			}
+			_ = x.pushWorkloadSecrets(node)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(certRotateInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				rotateFunc()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
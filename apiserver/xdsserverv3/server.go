@@ -21,8 +21,9 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
-	"time"
+	"sync"
 
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
 	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
@@ -41,10 +42,10 @@ import (
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	"github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
-	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 
 	"github.com/polarismesh/polaris/apiserver"
+	"github.com/polarismesh/polaris/auth"
 	"github.com/polarismesh/polaris/cache"
 	api "github.com/polarismesh/polaris/common/api/v1"
 	connlimit "github.com/polarismesh/polaris/common/conn/limit"
@@ -53,6 +54,7 @@ import (
 	"github.com/polarismesh/polaris/common/utils"
 	"github.com/polarismesh/polaris/namespace"
 	"github.com/polarismesh/polaris/service"
+	"github.com/polarismesh/polaris/store"
 )
 
 // XDSServer is the xDS server
@@ -64,29 +66,71 @@ type XDSServer struct {
 	exitCh          chan struct{}
 	namingServer    service.DiscoverServer
 	cache           cachev3.SnapshotCache
-	versionNum      *atomic.Uint64
 	server          *grpc.Server
 	connLimitConfig *connlimit.Config
-
-	xdsNodesMgr                *XDSNodeManager
+	// certMgr 内置 CA，未开启时为 nil
+	certMgr     *certManager
+	secretCache cachev3.SnapshotCache
+	// authChecker 签发工作负载证书前校验节点携带的 token
+	authChecker auth.AuthChecker
+
+	xdsNodesMgr *XDSNodeManager
+	// registryLock 保护 registryInfo，Restart 重新初始化时会与同步任务并发访问
+	registryLock               sync.Mutex
 	registryInfo               map[string][]*ServiceInfo
+	changes                    *changeSet
+	syncOnce                   sync.Once
 	CircuitBreakerConfigGetter CircuitBreakerConfigGetter
 	RatelimitConfigGetter      RatelimitConfigGetter
+	ServiceGetter              ServiceGetter
 }
 
 // Initialize 初始化
 func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{},
 	apiConf map[string]apiserver.APIConfig) error {
-	x.registryInfo = make(map[string][]*ServiceInfo)
 	x.listenPort = uint32(option["listenPort"].(int))
 	x.listenIP = option["listenIP"].(string)
 	x.xdsNodesMgr = newXDSNodeManager()
-	x.cache = NewSnapshotCache(cachev3.NewSnapshotCache(false, PolarisNodeHash{},
-		commonlog.GetScopeOrDefaultByName(commonlog.XDSLoggerName)), x)
 
-	x.versionNum = atomic.NewUint64(0)
 	var err error
 
+	caRaw, _ := option["ca"].(map[interface{}]interface{})
+	caConfig, err := ParseCAConfig(caRaw)
+	if err != nil {
+		log.Errorf("parse ca config %v", err)
+		return err
+	}
+	x.certMgr = nil
+	x.secretCache = nil
+	if caConfig.Enable {
+		// xDS 连接本身没有身份认证，没有开启客户端鉴权时无法确认节点的服务身份，不签发证书
+		strategyMgn, err := auth.GetStrategyServer()
+		if err != nil {
+			return err
+		}
+		x.authChecker = strategyMgn.GetAuthChecker()
+		if !x.authChecker.IsOpenClientAuth() {
+			log.Errorf("built-in ca requires client auth, SDS is disabled")
+			caConfig.Enable = false
+		}
+	}
+	if caConfig.Enable {
+		storage, err := store.GetStore()
+		if err != nil {
+			return err
+		}
+		if x.certMgr, err = newCertManager(caConfig, storage); err != nil {
+			log.Errorf("init built-in ca %v", err)
+			return err
+		}
+		x.secretCache = cachev3.NewSnapshotCache(false, cachev3.IDHash{},
+			commonlog.GetScopeOrDefaultByName(commonlog.XDSLoggerName))
+	}
+	snapshotCache := newSnapshotCache(cachev3.NewSnapshotCache(false, PolarisNodeHash{nodes: x.xdsNodesMgr},
+		commonlog.GetScopeOrDefaultByName(commonlog.XDSLoggerName)), x)
+	snapshotCache.secretCache = x.secretCache
+	x.cache = snapshotCache
+
 	x.namingServer, err = service.GetServer()
 	if err != nil {
 		log.Errorf("%v", err)
@@ -101,25 +145,12 @@ func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{
 		x.connLimitConfig = connConfig
 	}
 
-	if err = x.initRegistryInfo(); err != nil {
-		log.Errorf("initRegistryInfo %v", err)
-		return err
-	}
-
-	if err = x.getRegistryInfoWithCache(ctx, x.registryInfo); err != nil {
-		log.Errorf("getRegistryInfoWithCache %v", err)
-		return err
-	}
-	if err = x.pushSidecarInfoToXDSCache(x.registryInfo); err != nil {
-		log.Errorf("pushSidecarInfoToXDSCache %v", err)
-		return err
-	}
-	if err = x.pushGatewayInfoToXDSCache(x.registryInfo); err != nil {
-		log.Errorf("pushGatewayInfoToXDSCache %v", err)
+	if err = x.loadRegistryInfo(ctx); err != nil {
 		return err
 	}
 
 	_ = x.startSynTask(ctx)
+	x.startCertRotateTask(ctx)
 	return nil
 }
 
@@ -127,7 +158,11 @@ func (x *XDSServer) Initialize(ctx context.Context, option map[string]interface{
 func (x *XDSServer) Run(errCh chan error) {
 	// 启动 grpc server
 	ctx := context.Background()
-	cb := &Callbacks{log: commonlog.GetScopeOrDefaultByName(commonlog.XDSLoggerName), nodeMgr: x.xdsNodesMgr}
+	cb := &Callbacks{
+		log:           commonlog.GetScopeOrDefaultByName(commonlog.XDSLoggerName),
+		nodeMgr:       x.xdsNodesMgr,
+		onNodeRemoved: x.clearNodeSnapshot,
+	}
 	srv := serverv3.NewServer(ctx, x.cache, cb)
 	var grpcOptions []grpc.ServerOption
 	grpcOptions = append(grpcOptions, grpc.MaxConcurrentStreams(1000))
@@ -211,6 +246,8 @@ func (x *XDSServer) Restart(option map[string]interface{}, apiConf map[string]ap
 
 type RatelimitConfigGetter func(serviceKey model.ServiceKey) ([]*model.RateLimit, string)
 
+type ServiceGetter func(name string, namespace string) *model.Service
+
 // GetProtocol 服务注册到北极星中的协议
 func (x *XDSServer) GetProtocol() string {
 	return "xdsv3"
@@ -221,6 +258,35 @@ func (x *XDSServer) GetPort() uint32 {
 	return x.listenPort
 }
 
+// loadRegistryInfo 全量加载服务信息并推送到 xds cache
+func (x *XDSServer) loadRegistryInfo(ctx context.Context) error {
+	x.registryLock.Lock()
+	defer x.registryLock.Unlock()
+
+	x.registryInfo = make(map[string][]*ServiceInfo)
+	if err := x.initRegistryInfo(); err != nil {
+		log.Errorf("initRegistryInfo %v", err)
+		return err
+	}
+	if err := x.getRegistryInfoWithCache(ctx, x.registryInfo); err != nil {
+		log.Errorf("getRegistryInfoWithCache %v", err)
+		return err
+	}
+	if err := x.pushSidecarInfoToXDSCache(x.registryInfo); err != nil {
+		log.Errorf("pushSidecarInfoToXDSCache %v", err)
+		return err
+	}
+	if err := x.pushGatewayInfoToXDSCache(x.registryInfo); err != nil {
+		log.Errorf("pushGatewayInfoToXDSCache %v", err)
+		return err
+	}
+	if err := x.pushProxylessInfoToXDSCache(x.registryInfo); err != nil {
+		log.Errorf("pushProxylessInfoToXDSCache %v", err)
+		return err
+	}
+	return nil
+}
+
 func (x *XDSServer) initRegistryInfo() error {
 	namespaceServer, err := namespace.GetOriginServer()
 	if err != nil {
@@ -253,6 +319,7 @@ func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo m
 			Namespace: value.Namespace,
 			Instances: []*apiservice.Instance{},
 			Ports:     value.Ports,
+			Metadata:  value.Meta,
 		}
 		registryInfo[value.Namespace] = append(registryInfo[value.Namespace], info)
 		return true, nil
@@ -264,121 +331,172 @@ func (x *XDSServer) getRegistryInfoWithCache(ctx context.Context, registryInfo m
 	}
 
 	// 遍历每一个服务，获取路由、熔断策略和全量的服务实例信息
-	for _, v := range registryInfo {
+	for ns, v := range registryInfo {
 		for _, svc := range v {
-			s := &apiservice.Service{
-				Name:      utils.NewStringValue(svc.Name),
-				Namespace: utils.NewStringValue(svc.Namespace),
-				Revision:  utils.NewStringValue("-1"),
+			if err := x.fillServiceInfo(ctx, svc); err != nil {
+				return err
 			}
+		}
+		sortServiceInfos(registryInfo[ns])
+	}
 
-			// 获取routing配置
-			routeResp := x.namingServer.GetRoutingConfigWithCache(ctx, s)
-			if routeResp.GetCode().Value != api.ExecuteSuccess {
-				log.Errorf("error sync routing for %s, info : %s", svc.Name, routeResp.Info.GetValue())
-				return fmt.Errorf("[XDSV3] error sync routing for %s", svc.Name)
-			}
+	return nil
+}
 
-			if routeResp.Routing != nil {
-				svc.SvcRoutingRevision = routeResp.Routing.Revision.Value
-				svc.Routing = routeResp.Routing
-			}
+// fillServiceInfo 从缓存中获取服务的路由、实例以及限流配置
+func (x *XDSServer) fillServiceInfo(ctx context.Context, svc *ServiceInfo) error {
+	s := &apiservice.Service{
+		Name:      utils.NewStringValue(svc.Name),
+		Namespace: utils.NewStringValue(svc.Namespace),
+		Revision:  utils.NewStringValue("-1"),
+	}
 
-			// 获取instance配置
-			resp := x.namingServer.ServiceInstancesCache(ctx, s)
-			if resp.GetCode().Value != api.ExecuteSuccess {
-				log.Errorf("[XDSV3] error sync instances for %s, info : %s", svc.Name, resp.Info.GetValue())
-				return fmt.Errorf("error sync instances for %s", svc.Name)
-			}
+	// 获取routing配置
+	routeResp := x.namingServer.GetRoutingConfigWithCache(ctx, s)
+	if routeResp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("error sync routing for %s, info : %s", svc.Name, routeResp.Info.GetValue())
+		return fmt.Errorf("[XDSV3] error sync routing for %s", svc.Name)
+	}
 
-			svc.AliasFor = x.namingServer.Cache().Service().GetAliasFor(svc.Name, svc.Namespace)
-			svc.SvcInsRevision = resp.Service.Revision.Value
-			svc.Instances = resp.Instances
-			ports := x.namingServer.Cache().Instance().GetServicePorts(svc.ID)
-			if svc.AliasFor != nil {
-				ports = x.namingServer.Cache().Instance().GetServicePorts(svc.AliasFor.ID)
-			}
-			if len(ports) > 0 {
-				svc.Ports = strings.Join(ports, ",")
-			}
+	if routeResp.Routing != nil {
+		svc.SvcRoutingRevision = routeResp.Routing.Revision.Value
+		svc.Routing = routeResp.Routing
+	}
 
-			// 获取ratelimit配置
-			ratelimitResp := x.namingServer.GetRateLimitWithCache(ctx, s)
-			if ratelimitResp.GetCode().Value != api.ExecuteSuccess {
-				log.Errorf("[XDSV3] error sync ratelimit for %s, info : %s", svc.Name, ratelimitResp.Info.GetValue())
-				return fmt.Errorf("error sync ratelimit for %s", svc.Name)
-			}
-			if ratelimitResp.RateLimit != nil {
-				svc.SvcRateLimitRevision = ratelimitResp.RateLimit.Revision.Value
-				svc.RateLimit = ratelimitResp.RateLimit
-			}
-		}
+	// 获取instance配置
+	resp := x.namingServer.ServiceInstancesCache(ctx, s)
+	if resp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("[XDSV3] error sync instances for %s, info : %s", svc.Name, resp.Info.GetValue())
+		return fmt.Errorf("error sync instances for %s", svc.Name)
 	}
 
+	svc.AliasFor = x.namingServer.Cache().Service().GetAliasFor(svc.Name, svc.Namespace)
+	svc.SvcInsRevision = resp.Service.Revision.Value
+	svc.Instances = resp.Instances
+	ports := x.namingServer.Cache().Instance().GetServicePorts(svc.ID)
+	if svc.AliasFor != nil {
+		ports = x.namingServer.Cache().Instance().GetServicePorts(svc.AliasFor.ID)
+	}
+	if len(ports) > 0 {
+		svc.Ports = strings.Join(ports, ",")
+	}
+
+	// 获取ratelimit配置
+	ratelimitResp := x.namingServer.GetRateLimitWithCache(ctx, s)
+	if ratelimitResp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("[XDSV3] error sync ratelimit for %s, info : %s", svc.Name, ratelimitResp.Info.GetValue())
+		return fmt.Errorf("error sync ratelimit for %s", svc.Name)
+	}
+	if ratelimitResp.RateLimit != nil {
+		svc.SvcRateLimitRevision = ratelimitResp.RateLimit.Revision.Value
+		svc.RateLimit = ratelimitResp.RateLimit
+	}
+
+	// 获取circuitbreaker配置
+	cbResp := x.namingServer.GetCircuitBreakerWithCache(ctx, s)
+	if cbResp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("[XDSV3] error sync circuitbreaker for %s, info : %s", svc.Name, cbResp.Info.GetValue())
+		return fmt.Errorf("error sync circuitbreaker for %s", svc.Name)
+	}
+	if cbResp.CircuitBreaker != nil {
+		svc.SvcCircuitBreakerRevision = cbResp.CircuitBreaker.GetRevision().GetValue()
+		svc.CircuitBreaker = cbResp.CircuitBreaker
+	}
+
+	// 获取faultdetect配置
+	fdResp := x.namingServer.GetFaultDetectWithCache(ctx, s)
+	if fdResp.GetCode().Value != api.ExecuteSuccess {
+		log.Errorf("[XDSV3] error sync faultdetect for %s, info : %s", svc.Name, fdResp.Info.GetValue())
+		return fmt.Errorf("error sync faultdetect for %s", svc.Name)
+	}
+	if fdResp.FaultDetector != nil {
+		svc.SvcFaultDetectRevision = fdResp.FaultDetector.GetRevision()
+		svc.FaultDetect = fdResp.FaultDetector
+	}
+
+	// 获取流量策略配置
+	svc.TrafficPolicies, svc.SvcTrafficPolicyRevision = x.namingServer.Cache().TrafficPolicy().
+		GetTrafficPolicies(svc.Name, svc.Namespace)
+	// 访问控制策略在生成 sidecar 的 snapshot 时按照节点所属的服务获取，这里只记录版本用于变更对比
+	_, svc.SvcAccessPolicyRevision = x.namingServer.Cache().AccessPolicy().GetAccessPolicies(svc.Name, svc.Namespace)
 	return nil
 }
 
-func (x *XDSServer) startSynTask(ctx context.Context) error {
-	// 读取 polaris 缓存数据
-	synXdsConfFunc := func() {
-		registryInfo := make(map[string][]*ServiceInfo)
+// sortServiceInfos 按照服务名排序，保证生成的 xDS 资源顺序稳定，避免内容不变时版本号发生变化
+func sortServiceInfos(infos []*ServiceInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+}
 
-		err := x.getRegistryInfoWithCache(ctx, registryInfo)
-		if err != nil {
-			log.Errorf("get registry info from cache error %v", err)
-			return
+// startSynTask 监听服务、实例、路由、限流以及熔断探测缓存的变化，只重新生成受影响的命名空间的 snapshot
+func (x *XDSServer) startSynTask(ctx context.Context) error {
+	x.syncOnce.Do(func() {
+		x.changes = newChangeSet()
+		caches := x.namingServer.Cache()
+		caches.AddRevisionListener(func(serviceID string, _ string) {
+			x.changes.addService(serviceID)
+		})
+		listener := &cacheChangeListener{changes: x.changes}
+		for _, name := range []cache.CacheName{cache.CacheNameService, cache.CacheNameRoutingConfig,
+			cache.CacheNameRateLimit, cache.CacheNameCircuitBreaker, cache.CacheNameFaultDetectRule,
+			cache.CacheNameTrafficPolicy, cache.CacheNameAccessPolicy} {
+			caches.AddListener(name, []cache.Listener{listener})
 		}
+		go x.runSyncTask(ctx)
+	})
+	return nil
+}
 
-		needPush := make(map[string][]*ServiceInfo)
+// syncAll 全量对比缓存中的服务信息，推送发生变化的命名空间
+func (x *XDSServer) syncAll(ctx context.Context) {
+	x.registryLock.Lock()
+	defer x.registryLock.Unlock()
 
-		// 处理删除 ns 中最后一个 service
-		for ns, infos := range x.registryInfo {
-			_, ok := registryInfo[ns]
-			if !ok && len(infos) > 0 {
-				// 这一次轮询时，该命名空间下的最后一个服务已经被删除了，此时，当前的命名空间需要处理
-				needPush[ns] = []*ServiceInfo{}
-				x.registryInfo[ns] = []*ServiceInfo{}
-			}
-		}
+	registryInfo := make(map[string][]*ServiceInfo)
 
-		// 与本地缓存对比，是否发生了变化，对发生变化的命名空间，推送配置
-		for ns, infos := range registryInfo {
-			cacheServiceInfos, ok := x.registryInfo[ns]
-			if !ok {
-				// 新命名空间，需要处理
-				needPush[ns] = infos
-				x.registryInfo[ns] = infos
-				continue
-			}
+	err := x.getRegistryInfoWithCache(ctx, registryInfo)
+	if err != nil {
+		log.Errorf("get registry info from cache error %v", err)
+		x.retrySync(x.changes.addAll)
+		return
+	}
 
-			// todo 不考虑命名空间删除的情况
-			// 判断当前这个空间，是否需要更新配置
-			if x.checkUpdate(infos, cacheServiceInfos) {
-				needPush[ns] = infos
-				x.registryInfo[ns] = infos
-			}
-		}
+	needPush := make(map[string][]*ServiceInfo)
 
-		if len(needPush) > 0 {
-			_ = x.pushSidecarInfoToXDSCache(needPush)
-			_ = x.pushGatewayInfoToXDSCache(needPush)
+	// 处理删除 ns 中最后一个 service
+	for ns, infos := range x.registryInfo {
+		_, ok := registryInfo[ns]
+		if !ok && len(infos) > 0 {
+			// 这一次轮询时，该命名空间下的最后一个服务已经被删除了，此时，当前的命名空间需要处理
+			needPush[ns] = []*ServiceInfo{}
+			x.registryInfo[ns] = []*ServiceInfo{}
 		}
 	}
 
-	go func() {
-		ticker := time.NewTicker(5 * cache.UpdateCacheInterval)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ticker.C:
-				synXdsConfFunc()
-			case <-ctx.Done():
-				return
-			}
+	// 与本地缓存对比，是否发生了变化，对发生变化的命名空间，推送配置
+	for ns, infos := range registryInfo {
+		cacheServiceInfos, ok := x.registryInfo[ns]
+		if !ok {
+			// 新命名空间，需要处理
+			needPush[ns] = infos
+			x.registryInfo[ns] = infos
+			continue
 		}
-	}()
 
-	return nil
+		// todo 不考虑命名空间删除的情况
+		// 判断当前这个空间，是否需要更新配置
+		if x.checkUpdate(infos, cacheServiceInfos) {
+			needPush[ns] = infos
+			x.registryInfo[ns] = infos
+		}
+	}
+
+	if len(needPush) > 0 {
+		_ = x.pushSidecarInfoToXDSCache(needPush)
+		_ = x.pushGatewayInfoToXDSCache(needPush)
+		_ = x.pushProxylessInfoToXDSCache(needPush)
+	}
 }
 
 func (x *XDSServer) checkUpdate(curServiceInfo, cacheServiceInfo []*ServiceInfo) bool {
@@ -386,25 +504,13 @@ func (x *XDSServer) checkUpdate(curServiceInfo, cacheServiceInfo []*ServiceInfo)
 		return true
 	}
 
+	cached := make(map[string]*ServiceInfo, len(cacheServiceInfo))
+	for _, info := range cacheServiceInfo {
+		cached[info.Name] = info
+	}
 	for _, info := range curServiceInfo {
-		find := false
-		for _, serviceInfo := range cacheServiceInfo {
-			if info.Name == serviceInfo.Name {
-				// 通过 revision 判断
-				if info.SvcInsRevision != serviceInfo.SvcInsRevision {
-					return true
-				}
-				if info.SvcRoutingRevision != serviceInfo.SvcRoutingRevision {
-					return true
-				}
-				if info.SvcRateLimitRevision != serviceInfo.SvcRateLimitRevision {
-					return true
-				}
-
-				find = true
-			}
-		}
-		if !find {
+		serviceInfo, ok := cached[info.Name]
+		if !ok || serviceInfoChanged(serviceInfo, info) {
 			return true
 		}
 	}
@@ -412,6 +518,46 @@ func (x *XDSServer) checkUpdate(curServiceInfo, cacheServiceInfo []*ServiceInfo)
 	return false
 }
 
+// serviceInfoChanged 通过 revision 判断服务信息是否发生了变化
+func serviceInfoChanged(pre, cur *ServiceInfo) bool {
+	if pre.SvcInsRevision != cur.SvcInsRevision {
+		return true
+	}
+	if pre.SvcRoutingRevision != cur.SvcRoutingRevision {
+		return true
+	}
+	if pre.SvcRateLimitRevision != cur.SvcRateLimitRevision {
+		return true
+	}
+	if pre.SvcCircuitBreakerRevision != cur.SvcCircuitBreakerRevision {
+		return true
+	}
+	if pre.SvcFaultDetectRevision != cur.SvcFaultDetectRevision {
+		return true
+	}
+	if pre.SvcTrafficPolicyRevision != cur.SvcTrafficPolicyRevision {
+		return true
+	}
+	if pre.SvcAccessPolicyRevision != cur.SvcAccessPolicyRevision {
+		return true
+	}
+	if pre.Ports != cur.Ports {
+		return true
+	}
+	if pre.Metadata[LBPolicyTag] != cur.Metadata[LBPolicyTag] ||
+		pre.Metadata[LBHashHeaderTag] != cur.Metadata[LBHashHeaderTag] {
+		return true
+	}
+	return aliasForID(pre) != aliasForID(cur)
+}
+
+func aliasForID(info *ServiceInfo) string {
+	if info.AliasFor == nil {
+		return ""
+	}
+	return info.AliasFor.ID
+}
+
 func buildCommonRouteMatch(routeMatch *route.RouteMatch, source *traffic_manage.SourceService) {
 	for i := range source.GetArguments() {
 		argument := source.GetArguments()[i]
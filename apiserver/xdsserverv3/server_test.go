@@ -40,7 +40,6 @@ import (
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 	"github.com/stretchr/testify/assert"
-	"go.uber.org/atomic"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/structpb"
 
@@ -366,6 +365,19 @@ func TestNodeHashID(t *testing.T) {
 			},
 			TargetID: "gateway~default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1",
 		},
+		// sidecar declares dependencies
+		{
+			Node: &core.Node{
+				Id: "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1",
+				Metadata: &_struct.Struct{
+					Fields: map[string]*structpb.Value{
+						TLSModeTag:          structpb.NewStringValue(TLSModeStrict),
+						SidecarDependencies: structpb.NewStringValue("svc-a,svc-b"),
+					},
+				},
+			},
+			TargetID: "default/9b9f5630-81a1-47cd-a558-036eb616dc71~172.17.1.1",
+		},
 	}
 	for i, item := range testTable {
 		id := PolarisNodeHash{}.ID(item.Node)
@@ -423,7 +435,6 @@ func TestSnapshot(t *testing.T) {
 		xdsNodesMgr:           newXDSNodeManager(),
 		namingServer:          discoverSuit.DiscoverServer(),
 		RatelimitConfigGetter: func(_ model.ServiceKey) ([]*model.RateLimit, string) { return nil, "" },
-		versionNum:            atomic.NewUint64(1),
 		cache:                 cache.NewSnapshotCache(true, cache.IDHash{}, nil),
 	}
 
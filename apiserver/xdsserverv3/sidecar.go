@@ -20,9 +20,9 @@ package xdsserverv3
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
 	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
@@ -33,7 +33,6 @@ import (
 	v32 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
 	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
 	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
-	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
 	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
 	"github.com/golang/protobuf/ptypes"
 	_struct "github.com/golang/protobuf/ptypes/struct"
@@ -50,22 +49,122 @@ import (
 )
 
 func (x *XDSServer) pushSidecarInfoToXDSCache(registryInfo map[string][]*ServiceInfo) error {
-	versionLocal := time.Now().Format(time.RFC3339) + "/" + strconv.FormatUint(x.versionNum.Inc(), 10)
 	for ns, services := range registryInfo {
-		_ = x.makeSnapshot(ns, versionLocal, services)
-		_ = x.makePermissiveSnapshot(ns, versionLocal, services)
-		_ = x.makeStrictSnapshot(ns, versionLocal, services)
+		_ = x.makeSnapshot(ns, services)
+		_ = x.makePermissiveSnapshot(ns, services)
+		_ = x.makeStrictSnapshot(ns, services)
+	}
+	// 声明了依赖服务、带有地域信息或者需要入口访问控制的 sidecar 单独生成 snapshot，相同 key 的节点只需要生成一次
+	nodes := x.xdsNodesMgr.ListSidecarNodes()
+	built := make(map[string]struct{}, len(nodes))
+	for i := range nodes {
+		services, ok := registryInfo[nodes[i].Namespace]
+		if !ok {
+			continue
+		}
+		key := nodes[i].SnapshotKey
+		if _, ok := built[key]; ok {
+			continue
+		}
+		built[key] = struct{}{}
+		_ = x.makeSidecarNodeSnapshot(nodes[i], services)
 	}
 	return nil
 }
 
-func (x *XDSServer) makeSnapshot(ns, version string, services []*ServiceInfo) (err error) {
+// clearNodeSnapshot 节点断开连接后，清理只属于该节点的 snapshot，命名空间级别共享的 snapshot 不需要清理
+func (x *XDSServer) clearNodeSnapshot(node *XDSClient) {
+	if !node.NeedNodeSnapshot() && !node.IsGateway() {
+		return
+	}
+	key := node.SnapshotKey
+	if x.xdsNodesMgr.HasSnapshotKey(key) {
+		return
+	}
+	log.Infof("[XDS] node %s disconnected, clear snapshot %s", node.Node.Id, key)
+	x.cache.ClearSnapshot(key)
+}
+
+// buildSidecarXDSCache 为声明了依赖服务或者带有地域信息的 sidecar 生成 snapshot
+func (x *XDSServer) buildSidecarXDSCache(xdsNode *XDSClient, services []*ServiceInfo) error {
+	if !xdsNode.NeedNodeSnapshot() {
+		return fmt.Errorf("xds node=%s run type not sidecar or not need node snapshot", xdsNode.Node.Id)
+	}
+
+	if len(services) == 0 {
+		registryInfo := map[string][]*ServiceInfo{
+			xdsNode.Namespace: {},
+		}
+		_ = x.getRegistryInfoWithCache(context.Background(), registryInfo)
+		services = registryInfo[xdsNode.Namespace]
+	}
+
+	return x.makeSidecarNodeSnapshot(xdsNode, services)
+}
+
+// filterDependencies 只保留 sidecar 依赖的服务，未声明依赖时返回全部服务
+func filterDependencies(xdsNode *XDSClient, services []*ServiceInfo) []*ServiceInfo {
+	if !xdsNode.HasDependencies() {
+		return services
+	}
+	deps := xdsNode.Dependencies()
+	ret := make([]*ServiceInfo, 0, len(deps))
+	for i := range services {
+		if _, ok := deps[services[i].Name]; ok {
+			ret = append(ret, services[i])
+		}
+	}
+	return ret
+}
+
+// makeSidecarResources 按照 tls 模式以及节点的地域生成 sidecar 的 xds 资源，policies 为 sidecar 所属服务的访问控制策略
+func (x *XDSServer) makeSidecarResources(tlsMode string, services []*ServiceInfo,
+	locality *core.Locality, policies []*model.AccessPolicy) map[resource.Type][]types.Resource {
 	resources := make(map[resource.Type][]types.Resource)
-	resources[resource.EndpointType] = makeEndpoints(services)
-	resources[resource.ClusterType] = x.makeClusters(services)
+	resources[resource.EndpointType] = makeEndpoints(services, locality)
 	resources[resource.RouteType] = x.makeSidecarVirtualHosts(services)
-	resources[resource.ListenerType] = makeListeners()
-	snapshot, err := cachev3.NewSnapshot(version, resources)
+	switch tlsMode {
+	case TLSModePermissive:
+		resources[resource.ClusterType] = x.makePermissiveClusters(services)
+		resources[resource.ListenerType] = makePermissiveListeners(policies)
+	case TLSModeStrict:
+		resources[resource.ClusterType] = x.makeStrictClusters(services)
+		resources[resource.ListenerType] = makeStrictListeners(policies)
+	default:
+		resources[resource.ClusterType] = x.makeClusters(services)
+		resources[resource.ListenerType] = makeListeners()
+	}
+	return resources
+}
+
+// makeSidecarNodeSnapshot 生成只包含依赖服务、按照节点地域设置 endpoint 优先级以及带有入口访问控制的 snapshot
+func (x *XDSServer) makeSidecarNodeSnapshot(xdsNode *XDSClient, services []*ServiceInfo) error {
+	nodeId := xdsNode.SnapshotKey
+	services = filterDependencies(xdsNode, services)
+	var policies []*model.AccessPolicy
+	if xdsNode.NeedAccessControl() {
+		namespace, service := workloadIdentity(xdsNode)
+		policies, _ = x.namingServer.Cache().AccessPolicy().GetAccessPolicies(service, namespace)
+	}
+	snapshot, err := newSnapshot(x.makeSidecarResources(xdsNode.Metadata[TLSModeTag], services,
+		xdsNode.Locality, policies))
+	if err != nil {
+		log.Errorf("[XDS][Sidecar] fail to create snapshot for %s, err is %v", nodeId, err)
+		return err
+	}
+	if err = snapshot.Consistent(); err != nil {
+		return err
+	}
+	log.Infof("[XDS][Sidecar] will serve node: %s ,snapshot: %+v", nodeId, string(dumpSnapShotJSON(snapshot)))
+	if err := x.cache.SetSnapshot(context.Background(), nodeId, snapshot); err != nil {
+		log.Errorf("[XDS][Sidecar] snapshot error %q for %+v", err, snapshot)
+		return err
+	}
+	return nil
+}
+
+func (x *XDSServer) makeSnapshot(ns string, services []*ServiceInfo) (err error) {
+	snapshot, err := newSnapshot(x.makeSidecarResources(TLSModeNone, services, nil, nil))
 	if err != nil {
 		log.Errorf("[XDS][Sidecar] fail to create snapshot for %s, err is %v", ns, err)
 		return err
@@ -83,13 +182,8 @@ func (x *XDSServer) makeSnapshot(ns, version string, services []*ServiceInfo) (e
 	return
 }
 
-func (x *XDSServer) makePermissiveSnapshot(ns, version string, services []*ServiceInfo) (err error) {
-	resources := make(map[resource.Type][]types.Resource)
-	resources[resource.EndpointType] = makeEndpoints(services)
-	resources[resource.ClusterType] = x.makePermissiveClusters(services)
-	resources[resource.RouteType] = x.makeSidecarVirtualHosts(services)
-	resources[resource.ListenerType] = makePermissiveListeners()
-	snapshot, err := cachev3.NewSnapshot(version, resources)
+func (x *XDSServer) makePermissiveSnapshot(ns string, services []*ServiceInfo) (err error) {
+	snapshot, err := newSnapshot(x.makeSidecarResources(TLSModePermissive, services, nil, nil))
 	if err != nil {
 		return err
 	}
@@ -106,13 +200,8 @@ func (x *XDSServer) makePermissiveSnapshot(ns, version string, services []*Servi
 	return
 }
 
-func (x *XDSServer) makeStrictSnapshot(ns, version string, services []*ServiceInfo) (err error) {
-	resources := make(map[resource.Type][]types.Resource)
-	resources[resource.EndpointType] = makeEndpoints(services)
-	resources[resource.ClusterType] = x.makeStrictClusters(services)
-	resources[resource.RouteType] = x.makeSidecarVirtualHosts(services)
-	resources[resource.ListenerType] = makeStrictListeners()
-	snapshot, err := cachev3.NewSnapshot(version, resources)
+func (x *XDSServer) makeStrictSnapshot(ns string, services []*ServiceInfo) (err error) {
+	snapshot, err := newSnapshot(x.makeSidecarResources(TLSModeStrict, services, nil, nil))
 	if err != nil {
 		return err
 	}
@@ -386,10 +475,16 @@ func formatEndpointHealth(ins *apiservice.Instance) core.HealthStatus {
 	return core.HealthStatus_UNHEALTHY
 }
 
-func makeEndpoints(services []*ServiceInfo) []types.Resource {
+// makeEndpoints 按照实例的地域分组生成 endpoint，locality 为请求节点的地域，用于计算各分组的优先级
+func makeEndpoints(services []*ServiceInfo, locality *core.Locality) []types.Resource {
 	var clusterLoads []types.Resource
 	for _, serviceInfo := range services {
-		var lbEndpoints []*endpoint.LbEndpoint
+		var (
+			localities  []*core.Locality
+			lbEndpoints [][]*endpoint.LbEndpoint
+			index       = map[string]int{}
+			checkPort   = faultDetectPort(serviceInfo)
+		)
 		for _, instance := range serviceInfo.Instances {
 			// 只加入健康的实例
 			if !isNormalEndpoint(instance) {
@@ -415,17 +510,30 @@ func makeEndpoints(services []*ServiceInfo) []types.Resource {
 				LoadBalancingWeight: utils.NewUInt32Value(instance.GetWeight().GetValue()),
 				Metadata:            getEndpointMetaFromPolarisIns(instance),
 			}
+			if checkPort > 0 {
+				// 探测规则指定了端口时，envoy 使用该端口进行主动健康检查
+				ep.GetEndpoint().HealthCheckConfig = &endpoint.Endpoint_HealthCheckConfig{PortValue: checkPort}
+			}
 
-			lbEndpoints = append(lbEndpoints, ep)
+			insLocality := instanceLocality(instance)
+			key := localityKey(insLocality)
+			pos, ok := index[key]
+			if !ok {
+				pos = len(localities)
+				index[key] = pos
+				localities = append(localities, insLocality)
+				lbEndpoints = append(lbEndpoints, nil)
+			}
+			lbEndpoints[pos] = append(lbEndpoints[pos], ep)
+		}
+		if len(localities) == 0 {
+			localities = append(localities, nil)
+			lbEndpoints = append(lbEndpoints, nil)
 		}
 
 		cla := &endpoint.ClusterLoadAssignment{
 			ClusterName: serviceInfo.Name,
-			Endpoints: []*endpoint.LocalityLbEndpoints{
-				{
-					LbEndpoints: lbEndpoints,
-				},
-			},
+			Endpoints:   groupLocalityEndpoints(locality, localities, lbEndpoints),
 		}
 
 		clusterLoads = append(clusterLoads, cla)
@@ -441,8 +549,7 @@ func makeSidecarRoutes(serviceInfo *ServiceInfo) []*route.Route {
 		matchAllRoute *route.Route
 	)
 	// 路由目前只处理 inbounds
-	rules := filterInboundRouterRule(serviceInfo)
-	for _, rule := range rules {
+	iterateInboundRouterRule(serviceInfo, func(ruleName string, rule *traffic_manage.SubRuleRouting) {
 		var (
 			matchAll     bool
 			destinations []*traffic_manage.DestinationGroup
@@ -485,23 +592,31 @@ func makeSidecarRoutes(serviceInfo *ServiceInfo) []*route.Route {
 				},
 			},
 		}
+		applyTrafficPolicy(currentRoute, selectTrafficPolicy(serviceInfo.TrafficPolicies, ruleName))
 		if matchAll {
 			matchAllRoute = currentRoute
 		} else {
 			routes = append(routes, currentRoute)
 		}
-	}
+	})
 	if matchAllRoute == nil {
 		// 如果没有路由，会进入最后的默认处理
-		routes = append(routes, getDefaultRoute(serviceInfo.Name))
-	} else {
-		routes = append(routes, matchAllRoute)
+		matchAllRoute = getDefaultRoute(serviceInfo.Name)
+		applyTrafficPolicy(matchAllRoute, selectTrafficPolicy(serviceInfo.TrafficPolicies, ""))
 	}
-	return routes
+	return append(routes, matchAllRoute)
 }
 
 func filterInboundRouterRule(svc *ServiceInfo) []*traffic_manage.SubRuleRouting {
 	ret := make([]*traffic_manage.SubRuleRouting, 0, 16)
+	iterateInboundRouterRule(svc, func(_ string, subRule *traffic_manage.SubRuleRouting) {
+		ret = append(ret, subRule)
+	})
+	return ret
+}
+
+// iterateInboundRouterRule 遍历目标为当前服务的路由子规则，同时给出子规则所属的路由规则名称
+func iterateInboundRouterRule(svc *ServiceInfo, proc func(ruleName string, subRule *traffic_manage.SubRuleRouting)) {
 	for _, rule := range svc.Routing.GetRules() {
 		if rule.GetRoutingPolicy() != traffic_manage.RoutingPolicy_RulePolicy {
 			continue
@@ -520,11 +635,10 @@ func filterInboundRouterRule(svc *ServiceInfo) []*traffic_manage.SubRuleRouting
 				}
 			}
 			if match {
-				ret = append(ret, routerRule.Rules[i])
+				proc(rule.GetName(), routerRule.Rules[i])
 			}
 		}
 	}
-	return ret
 }
 
 // 默认路由
@@ -0,0 +1,293 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+var (
+	// syncDebounce 合并短时间内的多次变更，减少 snapshot 的重复生成
+	syncDebounce = 500 * time.Millisecond
+	// syncRetryInterval 刷新失败的服务重新加入变更记录的等待时间
+	syncRetryInterval = 5 * time.Second
+	// syncFullInterval 定期全量对比的周期，兜底遗漏的变更通知
+	syncFullInterval = time.Minute
+)
+
+// changeSet 记录自上次推送以来发生变化的服务
+type changeSet struct {
+	lock     sync.Mutex
+	services map[string]struct{}
+	// full 为 true 时需要全量对比所有服务
+	full   bool
+	signal chan struct{}
+}
+
+func newChangeSet() *changeSet {
+	return &changeSet{
+		services: map[string]struct{}{},
+		signal:   make(chan struct{}, 1),
+	}
+}
+
+func (c *changeSet) addService(ids ...string) {
+	c.lock.Lock()
+	for _, id := range ids {
+		c.services[id] = struct{}{}
+	}
+	c.lock.Unlock()
+	c.wakeup()
+}
+
+func (c *changeSet) addAll() {
+	c.lock.Lock()
+	c.full = true
+	c.lock.Unlock()
+	c.wakeup()
+}
+
+func (c *changeSet) wakeup() {
+	select {
+	case c.signal <- struct{}{}:
+	default:
+	}
+}
+
+// take 取出当前所有的变更，并重置变更记录
+func (c *changeSet) take() ([]string, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	full := c.full
+	ids := make([]string, 0, len(c.services))
+	for id := range c.services {
+		ids = append(ids, id)
+	}
+	c.services = map[string]struct{}{}
+	c.full = false
+	return ids, full
+}
+
+// cacheChangeListener 监听服务、路由、限流以及熔断探测缓存的变化
+type cacheChangeListener struct {
+	changes *changeSet
+}
+
+// OnCreated callback when cache value created
+func (l *cacheChangeListener) OnCreated(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnUpdated callback when cache value updated
+func (l *cacheChangeListener) OnUpdated(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnDeleted callback when cache value deleted
+func (l *cacheChangeListener) OnDeleted(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnBatchCreated callback when cache value created
+func (l *cacheChangeListener) OnBatchCreated(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnBatchUpdated callback when cache value updated
+func (l *cacheChangeListener) OnBatchUpdated(value interface{}) {
+	l.onChanged(value)
+}
+
+// OnBatchDeleted callback when cache value deleted
+func (l *cacheChangeListener) OnBatchDeleted(value interface{}) {
+	l.onChanged(value)
+}
+
+func (l *cacheChangeListener) onChanged(value interface{}) {
+	switch v := value.(type) {
+	case map[string]*model.Service:
+		ids := make([]string, 0, len(v))
+		for _, svc := range v {
+			ids = append(ids, svc.ID)
+		}
+		l.changes.addService(ids...)
+	case []*model.RoutingConfig:
+		ids := make([]string, 0, len(v))
+		for _, rule := range v {
+			ids = append(ids, rule.ID)
+		}
+		l.changes.addService(ids...)
+	case []*model.RouterConfig, []*model.CircuitBreakerRule, []*model.FaultDetectRule:
+		// v2 路由、熔断以及探测规则可以通过通配符引用任意服务，无法直接定位受影响的服务
+		l.changes.addAll()
+	case []*model.TrafficPolicy:
+		// 流量策略通过服务名关联服务，且会影响引用该服务的网关路由，直接全量对比
+		l.changes.addAll()
+	case []*model.AccessPolicy:
+		// 访问控制策略的缓存只记录了服务名，直接全量对比
+		l.changes.addAll()
+	case []*model.RateLimit:
+		ids := make([]string, 0, len(v))
+		for _, rule := range v {
+			ids = append(ids, rule.ServiceID)
+		}
+		l.changes.addService(ids...)
+	}
+}
+
+// runSyncTask 等待缓存变更通知，只重新生成受影响命名空间的 snapshot，同时低频全量对比兜底
+func (x *XDSServer) runSyncTask(ctx context.Context) {
+	ticker := time.NewTicker(syncFullInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-x.changes.signal:
+		case <-ticker.C:
+			x.syncAll(ctx)
+			continue
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-time.After(syncDebounce):
+		case <-ctx.Done():
+			return
+		}
+
+		ids, full := x.changes.take()
+		if full {
+			x.syncAll(ctx)
+			continue
+		}
+		if len(ids) > 0 {
+			x.syncServices(ctx, ids)
+		}
+	}
+}
+
+// syncServices 只刷新发生变化的服务，并推送这些服务所在的命名空间
+func (x *XDSServer) syncServices(ctx context.Context, ids []string) {
+	x.registryLock.Lock()
+	defer x.registryLock.Unlock()
+
+	// 服务 ID 到命名空间的索引，以及服务别名的反向索引
+	index := make(map[string]string)
+	aliases := make(map[string][]string)
+	for ns, infos := range x.registryInfo {
+		for _, info := range infos {
+			index[info.ID] = ns
+			if info.AliasFor != nil {
+				aliases[info.AliasFor.ID] = append(aliases[info.AliasFor.ID], info.ID)
+			}
+		}
+	}
+
+	changed := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		changed[id] = struct{}{}
+		for _, alias := range aliases[id] {
+			changed[alias] = struct{}{}
+		}
+	}
+
+	needPush := make(map[string][]*ServiceInfo)
+	failed := make([]string, 0, 4)
+	for id := range changed {
+		svc := x.namingServer.Cache().Service().GetServiceByID(id)
+		if svc == nil {
+			ns, ok := index[id]
+			if !ok {
+				continue
+			}
+			x.registryInfo[ns] = removeServiceInfo(x.registryInfo[ns], id)
+			needPush[ns] = x.registryInfo[ns]
+			continue
+		}
+
+		info := &ServiceInfo{
+			ID:        svc.ID,
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+			Instances: []*apiservice.Instance{},
+			Ports:     svc.Ports,
+			Metadata:  svc.Meta,
+		}
+		if err := x.fillServiceInfo(ctx, info); err != nil {
+			log.Errorf("[XDSV3] sync service %s error %v", svc.Name, err)
+			failed = append(failed, id)
+			continue
+		}
+		infos, updated := upsertServiceInfo(x.registryInfo[svc.Namespace], info)
+		if !updated {
+			continue
+		}
+		x.registryInfo[svc.Namespace] = infos
+		needPush[svc.Namespace] = infos
+	}
+	if len(failed) > 0 {
+		x.retrySync(func() { x.changes.addService(failed...) })
+	}
+
+	if len(needPush) > 0 {
+		_ = x.pushSidecarInfoToXDSCache(needPush)
+		_ = x.pushGatewayInfoToXDSCache(needPush)
+		_ = x.pushProxylessInfoToXDSCache(needPush)
+	}
+}
+
+// retrySync 等待 syncRetryInterval 之后重新加入变更记录，避免持续失败时频繁重试
+func (x *XDSServer) retrySync(requeue func()) {
+	time.AfterFunc(syncRetryInterval, requeue)
+}
+
+// upsertServiceInfo 更新或插入服务信息，返回新的服务列表以及是否发生了变化
+func upsertServiceInfo(infos []*ServiceInfo, info *ServiceInfo) ([]*ServiceInfo, bool) {
+	ret := make([]*ServiceInfo, 0, len(infos)+1)
+	updated := true
+	for _, item := range infos {
+		if item.ID != info.ID {
+			ret = append(ret, item)
+			continue
+		}
+		updated = serviceInfoChanged(item, info)
+	}
+	if !updated {
+		return infos, false
+	}
+	ret = append(ret, info)
+	sortServiceInfos(ret)
+	return ret, true
+}
+
+func removeServiceInfo(infos []*ServiceInfo, id string) []*ServiceInfo {
+	ret := make([]*ServiceInfo, 0, len(infos))
+	for _, item := range infos {
+		if item.ID != id {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
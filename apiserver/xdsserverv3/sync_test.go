@@ -0,0 +1,127 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/cache/types"
+	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v3"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func Test_cacheChangeListener(t *testing.T) {
+	changes := newChangeSet()
+	listener := &cacheChangeListener{changes: changes}
+
+	listener.OnBatchUpdated(map[string]*model.Service{"a": {ID: "svc-a"}})
+	listener.OnBatchUpdated([]*model.RateLimit{{ServiceID: "svc-b"}})
+	listener.OnBatchUpdated([]*model.RoutingConfig{{ID: "svc-a"}})
+
+	select {
+	case <-changes.signal:
+	default:
+		t.Fatal("change set must be signaled")
+	}
+	ids, full := changes.take()
+	assert.False(t, full)
+	assert.ElementsMatch(t, []string{"svc-a", "svc-b"}, ids)
+
+	listener.OnBatchUpdated([]*model.RouterConfig{{ID: "rule-1"}})
+	ids, full = changes.take()
+	assert.True(t, full)
+	assert.Empty(t, ids)
+
+	listener.OnBatchUpdated([]*model.CircuitBreakerRule{{ID: "cb-1"}})
+	_, full = changes.take()
+	assert.True(t, full)
+}
+
+func TestXDSServer_retrySync(t *testing.T) {
+	old := syncRetryInterval
+	syncRetryInterval = 10 * time.Millisecond
+	defer func() { syncRetryInterval = old }()
+
+	x := &XDSServer{changes: newChangeSet()}
+	x.retrySync(func() { x.changes.addService("svc-a") })
+	ids, _ := x.changes.take()
+	assert.Empty(t, ids, "must wait retry interval")
+
+	select {
+	case <-x.changes.signal:
+	case <-time.After(time.Second):
+		t.Fatal("failed services must be re-queued")
+	}
+	ids, full := x.changes.take()
+	assert.False(t, full)
+	assert.Equal(t, []string{"svc-a"}, ids)
+}
+
+func Test_upsertServiceInfo(t *testing.T) {
+	infos := []*ServiceInfo{
+		{ID: "1", Name: "a", SvcInsRevision: "r1"},
+		{ID: "3", Name: "c", SvcInsRevision: "r1"},
+	}
+
+	ret, updated := upsertServiceInfo(infos, &ServiceInfo{ID: "1", Name: "a", SvcInsRevision: "r1"})
+	assert.False(t, updated)
+	assert.Equal(t, infos, ret)
+
+	ret, updated = upsertServiceInfo(infos, &ServiceInfo{ID: "2", Name: "b", SvcInsRevision: "r1"})
+	assert.True(t, updated)
+	assert.Equal(t, []string{"a", "b", "c"}, []string{ret[0].Name, ret[1].Name, ret[2].Name})
+
+	ret, updated = upsertServiceInfo(ret, &ServiceInfo{ID: "3", Name: "c", SvcInsRevision: "r2"})
+	assert.True(t, updated)
+	assert.Len(t, ret, 3)
+	assert.Equal(t, "r2", ret[2].SvcInsRevision)
+
+	ret = removeServiceInfo(ret, "2")
+	assert.Equal(t, []string{"a", "c"}, []string{ret[0].Name, ret[1].Name})
+}
+
+func Test_newSnapshotVersion(t *testing.T) {
+	makeClusters := func(names ...string) []types.Resource {
+		ret := make([]types.Resource, 0, len(names))
+		for _, name := range names {
+			ret = append(ret, &cluster.Cluster{Name: name})
+		}
+		return ret
+	}
+
+	s1, err := newSnapshot(map[resource.Type][]types.Resource{
+		resource.ClusterType: makeClusters("a", "b"),
+	})
+	assert.NoError(t, err)
+	s2, err := newSnapshot(map[resource.Type][]types.Resource{
+		resource.ClusterType: makeClusters("a", "b"),
+	})
+	assert.NoError(t, err)
+	// 内容不变时版本号保持不变，避免无意义的推送
+	assert.Equal(t, s1.GetVersion(resource.ClusterType), s2.GetVersion(resource.ClusterType))
+
+	s3, err := newSnapshot(map[resource.Type][]types.Resource{
+		resource.ClusterType: makeClusters("a", "c"),
+	})
+	assert.NoError(t, err)
+	assert.NotEqual(t, s1.GetVersion(resource.ClusterType), s3.GetVersion(resource.ClusterType))
+}
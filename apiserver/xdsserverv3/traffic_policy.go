@@ -0,0 +1,154 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"strings"
+	"time"
+
+	cluster "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	route "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	faultcommon "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/common/fault/v3"
+	fault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	hcm "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// selectTrafficPolicy 选出路由对应的流量策略，路由级别的策略优先于服务级别的策略
+func selectTrafficPolicy(policies []*model.TrafficPolicy, routeName string) *model.TrafficPolicy {
+	var svcPolicy *model.TrafficPolicy
+	for _, policy := range policies {
+		if policy.Spec == nil {
+			continue
+		}
+		if routeName != "" && policy.RouteName == routeName {
+			return policy
+		}
+		if policy.RouteName == "" {
+			svcPolicy = policy
+		}
+	}
+	return svcPolicy
+}
+
+// applyTrafficPolicy 将流量策略中的超时、重试以及故障注入配置设置到 envoy route 上
+func applyTrafficPolicy(r *route.Route, policy *model.TrafficPolicy) {
+	if policy == nil || policy.Spec == nil {
+		return
+	}
+	spec := policy.Spec
+	if action := r.GetRoute(); action != nil {
+		if spec.Timeout > 0 {
+			action.Timeout = ptypes.DurationProto(time.Duration(spec.Timeout) * time.Millisecond)
+		}
+		action.RetryPolicy = makeRetryPolicy(spec.Retry)
+	}
+	if faultConf := makeHTTPFault(spec.Fault); faultConf != nil {
+		if r.TypedPerFilterConfig == nil {
+			r.TypedPerFilterConfig = map[string]*anypb.Any{}
+		}
+		r.TypedPerFilterConfig[wellknown.Fault] = mustNewAny(faultConf)
+	}
+}
+
+func makeRetryPolicy(retry *model.RetryPolicy) *route.RetryPolicy {
+	if retry == nil || retry.NumRetries == 0 {
+		return nil
+	}
+	policy := &route.RetryPolicy{
+		RetryOn:              strings.Join(retry.RetryOn, ","),
+		NumRetries:           &wrappers.UInt32Value{Value: retry.NumRetries},
+		RetriableStatusCodes: retry.RetriableStatusCodes,
+	}
+	if policy.RetryOn == "" {
+		// 与 envoy 默认的重试条件保持一致
+		policy.RetryOn = "connect-failure,refused-stream,unavailable,cancelled,retriable-status-codes"
+	}
+	if retry.PerTryTimeout > 0 {
+		policy.PerTryTimeout = ptypes.DurationProto(time.Duration(retry.PerTryTimeout) * time.Millisecond)
+	}
+	return policy
+}
+
+func makeHTTPFault(conf *model.FaultInjection) *fault.HTTPFault {
+	if conf == nil || (conf.Delay == nil && conf.Abort == nil) {
+		return nil
+	}
+	ret := &fault.HTTPFault{}
+	if delay := conf.Delay; delay != nil && delay.Percentage > 0 {
+		ret.Delay = &faultcommon.FaultDelay{
+			FaultDelaySecifier: &faultcommon.FaultDelay_FixedDelay{
+				FixedDelay: ptypes.DurationProto(time.Duration(delay.FixedDelay) * time.Millisecond),
+			},
+			Percentage: toFractionalPercent(delay.Percentage),
+		}
+	}
+	if abort := conf.Abort; abort != nil && abort.Percentage > 0 {
+		ret.Abort = &fault.FaultAbort{
+			ErrorType:  &fault.FaultAbort_HttpStatus{HttpStatus: abort.HttpStatus},
+			Percentage: toFractionalPercent(abort.Percentage),
+		}
+	}
+	if ret.Delay == nil && ret.Abort == nil {
+		return nil
+	}
+	return ret
+}
+
+// toFractionalPercent 百分比转换为百万分比，保留最多四位小数的精度
+func toFractionalPercent(percent float64) *envoy_type_v3.FractionalPercent {
+	return &envoy_type_v3.FractionalPercent{
+		Numerator:   uint32(percent * 10000),
+		Denominator: envoy_type_v3.FractionalPercent_MILLION,
+	}
+}
+
+// makeFaultFilter 故障注入过滤器，未在路由上配置故障注入时不生效
+func makeFaultFilter() *hcm.HttpFilter {
+	return &hcm.HttpFilter{
+		Name: wellknown.Fault,
+		ConfigType: &hcm.HttpFilter_TypedConfig{
+			TypedConfig: mustNewAny(&fault.HTTPFault{}),
+		},
+	}
+}
+
+// makeRetryBudget 服务级别流量策略中的重试预算，转换为 cluster 的 circuit breakers
+func makeRetryBudget(serviceInfo *ServiceInfo) *cluster.CircuitBreakers {
+	policy := selectTrafficPolicy(serviceInfo.TrafficPolicies, "")
+	if policy == nil || policy.Spec.Retry == nil || policy.Spec.Retry.Budget == nil {
+		return nil
+	}
+	budget := policy.Spec.Retry.Budget
+	return &cluster.CircuitBreakers{
+		Thresholds: []*cluster.CircuitBreakers_Thresholds{
+			{
+				RetryBudget: &cluster.CircuitBreakers_Thresholds_RetryBudget{
+					BudgetPercent:       &envoy_type_v3.Percent{Value: budget.BudgetPercent},
+					MinRetryConcurrency: &wrappers.UInt32Value{Value: budget.MinRetryConcurrency},
+				},
+			},
+		},
+	}
+}
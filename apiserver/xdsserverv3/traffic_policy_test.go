@@ -0,0 +1,139 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package xdsserverv3
+
+import (
+	"testing"
+	"time"
+
+	fault "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/fault/v3"
+	envoy_type_v3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func buildTrafficPolicyService() *ServiceInfo {
+	routing := &apitraffic.RuleRoutingConfig{
+		Rules: []*apitraffic.SubRuleRouting{
+			{
+				Sources: []*apitraffic.SourceService{
+					{
+						Service:   "*",
+						Namespace: "*",
+						Arguments: []*apitraffic.SourceMatch{
+							{
+								Type: apitraffic.SourceMatch_HEADER,
+								Key:  "env",
+								Value: &apimodel.MatchString{
+									Type:  apimodel.MatchString_EXACT,
+									Value: &wrappers.StringValue{Value: "gray"},
+								},
+							},
+						},
+					},
+				},
+				Destinations: []*apitraffic.DestinationGroup{
+					{Service: "tp-svc", Namespace: "default", Weight: 100},
+				},
+			},
+		},
+	}
+	return &ServiceInfo{
+		Name:      "tp-svc",
+		Namespace: "default",
+		Routing: &apitraffic.Routing{
+			Rules: []*apitraffic.RouteRule{
+				{
+					Name:          "gray-route",
+					RoutingPolicy: apitraffic.RoutingPolicy_RulePolicy,
+					RoutingConfig: mustNewAny(proto.MessageV2(routing)),
+				},
+			},
+		},
+		TrafficPolicies: []*model.TrafficPolicy{
+			{
+				ServicePolicy: model.ServicePolicy{ID: "svc-policy", Name: "svc-policy"},
+				Spec: &model.TrafficPolicySpec{
+					Timeout: 3000,
+					Retry: &model.RetryPolicy{
+						RetryOn:       []string{"5xx", "connect-failure"},
+						NumRetries:    2,
+						PerTryTimeout: 1000,
+						Budget:        &model.RetryBudget{BudgetPercent: 20, MinRetryConcurrency: 3},
+					},
+				},
+			},
+			{
+				ServicePolicy: model.ServicePolicy{ID: "route-policy", Name: "route-policy"},
+				RouteName:     "gray-route",
+				Spec: &model.TrafficPolicySpec{
+					Fault: &model.FaultInjection{
+						Delay: &model.FaultDelay{Percentage: 50, FixedDelay: 200},
+						Abort: &model.FaultAbort{Percentage: 12.5, HttpStatus: 503},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestMakeSidecarRoutesWithTrafficPolicy(t *testing.T) {
+	svc := buildTrafficPolicyService()
+	routes := makeSidecarRoutes(svc)
+	assert.Equal(t, 2, len(routes))
+
+	// 路由级别的策略覆盖服务级别的策略
+	grayRoute := routes[0]
+	assert.Nil(t, grayRoute.GetRoute().GetRetryPolicy())
+	assert.Nil(t, grayRoute.GetRoute().GetTimeout())
+	faultConf := &fault.HTTPFault{}
+	assert.NoError(t, grayRoute.GetTypedPerFilterConfig()[wellknown.Fault].UnmarshalTo(faultConf))
+	assert.Equal(t, 200*time.Millisecond, faultConf.GetDelay().GetFixedDelay().AsDuration())
+	assert.Equal(t, uint32(500000), faultConf.GetDelay().GetPercentage().GetNumerator())
+	assert.Equal(t, envoy_type_v3.FractionalPercent_MILLION, faultConf.GetAbort().GetPercentage().GetDenominator())
+	assert.Equal(t, uint32(125000), faultConf.GetAbort().GetPercentage().GetNumerator())
+	assert.Equal(t, uint32(503), faultConf.GetAbort().GetHttpStatus())
+
+	// 默认路由使用服务级别的策略
+	defaultRoute := routes[1]
+	assert.Equal(t, 3*time.Second, defaultRoute.GetRoute().GetTimeout().AsDuration())
+	retry := defaultRoute.GetRoute().GetRetryPolicy()
+	assert.Equal(t, "5xx,connect-failure", retry.GetRetryOn())
+	assert.Equal(t, uint32(2), retry.GetNumRetries().GetValue())
+	assert.Equal(t, time.Second, retry.GetPerTryTimeout().AsDuration())
+	assert.Empty(t, defaultRoute.GetTypedPerFilterConfig())
+}
+
+func TestMakeRetryBudget(t *testing.T) {
+	svc := buildTrafficPolicyService()
+	x := &XDSServer{}
+	c := x.makeCluster(svc)
+	thresholds := c.GetCircuitBreakers().GetThresholds()
+	assert.Equal(t, 1, len(thresholds))
+	assert.Equal(t, float64(20), thresholds[0].GetRetryBudget().GetBudgetPercent().GetValue())
+	assert.Equal(t, uint32(3), thresholds[0].GetRetryBudget().GetMinRetryConcurrency().GetValue())
+
+	svc.TrafficPolicies = nil
+	assert.Nil(t, x.makeCluster(svc).GetCircuitBreakers())
+}
@@ -79,6 +79,18 @@ type UserServer interface {
 	// Login 登录动作
 	Login(req *apisecurity.LoginRequest) *apiservice.Response
 
+	// OIDCLoginURL 生成 OIDC 单点登录跳转到 IdP 的授权地址以及需要写入 cookie 的 nonce
+	OIDCLoginURL(ctx context.Context) (*OIDCAuthorization, error)
+
+	// OIDCCallback 处理 IdP 的授权回调，校验 cookie 中的 nonce，完成用户映射后返回带有效期的登录 token
+	OIDCCallback(ctx context.Context, code, state, nonce string) *apiservice.Response
+
+	// CreateScopedToken 为当前用户签发带有效期以及授权范围限制的临时 token
+	CreateScopedToken(ctx context.Context, req *ScopedTokenRequest) *ScopedTokenResponse
+
+	// RevokeScopedToken 吊销临时 token
+	RevokeScopedToken(ctx context.Context, req *RevokeScopedTokenRequest) *ScopedTokenResponse
+
 	GroupOperator
 }
 
@@ -137,6 +149,21 @@ type StrategyServer interface {
 	// GetPrincipalResources 获取某个 principal 的所有可操作资源列表
 	GetPrincipalResources(ctx context.Context, query map[string]string) *apiservice.Response
 
+	// UpdateStrategyAction 修改鉴权策略的动作，支持只读、读写以及按操作授权
+	UpdateStrategyAction(ctx context.Context, req *StrategyAction) *StrategyActionResponse
+
+	// GetStrategyAction 查询鉴权策略的动作
+	GetStrategyAction(ctx context.Context, id string) *StrategyActionResponse
+
+	// GetStrategyConflicts 查询与该鉴权策略存在 ALLOW/DENY 冲突的信息
+	GetStrategyConflicts(ctx context.Context, id string) *StrategyConflictsResponse
+
+	// UpdateStrategyRules 修改鉴权策略中的治理规则资源，支持按照规则ID以及规则名称通配授权
+	UpdateStrategyRules(ctx context.Context, req *StrategyRuleResources) *StrategyRuleResourcesResponse
+
+	// GetStrategyRules 查询鉴权策略中的治理规则资源
+	GetStrategyRules(ctx context.Context, id string) *StrategyRuleResourcesResponse
+
 	// GetAuthChecker 获取鉴权检查器
 	GetAuthChecker() AuthChecker
 
@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
@@ -73,13 +74,29 @@ func (d *defaultAuthChecker) Initialize(options *auth.Config, s store.Store, cac
 		if err := json.Unmarshal(strategyContentBytes, cfg); err != nil {
 			return err
 		}
-		userContentBytes, err = json.Marshal(options.User.Option)
+		// oidc、ldap、mtls 为嵌套的配置结构，无法直接进行 json 序列化，需要单独解析
+		userOption := make(map[string]interface{}, len(options.User.Option))
+		for k, v := range options.User.Option {
+			if k != "oidc" && k != "ldap" && k != "mtls" {
+				userOption[k] = v
+			}
+		}
+		userContentBytes, err = json.Marshal(userOption)
 		if err != nil {
 			return err
 		}
 		if err := json.Unmarshal(userContentBytes, cfg); err != nil {
 			return err
 		}
+		if cfg.OIDC, err = ParseOIDCConfig(options.User.Option["oidc"]); err != nil {
+			return err
+		}
+		if cfg.LDAP, err = ParseLDAPConfig(options.User.Option["ldap"]); err != nil {
+			return err
+		}
+		if cfg.CertPrincipal, err = ParseCertPrincipalConfig(options.User.Option["mtls"]); err != nil {
+			return err
+		}
 	} else {
 		log.Warn("[Auth][Checker] auth.option has deprecated, use auth.user.option and auth.strategy.option instead.")
 		authContentBytes, err = json.Marshal(options.Option)
@@ -96,6 +113,9 @@ func (d *defaultAuthChecker) Initialize(options *auth.Config, s store.Store, cac
 	}
 
 	AuthOption = cfg
+	if _, err := scopedTokenKey(); err != nil {
+		log.Warn("[Auth][Checker] " + err.Error())
+	}
 	d.cacheMgn = cacheMgn
 	return nil
 }
@@ -153,6 +173,10 @@ func (d *defaultAuthChecker) checkMaintainPermission(preCtx *model.AcquireContex
 	if tokenInfo.Disable {
 		return false, model.ErrorTokenDisabled
 	}
+	// 临时 token 不允许执行运维写操作
+	if tokenInfo.IsScopedToken() {
+		return false, model.ErrorTokenOutOfScope
+	}
 	if !tokenInfo.IsUserToken {
 		return false, errors.New("only user role can access maintain API")
 	}
@@ -169,6 +193,7 @@ func (d *defaultAuthChecker) checkMaintainPermission(preCtx *model.AcquireContex
 //		case 1. 如果 token 被禁用
 //				a. 读操作，直接放通
 //				b. 写操作，快速失败
+//		case 2. 如果是临时 token，写操作涉及的资源必须在 token 的授权范围内
 //	step 3. 拉取token对应的操作者相关信息，注入到请求上下文中
 //	step 4. 进行权限检查
 func (d *defaultAuthChecker) CheckPermission(authCtx *model.AcquireContext) (bool, error) {
@@ -186,6 +211,11 @@ func (d *defaultAuthChecker) CheckPermission(authCtx *model.AcquireContext) (boo
 	if operatorInfo.Disable {
 		return false, model.ErrorTokenDisabled
 	}
+	if err := checkTokenScope(authCtx, operatorInfo); err != nil {
+		log.Error("[Auth][Checker] operation out of token scope", utils.ZapRequestID(reqId),
+			zap.String("method", authCtx.GetMethod()), zap.String("token", operatorInfo.String()))
+		return false, err
+	}
 
 	ok, err := d.doCheckPermission(authCtx)
 	if ok {
@@ -222,6 +252,7 @@ func canDowngradeAnonymous(authCtx *model.AcquireContext, err error) bool {
 
 // VerifyCredential 对 token 进行检查验证，并将 verify 过程中解析出的数据注入到 model.AcquireContext 中
 // step 1. 首先对 token 进行解析，获取相关的数据信息，注入到整个的 AcquireContext 中
+// 请求没有携带 token 时，尝试将 mTLS 客户端证书映射为对应的用户/用户组
 // step 2. 最后对 token 进行一些验证步骤的执行
 // step 3. 兜底措施：如果开启了鉴权的非严格模式，则根据错误的类型，判断是否转为匿名用户进行访问
 //   - 如果不是访问权限控制相关模块（用户、用户组、权限策略），不得转为匿名用户
@@ -229,10 +260,21 @@ func (d *defaultAuthChecker) VerifyCredential(authCtx *model.AcquireContext) err
 	reqId := utils.ParseRequestID(authCtx.GetRequestContext())
 
 	checkErr := func() error {
-		authToken := utils.ParseAuthToken(authCtx.GetRequestContext())
-		operator, err := d.decodeToken(authToken)
+		var (
+			authToken = utils.ParseAuthToken(authCtx.GetRequestContext())
+			operator  OperatorInfo
+			err       error
+		)
+		if clientCert := utils.ParseClientCert(authCtx.GetRequestContext()); authToken == "" && clientCert != nil {
+			operator, err = d.decodeClientCert(clientCert)
+		} else {
+			operator, err = d.decodeToken(authToken)
+		}
 		if err != nil {
 			log.Error("[Auth][Checker] decode token", zap.Error(err))
+			if errors.Is(err, model.ErrorTokenExpired) {
+				return err
+			}
 			return model.ErrorTokenInvalid
 		}
 
@@ -302,6 +344,17 @@ func (d *defaultAuthChecker) decodeToken(t string) (OperatorInfo, error) {
 	if t == "" {
 		return OperatorInfo{}, model.ErrorTokenInvalid
 	}
+	if isScopedToken(t) {
+		key, err := scopedTokenKey()
+		if err != nil {
+			return OperatorInfo{}, err
+		}
+		claims, err := parseScopedToken(key, t, time.Now())
+		if err != nil {
+			return OperatorInfo{}, err
+		}
+		return claims.toOperator(t), nil
+	}
 
 	ret, err := decryptMessage([]byte(AuthOption.Salt), t)
 	if err != nil {
@@ -340,7 +393,15 @@ func (d *defaultAuthChecker) checkToken(tokenInfo *OperatorInfo) (string, bool,
 			return "", false, model.ErrorNoUser
 		}
 
-		if tokenInfo.Origin != user.Token {
+		switch {
+		case tokenInfo.TokenID != "":
+			// 临时 token 由签名保证合法性，只需要检查是否已经被吊销
+			if d.Cache().User().IsTokenRevoked(tokenInfo.TokenID) {
+				return "", false, model.ErrorTokenRevoked
+			}
+		case tokenInfo.CertIdentity != "":
+			// 客户端证书已经在 TLS 握手时完成校验
+		case tokenInfo.Origin != user.Token:
 			return "", false, model.ErrorTokenNotExist
 		}
 
@@ -356,7 +417,7 @@ func (d *defaultAuthChecker) checkToken(tokenInfo *OperatorInfo) (string, bool,
 		return "", false, model.ErrorNoUserGroup
 	}
 
-	if tokenInfo.Origin != group.Token {
+	if tokenInfo.CertIdentity == "" && tokenInfo.Origin != group.Token {
 		return "", false, model.ErrorTokenNotExist
 	}
 
@@ -490,19 +551,72 @@ func (d *defaultAuthChecker) checkToken(tokenInfo *OperatorInfo) (string, bool,
 func (d *defaultAuthChecker) isResourceEditable(
 	userid string,
 	resourceType apisecurity.ResourceType,
-	resEntries []model.ResourceEntry) bool {
+	resEntries []model.ResourceEntry,
+	op model.ResourceOperation) bool {
 	principal := model.Principal{
 		PrincipalID:   userid,
 		PrincipalRole: model.PrincipalUser,
 	}
 	for _, entry := range resEntries {
-		if !d.cacheMgn.AuthStrategy().IsResourceEditable(principal, resourceType, entry.ID) {
+		if !d.cacheMgn.AuthStrategy().IsResourceOperable(principal, resourceType, entry.ID, op) {
 			return false
 		}
 	}
 	return true
 }
 
+// isRuleResourceEditable 治理规则按照规则ID以及规则名称匹配策略
+func (d *defaultAuthChecker) isRuleResourceEditable(
+	userid string,
+	resourceType model.RuleResourceType,
+	resEntries []model.ResourceEntry,
+	op model.ResourceOperation) bool {
+	principal := model.Principal{
+		PrincipalID:   userid,
+		PrincipalRole: model.PrincipalUser,
+	}
+	for _, entry := range resEntries {
+		if !d.cacheMgn.AuthStrategy().IsRuleResourceOperable(principal, resourceType, entry.ID, entry.Name, op) {
+			return false
+		}
+	}
+	return true
+}
+
+// isParentNamespaceDenied 命名空间上的拒绝策略对其下的服务、配置分组以及治理规则同样生效，
+// 即使本次请求不需要校验命名空间的授权(例如修改服务)，也需要检查资源所属命名空间的拒绝策略
+func (d *defaultAuthChecker) isParentNamespaceDenied(userid string, authCtx *model.AcquireContext,
+	op model.ResourceOperation) bool {
+	namespaces := utils.NewStringSet()
+	for resType, entries := range authCtx.GetAccessResources() {
+		if resType == apisecurity.ResourceType_Namespaces {
+			continue
+		}
+		for _, entry := range entries {
+			namespaces.Add(entry.Namespace)
+		}
+	}
+	for _, entries := range authCtx.GetRuleResources() {
+		for _, entry := range entries {
+			namespaces.Add(entry.Namespace)
+		}
+	}
+	principal := model.Principal{
+		PrincipalID:   userid,
+		PrincipalRole: model.PrincipalUser,
+	}
+	for _, namespace := range namespaces.ToSlice() {
+		if namespace == "" {
+			continue
+		}
+		if d.cacheMgn.AuthStrategy().IsResourceDenied(principal, apisecurity.ResourceType_Namespaces,
+			namespace, op) {
+			return true
+		}
+	}
+	return false
+}
+
 // doCheckPermission 执行权限检查
 func (d *defaultAuthChecker) doCheckPermission(authCtx *model.AcquireContext) (bool, error) {
 
@@ -515,21 +629,24 @@ func (d *defaultAuthChecker) doCheckPermission(authCtx *model.AcquireContext) (b
 	svcResEntries := reqRes[apisecurity.ResourceType_Services]
 	cfgResEntries := reqRes[apisecurity.ResourceType_ConfigGroups]
 
-	checkNamespace = d.isResourceEditable(userId, apisecurity.ResourceType_Namespaces, nsResEntries)
-	checkSvc = d.isResourceEditable(userId, apisecurity.ResourceType_Services, svcResEntries)
-	checkCfgGroup = d.isResourceEditable(userId, apisecurity.ResourceType_ConfigGroups, cfgResEntries)
+	op := authCtx.GetOperation()
+	checkNamespace = d.isResourceEditable(userId, apisecurity.ResourceType_Namespaces, nsResEntries, op)
+	checkSvc = d.isResourceEditable(userId, apisecurity.ResourceType_Services, svcResEntries, op)
+	checkCfgGroup = d.isResourceEditable(userId, apisecurity.ResourceType_ConfigGroups, cfgResEntries, op)
 
-	checkAllResEntries := checkNamespace && checkSvc && checkCfgGroup
+	checkAllResEntries := checkNamespace && checkSvc && checkCfgGroup &&
+		!d.isParentNamespaceDenied(userId, authCtx, op)
+	for resType, entries := range authCtx.GetRuleResources() {
+		if !checkAllResEntries {
+			break
+		}
+		checkAllResEntries = d.isRuleResourceEditable(userId, resType, entries, op)
+	}
 
 	var err error
 	if !checkAllResEntries {
-		err = fmt.Errorf("no permission")
+		err = fmt.Errorf("no permission to %s resource", strings.ToLower(op.String()))
 	}
 
 	return checkAllResEntries, err
 }
-
-// checkAction 检查操作是否和策略匹配
-func (d *defaultAuthChecker) checkAction(expect string, actual model.ResourceOperation) bool {
-	return true
-}
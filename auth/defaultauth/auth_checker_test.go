@@ -62,6 +62,7 @@ func Test_defaultAuthChecker_VerifyCredential(t *testing.T) {
 	storage.EXPECT().GetUnixSecond(gomock.Any()).AnyTimes().Return(time.Now().Unix(), nil)
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return([]*model.UserGroupDetail{}, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cacheMgn, err := cache.TestCacheInitialize(ctx, &cache.Config{
@@ -225,6 +226,7 @@ func Test_defaultAuthChecker_CheckPermission_Write_NoStrict(t *testing.T) {
 
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 	storage.EXPECT().GetStrategyDetailsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(strategies, nil)
 	storage.EXPECT().GetMoreNamespaces(gomock.Any()).AnyTimes().Return(namespaces, nil)
 	storage.EXPECT().GetMoreServices(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(serviceMap, nil)
@@ -465,6 +467,7 @@ func Test_defaultAuthChecker_CheckPermission_Write_Strict(t *testing.T) {
 
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 	storage.EXPECT().GetStrategyDetailsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(strategies, nil)
 	storage.EXPECT().GetMoreNamespaces(gomock.Any()).AnyTimes().Return(namespaces, nil)
 	storage.EXPECT().GetMoreServices(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(serviceMap, nil)
@@ -661,6 +664,7 @@ func Test_defaultAuthChecker_CheckPermission_Read_NoStrict(t *testing.T) {
 
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 	storage.EXPECT().GetStrategyDetailsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(strategies, nil)
 	storage.EXPECT().GetMoreNamespaces(gomock.Any()).AnyTimes().Return(namespaces, nil)
 	storage.EXPECT().GetMoreServices(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(serviceMap, nil)
@@ -878,6 +882,7 @@ func Test_defaultAuthChecker_CheckPermission_Read_Strict(t *testing.T) {
 
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 	storage.EXPECT().GetStrategyDetailsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(strategies, nil)
 	storage.EXPECT().GetMoreNamespaces(gomock.Any()).AnyTimes().Return(namespaces, nil)
 	storage.EXPECT().GetMoreServices(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(serviceMap, nil)
@@ -1090,6 +1095,7 @@ func Test_defaultAuthChecker_Initialize(t *testing.T) {
 	storage.EXPECT().GetUnixSecond(gomock.Any()).AnyTimes().Return(time.Now().Unix(), nil)
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return([]*model.UserGroupDetail{}, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cacheMgn, err := cache.TestCacheInitialize(ctx, &cache.Config{
@@ -1182,3 +1188,116 @@ func Test_defaultAuthChecker_Initialize(t *testing.T) {
 	})
 
 }
+
+func Test_defaultAuthChecker_CheckPermission_NamespaceDeny(t *testing.T) {
+	reset(false)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := createMockUser(10)
+	groups := createMockUserGroup(users)
+
+	namespaces := createMockNamespace(len(users)+len(groups)+10, users[0].ID)
+	services := createMockService(namespaces)
+	serviceMap := convertServiceSliceToMap(services)
+	strategies, _ := createMockStrategy(users, groups, services[:len(users)+len(groups)])
+	// users[1] 拥有 services[1] 的读写权限，但是被禁止修改 services[1] 所在的命名空间
+	strategies = append(strategies, &model.StrategyDetail{
+		ID:     "deny-namespace",
+		Name:   "deny-namespace",
+		Action: "MODIFY,DELETE",
+		Effect: model.StrategyEffectDeny,
+		Principals: []model.Principal{
+			{PrincipalID: users[1].ID, PrincipalRole: model.PrincipalUser},
+		},
+		Owner: users[0].ID,
+		Resources: []model.StrategyResource{
+			{
+				StrategyID: "deny-namespace",
+				ResType:    int32(apisecurity.ResourceType_Namespaces),
+				ResID:      services[1].Namespace,
+			},
+		},
+		Valid: true,
+	})
+
+	cfg, storage := initCache(ctrl)
+
+	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
+	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	storage.EXPECT().GetStrategyDetailsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(strategies, nil)
+	storage.EXPECT().GetMoreNamespaces(gomock.Any()).AnyTimes().Return(namespaces, nil)
+	storage.EXPECT().GetMoreServices(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(serviceMap, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cacheMgn, err := cache.TestCacheInitialize(ctx, cfg, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		cancel()
+		cacheMgn.Clear()
+		time.Sleep(2 * time.Second)
+	}()
+
+	time.Sleep(time.Second)
+
+	checker := &defaultAuthChecker{}
+	checker.cacheMgn = cacheMgn
+
+	newAuthCtx := func(user *model.User, op model.ResourceOperation, svc *model.Service) *model.AcquireContext {
+		ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, user.Token)
+		// 和修改服务一样，只携带服务本身的资源信息
+		return model.NewAcquireContext(
+			model.WithRequestContext(ctx),
+			model.WithMethod("Test_defaultAuthChecker_CheckPermission_NamespaceDeny"),
+			model.WithOperation(op),
+			model.WithModule(model.DiscoverModule),
+			model.WithAccessResources(map[apisecurity.ResourceType][]model.ResourceEntry{
+				apisecurity.ResourceType_Services: {
+					{
+						ID:        svc.ID,
+						Owner:     svc.Owner,
+						Namespace: svc.Namespace,
+					},
+				},
+			}),
+		)
+	}
+
+	t.Run("命名空间的拒绝策略对服务生效", func(t *testing.T) {
+		_, err := checker.CheckPermission(newAuthCtx(users[1], model.Modify, services[1]))
+		assert.Error(t, err)
+		_, err = checker.CheckPermission(newAuthCtx(users[1], model.Delete, services[1]))
+		assert.Error(t, err)
+	})
+
+	t.Run("命名空间的拒绝策略不影响未禁止的写操作", func(t *testing.T) {
+		_, err := checker.CheckPermission(newAuthCtx(users[1], model.Create, services[1]))
+		assert.NoError(t, err)
+	})
+
+	t.Run("命名空间的拒绝策略不影响其他用户", func(t *testing.T) {
+		_, err := checker.CheckPermission(newAuthCtx(users[2], model.Modify, services[2]))
+		assert.NoError(t, err)
+	})
+
+	t.Run("命名空间的拒绝策略对治理规则生效", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, users[1].Token)
+		authCtx := model.NewAcquireContext(
+			model.WithRequestContext(ctx),
+			model.WithMethod("Test_defaultAuthChecker_CheckPermission_NamespaceDeny"),
+			model.WithOperation(model.Modify),
+			model.WithModule(model.DiscoverModule),
+			model.WithRuleResources(map[model.RuleResourceType][]model.ResourceEntry{
+				model.ResourceTypeRouteRules: {
+					{ID: "rule-1", Name: "rule-1", Namespace: services[1].Namespace},
+				},
+			}),
+		)
+		_, err := checker.CheckPermission(authCtx)
+		assert.Error(t, err)
+	})
+}
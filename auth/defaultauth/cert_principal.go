@@ -0,0 +1,153 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/secure"
+)
+
+// CertPrincipalConfig mTLS 客户端证书映射为北极星用户/用户组的配置，对应 auth.user.option.mtls
+// 只有 API 服务开启了 tls.clientCertAuth 时，请求中才会携带经过 CA 校验的客户端证书
+type CertPrincipalConfig struct {
+	// Enable 是否开启客户端证书认证
+	Enable bool `mapstructure:"enable"`
+	// Rules 映射规则，按照顺序匹配，第一个命中的规则生效
+	Rules []*CertPrincipalRule `mapstructure:"rules"`
+}
+
+// CertPrincipalRule 客户端证书的映射规则，uri 与 commonName 同时设置时需要同时满足
+type CertPrincipalRule struct {
+	// URI 证书 SPIFFE ID（URI SAN）的匹配表达式，支持 path.Match 的通配语法
+	URI string `mapstructure:"uri"`
+	// CommonName 证书 Subject CN 的匹配表达式，支持 path.Match 的通配语法
+	CommonName string `mapstructure:"commonName"`
+	// User 映射的用户名称
+	User string `mapstructure:"user"`
+	// Owner 用户所属的主账户名称，映射为用户时必须设置
+	Owner string `mapstructure:"owner"`
+	// GroupID 映射的用户组ID
+	GroupID string `mapstructure:"groupId"`
+}
+
+// ParseCertPrincipalConfig 解析客户端证书映射配置，未开启时返回 nil
+func ParseCertPrincipalConfig(raw interface{}) (*CertPrincipalConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	cfg := &CertPrincipalConfig{}
+	if err := mapstructure.Decode(raw, cfg); err != nil {
+		return nil, err
+	}
+	if !cfg.Enable {
+		return nil, nil
+	}
+	if err := cfg.Verify(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Verify 检查客户端证书映射配置是否合法
+func (cfg *CertPrincipalConfig) Verify() error {
+	if len(cfg.Rules) == 0 {
+		return errors.New("[Auth][mTLS] rules must be set")
+	}
+	for i, rule := range cfg.Rules {
+		if rule.URI == "" && rule.CommonName == "" {
+			return fmt.Errorf("[Auth][mTLS] rule %d: uri or commonName must be set", i)
+		}
+		if _, err := path.Match(rule.URI, ""); err != nil {
+			return fmt.Errorf("[Auth][mTLS] rule %d: invalid uri pattern: %w", i, err)
+		}
+		if _, err := path.Match(rule.CommonName, ""); err != nil {
+			return fmt.Errorf("[Auth][mTLS] rule %d: invalid commonName pattern: %w", i, err)
+		}
+		if (rule.User == "") == (rule.GroupID == "") {
+			return fmt.Errorf("[Auth][mTLS] rule %d: one of user and groupId must be set", i)
+		}
+		if rule.User != "" && rule.Owner == "" {
+			return fmt.Errorf("[Auth][mTLS] rule %d: owner must be set for user", i)
+		}
+	}
+	return nil
+}
+
+// match 找到第一个匹配客户端证书的规则
+func (cfg *CertPrincipalConfig) match(cert *x509.Certificate) *CertPrincipalRule {
+	spiffeID := secure.SpiffeID(cert)
+	for _, rule := range cfg.Rules {
+		if rule.URI != "" {
+			if ok, _ := path.Match(rule.URI, spiffeID); !ok || spiffeID == "" {
+				continue
+			}
+		}
+		if rule.CommonName != "" {
+			if ok, _ := path.Match(rule.CommonName, cert.Subject.CommonName); !ok {
+				continue
+			}
+		}
+		return rule
+	}
+	return nil
+}
+
+// certIdentity 证书的身份描述，优先使用 SPIFFE ID
+func certIdentity(cert *x509.Certificate) string {
+	if spiffeID := secure.SpiffeID(cert); spiffeID != "" {
+		return spiffeID
+	}
+	return "CN=" + cert.Subject.CommonName
+}
+
+// decodeClientCert 根据映射规则，将经过 CA 校验的客户端证书转换为对应的用户/用户组
+func (d *defaultAuthChecker) decodeClientCert(cert *x509.Certificate) (OperatorInfo, error) {
+	cfg := AuthOption.CertPrincipal
+	if cfg == nil || cert == nil {
+		return OperatorInfo{}, model.ErrorTokenInvalid
+	}
+	rule := cfg.match(cert)
+	if rule == nil {
+		return OperatorInfo{}, fmt.Errorf("no principal rule match client cert %s", certIdentity(cert))
+	}
+
+	identity := certIdentity(cert)
+	operator := OperatorInfo{
+		Origin:       identity,
+		Role:         model.UnknownUserRole,
+		CertIdentity: identity,
+	}
+	if rule.GroupID != "" {
+		operator.OperatorID = rule.GroupID
+		return operator, nil
+	}
+	user := d.Cache().User().GetUserByName(rule.User, rule.Owner)
+	if user == nil {
+		return OperatorInfo{}, model.ErrorNoUser
+	}
+	operator.IsUserToken = true
+	operator.OperatorID = user.ID
+	return operator, nil
+}
@@ -0,0 +1,168 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/cache"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	storemock "github.com/polarismesh/polaris/store/mock"
+)
+
+func newTestClientCert(cn string, uri string) *x509.Certificate {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: cn}}
+	if uri != "" {
+		u, _ := url.Parse(uri)
+		cert.URIs = []*url.URL{u}
+	}
+	return cert
+}
+
+func Test_ParseCertPrincipalConfig(t *testing.T) {
+	cfg, err := ParseCertPrincipalConfig(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	cfg, err = ParseCertPrincipalConfig(map[interface{}]interface{}{"enable": false})
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	_, err = ParseCertPrincipalConfig(map[interface{}]interface{}{"enable": true})
+	assert.Error(t, err)
+
+	_, err = ParseCertPrincipalConfig(map[interface{}]interface{}{
+		"enable": true,
+		"rules": []interface{}{
+			map[interface{}]interface{}{"uri": "spiffe://cluster.local/*", "user": "sdk"},
+		},
+	})
+	assert.Error(t, err, "owner must be set for user")
+
+	_, err = ParseCertPrincipalConfig(map[interface{}]interface{}{
+		"enable": true,
+		"rules": []interface{}{
+			map[interface{}]interface{}{"uri": "spiffe://cluster.local/[", "groupId": "g"},
+		},
+	})
+	assert.Error(t, err, "invalid pattern")
+
+	cfg, err = ParseCertPrincipalConfig(map[interface{}]interface{}{
+		"enable": true,
+		"rules": []interface{}{
+			map[interface{}]interface{}{"uri": "spiffe://cluster.local/ns/*/sa/sdk", "user": "sdk", "owner": "polaris"},
+			map[interface{}]interface{}{"commonName": "*.polaris.svc", "groupId": "group-1"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Rules, 2)
+
+	assert.Equal(t, cfg.Rules[0], cfg.match(newTestClientCert("", "spiffe://cluster.local/ns/default/sa/sdk")))
+	assert.Equal(t, cfg.Rules[1], cfg.match(newTestClientCert("app.polaris.svc", "")))
+	assert.Nil(t, cfg.match(newTestClientCert("app.example.com", "spiffe://cluster.local/ns/default/sa/app")))
+}
+
+func Test_defaultAuthChecker_VerifyClientCert(t *testing.T) {
+	reset(true)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := createMockUser(2)
+	groups := createMockUserGroup(users)
+
+	storage := storemock.NewMockStore(ctrl)
+	storage.EXPECT().GetServicesCount().AnyTimes().Return(uint32(1), nil)
+	storage.EXPECT().GetUnixSecond(gomock.Any()).AnyTimes().Return(time.Now().Unix(), nil)
+	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
+	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cacheMgn, err := cache.TestCacheInitialize(ctx, &cache.Config{
+		Open: true,
+		Resources: []cache.ConfigEntry{
+			{
+				Name: "users",
+			},
+		},
+	}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cancel()
+		cacheMgn.Clear()
+	}()
+
+	AuthOption.CertPrincipal = &CertPrincipalConfig{
+		Enable: true,
+		Rules: []*CertPrincipalRule{
+			{URI: "spiffe://cluster.local/ns/*/sa/sdk", User: users[1].Name, Owner: users[0].Name},
+			{CommonName: "*.polaris.svc", GroupID: groups[0].ID},
+		},
+	}
+	checker := &defaultAuthChecker{cacheMgn: cacheMgn}
+	newAuthCtx := func(token string, cert *x509.Certificate) *model.AcquireContext {
+		ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, token)
+		ctx = context.WithValue(ctx, utils.ContextClientCertKey, cert)
+		return model.NewAcquireContext(
+			model.WithRequestContext(ctx),
+			model.WithModule(model.DiscoverModule),
+		)
+	}
+
+	t.Run("证书映射为用户", func(t *testing.T) {
+		authCtx := newAuthCtx("", newTestClientCert("", "spiffe://cluster.local/ns/default/sa/sdk"))
+		assert.NoError(t, checker.VerifyCredential(authCtx))
+		operator := authCtx.GetAttachment(model.TokenDetailInfoKey).(OperatorInfo)
+		assert.True(t, operator.IsUserToken)
+		assert.Equal(t, users[1].ID, operator.OperatorID)
+		assert.Equal(t, "spiffe://cluster.local/ns/default/sa/sdk", operator.CertIdentity)
+		assert.Equal(t, users[0].ID, utils.ParseOwnerID(authCtx.GetRequestContext()))
+	})
+
+	t.Run("证书映射为用户组", func(t *testing.T) {
+		authCtx := newAuthCtx("", newTestClientCert("app.polaris.svc", ""))
+		assert.NoError(t, checker.VerifyCredential(authCtx))
+		operator := authCtx.GetAttachment(model.TokenDetailInfoKey).(OperatorInfo)
+		assert.False(t, operator.IsUserToken)
+		assert.Equal(t, groups[0].ID, operator.OperatorID)
+	})
+
+	t.Run("请求携带token时以token为准", func(t *testing.T) {
+		authCtx := newAuthCtx(users[0].Token, newTestClientCert("app.polaris.svc", ""))
+		assert.NoError(t, checker.VerifyCredential(authCtx))
+		operator := authCtx.GetAttachment(model.TokenDetailInfoKey).(OperatorInfo)
+		assert.Equal(t, users[0].ID, operator.OperatorID)
+		assert.Empty(t, operator.CertIdentity)
+	})
+
+	t.Run("证书没有命中任何规则", func(t *testing.T) {
+		authCtx := newAuthCtx("", newTestClientCert("app.example.com", ""))
+		assert.ErrorIs(t, checker.VerifyCredential(authCtx), model.ErrorTokenInvalid)
+	})
+}
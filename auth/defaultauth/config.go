@@ -22,6 +22,12 @@ import "errors"
 // AuthOption 鉴权的配置信息
 var AuthOption = DefaultAuthConfig()
 
+// defaultSalt 默认的 salt，该值是公开的，使用默认 salt 时不允许签发临时 token
+const defaultSalt = "polarismesh@2021"
+
+// minScopedTokenSecretLen 临时 token 签名密钥的最小长度
+const minScopedTokenSecretLen = 32
+
 // AuthConfig 鉴权配置
 type AuthConfig struct {
 	// ConsoleOpen 控制台是否开启鉴权
@@ -32,6 +38,16 @@ type AuthConfig struct {
 	Salt string `json:"salt" xml:"salt"`
 	// Strict 是否启用鉴权的严格模式，即对于没有任何鉴权策略的资源，也必须带上正确的token才能操作, 默认关闭
 	Strict bool `json:"strict"`
+	// OIDC 单点登录配置，对应 auth.user.option.oidc，单独解析
+	OIDC *OIDCConfig `json:"-"`
+	// LDAP 用户源配置，对应 auth.user.option.ldap，单独解析
+	LDAP *LDAPConfig `json:"-"`
+	// CertPrincipal mTLS 客户端证书映射配置，对应 auth.user.option.mtls，单独解析
+	CertPrincipal *CertPrincipalConfig `json:"-"`
+	// ScopedTokenMaxTTL 临时 token 允许的最长有效期，单位秒，未设置时为 24 小时
+	ScopedTokenMaxTTL int64 `json:"scopedTokenMaxTTL"`
+	// ScopedTokenSecret 临时 token 的 HMAC 签名密钥，与 salt 分开配置，未设置时不允许签发以及使用临时 token
+	ScopedTokenSecret string `json:"scopedTokenSecret"`
 }
 
 // Verify 检查配置是否合法
@@ -43,6 +59,17 @@ func (cfg *AuthConfig) Verify() error {
 	default:
 		return errors.New("[Auth][Config] salt len must 16 | 24 | 32")
 	}
+	if cfg.ScopedTokenMaxTTL < 0 {
+		return errors.New("[Auth][Config] scopedTokenMaxTTL must not be negative")
+	}
+	if cfg.ScopedTokenSecret != "" {
+		if len(cfg.ScopedTokenSecret) < minScopedTokenSecretLen {
+			return errors.New("[Auth][Config] scopedTokenSecret len must not be less than 32")
+		}
+		if cfg.ScopedTokenSecret == cfg.Salt {
+			return errors.New("[Auth][Config] scopedTokenSecret must be different from salt")
+		}
+	}
 
 	return nil
 }
@@ -54,7 +81,7 @@ func DefaultAuthConfig() *AuthConfig {
 		ConsoleOpen: true,
 		// 针对客户端接口，默认不开启鉴权操作
 		ClientOpen: false,
-		Salt:       "polarismesh@2021",
+		Salt:       defaultSalt,
 		// 这里默认开启强 Token 检查模式
 		Strict: true,
 	}
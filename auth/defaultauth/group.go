@@ -127,6 +127,9 @@ func (svr *server) UpdateGroup(ctx context.Context, req *apisecurity.ModifyUserG
 	if errResp != nil {
 		return errResp
 	}
+	if err := svr.checkLDAPGroupRelation(data.UserGroup, req); err != nil {
+		return api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, err.Error())
+	}
 
 	modifyReq, needUpdate := updateGroupAttribute(ctx, data.UserGroup, req)
 	if !needUpdate {
@@ -554,6 +557,19 @@ func updateGroupAttribute(ctx context.Context, old *model.UserGroup, newUser *ap
 	return ret, needUpdate
 }
 
+// modifyGroupMembers 只变更用户组的成员关系，其余字段保持不变
+func modifyGroupMembers(group *model.UserGroup, addIds, removeIds []string) *model.ModifyUserGroup {
+	return &model.ModifyUserGroup{
+		ID:            group.ID,
+		Owner:         group.Owner,
+		Token:         group.Token,
+		TokenEnable:   group.TokenEnable,
+		Comment:       group.Comment,
+		AddUserIds:    addIds,
+		RemoveUserIds: removeIds,
+	}
+}
+
 // enhancedGroups2Api 数组专为 []*apisecurity.UserGroup
 func enhancedGroups2Api(groups []*model.UserGroup, handler UserGroup2Api) []*apisecurity.UserGroup {
 	out := make([]*apisecurity.UserGroup, 0, len(groups))
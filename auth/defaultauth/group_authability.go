@@ -96,6 +96,9 @@ func (svr *groupAuthAbility) GetGroupToken(ctx context.Context, req *apisecurity
 	if rsp != nil {
 		return rsp
 	}
+	if rsp := rejectScopedToken(ctx); rsp != nil {
+		return rsp
+	}
 
 	return svr.target.GetGroupToken(ctx, req)
 }
@@ -75,6 +75,7 @@ func newGroupTest(t *testing.T) *GroupTest {
 	storage.EXPECT().UpdateUser(gomock.Any()).AnyTimes().Return(nil)
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(append(users, newUsers...), nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(allGroups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 
 	cfg := &cache.Config{
 		Open: true,
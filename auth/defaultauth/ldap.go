@@ -0,0 +1,619 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// LDAPUserSource 从 LDAP 同步的用户来源
+const LDAPUserSource = "LDAP"
+
+var (
+	// ErrorLDAPSyncedField LDAP 同步的字段不允许在北极星中修改
+	ErrorLDAPSyncedField = errors.New("field synced from ldap can not be modified")
+)
+
+// LDAPConfig LDAP 用户源配置，对应 auth.user.option.ldap
+type LDAPConfig struct {
+	// Enable 是否开启 LDAP 用户源
+	Enable bool `mapstructure:"enable"`
+	// URL LDAP 服务地址，如 ldap://127.0.0.1:389 或者 ldaps://127.0.0.1:636
+	URL string `mapstructure:"url"`
+	// StartTLS 对 ldap:// 连接是否使用 StartTLS 升级
+	StartTLS bool `mapstructure:"startTLS"`
+	// InsecureSkipVerify 是否跳过服务端证书校验
+	InsecureSkipVerify bool `mapstructure:"insecureSkipVerify"`
+	// BindDN 用于查询目录的服务账号
+	BindDN string `mapstructure:"bindDN"`
+	// BindPassword 服务账号的密码
+	BindPassword string `mapstructure:"bindPassword"`
+	// UserBaseDN 用户的查询起点
+	UserBaseDN string `mapstructure:"userBaseDN"`
+	// UserFilter 用户的过滤条件，默认为 (objectClass=person)
+	UserFilter string `mapstructure:"userFilter"`
+	// UsernameAttribute 映射为北极星用户名的属性，默认为 uid
+	UsernameAttribute string `mapstructure:"usernameAttribute"`
+	// EmailAttribute 映射为用户邮箱的属性，默认为 mail
+	EmailAttribute string `mapstructure:"emailAttribute"`
+	// MobileAttribute 映射为用户手机号的属性，默认为 mobile
+	MobileAttribute string `mapstructure:"mobileAttribute"`
+	// GroupBaseDN 用户组的查询起点
+	GroupBaseDN string `mapstructure:"groupBaseDN"`
+	// GroupFilter 用户组的过滤条件，默认为 (objectClass=groupOfNames)
+	GroupFilter string `mapstructure:"groupFilter"`
+	// GroupNameAttribute 映射为北极星用户组名称的属性，默认为 cn
+	GroupNameAttribute string `mapstructure:"groupNameAttribute"`
+	// GroupMemberAttribute 用户组成员属性，默认为 member，值可以是用户的 DN 或者用户名（memberUid）
+	GroupMemberAttribute string `mapstructure:"groupMemberAttribute"`
+	// Groups 需要同步的用户组名称，只有这些用户组中的成员才会被同步为北极星用户
+	Groups []string `mapstructure:"groups"`
+	// Owner 同步的用户以及用户组所属的主账户名称
+	Owner string `mapstructure:"owner"`
+	// SyncInterval 同步周期，默认为 5m
+	SyncInterval time.Duration `mapstructure:"syncInterval"`
+	// Timeout 单次 LDAP 请求的超时时间，默认为 10s
+	Timeout time.Duration `mapstructure:"timeout"`
+	// PageSize 分页查询的大小，默认为 500，设置为 0 表示不分页
+	PageSize uint32 `mapstructure:"pageSize"`
+}
+
+// ParseLDAPConfig 解析 LDAP 配置，未配置时返回 nil
+func ParseLDAPConfig(raw interface{}) (*LDAPConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	cfg := &LDAPConfig{
+		UserFilter:           "(objectClass=person)",
+		UsernameAttribute:    "uid",
+		EmailAttribute:       "mail",
+		MobileAttribute:      "mobile",
+		GroupFilter:          "(objectClass=groupOfNames)",
+		GroupNameAttribute:   "cn",
+		GroupMemberAttribute: "member",
+		SyncInterval:         5 * time.Minute,
+		Timeout:              10 * time.Second,
+		PageSize:             500,
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     cfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return nil, err
+	}
+	if !cfg.Enable {
+		return nil, nil
+	}
+	if err := cfg.Verify(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Verify 检查 LDAP 配置是否合法
+func (cfg *LDAPConfig) Verify() error {
+	if cfg.URL == "" || cfg.UserBaseDN == "" || cfg.GroupBaseDN == "" {
+		return errors.New("[Auth][LDAP] url, userBaseDN and groupBaseDN must be set")
+	}
+	if cfg.Owner == "" || len(cfg.Groups) == 0 {
+		return errors.New("[Auth][LDAP] owner and groups must be set")
+	}
+	if _, err := compileLDAPFilter(cfg.UserFilter); err != nil {
+		return err
+	}
+	if _, err := compileLDAPFilter(cfg.GroupFilter); err != nil {
+		return err
+	}
+	if cfg.SyncInterval <= 0 || cfg.Timeout <= 0 {
+		return errors.New("[Auth][LDAP] syncInterval and timeout must be positive")
+	}
+	return nil
+}
+
+// IsSyncedGroup 用户组是否由 LDAP 同步
+func (cfg *LDAPConfig) IsSyncedGroup(name string) bool {
+	for _, item := range cfg.Groups {
+		if item == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ldapUser 目录中的用户
+type ldapUser struct {
+	DN     string
+	Name   string
+	Email  string
+	Mobile string
+}
+
+// ldapGroup 目录中的用户组，Members 为成员的用户名
+type ldapGroup struct {
+	Name    string
+	Found   bool
+	Members []string
+}
+
+// ldapSnapshot 一次同步从目录中拉取到的数据，Users 只包含同步用户组中的成员
+type ldapSnapshot struct {
+	Users  map[string]*ldapUser
+	Groups []*ldapGroup
+}
+
+// ldapDirectory 目录的访问能力，便于测试时替换
+type ldapDirectory interface {
+	// Authenticate 使用用户名、密码在目录中进行认证
+	Authenticate(username, password string) error
+	// Fetch 拉取需要同步的用户以及用户组
+	Fetch() (*ldapSnapshot, error)
+}
+
+type ldapClient struct {
+	cfg *LDAPConfig
+}
+
+func (c *ldapClient) connect() (*ldapConn, error) {
+	conn, err := dialLDAP(c.cfg.URL, c.cfg.StartTLS,
+		&tls.Config{InsecureSkipVerify: c.cfg.InsecureSkipVerify}, c.cfg.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if c.cfg.BindDN != "" {
+		if err := conn.Bind(c.cfg.BindDN, c.cfg.BindPassword); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("ldap bind service account: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// Authenticate 先使用服务账号找到用户的 DN，再使用用户的 DN 以及密码进行绑定
+func (c *ldapClient) Authenticate(username, password string) error {
+	if password == "" {
+		return errLDAPInvalidCredentials
+	}
+	conn, err := c.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	entries, err := conn.Search(&ldapSearchRequest{
+		BaseDN: c.cfg.UserBaseDN,
+		Scope:  ldapScopeWholeSubtree,
+		Filter: fmt.Sprintf("(&%s(%s=%s))", wrapLDAPFilter(c.cfg.UserFilter), c.cfg.UsernameAttribute,
+			escapeLDAPFilter(username)),
+		Attributes: []string{c.cfg.UsernameAttribute},
+	})
+	if err != nil {
+		return err
+	}
+	if len(entries) != 1 {
+		return errLDAPInvalidCredentials
+	}
+	return conn.Bind(entries[0].DN, password)
+}
+
+// Fetch 拉取全部用户后，按照同步的用户组进行过滤
+func (c *ldapClient) Fetch() (*ldapSnapshot, error) {
+	conn, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	userEntries, err := conn.Search(&ldapSearchRequest{
+		BaseDN: c.cfg.UserBaseDN,
+		Scope:  ldapScopeWholeSubtree,
+		Filter: c.cfg.UserFilter,
+		Attributes: []string{
+			c.cfg.UsernameAttribute, c.cfg.EmailAttribute, c.cfg.MobileAttribute,
+		},
+		PageSize: c.cfg.PageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	byDN := make(map[string]*ldapUser, len(userEntries))
+	byName := make(map[string]*ldapUser, len(userEntries))
+	for _, entry := range userEntries {
+		user := &ldapUser{
+			DN:     entry.DN,
+			Name:   entry.Attr(c.cfg.UsernameAttribute),
+			Email:  entry.Attr(c.cfg.EmailAttribute),
+			Mobile: entry.Attr(c.cfg.MobileAttribute),
+		}
+		if user.Name == "" {
+			continue
+		}
+		byDN[normalizeDN(entry.DN)] = user
+		byName[user.Name] = user
+	}
+
+	snapshot := &ldapSnapshot{Users: map[string]*ldapUser{}}
+	for _, name := range c.cfg.Groups {
+		entries, err := conn.Search(&ldapSearchRequest{
+			BaseDN: c.cfg.GroupBaseDN,
+			Scope:  ldapScopeWholeSubtree,
+			Filter: fmt.Sprintf("(&%s(%s=%s))", wrapLDAPFilter(c.cfg.GroupFilter), c.cfg.GroupNameAttribute,
+				escapeLDAPFilter(name)),
+			Attributes: []string{c.cfg.GroupNameAttribute, c.cfg.GroupMemberAttribute},
+			PageSize:   c.cfg.PageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		group := &ldapGroup{Name: name, Found: len(entries) > 0}
+		for _, entry := range entries {
+			for _, member := range entry.Attrs(c.cfg.GroupMemberAttribute) {
+				user, ok := byDN[normalizeDN(member)]
+				if !ok {
+					user, ok = byName[member]
+				}
+				if !ok {
+					continue
+				}
+				group.Members = append(group.Members, user.Name)
+				snapshot.Users[user.Name] = user
+			}
+		}
+		snapshot.Groups = append(snapshot.Groups, group)
+	}
+	return snapshot, nil
+}
+
+func wrapLDAPFilter(filter string) string {
+	filter = strings.TrimSpace(filter)
+	if strings.HasPrefix(filter, "(") {
+		return filter
+	}
+	return "(" + filter + ")"
+}
+
+// normalizeDN DN 中的属性名称以及值大小写不敏感，逗号两侧允许空格
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i := range parts {
+		parts[i] = strings.ToLower(strings.TrimSpace(parts[i]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// ldapSource LDAP 用户源，负责登录认证以及定期同步
+type ldapSource struct {
+	cfg       *LDAPConfig
+	directory ldapDirectory
+}
+
+func newLDAPSource(cfg *LDAPConfig) *ldapSource {
+	return &ldapSource{cfg: cfg, directory: &ldapClient{cfg: cfg}}
+}
+
+// isLDAPUser 用户是否由 LDAP 同步
+func (svr *server) isLDAPUser(user *model.User) bool {
+	return user != nil && user.Source == LDAPUserSource
+}
+
+// authenticateLDAPUser LDAP 用户通过目录进行认证，关闭 LDAP 之后这些用户无法登录
+func (svr *server) authenticateLDAPUser(user *model.User, password string) error {
+	if svr.ldap == nil {
+		return errLDAPInvalidCredentials
+	}
+	if err := svr.ldap.directory.Authenticate(user.Name, password); err != nil {
+		if !errors.Is(err, errLDAPInvalidCredentials) {
+			log.Error("[Auth][LDAP] authenticate user", zap.String("name", user.Name), zap.Error(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// checkLDAPGroupRelation LDAP 用户在同步的用户组中的成员关系由 LDAP 管理，不允许手动调整
+func (svr *server) checkLDAPGroupRelation(group *model.UserGroup, req *apisecurity.ModifyUserGroup) error {
+	if svr.ldap == nil || !svr.ldap.cfg.IsSyncedGroup(group.Name) {
+		return nil
+	}
+	owner := svr.cacheMgn.User().GetUserByName(svr.ldap.cfg.Owner, svr.ldap.cfg.Owner)
+	if owner == nil || owner.ID != group.Owner {
+		return nil
+	}
+	users := append(append([]*apisecurity.User{}, req.GetAddRelations().GetUsers()...),
+		req.GetRemoveRelations().GetUsers()...)
+	for _, item := range users {
+		if svr.isLDAPUser(svr.cacheMgn.User().GetUserByID(item.GetId().GetValue())) {
+			return ErrorLDAPSyncedField
+		}
+	}
+	return nil
+}
+
+// runLDAPSync 定期执行 LDAP 同步，多个节点之间通过选主保证只有一个节点执行
+func (svr *server) runLDAPSync(ctx context.Context) {
+	if err := svr.storage.StartLeaderElection(store.ElectionKeyAuthLDAPSync); err != nil {
+		log.Error("[Auth][LDAP] start leader election", zap.Error(err))
+		return
+	}
+	ticker := time.NewTicker(svr.ldap.cfg.SyncInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !svr.storage.IsLeader(store.ElectionKeyAuthLDAPSync) {
+					continue
+				}
+				if err := svr.syncLDAP(ctx); err != nil {
+					log.Error("[Auth][LDAP] sync users and groups", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// syncLDAP 执行一次同步
+//
+//  1. 同步用户组中的成员创建为 owner 下的子账户，并更新邮箱、手机号
+//  2. 删除已经不在同步用户组中的 LDAP 用户
+//  3. 同步用户组，只调整 LDAP 用户的成员关系，手动加入的本地用户保持不变
+func (svr *server) syncLDAP(ctx context.Context) error {
+	cfg := svr.ldap.cfg
+	owner, err := svr.storage.GetUserByName(cfg.Owner, "")
+	if err != nil {
+		return err
+	}
+	if owner == nil {
+		return fmt.Errorf("owner %s not found", cfg.Owner)
+	}
+	snapshot, err := svr.ldap.directory.Fetch()
+	if err != nil {
+		return err
+	}
+	// 所有的用户组都没有找到时，大概率是配置或者目录出现问题，避免误删用户
+	found := false
+	for _, group := range snapshot.Groups {
+		found = found || group.Found
+	}
+	if !found {
+		return errors.New("none of the configured groups found in ldap")
+	}
+
+	locals, err := svr.listLDAPUsers(owner)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, utils.StringContext("operator"), "ldap-sync")
+	users := svr.syncLDAPUsers(ctx, owner, snapshot, locals)
+	// 同步前已经存在的 LDAP 用户（包括本次删除的）也需要参与用户组成员的计算
+	ldapUserIds := make(map[string]struct{}, len(users)+len(locals))
+	for _, user := range locals {
+		ldapUserIds[user.ID] = struct{}{}
+	}
+	for _, user := range users {
+		ldapUserIds[user.ID] = struct{}{}
+	}
+	return svr.syncLDAPGroups(ctx, owner, snapshot, users, ldapUserIds)
+}
+
+// syncLDAPUsers 同步用户，返回同步之后用户名到 LDAP 用户的映射，单个用户同步失败不影响其他用户
+func (svr *server) syncLDAPUsers(ctx context.Context, owner *model.User, snapshot *ldapSnapshot,
+	locals map[string]*model.User) map[string]*model.User {
+	synced := make(map[string]*model.User, len(snapshot.Users))
+	for name, item := range snapshot.Users {
+		user, ok := locals[name]
+		if !ok {
+			var err error
+			if user, err = svr.createLDAPUser(ctx, owner, item); err != nil {
+				log.Error("[Auth][LDAP] create user", zap.String("name", name), zap.Error(err))
+				continue
+			}
+			if user != nil {
+				synced[name] = user
+			}
+			continue
+		}
+		synced[name] = user
+		if user.Email == item.Email && user.Mobile == item.Mobile {
+			continue
+		}
+		user.Email = item.Email
+		user.Mobile = item.Mobile
+		if err := svr.storage.UpdateUser(user); err != nil {
+			log.Error("[Auth][LDAP] update user", zap.String("name", name), zap.Error(err))
+			continue
+		}
+		log.Info("[Auth][LDAP] update user", zap.String("name", name))
+	}
+
+	for name, user := range locals {
+		if _, ok := snapshot.Users[name]; ok {
+			continue
+		}
+		if err := svr.storage.DeleteUser(user); err != nil {
+			log.Error("[Auth][LDAP] delete user", zap.String("name", name), zap.Error(err))
+			continue
+		}
+		log.Info("[Auth][LDAP] delete user which left the directory", zap.String("name", name))
+		svr.RecordHistory(userRecordEntry(ctx, &apisecurity.User{
+			Id:   utils.NewStringValue(user.ID),
+			Name: utils.NewStringValue(user.Name),
+		}, user, model.ODelete))
+	}
+	return synced
+}
+
+func (svr *server) listLDAPUsers(owner *model.User) (map[string]*model.User, error) {
+	const pageSize = 1000
+	users := map[string]*model.User{}
+	for offset := uint32(0); ; offset += pageSize {
+		_, items, err := svr.storage.GetUsers(map[string]string{
+			"owner":  owner.ID,
+			"source": LDAPUserSource,
+		}, offset, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.ID != owner.ID {
+				users[item.Name] = item
+			}
+		}
+		if len(items) < pageSize {
+			return users, nil
+		}
+	}
+}
+
+// createLDAPUser 创建 LDAP 用户，已经存在同名的本地用户时跳过，不会接管本地用户
+func (svr *server) createLDAPUser(ctx context.Context, owner *model.User, item *ldapUser) (*model.User, error) {
+	if err := checkName(utils.NewStringValue(item.Name)); err != nil || item.Name == owner.Name {
+		log.Warn("[Auth][LDAP] invalid username, skip", zap.String("name", item.Name))
+		return nil, nil
+	}
+	exist, err := svr.storage.GetUserByName(item.Name, owner.ID)
+	if err != nil {
+		return nil, err
+	}
+	if exist != nil {
+		log.Warn("[Auth][LDAP] local user with same name existed, skip", zap.String("name", item.Name))
+		return nil, nil
+	}
+
+	// LDAP 用户通过目录认证，随机生成一个密码占位
+	password, err := randomURLString(24)
+	if err != nil {
+		return nil, err
+	}
+	req := &apisecurity.User{
+		Name:     utils.NewStringValue(item.Name),
+		Password: utils.NewStringValue(password),
+		Owner:    utils.NewStringValue(owner.ID),
+		Source:   utils.NewStringValue(LDAPUserSource),
+		Email:    utils.NewStringValue(item.Email),
+		Mobile:   utils.NewStringValue(item.Mobile),
+		Comment:  utils.NewStringValue(item.DN),
+	}
+	user, err := createUserModel(req, model.OwnerUserRole)
+	if err != nil {
+		return nil, err
+	}
+	if err := svr.storage.AddUser(user); err != nil {
+		return nil, err
+	}
+	log.Info("[Auth][LDAP] create user", zap.String("name", user.Name))
+	req.Password = nil
+	svr.RecordHistory(userRecordEntry(ctx, req, user, model.OCreate))
+	return user, nil
+}
+
+// syncLDAPGroups 同步用户组，用户组不存在时自动创建
+func (svr *server) syncLDAPGroups(ctx context.Context, owner *model.User, snapshot *ldapSnapshot,
+	users map[string]*model.User, ldapUserIds map[string]struct{}) error {
+	for _, item := range snapshot.Groups {
+		expect := make(map[string]struct{}, len(item.Members))
+		for _, name := range item.Members {
+			if user, ok := users[name]; ok {
+				expect[user.ID] = struct{}{}
+			}
+		}
+
+		group, err := svr.storage.GetGroupByName(item.Name, owner.ID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			if !item.Found {
+				continue
+			}
+			if err := svr.createLDAPGroup(ctx, owner, item.Name, expect); err != nil {
+				log.Error("[Auth][LDAP] create group", zap.String("group", item.Name), zap.Error(err))
+			}
+			continue
+		}
+
+		detail, err := svr.storage.GetGroup(group.ID)
+		if err != nil {
+			return err
+		}
+		if detail == nil {
+			continue
+		}
+		var addIds, removeIds []string
+		for id := range expect {
+			if _, ok := detail.UserIds[id]; !ok {
+				addIds = append(addIds, id)
+			}
+		}
+		for id := range detail.UserIds {
+			_, isLDAP := ldapUserIds[id]
+			if _, ok := expect[id]; isLDAP && !ok {
+				removeIds = append(removeIds, id)
+			}
+		}
+		if len(addIds) == 0 && len(removeIds) == 0 {
+			continue
+		}
+		if err := svr.storage.UpdateGroup(modifyGroupMembers(detail.UserGroup, addIds, removeIds)); err != nil {
+			log.Error("[Auth][LDAP] update group", zap.String("group", item.Name), zap.Error(err))
+			continue
+		}
+		log.Info("[Auth][LDAP] update group members", zap.String("group", item.Name),
+			zap.Strings("add", addIds), zap.Strings("remove", removeIds))
+	}
+	return nil
+}
+
+func (svr *server) createLDAPGroup(ctx context.Context, owner *model.User, name string,
+	members map[string]struct{}) error {
+	req := &apisecurity.UserGroup{
+		Name:    utils.NewStringValue(name),
+		Owner:   utils.NewStringValue(owner.ID),
+		Comment: utils.NewStringValue("synced from ldap"),
+	}
+	data, err := createGroupModel(req)
+	if err != nil {
+		return err
+	}
+	data.UserIds = members
+	if err := svr.storage.AddGroup(data); err != nil {
+		return err
+	}
+	log.Info("[Auth][LDAP] create group", zap.String("group", name))
+	svr.RecordHistory(userGroupRecordEntry(ctx, req, data.UserGroup, model.OCreate))
+	return nil
+}
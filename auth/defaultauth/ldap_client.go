@@ -0,0 +1,659 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 这里只实现了同步用户以及登录认证所需要的 LDAPv3 协议子集（RFC 4511）：Bind、Search（支持分页控制）、StartTLS 以及 Unbind
+
+const (
+	berClassApplication = 0x40
+	berClassContext     = 0x80
+	berConstructed      = 0x20
+
+	berTagBoolean     = 0x01
+	berTagInteger     = 0x02
+	berTagOctetString = 0x04
+	berTagEnumerated  = 0x0a
+	berTagSequence    = 0x30
+	berTagSet         = 0x31
+
+	ldapTagBindRequest      = berClassApplication | berConstructed | 0
+	ldapTagBindResponse     = berClassApplication | berConstructed | 1
+	ldapTagUnbindRequest    = berClassApplication | 2
+	ldapTagSearchRequest    = berClassApplication | berConstructed | 3
+	ldapTagSearchEntry      = berClassApplication | berConstructed | 4
+	ldapTagSearchDone       = berClassApplication | berConstructed | 5
+	ldapTagExtendedRequest  = berClassApplication | berConstructed | 23
+	ldapTagExtendedResponse = berClassApplication | berConstructed | 24
+	ldapTagControls         = berClassContext | berConstructed | 0
+
+	ldapResultSuccess            = 0
+	ldapResultInvalidCredentials = 49
+
+	ldapScopeBaseObject   = 0
+	ldapScopeWholeSubtree = 2
+
+	ldapOIDStartTLS     = "1.3.6.1.4.1.1466.20037"
+	ldapOIDPagedResults = "1.2.840.113556.1.4.319"
+
+	// ldapMaxPacketSize 单个 LDAP 消息的最大长度，避免异常数据导致内存暴涨
+	ldapMaxPacketSize = 16 << 20
+	// berMaxDepth BER 元素的最大嵌套层数，避免恶意构造的深层嵌套导致栈溢出
+	berMaxDepth = 64
+)
+
+var (
+	// errLDAPInvalidCredentials LDAP 绑定时用户名或者密码错误
+	errLDAPInvalidCredentials = errors.New("ldap invalid credentials")
+)
+
+// berElement BER 编码的元素，只支持单字节的 tag 以及定长编码
+type berElement struct {
+	tag      byte
+	value    []byte
+	children []*berElement
+}
+
+func (e *berElement) isConstructed() bool {
+	return e.tag&berConstructed != 0
+}
+
+func (e *berElement) child(i int) *berElement {
+	if i < 0 || i >= len(e.children) {
+		return &berElement{}
+	}
+	return e.children[i]
+}
+
+func (e *berElement) str() string {
+	return string(e.value)
+}
+
+func (e *berElement) int() int64 {
+	var v int64
+	for i, b := range e.value {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+func berEncode(tag byte, content []byte) []byte {
+	out := []byte{tag}
+	n := len(content)
+	switch {
+	case n < 0x80:
+		out = append(out, byte(n))
+	default:
+		var size []byte
+		for l := n; l > 0; l >>= 8 {
+			size = append([]byte{byte(l)}, size...)
+		}
+		out = append(out, 0x80|byte(len(size)))
+		out = append(out, size...)
+	}
+	return append(out, content...)
+}
+
+func berString(tag byte, s string) []byte {
+	return berEncode(tag, []byte(s))
+}
+
+func berInt(tag byte, v int64) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(v)}, content...)
+		// 当剩余的高位全部为符号位时结束
+		if (v < 0x80 && v >= -0x80) || len(content) == 8 {
+			break
+		}
+		v >>= 8
+	}
+	return berEncode(tag, content)
+}
+
+func berBool(v bool) []byte {
+	if v {
+		return berEncode(berTagBoolean, []byte{0xff})
+	}
+	return berEncode(berTagBoolean, []byte{0x00})
+}
+
+func berSeq(tag byte, items ...[]byte) []byte {
+	var content []byte
+	for _, item := range items {
+		content = append(content, item...)
+	}
+	return berEncode(tag, content)
+}
+
+// berDecode 解析一段完整的 BER 数据，返回解析的元素以及剩余的数据
+func berDecode(data []byte) (*berElement, []byte, error) {
+	return berDecodeDepth(data, 0)
+}
+
+func berDecodeDepth(data []byte, depth int) (*berElement, []byte, error) {
+	if depth >= berMaxDepth {
+		return nil, nil, errors.New("ber nesting too deep")
+	}
+	if len(data) < 2 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	tag := data[0]
+	if tag&0x1f == 0x1f {
+		return nil, nil, errors.New("ber multi-byte tag not supported")
+	}
+	length, offset := int(data[1]), 2
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 {
+			return nil, nil, errors.New("ber indefinite length not supported")
+		}
+		if size > 4 || len(data) < 2+size {
+			return nil, nil, errors.New("ber invalid length")
+		}
+		length = 0
+		for _, b := range data[2 : 2+size] {
+			length = length<<8 | int(b)
+		}
+		offset += size
+	}
+	if length > len(data)-offset {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	elem := &berElement{tag: tag, value: data[offset : offset+length]}
+	if elem.isConstructed() {
+		rest := elem.value
+		for len(rest) > 0 {
+			var (
+				child *berElement
+				err   error
+			)
+			if child, rest, err = berDecodeDepth(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			elem.children = append(elem.children, child)
+		}
+	}
+	return elem, data[offset+length:], nil
+}
+
+// berReadPacket 从连接中读取一个完整的 BER 报文
+func berReadPacket(r *bufio.Reader) (*berElement, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	length := int(header[1])
+	if length&0x80 != 0 {
+		size := length & 0x7f
+		if size == 0 || size > 4 {
+			return nil, errors.New("ber invalid length")
+		}
+		sizeBytes := make([]byte, size)
+		if _, err := io.ReadFull(r, sizeBytes); err != nil {
+			return nil, err
+		}
+		header = append(header, sizeBytes...)
+		length = 0
+		for _, b := range sizeBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	if length > ldapMaxPacketSize {
+		return nil, fmt.Errorf("ldap packet too large: %d", length)
+	}
+	packet := make([]byte, len(header)+length)
+	copy(packet, header)
+	if _, err := io.ReadFull(r, packet[len(header):]); err != nil {
+		return nil, err
+	}
+	elem, _, err := berDecode(packet)
+	return elem, err
+}
+
+// ldapEntry 查询结果中的一条记录，属性名称统一转为小写
+type ldapEntry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Attr 获取属性的第一个值
+func (e *ldapEntry) Attr(name string) string {
+	if vals := e.Attributes[strings.ToLower(name)]; len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+// Attrs 获取属性的所有值
+func (e *ldapEntry) Attrs(name string) []string {
+	return e.Attributes[strings.ToLower(name)]
+}
+
+// ldapSearchRequest 查询请求
+type ldapSearchRequest struct {
+	BaseDN     string
+	Scope      int64
+	Filter     string
+	Attributes []string
+	PageSize   uint32
+}
+
+// ldapConn 一个 LDAP 连接，请求在连接上串行执行
+type ldapConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	msgID   int64
+	timeout time.Duration
+}
+
+// dialLDAP 建立 LDAP 连接，支持 ldap:// 以及 ldaps://，ldap:// 可以通过 StartTLS 升级为加密连接
+func dialLDAP(rawURL string, startTLS bool, tlsConfig *tls.Config, timeout time.Duration) (*ldapConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "ldaps":
+			host = net.JoinHostPort(u.Hostname(), "636")
+		default:
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+	}
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig = tlsConfig.Clone()
+		tlsConfig.ServerName = u.Hostname()
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfig)
+	default:
+		return nil, fmt.Errorf("unsupported ldap scheme: %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ldapConn{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}
+	if startTLS && u.Scheme == "ldap" {
+		if err := c.startTLS(tlsConfig); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *ldapConn) startTLS(tlsConfig *tls.Config) error {
+	rsp, err := c.roundTrip(berSeq(ldapTagExtendedRequest, berString(berClassContext|0, ldapOIDStartTLS)),
+		ldapTagExtendedResponse)
+	if err != nil {
+		return err
+	}
+	if err := ldapResultError(rsp); err != nil {
+		return fmt.Errorf("ldap start tls: %w", err)
+	}
+	tlsConn := tls.Client(c.conn, tlsConfig)
+	_ = tlsConn.SetDeadline(time.Now().Add(c.timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// Bind 简单认证，密码为空时 LDAP 会进行匿名绑定，因此直接拒绝
+func (c *ldapConn) Bind(dn, password string) error {
+	if password == "" {
+		return errLDAPInvalidCredentials
+	}
+	req := berSeq(ldapTagBindRequest,
+		berInt(berTagInteger, 3),
+		berString(berTagOctetString, dn),
+		berString(berClassContext|0, password),
+	)
+	rsp, err := c.roundTrip(req, ldapTagBindResponse)
+	if err != nil {
+		return err
+	}
+	return ldapResultError(rsp)
+}
+
+// Search 查询记录，PageSize 大于 0 时使用分页控制拉取全部结果
+func (c *ldapConn) Search(req *ldapSearchRequest) ([]*ldapEntry, error) {
+	filter, err := compileLDAPFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	attrs := make([][]byte, 0, len(req.Attributes))
+	for _, attr := range req.Attributes {
+		attrs = append(attrs, berString(berTagOctetString, attr))
+	}
+	body := berSeq(ldapTagSearchRequest,
+		berString(berTagOctetString, req.BaseDN),
+		berInt(berTagEnumerated, req.Scope),
+		berInt(berTagEnumerated, 0),
+		berInt(berTagInteger, 0),
+		berInt(berTagInteger, 0),
+		berBool(false),
+		filter,
+		berSeq(berTagSequence, attrs...),
+	)
+
+	var (
+		entries []*ldapEntry
+		cookie  []byte
+	)
+	for {
+		var controls []byte
+		if req.PageSize > 0 {
+			paging := berSeq(berTagSequence,
+				berInt(berTagInteger, int64(req.PageSize)),
+				berEncode(berTagOctetString, cookie))
+			controls = berSeq(ldapTagControls, berSeq(berTagSequence,
+				berString(berTagOctetString, ldapOIDPagedResults),
+				berEncode(berTagOctetString, paging)))
+		}
+		msgID, err := c.send(body, controls)
+		if err != nil {
+			return nil, err
+		}
+
+		var done *berElement
+		for done == nil {
+			packet, err := c.read(msgID)
+			if err != nil {
+				return nil, err
+			}
+			op := packet.child(1)
+			switch op.tag {
+			case ldapTagSearchEntry:
+				entries = append(entries, parseLDAPEntry(op))
+			case ldapTagSearchDone:
+				done = packet
+			}
+		}
+		if err := ldapResultError(done.child(1)); err != nil {
+			return nil, err
+		}
+		cookie = pagedResultsCookie(done)
+		if len(cookie) == 0 {
+			return entries, nil
+		}
+	}
+}
+
+// Close 发送 Unbind 后关闭连接
+func (c *ldapConn) Close() error {
+	_, _ = c.send(berEncode(ldapTagUnbindRequest, nil), nil)
+	return c.conn.Close()
+}
+
+func (c *ldapConn) roundTrip(op []byte, expectTag byte) (*berElement, error) {
+	msgID, err := c.send(op, nil)
+	if err != nil {
+		return nil, err
+	}
+	packet, err := c.read(msgID)
+	if err != nil {
+		return nil, err
+	}
+	if rsp := packet.child(1); rsp.tag == expectTag {
+		return rsp, nil
+	}
+	return nil, fmt.Errorf("unexpected ldap response tag 0x%x", packet.child(1).tag)
+}
+
+func (c *ldapConn) send(op, controls []byte) (int64, error) {
+	c.msgID++
+	packet := berSeq(berTagSequence, berInt(berTagInteger, c.msgID), op, controls)
+	_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+	_, err := c.conn.Write(packet)
+	return c.msgID, err
+}
+
+func (c *ldapConn) read(msgID int64) (*berElement, error) {
+	for {
+		_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+		packet, err := berReadPacket(c.reader)
+		if err != nil {
+			return nil, err
+		}
+		if packet.tag != berTagSequence || len(packet.children) < 2 {
+			return nil, errors.New("malformed ldap message")
+		}
+		// 忽略 Notice of Disconnection 等非当前请求的消息
+		if packet.child(0).int() == msgID {
+			return packet, nil
+		}
+	}
+}
+
+func ldapResultError(result *berElement) error {
+	code := result.child(0).int()
+	switch code {
+	case ldapResultSuccess:
+		return nil
+	case ldapResultInvalidCredentials:
+		return errLDAPInvalidCredentials
+	default:
+		return fmt.Errorf("ldap result code %d: %s", code, result.child(2).str())
+	}
+}
+
+func parseLDAPEntry(op *berElement) *ldapEntry {
+	entry := &ldapEntry{DN: op.child(0).str(), Attributes: map[string][]string{}}
+	for _, attr := range op.child(1).children {
+		name := strings.ToLower(attr.child(0).str())
+		for _, val := range attr.child(1).children {
+			entry.Attributes[name] = append(entry.Attributes[name], val.str())
+		}
+	}
+	return entry
+}
+
+func pagedResultsCookie(done *berElement) []byte {
+	if len(done.children) < 3 || done.children[2].tag != ldapTagControls {
+		return nil
+	}
+	for _, control := range done.children[2].children {
+		if control.child(0).str() != ldapOIDPagedResults {
+			continue
+		}
+		// criticality 为可选字段，controlValue 总是最后一个
+		value, _, err := berDecode(control.children[len(control.children)-1].value)
+		if err != nil {
+			return nil
+		}
+		return value.child(1).value
+	}
+	return nil
+}
+
+// escapeLDAPFilter 对过滤条件中的值进行转义（RFC 4515），防止 LDAP 注入
+func escapeLDAPFilter(val string) string {
+	var sb strings.Builder
+	for i := 0; i < len(val); i++ {
+		switch c := val[i]; c {
+		case '*', '(', ')', '\\', 0:
+			sb.WriteString(fmt.Sprintf("\\%02x", c))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// compileLDAPFilter 将字符串形式的过滤条件编译为 BER 编码，支持 & | ! = ~= >= <= 存在以及子串匹配
+func compileLDAPFilter(filter string) ([]byte, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		filter = "(objectClass=*)"
+	}
+	if !strings.HasPrefix(filter, "(") {
+		filter = "(" + filter + ")"
+	}
+	out, rest, err := compileLDAPFilterItem(filter)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, fmt.Errorf("invalid ldap filter %q", filter)
+	}
+	return out, nil
+}
+
+func compileLDAPFilterItem(filter string) ([]byte, string, error) {
+	if len(filter) < 2 || filter[0] != '(' {
+		return nil, "", fmt.Errorf("invalid ldap filter %q", filter)
+	}
+	switch filter[1] {
+	case '&', '|':
+		tag := byte(berClassContext | berConstructed | 0)
+		if filter[1] == '|' {
+			tag = berClassContext | berConstructed | 1
+		}
+		var items [][]byte
+		rest := filter[2:]
+		for strings.HasPrefix(rest, "(") {
+			item, next, err := compileLDAPFilterItem(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			rest = next
+		}
+		if !strings.HasPrefix(rest, ")") || len(items) == 0 {
+			return nil, "", fmt.Errorf("invalid ldap filter %q", filter)
+		}
+		return berSeq(tag, items...), rest[1:], nil
+	case '!':
+		item, rest, err := compileLDAPFilterItem(filter[2:])
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, ")") {
+			return nil, "", fmt.Errorf("invalid ldap filter %q", filter)
+		}
+		return berSeq(berClassContext|berConstructed|2, item), rest[1:], nil
+	}
+
+	end := strings.IndexByte(filter, ')')
+	if end < 0 {
+		return nil, "", fmt.Errorf("invalid ldap filter %q", filter)
+	}
+	expr, rest := filter[1:end], filter[end+1:]
+	eq := strings.IndexByte(expr, '=')
+	if eq <= 0 {
+		return nil, "", fmt.Errorf("invalid ldap filter %q", filter)
+	}
+	attr, rawVal := expr[:eq], expr[eq+1:]
+	tag := byte(berClassContext | berConstructed | 3)
+	switch attr[len(attr)-1] {
+	case '~':
+		tag, attr = berClassContext|berConstructed|8, attr[:len(attr)-1]
+	case '>':
+		tag, attr = berClassContext|berConstructed|5, attr[:len(attr)-1]
+	case '<':
+		tag, attr = berClassContext|berConstructed|6, attr[:len(attr)-1]
+	}
+	if attr == "" {
+		return nil, "", fmt.Errorf("invalid ldap filter %q", filter)
+	}
+
+	if tag == berClassContext|berConstructed|3 && strings.Contains(rawVal, "*") {
+		if rawVal == "*" {
+			return berString(berClassContext|7, attr), rest, nil
+		}
+		parts := strings.Split(rawVal, "*")
+		var subs [][]byte
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			val, err := unescapeLDAPFilter(part)
+			if err != nil {
+				return nil, "", err
+			}
+			subTag := byte(berClassContext | 1)
+			if i == 0 {
+				subTag = berClassContext | 0
+			} else if i == len(parts)-1 {
+				subTag = berClassContext | 2
+			}
+			subs = append(subs, berString(subTag, val))
+		}
+		return berSeq(berClassContext|berConstructed|4,
+			berString(berTagOctetString, attr), berSeq(berTagSequence, subs...)), rest, nil
+	}
+
+	val, err := unescapeLDAPFilter(rawVal)
+	if err != nil {
+		return nil, "", err
+	}
+	return berSeq(tag, berString(berTagOctetString, attr), berString(berTagOctetString, val)), rest, nil
+}
+
+func unescapeLDAPFilter(val string) (string, error) {
+	if !strings.Contains(val, "\\") {
+		return val, nil
+	}
+	var sb strings.Builder
+	for i := 0; i < len(val); i++ {
+		if val[i] != '\\' {
+			sb.WriteByte(val[i])
+			continue
+		}
+		if i+2 >= len(val) {
+			return "", fmt.Errorf("invalid ldap filter escape in %q", val)
+		}
+		b, err := hex.DecodeString(val[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("invalid ldap filter escape in %q", val)
+		}
+		sb.Write(b)
+		i += 2
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,367 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	storemock "github.com/polarismesh/polaris/store/mock"
+)
+
+// mockLDAPServer 本地模拟的 LDAP 服务，只处理 Bind、Search 以及 Unbind
+type mockLDAPServer struct {
+	t        *testing.T
+	listener net.Listener
+	// DN 到密码的映射
+	passwords map[string]string
+	entries   []*ldapEntry
+	pageSize  int
+}
+
+func newMockLDAPServer(t *testing.T) *mockLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := &mockLDAPServer{t: t, listener: listener, passwords: map[string]string{}, pageSize: 1}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *mockLDAPServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *mockLDAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		packet, err := berReadPacket(reader)
+		if err != nil {
+			return
+		}
+		msgID := packet.child(0).int()
+		op := packet.child(1)
+		reply := func(items ...[]byte) {
+			_, _ = conn.Write(berSeq(berTagSequence, append([][]byte{berInt(berTagInteger, msgID)}, items...)...))
+		}
+		result := func(tag byte, code int64) []byte {
+			return berSeq(tag, berInt(berTagEnumerated, code), berString(berTagOctetString, ""),
+				berString(berTagOctetString, ""))
+		}
+		switch op.tag {
+		case ldapTagBindRequest:
+			code := int64(ldapResultInvalidCredentials)
+			if pwd, ok := s.passwords[op.child(1).str()]; ok && pwd == op.child(2).str() {
+				code = ldapResultSuccess
+			}
+			reply(result(ldapTagBindResponse, code))
+		case ldapTagSearchRequest:
+			s.search(packet, op, reply, result)
+		case ldapTagUnbindRequest:
+			return
+		}
+	}
+}
+
+func (s *mockLDAPServer) search(packet, op *berElement, reply func(...[]byte), result func(byte, int64) []byte) {
+	base := op.child(0).str()
+	values := filterValues(op.child(6))
+	var matched []*ldapEntry
+	for _, entry := range s.entries {
+		if !strings.HasSuffix(entry.DN, base) {
+			continue
+		}
+		// 简化处理：过滤条件中出现的 cn/uid 取值必须与记录匹配
+		name := entry.Attr("uid") + entry.Attr("cn")
+		if _, ok := values[name]; !ok && (values["uid"] || values["cn"]) {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+
+	// 使用记录的下标作为分页 cookie
+	offset := 0
+	if len(packet.children) > 2 {
+		paging, _, _ := berDecode(packet.child(2).child(0).child(1).value)
+		if cookie := paging.child(1).str(); cookie != "" {
+			offset = int(cookie[0])
+		}
+	}
+	end := offset + s.pageSize
+	if end > len(matched) {
+		end = len(matched)
+	}
+	for _, entry := range matched[offset:end] {
+		var attrs [][]byte
+		for name, vals := range entry.Attributes {
+			var items [][]byte
+			for _, val := range vals {
+				items = append(items, berString(berTagOctetString, val))
+			}
+			attrs = append(attrs, berSeq(berTagSequence, berString(berTagOctetString, name),
+				berSeq(berTagSet, items...)))
+		}
+		reply(berSeq(ldapTagSearchEntry, berString(berTagOctetString, entry.DN), berSeq(berTagSequence, attrs...)))
+	}
+	var cookie []byte
+	if end < len(matched) {
+		cookie = []byte{byte(end)}
+	}
+	reply(result(ldapTagSearchDone, ldapResultSuccess), berSeq(ldapTagControls, berSeq(berTagSequence,
+		berString(berTagOctetString, ldapOIDPagedResults),
+		berEncode(berTagOctetString, berSeq(berTagSequence, berInt(berTagInteger, 0), berEncode(berTagOctetString, cookie))),
+	)))
+}
+
+// filterValues 收集等值匹配的属性值，以及出现了等值匹配的属性名称
+func filterValues(filter *berElement) map[string]bool {
+	ret := map[string]bool{}
+	var walk func(e *berElement)
+	walk = func(e *berElement) {
+		if e.tag == berClassContext|berConstructed|3 {
+			ret[strings.ToLower(e.child(0).str())] = true
+			ret[e.child(1).str()] = true
+			return
+		}
+		for _, child := range e.children {
+			walk(child)
+		}
+	}
+	walk(filter)
+	return ret
+}
+
+func newMockLDAPDirectory(t *testing.T) (*mockLDAPServer, *LDAPConfig) {
+	s := newMockLDAPServer(t)
+	s.passwords["cn=admin,dc=example,dc=com"] = "admin"
+	s.passwords["uid=alice,ou=people,dc=example,dc=com"] = "alice-pwd"
+	s.entries = []*ldapEntry{
+		{DN: "uid=alice,ou=people,dc=example,dc=com", Attributes: map[string][]string{
+			"uid": {"alice"}, "mail": {"alice@example.com"}}},
+		{DN: "uid=bob,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"bob"}}},
+		{DN: "uid=carol,ou=people,dc=example,dc=com", Attributes: map[string][]string{"uid": {"carol"}}},
+		{DN: "cn=dev,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"cn": {"dev"}, "member": {"UID=alice, ou=people,dc=example,dc=com", "bob"}}},
+	}
+	cfg, err := ParseLDAPConfig(map[string]interface{}{
+		"enable":       true,
+		"url":          s.url(),
+		"bindDN":       "cn=admin,dc=example,dc=com",
+		"bindPassword": "admin",
+		"userBaseDN":   "ou=people,dc=example,dc=com",
+		"groupBaseDN":  "ou=groups,dc=example,dc=com",
+		"groups":       []string{"dev", "ops"},
+		"owner":        "polaris",
+		"timeout":      "3s",
+	})
+	assert.NoError(t, err)
+	return s, cfg
+}
+
+func Test_compileLDAPFilter(t *testing.T) {
+	data, err := compileLDAPFilter("(&(objectClass=person)(uid=a\\2ab)(!(cn=x*y*)))")
+	assert.NoError(t, err)
+	filter, rest, err := berDecode(data)
+	assert.NoError(t, err)
+	assert.Empty(t, rest)
+	assert.Equal(t, byte(berClassContext|berConstructed|0), filter.tag)
+	assert.Len(t, filter.children, 3)
+	assert.Equal(t, "a*b", filter.child(1).child(1).str())
+	substr := filter.child(2).child(0)
+	assert.Equal(t, byte(berClassContext|berConstructed|4), substr.tag)
+	assert.Equal(t, "x", substr.child(1).child(0).str())
+	assert.Equal(t, byte(berClassContext|1), substr.child(1).child(1).tag)
+
+	data, err = compileLDAPFilter("objectClass=*")
+	assert.NoError(t, err)
+	assert.Equal(t, berString(berClassContext|7, "objectClass"), data)
+
+	for _, item := range []string{"(&)", "(uid=a", "(=a)", "(uid=\\zz)", "(uid=a)(cn=b)"} {
+		_, err = compileLDAPFilter(item)
+		assert.Error(t, err, item)
+	}
+	assert.Equal(t, "\\2a\\28\\29\\5c", escapeLDAPFilter("*()\\"))
+}
+
+func Test_berInt(t *testing.T) {
+	for _, v := range []int64{0, 1, 127, 128, 255, 256, -1, -128, -129, 1 << 40} {
+		elem, _, err := berDecode(berInt(berTagInteger, v))
+		assert.NoError(t, err)
+		assert.Equal(t, v, elem.int())
+	}
+	// 长度超过 127 时使用长格式编码
+	elem, _, err := berDecode(berString(berTagOctetString, strings.Repeat("a", 300)))
+	assert.NoError(t, err)
+	assert.Len(t, elem.value, 300)
+}
+
+func Test_berDecodeDepth(t *testing.T) {
+	data := berInt(berTagInteger, 1)
+	for i := 0; i < berMaxDepth-1; i++ {
+		data = berSeq(berTagSequence, data)
+	}
+	_, _, err := berDecode(data)
+	assert.NoError(t, err)
+
+	_, _, err = berDecode(berSeq(berTagSequence, data))
+	assert.Error(t, err)
+}
+
+func Fuzz_berDecode(f *testing.F) {
+	f.Add(berInt(berTagInteger, 1<<40))
+	f.Add(berSeq(berTagSequence, berInt(berTagInteger, 1), berSeq(ldapTagSearchEntry,
+		berString(berTagOctetString, "uid=alice"), berSeq(berTagSequence, berSeq(berTagSequence,
+			berString(berTagOctetString, "mail"), berSeq(berTagSet, berString(berTagOctetString, "a@b")))))))
+	f.Add(berSeq(ldapTagSearchDone, berInt(berTagEnumerated, 0), berString(berTagOctetString, ""),
+		berSeq(ldapTagControls, berSeq(berTagSequence, berString(berTagOctetString, ldapOIDPagedResults)))))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		elem, rest, err := berDecode(data)
+		if err != nil {
+			return
+		}
+		assert.True(t, len(rest) < len(data))
+		// 解析出的任意结构都不能导致后续处理 panic
+		_ = ldapResultError(elem)
+		_ = parseLDAPEntry(elem)
+		_ = pagedResultsCookie(elem)
+	})
+}
+
+func Test_ldapClient(t *testing.T) {
+	s, cfg := newMockLDAPDirectory(t)
+	defer s.listener.Close()
+	client := &ldapClient{cfg: cfg}
+
+	assert.NoError(t, client.Authenticate("alice", "alice-pwd"))
+	assert.ErrorIs(t, client.Authenticate("alice", "wrong"), errLDAPInvalidCredentials)
+	assert.ErrorIs(t, client.Authenticate("alice", ""), errLDAPInvalidCredentials)
+	assert.ErrorIs(t, client.Authenticate("nobody", "alice-pwd"), errLDAPInvalidCredentials)
+
+	snapshot, err := client.Fetch()
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Users, 2)
+	assert.Equal(t, "alice@example.com", snapshot.Users["alice"].Email)
+	assert.Equal(t, []*ldapGroup{
+		{Name: "dev", Found: true, Members: []string{"alice", "bob"}},
+		{Name: "ops"},
+	}, snapshot.Groups)
+
+	cfg.BindPassword = "wrong"
+	_, err = client.Fetch()
+	assert.ErrorIs(t, err, errLDAPInvalidCredentials)
+}
+
+type fakeLDAPDirectory struct {
+	snapshot *ldapSnapshot
+}
+
+func (f *fakeLDAPDirectory) Authenticate(username, password string) error {
+	return nil
+}
+
+func (f *fakeLDAPDirectory) Fetch() (*ldapSnapshot, error) {
+	return f.snapshot, nil
+}
+
+func Test_server_syncLDAP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	AuthOption = DefaultAuthConfig()
+	storage := storemock.NewMockStore(ctrl)
+	owner := &model.User{ID: "owner-id", Name: "polaris", Type: model.OwnerUserRole}
+	bob := &model.User{ID: "bob-id", Name: "bob", Owner: owner.ID, Source: LDAPUserSource}
+	carol := &model.User{ID: "carol-id", Name: "carol", Owner: owner.ID, Source: LDAPUserSource}
+	local := &model.User{ID: "local-id", Name: "local", Owner: owner.ID}
+
+	cfg := &LDAPConfig{Owner: "polaris", Groups: []string{"dev"}}
+	svr := &server{storage: storage, ldap: &ldapSource{cfg: cfg, directory: &fakeLDAPDirectory{
+		snapshot: &ldapSnapshot{
+			Users: map[string]*ldapUser{
+				"alice": {Name: "alice", Email: "alice@example.com"},
+				"carol": {Name: "carol", Email: "carol@example.com"},
+			},
+			Groups: []*ldapGroup{{Name: "dev", Found: true, Members: []string{"alice", "carol"}}},
+		},
+	}}}
+
+	var alice *model.User
+	storage.EXPECT().GetUserByName("polaris", "").Return(owner, nil)
+	storage.EXPECT().GetUsers(map[string]string{"owner": owner.ID, "source": LDAPUserSource}, uint32(0),
+		uint32(1000)).Return(uint32(2), []*model.User{bob, carol}, nil)
+	storage.EXPECT().GetUserByName("alice", owner.ID).Return(nil, nil)
+	storage.EXPECT().AddUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+		assert.Equal(t, LDAPUserSource, user.Source)
+		assert.Equal(t, owner.ID, user.Owner)
+		alice = user
+		return nil
+	})
+	storage.EXPECT().UpdateUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+		assert.Equal(t, "carol@example.com", user.Email)
+		return nil
+	})
+	storage.EXPECT().DeleteUser(bob).Return(nil)
+	storage.EXPECT().GetGroupByName("dev", owner.ID).Return(&model.UserGroup{ID: "dev-id", Name: "dev"}, nil)
+	storage.EXPECT().GetGroup("dev-id").Return(&model.UserGroupDetail{
+		UserGroup: &model.UserGroup{ID: "dev-id", Name: "dev", Owner: owner.ID, Token: "token"},
+		UserIds:   map[string]struct{}{bob.ID: {}, carol.ID: {}, local.ID: {}},
+	}, nil)
+	storage.EXPECT().UpdateGroup(gomock.Any()).DoAndReturn(func(group *model.ModifyUserGroup) error {
+		// 只移除离开目录的 LDAP 用户，手动加入的本地用户保持不变
+		assert.Equal(t, []string{alice.ID}, group.AddUserIds)
+		assert.Equal(t, []string{bob.ID}, group.RemoveUserIds)
+		assert.Equal(t, "token", group.Token)
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, svr.syncLDAP(ctx))
+}
+
+func Test_server_syncLDAP_NoGroupFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storage := storemock.NewMockStore(ctrl)
+	svr := &server{storage: storage, ldap: &ldapSource{
+		cfg: &LDAPConfig{Owner: "polaris", Groups: []string{"dev"}},
+		directory: &fakeLDAPDirectory{snapshot: &ldapSnapshot{
+			Groups: []*ldapGroup{{Name: "dev"}},
+		}},
+	}}
+	storage.EXPECT().GetUserByName("polaris", "").Return(&model.User{ID: "owner-id"}, nil)
+	// 没有找到任何用户组时不能删除已有的用户
+	assert.Error(t, svr.syncLDAP(context.Background()))
+}
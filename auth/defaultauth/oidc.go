@@ -0,0 +1,581 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/polarismesh/polaris/auth"
+)
+
+var (
+	// ErrorOIDCNotEnabled 没有开启 OIDC 单点登录
+	ErrorOIDCNotEnabled = errors.New("oidc login not enabled")
+	// ErrorOIDCInvalidState 授权回调中的 state 不合法或者已经过期
+	ErrorOIDCInvalidState = errors.New("invalid or expired oidc state")
+)
+
+const (
+	defaultOIDCUsernameClaim = "preferred_username"
+	defaultOIDCGroupsClaim   = "groups"
+	defaultOIDCStateTimeout  = 10 * time.Minute
+	defaultOIDCSessionTTL    = 8 * time.Hour
+	// minOIDCStateSecretLen state 加密密钥的最小长度
+	minOIDCStateSecretLen = 32
+	// oidcClockSkew 校验 ID Token 有效期时允许的时钟偏差
+	oidcClockSkew = time.Minute
+)
+
+// OIDCConfig OIDC 单点登录配置，对应 auth.user.option.oidc
+type OIDCConfig struct {
+	// Enable 是否开启 OIDC 单点登录
+	Enable bool `mapstructure:"enable"`
+	// Issuer IdP 的 issuer，未配置 endpoint 时通过 {issuer}/.well-known/openid-configuration 获取
+	Issuer string `mapstructure:"issuer"`
+	// ClientID 在 IdP 中注册的客户端ID
+	ClientID string `mapstructure:"clientId"`
+	// ClientSecret 客户端密钥，公共客户端可以为空，只依赖 PKCE
+	ClientSecret string `mapstructure:"clientSecret"`
+	// RedirectURL 在 IdP 中注册的回调地址
+	RedirectURL string `mapstructure:"redirectUrl"`
+	// AuthorizationEndpoint 授权地址，为空时使用 discovery 结果
+	AuthorizationEndpoint string `mapstructure:"authorizationEndpoint"`
+	// TokenEndpoint 换取 token 的地址，为空时使用 discovery 结果
+	TokenEndpoint string `mapstructure:"tokenEndpoint"`
+	// JWKSURI 签名公钥地址，为空时使用 discovery 结果
+	JWKSURI string `mapstructure:"jwksUri"`
+	// Scopes 申请的 scope，openid 会被自动添加
+	Scopes []string `mapstructure:"scopes"`
+	// UsernameClaim 映射为北极星用户名的 claim，默认为 preferred_username
+	UsernameClaim string `mapstructure:"usernameClaim"`
+	// GroupsClaim 映射为北极星用户组名称的 claim，默认为 groups
+	GroupsClaim string `mapstructure:"groupsClaim"`
+	// Owner 单点登录用户所属的主账户名称
+	Owner string `mapstructure:"owner"`
+	// AutoCreateUser 用户不存在时是否自动创建为 owner 下的子账户，默认开启
+	AutoCreateUser bool `mapstructure:"autoCreateUser"`
+	// SyncGroups 是否按照 GroupsClaim 同步用户在 owner 下的用户组关系
+	SyncGroups bool `mapstructure:"syncGroups"`
+	// StateTimeout 从发起登录到完成回调的最长时间
+	StateTimeout time.Duration `mapstructure:"stateTimeout"`
+	// StateSecret state 的加密以及签名密钥，与 salt 分开配置
+	StateSecret string `mapstructure:"stateSecret"`
+	// SessionTTL 单点登录签发的临时 token 的有效期，不超过 scopedTokenMaxTTL
+	SessionTTL time.Duration `mapstructure:"sessionTTL"`
+}
+
+// ParseOIDCConfig 解析 OIDC 配置，未配置时返回 nil
+func ParseOIDCConfig(raw interface{}) (*OIDCConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	cfg := &OIDCConfig{
+		AutoCreateUser: true,
+		UsernameClaim:  defaultOIDCUsernameClaim,
+		GroupsClaim:    defaultOIDCGroupsClaim,
+		StateTimeout:   defaultOIDCStateTimeout,
+		SessionTTL:     defaultOIDCSessionTTL,
+	}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     cfg,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return nil, err
+	}
+	if !cfg.Enable {
+		return nil, nil
+	}
+	if err := cfg.Verify(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Verify 检查 OIDC 配置是否合法
+func (cfg *OIDCConfig) Verify() error {
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return errors.New("[Auth][OIDC] issuer, clientId and redirectUrl must be set")
+	}
+	if cfg.Owner == "" {
+		return errors.New("[Auth][OIDC] owner must be set")
+	}
+	if len(cfg.StateSecret) < minOIDCStateSecretLen {
+		return errors.New("[Auth][OIDC] stateSecret len must not be less than 32")
+	}
+	if cfg.StateTimeout <= 0 || cfg.SessionTTL <= 0 {
+		return errors.New("[Auth][OIDC] stateTimeout and sessionTTL must be positive")
+	}
+	return nil
+}
+
+// oidcState 随着授权请求发送给 IdP 的 state，加密后保证 code_verifier 以及 nonce 不会泄露
+type oidcState struct {
+	Verifier string `json:"v"`
+	Nonce    string `json:"n"`
+	ExpireAt int64  `json:"e"`
+}
+
+// oidcIdentity 从 ID Token 中解析出来的用户身份信息
+type oidcIdentity struct {
+	Subject  string
+	Username string
+	Email    string
+	Groups   []string
+}
+
+// oidcProvider OIDC 授权码 + PKCE 流程的实现，state 通过 stateSecret 加密后下发，不依赖服务端的会话状态，
+// state 中的 nonce 同时写入浏览器的 HttpOnly cookie，回调时两者一致才能完成登录
+type oidcProvider struct {
+	cfg    *OIDCConfig
+	encKey []byte
+	macKey []byte
+	client *http.Client
+	now    func() time.Time
+
+	lock          sync.RWMutex
+	authEndpoint  string
+	tokenEndpoint string
+	jwksURI       string
+	keys          map[string]*rsa.PublicKey
+}
+
+func newOIDCProvider(cfg *OIDCConfig) *oidcProvider {
+	return &oidcProvider{
+		cfg:           cfg,
+		encKey:        deriveOIDCKey(cfg.StateSecret, "state-encrypt"),
+		macKey:        deriveOIDCKey(cfg.StateSecret, "state-sign"),
+		client:        &http.Client{Timeout: 10 * time.Second},
+		now:           time.Now,
+		authEndpoint:  cfg.AuthorizationEndpoint,
+		tokenEndpoint: cfg.TokenEndpoint,
+		jwksURI:       cfg.JWKSURI,
+		keys:          map[string]*rsa.PublicKey{},
+	}
+}
+
+// discover 按需拉取 IdP 的 openid-configuration，只补齐没有配置的 endpoint
+func (p *oidcProvider) discover(ctx context.Context) error {
+	p.lock.RLock()
+	ready := p.authEndpoint != "" && p.tokenEndpoint != "" && p.jwksURI != ""
+	p.lock.RUnlock()
+	if ready {
+		return nil
+	}
+
+	meta := struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}{}
+	wellKnown := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, &meta); err != nil {
+		return err
+	}
+	if meta.Issuer != p.cfg.Issuer {
+		return fmt.Errorf("oidc issuer mismatch, expect %s, actual %s", p.cfg.Issuer, meta.Issuer)
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.authEndpoint == "" {
+		p.authEndpoint = meta.AuthorizationEndpoint
+	}
+	if p.tokenEndpoint == "" {
+		p.tokenEndpoint = meta.TokenEndpoint
+	}
+	if p.jwksURI == "" {
+		p.jwksURI = meta.JWKSURI
+	}
+	return nil
+}
+
+// deriveOIDCKey 从 stateSecret 派生出加密以及签名使用的不同密钥
+func deriveOIDCKey(secret, usage string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(usage))
+	return mac.Sum(nil)
+}
+
+// AuthorizeURL 生成授权码流程的跳转地址，PKCE 使用 S256
+func (p *oidcProvider) AuthorizeURL(ctx context.Context) (*auth.OIDCAuthorization, error) {
+	if err := p.discover(ctx); err != nil {
+		return nil, err
+	}
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomURLString(16)
+	if err != nil {
+		return nil, err
+	}
+	expireAt := p.now().Add(p.cfg.StateTimeout)
+	state, err := p.encodeState(&oidcState{
+		Verifier: verifier,
+		Nonce:    nonce,
+		ExpireAt: expireAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.scopes(), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	p.lock.RLock()
+	endpoint := p.authEndpoint
+	p.lock.RUnlock()
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return &auth.OIDCAuthorization{
+		URL:      endpoint + sep + query.Encode(),
+		Nonce:    nonce,
+		ExpireAt: expireAt,
+	}, nil
+}
+
+func (p *oidcProvider) scopes() []string {
+	scopes := []string{"openid"}
+	for _, scope := range p.cfg.Scopes {
+		if scope != "" && scope != "openid" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// Exchange 使用授权码以及 state 中的 code_verifier 换取 ID Token，并完成 ID Token 的校验
+// nonce 为发起登录的浏览器 cookie 中保存的值，防止攻击者诱导用户使用攻击者的授权码完成登录
+func (p *oidcProvider) Exchange(ctx context.Context, code, rawState, nonce string) (*oidcIdentity, error) {
+	state, err := p.decodeState(rawState)
+	if err != nil {
+		return nil, err
+	}
+	if nonce == "" || !hmac.Equal([]byte(nonce), []byte(state.Nonce)) {
+		return nil, ErrorOIDCInvalidState
+	}
+	if code == "" {
+		return nil, errors.New("oidc authorization code is empty")
+	}
+	if err := p.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", state.Verifier)
+
+	p.lock.RLock()
+	endpoint := p.tokenEndpoint
+	p.lock.RUnlock()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	rsp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	tokenRsp := struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	body, err := io.ReadAll(io.LimitReader(rsp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &tokenRsp); err != nil {
+		return nil, fmt.Errorf("invalid oidc token response, status %d: %w", rsp.StatusCode, err)
+	}
+	if rsp.StatusCode != http.StatusOK || tokenRsp.Error != "" {
+		return nil, fmt.Errorf("oidc token exchange failed, status %d: %s %s",
+			rsp.StatusCode, tokenRsp.Error, tokenRsp.ErrorDescription)
+	}
+	if tokenRsp.IDToken == "" {
+		return nil, errors.New("oidc token response has no id_token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, tokenRsp.IDToken, state.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseIdentity(claims)
+}
+
+// verifyIDToken 校验 ID Token 的签名（RS256）、issuer、audience、有效期以及 nonce
+func (p *oidcProvider) verifyIDToken(ctx context.Context, rawToken, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed id_token")
+	}
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported id_token alg: %s", header.Alg)
+	}
+	key, err := p.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("verify id_token signature: %w", err)
+	}
+
+	claims := map[string]interface{}{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if iss, _ := claims["iss"].(string); iss != p.cfg.Issuer {
+		return nil, fmt.Errorf("id_token issuer mismatch: %s", iss)
+	}
+	if !audienceContains(claims["aud"], p.cfg.ClientID) {
+		return nil, errors.New("id_token audience mismatch")
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok || p.now().Add(-oidcClockSkew).After(time.Unix(int64(exp), 0)) {
+		return nil, errors.New("id_token expired")
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+	return claims, nil
+}
+
+func (p *oidcProvider) parseIdentity(claims map[string]interface{}) (*oidcIdentity, error) {
+	identity := &oidcIdentity{}
+	identity.Subject, _ = claims["sub"].(string)
+	identity.Username, _ = claims[p.cfg.UsernameClaim].(string)
+	identity.Email, _ = claims["email"].(string)
+	if identity.Username == "" {
+		return nil, fmt.Errorf("id_token has no %s claim", p.cfg.UsernameClaim)
+	}
+	switch groups := claims[p.cfg.GroupsClaim].(type) {
+	case []interface{}:
+		for _, item := range groups {
+			if name, ok := item.(string); ok && name != "" {
+				identity.Groups = append(identity.Groups, name)
+			}
+		}
+	case string:
+		if groups != "" {
+			identity.Groups = append(identity.Groups, groups)
+		}
+	}
+	return identity, nil
+}
+
+// publicKey 根据 kid 获取签名公钥，本地没有时重新拉取 JWKS，用于支持 IdP 的密钥轮转
+func (p *oidcProvider) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.lock.RLock()
+	key, ok := p.keys[kid]
+	p.lock.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	p.lock.RLock()
+	jwksURI := p.jwksURI
+	p.lock.RUnlock()
+	jwks := struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}{}
+	if err := p.getJSON(ctx, jwksURI, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, item := range jwks.Keys {
+		if item.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(item.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(item.E)
+		if err != nil {
+			continue
+		}
+		keys[item.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	p.lock.Lock()
+	p.keys = keys
+	p.lock.Unlock()
+
+	if key, ok = keys[kid]; !ok {
+		return nil, fmt.Errorf("oidc signing key %s not found", kid)
+	}
+	return key, nil
+}
+
+func (p *oidcProvider) getJSON(ctx context.Context, target string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	rsp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get %s failed, status %d", target, rsp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(rsp.Body, 1<<20)).Decode(out)
+}
+
+// encodeState 加密 state 并附加 HMAC，防止被篡改
+func (p *oidcProvider) encodeState(state *oidcState) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+	cipherText, err := encryptMessage(p.encKey, string(data))
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString([]byte(cipherText))
+	return payload + "." + p.signState(payload), nil
+}
+
+func (p *oidcProvider) decodeState(raw string) (*oidcState, error) {
+	items := strings.Split(raw, ".")
+	if len(items) != 2 || !hmac.Equal([]byte(items[1]), []byte(p.signState(items[0]))) {
+		return nil, ErrorOIDCInvalidState
+	}
+	cipherText, err := base64.RawURLEncoding.DecodeString(items[0])
+	if err != nil {
+		return nil, ErrorOIDCInvalidState
+	}
+	data, err := decryptMessage(p.encKey, string(cipherText))
+	if err != nil {
+		return nil, ErrorOIDCInvalidState
+	}
+	state := &oidcState{}
+	if err := json.Unmarshal([]byte(data), state); err != nil {
+		return nil, ErrorOIDCInvalidState
+	}
+	if p.now().Unix() > state.ExpireAt {
+		return nil, ErrorOIDCInvalidState
+	}
+	return state, nil
+}
+
+func (p *oidcProvider) signState(payload string) string {
+	mac := hmac.New(sha256.New, p.macKey)
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func decodeJWTSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("invalid id_token segment: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid id_token segment: %w", err)
+	}
+	return nil
+}
+
+func audienceContains(aud interface{}, clientID string) bool {
+	switch val := aud.(type) {
+	case string:
+		return val == clientID
+	case []interface{}:
+		for _, item := range val {
+			if s, ok := item.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func randomURLString(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
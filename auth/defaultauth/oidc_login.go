@@ -0,0 +1,225 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/auth"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// OIDCUserSource 通过 OIDC 单点登录自动创建的用户来源
+const OIDCUserSource = "OIDC"
+
+// OIDCLoginURL 生成 OIDC 单点登录的授权地址
+func (svr *server) OIDCLoginURL(ctx context.Context) (*auth.OIDCAuthorization, error) {
+	if svr.oidc == nil {
+		return nil, ErrorOIDCNotEnabled
+	}
+	return svr.oidc.AuthorizeURL(ctx)
+}
+
+// OIDCCallback 处理 IdP 的授权回调
+//
+//  1. 校验 cookie 中的 nonce 与 state 一致，使用授权码换取 ID Token 并校验
+//  2. 将 ID Token 中的用户映射为 owner 下的子账户，不存在时按需自动创建
+//  3. 按需同步用户在 owner 下的用户组关系
+//  4. 签发有效期为 sessionTTL 的登录会话作为登录凭据
+func (svr *server) OIDCCallback(ctx context.Context, code, state, nonce string) *apiservice.Response {
+	if svr.oidc == nil {
+		return api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, ErrorOIDCNotEnabled.Error())
+	}
+	requestID := utils.ParseRequestID(ctx)
+
+	identity, err := svr.oidc.Exchange(ctx, code, state, nonce)
+	if err != nil {
+		log.Error("[Auth][OIDC] exchange authorization code", utils.ZapRequestID(requestID), zap.Error(err))
+		return api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, err.Error())
+	}
+	if err := checkName(utils.NewStringValue(identity.Username)); err != nil {
+		log.Error("[Auth][OIDC] invalid username", utils.ZapRequestID(requestID),
+			zap.String("name", identity.Username), zap.Error(err))
+		return api.NewAuthResponseWithMsg(apimodel.Code_InvalidUserName, err.Error())
+	}
+
+	owner, err := svr.storage.GetUserByName(svr.oidc.cfg.Owner, "")
+	if err != nil {
+		log.Error("[Auth][OIDC] get owner", utils.ZapRequestID(requestID), zap.Error(err))
+		return api.NewAuthResponse(apimodel.Code_StoreLayerException)
+	}
+	if owner == nil {
+		log.Error("[Auth][OIDC] owner not found", utils.ZapRequestID(requestID),
+			zap.String("owner", svr.oidc.cfg.Owner))
+		return api.NewAuthResponse(apimodel.Code_NotFoundOwnerUser)
+	}
+	if owner.Name == identity.Username {
+		return api.NewAuthResponse(apimodel.Code_UserExisted)
+	}
+
+	user, resp := svr.loadOIDCUser(ctx, owner, identity)
+	if resp != nil {
+		return resp
+	}
+	if svr.oidc.cfg.SyncGroups {
+		if err := svr.syncOIDCUserGroups(ctx, owner, user, identity.Groups); err != nil {
+			log.Error("[Auth][OIDC] sync user groups", utils.ZapRequestID(requestID),
+				zap.String("name", user.Name), zap.Error(err))
+			return api.NewAuthResponse(StoreCode2APICode(err))
+		}
+	}
+
+	key, err := scopedTokenKey()
+	if err != nil {
+		return api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, err.Error())
+	}
+	ttl := int64(svr.oidc.cfg.SessionTTL / time.Second)
+	if maxTTL := scopedTokenMaxTTL(); ttl > maxTTL {
+		ttl = maxTTL
+	}
+	claims, token, err := issueSessionToken(key, user.ID, ttl)
+	if err != nil {
+		log.Error("[Auth][OIDC] sign session token", utils.ZapRequestID(requestID), zap.Error(err))
+		return api.NewAuthResponse(apimodel.Code_ExecuteException)
+	}
+
+	log.Info("[Auth][OIDC] user login", utils.ZapRequestID(requestID), zap.String("name", user.Name),
+		zap.String("subject", identity.Subject), zap.String("token-id", claims.ID),
+		zap.Int64("expire-at", claims.ExpireAt))
+	return api.NewLoginResponse(apimodel.Code_ExecuteSuccess, &apisecurity.LoginResponse{
+		UserId:  utils.NewStringValue(user.ID),
+		OwnerId: utils.NewStringValue(user.Owner),
+		Token:   utils.NewStringValue(token),
+		Name:    utils.NewStringValue(user.Name),
+		Role:    utils.NewStringValue(model.UserRoleNames[user.Type]),
+	})
+}
+
+// loadOIDCUser 查询 owner 下由单点登录创建的同名子账户，不存在且开启了 autoCreateUser 时自动创建
+func (svr *server) loadOIDCUser(ctx context.Context, owner *model.User,
+	identity *oidcIdentity) (*model.User, *apiservice.Response) {
+	requestID := utils.ParseRequestID(ctx)
+
+	user, err := svr.storage.GetUserByName(identity.Username, owner.ID)
+	if err != nil {
+		log.Error("[Auth][OIDC] get user by name and owner", utils.ZapRequestID(requestID),
+			zap.String("name", identity.Username), zap.Error(err))
+		return nil, api.NewAuthResponse(apimodel.Code_StoreLayerException)
+	}
+	if user != nil {
+		if !user.Valid {
+			return nil, api.NewAuthResponse(apimodel.Code_NotFoundUser)
+		}
+		// 不接管同名的本地用户以及其他来源的用户，避免 IdP 中同名的用户登录为该用户
+		if user.Source != OIDCUserSource {
+			log.Warn("[Auth][OIDC] user with same name from other source existed", utils.ZapRequestID(requestID),
+				zap.String("name", user.Name), zap.String("source", user.Source))
+			return nil, api.NewAuthResponse(apimodel.Code_UserExisted)
+		}
+		return user, nil
+	}
+	if !svr.oidc.cfg.AutoCreateUser {
+		return nil, api.NewAuthResponse(apimodel.Code_NotFoundUser)
+	}
+
+	// 单点登录的用户不使用密码登录，随机生成一个密码占位
+	password, err := randomURLString(24)
+	if err != nil {
+		return nil, api.NewAuthResponse(apimodel.Code_ExecuteException)
+	}
+	req := &apisecurity.User{
+		Name:     utils.NewStringValue(identity.Username),
+		Password: utils.NewStringValue(password),
+		Owner:    utils.NewStringValue(owner.ID),
+		Source:   utils.NewStringValue(OIDCUserSource),
+		Email:    utils.NewStringValue(identity.Email),
+		Comment:  utils.NewStringValue("created by oidc login"),
+	}
+	user, err = createUserModel(req, model.OwnerUserRole)
+	if err != nil {
+		log.Error("[Auth][OIDC] create user model", utils.ZapRequestID(requestID), zap.Error(err))
+		return nil, api.NewAuthResponse(apimodel.Code_ExecuteException)
+	}
+	if err := svr.storage.AddUser(user); err != nil {
+		log.Error("[Auth][OIDC] add user into store", utils.ZapRequestID(requestID), zap.Error(err))
+		return nil, api.NewAuthResponse(StoreCode2APICode(err))
+	}
+
+	log.Info("[Auth][OIDC] create user", utils.ZapRequestID(requestID), zap.String("name", user.Name))
+	req.Password = nil
+	svr.RecordHistory(userRecordEntry(ctx, req, user, model.OCreate))
+	return user, nil
+}
+
+// syncOIDCUserGroups 以 ID Token 中的用户组为准，同步用户在 owner 下的用户组关系，IdP 中存在但北极星中不存在的用户组会被忽略
+func (svr *server) syncOIDCUserGroups(ctx context.Context, owner, user *model.User, groupNames []string) error {
+	requestID := utils.ParseRequestID(ctx)
+
+	expect := make(map[string]struct{}, len(groupNames))
+	for _, name := range groupNames {
+		group, err := svr.storage.GetGroupByName(name, owner.ID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			log.Warn("[Auth][OIDC] user group not found, skip", utils.ZapRequestID(requestID),
+				zap.String("group", name))
+			continue
+		}
+		expect[group.ID] = struct{}{}
+		detail, err := svr.storage.GetGroup(group.ID)
+		if err != nil {
+			return err
+		}
+		if detail == nil {
+			continue
+		}
+		if _, ok := detail.UserIds[user.ID]; ok {
+			continue
+		}
+		if err := svr.storage.UpdateGroup(modifyGroupMembers(detail.UserGroup, []string{user.ID}, nil)); err != nil {
+			return err
+		}
+		log.Info("[Auth][OIDC] add user into group", utils.ZapRequestID(requestID),
+			zap.String("name", user.Name), zap.String("group", group.Name))
+	}
+
+	for _, groupID := range svr.cacheMgn.User().GetUserLinkGroupIds(user.ID) {
+		if _, ok := expect[groupID]; ok {
+			continue
+		}
+		group := svr.cacheMgn.User().GetGroup(groupID)
+		if group == nil || group.Owner != owner.ID {
+			continue
+		}
+		if err := svr.storage.UpdateGroup(modifyGroupMembers(group.UserGroup, nil, []string{user.ID})); err != nil {
+			return err
+		}
+		log.Info("[Auth][OIDC] remove user from group", utils.ZapRequestID(requestID),
+			zap.String("name", user.Name), zap.String("group", group.Name))
+	}
+	return nil
+}
@@ -0,0 +1,403 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	storemock "github.com/polarismesh/polaris/store/mock"
+)
+
+const testOIDCStateSecret = "polaris-oidc-state-secret-0123456789"
+
+// mockIdP 本地模拟的 OIDC IdP，提供 discovery、token 以及 jwks 接口
+type mockIdP struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// 授权码到 code_challenge 以及 nonce 的映射
+	codes  map[string][2]string
+	claims map[string]interface{}
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	idp := &mockIdP{t: t, key: key, codes: map[string][2]string{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		item, ok := idp.codes[r.PostForm.Get("code")]
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != item[0] {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := map[string]interface{}{
+			"iss":   idp.server.URL,
+			"aud":   []string{"polaris"},
+			"sub":   "10001",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": item[1],
+		}
+		for k, v := range idp.claims {
+			claims[k] = v
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": idp.sign(claims)})
+	})
+	idp.server = httptest.NewServer(mux)
+	return idp
+}
+
+func (idp *mockIdP) sign(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signing := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signing))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, digest[:])
+	assert.NoError(idp.t, err)
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// authorize 模拟用户在 IdP 完成登录，返回回调中的 code 以及 state
+func (idp *mockIdP) authorize(target string) (string, string) {
+	u, err := url.Parse(target)
+	assert.NoError(idp.t, err)
+	query := u.Query()
+	assert.Equal(idp.t, "S256", query.Get("code_challenge_method"))
+	code := "code-" + query.Get("nonce")
+	idp.codes[code] = [2]string{query.Get("code_challenge"), query.Get("nonce")}
+	return code, query.Get("state")
+}
+
+func newTestOIDCProvider(idp *mockIdP) *oidcProvider {
+	cfg, err := ParseOIDCConfig(map[interface{}]interface{}{
+		"enable":      true,
+		"issuer":      idp.server.URL,
+		"clientId":    "polaris",
+		"redirectUrl": "http://127.0.0.1:8090/core/v1/user/oidc/callback",
+		"owner":       "polaris",
+		"scopes":      []interface{}{"profile", "groups"},
+		"stateSecret": testOIDCStateSecret,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return newOIDCProvider(cfg)
+}
+
+func Test_ParseOIDCConfig(t *testing.T) {
+	cfg, err := ParseOIDCConfig(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	_, err = ParseOIDCConfig(map[string]interface{}{"enable": true, "issuer": "https://idp"})
+	assert.Error(t, err)
+
+	raw := map[string]interface{}{
+		"enable":       true,
+		"issuer":       "https://idp",
+		"clientId":     "polaris",
+		"redirectUrl":  "http://127.0.0.1/callback",
+		"owner":        "polaris",
+		"stateTimeout": "5m",
+	}
+	_, err = ParseOIDCConfig(raw)
+	assert.Error(t, err, "stateSecret must be set")
+
+	raw["stateSecret"] = "short"
+	_, err = ParseOIDCConfig(raw)
+	assert.Error(t, err, "stateSecret too short")
+
+	raw["stateSecret"] = testOIDCStateSecret
+	cfg, err = ParseOIDCConfig(raw)
+	assert.NoError(t, err)
+	assert.True(t, cfg.AutoCreateUser)
+	assert.Equal(t, defaultOIDCUsernameClaim, cfg.UsernameClaim)
+	assert.Equal(t, defaultOIDCGroupsClaim, cfg.GroupsClaim)
+	assert.Equal(t, 5*time.Minute, cfg.StateTimeout)
+	assert.Equal(t, defaultOIDCSessionTTL, cfg.SessionTTL)
+}
+
+func Test_oidcProvider_Exchange(t *testing.T) {
+	idp := newMockIdP(t)
+	defer idp.server.Close()
+	idp.claims = map[string]interface{}{
+		"preferred_username": "alice",
+		"email":              "alice@example.com",
+		"groups":             []string{"dev", "ops"},
+	}
+
+	t.Run("正常登录", func(t *testing.T) {
+		p := newTestOIDCProvider(idp)
+		authz, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(authz.URL, idp.server.URL+"/authorize?"))
+		assert.Contains(t, authz.URL, "scope=openid+profile+groups")
+		assert.NotEmpty(t, authz.Nonce)
+
+		code, state := idp.authorize(authz.URL)
+		identity, err := p.Exchange(context.Background(), code, state, authz.Nonce)
+		assert.NoError(t, err)
+		assert.Equal(t, &oidcIdentity{
+			Subject:  "10001",
+			Username: "alice",
+			Email:    "alice@example.com",
+			Groups:   []string{"dev", "ops"},
+		}, identity)
+	})
+
+	t.Run("state被篡改", func(t *testing.T) {
+		p := newTestOIDCProvider(idp)
+		authz, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+		_, err = p.Exchange(context.Background(), code, "x"+state, authz.Nonce)
+		assert.ErrorIs(t, err, ErrorOIDCInvalidState)
+	})
+
+	t.Run("nonce不匹配", func(t *testing.T) {
+		p := newTestOIDCProvider(idp)
+		authz, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+		_, err = p.Exchange(context.Background(), code, state, "")
+		assert.ErrorIs(t, err, ErrorOIDCInvalidState)
+
+		// 其他浏览器发起的登录，nonce 与 state 不匹配
+		other, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		_, err = p.Exchange(context.Background(), code, state, other.Nonce)
+		assert.ErrorIs(t, err, ErrorOIDCInvalidState)
+	})
+
+	t.Run("state密钥不同", func(t *testing.T) {
+		p := newTestOIDCProvider(idp)
+		authz, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+
+		cfg := *p.cfg
+		cfg.StateSecret = "another-oidc-state-secret-0123456789"
+		_, err = newOIDCProvider(&cfg).Exchange(context.Background(), code, state, authz.Nonce)
+		assert.ErrorIs(t, err, ErrorOIDCInvalidState)
+	})
+
+	t.Run("state过期", func(t *testing.T) {
+		p := newTestOIDCProvider(idp)
+		authz, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+		p.now = func() time.Time { return time.Now().Add(time.Hour) }
+		_, err = p.Exchange(context.Background(), code, state, authz.Nonce)
+		assert.ErrorIs(t, err, ErrorOIDCInvalidState)
+	})
+
+	t.Run("code_verifier不匹配", func(t *testing.T) {
+		p := newTestOIDCProvider(idp)
+		authz, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		code, _ := idp.authorize(authz.URL)
+		other, err := p.AuthorizeURL(context.Background())
+		assert.NoError(t, err)
+		_, otherState := idp.authorize(other.URL)
+		_, err = p.Exchange(context.Background(), code, otherState, other.Nonce)
+		assert.Error(t, err)
+	})
+}
+
+func Test_oidcProvider_verifyIDToken(t *testing.T) {
+	idp := newMockIdP(t)
+	defer idp.server.Close()
+	p := newTestOIDCProvider(idp)
+	assert.NoError(t, p.discover(context.Background()))
+
+	base := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   idp.server.URL,
+			"aud":   "polaris",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": "n",
+		}
+	}
+
+	_, err := p.verifyIDToken(context.Background(), idp.sign(base()), "n")
+	assert.NoError(t, err)
+
+	_, err = p.verifyIDToken(context.Background(), idp.sign(base()), "other")
+	assert.Error(t, err, "nonce mismatch")
+
+	claims := base()
+	claims["aud"] = []string{"other"}
+	_, err = p.verifyIDToken(context.Background(), idp.sign(claims), "n")
+	assert.Error(t, err, "audience mismatch")
+
+	claims = base()
+	claims["iss"] = "https://evil"
+	_, err = p.verifyIDToken(context.Background(), idp.sign(claims), "n")
+	assert.Error(t, err, "issuer mismatch")
+
+	claims = base()
+	claims["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = p.verifyIDToken(context.Background(), idp.sign(claims), "n")
+	assert.Error(t, err, "expired")
+
+	token := idp.sign(base())
+	parts := strings.Split(token, ".")
+	forged := base()
+	forged["sub"] = "admin"
+	payload, _ := json.Marshal(forged)
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	_, err = p.verifyIDToken(context.Background(), strings.Join(parts, "."), "n")
+	assert.Error(t, err, "bad signature")
+}
+
+func Test_server_OIDCCallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	AuthOption = DefaultAuthConfig()
+	AuthOption.Salt = "polaris@a7b068ce3235442b"
+	AuthOption.ScopedTokenSecret = "polaris-scoped-token-secret-0123456789"
+	defer func() { AuthOption = DefaultAuthConfig() }()
+	idp := newMockIdP(t)
+	defer idp.server.Close()
+	idp.claims = map[string]interface{}{"preferred_username": "alice"}
+
+	storage := storemock.NewMockStore(ctrl)
+	svr := &server{storage: storage, oidc: newTestOIDCProvider(idp)}
+	owner := &model.User{ID: "owner-id", Name: "polaris", Type: model.OwnerUserRole, Valid: true}
+
+	t.Run("自动创建子账户", func(t *testing.T) {
+		storage.EXPECT().GetUserByName("polaris", "").Return(owner, nil)
+		storage.EXPECT().GetUserByName("alice", owner.ID).Return(nil, nil)
+		var userID string
+		storage.EXPECT().AddUser(gomock.Any()).DoAndReturn(func(user *model.User) error {
+			userID = user.ID
+			assert.Equal(t, "alice", user.Name)
+			assert.Equal(t, owner.ID, user.Owner)
+			assert.Equal(t, OIDCUserSource, user.Source)
+			assert.Equal(t, model.SubAccountUserRole, user.Type)
+			return nil
+		})
+
+		authz, err := svr.OIDCLoginURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+		rsp := svr.OIDCCallback(context.Background(), code, state, authz.Nonce)
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), rsp.GetCode().GetValue(), rsp.GetInfo().GetValue())
+		assert.Equal(t, "alice", rsp.GetLoginResponse().GetName().GetValue())
+		assert.Equal(t, owner.ID, rsp.GetLoginResponse().GetOwnerId().GetValue())
+
+		// 登录凭据为带有效期的临时 token，而不是用户的静态 token
+		token := rsp.GetLoginResponse().GetToken().GetValue()
+		assert.True(t, isScopedToken(token))
+		key, err := scopedTokenKey()
+		assert.NoError(t, err)
+		claims, err := parseScopedToken(key, token, time.Now())
+		assert.NoError(t, err)
+		assert.Equal(t, userID, claims.Subject)
+		assert.Equal(t, int64(defaultOIDCSessionTTL/time.Second), claims.ExpireAt-claims.IssuedAt)
+		assert.False(t, claims.ReadOnly)
+		assert.Empty(t, claims.Namespaces)
+		assert.True(t, claims.Session)
+	})
+
+	t.Run("复用单点登录创建的子账户", func(t *testing.T) {
+		alice := &model.User{ID: "alice-id", Name: "alice", Owner: owner.ID, Source: OIDCUserSource,
+			Type: model.SubAccountUserRole, Valid: true}
+		storage.EXPECT().GetUserByName("polaris", "").Return(owner, nil)
+		storage.EXPECT().GetUserByName("alice", owner.ID).Return(alice, nil)
+
+		authz, err := svr.OIDCLoginURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+		rsp := svr.OIDCCallback(context.Background(), code, state, authz.Nonce)
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), rsp.GetCode().GetValue(), rsp.GetInfo().GetValue())
+		assert.Equal(t, alice.ID, rsp.GetLoginResponse().GetUserId().GetValue())
+	})
+
+	t.Run("存在同名的本地用户", func(t *testing.T) {
+		local := &model.User{ID: "alice-id", Name: "alice", Owner: owner.ID, Source: "Polaris",
+			Type: model.SubAccountUserRole, Valid: true}
+		storage.EXPECT().GetUserByName("polaris", "").Return(owner, nil)
+		storage.EXPECT().GetUserByName("alice", owner.ID).Return(local, nil)
+
+		authz, err := svr.OIDCLoginURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+		rsp := svr.OIDCCallback(context.Background(), code, state, authz.Nonce)
+		assert.Equal(t, uint32(apimodel.Code_UserExisted), rsp.GetCode().GetValue())
+	})
+
+	t.Run("关闭自动创建", func(t *testing.T) {
+		svr.oidc.cfg.AutoCreateUser = false
+		defer func() { svr.oidc.cfg.AutoCreateUser = true }()
+		storage.EXPECT().GetUserByName("polaris", "").Return(owner, nil)
+		storage.EXPECT().GetUserByName("alice", owner.ID).Return(nil, nil)
+
+		authz, err := svr.OIDCLoginURL(context.Background())
+		assert.NoError(t, err)
+		code, state := idp.authorize(authz.URL)
+		rsp := svr.OIDCCallback(context.Background(), code, state, authz.Nonce)
+		assert.Equal(t, uint32(apimodel.Code_NotFoundUser), rsp.GetCode().GetValue())
+	})
+
+	t.Run("未开启", func(t *testing.T) {
+		rsp := (&server{}).OIDCCallback(context.Background(), "code", "state", "nonce")
+		assert.Equal(t, uint32(apimodel.Code_NotAllowedAccess), rsp.GetCode().GetValue())
+	})
+}
@@ -0,0 +1,328 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/auth"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// scopedTokenIssuer 临时 token 的签发者
+	scopedTokenIssuer = "polaris"
+	// defaultScopedTokenTTL 临时 token 默认的有效期，单位秒
+	defaultScopedTokenTTL int64 = 3600
+	// defaultScopedTokenMaxTTL 临时 token 默认允许的最长有效期，单位秒
+	defaultScopedTokenMaxTTL int64 = 24 * 3600
+)
+
+// errScopedTokenDisabled 未配置独立的签名密钥或者仍在使用默认的 salt
+var errScopedTokenDisabled = errors.New("scoped token is disabled, scopedTokenSecret is not configured " +
+	"or salt is the default value")
+
+// scopedTokenHeader 临时 token 固定的 JWT 头部，签名算法为 HS256
+var scopedTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// scopedTokenClaims 临时 token 的 JWT 载荷
+type scopedTokenClaims struct {
+	ID         string   `json:"jti"`
+	Issuer     string   `json:"iss"`
+	Subject    string   `json:"sub"`
+	IssuedAt   int64    `json:"iat"`
+	ExpireAt   int64    `json:"exp"`
+	Namespaces []string `json:"ns,omitempty"`
+	ReadOnly   bool     `json:"ro,omitempty"`
+	// Session 单点登录签发的登录会话，等同于用户本人登录，不受临时 token 的写操作限制
+	Session bool `json:"sess,omitempty"`
+}
+
+// toOperator 将临时 token 的载荷转换为操作者信息，临时 token 只能由用户申请
+func (c *scopedTokenClaims) toOperator(origin string) OperatorInfo {
+	operator := OperatorInfo{
+		Origin:      origin,
+		IsUserToken: true,
+		OperatorID:  c.Subject,
+		Role:        model.UnknownUserRole,
+		TokenID:     c.ID,
+		ExpireAt:    c.ExpireAt,
+		Session:     c.Session,
+	}
+	if len(c.Namespaces) > 0 || c.ReadOnly {
+		operator.Scope = &TokenScope{
+			Namespaces: c.Namespaces,
+			ReadOnly:   c.ReadOnly,
+		}
+	}
+	return operator
+}
+
+// isScopedToken 静态 token 为标准 base64 编码，不会包含 '.'，据此区分临时 token
+func isScopedToken(t string) bool {
+	return strings.Count(t, ".") == 2
+}
+
+// scopedTokenMaxTTL 临时 token 允许的最长有效期
+func scopedTokenMaxTTL() int64 {
+	if AuthOption.ScopedTokenMaxTTL > 0 {
+		return AuthOption.ScopedTokenMaxTTL
+	}
+	return defaultScopedTokenMaxTTL
+}
+
+// scopedTokenKey 临时 token 的签名密钥，默认的 salt 以及主账户 ID 都是公开的，不能用来签名
+func scopedTokenKey() ([]byte, error) {
+	if AuthOption.ScopedTokenSecret == "" || AuthOption.Salt == defaultSalt {
+		return nil, errScopedTokenDisabled
+	}
+	return []byte(AuthOption.ScopedTokenSecret), nil
+}
+
+// signScopedToken 对临时 token 的载荷进行签名
+func signScopedToken(key []byte, claims *scopedTokenClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := scopedTokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + signScopedTokenInput(key, signingInput), nil
+}
+
+func signScopedTokenInput(key []byte, signingInput string) string {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// parseScopedToken 校验临时 token 的签名并解析载荷，token 过期时会同时返回载荷以及 model.ErrorTokenExpired
+func parseScopedToken(key []byte, token string, now time.Time) (*scopedTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, model.ErrorTokenInvalid
+	}
+	if parts[0] != scopedTokenHeader {
+		return nil, model.ErrorTokenInvalid
+	}
+	expect := signScopedTokenInput(key, parts[0]+"."+parts[1])
+	if !hmac.Equal([]byte(expect), []byte(parts[2])) {
+		return nil, model.ErrorTokenInvalid
+	}
+
+	claims := &scopedTokenClaims{}
+	if err := decodeJWTSegment(parts[1], claims); err != nil {
+		return nil, model.ErrorTokenInvalid
+	}
+	if claims.Issuer != scopedTokenIssuer || claims.ID == "" || claims.Subject == "" {
+		return nil, model.ErrorTokenInvalid
+	}
+	if now.Unix() >= claims.ExpireAt {
+		return claims, model.ErrorTokenExpired
+	}
+	return claims, nil
+}
+
+// issueScopedToken 为用户签发临时 token，ttl 单位为秒
+func issueScopedToken(key []byte, userID string, ttl int64, namespaces []string,
+	readOnly bool) (*scopedTokenClaims, string, error) {
+	return issueToken(key, &scopedTokenClaims{
+		Subject:    userID,
+		Namespaces: namespaces,
+		ReadOnly:   readOnly,
+	}, ttl)
+}
+
+// issueSessionToken 为单点登录的用户签发登录会话，ttl 单位为秒
+func issueSessionToken(key []byte, userID string, ttl int64) (*scopedTokenClaims, string, error) {
+	return issueToken(key, &scopedTokenClaims{
+		Subject: userID,
+		Session: true,
+	}, ttl)
+}
+
+func issueToken(key []byte, claims *scopedTokenClaims, ttl int64) (*scopedTokenClaims, string, error) {
+	now := time.Now()
+	claims.ID = uuid.NewString()
+	claims.Issuer = scopedTokenIssuer
+	claims.IssuedAt = now.Unix()
+	claims.ExpireAt = now.Unix() + ttl
+	token, err := signScopedToken(key, claims)
+	if err != nil {
+		return nil, "", err
+	}
+	return claims, token, nil
+}
+
+// checkTokenScope 检查本次写操作涉及的资源是否都在临时 token 的授权范围内
+func checkTokenScope(authCtx *model.AcquireContext, operator OperatorInfo) error {
+	scope := operator.Scope
+	if scope == nil {
+		return nil
+	}
+	if scope.ReadOnly {
+		return model.ErrorTokenOutOfScope
+	}
+	if len(scope.Namespaces) == 0 {
+		return nil
+	}
+	// 无法确认操作的资源所属的命名空间时，一律拒绝
+	if authCtx.IsAccessResourceEmpty() {
+		return model.ErrorTokenOutOfScope
+	}
+	for _, entries := range authCtx.GetAccessResources() {
+		if !scopeAllowEntries(scope, entries) {
+			return model.ErrorTokenOutOfScope
+		}
+	}
+	for _, entries := range authCtx.GetRuleResources() {
+		if !scopeAllowEntries(scope, entries) {
+			return model.ErrorTokenOutOfScope
+		}
+	}
+	return nil
+}
+
+func scopeAllowEntries(scope *TokenScope, entries []model.ResourceEntry) bool {
+	for i := range entries {
+		if entries[i].Namespace == "" || !scope.AllowNamespace(entries[i].Namespace) {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateScopedToken 为当前用户签发临时 token
+func (svr *server) CreateScopedToken(ctx context.Context, req *auth.ScopedTokenRequest) *auth.ScopedTokenResponse {
+	requestID := utils.ParseRequestID(ctx)
+	if req == nil {
+		return auth.NewScopedTokenResponse(apimodel.Code_EmptyRequest)
+	}
+	key, err := scopedTokenKey()
+	if err != nil {
+		return auth.NewScopedTokenResponseWithMsg(apimodel.Code_NotAllowedAccess, err.Error())
+	}
+
+	ttl := req.TTL
+	if ttl == 0 {
+		ttl = defaultScopedTokenTTL
+	}
+	if maxTTL := scopedTokenMaxTTL(); ttl < 0 || ttl > maxTTL {
+		return auth.NewScopedTokenResponseWithMsg(apimodel.Code_InvalidParameter,
+			fmt.Sprintf("ttl must be in (0, %d]", maxTTL))
+	}
+
+	namespaces := utils.NewStringSet()
+	for _, ns := range req.Namespaces {
+		if ns == "" {
+			return auth.NewScopedTokenResponseWithMsg(apimodel.Code_InvalidParameter, "namespace is empty")
+		}
+		namespaces.Add(ns)
+	}
+
+	user := svr.cacheMgn.User().GetUserByID(utils.ParseUserID(ctx))
+	if user == nil {
+		return auth.NewScopedTokenResponse(apimodel.Code_NotFoundUser)
+	}
+
+	claims, token, err := issueScopedToken(key, user.ID, ttl, namespaces.ToSlice(), req.ReadOnly)
+	if err != nil {
+		log.Error("[Auth][ScopedToken] sign scoped token", utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewScopedTokenResponse(apimodel.Code_ExecuteException)
+	}
+
+	log.Info("[Auth][ScopedToken] create scoped token", utils.ZapRequestID(requestID),
+		zap.String("id", claims.ID), zap.String("user", user.ID), zap.Int64("expire-at", claims.ExpireAt),
+		zap.Strings("namespaces", claims.Namespaces), zap.Bool("read-only", claims.ReadOnly))
+
+	resp := auth.NewScopedTokenResponse(apimodel.Code_ExecuteSuccess)
+	resp.ID = claims.ID
+	resp.Token = token
+	resp.ExpireAt = claims.ExpireAt
+	resp.Namespaces = claims.Namespaces
+	resp.ReadOnly = claims.ReadOnly
+	return resp
+}
+
+// RevokeScopedToken 吊销临时 token，只有 token 所属用户本人、其主账户以及超级账户可以吊销
+// 吊销记录通过缓存同步到各个节点，因此存在秒级的生效延迟
+func (svr *server) RevokeScopedToken(ctx context.Context,
+	req *auth.RevokeScopedTokenRequest) *auth.ScopedTokenResponse {
+	requestID := utils.ParseRequestID(ctx)
+	if req == nil || req.Token == "" {
+		return auth.NewScopedTokenResponse(apimodel.Code_EmptyRequest)
+	}
+	key, err := scopedTokenKey()
+	if err != nil {
+		return auth.NewScopedTokenResponseWithMsg(apimodel.Code_NotAllowedAccess, err.Error())
+	}
+
+	claims, err := parseScopedToken(key, req.Token, time.Now())
+	if err != nil && !errors.Is(err, model.ErrorTokenExpired) {
+		return auth.NewScopedTokenResponseWithMsg(apimodel.Code_InvalidParameter, err.Error())
+	}
+
+	user := svr.cacheMgn.User().GetUserByID(claims.Subject)
+	if user == nil {
+		return auth.NewScopedTokenResponse(apimodel.Code_NotFoundUser)
+	}
+	if !checkUserViewPermission(ctx, user) {
+		return auth.NewScopedTokenResponse(apimodel.Code_NotAllowedAccess)
+	}
+
+	resp := auth.NewScopedTokenResponse(apimodel.Code_ExecuteSuccess)
+	resp.ID = claims.ID
+	resp.ExpireAt = claims.ExpireAt
+	// 已经过期的 token 无法再使用，不需要记录到吊销列表中
+	if err != nil {
+		return resp
+	}
+
+	owner := user.Owner
+	if owner == "" {
+		owner = user.ID
+	}
+	revoked := &model.RevokedToken{
+		ID:         claims.ID,
+		Principal:  claims.Subject,
+		Owner:      owner,
+		ExpireTime: time.Unix(claims.ExpireAt, 0),
+	}
+	if err := svr.storage.AddRevokedToken(revoked); err != nil {
+		log.Error("[Auth][ScopedToken] save revoked token into store", utils.ZapRequestID(requestID),
+			zap.String("id", claims.ID), zap.Error(err))
+		return auth.NewScopedTokenResponseWithMsg(StoreCode2APICode(err), err.Error())
+	}
+
+	log.Info("[Auth][ScopedToken] revoke scoped token", utils.ZapRequestID(requestID),
+		zap.String("id", claims.ID), zap.String("user", claims.Subject),
+		zap.String("operator", utils.ParseUserID(ctx)))
+	return resp
+}
@@ -0,0 +1,270 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/cache"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	storemock "github.com/polarismesh/polaris/store/mock"
+)
+
+func Test_parseScopedToken(t *testing.T) {
+	key := []byte("polarismesh@2021")
+	now := time.Now()
+	claims := &scopedTokenClaims{
+		ID:         "token-1",
+		Issuer:     scopedTokenIssuer,
+		Subject:    "user-1",
+		IssuedAt:   now.Unix(),
+		ExpireAt:   now.Unix() + 60,
+		Namespaces: []string{"default"},
+		ReadOnly:   true,
+	}
+	token, err := signScopedToken(key, claims)
+	assert.NoError(t, err)
+
+	t.Run("正常解析", func(t *testing.T) {
+		assert.True(t, isScopedToken(token))
+		ret, err := parseScopedToken(key, token, now)
+		assert.NoError(t, err)
+		assert.Equal(t, claims, ret)
+
+		operator := ret.toOperator(token)
+		assert.True(t, operator.IsUserToken)
+		assert.Equal(t, "user-1", operator.OperatorID)
+		assert.Equal(t, "token-1", operator.TokenID)
+		assert.Equal(t, &TokenScope{Namespaces: []string{"default"}, ReadOnly: true}, operator.Scope)
+	})
+
+	t.Run("静态token不是临时token", func(t *testing.T) {
+		AuthOption.Salt = string(key)
+		static, err := createUserToken("user-1")
+		assert.NoError(t, err)
+		assert.False(t, isScopedToken(static))
+	})
+
+	t.Run("签名密钥不一致", func(t *testing.T) {
+		_, err := parseScopedToken([]byte("polarismesh@2022"), token, now)
+		assert.ErrorIs(t, err, model.ErrorTokenInvalid)
+	})
+
+	t.Run("载荷被篡改", func(t *testing.T) {
+		parts := strings.Split(token, ".")
+		forged, _ := signScopedToken([]byte("polarismesh@2022"), &scopedTokenClaims{
+			ID:       "token-1",
+			Issuer:   scopedTokenIssuer,
+			Subject:  "admin",
+			ExpireAt: now.Unix() + 60,
+		})
+		parts[1] = strings.Split(forged, ".")[1]
+		_, err := parseScopedToken(key, strings.Join(parts, "."), now)
+		assert.ErrorIs(t, err, model.ErrorTokenInvalid)
+	})
+
+	t.Run("token已过期", func(t *testing.T) {
+		ret, err := parseScopedToken(key, token, now.Add(time.Minute))
+		assert.ErrorIs(t, err, model.ErrorTokenExpired)
+		assert.Equal(t, "token-1", ret.ID)
+	})
+}
+
+func Test_checkTokenScope(t *testing.T) {
+	newCtx := func(res map[apisecurity.ResourceType][]model.ResourceEntry) *model.AcquireContext {
+		return model.NewAcquireContext(
+			model.WithOperation(model.Modify),
+			model.WithAccessResources(res),
+		)
+	}
+	res := map[apisecurity.ResourceType][]model.ResourceEntry{
+		apisecurity.ResourceType_Services: {{ID: "svc-1", Namespace: "default"}},
+	}
+
+	assert.NoError(t, checkTokenScope(newCtx(res), OperatorInfo{}))
+	assert.ErrorIs(t, checkTokenScope(newCtx(res), OperatorInfo{
+		Scope: &TokenScope{ReadOnly: true},
+	}), model.ErrorTokenOutOfScope)
+	assert.NoError(t, checkTokenScope(newCtx(res), OperatorInfo{
+		Scope: &TokenScope{Namespaces: []string{"default"}},
+	}))
+	assert.ErrorIs(t, checkTokenScope(newCtx(res), OperatorInfo{
+		Scope: &TokenScope{Namespaces: []string{"test"}},
+	}), model.ErrorTokenOutOfScope)
+	// 无法确认资源所属的命名空间
+	assert.ErrorIs(t, checkTokenScope(newCtx(map[apisecurity.ResourceType][]model.ResourceEntry{
+		apisecurity.ResourceType_ConfigGroups: {{ID: "1"}},
+	}), OperatorInfo{
+		Scope: &TokenScope{Namespaces: []string{"default"}},
+	}), model.ErrorTokenOutOfScope)
+	assert.ErrorIs(t, checkTokenScope(newCtx(nil), OperatorInfo{
+		Scope: &TokenScope{Namespaces: []string{"default"}},
+	}), model.ErrorTokenOutOfScope)
+}
+
+func Test_scopedTokenKey(t *testing.T) {
+	reset(true)
+	defer reset(true)
+
+	// 未配置独立的签名密钥
+	_, err := scopedTokenKey()
+	assert.ErrorIs(t, err, errScopedTokenDisabled)
+
+	// salt 仍为公开的默认值
+	AuthOption.ScopedTokenSecret = "polaris-scoped-token-secret-0123456789"
+	_, err = scopedTokenKey()
+	assert.ErrorIs(t, err, errScopedTokenDisabled)
+
+	AuthOption.Salt = "polaris@a7b068ce3235442b"
+	key, err := scopedTokenKey()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(AuthOption.ScopedTokenSecret), key)
+
+	cfg := DefaultAuthConfig()
+	cfg.ScopedTokenSecret = "too-short"
+	assert.Error(t, cfg.Verify())
+	cfg.ScopedTokenSecret = "polaris-scoped-token-secret-0123456789"
+	assert.NoError(t, cfg.Verify())
+}
+
+func Test_defaultAuthChecker_VerifyScopedToken(t *testing.T) {
+	reset(true)
+	defer reset(true)
+	AuthOption.Salt = "polaris@a7b068ce3235442b"
+	AuthOption.ScopedTokenSecret = "polaris-scoped-token-secret-0123456789"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	users := createMockUser(2)
+	now := time.Now()
+	newToken := func(id string, expireAt int64) string {
+		token, err := signScopedToken([]byte(AuthOption.ScopedTokenSecret), &scopedTokenClaims{
+			ID:       id,
+			Issuer:   scopedTokenIssuer,
+			Subject:  users[1].ID,
+			IssuedAt: now.Unix(),
+			ExpireAt: expireAt,
+			ReadOnly: true,
+		})
+		assert.NoError(t, err)
+		return token
+	}
+
+	storage := storemock.NewMockStore(ctrl)
+	storage.EXPECT().GetServicesCount().AnyTimes().Return(uint32(1), nil)
+	storage.EXPECT().GetUnixSecond(gomock.Any()).AnyTimes().Return(time.Now().Unix(), nil)
+	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
+	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return([]*model.UserGroupDetail{}, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return([]*model.RevokedToken{
+		{ID: "revoked", Principal: users[1].ID, ExpireTime: now.Add(time.Hour), ModifyTime: now},
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cacheMgn, err := cache.TestCacheInitialize(ctx, &cache.Config{
+		Open: true,
+		Resources: []cache.ConfigEntry{
+			{
+				Name: "users",
+			},
+		},
+	}, storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cancel()
+		cacheMgn.Clear()
+	}()
+
+	checker := &defaultAuthChecker{cacheMgn: cacheMgn}
+	newAuthCtx := func(token string) *model.AcquireContext {
+		return model.NewAcquireContext(
+			model.WithRequestContext(context.WithValue(context.Background(), utils.ContextAuthTokenKey, token)),
+			model.WithOperation(model.Modify),
+			model.WithModule(model.DiscoverModule),
+		)
+	}
+
+	t.Run("正常的临时token", func(t *testing.T) {
+		authCtx := newAuthCtx(newToken("normal", now.Unix()+60))
+		assert.NoError(t, checker.VerifyCredential(authCtx))
+		operator := authCtx.GetAttachment(model.TokenDetailInfoKey).(OperatorInfo)
+		assert.Equal(t, users[1].ID, operator.OperatorID)
+		assert.Equal(t, users[0].ID, utils.ParseOwnerID(authCtx.GetRequestContext()))
+
+		// 只读 token 不允许写操作
+		_, err := checker.CheckPermission(authCtx)
+		assert.ErrorIs(t, err, model.ErrorTokenOutOfScope)
+	})
+
+	t.Run("单点登录会话允许写操作", func(t *testing.T) {
+		key := []byte(AuthOption.ScopedTokenSecret)
+		_, session, err := issueSessionToken(key, users[1].ID, 60)
+		assert.NoError(t, err)
+		ctx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, session)
+		_, rsp := verifyAuth(ctx, WriteOp, NotOwner, checker)
+		assert.Nil(t, rsp)
+
+		// 用户申请的临时 token 仍然不允许修改鉴权资源
+		_, scoped, err := issueScopedToken(key, users[1].ID, 60, nil, false)
+		assert.NoError(t, err)
+		ctx = context.WithValue(context.Background(), utils.ContextAuthTokenKey, scoped)
+		_, rsp = verifyAuth(ctx, WriteOp, NotOwner, checker)
+		assert.Equal(t, uint32(apimodel.Code_NotAllowedAccess), rsp.GetCode().GetValue())
+	})
+
+	t.Run("已过期的临时token", func(t *testing.T) {
+		err := checker.VerifyCredential(newAuthCtx(newToken("expired", now.Unix()-1)))
+		assert.ErrorIs(t, err, model.ErrorTokenExpired)
+	})
+
+	t.Run("已吊销的临时token", func(t *testing.T) {
+		err := checker.VerifyCredential(newAuthCtx(newToken("revoked", now.Unix()+60)))
+		assert.ErrorIs(t, err, model.ErrorTokenRevoked)
+	})
+
+	t.Run("非严格模式下过期或者吊销的临时token不降级为匿名用户", func(t *testing.T) {
+		AuthOption.Strict = false
+		defer func() {
+			AuthOption.Strict = true
+		}()
+		err := checker.VerifyCredential(newAuthCtx(newToken("expired", now.Unix()-1)))
+		assert.ErrorIs(t, err, model.ErrorTokenExpired)
+		err = checker.VerifyCredential(newAuthCtx(newToken("revoked", now.Unix()+60)))
+		assert.ErrorIs(t, err, model.ErrorTokenRevoked)
+	})
+
+	t.Run("未启用临时token", func(t *testing.T) {
+		token := newToken("normal", now.Unix()+60)
+		AuthOption.ScopedTokenSecret = ""
+		defer func() {
+			AuthOption.ScopedTokenSecret = "polaris-scoped-token-secret-0123456789"
+		}()
+		err := checker.VerifyCredential(newAuthCtx(token))
+		assert.ErrorIs(t, err, errScopedTokenDisabled)
+	})
+}
@@ -39,6 +39,8 @@ type server struct {
 	history  plugin.History
 	cacheMgn *cache.CacheManager
 	authMgn  *defaultAuthChecker
+	oidc     *oidcProvider
+	ldap     *ldapSource
 }
 
 // initialize
@@ -60,14 +62,23 @@ func (svr *server) Login(req *apisecurity.LoginRequest) *apiservice.Response {
 		ownerName = username
 	}
 	user := svr.cacheMgn.User().GetUserByName(username, ownerName)
+	// LDAP 用户登录时可以不指定 owner
+	if user == nil && svr.ldap != nil && req.GetOwner().GetValue() == "" {
+		user = svr.cacheMgn.User().GetUserByName(username, svr.ldap.cfg.Owner)
+	}
 	if user == nil {
 		return api.NewAuthResponse(apimodel.Code_NotFoundUser)
 	}
 
-	// TODO AES 解密操作，在进行密码比对计算
-	err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.GetPassword().GetValue()))
+	var err error
+	if svr.isLDAPUser(user) {
+		err = svr.authenticateLDAPUser(user, req.GetPassword().GetValue())
+	} else {
+		// TODO AES 解密操作，在进行密码比对计算
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.GetPassword().GetValue()))
+	}
 	if err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) || errors.Is(err, errLDAPInvalidCredentials) {
 			return api.NewAuthResponseWithMsg(
 				apimodel.Code_NotAllowedAccess, model.ErrorWrongUsernameOrPassword.Error())
 		}
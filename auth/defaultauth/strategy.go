@@ -320,8 +320,25 @@ func (svr *server) GetStrategy(ctx context.Context, req *apisecurity.AuthStrateg
 		return api.NewAuthStrategyResponse(apimodel.Code_NotFoundAuthStrategyRule, req)
 	}
 
+	if !svr.canViewStrategy(ctx, ret) {
+		log.Error("[Auth][Strategy] get strategy detail denied",
+			utils.ZapRequestID(requestID),
+			zap.String("user", userId),
+			zap.String("strategy", req.Id.Value),
+			zap.Bool("is-owner", isOwner),
+		)
+		return api.NewAuthStrategyResponse(apimodel.Code_NotAllowedAccess, req)
+	}
+
+	return api.NewAuthStrategyResponse(apimodel.Code_ExecuteSuccess, svr.authStrategyFull2Api(ret))
+}
+
+// canViewStrategy 判断当前操作者是否可以查看该鉴权策略
+func (svr *server) canViewStrategy(ctx context.Context, ret *model.StrategyDetail) bool {
+	userId := utils.ParseUserID(ctx)
+
 	var canView bool
-	if isOwner {
+	if utils.ParseIsOwner(ctx) {
 		// 是否是本鉴权策略的 owner 账户, 或者是否是超级管理员, 是的话则快速跳过下面的检查
 		canView = (ret.Owner == userId) || authcommon.ParseUserRole(ctx) == model.AdminUserRole
 	}
@@ -342,21 +359,11 @@ func (svr *server) GetStrategy(ctx context.Context, req *apisecurity.AuthStrateg
 			}
 		}
 	}
-
-	if !canView {
-		log.Error("[Auth][Strategy] get strategy detail denied",
-			utils.ZapRequestID(requestID),
-			zap.String("user", userId),
-			zap.String("strategy", req.Id.Value),
-			zap.Bool("is-owner", isOwner),
-		)
-		return api.NewAuthStrategyResponse(apimodel.Code_NotAllowedAccess, req)
-	}
-
-	return api.NewAuthStrategyResponse(apimodel.Code_ExecuteSuccess, svr.authStrategyFull2Api(ret))
+	return canView
 }
 
 // GetPrincipalResources 获取某个principal可以获取到的所有资源ID数据信息
+// effect 为空或者 ALLOW 时返回实际可操作的资源，为 DENY 时返回拒绝策略中禁止操作的资源
 func (svr *server) GetPrincipalResources(ctx context.Context, query map[string]string) *apiservice.Response {
 	requestID := utils.ParseRequestID(ctx)
 	if len(query) == 0 {
@@ -380,14 +387,19 @@ func (svr *server) GetPrincipalResources(ctx context.Context, query map[string]s
 		return api.NewAuthResponse(apimodel.Code_InvalidPrincipalType)
 	}
 
+	effect, err := model.NormalizeStrategyEffect(query["effect"])
+	if err != nil {
+		return api.NewAuthResponseWithMsg(apimodel.Code_InvalidParameter, err.Error())
+	}
+
 	var (
 		resources = make([]model.StrategyResource, 0, 20)
-		err       error
+		groupIds  []string
 	)
 
 	// 找这个用户所关联的用户组
 	if model.PrincipalType(principalRole) == model.PrincipalUser {
-		groupIds := svr.cacheMgn.User().GetUserLinkGroupIds(principalId)
+		groupIds = svr.cacheMgn.User().GetUserLinkGroupIds(principalId)
 		for i := range groupIds {
 			res, err := svr.storage.GetStrategyResources(groupIds[i], model.PrincipalGroup)
 			if err != nil {
@@ -407,6 +419,8 @@ func (svr *server) GetPrincipalResources(ctx context.Context, query map[string]s
 	}
 
 	resources = append(resources, pResources...)
+	resources = svr.filterPrincipalResources(principalId, model.PrincipalType(principalRole), groupIds,
+		resources, effect)
 	tmp := &apisecurity.AuthStrategy{
 		Resources: &apisecurity.StrategyResources{
 			Namespaces:   make([]*apisecurity.StrategyResourceEntry, 0),
@@ -422,6 +436,67 @@ func (svr *server) GetPrincipalResources(ctx context.Context, query map[string]s
 	return api.NewStrategyResourcesResponse(apimodel.Code_ExecuteSuccess, tmp.Resources)
 }
 
+// filterPrincipalResources 按照鉴权策略的生效方式筛选 principal 关联的资源
+// ALLOW: 剔除被拒绝策略禁止了全部写操作的资源，仅禁止部分操作的资源仍然保留
+// DENY: 只返回拒绝策略中的资源
+func (svr *server) filterPrincipalResources(principalId string, role model.PrincipalType, groupIds []string,
+	resources []model.StrategyResource, effect string) []model.StrategyResource {
+	denyRules := make(map[string]*model.StrategyDetail)
+	collect := func(rules []*model.StrategyDetail) {
+		for i := range rules {
+			if rules[i].IsDeny() {
+				denyRules[rules[i].ID] = rules[i]
+			}
+		}
+	}
+	if role == model.PrincipalUser {
+		collect(svr.cacheMgn.AuthStrategy().GetStrategyDetailsByUID(principalId))
+	} else {
+		collect(svr.cacheMgn.AuthStrategy().GetStrategyDetailsByGroupID(principalId))
+	}
+	for i := range groupIds {
+		collect(svr.cacheMgn.AuthStrategy().GetStrategyDetailsByGroupID(groupIds[i]))
+	}
+
+	allowRes := make([]model.StrategyResource, 0, len(resources))
+	denyRes := make([]model.StrategyResource, 0, 4)
+	for i := range resources {
+		if _, ok := denyRules[resources[i].StrategyID]; ok {
+			denyRes = append(denyRes, resources[i])
+		} else {
+			allowRes = append(allowRes, resources[i])
+		}
+	}
+	if effect == model.StrategyEffectDeny {
+		return denyRes
+	}
+	if len(denyRules) == 0 {
+		return allowRes
+	}
+
+	denied := make(map[string]struct{}, len(denyRes))
+	for _, rule := range denyRules {
+		if !rule.DenyOperation(model.Create) || !rule.DenyOperation(model.Modify) ||
+			!rule.DenyOperation(model.Delete) {
+			continue
+		}
+		for _, res := range rule.Resources {
+			denied[fmt.Sprintf("%d_%s", res.ResType, res.ResID)] = struct{}{}
+		}
+	}
+	ret := make([]model.StrategyResource, 0, len(allowRes))
+	for _, res := range allowRes {
+		if _, ok := denied[fmt.Sprintf("%d_%s", res.ResType, res.ResID)]; ok {
+			continue
+		}
+		if _, ok := denied[fmt.Sprintf("%d_*", res.ResType)]; ok {
+			continue
+		}
+		ret = append(ret, res)
+	}
+	return ret
+}
+
 // enhancedAuthStrategy2Api
 func enhancedAuthStrategy2Api(s []*model.StrategyDetail, fn StrategyDetail2Api) []*apisecurity.AuthStrategy {
 	out := make([]*apisecurity.AuthStrategy, 0, len(s))
@@ -445,13 +520,22 @@ func (svr *server) authStrategy2Api(s *model.StrategyDetail) *apisecurity.AuthSt
 		Comment:         utils.NewStringValue(s.Comment),
 		Ctime:           utils.NewStringValue(commontime.Time2String(s.CreateTime)),
 		Mtime:           utils.NewStringValue(commontime.Time2String(s.ModifyTime)),
-		Action:          apisecurity.AuthAction(apisecurity.AuthAction_value[s.Action]),
+		Action:          authStrategyAction(s),
 		DefaultStrategy: utils.NewBoolValue(s.Default),
 	}
 
 	return out
 }
 
+// authStrategyAction apisecurity.AuthAction 只能表示只读以及读写，按操作授权的策略只要包含写操作即为 READ_WRITE，
+// 具体的操作列表通过 GetStrategyAction 查询
+func authStrategyAction(s *model.StrategyDetail) apisecurity.AuthAction {
+	if s.HasWriteOperation() {
+		return apisecurity.AuthAction_READ_WRITE
+	}
+	return apisecurity.AuthAction_ONLY_READ
+}
+
 // authStrategyFull2Api
 func (svr *server) authStrategyFull2Api(data *model.StrategyDetail) *apisecurity.AuthStrategy {
 	if data == nil {
@@ -477,7 +561,7 @@ func (svr *server) authStrategyFull2Api(data *model.StrategyDetail) *apisecurity
 		Comment:         utils.NewStringValue(data.Comment),
 		Ctime:           utils.NewStringValue(commontime.Time2String(data.CreateTime)),
 		Mtime:           utils.NewStringValue(commontime.Time2String(data.ModifyTime)),
-		Action:          apisecurity.AuthAction(apisecurity.AuthAction_value[data.Action]),
+		Action:          authStrategyAction(data),
 		DefaultStrategy: utils.NewBoolValue(data.Default),
 	}
 
@@ -491,7 +575,8 @@ func (svr *server) createAuthStrategyModel(strategy *apisecurity.AuthStrategy) *
 	ret := &model.StrategyDetail{
 		ID:         utils.NewUUID(),
 		Name:       strategy.Name.GetValue(),
-		Action:     apisecurity.AuthAction_READ_WRITE.String(),
+		Action:     strategy.GetAction().String(),
+		Effect:     model.StrategyEffectAllow,
 		Comment:    strategy.Comment.GetValue(),
 		Default:    false,
 		Owner:      strategy.Owner.GetValue(),
@@ -531,6 +616,7 @@ func (svr *server) updateAuthStrategyAttribute(ctx context.Context, strategy *ap
 		ID:         strategy.Id.GetValue(),
 		Name:       saved.Name,
 		Action:     saved.Action,
+		Effect:     saved.Effect,
 		Comment:    saved.Comment,
 		ModifyTime: time.Now(),
 	}
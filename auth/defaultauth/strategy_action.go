@@ -0,0 +1,243 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/auth"
+	authcommon "github.com/polarismesh/polaris/common/auth"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// UpdateStrategyAction 修改鉴权策略的动作以及生效方式
+// Case 1. 鉴权策略只能被自己的 owner 对应的用户或者管理员修改
+// Case 2. 默认鉴权策略固定为读写的授权策略，不允许修改
+// Case 3. effect 为空时保持原有的生效方式，修改成功后返回与其他策略之间的冲突信息
+func (svr *server) UpdateStrategyAction(ctx context.Context, req *auth.StrategyAction) *auth.StrategyActionResponse {
+	requestID := utils.ParseRequestID(ctx)
+	if req == nil || req.ID == "" {
+		return auth.NewStrategyActionResponseWithMsg(apimodel.Code_InvalidParameter, "strategy id is empty")
+	}
+	if req.Action == "" {
+		return auth.NewStrategyActionResponseWithMsg(apimodel.Code_InvalidParameter, "strategy action is empty")
+	}
+	action, err := model.NormalizeStrategyAction(req.Action)
+	if err != nil {
+		return auth.NewStrategyActionResponseWithMsg(apimodel.Code_InvalidParameter, err.Error())
+	}
+	var effect string
+	if req.Effect != "" {
+		if effect, err = model.NormalizeStrategyEffect(req.Effect); err != nil {
+			return auth.NewStrategyActionResponseWithMsg(apimodel.Code_InvalidParameter, err.Error())
+		}
+	}
+
+	saved, err := svr.storage.GetStrategyDetail(req.ID)
+	if err != nil {
+		log.Error("[Auth][Strategy] get strategy from store", utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewStrategyActionResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if saved == nil {
+		return auth.NewStrategyActionResponse(apimodel.Code_NotFoundAuthStrategyRule, nil)
+	}
+
+	userId := utils.ParseUserID(ctx)
+	if authcommon.ParseUserRole(ctx) != model.AdminUserRole {
+		if !utils.ParseIsOwner(ctx) || userId != saved.Owner {
+			log.Error("[Auth][Strategy] modify strategy action denied, current user not owner",
+				utils.ZapRequestID(requestID), zap.String("user", userId),
+				zap.String("owner", saved.Owner), zap.String("strategy", saved.ID))
+			return auth.NewStrategyActionResponse(apimodel.Code_NotAllowedAccess, nil)
+		}
+	}
+	if saved.Default {
+		return auth.NewStrategyActionResponseWithMsg(apimodel.Code_BadRequest,
+			"default strategy action can't modify")
+	}
+	if effect == "" {
+		effect = saved.Effect
+	}
+	if saved.Action == action && saved.Effect == effect {
+		return auth.NewStrategyActionResponse(apimodel.Code_NoNeedUpdate, strategyAction2Api(saved))
+	}
+
+	data := &model.ModifyStrategyDetail{
+		ID:         saved.ID,
+		Name:       saved.Name,
+		Action:     action,
+		Effect:     effect,
+		Comment:    saved.Comment,
+		ModifyTime: time.Now(),
+	}
+	if err := svr.storage.UpdateStrategy(data); err != nil {
+		log.Error("[Auth][Strategy] update strategy action into store",
+			utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewStrategyActionResponseWithMsg(StoreCode2APICode(err), err.Error())
+	}
+
+	log.Info("[Auth][Strategy] update strategy action into store", utils.ZapRequestID(requestID),
+		zap.String("name", saved.Name), zap.String("action", action), zap.String("effect", effect))
+	saved.Action = action
+	saved.Effect = effect
+	svr.RecordHistory(strategyActionRecordEntry(ctx, saved))
+
+	resp := auth.NewStrategyActionResponse(apimodel.Code_ExecuteSuccess, strategyAction2Api(saved))
+	resp.Conflicts = svr.computeStrategyConflicts(saved)
+	return resp
+}
+
+// GetStrategyAction 查询鉴权策略的动作，可查看的范围与 GetStrategy 保持一致
+func (svr *server) GetStrategyAction(ctx context.Context, id string) *auth.StrategyActionResponse {
+	requestID := utils.ParseRequestID(ctx)
+	if id == "" {
+		return auth.NewStrategyActionResponse(apimodel.Code_EmptyQueryParameter, nil)
+	}
+
+	ret, err := svr.storage.GetStrategyDetail(id)
+	if err != nil {
+		log.Error("[Auth][Strategy] get strategy from store", utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewStrategyActionResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if ret == nil {
+		return auth.NewStrategyActionResponse(apimodel.Code_NotFoundAuthStrategyRule, nil)
+	}
+	if !svr.canViewStrategy(ctx, ret) {
+		return auth.NewStrategyActionResponse(apimodel.Code_NotAllowedAccess, nil)
+	}
+
+	return auth.NewStrategyActionResponse(apimodel.Code_ExecuteSuccess, strategyAction2Api(ret))
+}
+
+// GetStrategyConflicts 查询与该鉴权策略存在 ALLOW/DENY 冲突的信息，只会计算同一个 owner 下的鉴权策略
+func (svr *server) GetStrategyConflicts(ctx context.Context, id string) *auth.StrategyConflictsResponse {
+	requestID := utils.ParseRequestID(ctx)
+	if id == "" {
+		return auth.NewStrategyConflictsResponse(apimodel.Code_EmptyQueryParameter, nil)
+	}
+
+	ret, err := svr.storage.GetStrategyDetail(id)
+	if err != nil {
+		log.Error("[Auth][Strategy] get strategy from store", utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewStrategyConflictsResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if ret == nil {
+		return auth.NewStrategyConflictsResponse(apimodel.Code_NotFoundAuthStrategyRule, nil)
+	}
+	if !svr.canViewStrategy(ctx, ret) {
+		return auth.NewStrategyConflictsResponse(apimodel.Code_NotAllowedAccess, nil)
+	}
+
+	return auth.NewStrategyConflictsResponse(apimodel.Code_ExecuteSuccess, svr.computeStrategyConflicts(ret))
+}
+
+// computeStrategyConflicts 计算鉴权策略的冲突信息
+func (svr *server) computeStrategyConflicts(rule *model.StrategyDetail) []*auth.StrategyConflict {
+	conflicts := svr.cacheMgn.AuthStrategy().GetStrategyConflicts(rule)
+	ret := make([]*auth.StrategyConflict, 0, len(conflicts))
+	for i := range conflicts {
+		item := conflicts[i]
+		if item.Allow.Owner != item.Deny.Owner {
+			continue
+		}
+		ops := make([]string, 0, len(item.Operations))
+		for _, op := range item.Operations {
+			ops = append(ops, op.String())
+		}
+		ret = append(ret, &auth.StrategyConflict{
+			AllowStrategyID:   item.Allow.ID,
+			AllowStrategyName: item.Allow.Name,
+			DenyStrategyID:    item.Deny.ID,
+			DenyStrategyName:  item.Deny.Name,
+			PrincipalID:       item.Principal.PrincipalID,
+			PrincipalType:     principalTypeName(item.Principal.PrincipalRole),
+			ResType:           resTypeName(item.ResType),
+			ResID:             item.ResID,
+			Operations:        ops,
+		})
+	}
+	return ret
+}
+
+func principalTypeName(role model.PrincipalType) string {
+	if role == model.PrincipalUser {
+		return "user"
+	}
+	return "group"
+}
+
+func resTypeName(resType int32) string {
+	switch apisecurity.ResourceType(resType) {
+	case apisecurity.ResourceType_Namespaces:
+		return "namespace"
+	case apisecurity.ResourceType_Services:
+		return "service"
+	case apisecurity.ResourceType_ConfigGroups:
+		return "config_group"
+	default:
+		if name, ok := model.RuleResourceTypeNames[model.RuleResourceType(resType)]; ok {
+			return name
+		}
+		return strconv.Itoa(int(resType))
+	}
+}
+
+// strategyAction2Api 将鉴权策略的动作转换为对外的结构，并展开其覆盖的操作列表
+func strategyAction2Api(s *model.StrategyDetail) *auth.StrategyAction {
+	action := s.Action
+	if action == "" {
+		action = model.ActionReadWrite
+	}
+	effect := model.StrategyEffectAllow
+	if s.IsDeny() {
+		effect = model.StrategyEffectDeny
+	}
+	ret := &auth.StrategyAction{
+		ID:         s.ID,
+		Name:       s.Name,
+		Action:     action,
+		Effect:     effect,
+		Operations: make([]string, 0, 4),
+	}
+	for _, op := range s.Operations() {
+		ret.Operations = append(ret.Operations, op.String())
+	}
+	return ret
+}
+
+// strategyActionRecordEntry 鉴权策略动作变更的操作记录
+func strategyActionRecordEntry(ctx context.Context, s *model.StrategyDetail) *model.RecordEntry {
+	detail, _ := json.Marshal(strategyAction2Api(s))
+	return &model.RecordEntry{
+		ResourceType:  model.RAuthStrategy,
+		ResourceName:  fmt.Sprintf("%s(%s)", s.Name, s.ID),
+		OperationType: model.OUpdate,
+		Operator:      utils.ParseOperator(ctx),
+		Detail:        string(detail),
+		HappenTime:    time.Now(),
+	}
+}
@@ -133,6 +133,63 @@ func (svr *strategyAuthAbility) GetPrincipalResources(ctx context.Context, query
 	return svr.target.GetPrincipalResources(ctx, query)
 }
 
+// UpdateStrategyAction update strategy action.
+func (svr *strategyAuthAbility) UpdateStrategyAction(ctx context.Context,
+	req *auth.StrategyAction) *auth.StrategyActionResponse {
+	ctx, rsp := verifyAuth(ctx, WriteOp, MustOwner, svr.authMgn)
+	if rsp != nil {
+		return auth.NewStrategyActionResponseWithMsg(apimodel.Code(rsp.GetCode().GetValue()), rsp.GetInfo().GetValue())
+	}
+
+	return svr.target.UpdateStrategyAction(ctx, req)
+}
+
+// GetStrategyAction get strategy action.
+func (svr *strategyAuthAbility) GetStrategyAction(ctx context.Context, id string) *auth.StrategyActionResponse {
+	ctx, rsp := verifyAuth(ctx, ReadOp, NotOwner, svr.authMgn)
+	if rsp != nil {
+		return auth.NewStrategyActionResponseWithMsg(apimodel.Code(rsp.GetCode().GetValue()), rsp.GetInfo().GetValue())
+	}
+
+	return svr.target.GetStrategyAction(ctx, id)
+}
+
+// GetStrategyConflicts get strategy conflicts.
+func (svr *strategyAuthAbility) GetStrategyConflicts(ctx context.Context,
+	id string) *auth.StrategyConflictsResponse {
+	ctx, rsp := verifyAuth(ctx, ReadOp, NotOwner, svr.authMgn)
+	if rsp != nil {
+		return auth.NewStrategyConflictsResponseWithMsg(apimodel.Code(rsp.GetCode().GetValue()),
+			rsp.GetInfo().GetValue())
+	}
+
+	return svr.target.GetStrategyConflicts(ctx, id)
+}
+
+// UpdateStrategyRules update strategy rule resources.
+func (svr *strategyAuthAbility) UpdateStrategyRules(ctx context.Context,
+	req *auth.StrategyRuleResources) *auth.StrategyRuleResourcesResponse {
+	ctx, rsp := verifyAuth(ctx, WriteOp, MustOwner, svr.authMgn)
+	if rsp != nil {
+		return auth.NewStrategyRuleResourcesResponseWithMsg(apimodel.Code(rsp.GetCode().GetValue()),
+			rsp.GetInfo().GetValue())
+	}
+
+	return svr.target.UpdateStrategyRules(ctx, req)
+}
+
+// GetStrategyRules get strategy rule resources.
+func (svr *strategyAuthAbility) GetStrategyRules(ctx context.Context,
+	id string) *auth.StrategyRuleResourcesResponse {
+	ctx, rsp := verifyAuth(ctx, ReadOp, NotOwner, svr.authMgn)
+	if rsp != nil {
+		return auth.NewStrategyRuleResourcesResponseWithMsg(apimodel.Code(rsp.GetCode().GetValue()),
+			rsp.GetInfo().GetValue())
+	}
+
+	return svr.target.GetStrategyRules(ctx, id)
+}
+
 // GetAuthChecker 获取鉴权管理器
 func (svr *strategyAuthAbility) GetAuthChecker() auth.AuthChecker {
 	return svr.authMgn
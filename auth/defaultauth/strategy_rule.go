@@ -0,0 +1,245 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package defaultauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/auth"
+	authcommon "github.com/polarismesh/polaris/common/auth"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// maxRuleResourceIDLen 与 auth_strategy_resource.res_id 的字段长度保持一致
+	maxRuleResourceIDLen = 128
+)
+
+// UpdateStrategyRules 修改鉴权策略中的治理规则资源
+// Case 1. 鉴权策略只能被自己的 owner 对应的用户或者管理员修改
+// Case 2. 默认鉴权策略不允许关联治理规则资源
+// Case 3. 已经存在的规则资源不会重复添加，不存在的规则资源忽略删除
+func (svr *server) UpdateStrategyRules(ctx context.Context,
+	req *auth.StrategyRuleResources) *auth.StrategyRuleResourcesResponse {
+	requestID := utils.ParseRequestID(ctx)
+	if req == nil || req.ID == "" {
+		return auth.NewStrategyRuleResourcesResponseWithMsg(apimodel.Code_InvalidParameter, "strategy id is empty")
+	}
+	addRes, err := parseStrategyRuleResources(req.ID, req.AddRules)
+	if err != nil {
+		return auth.NewStrategyRuleResourcesResponseWithMsg(apimodel.Code_InvalidParameter, err.Error())
+	}
+	removeRes, err := parseStrategyRuleResources(req.ID, req.RemoveRules)
+	if err != nil {
+		return auth.NewStrategyRuleResourcesResponseWithMsg(apimodel.Code_InvalidParameter, err.Error())
+	}
+
+	saved, err := svr.storage.GetStrategyDetail(req.ID)
+	if err != nil {
+		log.Error("[Auth][Strategy] get strategy from store", utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewStrategyRuleResourcesResponse(apimodel.Code_StoreLayerException, req.ID, nil)
+	}
+	if saved == nil {
+		return auth.NewStrategyRuleResourcesResponse(apimodel.Code_NotFoundAuthStrategyRule, req.ID, nil)
+	}
+
+	userId := utils.ParseUserID(ctx)
+	if authcommon.ParseUserRole(ctx) != model.AdminUserRole {
+		if !utils.ParseIsOwner(ctx) || userId != saved.Owner {
+			log.Error("[Auth][Strategy] modify strategy rules denied, current user not owner",
+				utils.ZapRequestID(requestID), zap.String("user", userId),
+				zap.String("owner", saved.Owner), zap.String("strategy", saved.ID))
+			return auth.NewStrategyRuleResourcesResponse(apimodel.Code_NotAllowedAccess, req.ID, nil)
+		}
+	}
+	if saved.Default {
+		return auth.NewStrategyRuleResourcesResponseWithMsg(apimodel.Code_BadRequest,
+			"default strategy can't link rule resources")
+	}
+
+	exists := make(map[string]struct{}, len(saved.Resources))
+	for _, res := range saved.Resources {
+		exists[fmt.Sprintf("%d_%s", res.ResType, res.ResID)] = struct{}{}
+	}
+	data := &model.ModifyStrategyDetail{
+		ID:              saved.ID,
+		Name:            saved.Name,
+		Action:          saved.Action,
+		Effect:          saved.Effect,
+		Comment:         saved.Comment,
+		AddResources:    make([]model.StrategyResource, 0, len(addRes)),
+		RemoveResources: make([]model.StrategyResource, 0, len(removeRes)),
+		ModifyTime:      time.Now(),
+	}
+	for _, res := range addRes {
+		if _, ok := exists[fmt.Sprintf("%d_%s", res.ResType, res.ResID)]; !ok {
+			data.AddResources = append(data.AddResources, res)
+		}
+	}
+	for _, res := range removeRes {
+		if _, ok := exists[fmt.Sprintf("%d_%s", res.ResType, res.ResID)]; ok {
+			data.RemoveResources = append(data.RemoveResources, res)
+		}
+	}
+	if len(data.AddResources) == 0 && len(data.RemoveResources) == 0 {
+		return auth.NewStrategyRuleResourcesResponse(apimodel.Code_NoNeedUpdate, saved.ID,
+			strategyRules2Api(saved.Resources))
+	}
+
+	if err := svr.storage.UpdateStrategy(data); err != nil {
+		log.Error("[Auth][Strategy] update strategy rules into store",
+			utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewStrategyRuleResourcesResponseWithMsg(StoreCode2APICode(err), err.Error())
+	}
+
+	log.Info("[Auth][Strategy] update strategy rules into store", utils.ZapRequestID(requestID),
+		zap.String("name", saved.Name), zap.Int("add", len(data.AddResources)),
+		zap.Int("remove", len(data.RemoveResources)))
+
+	resources := computeStrategyRuleResources(saved.Resources, data.AddResources, data.RemoveResources)
+	svr.RecordHistory(strategyRulesRecordEntry(ctx, saved, resources))
+	return auth.NewStrategyRuleResourcesResponse(apimodel.Code_ExecuteSuccess, saved.ID, strategyRules2Api(resources))
+}
+
+// GetStrategyRules 查询鉴权策略中的治理规则资源，可查看的范围与 GetStrategy 保持一致
+func (svr *server) GetStrategyRules(ctx context.Context, id string) *auth.StrategyRuleResourcesResponse {
+	requestID := utils.ParseRequestID(ctx)
+	if id == "" {
+		return auth.NewStrategyRuleResourcesResponse(apimodel.Code_EmptyQueryParameter, id, nil)
+	}
+
+	ret, err := svr.storage.GetStrategyDetail(id)
+	if err != nil {
+		log.Error("[Auth][Strategy] get strategy from store", utils.ZapRequestID(requestID), zap.Error(err))
+		return auth.NewStrategyRuleResourcesResponse(apimodel.Code_StoreLayerException, id, nil)
+	}
+	if ret == nil {
+		return auth.NewStrategyRuleResourcesResponse(apimodel.Code_NotFoundAuthStrategyRule, id, nil)
+	}
+	if !svr.canViewStrategy(ctx, ret) {
+		return auth.NewStrategyRuleResourcesResponse(apimodel.Code_NotAllowedAccess, id, nil)
+	}
+
+	return auth.NewStrategyRuleResourcesResponse(apimodel.Code_ExecuteSuccess, id, strategyRules2Api(ret.Resources))
+}
+
+// parseStrategyRuleResources 将治理规则资源转换为策略资源，name 转换为 name:<pattern> 格式的资源ID
+func parseStrategyRuleResources(strategyId string,
+	rules []*auth.StrategyRuleResource) ([]model.StrategyResource, error) {
+	ret := make([]model.StrategyResource, 0, len(rules))
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		resType, err := model.ParseRuleResourceType(rule.Type)
+		if err != nil {
+			return nil, err
+		}
+		if (rule.ID == "") == (rule.Name == "") {
+			return nil, fmt.Errorf("one of id and name must be set for %s rule resource", rule.Type)
+		}
+		if strings.HasPrefix(rule.ID, model.RuleResourceNamePrefix) {
+			return nil, fmt.Errorf("rule id can't start with %s", model.RuleResourceNamePrefix)
+		}
+		resId := rule.ID
+		if rule.Name != "" {
+			resId = model.RuleResourceNamePrefix + rule.Name
+		}
+		if err := model.CheckRuleResourceID(resId); err != nil {
+			return nil, err
+		}
+		if len(resId) > maxRuleResourceIDLen {
+			return nil, fmt.Errorf("rule resource %s is too long", resId)
+		}
+		ret = append(ret, model.StrategyResource{
+			StrategyID: strategyId,
+			ResType:    int32(resType),
+			ResID:      resId,
+		})
+	}
+	return ret, nil
+}
+
+// computeStrategyRuleResources 计算修改后鉴权策略的资源列表
+func computeStrategyRuleResources(saved, add, remove []model.StrategyResource) []model.StrategyResource {
+	removed := make(map[string]struct{}, len(remove))
+	for _, res := range remove {
+		removed[fmt.Sprintf("%d_%s", res.ResType, res.ResID)] = struct{}{}
+	}
+	ret := make([]model.StrategyResource, 0, len(saved)+len(add))
+	for _, res := range saved {
+		if _, ok := removed[fmt.Sprintf("%d_%s", res.ResType, res.ResID)]; !ok {
+			ret = append(ret, res)
+		}
+	}
+	return append(ret, add...)
+}
+
+// strategyRules2Api 筛选出鉴权策略中的治理规则资源，按照类型以及资源ID排序
+func strategyRules2Api(resources []model.StrategyResource) []*auth.StrategyRuleResource {
+	rules := make([]model.StrategyResource, 0, len(resources))
+	for _, res := range resources {
+		if model.IsRuleResourceType(res.ResType) {
+			rules = append(rules, res)
+		}
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].ResType != rules[j].ResType {
+			return rules[i].ResType < rules[j].ResType
+		}
+		return rules[i].ResID < rules[j].ResID
+	})
+
+	ret := make([]*auth.StrategyRuleResource, 0, len(rules))
+	for _, res := range rules {
+		item := &auth.StrategyRuleResource{
+			Type: model.RuleResourceTypeNames[model.RuleResourceType(res.ResType)],
+			ID:   res.ResID,
+		}
+		if strings.HasPrefix(res.ResID, model.RuleResourceNamePrefix) {
+			item.ID = ""
+			item.Name = strings.TrimPrefix(res.ResID, model.RuleResourceNamePrefix)
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// strategyRulesRecordEntry 鉴权策略治理规则资源变更的操作记录
+func strategyRulesRecordEntry(ctx context.Context, s *model.StrategyDetail,
+	resources []model.StrategyResource) *model.RecordEntry {
+	detail, _ := json.Marshal(strategyRules2Api(resources))
+	return &model.RecordEntry{
+		ResourceType:  model.RAuthStrategy,
+		ResourceName:  fmt.Sprintf("%s(%s)", s.Name, s.ID),
+		OperationType: model.OUpdate,
+		Operator:      utils.ParseOperator(ctx),
+		Detail:        string(detail),
+		HappenTime:    time.Now(),
+	}
+}
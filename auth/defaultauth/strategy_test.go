@@ -85,6 +85,7 @@ func newStrategyTest(t *testing.T) *StrategyTest {
 	storage.EXPECT().GetUnixSecond(gomock.Any()).AnyTimes().Return(time.Now().Unix(), nil)
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(users, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 	storage.EXPECT().GetStrategyDetailsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(allStrategies, nil)
 	storage.EXPECT().GetMoreNamespaces(gomock.Any()).AnyTimes().Return(namespaces, nil)
 	storage.EXPECT().GetMoreServices(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(serviceMap, nil)
@@ -731,6 +732,34 @@ func Test_GetStrategy(t *testing.T) {
 
 }
 
+func Test_authStrategyAction(t *testing.T) {
+	svr := &server{}
+
+	data := svr.createAuthStrategyModel(&apisecurity.AuthStrategy{
+		Name:   utils.NewStringValue("only-read"),
+		Action: apisecurity.AuthAction_ONLY_READ,
+	})
+	assert.Equal(t, model.ActionOnlyRead, data.Action)
+	data = svr.createAuthStrategyModel(&apisecurity.AuthStrategy{
+		Name:   utils.NewStringValue("read-write"),
+		Action: apisecurity.AuthAction_READ_WRITE,
+	})
+	assert.Equal(t, model.ActionReadWrite, data.Action)
+
+	for action, expect := range map[string]apisecurity.AuthAction{
+		"":                    apisecurity.AuthAction_READ_WRITE,
+		model.ActionOnlyRead:  apisecurity.AuthAction_ONLY_READ,
+		model.ActionReadWrite: apisecurity.AuthAction_READ_WRITE,
+		"READ,CREATE":         apisecurity.AuthAction_READ_WRITE,
+		"READ,MODIFY,DELETE":  apisecurity.AuthAction_READ_WRITE,
+		"READ":                apisecurity.AuthAction_ONLY_READ,
+		"UNKNOWN":             apisecurity.AuthAction_ONLY_READ,
+	} {
+		detail := &model.StrategyDetail{ID: "rule-1", Action: action}
+		assert.Equal(t, expect, svr.authStrategy2Api(detail).GetAction(), action)
+	}
+}
+
 func Test_parseStrategySearchArgs(t *testing.T) {
 	type args struct {
 		ctx           context.Context
@@ -54,6 +54,42 @@ type OperatorInfo struct {
 
 	// 是否属于匿名操作者
 	Anonymous bool
+
+	// TokenID 临时 token 的唯一标识，静态 token 为空
+	TokenID string
+
+	// ExpireAt 临时 token 的过期时间，静态 token 为 0
+	ExpireAt int64
+
+	// Scope 临时 token 的授权范围，为 nil 表示不做额外限制
+	Scope *TokenScope
+
+	// Session 是否为单点登录签发的登录会话
+	Session bool
+
+	// CertIdentity 通过 mTLS 客户端证书认证时证书的身份（SPIFFE ID 或者 CN），token 认证时为空
+	CertIdentity string
+}
+
+// TokenScope 临时 token 的授权范围
+type TokenScope struct {
+	// Namespaces 允许访问的命名空间，为空表示不限制命名空间
+	Namespaces []string
+	// ReadOnly 是否只允许读操作
+	ReadOnly bool
+}
+
+// AllowNamespace 判断命名空间是否在授权范围内
+func (s *TokenScope) AllowNamespace(namespace string) bool {
+	if s == nil || len(s.Namespaces) == 0 {
+		return true
+	}
+	for i := range s.Namespaces {
+		if s.Namespaces[i] == namespace {
+			return true
+		}
+	}
+	return false
 }
 
 func newAnonymous() OperatorInfo {
@@ -75,7 +111,16 @@ func IsSubAccount(t OperatorInfo) bool {
 	return t.Role == model.SubAccountUserRole
 }
 
+// IsScopedToken 是否为用户申请的临时 token，单点登录的会话虽然同样带有 TokenID，但是等同于用户本人登录
+func (t *OperatorInfo) IsScopedToken() bool {
+	return t.TokenID != "" && !t.Session
+}
+
 func (t *OperatorInfo) String() string {
+	if t.TokenID != "" {
+		return fmt.Sprintf("operator-id=%s, owner=%s, role=%d, is-user=%v, disable=%v, token-id=%s, expire-at=%d",
+			t.OperatorID, t.OwnerID, t.Role, t.IsUserToken, t.Disable, t.TokenID, t.ExpireAt)
+	}
 	return fmt.Sprintf("operator-id=%s, owner=%s, role=%d, is-user=%v, disable=%v",
 		t.OperatorID, t.OwnerID, t.Role, t.IsUserToken, t.Disable)
 }
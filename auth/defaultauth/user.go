@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
@@ -160,6 +161,11 @@ func (svr *server) UpdateUser(ctx context.Context, req *apisecurity.User) *apise
 	if !checkUserViewPermission(ctx, user) {
 		return api.NewAuthResponse(apimodel.Code_NotAllowedAccess)
 	}
+	// LDAP 用户的邮箱、手机号由 LDAP 同步
+	if svr.isLDAPUser(user) && ((req.Email != nil && req.Email.GetValue() != user.Email) ||
+		(req.Mobile != nil && req.Mobile.GetValue() != user.Mobile)) {
+		return api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, ErrorLDAPSyncedField.Error())
+	}
 
 	data, needUpdate, err := updateUserAttribute(user, req)
 	if err != nil {
@@ -202,6 +208,10 @@ func (svr *server) UpdateUserPassword(ctx context.Context, req *apisecurity.Modi
 	if !checkUserViewPermission(ctx, user) {
 		return api.NewAuthResponse(apimodel.Code_NotAllowedAccess)
 	}
+	// LDAP 用户通过目录认证，密码需要在 LDAP 中修改
+	if svr.isLDAPUser(user) {
+		return api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, ErrorLDAPSyncedField.Error())
+	}
 
 	ignoreOrigin := authcommon.ParseUserRole(ctx) == model.AdminUserRole ||
 		authcommon.ParseUserRole(ctx) == model.OwnerUserRole
@@ -571,6 +581,15 @@ func checkCreateUser(req *apisecurity.User) *apiservice.Response {
 		return api.NewUserResponse(apimodel.Code_InvalidUserEmail, req)
 	}
 
+	// LDAP 用户只能由目录同步创建，否则会走 LDAP 认证并且被同步任务清理
+	if strings.EqualFold(req.GetSource().GetValue(), LDAPUserSource) {
+		return api.NewUserResponseWithMsg(apimodel.Code_BadRequest, "user source LDAP is reserved", req)
+	}
+	// OIDC 用户只能由单点登录创建，否则会被 IdP 中的同名用户登录
+	if strings.EqualFold(req.GetSource().GetValue(), OIDCUserSource) {
+		return api.NewUserResponseWithMsg(apimodel.Code_BadRequest, "user source OIDC is reserved", req)
+	}
+
 	return nil
 }
 
@@ -19,6 +19,8 @@ package defaultauth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
 	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
@@ -55,6 +57,20 @@ func (svr *userAuthAbility) Initialize(authOpt *auth.Config, storage store.Store
 		cacheMgn: cacheMgn,
 		authMgn:  authMgn,
 	}
+	if AuthOption.OIDC != nil {
+		// 单点登录只签发带有效期的临时 token，不下发用户永久有效的静态 token
+		if _, err := scopedTokenKey(); err != nil {
+			return fmt.Errorf("[Auth][OIDC] oidc login requires scoped token: %w", err)
+		}
+		if AuthOption.OIDC.StateSecret == AuthOption.Salt || AuthOption.OIDC.StateSecret == AuthOption.ScopedTokenSecret {
+			return errors.New("[Auth][OIDC] stateSecret must not be the same as salt or scopedTokenSecret")
+		}
+		svr.target.oidc = newOIDCProvider(AuthOption.OIDC)
+	}
+	if AuthOption.LDAP != nil {
+		svr.target.ldap = newLDAPSource(AuthOption.LDAP)
+		svr.target.runLDAPSync(context.Background())
+	}
 	svr.groupAuthAbility = &groupAuthAbility{
 		authMgn: svr.authMgn,
 		target:  svr.target,
@@ -146,6 +162,9 @@ func (svr *userAuthAbility) GetUserToken(ctx context.Context, user *apisecurity.
 	if rsp != nil {
 		return rsp
 	}
+	if rsp := rejectScopedToken(ctx); rsp != nil {
+		return rsp
+	}
 
 	return svr.target.GetUserToken(ctx, user)
 }
@@ -176,3 +195,37 @@ func (svr *userAuthAbility) ResetUserToken(ctx context.Context, user *apisecurit
 func (svr *userAuthAbility) Login(req *apisecurity.LoginRequest) *apiservice.Response {
 	return svr.target.Login(req)
 }
+
+// CreateScopedToken 为当前用户签发临时 token，临时 token 不能用于再次申请
+func (svr *userAuthAbility) CreateScopedToken(ctx context.Context,
+	req *auth.ScopedTokenRequest) *auth.ScopedTokenResponse {
+	ctx, rsp := verifyAuth(ctx, WriteOp, NotOwner, svr.authMgn)
+	if rsp != nil {
+		return auth.NewScopedTokenResponseWithMsg(apimodel.Code(rsp.GetCode().GetValue()),
+			rsp.GetInfo().GetValue())
+	}
+
+	return svr.target.CreateScopedToken(ctx, req)
+}
+
+// RevokeScopedToken 吊销临时 token
+func (svr *userAuthAbility) RevokeScopedToken(ctx context.Context,
+	req *auth.RevokeScopedTokenRequest) *auth.ScopedTokenResponse {
+	ctx, rsp := verifyAuth(ctx, WriteOp, NotOwner, svr.authMgn)
+	if rsp != nil {
+		return auth.NewScopedTokenResponseWithMsg(apimodel.Code(rsp.GetCode().GetValue()),
+			rsp.GetInfo().GetValue())
+	}
+
+	return svr.target.RevokeScopedToken(ctx, req)
+}
+
+// OIDCLoginURL 生成 OIDC 单点登录的授权地址
+func (svr *userAuthAbility) OIDCLoginURL(ctx context.Context) (*auth.OIDCAuthorization, error) {
+	return svr.target.OIDCLoginURL(ctx)
+}
+
+// OIDCCallback 处理 OIDC 单点登录的授权回调
+func (svr *userAuthAbility) OIDCCallback(ctx context.Context, code, state, nonce string) *apiservice.Response {
+	return svr.target.OIDCCallback(ctx, code, state, nonce)
+}
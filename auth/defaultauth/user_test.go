@@ -84,6 +84,7 @@ func newUserTest(t *testing.T) *UserTest {
 
 	storage.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(allUsers, nil)
 	storage.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(groups, nil)
+	storage.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
 	storage.EXPECT().UpdateUser(gomock.Any()).AnyTimes().Return(nil)
 	storage.EXPECT().DeleteUser(gomock.Any()).AnyTimes().Return(nil)
 
@@ -196,6 +197,40 @@ func Test_server_CreateUsers(t *testing.T) {
 		assert.Equal(t, api.InvalidUserPassword, resp.Responses[0].Code.GetValue(), "create users must fail")
 	})
 
+	t.Run("主账户创建账户-LDAP来源-失败", func(t *testing.T) {
+		createUsersReq := []*apisecurity.User{
+			{
+				Id:       &wrappers.StringValue{Value: utils.NewUUID()},
+				Name:     &wrappers.StringValue{Value: "create-user-ldap"},
+				Password: &wrappers.StringValue{Value: "create-user-ldap"},
+				Source:   &wrappers.StringValue{Value: "ldap"},
+			},
+		}
+
+		reqCtx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, userTest.ownerOne.Token)
+		resp := userTest.svr.CreateUsers(reqCtx, createUsersReq)
+
+		t.Logf("CreateUsers resp : %+v", resp)
+		assert.Equal(t, api.BadRequest, resp.Responses[0].Code.GetValue(), "create users must fail")
+	})
+
+	t.Run("主账户创建账户-OIDC来源-失败", func(t *testing.T) {
+		createUsersReq := []*apisecurity.User{
+			{
+				Id:       &wrappers.StringValue{Value: utils.NewUUID()},
+				Name:     &wrappers.StringValue{Value: "create-user-oidc"},
+				Password: &wrappers.StringValue{Value: "create-user-oidc"},
+				Source:   &wrappers.StringValue{Value: "oidc"},
+			},
+		}
+
+		reqCtx := context.WithValue(context.Background(), utils.ContextAuthTokenKey, userTest.ownerOne.Token)
+		resp := userTest.svr.CreateUsers(reqCtx, createUsersReq)
+
+		t.Logf("CreateUsers resp : %+v", resp)
+		assert.Equal(t, api.BadRequest, resp.Responses[0].Code.GetValue(), "create users must fail")
+	})
+
 	t.Run("主账户创建账户-同名用户-失败", func(t *testing.T) {
 		createUsersReq := []*apisecurity.User{
 			{
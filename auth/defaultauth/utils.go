@@ -204,6 +204,13 @@ func verifyAuth(ctx context.Context, isWrite bool,
 		return nil, api.NewAuthResponse(apimodel.Code_OperationRoleForbidden)
 	}
 
+	// 临时 token 不允许修改用户、用户组以及鉴权策略，也不允许再次申请临时 token
+	if isWrite && tokenInfo.IsScopedToken() {
+		log.Error("[Auth][Server] scoped token can not modify auth resources", utils.ZapRequestID(reqId),
+			zap.String("token", tokenInfo.String()))
+		return nil, api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, model.ErrorTokenOutOfScope.Error())
+	}
+
 	if needOwner && IsSubAccount(tokenInfo) {
 		log.Error("[Auth][Server] only admin/owner account can access this API", utils.ZapRequestID(reqId))
 		return nil, api.NewAuthResponse(apimodel.Code_OperationRoleForbidden)
@@ -211,3 +218,12 @@ func verifyAuth(ctx context.Context, isWrite bool,
 
 	return authCtx.GetRequestContext(), nil
 }
+
+// rejectScopedToken 临时 token 不允许查看用户、用户组的静态 token，避免通过临时 token 获取永久凭据
+func rejectScopedToken(ctx context.Context) *apiservice.Response {
+	if !isScopedToken(utils.ParseAuthToken(ctx)) {
+		return nil
+	}
+	log.Error("[Auth][Server] scoped token can not view static token", utils.ZapRequestIDByCtx(ctx))
+	return api.NewAuthResponseWithMsg(apimodel.Code_NotAllowedAccess, model.ErrorTokenOutOfScope.Error())
+}
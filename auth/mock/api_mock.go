@@ -550,6 +550,20 @@ func (m *MockUserServer) EXPECT() *MockUserServerMockRecorder {
 	return m.recorder
 }
 
+// CreateScopedToken mocks base method.
+func (m *MockUserServer) CreateScopedToken(ctx context.Context, req *auth.ScopedTokenRequest) *auth.ScopedTokenResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScopedToken", ctx, req)
+	ret0, _ := ret[0].(*auth.ScopedTokenResponse)
+	return ret0
+}
+
+// CreateScopedToken indicates an expected call of CreateScopedToken.
+func (mr *MockUserServerMockRecorder) CreateScopedToken(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScopedToken", reflect.TypeOf((*MockUserServer)(nil).CreateScopedToken), ctx, req)
+}
+
 // CreateUsers mocks base method.
 func (m *MockUserServer) CreateUsers(ctx context.Context, users []*security.User) *service_manage.BatchWriteResponse {
 	m.ctrl.T.Helper()
@@ -606,6 +620,35 @@ func (mr *MockUserServerMockRecorder) GetUsers(ctx, query interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserServer)(nil).GetUsers), ctx, query)
 }
 
+// OIDCCallback mocks base method.
+func (m *MockUserServer) OIDCCallback(ctx context.Context, code, state, nonce string) *service_manage.Response {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCCallback", ctx, code, state, nonce)
+	ret0, _ := ret[0].(*service_manage.Response)
+	return ret0
+}
+
+// OIDCCallback indicates an expected call of OIDCCallback.
+func (mr *MockUserServerMockRecorder) OIDCCallback(ctx, code, state, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCCallback", reflect.TypeOf((*MockUserServer)(nil).OIDCCallback), ctx, code, state, nonce)
+}
+
+// OIDCLoginURL mocks base method.
+func (m *MockUserServer) OIDCLoginURL(ctx context.Context) (*auth.OIDCAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OIDCLoginURL", ctx)
+	ret0, _ := ret[0].(*auth.OIDCAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OIDCLoginURL indicates an expected call of OIDCLoginURL.
+func (mr *MockUserServerMockRecorder) OIDCLoginURL(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OIDCLoginURL", reflect.TypeOf((*MockUserServer)(nil).OIDCLoginURL), ctx)
+}
+
 // ResetUserToken mocks base method.
 func (m *MockUserServer) ResetUserToken(ctx context.Context, user *security.User) *service_manage.Response {
 	m.ctrl.T.Helper()
@@ -620,6 +663,20 @@ func (mr *MockUserServerMockRecorder) ResetUserToken(ctx, user interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetUserToken", reflect.TypeOf((*MockUserServer)(nil).ResetUserToken), ctx, user)
 }
 
+// RevokeScopedToken mocks base method.
+func (m *MockUserServer) RevokeScopedToken(ctx context.Context, req *auth.RevokeScopedTokenRequest) *auth.ScopedTokenResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeScopedToken", ctx, req)
+	ret0, _ := ret[0].(*auth.ScopedTokenResponse)
+	return ret0
+}
+
+// RevokeScopedToken indicates an expected call of RevokeScopedToken.
+func (mr *MockUserServerMockRecorder) RevokeScopedToken(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeScopedToken", reflect.TypeOf((*MockUserServer)(nil).RevokeScopedToken), ctx, req)
+}
+
 // UpdateUser mocks base method.
 func (m *MockUserServer) UpdateUser(ctx context.Context, user *security.User) *service_manage.Response {
 	m.ctrl.T.Helper()
@@ -862,6 +919,48 @@ func (mr *MockStrategyServerMockRecorder) GetPrincipalResources(ctx, query inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrincipalResources", reflect.TypeOf((*MockStrategyServer)(nil).GetPrincipalResources), ctx, query)
 }
 
+// GetStrategyAction mocks base method.
+func (m *MockStrategyServer) GetStrategyAction(ctx context.Context, id string) *auth.StrategyActionResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStrategyAction", ctx, id)
+	ret0, _ := ret[0].(*auth.StrategyActionResponse)
+	return ret0
+}
+
+// GetStrategyAction indicates an expected call of GetStrategyAction.
+func (mr *MockStrategyServerMockRecorder) GetStrategyAction(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStrategyAction", reflect.TypeOf((*MockStrategyServer)(nil).GetStrategyAction), ctx, id)
+}
+
+// GetStrategyConflicts mocks base method.
+func (m *MockStrategyServer) GetStrategyConflicts(ctx context.Context, id string) *auth.StrategyConflictsResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStrategyConflicts", ctx, id)
+	ret0, _ := ret[0].(*auth.StrategyConflictsResponse)
+	return ret0
+}
+
+// GetStrategyConflicts indicates an expected call of GetStrategyConflicts.
+func (mr *MockStrategyServerMockRecorder) GetStrategyConflicts(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStrategyConflicts", reflect.TypeOf((*MockStrategyServer)(nil).GetStrategyConflicts), ctx, id)
+}
+
+// GetStrategyRules mocks base method.
+func (m *MockStrategyServer) GetStrategyRules(ctx context.Context, id string) *auth.StrategyRuleResourcesResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStrategyRules", ctx, id)
+	ret0, _ := ret[0].(*auth.StrategyRuleResourcesResponse)
+	return ret0
+}
+
+// GetStrategyRules indicates an expected call of GetStrategyRules.
+func (mr *MockStrategyServerMockRecorder) GetStrategyRules(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStrategyRules", reflect.TypeOf((*MockStrategyServer)(nil).GetStrategyRules), ctx, id)
+}
+
 // GetStrategies mocks base method.
 func (m *MockStrategyServer) GetStrategies(ctx context.Context, query map[string]string) *service_manage.BatchQueryResponse {
 	m.ctrl.T.Helper()
@@ -903,3 +1002,31 @@ func (mr *MockStrategyServerMockRecorder) UpdateStrategies(ctx, reqs interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStrategies", reflect.TypeOf((*MockStrategyServer)(nil).UpdateStrategies), ctx, reqs)
 }
+
+// UpdateStrategyRules mocks base method.
+func (m *MockStrategyServer) UpdateStrategyRules(ctx context.Context, req *auth.StrategyRuleResources) *auth.StrategyRuleResourcesResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStrategyRules", ctx, req)
+	ret0, _ := ret[0].(*auth.StrategyRuleResourcesResponse)
+	return ret0
+}
+
+// UpdateStrategyRules indicates an expected call of UpdateStrategyRules.
+func (mr *MockStrategyServerMockRecorder) UpdateStrategyRules(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStrategyRules", reflect.TypeOf((*MockStrategyServer)(nil).UpdateStrategyRules), ctx, req)
+}
+
+// UpdateStrategyAction mocks base method.
+func (m *MockStrategyServer) UpdateStrategyAction(ctx context.Context, req *auth.StrategyAction) *auth.StrategyActionResponse {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStrategyAction", ctx, req)
+	ret0, _ := ret[0].(*auth.StrategyActionResponse)
+	return ret0
+}
+
+// UpdateStrategyAction indicates an expected call of UpdateStrategyAction.
+func (mr *MockStrategyServerMockRecorder) UpdateStrategyAction(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStrategyAction", reflect.TypeOf((*MockStrategyServer)(nil).UpdateStrategyAction), ctx, req)
+}
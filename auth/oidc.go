@@ -0,0 +1,33 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package auth
+
+import "time"
+
+// OIDCNonceCookie 发起 OIDC 单点登录时保存 nonce 的 cookie 名称
+const OIDCNonceCookie = "polaris_oidc_nonce"
+
+// OIDCAuthorization 发起 OIDC 单点登录的信息
+type OIDCAuthorization struct {
+	// URL 跳转到 IdP 的授权地址
+	URL string
+	// Nonce 需要写入浏览器的 HttpOnly cookie，回调时与 state 中的 nonce 比较，保证回调来自发起登录的浏览器
+	Nonce string
+	// ExpireAt state 以及 nonce 的过期时间
+	ExpireAt time.Time
+}
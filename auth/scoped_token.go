@@ -0,0 +1,64 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package auth
+
+import (
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+)
+
+// ScopedTokenRequest 申请临时 token 的请求
+// ttl 为有效期，单位秒，namespaces 为可访问的命名空间，为空表示不限制，read_only 表示只允许读操作
+type ScopedTokenRequest struct {
+	TTL        int64    `json:"ttl,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	ReadOnly   bool     `json:"read_only,omitempty"`
+}
+
+// RevokeScopedTokenRequest 吊销临时 token 的请求
+type RevokeScopedTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// ScopedTokenResponse 临时 token 的操作结果
+type ScopedTokenResponse struct {
+	Code       uint32   `json:"code"`
+	Info       string   `json:"info"`
+	ID         string   `json:"id,omitempty"`
+	Token      string   `json:"token,omitempty"`
+	ExpireAt   int64    `json:"expire_at,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	ReadOnly   bool     `json:"read_only,omitempty"`
+}
+
+// NewScopedTokenResponse 创建临时 token 的操作结果
+func NewScopedTokenResponse(code apimodel.Code) *ScopedTokenResponse {
+	return &ScopedTokenResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)),
+	}
+}
+
+// NewScopedTokenResponseWithMsg 创建带有错误信息的临时 token 操作结果
+func NewScopedTokenResponseWithMsg(code apimodel.Code, msg string) *ScopedTokenResponse {
+	return &ScopedTokenResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + msg,
+	}
+}
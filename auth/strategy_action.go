@@ -0,0 +1,99 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package auth
+
+import (
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+)
+
+// StrategyAction 鉴权策略的动作
+// action 支持 ONLY_READ、READ_WRITE 以及 READ,CREATE,MODIFY,DELETE 的任意组合
+// effect 支持 ALLOW、DENY，DENY 策略禁止成员对资源执行 action 中的操作，并且优先于 ALLOW 策略
+type StrategyAction struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name,omitempty"`
+	Action     string   `json:"action"`
+	Effect     string   `json:"effect,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+}
+
+// StrategyActionResponse 鉴权策略动作的操作结果
+type StrategyActionResponse struct {
+	Code      uint32              `json:"code"`
+	Info      string              `json:"info"`
+	Action    *StrategyAction     `json:"action,omitempty"`
+	Conflicts []*StrategyConflict `json:"conflicts,omitempty"`
+}
+
+// StrategyConflict 授权策略与拒绝策略作用在同一个成员、同一个资源上的冲突，拒绝策略优先生效
+type StrategyConflict struct {
+	AllowStrategyID   string   `json:"allow_strategy_id"`
+	AllowStrategyName string   `json:"allow_strategy_name"`
+	DenyStrategyID    string   `json:"deny_strategy_id"`
+	DenyStrategyName  string   `json:"deny_strategy_name"`
+	PrincipalID       string   `json:"principal_id"`
+	PrincipalType     string   `json:"principal_type"`
+	ResType           string   `json:"res_type"`
+	ResID             string   `json:"res_id"`
+	Operations        []string `json:"operations"`
+}
+
+// StrategyConflictsResponse 鉴权策略冲突的查询结果
+type StrategyConflictsResponse struct {
+	Code      uint32              `json:"code"`
+	Info      string              `json:"info"`
+	Size      uint32              `json:"size"`
+	Conflicts []*StrategyConflict `json:"conflicts"`
+}
+
+// NewStrategyActionResponse 创建鉴权策略动作的操作结果
+func NewStrategyActionResponse(code apimodel.Code, action *StrategyAction) *StrategyActionResponse {
+	return &StrategyActionResponse{
+		Code:   uint32(code),
+		Info:   api.Code2Info(uint32(code)),
+		Action: action,
+	}
+}
+
+// NewStrategyActionResponseWithMsg 创建带有错误信息的鉴权策略动作操作结果
+func NewStrategyActionResponseWithMsg(code apimodel.Code, msg string) *StrategyActionResponse {
+	return &StrategyActionResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + msg,
+	}
+}
+
+// NewStrategyConflictsResponse 创建鉴权策略冲突的查询结果
+func NewStrategyConflictsResponse(code apimodel.Code, conflicts []*StrategyConflict) *StrategyConflictsResponse {
+	return &StrategyConflictsResponse{
+		Code:      uint32(code),
+		Info:      api.Code2Info(uint32(code)),
+		Size:      uint32(len(conflicts)),
+		Conflicts: conflicts,
+	}
+}
+
+// NewStrategyConflictsResponseWithMsg 创建带有错误信息的鉴权策略冲突查询结果
+func NewStrategyConflictsResponseWithMsg(code apimodel.Code, msg string) *StrategyConflictsResponse {
+	return &StrategyConflictsResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + msg,
+	}
+}
@@ -0,0 +1,67 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package auth
+
+import (
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+)
+
+// StrategyRuleResource 鉴权策略中的治理规则资源
+// type 支持 route_rule、ratelimit_rule、circuitbreaker_rule、faultdetect_rule
+// id 为规则ID或者 *，name 为规则名称的通配表达式，两者只能设置其一
+type StrategyRuleResource struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// StrategyRuleResources 修改鉴权策略中的治理规则资源
+type StrategyRuleResources struct {
+	ID          string                  `json:"id"`
+	AddRules    []*StrategyRuleResource `json:"add_rules,omitempty"`
+	RemoveRules []*StrategyRuleResource `json:"remove_rules,omitempty"`
+}
+
+// StrategyRuleResourcesResponse 鉴权策略治理规则资源的操作结果
+type StrategyRuleResourcesResponse struct {
+	Code  uint32                  `json:"code"`
+	Info  string                  `json:"info"`
+	ID    string                  `json:"id,omitempty"`
+	Rules []*StrategyRuleResource `json:"rules,omitempty"`
+}
+
+// NewStrategyRuleResourcesResponse 创建鉴权策略治理规则资源的操作结果
+func NewStrategyRuleResourcesResponse(code apimodel.Code, id string,
+	rules []*StrategyRuleResource) *StrategyRuleResourcesResponse {
+	return &StrategyRuleResourcesResponse{
+		Code:  uint32(code),
+		Info:  api.Code2Info(uint32(code)),
+		ID:    id,
+		Rules: rules,
+	}
+}
+
+// NewStrategyRuleResourcesResponseWithMsg 创建带有错误信息的鉴权策略治理规则资源操作结果
+func NewStrategyRuleResourcesResponseWithMsg(code apimodel.Code, msg string) *StrategyRuleResourcesResponse {
+	return &StrategyRuleResourcesResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + msg,
+	}
+}
@@ -0,0 +1,61 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cache
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	// AccessPolicyName access policy cache name
+	AccessPolicyName = "accessPolicy"
+)
+
+// AccessPolicyCache 访问控制策略缓存
+type AccessPolicyCache interface {
+	Cache
+	// GetAccessPolicies 获取服务下已经启用的访问控制策略以及这些策略的整体版本号
+	GetAccessPolicies(name string, namespace string) ([]*model.AccessPolicy, string)
+}
+
+type accessPolicyCache struct {
+	*servicePolicyCache[*model.AccessPolicy]
+}
+
+// init 自注册到缓存列表
+func init() {
+	RegisterCache(AccessPolicyName, CacheAccessPolicy)
+}
+
+// newAccessPolicyCache accessPolicyCache constructor
+func newAccessPolicyCache(s store.Store) *accessPolicyCache {
+	fetch := func(mtime time.Time, firstUpdate bool) ([]*model.AccessPolicy, error) {
+		return s.GetAccessPoliciesForCache(mtime, firstUpdate)
+	}
+	return &accessPolicyCache{
+		servicePolicyCache: newServicePolicyCache(s, AccessPolicyName, fetch),
+	}
+}
+
+// GetAccessPolicies 获取服务下已经启用的访问控制策略，按照ID排序
+func (ac *accessPolicyCache) GetAccessPolicies(name string, namespace string) ([]*model.AccessPolicy, string) {
+	return ac.getPolicies(name, namespace)
+}
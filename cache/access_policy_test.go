@@ -0,0 +1,90 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store/mock"
+)
+
+func TestAccessPolicyCacheUpdate(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+
+	storage := mock.NewMockStore(ctl)
+	ac := newAccessPolicyCache(storage)
+	storage.EXPECT().GetUnixSecond(gomock.Any()).AnyTimes().Return(time.Now().Unix(), nil)
+	_ = ac.initialize(nil)
+
+	policies := []*model.AccessPolicy{
+		{
+			ServicePolicy: model.ServicePolicy{
+				ID:         "policy-1",
+				Namespace:  "default",
+				Service:    "echo",
+				Enable:     true,
+				Valid:      true,
+				Rule:       `{"sources":[{"namespace":"default","service":"frontend"}],"methods":["GET"]}`,
+				Revision:   "rev-1",
+				ModifyTime: time.Unix(10, 0),
+			},
+		},
+		{
+			ServicePolicy: model.ServicePolicy{
+				ID:         "policy-2",
+				Namespace:  "default",
+				Service:    "echo",
+				Enable:     true,
+				Valid:      true,
+				Rule:       `{"sources":[{"namespace":"ops","service":"*"}]}`,
+				Revision:   "rev-2",
+				ModifyTime: time.Unix(11, 0),
+			},
+			DryRun: true,
+		},
+	}
+	storage.EXPECT().GetAccessPoliciesForCache(gomock.Any(), true).Return(policies, nil)
+	assert.NoError(t, ac.update())
+
+	ret, revision := ac.GetAccessPolicies("echo", "default")
+	assert.Equal(t, 2, len(ret))
+	assert.NotEmpty(t, revision)
+	assert.Equal(t, "frontend", ret[0].Spec.Sources[0].Service)
+	assert.Equal(t, []string{"GET"}, ret[0].Spec.Methods)
+	assert.True(t, ret[1].DryRun)
+
+	t.Run("停用策略之后从缓存中移除", func(t *testing.T) {
+		disabled := *policies[1]
+		disabled.Enable = false
+		disabled.ModifyTime = time.Unix(12, 0)
+		storage.EXPECT().GetAccessPoliciesForCache(gomock.Any(), false).
+			Return([]*model.AccessPolicy{&disabled}, nil)
+		assert.NoError(t, ac.update())
+
+		ret, newRevision := ac.GetAccessPolicies("echo", "default")
+		assert.Equal(t, 1, len(ret))
+		assert.NotEqual(t, revision, newRevision)
+		assert.Equal(t, int64(12), ac.LastMtime(ac.name()).Unix())
+	})
+}
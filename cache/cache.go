@@ -47,6 +47,8 @@ var (
 	_ L5Cache             = (*l5Cache)(nil)
 	_ FileCache           = (*fileCache)(nil)
 	_ FaultDetectCache    = (*faultDetectCache)(nil)
+	_ TrafficPolicyCache  = (*trafficPolicyCache)(nil)
+	_ AccessPolicyCache   = (*accessPolicyCache)(nil)
 )
 
 const (
@@ -64,6 +66,8 @@ const (
 	CacheClient
 	CacheConfigFile
 	CacheFaultDetector
+	CacheTrafficPolicy
+	CacheAccessPolicy
 
 	CacheLast
 )
@@ -84,6 +88,8 @@ const (
 	CacheNameClient          CacheName = "Client"
 	CacheNameConfigFile      CacheName = "ConfigFile"
 	CacheNameFaultDetectRule CacheName = "FaultDetectRule"
+	CacheNameTrafficPolicy   CacheName = "TrafficPolicy"
+	CacheNameAccessPolicy    CacheName = "AccessPolicy"
 )
 
 var (
@@ -100,6 +106,8 @@ var (
 		CacheNameClient:          CacheClient,
 		CacheNameConfigFile:      CacheConfigFile,
 		CacheNameFaultDetectRule: CacheFaultDetector,
+		CacheNameTrafficPolicy:   CacheTrafficPolicy,
+		CacheNameAccessPolicy:    CacheAccessPolicy,
 	}
 )
 
@@ -286,8 +294,13 @@ type CacheManager struct {
 	comRevisionCh chan *revisionNotify
 	revisions     map[string]string // service id -> reversion (所有instance reversion 的累计计算值)
 	lock          sync.RWMutex      // for revisions rw lock
+
+	revisionListeners []RevisionListener
 }
 
+// RevisionListener 服务实例 revision 发生变化时的回调，revision 为空表示服务下的实例已经全部删除
+type RevisionListener func(serviceID string, revision string)
+
 // initialize 缓存对象初始化
 func (nc *CacheManager) initialize() error {
 	if config.DiffTime != 0 {
@@ -440,6 +453,7 @@ func (nc *CacheManager) processRevisionWorker(req *revisionNotify) bool {
 	if !req.valid {
 		log.Infof("[Cache][Revision] service(%s) revision has all been removed", req.serviceID)
 		nc.deleteRevisions(req.serviceID)
+		nc.notifyRevisionChanged(req.serviceID, "")
 		return true
 	}
 
@@ -457,11 +471,32 @@ func (nc *CacheManager) processRevisionWorker(req *revisionNotify) bool {
 		return false
 	}
 
+	preRevision, _ := nc.readRevisions(req.serviceID)
 	nc.setRevisions(req.serviceID, revision) // string -> string
 	log.Infof("[Cache] compute service id(%s) instances revision : %s", req.serviceID, revision)
+	if preRevision != revision {
+		nc.notifyRevisionChanged(req.serviceID, revision)
+	}
 	return true
 }
 
+// AddRevisionListener 监听服务实例 revision 的变化
+func (nc *CacheManager) AddRevisionListener(listener RevisionListener) {
+	nc.lock.Lock()
+	defer nc.lock.Unlock()
+	nc.revisionListeners = append(nc.revisionListeners, listener)
+}
+
+func (nc *CacheManager) notifyRevisionChanged(serviceID string, revision string) {
+	nc.lock.RLock()
+	listeners := nc.revisionListeners
+	nc.lock.RUnlock()
+
+	for _, listener := range listeners {
+		listener(serviceID, revision)
+	}
+}
+
 // GetUpdateCacheInterval 获取当前cache的更新间隔
 func (nc *CacheManager) GetUpdateCacheInterval() time.Duration {
 	return UpdateCacheInterval
@@ -525,6 +560,16 @@ func (nc *CacheManager) FaultDetector() FaultDetectCache {
 	return nc.caches[CacheFaultDetector].(FaultDetectCache)
 }
 
+// TrafficPolicy 获取流量策略缓存信息
+func (nc *CacheManager) TrafficPolicy() TrafficPolicyCache {
+	return nc.caches[CacheTrafficPolicy].(TrafficPolicyCache)
+}
+
+// AccessPolicy 获取访问控制策略缓存信息
+func (nc *CacheManager) AccessPolicy() AccessPolicyCache {
+	return nc.caches[CacheAccessPolicy].(AccessPolicyCache)
+}
+
 // User Get user information cache information
 func (nc *CacheManager) User() UserCache {
 	return nc.caches[CacheUser].(UserCache)
@@ -102,6 +102,9 @@ func (c *circuitBreakerCache) realUpdate() (map[string]time.Time, int64, error)
 		return nil, -1, err
 	}
 	lastMtimes := c.setCircuitBreaker(cbRules)
+	if len(cbRules) > 0 {
+		c.manager.onEvent(cbRules, EventBatchUpdated)
+	}
 	return lastMtimes, int64(len(cbRules)), nil
 }
 
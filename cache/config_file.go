@@ -95,6 +95,31 @@ type Entry struct {
 	ExpireTime time.Time
 	// 标识是否是空缓存
 	Empty bool
+	// Gray 正在进行中的灰度发布
+	Gray *GrayEntry
+}
+
+// GrayEntry 灰度发布缓存实体对象
+type GrayEntry struct {
+	Content string
+	Md5     string
+	Version uint64
+	Rule    *model.ConfigFileGrayRule
+}
+
+// Select 根据客户端信息选择需要下发的配置，命中灰度规则的客户端返回灰度发布的内容
+func (e *Entry) Select(client *model.GrayClient) *Entry {
+	if e.Empty || e.Gray == nil || !e.Gray.Rule.Match(client) {
+		return e
+	}
+	return &Entry{
+		Content:    e.Gray.Content,
+		Md5:        e.Gray.Md5,
+		Version:    e.Gray.Version,
+		DataKey:    e.DataKey,
+		ExpireTime: e.ExpireTime,
+		Gray:       e.Gray,
+	}
 }
 
 // newFileCache 创建文件缓存
@@ -265,6 +290,11 @@ func (fc *fileCache) GetOrLoadIfAbsent(namespace, group, fileName string) (*Entr
 		return emptyEntry, nil
 	}
 
+	grayEntry, err := fc.getConfigFileGrayEntry(namespace, group, fileName)
+	if err != nil {
+		return nil, err
+	}
+
 	// 数据库中有对象，更新缓存
 	newEntry := &Entry{
 		Content:    file.Content,
@@ -272,6 +302,7 @@ func (fc *fileCache) GetOrLoadIfAbsent(namespace, group, fileName string) (*Entr
 		Version:    file.Version,
 		DataKey:    dataKey,
 		ExpireTime: fc.getExpireTime(),
+		Gray:       grayEntry,
 	}
 
 	// 缓存不存在，则直接存入缓存
@@ -318,6 +349,37 @@ func (fc *fileCache) getConfigFileReleaseAndDataKey(
 	return file, dataKey, nil
 }
 
+func (fc *fileCache) getConfigFileGrayEntry(namespace, group, fileName string) (*GrayEntry, error) {
+	grayRelease, err := fc.storage.GetConfigFileGrayRelease(nil, namespace, group, fileName)
+	if err != nil {
+		configLog.Error("[Config][Cache] load config file gray release error.",
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return nil, err
+	}
+	if grayRelease == nil {
+		return nil, nil
+	}
+	rule, err := model.ParseConfigFileGrayRule(grayRelease.Rule)
+	if err != nil {
+		// 规则异常的灰度发布不对任何客户端生效
+		configLog.Error("[Config][Cache] parse config file gray rule error.",
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return nil, nil
+	}
+	return &GrayEntry{
+		Content: grayRelease.Content,
+		Md5:     grayRelease.Md5,
+		Version: grayRelease.Version,
+		Rule:    rule,
+	}, nil
+}
+
 // Remove 删除缓存对象
 func (fc *fileCache) Remove(namespace, group, fileName string) {
 	atomic.AddInt32(&fc.removeCnt, 1)
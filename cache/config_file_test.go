@@ -50,6 +50,7 @@ func TestGetAndRemoveAndReloadConfigFile(t *testing.T) {
 	// 前三次调用返回一个值，第四次调用返回另外一个值，默认更新
 	mockedStorage.EXPECT().GetConfigFileRelease(nil, testNamespace, testGroup, testFile).Return(configFileRelease, nil).Times(3)
 	mockedStorage.EXPECT().QueryTagByConfigFile(testNamespace, testGroup, testFile).Return(configFileTags, nil).Times(3)
+	mockedStorage.EXPECT().GetConfigFileGrayRelease(nil, testNamespace, testGroup, testFile).Return(nil, nil).Times(3)
 
 	for i := 0; i < 100; i++ {
 		go func() {
@@ -100,6 +101,7 @@ func TestConcurrentGetConfigFile(t *testing.T) {
 	// 一共调用三次，
 	mockedStorage.EXPECT().GetConfigFileRelease(nil, testNamespace, testGroup, testFile).Return(configFileRelease, nil).Times(1)
 	mockedStorage.EXPECT().QueryTagByConfigFile(testNamespace, testGroup, testFile).Return(configFileTags, nil).Times(1)
+	mockedStorage.EXPECT().GetConfigFileGrayRelease(nil, testNamespace, testGroup, testFile).Return(nil, nil).Times(1)
 
 	for i := 0; i < 1000; i++ {
 		go func() {
@@ -128,6 +130,7 @@ func TestUpdateCache(t *testing.T) {
 	configFileTags := assembelConfigFileTags(configFile)
 	first := mockedStorage.EXPECT().GetConfigFileRelease(nil, testNamespace, testGroup, testFile).Return(configFileRelease, nil).Times(1)
 	mockedStorage.EXPECT().QueryTagByConfigFile(testNamespace, testGroup, testFile).Return(configFileTags, nil).Times(1)
+	mockedStorage.EXPECT().GetConfigFileGrayRelease(nil, testNamespace, testGroup, testFile).Return(nil, nil).Times(1)
 
 	// 第二次调用返会值
 	secondValue := "secondValue"
@@ -139,6 +142,7 @@ func TestUpdateCache(t *testing.T) {
 	configFileTags = assembelConfigFileTags(configFile)
 	second := mockedStorage.EXPECT().GetConfigFileRelease(nil, testNamespace, testGroup, testFile).Return(configFileRelease2, nil).Times(1)
 	mockedStorage.EXPECT().QueryTagByConfigFile(testNamespace, testGroup, testFile).Return(configFileTags, nil).Times(1)
+	mockedStorage.EXPECT().GetConfigFileGrayRelease(nil, testNamespace, testGroup, testFile).Return(nil, nil).Times(1)
 
 	gomock.InOrder(first, second)
 
@@ -168,6 +172,42 @@ func TestUpdateCache(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 }
 
+// TestSelectGrayEntry 测试灰度发布时，按照客户端信息选择下发的配置
+func TestSelectGrayEntry(t *testing.T) {
+	control, mockedStorage, fileCache := newConfigFileMockedCache(t)
+	fileCache.clear()
+	defer control.Finish()
+
+	configFile := assembleConfigFile()
+	configFileRelease := assembleConfigFileRelease(configFile)
+	configFileTags := assembelConfigFileTags(configFile)
+	grayRelease := &model.ConfigFileGrayRelease{
+		Namespace: testNamespace,
+		Group:     testGroup,
+		FileName:  testFile,
+		Content:   "k1=gray",
+		Md5:       "gray-md5",
+		Version:   configFileRelease.Version + 1,
+		Rule:      `{"client_ips":["10.0.0.0/24"]}`,
+		Valid:     true,
+	}
+	mockedStorage.EXPECT().GetConfigFileRelease(nil, testNamespace, testGroup, testFile).Return(configFileRelease, nil).Times(1)
+	mockedStorage.EXPECT().QueryTagByConfigFile(testNamespace, testGroup, testFile).Return(configFileTags, nil).Times(1)
+	mockedStorage.EXPECT().GetConfigFileGrayRelease(nil, testNamespace, testGroup, testFile).Return(grayRelease, nil).Times(1)
+
+	entry, err := fileCache.GetOrLoadIfAbsent(testNamespace, testGroup, testFile)
+	assert.NoError(t, err)
+	assert.NotNil(t, entry.Gray)
+
+	grayEntry := entry.Select(&model.GrayClient{IP: "10.0.0.8"})
+	assert.Equal(t, grayRelease.Content, grayEntry.Content)
+	assert.Equal(t, grayRelease.Version, grayEntry.Version)
+
+	normalEntry := entry.Select(&model.GrayClient{IP: "10.0.1.8"})
+	assert.Equal(t, configFileRelease.Content, normalEntry.Content)
+	assert.Equal(t, configFileRelease.Version, normalEntry.Version)
+}
+
 func newConfigFileMockedCache(t *testing.T) (*gomock.Controller, *mock.MockStore, FileCache) {
 	control := gomock.NewController(t)
 	mockedStorage := mock.NewMockStore(control)
@@ -81,6 +81,8 @@ func newCacheManager(ctx context.Context, cacheOpt *Config, storage store.Store)
 	mgr.caches[CacheRateLimit] = newRateLimitCache(storage, sc)
 	mgr.caches[CacheCircuitBreaker] = newCircuitBreakerCache(storage)
 	mgr.caches[CacheFaultDetector] = newFaultDetectCache(storage)
+	mgr.caches[CacheTrafficPolicy] = newTrafficPolicyCache(storage)
+	mgr.caches[CacheAccessPolicy] = newAccessPolicyCache(storage)
 	mgr.caches[CacheUser] = newUserCache(storage)
 	mgr.caches[CacheAuthStrategy] = newStrategyCache(storage, mgr.caches[CacheUser].(UserCache))
 	mgr.caches[CacheNamespace] = newNamespaceCache(storage)
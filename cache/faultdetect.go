@@ -100,7 +100,9 @@ func (f *faultDetectCache) realUpdate() (map[string]time.Time, int64, error) {
 		return nil, -1, err
 	}
 	lastMtimes := f.setFaultDetectRules(fdRules)
-
+	if len(fdRules) > 0 {
+		f.manager.onEvent(fdRules, EventBatchUpdated)
+	}
 	return lastMtimes, int64(len(fdRules)), nil
 }
 
@@ -47,6 +47,8 @@ const (
 	EventInstanceReload
 	// EventPrincipalRemove value principal batch remove
 	EventPrincipalRemove
+	// EventBatchUpdated value batch updated
+	EventBatchUpdated
 )
 
 type listenerManager struct {
@@ -71,7 +73,7 @@ func (l *listenerManager) onEvent(value interface{}, event EventType) {
 			listener.OnUpdated(value)
 		case EventDeleted:
 			listener.OnDeleted(value)
-		case EventInstanceReload:
+		case EventInstanceReload, EventBatchUpdated:
 			listener.OnBatchUpdated(value)
 		case EventPrincipalRemove:
 			listener.OnBatchDeleted(value)
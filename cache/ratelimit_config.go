@@ -105,6 +105,9 @@ func (rlc *rateLimitCache) realUpdate() (map[string]time.Time, int64, error) {
 		return nil, -1, err
 	}
 	rlc.setRateLimit(rateLimits)
+	if len(rateLimits) > 0 {
+		rlc.manager.onEvent(rateLimits, EventBatchUpdated)
+	}
 	return nil, int64(len(rateLimits)), err
 }
 
@@ -129,6 +129,12 @@ func (rc *routingConfigCache) realUpdate() (map[string]time.Time, int64, error)
 	lastMtimes := map[string]time.Time{}
 	rc.setRoutingConfigV1(lastMtimes, outV1)
 	rc.setRoutingConfigV2(lastMtimes, outV2)
+	if len(outV1) > 0 {
+		rc.manager.onEvent(outV1, EventBatchUpdated)
+	}
+	if len(outV2) > 0 {
+		rc.manager.onEvent(outV2, EventBatchUpdated)
+	}
 	return lastMtimes, int64(len(outV1) + len(outV2)), err
 }
 
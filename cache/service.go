@@ -186,6 +186,9 @@ func (sc *serviceCache) realUpdate() (map[string]time.Time, int64, error) {
 	}
 
 	lastMtimes, update, del := sc.setServices(services)
+	if len(services) > 0 {
+		sc.manager.onEvent(services, EventBatchUpdated)
+	}
 	costTime := time.Since(start)
 	log.Info("[Cache][Service] get more services", zap.Int("update", update), zap.Int("delete", del),
 		zap.Time("last", sc.LastMtime()), zap.Duration("used", costTime))
@@ -0,0 +1,192 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cache
+
+import (
+	"crypto/sha1"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// servicePolicySet 单个服务下的策略
+type servicePolicySet[T model.ServicePolicyRule] struct {
+	policies map[string]T
+	revision string
+}
+
+// servicePolicyCache 按照服务维度缓存挂载在服务上的策略，各类策略共用
+type servicePolicyCache[T model.ServicePolicyRule] struct {
+	*baseCache
+
+	cacheName string
+	// fetch 从存储层拉取增量的策略
+	fetch func(mtime time.Time, firstUpdate bool) ([]T, error)
+
+	lock sync.RWMutex
+	// key: namespace/service
+	services map[model.ServiceKey]*servicePolicySet[T]
+	// key: policy id, value: 策略所属的服务，用于处理策略更换服务的场景
+	policyServices map[string]model.ServiceKey
+
+	singleFlight singleflight.Group
+}
+
+// newServicePolicyCache servicePolicyCache constructor
+func newServicePolicyCache[T model.ServicePolicyRule](s store.Store, cacheName string,
+	fetch func(mtime time.Time, firstUpdate bool) ([]T, error)) *servicePolicyCache[T] {
+	return &servicePolicyCache[T]{
+		baseCache:      newBaseCache(s),
+		cacheName:      cacheName,
+		fetch:          fetch,
+		services:       make(map[model.ServiceKey]*servicePolicySet[T]),
+		policyServices: make(map[string]model.ServiceKey),
+	}
+}
+
+// initialize 实现Cache接口的函数
+func (pc *servicePolicyCache[T]) initialize(_ map[string]interface{}) error {
+	return nil
+}
+
+func (pc *servicePolicyCache[T]) update() error {
+	_, err, _ := pc.singleFlight.Do(pc.name(), func() (interface{}, error) {
+		return nil, pc.doCacheUpdate(pc.name(), pc.realUpdate)
+	})
+	return err
+}
+
+func (pc *servicePolicyCache[T]) realUpdate() (map[string]time.Time, int64, error) {
+	policies, err := pc.fetch(pc.LastFetchTime(), pc.isFirstUpdate())
+	if err != nil {
+		log.Errorf("[Cache] %s cache update err: %s", pc.name(), err.Error())
+		return nil, -1, err
+	}
+	lastMtimes := pc.setPolicies(policies)
+	if len(policies) > 0 {
+		pc.manager.onEvent(policies, EventBatchUpdated)
+	}
+	return lastMtimes, int64(len(policies)), nil
+}
+
+// clear 实现Cache接口的函数
+func (pc *servicePolicyCache[T]) clear() error {
+	pc.baseCache.clear()
+	pc.lock.Lock()
+	pc.services = make(map[model.ServiceKey]*servicePolicySet[T])
+	pc.policyServices = make(map[string]model.ServiceKey)
+	pc.lock.Unlock()
+	return nil
+}
+
+// name 实现资源名称
+func (pc *servicePolicyCache[T]) name() string {
+	return pc.cacheName
+}
+
+// getPolicies 获取服务下已经启用的策略，按照ID排序
+func (pc *servicePolicyCache[T]) getPolicies(name string, namespace string) ([]T, string) {
+	pc.lock.RLock()
+	defer pc.lock.RUnlock()
+
+	svcPolicies, ok := pc.services[model.ServiceKey{Namespace: namespace, Name: name}]
+	if !ok {
+		return nil, ""
+	}
+	ret := make([]T, 0, len(svcPolicies.policies))
+	for _, policy := range svcPolicies.policies {
+		ret = append(ret, policy)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].GetServicePolicy().ID < ret[j].GetServicePolicy().ID
+	})
+	return ret, svcPolicies.revision
+}
+
+// setPolicies 更新store的数据到cache中，删除以及未启用的策略不会出现在缓存中
+func (pc *servicePolicyCache[T]) setPolicies(policies []T) map[string]time.Time {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	pc.lock.Lock()
+	defer pc.lock.Unlock()
+
+	lastMtime := pc.LastMtime(pc.name()).Unix()
+	changed := make(map[model.ServiceKey]struct{})
+	for _, item := range policies {
+		policy := item.GetServicePolicy()
+		if policy.ModifyTime.Unix() > lastMtime {
+			lastMtime = policy.ModifyTime.Unix()
+		}
+		if preKey, ok := pc.policyServices[policy.ID]; ok {
+			if svcPolicies, ok := pc.services[preKey]; ok {
+				delete(svcPolicies.policies, policy.ID)
+			}
+			delete(pc.policyServices, policy.ID)
+			changed[preKey] = struct{}{}
+		}
+		if !policy.Valid || !policy.Enable {
+			continue
+		}
+		if err := item.ParseSpec(); err != nil {
+			log.Errorf("[Cache] %s(%s) parse rule err: %s", pc.name(), policy.ID, err.Error())
+			continue
+		}
+		svcKey := policy.ServiceKey()
+		svcPolicies, ok := pc.services[svcKey]
+		if !ok {
+			svcPolicies = &servicePolicySet[T]{policies: make(map[string]T)}
+			pc.services[svcKey] = svcPolicies
+		}
+		svcPolicies.policies[policy.ID] = item
+		pc.policyServices[policy.ID] = svcKey
+		changed[svcKey] = struct{}{}
+	}
+
+	for svcKey := range changed {
+		svcPolicies, ok := pc.services[svcKey]
+		if !ok {
+			continue
+		}
+		if len(svcPolicies.policies) == 0 {
+			delete(pc.services, svcKey)
+			continue
+		}
+		revisions := make([]string, 0, len(svcPolicies.policies))
+		for _, policy := range svcPolicies.policies {
+			revisions = append(revisions, policy.GetServicePolicy().Revision)
+		}
+		sort.Strings(revisions)
+		revision, err := ComputeRevisionBySlice(sha1.New(), revisions)
+		if err != nil {
+			log.Errorf("[Cache] compute %s revision service(%s) err: %s", pc.name(), svcKey.Name, err.Error())
+			continue
+		}
+		svcPolicies.revision = revision
+	}
+
+	return map[string]time.Time{
+		pc.name(): time.Unix(lastMtime, 0),
+	}
+}
@@ -61,6 +61,21 @@ type StrategyCache interface {
 	// IsResourceEditable 判断该资源是否可以操作
 	IsResourceEditable(principal model.Principal, resType apisecurity.ResourceType, resId string) bool
 
+	// IsResourceOperable 判断 principal 是否可以对该资源执行指定的操作
+	IsResourceOperable(principal model.Principal, resType apisecurity.ResourceType, resId string,
+		op model.ResourceOperation) bool
+
+	// IsResourceDenied 判断 principal 对该资源执行指定操作是否命中了拒绝策略
+	IsResourceDenied(principal model.Principal, resType apisecurity.ResourceType, resId string,
+		op model.ResourceOperation) bool
+
+	// IsRuleResourceOperable 判断 principal 是否可以对该治理规则执行指定的操作，策略中的规则资源支持按照名称通配
+	IsRuleResourceOperable(principal model.Principal, resType model.RuleResourceType, ruleId, ruleName string,
+		op model.ResourceOperation) bool
+
+	// GetStrategyConflicts 获取与该鉴权策略存在 ALLOW/DENY 冲突的信息
+	GetStrategyConflicts(rule *model.StrategyDetail) []model.StrategyConflict
+
 	// ForceSyncStrategy2Cache 强制同步鉴权策略到cache (串行)
 	ForceSyncStrategy2Cache() error
 }
@@ -77,6 +92,8 @@ type strategyCache struct {
 	namespace2Strategy   *strategyLinkBucket
 	service2Strategy     *strategyLinkBucket
 	configGroup2Strategy *strategyLinkBucket
+	// rule2Strategy 治理规则资源，key 为资源ID（规则ID、* 或者 name:<pattern>）
+	rule2Strategy map[model.RuleResourceType]*strategyLinkBucket
 
 	userCache    UserCache
 	lastMtime    int64
@@ -118,6 +135,13 @@ func (sc *strategyCache) initBuckets() {
 		lock:       sync.RWMutex{},
 		strategies: make(map[string]*strategyIdBucket),
 	}
+	sc.rule2Strategy = make(map[model.RuleResourceType]*strategyLinkBucket, len(model.RuleResourceTypes))
+	for _, resType := range model.RuleResourceTypes {
+		sc.rule2Strategy[resType] = &strategyLinkBucket{
+			lock:       sync.RWMutex{},
+			strategies: make(map[string]*strategyIdBucket),
+		}
+	}
 }
 
 func (sc *strategyCache) initialize(c map[string]interface{}) error {
@@ -210,6 +234,7 @@ func buildEnchanceStrategyDetail(strategy *model.StrategyDetail) *model.Strategy
 		StrategyDetail: strategy,
 		UserPrincipal:  users,
 		GroupPrincipal: groups,
+		Actions:        model.ParseStrategyOperations(strategy.Action),
 	}
 }
 
@@ -236,6 +261,16 @@ func (sc *strategyCache) handlerResourceStrategy(strategies []*model.StrategyDet
 			} else {
 				sc.configGroup2Strategy.save(resId, strategyId)
 			}
+		default:
+			bucket, ok := sc.rule2Strategy[model.RuleResourceType(resType)]
+			if !ok {
+				return
+			}
+			if remove {
+				bucket.delete(resId, strategyId)
+			} else {
+				bucket.save(resId, strategyId)
+			}
 		}
 	}
 
@@ -349,41 +384,86 @@ func (sc *strategyCache) name() string {
 
 // 对于 check 逻辑，如果是计算 * 策略，则必须要求 * 资源下必须有策略
 // 如果是具体的资源ID，则该资源下不必有策略，如果没有策略就认为这个资源是可以被任何人编辑的
-func (sc *strategyCache) checkResourceEditable(strategIds []string, principal model.Principal, mustCheck bool) bool {
+// principal 所在的策略还需要允许本次的操作动作
+func (sc *strategyCache) checkResourceEditable(strategIds []string, principal model.Principal, mustCheck bool,
+	op model.ResourceOperation) bool {
 	// 是否可以编辑
 	editable := false
-	// 是否真的包含策略
-	isCheck := len(strategIds) != 0
-
-	// 如果根本没有遍历过，则表示该资源下没有对应的策略列表，直接返回可编辑状态即可
-	if !isCheck && !mustCheck {
-		return true
-	}
+	// 是否真的包含授权策略
+	isCheck := false
 
 	for i := range strategIds {
+		rule, ok := sc.strategys.get(strategIds[i])
+		// 拒绝策略已经在 checkResourceDenied 中处理，这里只计算授权策略
+		if ok && rule.IsDeny() {
+			continue
+		}
 		isCheck = true
-		if rule, ok := sc.strategys.get(strategIds[i]); ok {
+		if ok {
+			var exist bool
 			if principal.PrincipalRole == model.PrincipalUser {
-				_, exist := rule.UserPrincipal[principal.PrincipalID]
-				editable = editable || exist
+				_, exist = rule.UserPrincipal[principal.PrincipalID]
 			} else {
-				_, exist := rule.GroupPrincipal[principal.PrincipalID]
-				editable = editable || exist
+				_, exist = rule.GroupPrincipal[principal.PrincipalID]
 			}
+			editable = editable || (exist && rule.AllowOperation(op))
 		}
 	}
 
+	// 如果根本没有遍历过，则表示该资源下没有对应的策略列表，直接返回可编辑状态即可
+	if !isCheck && !mustCheck {
+		return true
+	}
 	return editable
 }
 
+// checkResourceDenied 判断是否存在包含该 principal、且禁止执行 op 操作的拒绝策略
+func (sc *strategyCache) checkResourceDenied(strategIds []string, principal model.Principal,
+	op model.ResourceOperation) bool {
+	for i := range strategIds {
+		rule, ok := sc.strategys.get(strategIds[i])
+		if !ok || !rule.DenyOperation(op) {
+			continue
+		}
+		var exist bool
+		if principal.PrincipalRole == model.PrincipalUser {
+			_, exist = rule.UserPrincipal[principal.PrincipalID]
+		} else {
+			_, exist = rule.GroupPrincipal[principal.PrincipalID]
+		}
+		if exist {
+			return true
+		}
+	}
+	return false
+}
+
 // IsResourceEditable 判断当前资源是否可以操作
 // 这里需要考虑两种情况，一种是 “ * ” 策略，另一种是明确指出了具体的资源ID的策略
 func (sc *strategyCache) IsResourceEditable(
 	principal model.Principal, resType apisecurity.ResourceType, resId string) bool {
-	var (
-		valAll, val []string
-		ok          bool
-	)
+	return sc.IsResourceOperable(principal, resType, resId, model.Modify)
+}
+
+// IsResourceOperable 判断 principal 是否可以对当前资源执行 op 操作
+// 只有 principal 关联的、且动作包含 op 的策略才会授予该资源的操作权限
+// 拒绝策略优先：只要 principal 或其所在用户组命中了禁止 op 的拒绝策略，无论是否有授权策略均不可操作
+func (sc *strategyCache) IsResourceOperable(principal model.Principal, resType apisecurity.ResourceType,
+	resId string, op model.ResourceOperation) bool {
+	val, valAll, ok := sc.resourceStrategies(resType, resId)
+	return sc.isOperable(principal, val, valAll, ok, op)
+}
+
+// IsResourceDenied 只计算拒绝策略，用于命名空间的拒绝策略对其下的服务、配置分组以及治理规则同样生效
+func (sc *strategyCache) IsResourceDenied(principal model.Principal, resType apisecurity.ResourceType,
+	resId string, op model.ResourceOperation) bool {
+	val, valAll, _ := sc.resourceStrategies(resType, resId)
+	return sc.isDenied(principal, val, valAll, op)
+}
+
+// resourceStrategies 返回资源直接关联的策略、* 资源关联的策略以及资源是否直接关联了策略
+func (sc *strategyCache) resourceStrategies(resType apisecurity.ResourceType,
+	resId string) (val []string, valAll []string, ok bool) {
 	switch resType {
 	case apisecurity.ResourceType_Namespaces:
 		val, ok = sc.namespace2Strategy.get(resId)
@@ -395,12 +475,72 @@ func (sc *strategyCache) IsResourceEditable(
 		val, ok = sc.configGroup2Strategy.get(resId)
 		valAll, _ = sc.configGroup2Strategy.get("*")
 	}
+	return val, valAll, ok
+}
 
+// IsRuleResourceOperable 判断 principal 是否可以对治理规则执行 op 操作
+// 规则ID精确匹配以及 name:<pattern> 名称匹配的策略等同于具体资源的策略，* 策略的处理方式与其他资源保持一致
+func (sc *strategyCache) IsRuleResourceOperable(principal model.Principal, resType model.RuleResourceType,
+	ruleId, ruleName string, op model.ResourceOperation) bool {
+	bucket, exist := sc.rule2Strategy[resType]
+	if !exist {
+		return true
+	}
+	var (
+		val       = make([]string, 0, 4)
+		valAll, _ = bucket.get("*")
+		ok        bool
+	)
+	bucket.foreach(func(resId string, strategyIds []string) {
+		if resId == "*" || !model.MatchRuleResource(resId, ruleId, ruleName) {
+			return
+		}
+		ok = true
+		val = append(val, strategyIds...)
+	})
+	return sc.isOperable(principal, val, valAll, ok, op)
+}
+
+// isOperable val 为资源直接关联的策略，valAll 为 * 资源关联的策略，linked 表示资源是否直接关联了策略
+func (sc *strategyCache) isOperable(principal model.Principal, val, valAll []string, linked bool,
+	op model.ResourceOperation) bool {
+	if sc.isDenied(principal, val, valAll, op) {
+		return false
+	}
 	// 代表该资源没有关联到任何策略，任何人都可以编辑
-	if !ok {
+	if !linked {
 		return true
 	}
 
+	principals := sc.expandPrincipal(principal)
+
+	for i := range principals {
+		item := principals[i]
+		if valAll != nil && sc.checkResourceEditable(valAll, item, true, op) {
+			return true
+		}
+
+		if sc.checkResourceEditable(val, item, false, op) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDenied principal 以及其所在的用户组是否命中了禁止 op 的拒绝策略
+func (sc *strategyCache) isDenied(principal model.Principal, val, valAll []string, op model.ResourceOperation) bool {
+	principals := sc.expandPrincipal(principal)
+	for i := range principals {
+		if sc.checkResourceDenied(val, principals[i], op) || sc.checkResourceDenied(valAll, principals[i], op) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandPrincipal 用户需要同时计算其所在的用户组
+func (sc *strategyCache) expandPrincipal(principal model.Principal) []model.Principal {
 	principals := make([]model.Principal, 0, 4)
 	principals = append(principals, principal)
 	if principal.PrincipalRole == model.PrincipalUser {
@@ -412,19 +552,116 @@ func (sc *strategyCache) IsResourceEditable(
 			})
 		}
 	}
+	return principals
+}
 
-	for i := range principals {
-		item := principals[i]
-		if valAll != nil && sc.checkResourceEditable(valAll, item, true) {
-			return true
+// GetStrategyConflicts 计算与该鉴权策略相反生效方式的策略之间的冲突
+// 当授权策略与拒绝策略覆盖同一个成员（包括用户所在的用户组）、同一个资源（包括 * 资源）以及同一个写操作时，视为冲突
+// rule 可以是尚未同步到缓存中的策略，缓存中同 ID 的策略会被忽略
+func (sc *strategyCache) GetStrategyConflicts(rule *model.StrategyDetail) []model.StrategyConflict {
+	if rule == nil {
+		return nil
+	}
+	target := buildEnchanceStrategyDetail(rule)
+
+	ret := make([]model.StrategyConflict, 0, 4)
+	sc.strategys.foreach(func(_ string, item *model.StrategyDetailCache) {
+		if item.ID == target.ID || item.IsDeny() == target.IsDeny() {
+			return
+		}
+		allow, deny := target, item
+		if target.IsDeny() {
+			allow, deny = item, target
 		}
+		ret = append(ret, sc.computeStrategyConflicts(allow, deny)...)
+	})
+	return ret
+}
 
-		if sc.checkResourceEditable(val, item, false) {
-			return true
+func (sc *strategyCache) computeStrategyConflicts(allow, deny *model.StrategyDetailCache) []model.StrategyConflict {
+	ops := make([]model.ResourceOperation, 0, 3)
+	for _, op := range allow.Operations() {
+		// 读操作不做鉴权，不会产生实际的冲突
+		if op != model.Read && deny.DenyOperation(op) {
+			ops = append(ops, op)
 		}
 	}
+	if len(ops) == 0 {
+		return nil
+	}
 
-	return false
+	principals := sc.overlapPrincipals(allow, deny)
+	if len(principals) == 0 {
+		return nil
+	}
+
+	ret := make([]model.StrategyConflict, 0, len(principals))
+	for _, allowRes := range allow.Resources {
+		for _, denyRes := range deny.Resources {
+			if allowRes.ResType != denyRes.ResType {
+				continue
+			}
+			resId := allowRes.ResID
+			switch {
+			case allowRes.ResID == denyRes.ResID, denyRes.ResID == "*":
+			case allowRes.ResID == "*":
+				resId = denyRes.ResID
+			default:
+				continue
+			}
+			for i := range principals {
+				ret = append(ret, model.StrategyConflict{
+					Allow:      allow.StrategyDetail,
+					Deny:       deny.StrategyDetail,
+					Principal:  principals[i],
+					ResType:    allowRes.ResType,
+					ResID:      resId,
+					Operations: ops,
+				})
+			}
+		}
+	}
+	return ret
+}
+
+// overlapPrincipals 计算授权策略中会被拒绝策略覆盖的成员
+func (sc *strategyCache) overlapPrincipals(allow, deny *model.StrategyDetailCache) []model.Principal {
+	ret := make([]model.Principal, 0, 4)
+	seen := make(map[string]struct{}, 4)
+	add := func(principal model.Principal) {
+		key := fmt.Sprintf("%d_%s", principal.PrincipalRole, principal.PrincipalID)
+		if _, ok := seen[key]; ok {
+			return
+		}
+		seen[key] = struct{}{}
+		ret = append(ret, principal)
+	}
+
+	for uid, principal := range allow.UserPrincipal {
+		if _, ok := deny.UserPrincipal[uid]; ok {
+			add(principal)
+			continue
+		}
+		for _, gid := range sc.userCache.GetUserLinkGroupIds(uid) {
+			if _, ok := deny.GroupPrincipal[gid]; ok {
+				add(principal)
+				break
+			}
+		}
+	}
+	for gid, principal := range allow.GroupPrincipal {
+		if _, ok := deny.GroupPrincipal[gid]; ok {
+			add(principal)
+			continue
+		}
+		// 用户组中的部分用户被拒绝
+		for uid, item := range deny.UserPrincipal {
+			if sc.userCache.IsUserInGroup(uid, gid) {
+				add(item)
+			}
+		}
+	}
+	return ret
 }
 
 func (sc *strategyCache) GetStrategyDetailsByUID(uid string) []*model.StrategyDetail {
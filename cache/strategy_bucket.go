@@ -50,6 +50,15 @@ func (s *strategyBucket) get(key string) (*model.StrategyDetailCache, bool) {
 	return val, ok
 }
 
+func (s *strategyBucket) foreach(proc func(key string, val *model.StrategyDetailCache)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for k, v := range s.strategies {
+		proc(k, v)
+	}
+}
+
 type strategyIdBucket struct {
 	lock sync.RWMutex
 	ids  map[string]struct{}
@@ -134,3 +143,12 @@ func (s *strategyLinkBucket) get(key string) ([]string, bool) {
 	}
 	return val.toSlice(), ok
 }
+
+func (s *strategyLinkBucket) foreach(proc func(linkId string, strategyIds []string)) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for k, v := range s.strategies {
+		proc(k, v.toSlice())
+	}
+}
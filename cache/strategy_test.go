@@ -333,6 +333,208 @@ func Test_strategyCache_IsResourceEditable_1(t *testing.T) {
 	})
 }
 
+func Test_strategyCache_IsResourceOperable(t *testing.T) {
+	newCache := func() (*strategyCache, *userCache) {
+		userCache := &userCache{}
+		userCache.initBuckets()
+		strategyCache := &strategyCache{
+			baseCache: newBaseCache(nil),
+			userCache: userCache,
+		}
+		strategyCache.initBuckets()
+		return strategyCache, userCache
+	}
+	buildRule := func(id, action string, principal model.Principal, resId string) *model.StrategyDetail {
+		return &model.StrategyDetail{
+			ID:         id,
+			Name:       id,
+			Action:     action,
+			Principals: []model.Principal{principal},
+			Valid:      true,
+			Resources: []model.StrategyResource{
+				{
+					StrategyID: id,
+					ResType:    int32(apisecurity.ResourceType_Namespaces),
+					ResID:      resId,
+				},
+			},
+		}
+	}
+	user1 := model.Principal{PrincipalID: "user-1", PrincipalRole: model.PrincipalUser}
+
+	t.Run("只读策略-不允许写操作", func(t *testing.T) {
+		strategyCache, _ := newCache()
+		strategyCache.setStrategys([]*model.StrategyDetail{
+			buildRule("rule-1", model.ActionOnlyRead, user1, "namespace-1"),
+		})
+
+		for _, op := range []model.ResourceOperation{model.Create, model.Modify, model.Delete} {
+			assert.False(t, strategyCache.IsResourceOperable(user1,
+				apisecurity.ResourceType_Namespaces, "namespace-1", op), op.String())
+		}
+		assert.True(t, strategyCache.IsResourceOperable(user1,
+			apisecurity.ResourceType_Namespaces, "namespace-1", model.Read))
+		assert.False(t, strategyCache.IsResourceEditable(user1, apisecurity.ResourceType_Namespaces, "namespace-1"))
+	})
+
+	t.Run("按操作授权-只允许授予的操作", func(t *testing.T) {
+		strategyCache, _ := newCache()
+		strategyCache.setStrategys([]*model.StrategyDetail{
+			buildRule("rule-1", "READ,MODIFY", user1, "*"),
+		})
+
+		assert.True(t, strategyCache.IsResourceOperable(user1,
+			apisecurity.ResourceType_Namespaces, "namespace-1", model.Modify))
+		assert.False(t, strategyCache.IsResourceOperable(user1,
+			apisecurity.ResourceType_Namespaces, "namespace-1", model.Delete))
+	})
+
+	t.Run("用户组读写策略与用户只读策略叠加-允许写操作", func(t *testing.T) {
+		strategyCache, userCache := newCache()
+		userCache.user2Groups.save("user-1")
+		links, _ := userCache.user2Groups.get("user-1")
+		links.save("group-1")
+
+		strategyCache.setStrategys([]*model.StrategyDetail{
+			buildRule("rule-1", model.ActionOnlyRead, user1, "namespace-1"),
+			buildRule("rule-2", model.ActionReadWrite, model.Principal{
+				PrincipalID:   "group-1",
+				PrincipalRole: model.PrincipalGroup,
+			}, "namespace-1"),
+		})
+
+		assert.True(t, strategyCache.IsResourceOperable(user1,
+			apisecurity.ResourceType_Namespaces, "namespace-1", model.Delete))
+	})
+
+	t.Run("历史数据action为空-按读写处理", func(t *testing.T) {
+		strategyCache, _ := newCache()
+		strategyCache.setStrategys([]*model.StrategyDetail{
+			buildRule("rule-1", "", user1, "namespace-1"),
+		})
+
+		assert.True(t, strategyCache.IsResourceOperable(user1,
+			apisecurity.ResourceType_Namespaces, "namespace-1", model.Delete))
+	})
+}
+
+func Test_strategyCache_DenyOverrides(t *testing.T) {
+	userCache := &userCache{}
+	userCache.initBuckets()
+	strategyCache := &strategyCache{
+		baseCache: newBaseCache(nil),
+		userCache: userCache,
+	}
+	strategyCache.initBuckets()
+
+	userCache.groups.save("contractors", &model.UserGroupDetail{
+		UserGroup: &model.UserGroup{
+			ID: "contractors",
+		},
+		UserIds: map[string]struct{}{
+			"user-1": {},
+		},
+	})
+	userCache.user2Groups.save("user-1")
+	links, _ := userCache.user2Groups.get("user-1")
+	links.save("contractors")
+
+	allow := &model.StrategyDetail{
+		ID:     "allow-all",
+		Name:   "allow-all",
+		Owner:  "polaris",
+		Action: model.ActionReadWrite,
+		Principals: []model.Principal{
+			{PrincipalID: "user-1", PrincipalRole: model.PrincipalUser},
+			{PrincipalID: "user-2", PrincipalRole: model.PrincipalUser},
+		},
+		Valid: true,
+		Resources: []model.StrategyResource{
+			{StrategyID: "allow-all", ResType: int32(apisecurity.ResourceType_Namespaces), ResID: "*"},
+			{StrategyID: "allow-all", ResType: int32(apisecurity.ResourceType_Namespaces), ResID: "Production"},
+		},
+	}
+	deny := &model.StrategyDetail{
+		ID:     "deny-production",
+		Name:   "deny-production",
+		Owner:  "polaris",
+		Action: model.ActionReadWrite,
+		Effect: model.StrategyEffectDeny,
+		Principals: []model.Principal{
+			{PrincipalID: "contractors", PrincipalRole: model.PrincipalGroup},
+		},
+		Valid: true,
+		Resources: []model.StrategyResource{
+			{StrategyID: "deny-production", ResType: int32(apisecurity.ResourceType_Namespaces), ResID: "Production"},
+		},
+	}
+	strategyCache.setStrategys([]*model.StrategyDetail{allow, deny})
+
+	user1 := model.Principal{PrincipalID: "user-1", PrincipalRole: model.PrincipalUser}
+	user2 := model.Principal{PrincipalID: "user-2", PrincipalRole: model.PrincipalUser}
+
+	t.Run("拒绝策略优先于授权策略", func(t *testing.T) {
+		assert.False(t, strategyCache.IsResourceOperable(user1,
+			apisecurity.ResourceType_Namespaces, "Production", model.Delete))
+		assert.False(t, strategyCache.IsResourceEditable(user1, apisecurity.ResourceType_Namespaces, "Production"))
+		assert.True(t, strategyCache.IsResourceOperable(user2,
+			apisecurity.ResourceType_Namespaces, "Production", model.Delete))
+	})
+
+	t.Run("拒绝策略不影响其他资源", func(t *testing.T) {
+		strategyCache.namespace2Strategy.save("Test", "allow-all")
+		assert.True(t, strategyCache.IsResourceEditable(user1, apisecurity.ResourceType_Namespaces, "Test"))
+	})
+
+	t.Run("只计算拒绝策略", func(t *testing.T) {
+		assert.True(t, strategyCache.IsResourceDenied(user1,
+			apisecurity.ResourceType_Namespaces, "Production", model.Modify))
+		assert.False(t, strategyCache.IsResourceDenied(user2,
+			apisecurity.ResourceType_Namespaces, "Production", model.Modify))
+		assert.False(t, strategyCache.IsResourceDenied(user1,
+			apisecurity.ResourceType_Namespaces, "Test", model.Modify))
+	})
+
+	t.Run("只关联了拒绝策略的资源-其他人仍可以操作", func(t *testing.T) {
+		denyOnly := &model.StrategyDetail{
+			ID:     "deny-only",
+			Name:   "deny-only",
+			Action: "DELETE",
+			Effect: model.StrategyEffectDeny,
+			Principals: []model.Principal{
+				{PrincipalID: "user-2", PrincipalRole: model.PrincipalUser},
+			},
+			Valid: true,
+			Resources: []model.StrategyResource{
+				{StrategyID: "deny-only", ResType: int32(apisecurity.ResourceType_Services), ResID: "svc-1"},
+			},
+		}
+		strategyCache.setStrategys([]*model.StrategyDetail{denyOnly})
+
+		assert.True(t, strategyCache.IsResourceOperable(user1,
+			apisecurity.ResourceType_Services, "svc-1", model.Delete))
+		assert.True(t, strategyCache.IsResourceOperable(user2,
+			apisecurity.ResourceType_Services, "svc-1", model.Modify))
+		assert.False(t, strategyCache.IsResourceOperable(user2,
+			apisecurity.ResourceType_Services, "svc-1", model.Delete))
+	})
+
+	t.Run("计算授权策略与拒绝策略的冲突", func(t *testing.T) {
+		conflicts := strategyCache.GetStrategyConflicts(deny)
+		// user-1 通过 contractors 用户组被拒绝，* 以及 Production 两条授权资源都与 Production 冲突
+		assert.Equal(t, 2, len(conflicts))
+		for _, item := range conflicts {
+			assert.Equal(t, "allow-all", item.Allow.ID)
+			assert.Equal(t, "deny-production", item.Deny.ID)
+			assert.Equal(t, "user-1", item.Principal.PrincipalID)
+			assert.Equal(t, "Production", item.ResID)
+			assert.Equal(t, []model.ResourceOperation{model.Create, model.Modify, model.Delete}, item.Operations)
+		}
+
+		assert.Equal(t, 2, len(strategyCache.GetStrategyConflicts(allow)))
+	})
+}
+
 func buildStrategies(num int) []*model.StrategyDetail {
 
 	ret := make([]*model.StrategyDetail, 0, num)
@@ -383,3 +585,98 @@ func testBuildPrincipalMap(principals []model.Principal, role model.PrincipalTyp
 
 	return ret
 }
+
+func Test_strategyCache_IsRuleResourceOperable(t *testing.T) {
+	userCache := &userCache{}
+	userCache.initBuckets()
+	strategyCache := &strategyCache{
+		baseCache: newBaseCache(nil),
+		userCache: userCache,
+	}
+	strategyCache.initBuckets()
+
+	routeRules := int32(model.ResourceTypeRouteRules)
+	ratelimitRules := int32(model.ResourceTypeRateLimitRules)
+	strategyCache.setStrategys([]*model.StrategyDetail{
+		{
+			ID:     "route-owner",
+			Name:   "route-owner",
+			Action: model.ActionReadWrite,
+			Principals: []model.Principal{
+				{PrincipalID: "user-1", PrincipalRole: model.PrincipalUser},
+			},
+			Valid: true,
+			Resources: []model.StrategyResource{
+				{StrategyID: "route-owner", ResType: routeRules, ResID: "rule-1"},
+				{StrategyID: "route-owner", ResType: ratelimitRules, ResID: "name:order-*"},
+			},
+		},
+		{
+			ID:     "deny-ratelimit-delete",
+			Name:   "deny-ratelimit-delete",
+			Action: "DELETE",
+			Effect: model.StrategyEffectDeny,
+			Principals: []model.Principal{
+				{PrincipalID: "user-1", PrincipalRole: model.PrincipalUser},
+			},
+			Valid: true,
+			Resources: []model.StrategyResource{
+				{StrategyID: "deny-ratelimit-delete", ResType: ratelimitRules, ResID: "name:order-core-*"},
+			},
+		},
+	})
+
+	user1 := model.Principal{PrincipalID: "user-1", PrincipalRole: model.PrincipalUser}
+	user2 := model.Principal{PrincipalID: "user-2", PrincipalRole: model.PrincipalUser}
+
+	t.Run("按照规则ID授权", func(t *testing.T) {
+		assert.True(t, strategyCache.IsRuleResourceOperable(user1, model.ResourceTypeRouteRules,
+			"rule-1", "", model.Modify))
+		assert.False(t, strategyCache.IsRuleResourceOperable(user2, model.ResourceTypeRouteRules,
+			"rule-1", "", model.Modify))
+		// 没有关联策略的规则任何人都可以操作
+		assert.True(t, strategyCache.IsRuleResourceOperable(user2, model.ResourceTypeRouteRules,
+			"rule-2", "", model.Modify))
+		// 不同类型的规则之间互不影响
+		assert.True(t, strategyCache.IsRuleResourceOperable(user2, model.ResourceTypeFaultDetectRules,
+			"rule-1", "", model.Modify))
+	})
+
+	t.Run("按照规则名称通配授权", func(t *testing.T) {
+		assert.True(t, strategyCache.IsRuleResourceOperable(user1, model.ResourceTypeRateLimitRules,
+			"", "order-limit", model.Create))
+		assert.False(t, strategyCache.IsRuleResourceOperable(user2, model.ResourceTypeRateLimitRules,
+			"", "order-limit", model.Create))
+		assert.True(t, strategyCache.IsRuleResourceOperable(user2, model.ResourceTypeRateLimitRules,
+			"limit-1", "user-limit", model.Create))
+	})
+
+	t.Run("拒绝策略按照名称通配生效", func(t *testing.T) {
+		assert.True(t, strategyCache.IsRuleResourceOperable(user1, model.ResourceTypeRateLimitRules,
+			"limit-2", "order-core-limit", model.Modify))
+		assert.False(t, strategyCache.IsRuleResourceOperable(user1, model.ResourceTypeRateLimitRules,
+			"limit-2", "order-core-limit", model.Delete))
+	})
+
+	t.Run("*策略覆盖全部规则", func(t *testing.T) {
+		strategyCache.setStrategys([]*model.StrategyDetail{
+			{
+				ID:     "route-admin",
+				Name:   "route-admin",
+				Action: model.ActionReadWrite,
+				Principals: []model.Principal{
+					{PrincipalID: "user-2", PrincipalRole: model.PrincipalUser},
+				},
+				Valid: true,
+				Resources: []model.StrategyResource{
+					{StrategyID: "route-admin", ResType: routeRules, ResID: "*"},
+				},
+			},
+		})
+		assert.True(t, strategyCache.IsRuleResourceOperable(user2, model.ResourceTypeRouteRules,
+			"rule-1", "", model.Delete))
+		// 与其他资源保持一致，没有直接关联策略的规则不受 * 策略的限制
+		assert.True(t, strategyCache.IsRuleResourceOperable(user1, model.ResourceTypeRouteRules,
+			"rule-3", "", model.Delete))
+	})
+}
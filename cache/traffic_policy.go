@@ -0,0 +1,61 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cache
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	// TrafficPolicyName traffic policy cache name
+	TrafficPolicyName = "trafficPolicy"
+)
+
+// TrafficPolicyCache 流量策略缓存
+type TrafficPolicyCache interface {
+	Cache
+	// GetTrafficPolicies 获取服务下已经启用的流量策略以及这些策略的整体版本号
+	GetTrafficPolicies(name string, namespace string) ([]*model.TrafficPolicy, string)
+}
+
+type trafficPolicyCache struct {
+	*servicePolicyCache[*model.TrafficPolicy]
+}
+
+// init 自注册到缓存列表
+func init() {
+	RegisterCache(TrafficPolicyName, CacheTrafficPolicy)
+}
+
+// newTrafficPolicyCache trafficPolicyCache constructor
+func newTrafficPolicyCache(s store.Store) *trafficPolicyCache {
+	fetch := func(mtime time.Time, firstUpdate bool) ([]*model.TrafficPolicy, error) {
+		return s.GetTrafficPoliciesForCache(mtime, firstUpdate)
+	}
+	return &trafficPolicyCache{
+		servicePolicyCache: newServicePolicyCache(s, TrafficPolicyName, fetch),
+	}
+}
+
+// GetTrafficPolicies 获取服务下已经启用的流量策略，按照ID排序
+func (tc *trafficPolicyCache) GetTrafficPolicies(name string, namespace string) ([]*model.TrafficPolicy, string) {
+	return tc.getPolicies(name, namespace)
+}
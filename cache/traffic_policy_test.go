@@ -0,0 +1,112 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store/mock"
+)
+
+func newTestTrafficPolicyCache(t *testing.T) (*gomock.Controller, *mock.MockStore, *trafficPolicyCache) {
+	ctl := gomock.NewController(t)
+
+	storage := mock.NewMockStore(ctl)
+	tc := newTrafficPolicyCache(storage)
+	storage.EXPECT().GetUnixSecond(gomock.Any()).AnyTimes().Return(time.Now().Unix(), nil)
+	_ = tc.initialize(nil)
+	return ctl, storage, tc
+}
+
+func TestTrafficPolicyCacheUpdate(t *testing.T) {
+	ctl, storage, tc := newTestTrafficPolicyCache(t)
+	defer ctl.Finish()
+
+	policies := []*model.TrafficPolicy{
+		{
+			ServicePolicy: model.ServicePolicy{
+				ID:         "policy-1",
+				Namespace:  "default",
+				Service:    "echo",
+				Enable:     true,
+				Valid:      true,
+				Rule:       `{"timeout":3000,"retry":{"retry_on":["5xx"],"num_retries":2}}`,
+				Revision:   "rev-1",
+				ModifyTime: time.Unix(10, 0),
+			},
+		},
+		{
+			ServicePolicy: model.ServicePolicy{
+				ID:         "policy-2",
+				Namespace:  "default",
+				Service:    "echo",
+				Enable:     false,
+				Valid:      true,
+				Revision:   "rev-2",
+				ModifyTime: time.Unix(11, 0),
+			},
+			RouteName: "gray",
+		},
+	}
+	storage.EXPECT().GetTrafficPoliciesForCache(gomock.Any(), true).Return(policies, nil)
+	assert.NoError(t, tc.update())
+
+	ret, revision := tc.GetTrafficPolicies("echo", "default")
+	assert.Equal(t, 1, len(ret))
+	assert.Equal(t, "policy-1", ret[0].ID)
+	assert.NotEmpty(t, revision)
+	assert.Equal(t, uint32(3000), ret[0].Spec.Timeout)
+	assert.Equal(t, uint32(2), ret[0].Spec.Retry.NumRetries)
+
+	t.Run("启用策略之后版本号变化", func(t *testing.T) {
+		enabled := *policies[1]
+		enabled.Enable = true
+		enabled.ModifyTime = time.Unix(12, 0)
+		storage.EXPECT().GetTrafficPoliciesForCache(gomock.Any(), false).
+			Return([]*model.TrafficPolicy{&enabled}, nil)
+		assert.NoError(t, tc.update())
+
+		ret, newRevision := tc.GetTrafficPolicies("echo", "default")
+		assert.Equal(t, 2, len(ret))
+		assert.NotEqual(t, revision, newRevision)
+		assert.Equal(t, int64(12), tc.LastMtime(tc.name()).Unix())
+	})
+
+	t.Run("策略更换服务以及删除", func(t *testing.T) {
+		moved := *policies[0]
+		moved.Service = "echo-v2"
+		moved.Revision = "rev-3"
+		deleted := *policies[1]
+		deleted.Valid = false
+		storage.EXPECT().GetTrafficPoliciesForCache(gomock.Any(), false).
+			Return([]*model.TrafficPolicy{&moved, &deleted}, nil)
+		assert.NoError(t, tc.update())
+
+		ret, revision := tc.GetTrafficPolicies("echo", "default")
+		assert.Equal(t, 0, len(ret))
+		assert.Empty(t, revision)
+
+		ret, _ = tc.GetTrafficPolicies("echo-v2", "default")
+		assert.Equal(t, 1, len(ret))
+	})
+}
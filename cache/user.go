@@ -77,6 +77,11 @@ type UserCache interface {
 	//  @param id
 	//  @return []string
 	GetUserLinkGroupIds(id string) []string
+
+	// IsTokenRevoked 临时 token 是否已经被吊销
+	//  @param id
+	//  @return bool
+	IsTokenRevoked(id string) bool
 }
 
 type userRefreshResult struct {
@@ -96,10 +101,11 @@ type userCache struct {
 	storage store.Store
 
 	adminUser   atomic.Value
-	users       *userBucket       // userid -> user
-	name2Users  *usernameBucket   // username -> user
-	groups      *groupBucket      // groupid -> group
-	user2Groups *userGroupsBucket // userid -> groups
+	users       *userBucket         // userid -> user
+	name2Users  *usernameBucket     // username -> user
+	groups      *groupBucket        // groupid -> group
+	user2Groups *userGroupsBucket   // userid -> groups
+	revoked     *revokedTokenBucket // token-id -> expire time
 
 	lastUserMtime  int64
 	lastGroupMtime int64
@@ -140,6 +146,10 @@ func (uc *userCache) initBuckets() {
 		lock:   sync.RWMutex{},
 		groups: make(map[string]*groupIdSlice),
 	}
+	uc.revoked = &revokedTokenBucket{
+		lock:   sync.RWMutex{},
+		tokens: make(map[string]time.Time),
+	}
 }
 
 func (uc *userCache) update() error {
@@ -164,7 +174,13 @@ func (uc *userCache) realUpdate() (map[string]time.Time, int64, error) {
 		log.Errorf("[Cache][Group] update group err: %s", err.Error())
 		return nil, -1, err
 	}
+	revokedTokens, err := uc.storage.GetRevokedTokensForCache(uc.LastFetchTime(), uc.isFirstUpdate())
+	if err != nil {
+		log.Errorf("[Cache][User] update revoked token err: %s", err.Error())
+		return nil, -1, err
+	}
 	lastMimes, refreshRet := uc.setUserAndGroups(users, groups)
+	uc.handlerRevokedTokenUpdate(lastMimes, revokedTokens)
 
 	timeDiff := time.Since(start)
 	if timeDiff > time.Second {
@@ -180,7 +196,7 @@ func (uc *userCache) realUpdate() (map[string]time.Time, int64, error) {
 			zap.Int("delete", refreshRet.groupDel),
 			zap.Time("last", time.Unix(uc.lastGroupMtime, 0)), zap.Duration("used", time.Since(start)))
 	}
-	return lastMimes, int64(len(users) + len(groups)), nil
+	return lastMimes, int64(len(users) + len(groups) + len(revokedTokens)), nil
 }
 
 func (uc *userCache) setUserAndGroups(users []*model.User,
@@ -314,6 +330,17 @@ func (uc *userCache) handlerGroupCacheUpdate(lastMimes map[string]time.Time, ret
 	lastMimes["group"] = time.Unix(lastGroupMtime, 0)
 }
 
+// handlerRevokedTokenUpdate 处理临时 token 吊销列表更新，同时清理已经过期的吊销记录
+func (uc *userCache) handlerRevokedTokenUpdate(lastMimes map[string]time.Time, tokens []*model.RevokedToken) {
+	lastMtime := uc.LastMtime("revoked_token").Unix()
+	for i := range tokens {
+		lastMtime = int64(math.Max(float64(lastMtime), float64(tokens[i].ModifyTime.Unix())))
+		uc.revoked.save(tokens[i].ID, tokens[i].ExpireTime)
+	}
+	uc.revoked.cleanExpired(time.Now())
+	lastMimes["revoked_token"] = time.Unix(lastMtime, 0)
+}
+
 func (uc *userCache) clear() error {
 	uc.baseCache.clear()
 	uc.initBuckets()
@@ -404,3 +431,11 @@ func (uc *userCache) GetUserLinkGroupIds(userId string) []string {
 	}
 	return val.toSlice()
 }
+
+// IsTokenRevoked 临时 token 是否已经被吊销
+func (uc *userCache) IsTokenRevoked(id string) bool {
+	if id == "" {
+		return false
+	}
+	return uc.revoked.exist(id)
+}
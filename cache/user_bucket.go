@@ -19,6 +19,7 @@ package cache
 
 import (
 	"sync"
+	"time"
 
 	"github.com/polarismesh/polaris/common/model"
 )
@@ -185,3 +186,35 @@ func (u *groupBucket) delete(key string) {
 
 	delete(u.groups, key)
 }
+
+// revokedTokenBucket 临时 token 的吊销列表，记录 token 的过期时间，过期后即可清理
+type revokedTokenBucket struct {
+	lock   sync.RWMutex
+	tokens map[string]time.Time
+}
+
+func (b *revokedTokenBucket) exist(id string) bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	_, ok := b.tokens[id]
+	return ok
+}
+
+func (b *revokedTokenBucket) save(id string, expireTime time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.tokens[id] = expireTime
+}
+
+func (b *revokedTokenBucket) cleanExpired(now time.Time) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for id, expireTime := range b.tokens {
+		if expireTime.Before(now) {
+			delete(b.tokens, id)
+		}
+	}
+}
@@ -151,6 +151,7 @@ func TestUserCache_UpdateNormal(t *testing.T) {
 		}
 		store.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).Return(copyUsers, nil).Times(1)
 		store.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).Return(copyGroups, nil).Times(1)
+		store.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
 
 		assert.NoError(t, uc.update())
 
@@ -208,6 +209,7 @@ func TestUserCache_UpdateNormal(t *testing.T) {
 
 		store.EXPECT().GetUsersForCache(gomock.Any(), gomock.Any()).Return(copyUsers, nil).Times(1)
 		store.EXPECT().GetGroupsForCache(gomock.Any(), gomock.Any()).Return(copyGroups, nil).Times(1)
+		store.EXPECT().GetRevokedTokensForCache(gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
 
 		assert.NoError(t, uc.update())
 
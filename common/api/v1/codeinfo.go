@@ -323,7 +323,7 @@ var code2info = map[uint32]string{
 	InvalidConfigFileGroupName:     "invalid config file group name",
 	InvalidConfigFileName:          "invalid config file name",
 	InvalidConfigFileContentLength: "config file content too long",
-	InvalidConfigFileFormat:        "invalid config file format, support json,xml,html,properties,text,yaml",
+	InvalidConfigFileFormat:        "invalid config file format, support json,xml,html,properties,text,yaml,toml",
 	InvalidConfigFileTags: "invalid config file tags, tags should be pair, like key1,value1,key2,value2, " +
 		"both key and value should not blank",
 	InvalidWatchConfigFileFormat:  "invalid watch config file format",
@@ -23,4 +23,6 @@ const (
 	InstanceEventTopic = "instance_event"
 	// LeaderChangeEventTopic
 	LeaderChangeEventTopic = "leader_change_event"
+	// ConfigFilePublishTopic 配置文件发布事件主题
+	ConfigFilePublishTopic = "config_file_publish_event"
 )
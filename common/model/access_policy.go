@@ -0,0 +1,53 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+// AccessPolicy 服务的访问控制策略，只允许策略中声明的主调服务访问所属的服务
+// 同一个服务下的多个策略之间为或的关系，DryRun 为 true 时只记录会被拒绝的请求，不做拦截
+type AccessPolicy struct {
+	ServicePolicy
+	DryRun bool
+	// Spec 反序列化之后的策略内容，不做持久化
+	Spec *AccessPolicySpec
+}
+
+// ParseSpec 解析策略内容
+func (p *AccessPolicy) ParseSpec() error {
+	spec := &AccessPolicySpec{}
+	if err := p.unmarshalRule(spec); err != nil {
+		return err
+	}
+	p.Spec = spec
+	return nil
+}
+
+// AccessPolicySpec 访问控制策略内容
+type AccessPolicySpec struct {
+	// Sources 允许访问的主调服务
+	Sources []*AccessSource `json:"sources"`
+	// Paths 允许访问的路径，以 * 结尾时按照前缀匹配，为空时不限制
+	Paths []string `json:"paths,omitempty"`
+	// Methods 允许访问的 http 方法，为空时不限制
+	Methods []string `json:"methods,omitempty"`
+}
+
+// AccessSource 允许访问的主调服务，Service 为 * 时表示命名空间下的全部服务，Namespace 为 * 时表示全部服务
+type AccessSource struct {
+	Namespace string `json:"namespace"`
+	Service   string `json:"service"`
+}
@@ -35,6 +35,8 @@ type AcquireContext struct {
 	operation ResourceOperation
 	// Resources 本次
 	accessResources map[apisecurity.ResourceType][]ResourceEntry
+	// ruleResources 本次操作涉及的治理规则
+	ruleResources map[RuleResourceType][]ResourceEntry
 	// Attachment 携带信息，用于操作完权限检查和资源操作的后置处理逻辑，解决信息需要二次查询问题
 	attachment map[string]interface{}
 	// fromClient 是否来自客户端的请求
@@ -57,6 +59,7 @@ func NewAcquireContext(options ...acquireContextOption) *AcquireContext {
 	authCtx := &AcquireContext{
 		attachment:      make(map[string]interface{}),
 		accessResources: make(map[apisecurity.ResourceType][]ResourceEntry),
+		ruleResources:   make(map[RuleResourceType][]ResourceEntry),
 		module:          UnknowModule,
 	}
 
@@ -120,6 +123,16 @@ func WithAccessResources(accessResources map[apisecurity.ResourceType][]Resource
 	}
 }
 
+// WithRuleResources 设置本次访问的治理规则
+//
+//	@param ruleResources
+//	@return acquireContextOption
+func WithRuleResources(ruleResources map[RuleResourceType][]ResourceEntry) acquireContextOption {
+	return func(authCtx *AcquireContext) {
+		authCtx.ruleResources = ruleResources
+	}
+}
+
 // WithAttachment 设置本次请求的额外携带信息
 //
 //	@param attachment
@@ -194,6 +207,11 @@ func (authCtx *AcquireContext) SetAccessResources(accessRes map[apisecurity.Reso
 	authCtx.accessResources = accessRes
 }
 
+// GetRuleResources 获取本次请求涉及的治理规则
+func (authCtx *AcquireContext) GetRuleResources() map[RuleResourceType][]ResourceEntry {
+	return authCtx.ruleResources
+}
+
 // GetAttachments 获取本次请求的额外携带信息
 func (authCtx *AcquireContext) GetAttachments() map[string]interface{} {
 	return authCtx.attachment
@@ -229,5 +247,13 @@ func (authCtx *AcquireContext) IsAccessResourceEmpty() bool {
 	svcEmpty := len(authCtx.accessResources[apisecurity.ResourceType_Services]) == 0
 	cfgEmpty := len(authCtx.accessResources[apisecurity.ResourceType_ConfigGroups]) == 0
 
-	return nsEmpty && svcEmpty && cfgEmpty
+	if !(nsEmpty && svcEmpty && cfgEmpty) {
+		return false
+	}
+	for _, entries := range authCtx.ruleResources {
+		if len(entries) != 0 {
+			return false
+		}
+	}
+	return true
 }
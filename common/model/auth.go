@@ -20,8 +20,12 @@ package model
 import (
 	"errors"
 	"fmt"
+	"path"
 	"strconv"
+	"strings"
 	"time"
+
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
 )
 
 var (
@@ -48,6 +52,15 @@ var (
 
 	// ErrorTokenDisabled token 已经被禁用
 	ErrorTokenDisabled error = errors.New("token already disabled")
+
+	// ErrorTokenExpired token 已经过期
+	ErrorTokenExpired error = errors.New("token already expired")
+
+	// ErrorTokenRevoked token 已经被吊销
+	ErrorTokenRevoked error = errors.New("token already revoked")
+
+	// ErrorTokenOutOfScope 操作超出了 token 的授权范围
+	ErrorTokenOutOfScope error = errors.New("operation out of token scope")
 )
 
 const (
@@ -148,6 +161,255 @@ const (
 	Delete ResourceOperation = 40
 )
 
+var resourceOperationNames = map[ResourceOperation]string{
+	Read:   "READ",
+	Create: "CREATE",
+	Modify: "MODIFY",
+	Delete: "DELETE",
+}
+
+// String 资源操作的名称
+func (o ResourceOperation) String() string {
+	if name, ok := resourceOperationNames[o]; ok {
+		return name
+	}
+	return strconv.Itoa(int(o))
+}
+
+const (
+	// ActionOnlyRead 只读，与 apisecurity.AuthAction 的枚举名称保持一致
+	ActionOnlyRead = "ONLY_READ"
+	// ActionReadWrite 读写
+	ActionReadWrite = "READ_WRITE"
+)
+
+// allOperations 按照存储顺序排列的全部资源操作
+var allOperations = []ResourceOperation{Read, Create, Modify, Delete}
+
+// ParseStrategyAction 解析鉴权策略的动作
+// 支持 ONLY_READ、READ_WRITE 以及 READ,CREATE,MODIFY,DELETE 任意组合的按操作授权，读操作总是被允许
+// action 为空时按照 READ_WRITE 处理，兼容历史数据
+func ParseStrategyAction(action string) (map[ResourceOperation]struct{}, error) {
+	ret := map[ResourceOperation]struct{}{Read: {}}
+	action = strings.TrimSpace(action)
+	switch strings.ToUpper(action) {
+	case "", ActionReadWrite:
+		for _, op := range allOperations {
+			ret[op] = struct{}{}
+		}
+		return ret, nil
+	case ActionOnlyRead:
+		return ret, nil
+	}
+
+	for _, item := range strings.Split(action, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		found := false
+		for op, name := range resourceOperationNames {
+			if name == item {
+				ret[op] = struct{}{}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid strategy action: %s", item)
+		}
+	}
+	return ret, nil
+}
+
+// NormalizeStrategyAction 将鉴权策略的动作转换为统一的存储格式
+func NormalizeStrategyAction(action string) (string, error) {
+	ops, err := ParseStrategyAction(action)
+	if err != nil {
+		return "", err
+	}
+	switch len(ops) {
+	case 1:
+		return ActionOnlyRead, nil
+	case len(allOperations):
+		return ActionReadWrite, nil
+	}
+	names := make([]string, 0, len(ops))
+	for _, op := range allOperations {
+		if _, ok := ops[op]; ok {
+			names = append(names, op.String())
+		}
+	}
+	return strings.Join(names, ","), nil
+}
+
+const (
+	// StrategyEffectAllow 授权策略，为空时的默认值
+	StrategyEffectAllow = "ALLOW"
+	// StrategyEffectDeny 拒绝策略，命中时优先于任何授权策略
+	StrategyEffectDeny = "DENY"
+)
+
+// NormalizeStrategyEffect 校验并转换鉴权策略的生效方式，为空时按照 ALLOW 处理
+func NormalizeStrategyEffect(effect string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(effect)) {
+	case "", StrategyEffectAllow:
+		return StrategyEffectAllow, nil
+	case StrategyEffectDeny:
+		return StrategyEffectDeny, nil
+	default:
+		return "", fmt.Errorf("invalid strategy effect: %s", effect)
+	}
+}
+
+// IsDeny 是否为拒绝策略
+func (s *StrategyDetail) IsDeny() bool {
+	return strings.EqualFold(s.Effect, StrategyEffectDeny)
+}
+
+// ParseStrategyOperations 解析策略动作所覆盖的操作，无法解析的动作只覆盖读操作
+func ParseStrategyOperations(action string) map[ResourceOperation]struct{} {
+	ops, err := ParseStrategyAction(action)
+	if err != nil {
+		return map[ResourceOperation]struct{}{Read: {}}
+	}
+	return ops
+}
+
+// sortOperations 按照存储顺序输出操作列表
+func sortOperations(ops map[ResourceOperation]struct{}) []ResourceOperation {
+	ret := make([]ResourceOperation, 0, len(ops))
+	for _, op := range allOperations {
+		if _, ok := ops[op]; ok {
+			ret = append(ret, op)
+		}
+	}
+	return ret
+}
+
+// Operations 策略动作所覆盖的操作列表，无法解析的动作只覆盖读操作
+func (s *StrategyDetail) Operations() []ResourceOperation {
+	return sortOperations(ParseStrategyOperations(s.Action))
+}
+
+// HasWriteOperation 策略动作是否覆盖了任意写操作
+func (s *StrategyDetail) HasWriteOperation() bool {
+	return len(ParseStrategyOperations(s.Action)) > 1
+}
+
+func (s *StrategyDetail) coverOperation(op ResourceOperation) bool {
+	_, ok := ParseStrategyOperations(s.Action)[op]
+	return ok
+}
+
+// AllowOperation 判断授权策略的动作是否允许执行该操作，无法解析的动作不授予任何写权限
+func (s *StrategyDetail) AllowOperation(op ResourceOperation) bool {
+	if s.IsDeny() {
+		return false
+	}
+	return s.coverOperation(op)
+}
+
+// DenyOperation 判断拒绝策略的动作是否禁止执行该操作
+func (s *StrategyDetail) DenyOperation(op ResourceOperation) bool {
+	return s.IsDeny() && s.coverOperation(op)
+}
+
+// StrategyConflict 授权策略与拒绝策略作用在同一个成员、同一个资源上时的冲突信息，拒绝策略优先生效
+type StrategyConflict struct {
+	Allow      *StrategyDetail
+	Deny       *StrategyDetail
+	Principal  Principal
+	ResType    int32
+	ResID      string
+	Operations []ResourceOperation
+}
+
+// RuleResourceType 治理规则类型的鉴权资源，apisecurity.ResourceType 中没有定义治理规则，不能复用该枚举
+// 策略资源的 res_type 字段同时保存两类资源，治理规则从 100 开始取值避免与 apisecurity.ResourceType 冲突
+type RuleResourceType int32
+
+const (
+	// ResourceTypeRouteRules 路由规则
+	ResourceTypeRouteRules RuleResourceType = 100 + iota
+	// ResourceTypeRateLimitRules 限流规则
+	ResourceTypeRateLimitRules
+	// ResourceTypeCircuitBreakerRules 熔断规则
+	ResourceTypeCircuitBreakerRules
+	// ResourceTypeFaultDetectRules 主动探测规则
+	ResourceTypeFaultDetectRules
+)
+
+// RuleResourceTypes 全部的治理规则资源类型
+var RuleResourceTypes = []RuleResourceType{
+	ResourceTypeRouteRules,
+	ResourceTypeRateLimitRules,
+	ResourceTypeCircuitBreakerRules,
+	ResourceTypeFaultDetectRules,
+}
+
+// RuleResourceTypeNames 治理规则资源类型对外展示的名称
+var RuleResourceTypeNames = map[RuleResourceType]string{
+	ResourceTypeRouteRules:          "route_rule",
+	ResourceTypeRateLimitRules:      "ratelimit_rule",
+	ResourceTypeCircuitBreakerRules: "circuitbreaker_rule",
+	ResourceTypeFaultDetectRules:    "faultdetect_rule",
+}
+
+// ParseRuleResourceType 根据名称解析治理规则资源类型
+func ParseRuleResourceType(name string) (RuleResourceType, error) {
+	for resType, item := range RuleResourceTypeNames {
+		if strings.EqualFold(item, strings.TrimSpace(name)) {
+			return resType, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid rule resource type: %s", name)
+}
+
+// RuleResourceNamePrefix 按照规则名称匹配的资源ID前缀，例如 name:order-*
+const RuleResourceNamePrefix = "name:"
+
+// IsRuleResourceType 策略资源的 res_type 是否为治理规则类型的鉴权资源
+func IsRuleResourceType(resType int32) bool {
+	for _, item := range RuleResourceTypes {
+		if int32(item) == resType {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRuleResourceID 校验策略中治理规则资源ID的格式，支持 *、规则ID 以及 name:<名称通配表达式>
+func CheckRuleResourceID(resId string) error {
+	if resId == "" {
+		return errors.New("rule resource id is empty")
+	}
+	if !strings.HasPrefix(resId, RuleResourceNamePrefix) {
+		return nil
+	}
+	pattern := strings.TrimPrefix(resId, RuleResourceNamePrefix)
+	if pattern == "" {
+		return fmt.Errorf("rule name pattern is empty: %s", resId)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid rule name pattern %s: %w", pattern, err)
+	}
+	return nil
+}
+
+// MatchRuleResource 判断策略中的治理规则资源ID是否命中某条规则
+// name:<pattern> 按照规则名称进行通配匹配，其余按照规则ID精确匹配
+func MatchRuleResource(resId, ruleId, ruleName string) bool {
+	if resId == "*" {
+		return true
+	}
+	if !strings.HasPrefix(resId, RuleResourceNamePrefix) {
+		return ruleId != "" && resId == ruleId
+	}
+	if ruleName == "" {
+		return false
+	}
+	ok, err := path.Match(strings.TrimPrefix(resId, RuleResourceNamePrefix), ruleName)
+	return err == nil && ok
+}
+
 // BzModule 模块标识
 type BzModule int16
 
@@ -190,6 +452,24 @@ var (
 type ResourceEntry struct {
 	ID    string
 	Owner string
+	// Name 资源名称，目前仅治理规则需要按照名称匹配鉴权策略
+	Name string
+	// Namespace 资源所属的命名空间，用于限定了命名空间范围的临时 token 的校验
+	Namespace string
+}
+
+// RevokedToken 被吊销的临时 token，过期之后不再需要保留
+type RevokedToken struct {
+	// ID token 的唯一标识
+	ID string
+	// Principal token 对应的用户ID
+	Principal string
+	// Owner 用户对应的主账户ID
+	Owner string
+	// ExpireTime token 的过期时间
+	ExpireTime time.Time
+	CreateTime time.Time
+	ModifyTime time.Time
 }
 
 // User 用户
@@ -265,6 +545,7 @@ type StrategyDetail struct {
 	ID         string
 	Name       string
 	Action     string
+	Effect     string
 	Comment    string
 	Principals []Principal
 	Default    bool
@@ -281,6 +562,34 @@ type StrategyDetailCache struct {
 	*StrategyDetail
 	UserPrincipal  map[string]Principal
 	GroupPrincipal map[string]Principal
+	// Actions 加载缓存时解析好的策略动作，鉴权时不再重复解析，为空时按照 Action 实时解析
+	Actions map[ResourceOperation]struct{}
+}
+
+// Operations 策略动作所覆盖的操作列表
+func (s *StrategyDetailCache) Operations() []ResourceOperation {
+	if s.Actions == nil {
+		return s.StrategyDetail.Operations()
+	}
+	return sortOperations(s.Actions)
+}
+
+// AllowOperation 判断授权策略的动作是否允许执行该操作
+func (s *StrategyDetailCache) AllowOperation(op ResourceOperation) bool {
+	return !s.IsDeny() && s.coverOperation(op)
+}
+
+// DenyOperation 判断拒绝策略的动作是否禁止执行该操作
+func (s *StrategyDetailCache) DenyOperation(op ResourceOperation) bool {
+	return s.IsDeny() && s.coverOperation(op)
+}
+
+func (s *StrategyDetailCache) coverOperation(op ResourceOperation) bool {
+	if s.Actions == nil {
+		return s.StrategyDetail.coverOperation(op)
+	}
+	_, ok := s.Actions[op]
+	return ok
 }
 
 // ModifyStrategyDetail 修改鉴权策略详细
@@ -288,6 +597,7 @@ type ModifyStrategyDetail struct {
 	ID               string
 	Name             string
 	Action           string
+	Effect           string
 	Comment          string
 	AddPrincipals    []Principal
 	RemovePrincipals []Principal
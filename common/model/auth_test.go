@@ -0,0 +1,151 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+
+	apisecurity "github.com/polarismesh/specification/source/go/api/v1/security"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeStrategyAction(t *testing.T) {
+	tests := []struct {
+		action string
+		expect string
+		hasErr bool
+	}{
+		{action: "", expect: ActionReadWrite},
+		{action: "read_write", expect: ActionReadWrite},
+		{action: ActionOnlyRead, expect: ActionOnlyRead},
+		{action: "READ", expect: ActionOnlyRead},
+		{action: "modify, create", expect: "READ,CREATE,MODIFY"},
+		{action: "READ,CREATE,MODIFY,DELETE", expect: ActionReadWrite},
+		{action: "READ,DROP", hasErr: true},
+	}
+	for _, tt := range tests {
+		ret, err := NormalizeStrategyAction(tt.action)
+		if tt.hasErr {
+			assert.Error(t, err, tt.action)
+			continue
+		}
+		assert.NoError(t, err, tt.action)
+		assert.Equal(t, tt.expect, ret, tt.action)
+	}
+}
+
+func TestStrategyDetail_AllowOperation(t *testing.T) {
+	readOnly := &StrategyDetail{Action: ActionOnlyRead}
+	assert.True(t, readOnly.AllowOperation(Read))
+	assert.False(t, readOnly.AllowOperation(Create))
+	assert.False(t, readOnly.AllowOperation(Delete))
+
+	partial := &StrategyDetail{Action: "READ,MODIFY"}
+	assert.True(t, partial.AllowOperation(Modify))
+	assert.False(t, partial.AllowOperation(Delete))
+
+	invalid := &StrategyDetail{Action: "UNKNOWN"}
+	assert.True(t, invalid.AllowOperation(Read))
+	assert.False(t, invalid.AllowOperation(Modify))
+}
+
+func TestStrategyDetail_DenyOperation(t *testing.T) {
+	effect, err := NormalizeStrategyEffect("deny")
+	assert.NoError(t, err)
+	assert.Equal(t, StrategyEffectDeny, effect)
+	_, err = NormalizeStrategyEffect("reject")
+	assert.Error(t, err)
+
+	deny := &StrategyDetail{Action: "DELETE", Effect: StrategyEffectDeny}
+	assert.True(t, deny.IsDeny())
+	assert.True(t, deny.DenyOperation(Delete))
+	assert.False(t, deny.DenyOperation(Modify))
+	assert.False(t, deny.AllowOperation(Read))
+
+	allow := &StrategyDetail{Action: ActionReadWrite}
+	assert.False(t, allow.DenyOperation(Delete))
+	assert.Equal(t, []ResourceOperation{Read, Create, Modify, Delete}, allow.Operations())
+}
+
+func TestStrategyDetailCache_Operations(t *testing.T) {
+	detail := &StrategyDetail{Action: "READ,CREATE"}
+	assert.True(t, detail.HasWriteOperation())
+	assert.False(t, (&StrategyDetail{Action: ActionOnlyRead}).HasWriteOperation())
+
+	item := &StrategyDetailCache{StrategyDetail: detail, Actions: ParseStrategyOperations(detail.Action)}
+	assert.True(t, item.AllowOperation(Create))
+	assert.False(t, item.AllowOperation(Modify))
+	assert.Equal(t, []ResourceOperation{Read, Create}, item.Operations())
+
+	// 使用加载缓存时解析的结果，不再重新解析 Action
+	detail.Action = ActionReadWrite
+	assert.False(t, item.AllowOperation(Modify))
+
+	deny := &StrategyDetailCache{
+		StrategyDetail: &StrategyDetail{Action: "DELETE", Effect: StrategyEffectDeny},
+		Actions:        ParseStrategyOperations("DELETE"),
+	}
+	assert.True(t, deny.DenyOperation(Delete))
+	assert.False(t, deny.DenyOperation(Modify))
+	assert.False(t, deny.AllowOperation(Read))
+
+	// 没有预先解析时按照 Action 实时解析
+	assert.True(t, (&StrategyDetailCache{StrategyDetail: &StrategyDetail{}}).AllowOperation(Delete))
+}
+
+func TestMatchRuleResource(t *testing.T) {
+	tests := []struct {
+		resId    string
+		ruleId   string
+		ruleName string
+		want     bool
+	}{
+		{resId: "*", ruleId: "rule-1", want: true},
+		{resId: "rule-1", ruleId: "rule-1", want: true},
+		{resId: "rule-1", ruleId: "rule-2", ruleName: "rule-1", want: false},
+		{resId: "rule-1", ruleName: "order", want: false},
+		{resId: "name:order-*", ruleName: "order-limit", want: true},
+		{resId: "name:order-?", ruleName: "order-a", want: true},
+		{resId: "name:order-*", ruleId: "order-limit", want: false},
+		{resId: "name:order", ruleName: "order-limit", want: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchRuleResource(tt.resId, tt.ruleId, tt.ruleName), tt.resId)
+	}
+
+	assert.NoError(t, CheckRuleResourceID("name:order-*"))
+	assert.Error(t, CheckRuleResourceID("name:"))
+	assert.Error(t, CheckRuleResourceID("name:order-[*"))
+	assert.Error(t, CheckRuleResourceID(""))
+}
+
+func TestParseRuleResourceType(t *testing.T) {
+	resType, err := ParseRuleResourceType(" Route_Rule ")
+	assert.NoError(t, err)
+	assert.Equal(t, ResourceTypeRouteRules, resType)
+	_, err = ParseRuleResourceType("service")
+	assert.Error(t, err)
+
+	// res_type 中同时保存了 apisecurity.ResourceType，两者取值不能重叠
+	for _, item := range RuleResourceTypes {
+		assert.True(t, IsRuleResourceType(int32(item)))
+		_, defined := apisecurity.ResourceType_name[int32(item)]
+		assert.False(t, defined)
+	}
+	assert.False(t, IsRuleResourceType(int32(apisecurity.ResourceType_ConfigGroups)))
+}
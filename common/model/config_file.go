@@ -29,6 +29,7 @@ type ConfigFileGroup struct {
 	Comment    string
 	CreateTime time.Time
 	Owner      string
+	JsonSchema string
 	CreateBy   string
 	ModifyTime time.Time
 	ModifyBy   string
@@ -71,20 +72,20 @@ type ConfigFileRelease struct {
 	Valid      bool
 }
 
-// ConfigFileReleaseHistory 配置文件发布历史记录数据持久化对象
-type ConfigFileReleaseHistory struct {
-	Id         uint64
-	Name       string
-	Namespace  string
-	Group      string
-	FileName   string
-	Format     string
-	Tags       string
-	Content    string
-	Comment    string
-	Md5        string
-	Type       string
-	Status     string
+// ConfigFileGrayRelease 配置文件灰度发布数据持久化对象，同一个配置文件同时只存在一个生效的灰度发布
+type ConfigFileGrayRelease struct {
+	Id        uint64
+	Name      string
+	Namespace string
+	Group     string
+	FileName  string
+	Content   string
+	Comment   string
+	Md5       string
+	Version   uint64
+	// Rule 灰度客户端选择规则, JSON 格式的 ConfigFileGrayRule
+	Rule       string
+	Flag       int
 	CreateTime time.Time
 	CreateBy   string
 	ModifyTime time.Time
@@ -92,6 +93,31 @@ type ConfigFileReleaseHistory struct {
 	Valid      bool
 }
 
+// ConfigFileReleaseHistory 配置文件发布历史记录数据持久化对象
+type ConfigFileReleaseHistory struct {
+	Id        uint64
+	Name      string
+	Namespace string
+	Group     string
+	FileName  string
+	Format    string
+	Tags      string
+	Content   string
+	Comment   string
+	Md5       string
+	Type      string
+	Status    string
+	// Approvers 审批通过本次发布的审批人，多个审批人使用逗号分隔
+	Approvers string
+	// SourceHistoryId 回滚发布时回滚到的历史发布 ID，其余发布为 0
+	SourceHistoryId uint64
+	CreateTime      time.Time
+	CreateBy        string
+	ModifyTime      time.Time
+	ModifyBy        string
+	Valid           bool
+}
+
 // ConfigFileTag 配置文件标签数据持久化对象
 type ConfigFileTag struct {
 	Id         uint64
@@ -119,3 +145,16 @@ type ConfigFileTemplate struct {
 	ModifyTime time.Time
 	ModifyBy   string
 }
+
+// ConfigFilePublishEvent 配置文件发布成功后通过 eventhub 广播的事件
+type ConfigFilePublishEvent struct {
+	Namespace   string
+	Group       string
+	FileName    string
+	ReleaseName string
+	// ReleaseType 发布类型，normal、gray、rollback、delete
+	ReleaseType string
+	Md5         string
+	Operator    string
+	CreateTime  time.Time
+}
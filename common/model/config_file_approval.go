@@ -0,0 +1,123 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// ReleaseRequestStatusPending 等待审批
+	ReleaseRequestStatusPending = "pending"
+	// ReleaseRequestStatusApproved 审批通过并且已经发布
+	ReleaseRequestStatusApproved = "approved"
+	// ReleaseRequestStatusRejected 审批被驳回
+	ReleaseRequestStatusRejected = "rejected"
+	// ReleaseRequestStatusCanceled 发起人撤销了发布申请
+	ReleaseRequestStatusCanceled = "canceled"
+	// ReleaseRequestStatusFailed 审批通过但是发布失败
+	ReleaseRequestStatusFailed = "failed"
+)
+
+// ConfigFileApprovalPolicy 配置发布审批策略，Group 为空时对整个命名空间生效，分组上的策略优先于命名空间上的策略
+type ConfigFileApprovalPolicy struct {
+	Id        uint64
+	Namespace string
+	Group     string
+	// RequiredApprovals 发布前需要的审批人数
+	RequiredApprovals int
+	// Users 可以审批的用户名，多个用户使用逗号分隔
+	Users string
+	// UserGroups 可以审批的用户组名称，多个用户组使用逗号分隔
+	UserGroups string
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+	Valid      bool
+}
+
+// ApproverUsers 可以审批的用户名列表
+func (p *ConfigFileApprovalPolicy) ApproverUsers() []string {
+	return SplitApprovers(p.Users)
+}
+
+// ApproverUserGroups 可以审批的用户组名称列表
+func (p *ConfigFileApprovalPolicy) ApproverUserGroups() []string {
+	return SplitApprovers(p.UserGroups)
+}
+
+// ConfigFileReleaseRequest 待审批的配置发布申请，保存申请时配置文件的内容，审批通过后发布的是该内容
+type ConfigFileReleaseRequest struct {
+	Id          uint64
+	Namespace   string
+	Group       string
+	FileName    string
+	ReleaseName string
+	Comment     string
+	Content     string
+	Md5         string
+	Status      string
+	// RequiredApprovals 申请时审批策略要求的审批人数
+	RequiredApprovals int
+	// Approvers 已经同意发布的审批人，多个审批人使用逗号分隔
+	Approvers string
+	// Reason 驳回、撤销或者发布失败的原因
+	Reason string
+	// SourceHistoryId 回滚申请回滚到的历史发布 ID，普通发布申请为 0
+	SourceHistoryId uint64
+	// Revision 每次更新都会变化，用于并发审批时的冲突检测
+	Revision   string
+	CreateTime time.Time
+	CreateBy   string
+	ModifyTime time.Time
+	ModifyBy   string
+	Valid      bool
+}
+
+// ApproverList 已经同意发布的审批人列表
+func (r *ConfigFileReleaseRequest) ApproverList() []string {
+	return SplitApprovers(r.Approvers)
+}
+
+// HasApproved 审批人是否已经同意过该发布申请
+func (r *ConfigFileReleaseRequest) HasApproved(userName string) bool {
+	for _, approver := range r.ApproverList() {
+		if approver == userName {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitApprovers 解析逗号分隔的审批人列表
+func SplitApprovers(approvers string) []string {
+	ret := make([]string, 0, 4)
+	for _, item := range strings.Split(approvers, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// JoinApprovers 将审批人列表合并为逗号分隔的字符串
+func JoinApprovers(approvers []string) string {
+	return strings.Join(approvers, ",")
+}
@@ -0,0 +1,135 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	// GrayClientIdLabel 客户端通过该标签上报自身的客户端 ID，用于灰度规则中的 ClientIds 匹配
+	GrayClientIdLabel = "client-id"
+)
+
+// ConfigFileGrayRule 灰度发布的客户端选择规则，ClientIPs、ClientIds、Labels 任意一项命中即认为客户端命中灰度
+type ConfigFileGrayRule struct {
+	// ClientIPs 客户端 IP 列表，支持单个 IP 以及 CIDR 格式的 IP 段
+	ClientIPs []string `json:"client_ips,omitempty"`
+	// ClientIds 客户端 ID 列表
+	ClientIds []string `json:"client_ids,omitempty"`
+	// Labels 客户端标签，需要全部匹配
+	Labels map[string]string `json:"labels,omitempty"`
+
+	ipNets []*net.IPNet
+}
+
+// GrayClient 参与灰度规则匹配的客户端信息
+type GrayClient struct {
+	IP       string
+	ClientId string
+	Labels   map[string]string
+}
+
+// ParseConfigFileGrayRule 解析并校验灰度规则
+func ParseConfigFileGrayRule(rule string) (*ConfigFileGrayRule, error) {
+	ret := &ConfigFileGrayRule{}
+	if err := json.Unmarshal([]byte(rule), ret); err != nil {
+		return nil, err
+	}
+	if err := ret.Init(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Init 校验灰度规则并预先解析 IP 段
+func (r *ConfigFileGrayRule) Init() error {
+	if len(r.ClientIPs) == 0 && len(r.ClientIds) == 0 && len(r.Labels) == 0 {
+		return errors.New("gray rule must contain at least one of client_ips, client_ids, labels")
+	}
+	ipNets := make([]*net.IPNet, 0, len(r.ClientIPs))
+	for _, item := range r.ClientIPs {
+		ipNet, err := parseGrayIPNet(item)
+		if err != nil {
+			return err
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	r.ipNets = ipNets
+	return nil
+}
+
+// String 序列化灰度规则，用于持久化
+func (r *ConfigFileGrayRule) String() string {
+	data, _ := json.Marshal(r)
+	return string(data)
+}
+
+// Match 判断客户端是否命中灰度规则
+func (r *ConfigFileGrayRule) Match(client *GrayClient) bool {
+	if r == nil || client == nil {
+		return false
+	}
+	if ip := net.ParseIP(client.IP); ip != nil {
+		for _, ipNet := range r.ipNets {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	if client.ClientId != "" {
+		for _, clientId := range r.ClientIds {
+			if clientId == client.ClientId {
+				return true
+			}
+		}
+	}
+	if len(r.Labels) == 0 {
+		return false
+	}
+	for k, v := range r.Labels {
+		if val, ok := client.Labels[k]; !ok || val != v {
+			return false
+		}
+	}
+	return true
+}
+
+func parseGrayIPNet(item string) (*net.IPNet, error) {
+	item = strings.TrimSpace(item)
+	if strings.Contains(item, "/") {
+		_, ipNet, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client ip range %s: %w", item, err)
+		}
+		return ipNet, nil
+	}
+	ip := net.ParseIP(item)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid client ip %s", item)
+	}
+	bits := 128
+	if v4 := ip.To4(); v4 != nil {
+		ip, bits = v4, 32
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
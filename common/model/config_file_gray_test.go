@@ -0,0 +1,76 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigFileGrayRule(t *testing.T) {
+	_, err := ParseConfigFileGrayRule(`{}`)
+	assert.Error(t, err)
+
+	_, err = ParseConfigFileGrayRule(`{"client_ips":["10.0.0.300"]}`)
+	assert.Error(t, err)
+
+	_, err = ParseConfigFileGrayRule(`{"client_ips":["10.0.0.0/33"]}`)
+	assert.Error(t, err)
+
+	rule, err := ParseConfigFileGrayRule(`{"client_ips":["10.0.0.0/24","192.168.1.1","fe80::/64"]}`)
+	assert.NoError(t, err)
+	assert.Len(t, rule.ClientIPs, 3)
+
+	again, err := ParseConfigFileGrayRule(rule.String())
+	assert.NoError(t, err)
+	assert.Equal(t, rule.ClientIPs, again.ClientIPs)
+}
+
+func TestConfigFileGrayRule_Match(t *testing.T) {
+	rule := &ConfigFileGrayRule{
+		ClientIPs: []string{"10.0.0.0/24", "192.168.1.1"},
+		ClientIds: []string{"client-a"},
+		Labels:    map[string]string{"env": "gray", "zone": "sz"},
+	}
+	assert.NoError(t, rule.Init())
+
+	t.Run("match_by_ip_range", func(t *testing.T) {
+		assert.True(t, rule.Match(&GrayClient{IP: "10.0.0.12"}))
+		assert.True(t, rule.Match(&GrayClient{IP: "192.168.1.1"}))
+		assert.False(t, rule.Match(&GrayClient{IP: "192.168.1.2"}))
+		assert.False(t, rule.Match(&GrayClient{IP: "10.0.1.12"}))
+	})
+
+	t.Run("match_by_client_id", func(t *testing.T) {
+		assert.True(t, rule.Match(&GrayClient{IP: "127.0.0.1", ClientId: "client-a"}))
+		assert.False(t, rule.Match(&GrayClient{IP: "127.0.0.1", ClientId: "client-b"}))
+	})
+
+	t.Run("match_by_labels", func(t *testing.T) {
+		assert.True(t, rule.Match(&GrayClient{Labels: map[string]string{"env": "gray", "zone": "sz", "x": "y"}}))
+		assert.False(t, rule.Match(&GrayClient{Labels: map[string]string{"env": "gray"}}))
+		assert.False(t, rule.Match(&GrayClient{Labels: map[string]string{"env": "prod", "zone": "sz"}}))
+	})
+
+	t.Run("nil_rule_or_client", func(t *testing.T) {
+		var empty *ConfigFileGrayRule
+		assert.False(t, empty.Match(&GrayClient{IP: "10.0.0.12"}))
+		assert.False(t, rule.Match(nil))
+	})
+}
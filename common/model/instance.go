@@ -33,6 +33,23 @@ const (
 	MetadataInstanceLastHeartbeatTime = "internal-lastheartbeat"
 )
 
+// HealthCheckProbe 服务端主动探测实例的健康检查类型，探测参数通过 internal-health-check-* 元数据声明。
+// specification 的 HealthCheck.HealthCheckType 中尚未定义该取值，与 plugin.HealthCheckerProbe 保持一致
+const HealthCheckProbe apiservice.HealthCheck_HealthCheckType = 2
+
+// IsHealthCheckDeclared 实例是否声明了健康检查，心跳需要携带 heartbeat，主动探测只需要声明类型
+func IsHealthCheckDeclared(check *apiservice.HealthCheck) bool {
+	return check.GetHeartbeat() != nil || check.GetType() == HealthCheckProbe
+}
+
+// NormalizeHealthCheckType 除主动探测外，实例的健康检查类型统一为心跳
+func NormalizeHealthCheckType(checkType apiservice.HealthCheck_HealthCheckType) apiservice.HealthCheck_HealthCheckType {
+	if checkType == HealthCheckProbe {
+		return HealthCheckProbe
+	}
+	return apiservice.HealthCheck_HEARTBEAT
+}
+
 // Instance 组合了api的Instance对象
 type Instance struct {
 	Proto             *apiservice.Instance
@@ -433,11 +450,15 @@ func CreateInstanceModel(serviceID string, req *apiservice.Instance) *Instance {
 
 	// health Check，healthCheck不能为空，且没有显示把enable_health_check置为false
 	// 如果create的时候，打开了healthCheck，那么实例模式是unhealthy，必须要一次心跳才会healthy
-	if req.GetHealthCheck().GetHeartbeat() != nil &&
+	// 主动探测的实例不需要声明心跳，必须要一次探测成功才会healthy
+	if IsHealthCheckDeclared(req.GetHealthCheck()) &&
 		(req.GetEnableHealthCheck() == nil || req.GetEnableHealthCheck().GetValue()) {
 		protoIns.EnableHealthCheck = utils.NewBoolValue(true)
 		protoIns.HealthCheck = req.HealthCheck
-		protoIns.HealthCheck.Type = apiservice.HealthCheck_HEARTBEAT
+		protoIns.HealthCheck.Type = NormalizeHealthCheckType(req.GetHealthCheck().GetType())
+		if protoIns.HealthCheck.Heartbeat == nil {
+			protoIns.HealthCheck.Heartbeat = &apiservice.HeartbeatHealthCheck{}
+		}
 		// ttl range: (0, 60]
 		ttl := protoIns.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
 		if ttl == 0 || ttl > 60 {
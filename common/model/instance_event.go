@@ -47,6 +47,8 @@ const (
 	EventInstanceSendHeartbeat InstanceEventType = "InstanceSendHeartbeat"
 	// EventInstanceUpdate Instance metadata and info update event
 	EventInstanceUpdate InstanceEventType = "InstanceUpdate"
+	// EventServiceNoHealthyInstance Service has lost all healthy and unisolated instances
+	EventServiceNoHealthyInstance InstanceEventType = "ServiceNoHealthyInstance"
 )
 
 // CtxEventKeyMetadata 用于将metadata从Context中传入并取出
@@ -29,3 +29,24 @@ type LeaderElection struct {
 	ModifyTime time.Time
 	Valid      bool
 }
+
+const (
+	// CertificateAuthorityRoot 内置根 CA 的记录 ID
+	CertificateAuthorityRoot = "root"
+	// CertificateAuthorityIntermediate 导入的中间 CA 的记录 ID，存在时使用中间 CA 签发工作负载证书
+	CertificateAuthorityIntermediate = "intermediate"
+)
+
+// CertificateAuthority xDS 签发工作负载证书使用的 CA
+type CertificateAuthority struct {
+	// ID CA 记录的标识，root 或者 intermediate
+	ID string
+	// CertPEM CA 证书
+	CertPEM string
+	// KeyPEM CA 私钥
+	KeyPEM string
+	// ChainPEM 上级证书链，一直到根证书为止，内置根 CA 为空
+	ChainPEM   string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
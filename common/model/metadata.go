@@ -23,4 +23,22 @@ const (
 
 	// MetaKeyBuildRevision build revision for server
 	MetaKeyBuildRevision = "build-revision"
+
+	// MetaKeyHealthCheckProtocol protocol to probe the instance actively, support http, tcp and grpc,
+	// only takes effect when the instance declares the health check type HealthCheckProbe
+	MetaKeyHealthCheckProtocol = "internal-health-check-protocol"
+	// MetaKeyHealthCheckPort port to probe, default is the instance port
+	MetaKeyHealthCheckPort = "internal-health-check-port"
+	// MetaKeyHealthCheckPath http path to probe
+	MetaKeyHealthCheckPath = "internal-health-check-path"
+	// MetaKeyHealthCheckGrpcService service name used in grpc health check request
+	MetaKeyHealthCheckGrpcService = "internal-health-check-grpc-service"
+	// MetaKeyHealthCheckInterval probe interval, e.g. 5s
+	MetaKeyHealthCheckInterval = "internal-health-check-interval"
+	// MetaKeyHealthCheckTimeout probe timeout, e.g. 1s
+	MetaKeyHealthCheckTimeout = "internal-health-check-timeout"
+	// MetaKeyHealthCheckHealthyThreshold continuous success count to turn the instance healthy
+	MetaKeyHealthCheckHealthyThreshold = "internal-health-check-healthy-threshold"
+	// MetaKeyHealthCheckUnhealthyThreshold continuous failure count to turn the instance unhealthy
+	MetaKeyHealthCheckUnhealthyThreshold = "internal-health-check-unhealthy-threshold"
 )
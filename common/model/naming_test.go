@@ -22,6 +22,7 @@ import (
 
 	"github.com/golang/protobuf/ptypes/wrappers"
 	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	apitraffic "github.com/polarismesh/specification/source/go/api/v1/traffic_manage"
 	"github.com/stretchr/testify/assert"
 )
@@ -107,3 +108,30 @@ func TestRateLimit_Arguments2Labels(t *testing.T) {
 	assert.True(t, hasValue)
 	assert.Equal(t, "127.0.0.1", value.Value.GetValue())
 }
+
+// TestCreateInstanceModel_HealthCheckType 测试实例声明的健康检查类型
+func TestCreateInstanceModel_HealthCheckType(t *testing.T) {
+	// 未声明主动探测的实例统一为心跳
+	ins := CreateInstanceModel("svc", &apiservice.Instance{
+		HealthCheck: &apiservice.HealthCheck{
+			Heartbeat: &apiservice.HeartbeatHealthCheck{Ttl: &wrappers.UInt32Value{Value: 10}},
+		},
+		Metadata: map[string]string{MetaKeyHealthCheckProtocol: "tcp"},
+	})
+	assert.True(t, ins.EnableHealthCheck())
+	assert.Equal(t, apiservice.HealthCheck_HEARTBEAT, ins.HealthCheck().GetType())
+
+	// 主动探测的实例不需要声明心跳
+	ins = CreateInstanceModel("svc", &apiservice.Instance{
+		HealthCheck: &apiservice.HealthCheck{Type: HealthCheckProbe},
+		Metadata:    map[string]string{MetaKeyHealthCheckProtocol: "tcp"},
+	})
+	assert.True(t, ins.EnableHealthCheck())
+	assert.Equal(t, HealthCheckProbe, ins.HealthCheck().GetType())
+	assert.Equal(t, uint32(5), ins.HealthCheck().GetHeartbeat().GetTtl().GetValue())
+
+	// 没有声明健康检查
+	ins = CreateInstanceModel("svc", &apiservice.Instance{})
+	assert.False(t, ins.EnableHealthCheck())
+	assert.Nil(t, ins.HealthCheck())
+}
@@ -50,33 +50,38 @@ type Resource string
 
 // Define the type of resource type
 const (
-	RNamespace          Resource = "Namespace"
-	RService            Resource = "Service"
-	RRouting            Resource = "Routing"
-	RCircuitBreaker     Resource = "CircuitBreaker"
-	RInstance           Resource = "Instance"
-	RRateLimit          Resource = "RateLimit"
-	RUser               Resource = "User"
-	RUserGroup          Resource = "UserGroup"
-	RUserGroupRelation  Resource = "UserGroupRelation"
-	RAuthStrategy       Resource = "AuthStrategy"
-	RConfigGroup        Resource = "ConfigGroup"
-	RConfigFile         Resource = "ConfigFile"
-	RConfigFileRelease  Resource = "ConfigFileRelease"
-	RCircuitBreakerRule Resource = "CircuitBreakerRule"
-	RFaultDetectRule    Resource = "FaultDetectRule"
+	RNamespace             Resource = "Namespace"
+	RService               Resource = "Service"
+	RRouting               Resource = "Routing"
+	RCircuitBreaker        Resource = "CircuitBreaker"
+	RInstance              Resource = "Instance"
+	RRateLimit             Resource = "RateLimit"
+	RUser                  Resource = "User"
+	RUserGroup             Resource = "UserGroup"
+	RUserGroupRelation     Resource = "UserGroupRelation"
+	RAuthStrategy          Resource = "AuthStrategy"
+	RConfigGroup           Resource = "ConfigGroup"
+	RConfigFile            Resource = "ConfigFile"
+	RConfigFileRelease     Resource = "ConfigFileRelease"
+	RConfigFileGrayRelease Resource = "ConfigFileGrayRelease"
+	RConfigApprovalPolicy  Resource = "ConfigApprovalPolicy"
+	RConfigReleaseRequest  Resource = "ConfigReleaseRequest"
+	RCircuitBreakerRule    Resource = "CircuitBreakerRule"
+	RFaultDetectRule       Resource = "FaultDetectRule"
+	RTrafficPolicy         Resource = "TrafficPolicy"
+	RAccessPolicy          Resource = "AccessPolicy"
 )
 
 // RecordEntry Operation records
 type RecordEntry struct {
-	ResourceType  Resource
-	ResourceName  string
-	Namespace     string
-	Operator      string
-	OperationType OperationType
-	Detail        string
-	Server        string
-	HappenTime    time.Time
+	ResourceType  Resource      `json:"resource_type"`
+	ResourceName  string        `json:"resource_name"`
+	Namespace     string        `json:"namespace"`
+	Operator      string        `json:"operator"`
+	OperationType OperationType `json:"operation_type"`
+	Detail        string        `json:"detail"`
+	Server        string        `json:"server"`
+	HappenTime    time.Time     `json:"happen_time"`
 }
 
 func (r *RecordEntry) String() string {
@@ -0,0 +1,70 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+var (
+	_ ServicePolicyRule = (*TrafficPolicy)(nil)
+	_ ServicePolicyRule = (*AccessPolicy)(nil)
+)
+
+// ServicePolicy 挂载在服务上的策略的公共字段，各类策略共用
+type ServicePolicy struct {
+	ID          string
+	Name        string
+	Namespace   string
+	Service     string
+	Description string
+	Enable      bool
+	// Rule 策略内容，具体策略 Spec 的 json 序列化结果
+	Rule       string
+	Revision   string
+	Valid      bool
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+// GetServicePolicy 获取策略的公共字段
+func (p *ServicePolicy) GetServicePolicy() *ServicePolicy {
+	return p
+}
+
+// ServiceKey 策略所属的服务
+func (p *ServicePolicy) ServiceKey() ServiceKey {
+	return ServiceKey{Namespace: p.Namespace, Name: p.Service}
+}
+
+// unmarshalRule 将策略内容反序列化到 spec 中，策略内容为空时保持 spec 不变
+func (p *ServicePolicy) unmarshalRule(spec interface{}) error {
+	if p.Rule == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(p.Rule), spec)
+}
+
+// ServicePolicyRule 挂载在服务上的策略，缓存、存储层以及 API 层按照该接口处理公共字段
+type ServicePolicyRule interface {
+	// GetServicePolicy 获取策略的公共字段
+	GetServicePolicy() *ServicePolicy
+	// ParseSpec 将 Rule 解析为具体的策略内容
+	ParseSpec() error
+}
@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package model
+
+// TrafficPolicy 服务的流量策略，包含超时、重试以及故障注入配置
+// RouteName 为空时对服务下的全部路由生效，否则只对同名的路由规则生效
+type TrafficPolicy struct {
+	ServicePolicy
+	RouteName string
+	// Spec 反序列化之后的策略内容，不做持久化
+	Spec *TrafficPolicySpec
+}
+
+// ParseSpec 解析策略内容
+func (p *TrafficPolicy) ParseSpec() error {
+	spec := &TrafficPolicySpec{}
+	if err := p.unmarshalRule(spec); err != nil {
+		return err
+	}
+	p.Spec = spec
+	return nil
+}
+
+// TrafficPolicySpec 流量策略内容，时间单位均为毫秒
+type TrafficPolicySpec struct {
+	// Timeout 请求的整体超时时间，包含所有的重试
+	Timeout uint32 `json:"timeout,omitempty"`
+	// Retry 重试策略
+	Retry *RetryPolicy `json:"retry,omitempty"`
+	// Fault 故障注入策略，用于故障演练
+	Fault *FaultInjection `json:"fault,omitempty"`
+}
+
+// RetryPolicy 重试策略
+type RetryPolicy struct {
+	// RetryOn 触发重试的条件，例如 5xx、connect-failure、retriable-status-codes
+	RetryOn []string `json:"retry_on,omitempty"`
+	// NumRetries 最大重试次数
+	NumRetries uint32 `json:"num_retries,omitempty"`
+	// PerTryTimeout 单次请求的超时时间
+	PerTryTimeout uint32 `json:"per_try_timeout,omitempty"`
+	// RetriableStatusCodes retry_on 包含 retriable-status-codes 时需要重试的状态码
+	RetriableStatusCodes []uint32 `json:"retriable_status_codes,omitempty"`
+	// Budget 重试预算，限制重试请求占活跃请求的比例
+	Budget *RetryBudget `json:"budget,omitempty"`
+}
+
+// RetryBudget 重试预算
+type RetryBudget struct {
+	// BudgetPercent 允许的重试请求占活跃请求的百分比
+	BudgetPercent float64 `json:"budget_percent,omitempty"`
+	// MinRetryConcurrency 不受预算限制的最小重试并发数
+	MinRetryConcurrency uint32 `json:"min_retry_concurrency,omitempty"`
+}
+
+// FaultInjection 故障注入策略
+type FaultInjection struct {
+	Delay *FaultDelay `json:"delay,omitempty"`
+	Abort *FaultAbort `json:"abort,omitempty"`
+}
+
+// FaultDelay 延迟注入
+type FaultDelay struct {
+	// Percentage 注入延迟的请求百分比
+	Percentage float64 `json:"percentage"`
+	// FixedDelay 注入的延迟时间
+	FixedDelay uint32 `json:"fixed_delay"`
+}
+
+// FaultAbort 中断注入
+type FaultAbort struct {
+	// Percentage 中断的请求百分比
+	Percentage float64 `json:"percentage"`
+	// HttpStatus 中断请求时返回的 http 状态码
+	HttpStatus uint32 `json:"http_status"`
+}
@@ -0,0 +1,329 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package secure
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+const (
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypePrivateKey  = "PRIVATE KEY"
+
+	// certBackdate 证书生效时间向前偏移，避免节点之间的时钟误差导致证书还未生效
+	certBackdate = time.Minute
+)
+
+// Authority 签发工作负载证书的 CA
+type Authority struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	// chain 上级证书链，最后一个为根证书，自签名的根 CA 为空
+	chain []*x509.Certificate
+	roots []*x509.Certificate
+}
+
+// WorkloadCert 签发的工作负载证书
+type WorkloadCert struct {
+	// SpiffeID 证书中携带的 SPIFFE ID
+	SpiffeID string
+	// SerialNumber 证书序列号，十六进制
+	SerialNumber string
+	// CertChainPEM 工作负载证书以及中间证书，不包含根证书
+	CertChainPEM string
+	// KeyPEM 工作负载私钥
+	KeyPEM    string
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+// NeedRotate 剩余有效期不足三分之一时需要重新签发
+func (w *WorkloadCert) NeedRotate(now time.Time) bool {
+	lifetime := w.NotAfter.Sub(w.NotBefore)
+	return w.NotAfter.Sub(now) < lifetime/3
+}
+
+// WorkloadSpiffeID 生成服务对应的 SPIFFE ID，格式为 spiffe://{trustDomain}/ns/{namespace}/sa/{service}
+func WorkloadSpiffeID(trustDomain, namespace, service string) string {
+	u := &url.URL{
+		Scheme: SpiffeScheme,
+		Host:   trustDomain,
+		Path:   "/ns/" + namespace + "/sa/" + service,
+	}
+	return u.String()
+}
+
+// GenerateRootCA 生成自签名的根 CA，返回 PEM 格式的证书以及私钥
+func GenerateRootCA(trustDomain string, ttl time.Duration) (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Polaris"},
+			CommonName:   "Polaris Root CA",
+		},
+		URIs:                  []*url.URL{{Scheme: SpiffeScheme, Host: trustDomain}},
+		NotBefore:             now.Add(-certBackdate),
+		NotAfter:              now.Add(ttl),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return "", "", err
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der})), keyPEM, nil
+}
+
+// NewAuthority 根据 PEM 格式的 CA 证书、私钥以及上级证书链构建 CA，证书链为空时 CA 证书必须是自签名的根证书
+func NewAuthority(certPEM, keyPEM, chainPEM string) (*Authority, error) {
+	certs, err := ParseCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("ca certificate is empty")
+	}
+	cert := certs[0]
+	if !cert.IsCA {
+		return nil, errors.New("certificate is not a ca")
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return nil, errors.New("ca certificate can not be used to sign certificates")
+	}
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, errors.New("ca certificate is not in the validity period")
+	}
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !pub.Equal(cert.PublicKey) {
+		return nil, errors.New("ca private key does not match the certificate")
+	}
+	chain, err := ParseCertificatesPEM(chainPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	authority := &Authority{cert: cert, key: key, chain: chain}
+	if len(chain) == 0 {
+		if err := cert.CheckSignatureFrom(cert); err != nil {
+			return nil, errors.New("ca certificate chain is required for a non self-signed ca")
+		}
+		authority.roots = []*x509.Certificate{cert}
+		return authority, nil
+	}
+
+	root := chain[len(chain)-1]
+	if err := root.CheckSignatureFrom(root); err != nil {
+		return nil, errors.New("the last certificate of the chain must be a self-signed root")
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	intermediates := x509.NewCertPool()
+	for _, item := range chain[:len(chain)-1] {
+		intermediates.AddCert(item)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, fmt.Errorf("verify ca certificate chain: %w", err)
+	}
+	authority.roots = []*x509.Certificate{root}
+	return authority, nil
+}
+
+// Certificate 返回签发证书使用的 CA 证书
+func (a *Authority) Certificate() *x509.Certificate {
+	return a.cert
+}
+
+// Roots 返回 CA 对应的根证书
+func (a *Authority) Roots() []*x509.Certificate {
+	return a.roots
+}
+
+// TrustBundle 合并多个 CA 的根证书并编码为 PEM 格式，相同的根证书只保留一份
+func TrustBundle(authorities ...*Authority) string {
+	var (
+		roots []*x509.Certificate
+		seen  = map[string]struct{}{}
+	)
+	for _, authority := range authorities {
+		if authority == nil {
+			continue
+		}
+		for _, root := range authority.roots {
+			if _, ok := seen[string(root.Raw)]; ok {
+				continue
+			}
+			seen[string(root.Raw)] = struct{}{}
+			roots = append(roots, root)
+		}
+	}
+	return EncodeCertificatesPEM(roots)
+}
+
+// IssueWorkloadCert 为 SPIFFE ID 签发工作负载证书，有效期不会超过 CA 证书的有效期
+func (a *Authority) IssueWorkloadCert(spiffeID string, ttl time.Duration) (*WorkloadCert, error) {
+	uri, err := url.Parse(spiffeID)
+	if err != nil {
+		return nil, err
+	}
+	if uri.Scheme != SpiffeScheme || uri.Host == "" {
+		return nil, fmt.Errorf("invalid spiffe id %s", spiffeID)
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	notAfter := now.Add(ttl)
+	if notAfter.After(a.cert.NotAfter) {
+		notAfter = a.cert.NotAfter
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		URIs:         []*url.URL{uri},
+		NotBefore:    now.Add(-certBackdate),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.cert, key.Public(), a.key)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := encodePrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}))
+	if len(a.chain) > 0 {
+		// 中间 CA 签发时需要带上中间证书，根证书通过信任包下发
+		buf.WriteString(EncodeCertificatesPEM(append([]*x509.Certificate{a.cert}, a.chain[:len(a.chain)-1]...)))
+	}
+	return &WorkloadCert{
+		SpiffeID:     spiffeID,
+		SerialNumber: serial.Text(16),
+		CertChainPEM: buf.String(),
+		KeyPEM:       keyPEM,
+		NotBefore:    tmpl.NotBefore,
+		NotAfter:     notAfter,
+	}, nil
+}
+
+// ParseCertificatesPEM 解析 PEM 格式的证书列表，忽略非证书的内容
+func ParseCertificatesPEM(data string) ([]*x509.Certificate, error) {
+	var (
+		certs []*x509.Certificate
+		rest  = []byte(data)
+	)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != pemTypeCertificate {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+}
+
+// EncodeCertificatesPEM 将证书列表编码为 PEM 格式
+func EncodeCertificatesPEM(certs []*x509.Certificate) string {
+	buf := bytes.NewBuffer(nil)
+	for _, cert := range certs {
+		_ = pem.Encode(buf, &pem.Block{Type: pemTypeCertificate, Bytes: cert.Raw})
+	}
+	return buf.String()
+}
+
+func parsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no pem encoded private key found")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type")
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key format")
+}
+
+func encodePrivateKey(key *ecdsa.PrivateKey) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der})), nil
+}
+
+func newSerialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
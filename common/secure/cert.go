@@ -0,0 +1,80 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package secure
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// SpiffeScheme SPIFFE ID 的 URI scheme
+const SpiffeScheme = "spiffe"
+
+// ServerConfig 根据 tls 配置信息生成服务端的 tls.Config，开启 ClientCertAuth 时强制校验客户端证书
+func (t *TLSInfo) ServerConfig() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		CipherSuites: t.CipherSuites,
+		MinVersion:   tls.VersionTLS12,
+	}
+	if !t.ClientCertAuth {
+		return cfg, nil
+	}
+	if t.TrustedCAFile == "" {
+		return nil, errors.New("trustedCAFile is required when clientCertAuth is enabled")
+	}
+	caPEM, err := os.ReadFile(t.TrustedCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid certificate found in %s", t.TrustedCAFile)
+	}
+	cfg.ClientCAs = pool
+	cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	return cfg, nil
+}
+
+// VerifiedPeerCertificate 返回经过 CA 校验的对端证书，没有经过校验的证书不能作为身份凭据
+func VerifiedPeerCertificate(state *tls.ConnectionState) *x509.Certificate {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
+
+// SpiffeID 返回证书中的 SPIFFE ID，即 scheme 为 spiffe 的 URI SAN，不存在时返回空
+func SpiffeID(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	for _, uri := range cert.URIs {
+		if uri.Scheme == SpiffeScheme {
+			return uri.String()
+		}
+	}
+	return ""
+}
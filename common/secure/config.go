@@ -37,6 +37,8 @@ type TLSConfig struct {
 	// InsecureSkipVerify tls 的一个配置
 	// 客户端是否验证证书和服务器主机名
 	InsecureSkipVerify bool `mapstructure:"insecureSkipTlsVerify"`
+	// ClientCertAuth 是否要求客户端提供由 TrustedCAFile 签发的证书
+	ClientCertAuth bool `mapstructure:"clientCertAuth"`
 }
 
 // ParseTLSConfig 解析 tls 配置
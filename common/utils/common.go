@@ -20,10 +20,12 @@ package utils
 import (
 	"context"
 	"crypto/sha1"
+	"crypto/x509"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"regexp"
 	"strconv"
 	"strings"
@@ -329,6 +331,30 @@ func ParseClientAddress(ctx context.Context) string {
 	return rid
 }
 
+// ParseClientCert 从ctx中获取经过 mTLS 校验的客户端证书
+func ParseClientCert(ctx context.Context) *x509.Certificate {
+	if ctx == nil {
+		return nil
+	}
+	cert, _ := ctx.Value(ContextClientCertKey).(*x509.Certificate)
+	return cert
+}
+
+// ParseClientIP 从ctx中获取客户端IP，优先使用 gRPC 层解析的 client-ip，否则从客户端地址中截取
+func ParseClientIP(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if ip, _ := ctx.Value(StringContext("client-ip")).(string); ip != "" {
+		return ip
+	}
+	addr := ParseClientAddress(ctx)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
 // ParseAuthToken 从ctx中获取token
 func ParseAuthToken(ctx context.Context) string {
 	if ctx == nil {
@@ -24,6 +24,10 @@ const (
 	ReleaseTypeNormal = "normal"
 	// ReleaseTypeDelete 发布类型，删除配置文件
 	ReleaseTypeDelete = "delete"
+	// ReleaseTypeGray 发布类型，灰度发布
+	ReleaseTypeGray = "gray"
+	// ReleaseTypeRollback 发布类型，回滚到历史发布
+	ReleaseTypeRollback = "rollback"
 
 	// ReleaseStatusSuccess 发布成功状态
 	ReleaseStatusSuccess = "success"
@@ -31,6 +35,8 @@ const (
 	ReleaseStatusFail = "failure"
 	// ReleaseStatusToRelease 待发布状态
 	ReleaseStatusToRelease = "to-be-released"
+	// ReleaseStatusGray 灰度发布中
+	ReleaseStatusGray = "in-gray"
 
 	// 文件格式
 	FileFormatText       = "text"
@@ -39,6 +45,7 @@ const (
 	FileFormatJson       = "json"
 	FileFormatHtml       = "html"
 	FileFormatProperties = "properties"
+	FileFormatToml       = "toml"
 
 	FileIdSeparator = "+"
 
@@ -73,6 +73,8 @@ const (
 	ContextIsFromSystem = StringContext("from-system")
 	// ContextOperator operator info
 	ContextOperator = StringContext("operator")
+	// ContextClientCertKey client certificate verified by mTLS
+	ContextClientCertKey = StringContext("client-cert")
 )
 
 const (
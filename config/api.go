@@ -47,6 +47,12 @@ type ConfigFileGroupOperate interface {
 
 	// UpdateConfigFileGroup 更新配置文件组
 	UpdateConfigFileGroup(ctx context.Context, configFileGroup *apiconfig.ConfigFileGroup) *apiconfig.ConfigResponse
+
+	// UpdateConfigFileGroupSchema 设置配置文件组的 JSON Schema
+	UpdateConfigFileGroupSchema(ctx context.Context, req *ConfigFileGroupSchema) *ConfigFileGroupSchemaResponse
+
+	// GetConfigFileGroupSchema 获取配置文件组的 JSON Schema
+	GetConfigFileGroupSchema(ctx context.Context, namespace, group string) *ConfigFileGroupSchemaResponse
 }
 
 // ConfigFileOperate 配置文件接口
@@ -100,6 +106,24 @@ type ConfigFileReleaseOperate interface {
 
 	// DeleteConfigFileRelease 删除配置文件发布内容
 	DeleteConfigFileRelease(ctx context.Context, namespace, group, fileName, deleteBy string) *apiconfig.ConfigResponse
+
+	// RollbackConfigFileRelease 将配置文件回滚到指定的历史发布
+	RollbackConfigFileRelease(ctx context.Context, req *ConfigFileReleaseRollbackRequest) *apiconfig.ConfigResponse
+}
+
+// ConfigFileGrayReleaseOperate 配置文件灰度发布接口
+type ConfigFileGrayReleaseOperate interface {
+	// PublishConfigFileGray 灰度发布配置文件，只有命中灰度规则的客户端能够获取到本次发布
+	PublishConfigFileGray(ctx context.Context, req *ConfigFileGrayReleaseRequest) *ConfigFileGrayReleaseResponse
+
+	// GetConfigFileGrayRelease 获取配置文件的全量发布以及进行中的灰度发布
+	GetConfigFileGrayRelease(ctx context.Context, namespace, group, fileName string) *ConfigFileGrayReleaseResponse
+
+	// PromoteConfigFileGrayRelease 将灰度发布全量发布给所有客户端
+	PromoteConfigFileGrayRelease(ctx context.Context, namespace, group, fileName string) *ConfigFileGrayReleaseResponse
+
+	// AbandonConfigFileGrayRelease 放弃灰度发布
+	AbandonConfigFileGrayRelease(ctx context.Context, namespace, group, fileName string) *ConfigFileGrayReleaseResponse
 }
 
 // ConfigFileReleaseHistoryOperate 配置文件发布历史接口
@@ -112,6 +136,41 @@ type ConfigFileReleaseHistoryOperate interface {
 	GetConfigFileLatestReleaseHistory(ctx context.Context, namespace, group, fileName string) *apiconfig.ConfigResponse
 }
 
+// ConfigFileReleaseDiffOperate 配置文件版本对比接口
+type ConfigFileReleaseDiffOperate interface {
+	// DiffConfigFileRelease 对比配置文件当前生效的发布与草稿
+	DiffConfigFileRelease(ctx context.Context, namespace, group, fileName string) *ConfigFileDiffResponse
+
+	// DiffConfigFileReleaseHistory 对比配置文件的两个发布历史
+	DiffConfigFileReleaseHistory(ctx context.Context,
+		namespace, group, fileName string, fromId, toId uint64) *ConfigFileDiffResponse
+}
+
+// ConfigFileApprovalOperate 配置发布审批接口
+type ConfigFileApprovalOperate interface {
+	// UpdateConfigApprovalPolicy 设置配置发布审批策略
+	UpdateConfigApprovalPolicy(ctx context.Context, req *ConfigApprovalPolicy) *ConfigApprovalPolicyResponse
+
+	// GetConfigApprovalPolicy 查询配置发布审批策略
+	GetConfigApprovalPolicy(ctx context.Context, namespace, group string) *ConfigApprovalPolicyResponse
+
+	// DeleteConfigApprovalPolicy 删除配置发布审批策略
+	DeleteConfigApprovalPolicy(ctx context.Context, namespace, group string) *ConfigApprovalPolicyResponse
+
+	// QueryConfigReleaseRequests 查询配置发布申请
+	QueryConfigReleaseRequests(ctx context.Context, namespace, group, fileName, status string,
+		offset, limit uint32) *ConfigReleaseRequestBatchResponse
+
+	// ApproveConfigReleaseRequest 同意配置发布申请
+	ApproveConfigReleaseRequest(ctx context.Context, req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse
+
+	// RejectConfigReleaseRequest 驳回配置发布申请
+	RejectConfigReleaseRequest(ctx context.Context, req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse
+
+	// CancelConfigReleaseRequest 撤销配置发布申请
+	CancelConfigReleaseRequest(ctx context.Context, req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse
+}
+
 // ConfigFileClientOperate 给客户端提供服务接口，不同的上层协议抽象的公共服务逻辑
 type ConfigFileClientOperate interface {
 	// GetConfigFileForClient 获取配置文件
@@ -128,6 +187,9 @@ type ConfigFileClientOperate interface {
 
 	// WatchConfigFiles 客户端监听配置文件
 	WatchConfigFiles(ctx context.Context, request *apiconfig.ClientWatchConfigFileRequest) (WatchCallback, error)
+
+	// OpenWatchStream 客户端基于 stream 监听配置文件，配置发布后主动推送
+	OpenWatchStream(ctx context.Context) *WatchStream
 }
 
 // ConfigFileTemplateOperate config file template operate
@@ -147,7 +209,10 @@ type ConfigCenterServer interface {
 	ConfigFileGroupOperate
 	ConfigFileOperate
 	ConfigFileReleaseOperate
+	ConfigFileGrayReleaseOperate
 	ConfigFileReleaseHistoryOperate
+	ConfigFileReleaseDiffOperate
+	ConfigFileApprovalOperate
 	ConfigFileClientOperate
 	ConfigFileTemplateOperate
 }
@@ -214,8 +214,14 @@ func (c *ConfigCenterTest) clearTestDataWhenUseBoltdb() error {
 		"ConfigFileReleaseHistoryID",
 		"ConfigFileRelease",
 		"ConfigFileReleaseID",
+		"ConfigFileGrayRelease",
+		"ConfigFileGrayReleaseID",
 		"ConfigFileTag",
 		"ConfigFileTagID",
+		"ConfigFileApprovalPolicy",
+		"ConfigFileApprovalPolicyID",
+		"ConfigFileReleaseRequest",
+		"ConfigFileReleaseRequestID",
 		"namespace",
 	}
 
@@ -255,6 +261,10 @@ func (c *ConfigCenterTest) clearTestDataWhenUseRDS() error {
 	if err != nil {
 		return err
 	}
+	_, err = tx.Exec("delete from config_file_gray_release where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
 	_, err = tx.Exec("delete from config_file_release_history where namespace = ? ", testNamespace)
 	if err != nil {
 		return err
@@ -263,6 +273,14 @@ func (c *ConfigCenterTest) clearTestDataWhenUseRDS() error {
 	if err != nil {
 		return err
 	}
+	_, err = tx.Exec("delete from config_file_approval_policy where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("delete from config_file_release_request where namespace = ? ", testNamespace)
+	if err != nil {
+		return err
+	}
 	_, err = tx.Exec("delete from namespace where name = ? ", testNamespace)
 	if err != nil {
 		return err
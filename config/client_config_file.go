@@ -75,6 +75,10 @@ func (s *Server) GetConfigFileForClient(ctx context.Context,
 		return api.NewConfigClientResponse(apimodel.Code_NotFoundResource, nil)
 	}
 
+	// 命中灰度规则的客户端获取灰度发布的内容
+	grayClient := newGrayClient(utils.ParseClientIP(ctx), client)
+	entry = entry.Select(grayClient)
+
 	// 客户端版本号大于服务端版本号，服务端需要重新加载缓存
 	if clientVersion > entry.Version {
 		entry, err = s.fileCache.ReLoad(namespace, group, fileName)
@@ -86,6 +90,7 @@ func (s *Server) GetConfigFileForClient(ctx context.Context,
 			return api.NewConfigClientResponseWithMessage(
 				apimodel.Code_ExecuteException, "load config file error")
 		}
+		entry = entry.Select(grayClient)
 	}
 
 	log.Info("[Config][Client] client get config file success.",
@@ -166,7 +171,7 @@ func (s *Server) WatchConfigFiles(ctx context.Context,
 	// 3. 监听配置变更，hold 请求 30s，30s 内如果有配置发布，则响应请求
 	clientId := clientAddr + "@" + utils.NewUUID()[0:8]
 
-	finishChan := s.ConnManager().AddConn(clientId, watchFiles)
+	finishChan := s.ConnManager().AddConn(clientId, utils.ParseClientIP(ctx), watchFiles)
 
 	return func() *apiconfig.ConfigClientResponse {
 		return <-finishChan
@@ -210,6 +215,8 @@ func (s *Server) doCheckClientConfigFile(ctx context.Context, configFiles []*api
 			return api.NewConfigClientResponse(apimodel.Code_ExecuteException, nil)
 		}
 
+		// 命中灰度规则的客户端按照灰度发布的版本进行比较
+		entry = entry.Select(newGrayClient(utils.ParseClientIP(ctx), configFile))
 		if compartor(configFile, entry) {
 			return utils2.GenConfigFileResponse(namespace, group, fileName, "", entry.Md5, entry.Version, false, "")
 		}
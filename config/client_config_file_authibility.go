@@ -95,3 +95,8 @@ func (s *serverAuthability) WatchConfigFiles(ctx context.Context,
 	request *apiconfig.ClientWatchConfigFileRequest) (WatchCallback, error) {
 	return s.targetServer.WatchConfigFiles(ctx, request)
 }
+
+// OpenWatchStream 基于 stream 监听配置文件变化
+func (s *serverAuthability) OpenWatchStream(ctx context.Context) *WatchStream {
+	return s.targetServer.OpenWatchStream(ctx)
+}
@@ -385,7 +385,7 @@ func TestWatchConfigFileAtFirstPublish(t *testing.T) {
 			testSuit.testServer.WatchCenter().RemoveWatcher(clientId, watchConfigFiles)
 		}()
 
-		testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
+		testSuit.testServer.WatchCenter().AddWatcher(clientId, "127.0.0.1", watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
 			t.Logf("clientId=[%s] receive config publish msg", clientId)
 			received <- rsp.ConfigFile.Version.GetValue()
 			return true
@@ -410,7 +410,7 @@ func TestWatchConfigFileAtFirstPublish(t *testing.T) {
 
 		clientId := "TestWatchConfigFileAtFirstPublish-second"
 
-		testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
+		testSuit.testServer.WatchCenter().AddWatcher(clientId, "127.0.0.1", watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
 			t.Logf("clientId=[%s] receive config publish msg", clientId)
 			received <- rsp.ConfigFile.Version.GetValue()
 			return true
@@ -449,7 +449,7 @@ func Test10000ClientWatchConfigFile(t *testing.T) {
 		clientId := fmt.Sprintf("Test10000ClientWatchConfigFile-client-id=%d", i)
 		received[clientId] = false
 		receivedVersion[clientId] = uint64(0)
-		testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
+		testSuit.testServer.WatchCenter().AddWatcher(clientId, "127.0.0.1", watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
 			received[clientId] = true
 			receivedVersion[clientId] = rsp.ConfigFile.Version.GetValue()
 			return true
@@ -518,7 +518,7 @@ func TestDeleteConfigFile(t *testing.T) {
 
 	t.Log("add config watcher")
 
-	testSuit.testServer.WatchCenter().AddWatcher(clientId, watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
+	testSuit.testServer.WatchCenter().AddWatcher(clientId, "127.0.0.1", watchConfigFiles, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
 		received <- rsp.ConfigFile.Version.GetValue()
 		return true
 	})
@@ -544,3 +544,93 @@ func TestDeleteConfigFile(t *testing.T) {
 	rsp4 := testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx, fileInfo)
 	assert.Equal(t, uint32(api.NotFoundResource), rsp4.Code.GetValue())
 }
+
+// TestWatchConfigFileStream 测试基于 stream 订阅配置，订阅后版本落后立即推送，配置发布主动推送，取消订阅后不再推送
+func TestWatchConfigFileStream(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	watchStream := testSuit.testService.OpenWatchStream(testSuit.defaultCtx)
+	defer watchStream.Close()
+
+	waitNotify := func(t *testing.T) *apiconfig.ConfigClientResponse {
+		select {
+		case notify := <-watchStream.Notifications():
+			return notify
+		case <-time.After(3 * time.Second):
+			return nil
+		}
+	}
+
+	t.Run("参数错误", func(t *testing.T) {
+		watchRsp := watchStream.Subscribe(testSuit.defaultCtx, &apiconfig.ClientWatchConfigFileRequest{
+			WatchFiles: []*apiconfig.ClientConfigFileInfo{{Namespace: utils.NewStringValue(testNamespace)}},
+		})
+		assert.Equal(t, api.BadRequest, watchRsp.Code.GetValue())
+	})
+
+	t.Run("订阅时版本落后立即推送", func(t *testing.T) {
+		watchRsp := watchStream.Subscribe(testSuit.defaultCtx, &apiconfig.ClientWatchConfigFileRequest{
+			WatchFiles: assembleDefaultClientConfigFile(0),
+		})
+		assert.Equal(t, api.ExecuteSuccess, watchRsp.Code.GetValue())
+
+		notify := waitNotify(t)
+		assert.NotNil(t, notify)
+		assert.Equal(t, uint64(1), notify.GetConfigFile().GetVersion().GetValue())
+	})
+
+	t.Run("配置发布主动推送", func(t *testing.T) {
+		watchRsp := watchStream.Subscribe(testSuit.defaultCtx, &apiconfig.ClientWatchConfigFileRequest{
+			WatchFiles: assembleDefaultClientConfigFile(1),
+		})
+		assert.Equal(t, api.ExecuteSuccess, watchRsp.Code.GetValue())
+
+		for i := 2; i <= 3; i++ {
+			rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+			assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+			notify := waitNotify(t)
+			assert.NotNil(t, notify)
+			assert.Equal(t, uint64(i), notify.GetConfigFile().GetVersion().GetValue())
+		}
+	})
+
+	t.Run("取消订阅后不再推送", func(t *testing.T) {
+		watchRsp := watchStream.Subscribe(testSuit.defaultCtx, &apiconfig.ClientWatchConfigFileRequest{})
+		assert.Equal(t, api.ExecuteSuccess, watchRsp.Code.GetValue())
+
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+		select {
+		case notify := <-watchStream.Notifications():
+			t.Fatalf("unexpected notify after unsubscribe: %+v", notify)
+		case <-time.After(time.Second):
+		}
+	})
+
+	t.Run("关闭stream", func(t *testing.T) {
+		watchStream.Close()
+		<-watchStream.Done()
+
+		watchRsp := watchStream.Subscribe(testSuit.defaultCtx, &apiconfig.ClientWatchConfigFileRequest{
+			WatchFiles: assembleDefaultClientConfigFile(0),
+		})
+		assert.Equal(t, api.ExecuteException, watchRsp.Code.GetValue())
+	})
+}
@@ -43,6 +43,12 @@ import (
 
 // CreateConfigFile 创建配置文件
 func (s *Server) CreateConfigFile(ctx context.Context, configFile *apiconfig.ConfigFile) *apiconfig.ConfigResponse {
+	// 校验配置内容是否符合配置格式，避免客户端拿到无法解析的配置
+	if code, err := s.checkConfigFileContent(ctx, configFile.GetNamespace().GetValue(),
+		configFile.GetGroup().GetValue(), configFile.GetFormat().GetValue(),
+		configFile.GetContent().GetValue()); err != nil {
+		return api.NewConfigFileResponseWithMessage(code, err.Error())
+	}
 	if rsp := s.prepareCreateConfigFile(ctx, configFile); rsp.Code.Value != api.ExecuteSuccess {
 		return rsp
 	}
@@ -413,6 +419,15 @@ func (s *Server) UpdateConfigFile(ctx context.Context, configFile *apiconfig.Con
 		return api.NewConfigFileResponse(apimodel.Code_NotFoundResource, configFile)
 	}
 
+	format := configFile.GetFormat().GetValue()
+	if format == "" {
+		format = managedFile.Format
+	}
+	if code, err := s.checkConfigFileContent(ctx, namespace, group, format,
+		configFile.GetContent().GetValue()); err != nil {
+		return api.NewConfigFileResponseWithMessage(code, err.Error())
+	}
+
 	userName := utils.ParseUserName(ctx)
 	configFile.ModifyBy = utils.NewStringValue(userName)
 
@@ -691,6 +706,15 @@ func (s *Server) ExportConfigFile(ctx context.Context,
 // ImportConfigFile 导入配置文件
 func (s *Server) ImportConfigFile(ctx context.Context,
 	configFiles []*apiconfig.ConfigFile, conflictHandling string) *apiconfig.ConfigImportResponse {
+	for _, configFile := range configFiles {
+		if code, err := s.checkConfigFileContent(ctx, configFile.GetNamespace().GetValue(),
+			configFile.GetGroup().GetValue(), configFile.GetFormat().GetValue(),
+			configFile.GetContent().GetValue()); err != nil {
+			return api.NewConfigFileImportResponseWithMessage(code,
+				configFile.GetGroup().GetValue()+"/"+configFile.GetName().GetValue()+": "+err.Error())
+		}
+	}
+
 	// 预创建命名空间和分组
 	// TODO 由于创建命名空间和配置分组的boltDB store API未支持外部传入Tx，导致无法放入到业务显示开启的事物后，否则会因重复开启读写事物导致死锁
 	for _, configFile := range configFiles {
@@ -957,7 +981,8 @@ func (s *Server) fillReleaseAndTags(ctx context.Context, file *apiconfig.ConfigF
 	}
 
 	latestRelease := latestReleaseRsp.ConfigFileReleaseHistory
-	if latestRelease != nil && latestRelease.Type.GetValue() == utils.ReleaseTypeNormal {
+	if latestRelease != nil && (latestRelease.Type.GetValue() == utils.ReleaseTypeNormal ||
+		latestRelease.Type.GetValue() == utils.ReleaseTypeRollback) {
 		file.ReleaseBy = latestRelease.CreateBy
 		file.ReleaseTime = latestRelease.CreateTime
 
@@ -967,6 +992,11 @@ func (s *Server) fillReleaseAndTags(ctx context.Context, file *apiconfig.ConfigF
 		} else {
 			file.Status = utils.NewStringValue(utils.ReleaseStatusToRelease)
 		}
+	} else if latestRelease != nil && latestRelease.Type.GetValue() == utils.ReleaseTypeGray {
+		// 灰度发布中，需要全量发布或者放弃灰度之后才能再次全量发布
+		file.ReleaseBy = latestRelease.CreateBy
+		file.ReleaseTime = latestRelease.CreateTime
+		file.Status = utils.NewStringValue(utils.ReleaseStatusGray)
 	} else {
 		// 如果从来没有发布过，也是待发布状态
 		file.Status = utils.NewStringValue(utils.ReleaseStatusToRelease)
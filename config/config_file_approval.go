@@ -0,0 +1,637 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+	"github.com/polarismesh/polaris/store"
+)
+
+// contextApprovedReleaseRequestKey 审批通过后执行发布时，通过该 key 在上下文中携带对应的发布申请
+const contextApprovedReleaseRequestKey = utils.StringContext("Config-Approved-Release-Request")
+
+// ConfigApprovalPolicy 配置发布审批策略，Group 为空时对整个命名空间生效
+type ConfigApprovalPolicy struct {
+	Namespace         string   `json:"namespace"`
+	Group             string   `json:"group"`
+	RequiredApprovals int      `json:"required_approvals"`
+	Users             []string `json:"users"`
+	UserGroups        []string `json:"user_groups"`
+	CreateBy          string   `json:"create_by,omitempty"`
+	CreateTime        string   `json:"create_time,omitempty"`
+	ModifyBy          string   `json:"modify_by,omitempty"`
+	ModifyTime        string   `json:"modify_time,omitempty"`
+}
+
+// ConfigApprovalPolicyResponse 配置发布审批策略操作结果
+type ConfigApprovalPolicyResponse struct {
+	Code   uint32                `json:"code"`
+	Info   string                `json:"info"`
+	Policy *ConfigApprovalPolicy `json:"policy,omitempty"`
+}
+
+// NewConfigApprovalPolicyResponse 创建配置发布审批策略操作结果
+func NewConfigApprovalPolicyResponse(code apimodel.Code,
+	policy *model.ConfigFileApprovalPolicy) *ConfigApprovalPolicyResponse {
+	return &ConfigApprovalPolicyResponse{
+		Code:   uint32(code),
+		Info:   api.Code2Info(uint32(code)),
+		Policy: approvalPolicy2Api(policy),
+	}
+}
+
+// NewConfigApprovalPolicyResponseWithMessage 创建带有错误信息的配置发布审批策略操作结果
+func NewConfigApprovalPolicyResponseWithMessage(code apimodel.Code, message string) *ConfigApprovalPolicyResponse {
+	return &ConfigApprovalPolicyResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + message,
+	}
+}
+
+// ConfigReleaseRequestInfo 配置发布申请
+type ConfigReleaseRequestInfo struct {
+	Id                uint64   `json:"id"`
+	Namespace         string   `json:"namespace"`
+	Group             string   `json:"group"`
+	FileName          string   `json:"file_name"`
+	ReleaseName       string   `json:"release_name"`
+	Comment           string   `json:"comment"`
+	Content           string   `json:"content"`
+	Md5               string   `json:"md5"`
+	Status            string   `json:"status"`
+	RequiredApprovals int      `json:"required_approvals"`
+	Approvers         []string `json:"approvers"`
+	Reason            string   `json:"reason,omitempty"`
+	SourceHistoryId   uint64   `json:"source_history_id,omitempty"`
+	CreateBy          string   `json:"create_by"`
+	CreateTime        string   `json:"create_time"`
+	ModifyBy          string   `json:"modify_by"`
+	ModifyTime        string   `json:"modify_time"`
+}
+
+// ConfigReleaseRequestReview 审批、驳回以及撤销配置发布申请的请求
+type ConfigReleaseRequestReview struct {
+	Id        uint64 `json:"id"`
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	FileName  string `json:"file_name"`
+	Reason    string `json:"reason"`
+}
+
+// ConfigReleaseRequestResponse 配置发布申请操作结果
+type ConfigReleaseRequestResponse struct {
+	Code    uint32                    `json:"code"`
+	Info    string                    `json:"info"`
+	Request *ConfigReleaseRequestInfo `json:"request,omitempty"`
+}
+
+// NewConfigReleaseRequestResponse 创建配置发布申请操作结果
+func NewConfigReleaseRequestResponse(code apimodel.Code,
+	request *model.ConfigFileReleaseRequest) *ConfigReleaseRequestResponse {
+	return &ConfigReleaseRequestResponse{
+		Code:    uint32(code),
+		Info:    api.Code2Info(uint32(code)),
+		Request: releaseRequest2Api(request),
+	}
+}
+
+// NewConfigReleaseRequestResponseWithMessage 创建带有错误信息的配置发布申请操作结果
+func NewConfigReleaseRequestResponseWithMessage(code apimodel.Code, message string) *ConfigReleaseRequestResponse {
+	return &ConfigReleaseRequestResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + message,
+	}
+}
+
+// ConfigReleaseRequestBatchResponse 配置发布申请查询结果
+type ConfigReleaseRequestBatchResponse struct {
+	Code     uint32                      `json:"code"`
+	Info     string                      `json:"info"`
+	Total    uint32                      `json:"total"`
+	Requests []*ConfigReleaseRequestInfo `json:"requests"`
+}
+
+// NewConfigReleaseRequestBatchResponse 创建配置发布申请查询结果
+func NewConfigReleaseRequestBatchResponse(code apimodel.Code, total uint32,
+	requests []*model.ConfigFileReleaseRequest) *ConfigReleaseRequestBatchResponse {
+	ret := &ConfigReleaseRequestBatchResponse{
+		Code:     uint32(code),
+		Info:     api.Code2Info(uint32(code)),
+		Total:    total,
+		Requests: make([]*ConfigReleaseRequestInfo, 0, len(requests)),
+	}
+	for _, request := range requests {
+		ret.Requests = append(ret.Requests, releaseRequest2Api(request))
+	}
+	return ret
+}
+
+// UpdateConfigApprovalPolicy 设置配置发布审批策略，已存在则覆盖
+func (s *Server) UpdateConfigApprovalPolicy(ctx context.Context,
+	req *ConfigApprovalPolicy) *ConfigApprovalPolicyResponse {
+	if code := checkApprovalPolicyParams(req.Namespace, req.Group); code != apimodel.Code_ExecuteSuccess {
+		return NewConfigApprovalPolicyResponse(code, nil)
+	}
+	if len(req.Users) == 0 && len(req.UserGroups) == 0 {
+		return NewConfigApprovalPolicyResponseWithMessage(apimodel.Code_BadRequest,
+			"users and user_groups can not be both empty")
+	}
+	if req.RequiredApprovals <= 0 {
+		return NewConfigApprovalPolicyResponseWithMessage(apimodel.Code_BadRequest,
+			"required_approvals must be greater than 0")
+	}
+	// 只配置了审批用户时，审批人数不能超过可以审批的用户数，否则发布申请永远无法通过
+	if len(req.UserGroups) == 0 && req.RequiredApprovals > len(req.Users) {
+		return NewConfigApprovalPolicyResponseWithMessage(apimodel.Code_BadRequest,
+			"required_approvals can not be greater than the number of users")
+	}
+	if !s.checkNamespaceExisted(req.Namespace) {
+		return NewConfigApprovalPolicyResponse(apimodel.Code_NotFoundNamespace, nil)
+	}
+
+	userName := utils.ParseUserName(ctx)
+	policy := &model.ConfigFileApprovalPolicy{
+		Namespace:         req.Namespace,
+		Group:             req.Group,
+		RequiredApprovals: req.RequiredApprovals,
+		Users:             model.JoinApprovers(req.Users),
+		UserGroups:        model.JoinApprovers(req.UserGroups),
+		CreateBy:          userName,
+		ModifyBy:          userName,
+	}
+	if err := s.storage.SaveConfigFileApprovalPolicy(policy); err != nil {
+		log.Error("[Config][Service] save config approval policy error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(req.Namespace), utils.ZapGroup(req.Group), zap.Error(err))
+		return NewConfigApprovalPolicyResponse(apimodel.Code_StoreLayerException, nil)
+	}
+
+	saved, err := s.storage.GetConfigFileApprovalPolicy(req.Namespace, req.Group)
+	if err != nil {
+		log.Error("[Config][Service] get config approval policy error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(req.Namespace), utils.ZapGroup(req.Group), zap.Error(err))
+		return NewConfigApprovalPolicyResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	s.RecordHistory(ctx, approvalPolicyRecordEntry(ctx, policy, model.OUpdate))
+	return NewConfigApprovalPolicyResponse(apimodel.Code_ExecuteSuccess, saved)
+}
+
+// GetConfigApprovalPolicy 查询配置发布审批策略
+func (s *Server) GetConfigApprovalPolicy(ctx context.Context,
+	namespace, group string) *ConfigApprovalPolicyResponse {
+	if code := checkApprovalPolicyParams(namespace, group); code != apimodel.Code_ExecuteSuccess {
+		return NewConfigApprovalPolicyResponse(code, nil)
+	}
+	policy, err := s.storage.GetConfigFileApprovalPolicy(namespace, group)
+	if err != nil {
+		log.Error("[Config][Service] get config approval policy error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), zap.Error(err))
+		return NewConfigApprovalPolicyResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if policy == nil {
+		return NewConfigApprovalPolicyResponse(apimodel.Code_NotFoundResource, nil)
+	}
+	return NewConfigApprovalPolicyResponse(apimodel.Code_ExecuteSuccess, policy)
+}
+
+// DeleteConfigApprovalPolicy 删除配置发布审批策略，已经创建的发布申请不受影响
+func (s *Server) DeleteConfigApprovalPolicy(ctx context.Context,
+	namespace, group string) *ConfigApprovalPolicyResponse {
+	if code := checkApprovalPolicyParams(namespace, group); code != apimodel.Code_ExecuteSuccess {
+		return NewConfigApprovalPolicyResponse(code, nil)
+	}
+	if err := s.storage.DeleteConfigFileApprovalPolicy(namespace, group); err != nil {
+		log.Error("[Config][Service] delete config approval policy error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), zap.Error(err))
+		return NewConfigApprovalPolicyResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	s.RecordHistory(ctx, approvalPolicyRecordEntry(ctx, &model.ConfigFileApprovalPolicy{
+		Namespace: namespace,
+		Group:     group,
+	}, model.ODelete))
+	return NewConfigApprovalPolicyResponse(apimodel.Code_ExecuteSuccess, nil)
+}
+
+// QueryConfigReleaseRequests 查询配置发布申请，按照申请时间倒序排序
+func (s *Server) QueryConfigReleaseRequests(ctx context.Context, namespace, group, fileName, status string,
+	offset, limit uint32) *ConfigReleaseRequestBatchResponse {
+	if limit > MaxPageSize {
+		return &ConfigReleaseRequestBatchResponse{
+			Code: uint32(apimodel.Code_InvalidParameter),
+			Info: api.Code2Info(uint32(apimodel.Code_InvalidParameter)) + ":limit is too large",
+		}
+	}
+	if limit == 0 {
+		limit = MaxPageSize
+	}
+	total, requests, err := s.storage.QueryConfigFileReleaseRequests(namespace, group, fileName, status,
+		offset, limit)
+	if err != nil {
+		log.Error("[Config][Service] query config release requests error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigReleaseRequestBatchResponse(apimodel.Code_StoreLayerException, 0, nil)
+	}
+	return NewConfigReleaseRequestBatchResponse(apimodel.Code_ExecuteSuccess, total, requests)
+}
+
+// ApproveConfigReleaseRequest 同意配置发布申请，同意的审批人数达到策略要求后自动发布申请中的配置内容
+func (s *Server) ApproveConfigReleaseRequest(ctx context.Context,
+	req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse {
+	request, rsp := s.getPendingReleaseRequest(ctx, req)
+	if rsp != nil {
+		return rsp
+	}
+
+	userName := utils.ParseUserName(ctx)
+	// 发起人不能审批自己的发布申请
+	if userName == "" || userName == request.CreateBy {
+		return NewConfigReleaseRequestResponseWithMessage(apimodel.Code_NotAllowedAccess,
+			"release request can not be approved by its creator")
+	}
+	policy, err := s.getConfigApprovalPolicy(request.Namespace, request.Group)
+	if err != nil {
+		log.Error("[Config][Service] get config approval policy error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(request.Namespace), utils.ZapGroup(request.Group), zap.Error(err))
+		return NewConfigReleaseRequestResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if policy == nil || !s.isReleaseApprover(ctx, policy) {
+		return NewConfigReleaseRequestResponseWithMessage(apimodel.Code_NotAllowedAccess,
+			"user is not an approver of the release request")
+	}
+	if request.HasApproved(userName) {
+		return NewConfigReleaseRequestResponseWithMessage(apimodel.Code_DataConflict,
+			"user has already approved the release request")
+	}
+
+	preRevision := request.Revision
+	request.Approvers = model.JoinApprovers(append(request.ApproverList(), userName))
+	request.Revision = utils.NewUUID()
+	request.ModifyBy = userName
+	if len(request.ApproverList()) >= request.RequiredApprovals {
+		request.Status = model.ReleaseRequestStatusApproved
+	}
+	if rsp := s.updateReleaseRequest(ctx, request, preRevision); rsp != nil {
+		return rsp
+	}
+	if request.Status != model.ReleaseRequestStatusApproved {
+		s.RecordHistory(ctx, releaseRequestRecordEntry(ctx, request, model.OUpdate))
+		return NewConfigReleaseRequestResponse(apimodel.Code_ExecuteSuccess, request)
+	}
+
+	// 审批通过，以申请人的身份发布申请中的配置内容，审批人记录到发布历史中
+	publishCtx := context.WithValue(ctx, contextApprovedReleaseRequestKey, request)
+	publishCtx = context.WithValue(publishCtx, utils.ContextUserNameKey, request.CreateBy)
+	publishRsp := s.PublishConfigFile(publishCtx, &apiconfig.ConfigFileRelease{
+		Name:      utils.NewStringValue(request.ReleaseName),
+		Namespace: utils.NewStringValue(request.Namespace),
+		Group:     utils.NewStringValue(request.Group),
+		FileName:  utils.NewStringValue(request.FileName),
+		Comment:   utils.NewStringValue(request.Comment),
+	})
+	if publishRsp.GetCode().GetValue() != api.ExecuteSuccess {
+		preRevision = request.Revision
+		request.Status = model.ReleaseRequestStatusFailed
+		request.Reason = publishRsp.GetInfo().GetValue()
+		request.Revision = utils.NewUUID()
+		if rsp := s.updateReleaseRequest(ctx, request, preRevision); rsp != nil {
+			return rsp
+		}
+		return NewConfigReleaseRequestResponseWithMessage(apimodel.Code(publishRsp.GetCode().GetValue()),
+			request.Reason)
+	}
+	s.RecordHistory(ctx, releaseRequestRecordEntry(ctx, request, model.OUpdate))
+	return NewConfigReleaseRequestResponse(apimodel.Code_ExecuteSuccess, request)
+}
+
+// RejectConfigReleaseRequest 驳回配置发布申请
+func (s *Server) RejectConfigReleaseRequest(ctx context.Context,
+	req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse {
+	request, rsp := s.getPendingReleaseRequest(ctx, req)
+	if rsp != nil {
+		return rsp
+	}
+	policy, err := s.getConfigApprovalPolicy(request.Namespace, request.Group)
+	if err != nil {
+		log.Error("[Config][Service] get config approval policy error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(request.Namespace), utils.ZapGroup(request.Group), zap.Error(err))
+		return NewConfigReleaseRequestResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if policy == nil || !s.isReleaseApprover(ctx, policy) {
+		return NewConfigReleaseRequestResponseWithMessage(apimodel.Code_NotAllowedAccess,
+			"user is not an approver of the release request")
+	}
+	return s.finishReleaseRequest(ctx, request, model.ReleaseRequestStatusRejected, req.Reason)
+}
+
+// CancelConfigReleaseRequest 撤销配置发布申请，只有发起人可以撤销
+func (s *Server) CancelConfigReleaseRequest(ctx context.Context,
+	req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse {
+	request, rsp := s.getPendingReleaseRequest(ctx, req)
+	if rsp != nil {
+		return rsp
+	}
+	if utils.ParseUserName(ctx) != request.CreateBy {
+		return NewConfigReleaseRequestResponseWithMessage(apimodel.Code_NotAllowedAccess,
+			"release request can only be canceled by its creator")
+	}
+	return s.finishReleaseRequest(ctx, request, model.ReleaseRequestStatusCanceled, req.Reason)
+}
+
+// createConfigFileReleaseRequest 命中审批策略时，保存配置文件当前的内容并创建待审批的发布申请
+func (s *Server) createConfigFileReleaseRequest(ctx context.Context, policy *model.ConfigFileApprovalPolicy,
+	configFileRelease *apiconfig.ConfigFileRelease, toPublishFile *model.ConfigFile) *apiconfig.ConfigResponse {
+	namespace, group, fileName := toPublishFile.Namespace, toPublishFile.Group, toPublishFile.Name
+	_, pendings, err := s.storage.QueryConfigFileReleaseRequests(namespace, group, fileName,
+		model.ReleaseRequestStatusPending, 0, 1)
+	if err != nil {
+		log.Error("[Config][Service] query pending release request error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if len(pendings) > 0 {
+		return api.NewConfigFileResponseWithMessage(apimodel.Code_DataConflict,
+			fmt.Sprintf("release request %d is waiting for approval", pendings[0].Id))
+	}
+
+	userName := utils.ParseUserName(ctx)
+	request, err := s.storage.CreateConfigFileReleaseRequest(s.getTx(ctx), &model.ConfigFileReleaseRequest{
+		Namespace:         namespace,
+		Group:             group,
+		FileName:          fileName,
+		ReleaseName:       configFileRelease.GetName().GetValue(),
+		Comment:           configFileRelease.GetComment().GetValue(),
+		Content:           toPublishFile.Content,
+		Md5:               utils2.CalMd5(toPublishFile.Content),
+		Status:            model.ReleaseRequestStatusPending,
+		RequiredApprovals: policy.RequiredApprovals,
+		SourceHistoryId:   releaseSourceHistoryId(ctx),
+		Revision:          utils.NewUUID(),
+		CreateBy:          userName,
+		ModifyBy:          userName,
+	})
+	if err != nil {
+		log.Error("[Config][Service] create release request error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	s.RecordHistory(ctx, releaseRequestRecordEntry(ctx, request, model.OCreate))
+	return api.NewConfigFileResponseWithMessage(apimodel.Code_ExecuteSuccess,
+		fmt.Sprintf("release request %d is waiting for approval", request.Id))
+}
+
+// releaseApprovalPolicy 全量发布、回滚、灰度发布以及灰度全量发布都会把配置推送给客户端，统一通过这里检查审批策略
+// 审批通过后以申请人身份执行的发布不再检查，未命中审批策略时返回 nil
+func (s *Server) releaseApprovalPolicy(ctx context.Context,
+	namespace, group, fileName string) (*model.ConfigFileApprovalPolicy, error) {
+	approved, _ := ctx.Value(contextApprovedReleaseRequestKey).(*model.ConfigFileReleaseRequest)
+	if approved != nil && approved.Namespace == namespace && approved.Group == group &&
+		approved.FileName == fileName {
+		return nil, nil
+	}
+	policy, err := s.getConfigApprovalPolicy(namespace, group)
+	if err != nil {
+		log.Error("[Config][Service] get config approval policy error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return nil, err
+	}
+	return policy, nil
+}
+
+// checkGrayReleaseApproval 发布申请中不包含灰度规则，命中审批策略的配置文件不允许灰度发布以及灰度全量发布
+func (s *Server) checkGrayReleaseApproval(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	policy, err := s.releaseApprovalPolicy(ctx, namespace, group, fileName)
+	if err != nil {
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+	if policy != nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_NotAllowedAccess,
+			"config file is protected by approval policy, gray release is not allowed")
+	}
+	return nil
+}
+
+// getConfigApprovalPolicy 获取配置分组生效的审批策略，分组上的策略优先于命名空间上的策略
+func (s *Server) getConfigApprovalPolicy(namespace, group string) (*model.ConfigFileApprovalPolicy, error) {
+	policy, err := s.storage.GetConfigFileApprovalPolicy(namespace, group)
+	if err != nil || policy != nil {
+		return policy, err
+	}
+	return s.storage.GetConfigFileApprovalPolicy(namespace, "")
+}
+
+// isReleaseApprover 当前用户是否在审批策略的用户列表中，或者属于审批策略中的某个用户组
+func (s *Server) isReleaseApprover(ctx context.Context, policy *model.ConfigFileApprovalPolicy) bool {
+	userName := utils.ParseUserName(ctx)
+	if userName == "" {
+		return false
+	}
+	for _, user := range policy.ApproverUsers() {
+		if user == userName {
+			return true
+		}
+	}
+
+	userGroups := policy.ApproverUserGroups()
+	userID := utils.ParseUserID(ctx)
+	if len(userGroups) == 0 || userID == "" || s.caches == nil {
+		return false
+	}
+	userCache := s.caches.User()
+	for _, groupID := range userCache.GetUserLinkGroupIds(userID) {
+		userGroup := userCache.GetGroup(groupID)
+		if userGroup == nil {
+			continue
+		}
+		for _, name := range userGroups {
+			if name == userGroup.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) getPendingReleaseRequest(ctx context.Context,
+	req *ConfigReleaseRequestReview) (*model.ConfigFileReleaseRequest, *ConfigReleaseRequestResponse) {
+	if req.Id == 0 {
+		return nil, NewConfigReleaseRequestResponseWithMessage(apimodel.Code_BadRequest, "id is required")
+	}
+	request, err := s.storage.GetConfigFileReleaseRequest(s.getTx(ctx), req.Id)
+	if err != nil {
+		log.Error("[Config][Service] get release request error.", utils.ZapRequestIDByCtx(ctx),
+			zap.Uint64("id", req.Id), zap.Error(err))
+		return nil, NewConfigReleaseRequestResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	// 请求中的配置文件信息用于鉴权，需要与发布申请保持一致
+	if request == nil || request.Namespace != req.Namespace || request.Group != req.Group ||
+		request.FileName != req.FileName {
+		return nil, NewConfigReleaseRequestResponse(apimodel.Code_NotFoundResource, nil)
+	}
+	if request.Status != model.ReleaseRequestStatusPending {
+		return nil, NewConfigReleaseRequestResponseWithMessage(apimodel.Code_DataConflict,
+			fmt.Sprintf("release request is already %s", request.Status))
+	}
+	return request, nil
+}
+
+func (s *Server) finishReleaseRequest(ctx context.Context, request *model.ConfigFileReleaseRequest,
+	status, reason string) *ConfigReleaseRequestResponse {
+	preRevision := request.Revision
+	request.Status = status
+	request.Reason = reason
+	request.Revision = utils.NewUUID()
+	request.ModifyBy = utils.ParseUserName(ctx)
+	if rsp := s.updateReleaseRequest(ctx, request, preRevision); rsp != nil {
+		return rsp
+	}
+	s.RecordHistory(ctx, releaseRequestRecordEntry(ctx, request, model.OUpdate))
+	return NewConfigReleaseRequestResponse(apimodel.Code_ExecuteSuccess, request)
+}
+
+func (s *Server) updateReleaseRequest(ctx context.Context, request *model.ConfigFileReleaseRequest,
+	preRevision string) *ConfigReleaseRequestResponse {
+	err := s.storage.UpdateConfigFileReleaseRequest(s.getTx(ctx), request, preRevision)
+	if err == nil {
+		return nil
+	}
+	if store.Code(err) == store.DataConflictErr {
+		return NewConfigReleaseRequestResponseWithMessage(apimodel.Code_DataConflict,
+			"release request has been modified by others, please retry")
+	}
+	log.Error("[Config][Service] update release request error.", utils.ZapRequestIDByCtx(ctx),
+		zap.Uint64("id", request.Id), zap.Error(err))
+	return NewConfigReleaseRequestResponse(apimodel.Code_StoreLayerException, nil)
+}
+
+// releaseApprovers 审批通过后发布时返回审批人，用于记录到发布历史中
+func releaseApprovers(ctx context.Context) string {
+	request, _ := ctx.Value(contextApprovedReleaseRequestKey).(*model.ConfigFileReleaseRequest)
+	if request == nil {
+		return ""
+	}
+	return request.Approvers
+}
+
+// releaseSourceHistoryId 回滚时返回回滚到的历史发布 ID，审批通过后发布时从发布申请中获取，其余发布返回 0
+func releaseSourceHistoryId(ctx context.Context) uint64 {
+	if id, ok := ctx.Value(contextRollbackHistoryKey).(uint64); ok {
+		return id
+	}
+	request, _ := ctx.Value(contextApprovedReleaseRequestKey).(*model.ConfigFileReleaseRequest)
+	if request == nil {
+		return 0
+	}
+	return request.SourceHistoryId
+}
+
+func checkApprovalPolicyParams(namespace, group string) apimodel.Code {
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return apimodel.Code_InvalidNamespaceName
+	}
+	// group 为空表示命名空间级别的审批策略
+	if group != "" {
+		if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+			return apimodel.Code_InvalidConfigFileGroupName
+		}
+	}
+	return apimodel.Code_ExecuteSuccess
+}
+
+func approvalPolicy2Api(policy *model.ConfigFileApprovalPolicy) *ConfigApprovalPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &ConfigApprovalPolicy{
+		Namespace:         policy.Namespace,
+		Group:             policy.Group,
+		RequiredApprovals: policy.RequiredApprovals,
+		Users:             policy.ApproverUsers(),
+		UserGroups:        policy.ApproverUserGroups(),
+		CreateBy:          policy.CreateBy,
+		CreateTime:        commontime.Time2String(policy.CreateTime),
+		ModifyBy:          policy.ModifyBy,
+		ModifyTime:        commontime.Time2String(policy.ModifyTime),
+	}
+}
+
+func releaseRequest2Api(request *model.ConfigFileReleaseRequest) *ConfigReleaseRequestInfo {
+	if request == nil {
+		return nil
+	}
+	return &ConfigReleaseRequestInfo{
+		Id:                request.Id,
+		Namespace:         request.Namespace,
+		Group:             request.Group,
+		FileName:          request.FileName,
+		ReleaseName:       request.ReleaseName,
+		Comment:           request.Comment,
+		Content:           request.Content,
+		Md5:               request.Md5,
+		Status:            request.Status,
+		RequiredApprovals: request.RequiredApprovals,
+		Approvers:         request.ApproverList(),
+		Reason:            request.Reason,
+		SourceHistoryId:   request.SourceHistoryId,
+		CreateBy:          request.CreateBy,
+		CreateTime:        commontime.Time2String(request.CreateTime),
+		ModifyBy:          request.ModifyBy,
+		ModifyTime:        commontime.Time2String(request.ModifyTime),
+	}
+}
+
+// approvalPolicyRecordEntry 生成审批策略的操作记录
+func approvalPolicyRecordEntry(ctx context.Context, policy *model.ConfigFileApprovalPolicy,
+	operationType model.OperationType) *model.RecordEntry {
+	return &model.RecordEntry{
+		ResourceType:  model.RConfigApprovalPolicy,
+		ResourceName:  policy.Namespace + "." + policy.Group,
+		Namespace:     policy.Namespace,
+		OperationType: operationType,
+		Operator:      utils.ParseOperator(ctx),
+		Detail: fmt.Sprintf("required_approvals=%d, users=%s, user_groups=%s", policy.RequiredApprovals,
+			policy.Users, policy.UserGroups),
+		HappenTime: time.Now(),
+	}
+}
+
+// releaseRequestRecordEntry 生成发布申请的操作记录
+func releaseRequestRecordEntry(ctx context.Context, request *model.ConfigFileReleaseRequest,
+	operationType model.OperationType) *model.RecordEntry {
+	return &model.RecordEntry{
+		ResourceType:  model.RConfigReleaseRequest,
+		ResourceName:  utils.GenFileId(request.Namespace, request.Group, request.FileName),
+		Namespace:     request.Namespace,
+		OperationType: operationType,
+		Operator:      utils.ParseOperator(ctx),
+		Detail: fmt.Sprintf("id=%d, status=%s, approvers=%s", request.Id, request.Status,
+			request.Approvers),
+		HappenTime: time.Now(),
+	}
+}
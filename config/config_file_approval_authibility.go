@@ -0,0 +1,131 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+
+	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// UpdateConfigApprovalPolicy 设置配置发布审批策略
+func (s *serverAuthability) UpdateConfigApprovalPolicy(ctx context.Context,
+	req *ConfigApprovalPolicy) *ConfigApprovalPolicyResponse {
+	authCtx := s.collectConfigApprovalPolicyAuthContext(ctx, req.Namespace, req.Group, model.Modify,
+		"UpdateConfigApprovalPolicy")
+
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigApprovalPolicyResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.UpdateConfigApprovalPolicy(ctx, req)
+}
+
+// GetConfigApprovalPolicy 查询配置发布审批策略
+func (s *serverAuthability) GetConfigApprovalPolicy(ctx context.Context,
+	namespace, group string) *ConfigApprovalPolicyResponse {
+	authCtx := s.collectConfigGroupAuthContext(ctx, []*apiconfig.ConfigFileGroup{{Name: utils.NewStringValue(group),
+		Namespace: utils.NewStringValue(namespace)}}, model.Read, "GetConfigApprovalPolicy")
+
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigApprovalPolicyResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.GetConfigApprovalPolicy(ctx, namespace, group)
+}
+
+// DeleteConfigApprovalPolicy 删除配置发布审批策略
+func (s *serverAuthability) DeleteConfigApprovalPolicy(ctx context.Context,
+	namespace, group string) *ConfigApprovalPolicyResponse {
+	// 删除审批策略等同于放开命名空间下的发布，同样需要命名空间的写权限
+	authCtx := s.collectConfigApprovalPolicyAuthContext(ctx, namespace, group, model.Modify,
+		"DeleteConfigApprovalPolicy")
+
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigApprovalPolicyResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.DeleteConfigApprovalPolicy(ctx, namespace, group)
+}
+
+// QueryConfigReleaseRequests 查询配置发布申请
+func (s *serverAuthability) QueryConfigReleaseRequests(ctx context.Context, namespace, group, fileName,
+	status string, offset, limit uint32) *ConfigReleaseRequestBatchResponse {
+	authCtx := s.collectConfigGroupAuthContext(ctx, []*apiconfig.ConfigFileGroup{{Name: utils.NewStringValue(group),
+		Namespace: utils.NewStringValue(namespace)}}, model.Read, "QueryConfigReleaseRequests")
+
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		rsp := NewConfigReleaseRequestBatchResponse(convertToErrCode(err), 0, nil)
+		rsp.Info += ":" + err.Error()
+		return rsp
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.QueryConfigReleaseRequests(ctx, namespace, group, fileName, status, offset, limit)
+}
+
+// ApproveConfigReleaseRequest 同意配置发布申请，审批人的资格由审批策略决定
+func (s *serverAuthability) ApproveConfigReleaseRequest(ctx context.Context,
+	req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse {
+	return s.reviewConfigReleaseRequest(ctx, req, "ApproveConfigReleaseRequest",
+		s.targetServer.ApproveConfigReleaseRequest)
+}
+
+// RejectConfigReleaseRequest 驳回配置发布申请
+func (s *serverAuthability) RejectConfigReleaseRequest(ctx context.Context,
+	req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse {
+	return s.reviewConfigReleaseRequest(ctx, req, "RejectConfigReleaseRequest",
+		s.targetServer.RejectConfigReleaseRequest)
+}
+
+// CancelConfigReleaseRequest 撤销配置发布申请
+func (s *serverAuthability) CancelConfigReleaseRequest(ctx context.Context,
+	req *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse {
+	return s.reviewConfigReleaseRequest(ctx, req, "CancelConfigReleaseRequest",
+		s.targetServer.CancelConfigReleaseRequest)
+}
+
+// reviewConfigReleaseRequest 这里只校验配置文件的读权限，能否审批由审批策略中的用户以及用户组决定
+func (s *serverAuthability) reviewConfigReleaseRequest(ctx context.Context, req *ConfigReleaseRequestReview,
+	methodName string, action func(context.Context, *ConfigReleaseRequestReview) *ConfigReleaseRequestResponse,
+) *ConfigReleaseRequestResponse {
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{grayReleaseAuthResource(req.Namespace, req.Group, req.FileName)},
+		model.Read, methodName)
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigReleaseRequestResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return action(ctx, req)
+}
@@ -0,0 +1,373 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+// ConfigFileGrayReleaseRequest 灰度发布配置文件请求
+type ConfigFileGrayReleaseRequest struct {
+	Namespace string                    `json:"namespace"`
+	Group     string                    `json:"group"`
+	FileName  string                    `json:"file_name"`
+	Name      string                    `json:"name"`
+	Comment   string                    `json:"comment"`
+	Rule      *model.ConfigFileGrayRule `json:"rule"`
+}
+
+// ConfigFileGrayReleaseInfo 配置文件发布信息，灰度发布时携带灰度规则
+type ConfigFileGrayReleaseInfo struct {
+	Name       string                    `json:"name"`
+	Namespace  string                    `json:"namespace"`
+	Group      string                    `json:"group"`
+	FileName   string                    `json:"file_name"`
+	Content    string                    `json:"content"`
+	Comment    string                    `json:"comment"`
+	Md5        string                    `json:"md5"`
+	Version    uint64                    `json:"version"`
+	Rule       *model.ConfigFileGrayRule `json:"rule,omitempty"`
+	CreateBy   string                    `json:"create_by"`
+	CreateTime string                    `json:"create_time"`
+	ModifyBy   string                    `json:"modify_by"`
+	ModifyTime string                    `json:"modify_time"`
+}
+
+// ConfigFileGrayReleaseResponse 灰度发布操作结果，同时返回全量发布以及灰度发布的内容便于对比
+type ConfigFileGrayReleaseResponse struct {
+	Code        uint32                     `json:"code"`
+	Info        string                     `json:"info"`
+	Release     *ConfigFileGrayReleaseInfo `json:"release,omitempty"`
+	GrayRelease *ConfigFileGrayReleaseInfo `json:"gray_release,omitempty"`
+}
+
+// NewConfigFileGrayReleaseResponse 创建灰度发布操作结果
+func NewConfigFileGrayReleaseResponse(code apimodel.Code, release *model.ConfigFileRelease,
+	grayRelease *model.ConfigFileGrayRelease) *ConfigFileGrayReleaseResponse {
+	return &ConfigFileGrayReleaseResponse{
+		Code:        uint32(code),
+		Info:        api.Code2Info(uint32(code)),
+		Release:     configFileRelease2GrayInfo(release),
+		GrayRelease: configFileGrayRelease2Info(grayRelease),
+	}
+}
+
+// NewConfigFileGrayReleaseResponseWithMessage 创建带有错误信息的灰度发布操作结果
+func NewConfigFileGrayReleaseResponseWithMessage(code apimodel.Code, message string) *ConfigFileGrayReleaseResponse {
+	return &ConfigFileGrayReleaseResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + message,
+	}
+}
+
+// PublishConfigFileGray 灰度发布配置文件，只有命中灰度规则的客户端能够获取到本次发布的内容
+func (s *Server) PublishConfigFileGray(ctx context.Context,
+	req *ConfigFileGrayReleaseRequest) *ConfigFileGrayReleaseResponse {
+	if rsp := checkConfigFileGrayParams(req.Namespace, req.Group, req.FileName); rsp != nil {
+		return rsp
+	}
+	if req.Rule == nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_BadRequest, "gray rule can not be empty")
+	}
+	if err := req.Rule.Init(); err != nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_BadRequest, err.Error())
+	}
+	if !s.checkNamespaceExisted(req.Namespace) {
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_NotFoundNamespace, nil, nil)
+	}
+
+	tx := s.getTx(ctx)
+	namespace, group, fileName := req.Namespace, req.Group, req.FileName
+	toPublishFile, err := s.storage.GetConfigFile(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file error when gray publish.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+	if toPublishFile == nil {
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_NotFoundResource, nil, nil)
+	}
+	if rsp := s.checkGrayReleaseApproval(ctx, namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+
+	release, grayRelease, rsp := s.getReleaseAndGrayRelease(ctx, namespace, group, fileName)
+	if rsp != nil {
+		return rsp
+	}
+	// 灰度发布基于已有的全量发布，未全量发布过的配置文件直接全量发布即可
+	if release == nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_NotFoundResource,
+			"config file has not been released, gray release is not allowed")
+	}
+
+	// 灰度版本号需要大于全量发布以及之前的灰度版本号，保证命中灰度的客户端能够感知到变更
+	version := release.Version + 1
+	if grayRelease != nil && grayRelease.Version >= version {
+		version = grayRelease.Version + 1
+	}
+	releaseName := req.Name
+	if releaseName == "" {
+		releaseName = utils2.GenReleaseName(release.Name, fileName)
+	}
+	operator := utils.ParseUserName(ctx)
+
+	savedGrayRelease, err := s.storage.SaveConfigFileGrayRelease(tx, &model.ConfigFileGrayRelease{
+		Name:      releaseName,
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   toPublishFile.Content,
+		Comment:   req.Comment,
+		Md5:       utils2.CalMd5(toPublishFile.Content),
+		Version:   version,
+		Rule:      req.Rule.String(),
+		CreateBy:  operator,
+		ModifyBy:  operator,
+	})
+	if err != nil {
+		log.Error("[Config][Service] save config file gray release error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+
+	s.recordReleaseHistory(ctx, &model.ConfigFileRelease{
+		Name:      savedGrayRelease.Name,
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   savedGrayRelease.Content,
+		Comment:   savedGrayRelease.Comment,
+		Md5:       savedGrayRelease.Md5,
+		Version:   savedGrayRelease.Version,
+		ModifyBy:  operator,
+	}, utils.ReleaseTypeGray, utils.ReleaseStatusSuccess)
+	s.RecordHistory(ctx, configFileGrayReleaseRecordEntry(ctx, savedGrayRelease, model.OCreate))
+
+	return NewConfigFileGrayReleaseResponse(apimodel.Code_ExecuteSuccess, release, savedGrayRelease)
+}
+
+// GetConfigFileGrayRelease 查询配置文件的全量发布以及进行中的灰度发布
+func (s *Server) GetConfigFileGrayRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	if rsp := checkConfigFileGrayParams(namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+	release, grayRelease, rsp := s.getReleaseAndGrayRelease(ctx, namespace, group, fileName)
+	if rsp != nil {
+		return rsp
+	}
+	return NewConfigFileGrayReleaseResponse(apimodel.Code_ExecuteSuccess, release, grayRelease)
+}
+
+// PromoteConfigFileGrayRelease 将灰度发布的内容全量发布给所有客户端，并结束灰度
+func (s *Server) PromoteConfigFileGrayRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	if rsp := checkConfigFileGrayParams(namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+	release, grayRelease, rsp := s.getReleaseAndGrayRelease(ctx, namespace, group, fileName)
+	if rsp != nil {
+		return rsp
+	}
+	if grayRelease == nil || release == nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_NotFoundResource,
+			"gray release not found")
+	}
+	if rsp := s.checkGrayReleaseApproval(ctx, namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+
+	tx := s.getTx(ctx)
+	operator := utils.ParseUserName(ctx)
+	// 先结束灰度，再更新全量发布，保证发布扫描刷新缓存时灰度已经失效
+	if err := s.storage.DeleteConfigFileGrayRelease(tx, namespace, group, fileName, operator); err != nil {
+		log.Error("[Config][Service] delete config file gray release error when promote.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+
+	// 全量发布沿用灰度版本号，已经获取到灰度内容的客户端无需再次拉取
+	promoteRelease := &model.ConfigFileRelease{
+		Name:      grayRelease.Name,
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   grayRelease.Content,
+		Comment:   grayRelease.Comment,
+		Md5:       grayRelease.Md5,
+		Version:   grayRelease.Version,
+		ModifyBy:  operator,
+	}
+	if grayRelease.Version <= release.Version {
+		promoteRelease.Version = release.Version + 1
+	}
+	updatedRelease, err := s.storage.UpdateConfigFileRelease(tx, promoteRelease)
+	if err != nil {
+		log.Error("[Config][Service] update config file release error when promote gray release.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		s.recordReleaseFail(ctx, promoteRelease)
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+
+	s.recordReleaseHistory(ctx, updatedRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusSuccess)
+	s.RecordHistory(ctx, configFileGrayReleaseRecordEntry(ctx, grayRelease, model.OUpdate))
+
+	return NewConfigFileGrayReleaseResponse(apimodel.Code_ExecuteSuccess, updatedRelease, nil)
+}
+
+// AbandonConfigFileGrayRelease 放弃灰度发布，命中灰度的客户端重新获取全量发布的内容
+func (s *Server) AbandonConfigFileGrayRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	if rsp := checkConfigFileGrayParams(namespace, group, fileName); rsp != nil {
+		return rsp
+	}
+	release, grayRelease, rsp := s.getReleaseAndGrayRelease(ctx, namespace, group, fileName)
+	if rsp != nil {
+		return rsp
+	}
+	if grayRelease == nil || release == nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(apimodel.Code_NotFoundResource,
+			"gray release not found")
+	}
+
+	tx := s.getTx(ctx)
+	operator := utils.ParseUserName(ctx)
+	if err := s.storage.DeleteConfigFileGrayRelease(tx, namespace, group, fileName, operator); err != nil {
+		log.Error("[Config][Service] delete config file gray release error when abandon.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+
+	// 全量发布的内容不变，只提升版本号，让已经拿到灰度内容的客户端重新拉取全量发布的内容
+	release.Version = grayRelease.Version + 1
+	release.ModifyBy = operator
+	updatedRelease, err := s.storage.UpdateConfigFileRelease(tx, release)
+	if err != nil {
+		log.Error("[Config][Service] update config file release error when abandon gray release.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+
+	s.recordReleaseHistory(ctx, updatedRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusSuccess)
+	s.RecordHistory(ctx, configFileGrayReleaseRecordEntry(ctx, grayRelease, model.ODelete))
+
+	return NewConfigFileGrayReleaseResponse(apimodel.Code_ExecuteSuccess, updatedRelease, nil)
+}
+
+// getReleaseAndGrayRelease 获取配置文件当前的全量发布以及灰度发布
+func (s *Server) getReleaseAndGrayRelease(ctx context.Context, namespace, group, fileName string) (
+	*model.ConfigFileRelease, *model.ConfigFileGrayRelease, *ConfigFileGrayReleaseResponse) {
+	tx := s.getTx(ctx)
+	release, err := s.storage.GetConfigFileRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file release error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return nil, nil, NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+	grayRelease, err := s.storage.GetConfigFileGrayRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file gray release error.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return nil, nil, NewConfigFileGrayReleaseResponse(apimodel.Code_StoreLayerException, nil, nil)
+	}
+	return release, grayRelease, nil
+}
+
+func checkConfigFileGrayParams(namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	if err := utils2.CheckFileName(utils.NewStringValue(fileName)); err != nil {
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_InvalidConfigFileName, nil, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_InvalidNamespaceName, nil, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return NewConfigFileGrayReleaseResponse(apimodel.Code_InvalidConfigFileGroupName, nil, nil)
+	}
+	return nil
+}
+
+func configFileRelease2GrayInfo(release *model.ConfigFileRelease) *ConfigFileGrayReleaseInfo {
+	if release == nil {
+		return nil
+	}
+	return &ConfigFileGrayReleaseInfo{
+		Name:       release.Name,
+		Namespace:  release.Namespace,
+		Group:      release.Group,
+		FileName:   release.FileName,
+		Content:    release.Content,
+		Comment:    release.Comment,
+		Md5:        release.Md5,
+		Version:    release.Version,
+		CreateBy:   release.CreateBy,
+		CreateTime: commontime.Time2String(release.CreateTime),
+		ModifyBy:   release.ModifyBy,
+		ModifyTime: commontime.Time2String(release.ModifyTime),
+	}
+}
+
+func configFileGrayRelease2Info(grayRelease *model.ConfigFileGrayRelease) *ConfigFileGrayReleaseInfo {
+	if grayRelease == nil {
+		return nil
+	}
+	rule, _ := model.ParseConfigFileGrayRule(grayRelease.Rule)
+	return &ConfigFileGrayReleaseInfo{
+		Name:       grayRelease.Name,
+		Namespace:  grayRelease.Namespace,
+		Group:      grayRelease.Group,
+		FileName:   grayRelease.FileName,
+		Content:    grayRelease.Content,
+		Comment:    grayRelease.Comment,
+		Md5:        grayRelease.Md5,
+		Version:    grayRelease.Version,
+		Rule:       rule,
+		CreateBy:   grayRelease.CreateBy,
+		CreateTime: commontime.Time2String(grayRelease.CreateTime),
+		ModifyBy:   grayRelease.ModifyBy,
+		ModifyTime: commontime.Time2String(grayRelease.ModifyTime),
+	}
+}
+
+// configFileGrayReleaseRecordEntry 生成灰度发布的操作记录
+func configFileGrayReleaseRecordEntry(ctx context.Context, grayRelease *model.ConfigFileGrayRelease,
+	operationType model.OperationType) *model.RecordEntry {
+	return &model.RecordEntry{
+		ResourceType:  model.RConfigFileGrayRelease,
+		ResourceName:  utils.GenFileId(grayRelease.Namespace, grayRelease.Group, grayRelease.FileName),
+		Namespace:     grayRelease.Namespace,
+		OperationType: operationType,
+		Operator:      utils.ParseOperator(ctx),
+		Detail:        grayRelease.Rule,
+		HappenTime:    time.Now(),
+	}
+}
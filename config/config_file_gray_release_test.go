@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"testing"
+
+	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func assembleGrayClientConfigFile(labels map[string]string) *apiconfig.ClientConfigFileInfo {
+	file := assembleDefaultClientConfigFile(0)[0]
+	for k, v := range labels {
+		file.Tags = append(file.Tags, &apiconfig.ConfigFileTag{
+			Key:   utils.NewStringValue(k),
+			Value: utils.NewStringValue(v),
+		})
+	}
+	return file
+}
+
+// TestConfigFileGrayRelease 测试灰度发布，只有命中灰度规则的客户端能获取到灰度内容
+func TestConfigFileGrayRelease(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	grayContent := "k1=v1,k2=gray"
+	configFile.Content = utils.NewStringValue(grayContent)
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	grayReq := &ConfigFileGrayReleaseRequest{
+		Namespace: testNamespace,
+		Group:     testGroup,
+		FileName:  testFile,
+		Rule: &model.ConfigFileGrayRule{
+			Labels: map[string]string{"env": "canary"},
+		},
+	}
+
+	t.Run("gray_publish_without_rule", func(t *testing.T) {
+		grayRsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, &ConfigFileGrayReleaseRequest{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  testFile,
+		})
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), grayRsp.Code)
+	})
+
+	t.Run("gray_publish", func(t *testing.T) {
+		grayRsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, grayReq)
+		assert.Equal(t, api.ExecuteSuccess, grayRsp.Code, grayRsp.Info)
+		assert.Equal(t, uint64(1), grayRsp.Release.Version)
+		assert.Equal(t, uint64(2), grayRsp.GrayRelease.Version)
+		assert.Equal(t, grayContent, grayRsp.GrayRelease.Content)
+
+		_, err := testSuit.testServer.fileCache.ReLoad(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+
+		// 命中灰度规则的客户端获取灰度内容
+		clientRsp := testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx,
+			assembleGrayClientConfigFile(map[string]string{"env": "canary"}))
+		assert.Equal(t, api.ExecuteSuccess, clientRsp.Code.GetValue())
+		assert.Equal(t, uint64(2), clientRsp.ConfigFile.Version.GetValue())
+		assert.Equal(t, grayContent, clientRsp.ConfigFile.Content.GetValue())
+
+		// 未命中灰度规则的客户端仍然获取全量发布的内容
+		clientRsp = testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx,
+			assembleGrayClientConfigFile(map[string]string{"env": "prod"}))
+		assert.Equal(t, api.ExecuteSuccess, clientRsp.Code.GetValue())
+		assert.Equal(t, uint64(1), clientRsp.ConfigFile.Version.GetValue())
+		assert.Equal(t, "k1=v1,k2=v2", clientRsp.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("publish_when_gray_in_progress", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, uint32(apimodel.Code_DataConflict), rsp.Code.GetValue())
+	})
+
+	t.Run("promote_gray_release", func(t *testing.T) {
+		grayRsp := testSuit.testService.PromoteConfigFileGrayRelease(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, grayRsp.Code, grayRsp.Info)
+		assert.Equal(t, uint64(2), grayRsp.Release.Version)
+		assert.Equal(t, grayContent, grayRsp.Release.Content)
+
+		grayRsp = testSuit.testService.GetConfigFileGrayRelease(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, grayRsp.Code)
+		assert.Nil(t, grayRsp.GrayRelease)
+
+		_, err := testSuit.testServer.fileCache.ReLoad(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		clientRsp := testSuit.testService.GetConfigFileForClient(testSuit.defaultCtx,
+			assembleGrayClientConfigFile(map[string]string{"env": "prod"}))
+		assert.Equal(t, api.ExecuteSuccess, clientRsp.Code.GetValue())
+		assert.Equal(t, uint64(2), clientRsp.ConfigFile.Version.GetValue())
+		assert.Equal(t, grayContent, clientRsp.ConfigFile.Content.GetValue())
+	})
+
+	t.Run("abandon_gray_release", func(t *testing.T) {
+		grayRsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, grayReq)
+		assert.Equal(t, api.ExecuteSuccess, grayRsp.Code, grayRsp.Info)
+		assert.Equal(t, uint64(3), grayRsp.GrayRelease.Version)
+
+		grayRsp = testSuit.testService.AbandonConfigFileGrayRelease(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, grayRsp.Code, grayRsp.Info)
+		// 放弃灰度后全量发布的版本号需要大于灰度版本号
+		assert.Equal(t, uint64(4), grayRsp.Release.Version)
+		assert.Equal(t, grayContent, grayRsp.Release.Content)
+
+		grayRsp = testSuit.testService.AbandonConfigFileGrayRelease(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile)
+		assert.Equal(t, uint32(apimodel.Code_NotFoundResource), grayRsp.Code)
+	})
+}
@@ -111,3 +111,35 @@ func (s *serverAuthability) UpdateConfigFileGroup(ctx context.Context,
 
 	return s.targetServer.UpdateConfigFileGroup(ctx, configFileGroup)
 }
+
+// UpdateConfigFileGroupSchema 设置配置文件组的 JSON Schema
+func (s *serverAuthability) UpdateConfigFileGroupSchema(ctx context.Context,
+	req *ConfigFileGroupSchema) *ConfigFileGroupSchemaResponse {
+	authCtx := s.collectConfigGroupAuthContext(ctx, []*apiconfig.ConfigFileGroup{{Name: utils.NewStringValue(req.Group),
+		Namespace: utils.NewStringValue(req.Namespace)}}, model.Modify, "UpdateConfigFileGroupSchema")
+
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileGroupSchemaResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.UpdateConfigFileGroupSchema(ctx, req)
+}
+
+// GetConfigFileGroupSchema 获取配置文件组的 JSON Schema
+func (s *serverAuthability) GetConfigFileGroupSchema(ctx context.Context,
+	namespace, group string) *ConfigFileGroupSchemaResponse {
+	authCtx := s.collectConfigGroupAuthContext(ctx, []*apiconfig.ConfigFileGroup{{Name: utils.NewStringValue(group),
+		Namespace: utils.NewStringValue(namespace)}}, model.Read, "GetConfigFileGroupSchema")
+
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileGroupSchemaResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+
+	return s.targetServer.GetConfigFileGroupSchema(ctx, namespace, group)
+}
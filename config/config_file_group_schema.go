@@ -0,0 +1,152 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"fmt"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+// ConfigFileGroupSchema 配置文件组上设置的 JSON Schema，组内 json、yaml、toml 格式的配置内容需要满足该约束
+type ConfigFileGroupSchema struct {
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	Schema    string `json:"schema"`
+}
+
+// ConfigFileGroupSchemaResponse 配置文件组 JSON Schema 操作结果
+type ConfigFileGroupSchemaResponse struct {
+	Code   uint32                 `json:"code"`
+	Info   string                 `json:"info"`
+	Schema *ConfigFileGroupSchema `json:"schema,omitempty"`
+}
+
+// NewConfigFileGroupSchemaResponse 创建配置文件组 JSON Schema 操作结果
+func NewConfigFileGroupSchemaResponse(code apimodel.Code, schema *ConfigFileGroupSchema) *ConfigFileGroupSchemaResponse {
+	return &ConfigFileGroupSchemaResponse{
+		Code:   uint32(code),
+		Info:   api.Code2Info(uint32(code)),
+		Schema: schema,
+	}
+}
+
+// NewConfigFileGroupSchemaResponseWithMessage 创建带有错误信息的配置文件组 JSON Schema 操作结果
+func NewConfigFileGroupSchemaResponseWithMessage(code apimodel.Code, message string) *ConfigFileGroupSchemaResponse {
+	return &ConfigFileGroupSchemaResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + message,
+	}
+}
+
+// UpdateConfigFileGroupSchema 设置配置文件组的 JSON Schema，schema 为空时清除
+func (s *Server) UpdateConfigFileGroupSchema(ctx context.Context,
+	req *ConfigFileGroupSchema) *ConfigFileGroupSchemaResponse {
+	if err := utils2.CheckResourceName(utils.NewStringValue(req.Namespace)); err != nil {
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_InvalidNamespaceName, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(req.Group)); err != nil {
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_InvalidConfigFileGroupName, nil)
+	}
+	if req.Schema != "" {
+		if _, err := utils2.ParseJsonSchema(req.Schema); err != nil {
+			return NewConfigFileGroupSchemaResponseWithMessage(apimodel.Code_BadRequest, err.Error())
+		}
+	}
+
+	fileGroup, err := s.storage.GetConfigFileGroup(req.Namespace, req.Group)
+	if err != nil {
+		log.Error("[Config][Service] get config file group failed. ", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(req.Namespace), utils.ZapGroup(req.Group), zap.Error(err))
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if fileGroup == nil {
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_NotFoundResource, nil)
+	}
+
+	if err := s.storage.UpdateConfigFileGroupSchema(req.Namespace, req.Group, req.Schema,
+		utils.ParseUserName(ctx)); err != nil {
+		log.Error("[Config][Service] update config file group schema failed. ", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(req.Namespace), utils.ZapGroup(req.Group), zap.Error(err))
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_StoreLayerException, nil)
+	}
+
+	s.RecordHistory(ctx, configGroupRecordEntry(ctx, configFileGroup2Api(fileGroup), fileGroup, model.OUpdate))
+
+	return NewConfigFileGroupSchemaResponse(apimodel.Code_ExecuteSuccess, req)
+}
+
+// GetConfigFileGroupSchema 查询配置文件组的 JSON Schema
+func (s *Server) GetConfigFileGroupSchema(ctx context.Context,
+	namespace, group string) *ConfigFileGroupSchemaResponse {
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_InvalidNamespaceName, nil)
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_InvalidConfigFileGroupName, nil)
+	}
+
+	fileGroup, err := s.storage.GetConfigFileGroup(namespace, group)
+	if err != nil {
+		log.Error("[Config][Service] get config file group failed. ", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), zap.Error(err))
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if fileGroup == nil {
+		return NewConfigFileGroupSchemaResponse(apimodel.Code_NotFoundResource, nil)
+	}
+	return NewConfigFileGroupSchemaResponse(apimodel.Code_ExecuteSuccess, &ConfigFileGroupSchema{
+		Namespace: namespace,
+		Group:     group,
+		Schema:    fileGroup.JsonSchema,
+	})
+}
+
+// checkConfigFileContent 按照配置文件格式校验配置内容，配置文件组设置了 JSON Schema 时同时校验是否满足约束
+func (s *Server) checkConfigFileContent(ctx context.Context,
+	namespace, group, format, content string) (apimodel.Code, error) {
+	if err := utils2.CheckContentFormat(format, content); err != nil {
+		return apimodel.Code_InvalidConfigFileFormat, err
+	}
+	if !utils2.IsStructuredFormat(format) {
+		return apimodel.Code_ExecuteSuccess, nil
+	}
+
+	fileGroup, err := s.storage.GetConfigFileGroup(namespace, group)
+	if err != nil {
+		log.Error("[Config][Service] get config file group failed when check content. ",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group), zap.Error(err))
+		return apimodel.Code_StoreLayerException, err
+	}
+	if fileGroup == nil || fileGroup.JsonSchema == "" {
+		return apimodel.Code_ExecuteSuccess, nil
+	}
+	// 内容格式正确但不满足配置文件组约束时，与格式错误区分开，便于调用方定位问题
+	if err := utils2.ValidateContentBySchema(fileGroup.JsonSchema, format, content); err != nil {
+		return apimodel.Code_InvalidParameter, fmt.Errorf("content does not match json schema of group %s: %w",
+			group, err)
+	}
+	return apimodel.Code_ExecuteSuccess, nil
+}
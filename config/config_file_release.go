@@ -20,6 +20,7 @@ package config
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
@@ -83,6 +84,50 @@ func (s *Server) PublishConfigFile(
 		return api.NewConfigFileResponse(apimodel.Code_NotFoundResource, nil)
 	}
 
+	// 灰度发布进行中，需要先全量发布灰度内容或者放弃灰度
+	grayRelease, err := s.storage.GetConfigFileGrayRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file gray release error.",
+			utils.ZapRequestID(requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if grayRelease != nil {
+		return api.NewConfigFileResponseWithMessage(apimodel.Code_DataConflict,
+			"gray release is in progress, promote or abandon it first")
+	}
+
+	// 审批通过的发布申请发布的是申请时保存的配置内容
+	approvedRequest, _ := ctx.Value(contextApprovedReleaseRequestKey).(*model.ConfigFileReleaseRequest)
+	if approvedRequest != nil {
+		toPublishFile.Content = approvedRequest.Content
+	}
+
+	// 加密的配置在保存时已经校验过明文内容，发布时只校验未加密的配置
+	_, dataKey, err := s.getEncryptAlgorithmAndDataKey(ctx, namespace, group, fileName)
+	if err != nil {
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if dataKey == "" {
+		if code, err := s.checkConfigFileContent(ctx, namespace, group, toPublishFile.Format,
+			toPublishFile.Content); err != nil {
+			s.recordReleaseFail(ctx, transferConfigFileReleaseAPIModel2StoreModel(configFileRelease))
+			return api.NewConfigFileResponseWithMessage(code, err.Error())
+		}
+	}
+
+	// 命中审批策略时只创建发布申请，审批通过后再发布
+	policy, err := s.releaseApprovalPolicy(ctx, namespace, group, fileName)
+	if err != nil {
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if policy != nil {
+		return s.createConfigFileReleaseRequest(ctx, policy, configFileRelease, toPublishFile)
+	}
+
 	md5 := utils2.CalMd5(toPublishFile.Content)
 
 	// 获取 configFileRelease 信息
@@ -272,6 +317,17 @@ func (s *Server) DeleteConfigFileRelease(ctx context.Context, namespace,
 		}
 	}
 
+	// 配置文件的发布被删除，进行中的灰度发布同时结束
+	if err := s.storage.DeleteConfigFileGrayRelease(s.getTx(ctx), namespace, group, fileName, deleteBy); err != nil {
+		log.Error("[Config][Service] delete config file gray release error when delete release.",
+			utils.ZapRequestID(requestID),
+			zap.String("namespace", namespace),
+			zap.String("group", group),
+			zap.String("fileName", fileName),
+			zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+
 	err := s.storage.DeleteConfigFileRelease(s.getTx(ctx), namespace, group, fileName, deleteBy)
 
 	if err != nil {
@@ -311,6 +367,148 @@ func (s *Server) DeleteConfigFileRelease(ctx context.Context, namespace,
 	return api.NewConfigFileReleaseResponse(apimodel.Code_ExecuteSuccess, nil)
 }
 
+// contextRollbackHistoryKey 回滚时通过该 key 在上下文中携带回滚到的历史发布 ID，记录到发布申请以及发布历史中
+const contextRollbackHistoryKey = utils.StringContext("Config-Rollback-History")
+
+// ConfigFileReleaseRollbackRequest 配置文件回滚请求
+type ConfigFileReleaseRollbackRequest struct {
+	Namespace string `json:"namespace"`
+	Group     string `json:"group"`
+	FileName  string `json:"file_name"`
+	HistoryId uint64 `json:"history_id"`
+	// Comment 回滚备注，为空时使用回滚的历史发布信息
+	Comment string `json:"comment"`
+}
+
+// RollbackConfigFileRelease 将配置文件回滚到指定的历史发布，回滚会以历史发布的内容重新发布一个新版本
+func (s *Server) RollbackConfigFileRelease(ctx context.Context,
+	req *ConfigFileReleaseRollbackRequest) *apiconfig.ConfigResponse {
+	namespace, group, fileName, historyId := req.Namespace, req.Group, req.FileName, req.HistoryId
+
+	if err := utils2.CheckFileName(utils.NewStringValue(fileName)); err != nil {
+		return api.NewConfigFileResponse(apimodel.Code_InvalidConfigFileName, nil)
+	}
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return api.NewConfigFileResponse(apimodel.Code_InvalidNamespaceName, nil)
+	}
+
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return api.NewConfigFileResponse(apimodel.Code_InvalidConfigFileGroupName, nil)
+	}
+
+	if historyId == 0 {
+		return api.NewConfigFileResponseWithMessage(apimodel.Code_BadRequest, "history id is required")
+	}
+
+	history, err := s.storage.GetConfigFileReleaseHistory(historyId)
+	if err != nil {
+		log.Error("[Config][Service] get config file release history error when rollback.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Uint64("historyId", historyId), zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	// 只能回滚到当前配置文件发布成功的历史版本
+	if history == nil || history.Namespace != namespace || history.Group != group || history.FileName != fileName {
+		return api.NewConfigFileResponseWithMessage(apimodel.Code_NotFoundResource, "release history not found")
+	}
+	if history.Type == utils.ReleaseTypeDelete || history.Status != utils.ReleaseStatusSuccess {
+		return api.NewConfigFileResponseWithMessage(apimodel.Code_BadRequest,
+			"only successful release history can be rolled back")
+	}
+	comment := req.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("rollback to release history %d(%s)", history.Id, history.Name)
+	}
+	ctx = context.WithValue(ctx, contextRollbackHistoryKey, history.Id)
+
+	tx := s.getTx(ctx)
+	toPublishFile, err := s.storage.GetConfigFile(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file error when rollback.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if toPublishFile == nil {
+		return api.NewConfigFileResponse(apimodel.Code_NotFoundResource, nil)
+	}
+
+	grayRelease, err := s.storage.GetConfigFileGrayRelease(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file gray release error when rollback.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if grayRelease != nil {
+		return api.NewConfigFileResponseWithMessage(apimodel.Code_DataConflict,
+			"gray release is in progress, promote or abandon it first")
+	}
+
+	managedFileRelease, err := s.storage.GetConfigFileReleaseWithAllFlag(tx, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file release error when rollback.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if managedFileRelease == nil {
+		return api.NewConfigFileResponse(apimodel.Code_NotFoundResource, nil)
+	}
+
+	// 回滚同样会发布到客户端，命中审批策略时以回滚的目标内容创建发布申请
+	policy, err := s.releaseApprovalPolicy(ctx, namespace, group, fileName)
+	if err != nil {
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+	if policy != nil {
+		rollbackFile := *toPublishFile
+		rollbackFile.Content = history.Content
+		return s.createConfigFileReleaseRequest(ctx, policy, &apiconfig.ConfigFileRelease{
+			Name:    utils.NewStringValue(utils2.GenReleaseName(managedFileRelease.Name, fileName)),
+			Comment: utils.NewStringValue(comment),
+		}, &rollbackFile)
+	}
+
+	// 版本号继续递增，客户端通过版本号变化感知到回滚
+	userName := utils.ParseUserName(ctx)
+	fileRelease := &model.ConfigFileRelease{
+		Name:      utils2.GenReleaseName(managedFileRelease.Name, fileName),
+		Namespace: namespace,
+		Group:     group,
+		FileName:  fileName,
+		Content:   history.Content,
+		Comment:   comment,
+		Md5:       history.Md5,
+		Version:   managedFileRelease.Version + 1,
+		ModifyBy:  userName,
+	}
+	if fileRelease.Md5 == "" {
+		fileRelease.Md5 = utils2.CalMd5(fileRelease.Content)
+	}
+
+	updatedFileRelease, err := s.storage.UpdateConfigFileRelease(tx, fileRelease)
+	if err != nil {
+		log.Error("[Config][Service] update config file release error when rollback.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		s.recordReleaseHistory(ctx, fileRelease, utils.ReleaseTypeRollback, utils.ReleaseStatusFail)
+		return api.NewConfigFileResponse(apimodel.Code_StoreLayerException, nil)
+	}
+
+	s.recordReleaseHistory(ctx, updatedFileRelease, utils.ReleaseTypeRollback, utils.ReleaseStatusSuccess)
+	s.RecordHistory(ctx, configFileReleaseRecordEntry(ctx, &apiconfig.ConfigFileRelease{
+		Namespace: utils.NewStringValue(namespace),
+		Name:      utils.NewStringValue(updatedFileRelease.Name),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+		Comment:   utils.NewStringValue(updatedFileRelease.Comment),
+	}, updatedFileRelease, model.OUpdate))
+
+	return api.NewConfigFileReleaseResponse(apimodel.Code_ExecuteSuccess, configFileRelease2Api(updatedFileRelease))
+}
+
 func (s *Server) recordReleaseFail(ctx context.Context, configFileRelease *model.ConfigFileRelease) {
 	s.recordReleaseHistory(ctx, configFileRelease, utils.ReleaseTypeNormal, utils.ReleaseStatusFail)
 }
@@ -84,3 +84,120 @@ func (s *serverAuthability) DeleteConfigFileRelease(ctx context.Context, namespa
 
 	return s.targetServer.DeleteConfigFileRelease(ctx, namespace, group, fileName, deleteBy)
 }
+
+// RollbackConfigFileRelease 回滚配置文件发布，与发布配置文件所需的权限一致
+func (s *serverAuthability) RollbackConfigFileRelease(ctx context.Context,
+	req *ConfigFileReleaseRollbackRequest) *apiconfig.ConfigResponse {
+	configFileRelease := &apiconfig.ConfigFileRelease{
+		Namespace: utils.NewStringValue(req.Namespace),
+		Group:     utils.NewStringValue(req.Group),
+		FileName:  utils.NewStringValue(req.FileName),
+	}
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{configFileRelease}, model.Create, "RollbackConfigFileRelease")
+
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return api.NewConfigFileResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return s.targetServer.RollbackConfigFileRelease(ctx, req)
+}
+
+// PublishConfigFileGray 灰度发布配置文件
+func (s *serverAuthability) PublishConfigFileGray(ctx context.Context,
+	req *ConfigFileGrayReleaseRequest) *ConfigFileGrayReleaseResponse {
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{grayReleaseAuthResource(req.Namespace, req.Group, req.FileName)},
+		model.Create, "PublishConfigFileGray")
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return s.targetServer.PublishConfigFileGray(ctx, req)
+}
+
+// GetConfigFileGrayRelease 获取配置文件的全量发布以及灰度发布
+func (s *serverAuthability) GetConfigFileGrayRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{grayReleaseAuthResource(namespace, group, fileName)},
+		model.Read, "GetConfigFileGrayRelease")
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return s.targetServer.GetConfigFileGrayRelease(ctx, namespace, group, fileName)
+}
+
+// PromoteConfigFileGrayRelease 将灰度发布全量发布
+func (s *serverAuthability) PromoteConfigFileGrayRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{grayReleaseAuthResource(namespace, group, fileName)},
+		model.Modify, "PromoteConfigFileGrayRelease")
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return s.targetServer.PromoteConfigFileGrayRelease(ctx, namespace, group, fileName)
+}
+
+// AbandonConfigFileGrayRelease 放弃灰度发布
+func (s *serverAuthability) AbandonConfigFileGrayRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileGrayReleaseResponse {
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{grayReleaseAuthResource(namespace, group, fileName)},
+		model.Delete, "AbandonConfigFileGrayRelease")
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileGrayReleaseResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return s.targetServer.AbandonConfigFileGrayRelease(ctx, namespace, group, fileName)
+}
+
+func grayReleaseAuthResource(namespace, group, fileName string) *apiconfig.ConfigFileRelease {
+	return &apiconfig.ConfigFileRelease{
+		Namespace: utils.NewStringValue(namespace),
+		Group:     utils.NewStringValue(group),
+		FileName:  utils.NewStringValue(fileName),
+	}
+}
+
+// DiffConfigFileRelease 对比配置文件当前生效的发布与草稿
+func (s *serverAuthability) DiffConfigFileRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileDiffResponse {
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{grayReleaseAuthResource(namespace, group, fileName)},
+		model.Read, "DiffConfigFileRelease")
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileDiffResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return s.targetServer.DiffConfigFileRelease(ctx, namespace, group, fileName)
+}
+
+// DiffConfigFileReleaseHistory 对比配置文件的两个发布历史
+func (s *serverAuthability) DiffConfigFileReleaseHistory(ctx context.Context,
+	namespace, group, fileName string, fromId, toId uint64) *ConfigFileDiffResponse {
+	authCtx := s.collectConfigFileReleaseAuthContext(ctx,
+		[]*apiconfig.ConfigFileRelease{grayReleaseAuthResource(namespace, group, fileName)},
+		model.Read, "DiffConfigFileReleaseHistory")
+	if _, err := s.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewConfigFileDiffResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return s.targetServer.DiffConfigFileReleaseHistory(ctx, namespace, group, fileName, fromId, toId)
+}
@@ -0,0 +1,234 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"encoding/base64"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+const (
+	// DiffVersionDraft 配置文件当前的草稿
+	DiffVersionDraft = "draft"
+	// DiffVersionRelease 配置文件当前生效的发布
+	DiffVersionRelease = "release"
+	// DiffVersionHistory 配置文件的发布历史
+	DiffVersionHistory = "history"
+)
+
+// ConfigFileDiffVersion 参与对比的配置版本
+type ConfigFileDiffVersion struct {
+	Type    string `json:"type"`
+	Id      uint64 `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Version uint64 `json:"version,omitempty"`
+}
+
+// ConfigFileDiffResponse 配置对比结果，Mode 为 line 时对比结果在 Lines 中，为 key 时在 Keys 中
+type ConfigFileDiffResponse struct {
+	Code      uint32                 `json:"code"`
+	Info      string                 `json:"info"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Group     string                 `json:"group,omitempty"`
+	FileName  string                 `json:"file_name,omitempty"`
+	Format    string                 `json:"format,omitempty"`
+	From      *ConfigFileDiffVersion `json:"from,omitempty"`
+	To        *ConfigFileDiffVersion `json:"to,omitempty"`
+	Mode      string                 `json:"mode,omitempty"`
+	Lines     []*utils2.LineDiff     `json:"lines,omitempty"`
+	Keys      []*utils2.KeyDiff      `json:"keys,omitempty"`
+}
+
+// NewConfigFileDiffResponse 创建配置对比结果
+func NewConfigFileDiffResponse(code apimodel.Code) *ConfigFileDiffResponse {
+	return &ConfigFileDiffResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)),
+	}
+}
+
+// NewConfigFileDiffResponseWithMessage 创建带有错误信息的配置对比结果
+func NewConfigFileDiffResponseWithMessage(code apimodel.Code, message string) *ConfigFileDiffResponse {
+	return &ConfigFileDiffResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + message,
+	}
+}
+
+// DiffConfigFileRelease 对比配置文件当前生效的发布与草稿，from 为发布内容，to 为草稿内容
+func (s *Server) DiffConfigFileRelease(ctx context.Context,
+	namespace, group, fileName string) *ConfigFileDiffResponse {
+	if code := checkDiffConfigFileParams(namespace, group, fileName); code != apimodel.Code_ExecuteSuccess {
+		return NewConfigFileDiffResponse(code)
+	}
+
+	file, err := s.storage.GetConfigFile(nil, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file error when diff release.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileDiffResponse(apimodel.Code_StoreLayerException)
+	}
+	if file == nil {
+		return NewConfigFileDiffResponseWithMessage(apimodel.Code_NotFoundResource, "config file not found")
+	}
+	release, err := s.storage.GetConfigFileRelease(nil, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file release error when diff release.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileDiffResponse(apimodel.Code_StoreLayerException)
+	}
+
+	// 未发布过的配置文件，草稿内容全部视为新增
+	fromContent := ""
+	from := &ConfigFileDiffVersion{Type: DiffVersionRelease}
+	if release != nil {
+		fromContent = release.Content
+		from.Id, from.Name, from.Version = release.Id, release.Name, release.Version
+	}
+	toContent := file.Content
+	if err := s.decryptDiffContents(ctx, file, &fromContent, &toContent); err != nil {
+		log.Error("[Config][Service] decrypt config file error when diff release.", utils.ZapRequestIDByCtx(ctx),
+			utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileDiffResponse(apimodel.Code_DecryptConfigFileException)
+	}
+
+	ret := newConfigFileDiffResult(namespace, group, fileName, file.Format, fromContent, toContent)
+	ret.From = from
+	ret.To = &ConfigFileDiffVersion{Type: DiffVersionDraft, Id: file.Id, Name: file.Name}
+	return ret
+}
+
+// DiffConfigFileReleaseHistory 对比配置文件的两个发布历史
+func (s *Server) DiffConfigFileReleaseHistory(ctx context.Context,
+	namespace, group, fileName string, fromId, toId uint64) *ConfigFileDiffResponse {
+	if code := checkDiffConfigFileParams(namespace, group, fileName); code != apimodel.Code_ExecuteSuccess {
+		return NewConfigFileDiffResponse(code)
+	}
+	if fromId == 0 || toId == 0 {
+		return NewConfigFileDiffResponseWithMessage(apimodel.Code_BadRequest, "fromId and toId are required")
+	}
+
+	histories := make([]*model.ConfigFileReleaseHistory, 0, 2)
+	for _, id := range []uint64{fromId, toId} {
+		history, err := s.storage.GetConfigFileReleaseHistory(id)
+		if err != nil {
+			log.Error("[Config][Service] get config file release history error when diff.",
+				utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+				utils.ZapFileName(fileName), zap.Uint64("historyId", id), zap.Error(err))
+			return NewConfigFileDiffResponse(apimodel.Code_StoreLayerException)
+		}
+		// 只能对比同一个配置文件的发布历史
+		if history == nil || history.Namespace != namespace || history.Group != group || history.FileName != fileName {
+			return NewConfigFileDiffResponseWithMessage(apimodel.Code_NotFoundResource, "release history not found")
+		}
+		histories = append(histories, history)
+	}
+	fromHistory, toHistory := histories[0], histories[1]
+
+	file, err := s.storage.GetConfigFile(nil, namespace, group, fileName)
+	if err != nil {
+		log.Error("[Config][Service] get config file error when diff release history.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileDiffResponse(apimodel.Code_StoreLayerException)
+	}
+	fromContent, toContent := fromHistory.Content, toHistory.Content
+	if err := s.decryptDiffContents(ctx, file, &fromContent, &toContent); err != nil {
+		log.Error("[Config][Service] decrypt config file error when diff release history.",
+			utils.ZapRequestIDByCtx(ctx), utils.ZapNamespace(namespace), utils.ZapGroup(group),
+			utils.ZapFileName(fileName), zap.Error(err))
+		return NewConfigFileDiffResponse(apimodel.Code_DecryptConfigFileException)
+	}
+
+	format := toHistory.Format
+	if format == "" && file != nil {
+		format = file.Format
+	}
+	ret := newConfigFileDiffResult(namespace, group, fileName, format, fromContent, toContent)
+	ret.From = &ConfigFileDiffVersion{Type: DiffVersionHistory, Id: fromHistory.Id, Name: fromHistory.Name}
+	ret.To = &ConfigFileDiffVersion{Type: DiffVersionHistory, Id: toHistory.Id, Name: toHistory.Name}
+	return ret
+}
+
+func checkDiffConfigFileParams(namespace, group, fileName string) apimodel.Code {
+	if err := utils2.CheckResourceName(utils.NewStringValue(namespace)); err != nil {
+		return apimodel.Code_InvalidNamespaceName
+	}
+	if err := utils2.CheckResourceName(utils.NewStringValue(group)); err != nil {
+		return apimodel.Code_InvalidConfigFileGroupName
+	}
+	if err := utils2.CheckFileName(utils.NewStringValue(fileName)); err != nil {
+		return apimodel.Code_InvalidConfigFileName
+	}
+	return apimodel.Code_ExecuteSuccess
+}
+
+// newConfigFileDiffResult 结构化的配置优先按配置项对比，内容无法解析时退化为按行对比
+func newConfigFileDiffResult(namespace, group, fileName, format, from, to string) *ConfigFileDiffResponse {
+	ret := NewConfigFileDiffResponse(apimodel.Code_ExecuteSuccess)
+	ret.Namespace, ret.Group, ret.FileName, ret.Format = namespace, group, fileName, format
+	if utils2.SupportKeyDiff(format) {
+		if keys, err := utils2.DiffKeys(format, from, to); err == nil {
+			ret.Mode, ret.Keys = utils2.DiffModeKey, keys
+			return ret
+		}
+	}
+	ret.Mode, ret.Lines = utils2.DiffModeLine, utils2.DiffLines(from, to)
+	return ret
+}
+
+// decryptDiffContents 加密的配置文件只有创建人能够看到明文的对比结果，配置文件已删除时不解密
+func (s *Server) decryptDiffContents(ctx context.Context, file *model.ConfigFile, contents ...*string) error {
+	if s.cryptoManager == nil || file == nil || utils.ParseUserName(ctx) != file.CreateBy {
+		return nil
+	}
+	algorithm, dataKey, err := s.getEncryptAlgorithmAndDataKey(ctx, file.Namespace, file.Group, file.Name)
+	if err != nil {
+		return err
+	}
+	if dataKey == "" {
+		return nil
+	}
+	dataKeyBytes, err := base64.StdEncoding.DecodeString(dataKey)
+	if err != nil {
+		return err
+	}
+	crypto, err := s.cryptoManager.GetCrypto(algorithm)
+	if err != nil {
+		return err
+	}
+	for _, content := range contents {
+		if *content == "" {
+			continue
+		}
+		plainContent, err := crypto.Decrypt(*content, dataKeyBytes)
+		if err != nil {
+			return err
+		}
+		*content = plainContent
+	}
+	return nil
+}
@@ -26,6 +26,7 @@ import (
 	"go.uber.org/zap"
 
 	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/eventhub"
 	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/common/time"
 	"github.com/polarismesh/polaris/common/utils"
@@ -55,6 +56,11 @@ func (s *Server) recordReleaseHistory(ctx context.Context, fileRelease *model.Co
 			filterTags = append(filterTags, tag)
 		}
 	}
+	// 审批通过的回滚申请按照正常发布的流程发布，同样记录为回滚
+	sourceHistoryId := releaseSourceHistoryId(ctx)
+	if sourceHistoryId != 0 && releaseType == utils.ReleaseTypeNormal {
+		releaseType = utils.ReleaseTypeRollback
+	}
 	releaseHistory := &model.ConfigFileReleaseHistory{
 		Name:      fileRelease.Name,
 		Namespace: namespace,
@@ -67,8 +73,11 @@ func (s *Server) recordReleaseHistory(ctx context.Context, fileRelease *model.Co
 		Md5:       fileRelease.Md5,
 		Type:      releaseType,
 		Status:    status,
+		Approvers: releaseApprovers(ctx),
 		CreateBy:  fileRelease.ModifyBy,
 		ModifyBy:  fileRelease.ModifyBy,
+
+		SourceHistoryId: sourceHistoryId,
 	}
 
 	err := s.storage.CreateConfigFileReleaseHistory(s.getTx(ctx), releaseHistory)
@@ -81,6 +90,18 @@ func (s *Server) recordReleaseHistory(ctx context.Context, fileRelease *model.Co
 			zap.String("fileName", fileRelease.FileName),
 			zap.Error(err))
 	}
+
+	if status == utils.ReleaseStatusSuccess {
+		eventhub.Publish(eventhub.ConfigFilePublishTopic, &model.ConfigFilePublishEvent{
+			Namespace:   namespace,
+			Group:       group,
+			FileName:    fileName,
+			ReleaseName: fileRelease.Name,
+			ReleaseType: releaseType,
+			Md5:         fileRelease.Md5,
+			Operator:    fileRelease.ModifyBy,
+		})
+	}
 }
 
 // GetConfigFileReleaseHistory 获取配置文件发布历史记录
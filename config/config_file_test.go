@@ -32,6 +32,7 @@ import (
 	api "github.com/polarismesh/polaris/common/api/v1"
 	"github.com/polarismesh/polaris/common/model"
 	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
 	"github.com/polarismesh/polaris/plugin/crypto/aes"
 	storemock "github.com/polarismesh/polaris/store/mock"
 	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
@@ -496,6 +497,491 @@ func TestPublishConfigFile(t *testing.T) {
 
 }
 
+// TestRollbackConfigFileRelease 测试回滚配置文件到历史发布
+func TestRollbackConfigFileRelease(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	firstContent := configFile.Content.GetValue()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	configFile.Content = utils.NewStringValue("k3=v3")
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+	assert.Equal(t, uint64(2), rsp.ConfigFileRelease.Version.GetValue())
+
+	historyRsp := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx,
+		testNamespace, testGroup, testFile, 0, 10, 0)
+	assert.Equal(t, api.ExecuteSuccess, historyRsp.Code.GetValue())
+	assert.Equal(t, 2, len(historyRsp.ConfigFileReleaseHistories))
+	// 发布历史按照 ID 倒序，最后一条为第一次发布
+	firstHistory := historyRsp.ConfigFileReleaseHistories[1]
+	assert.Equal(t, firstContent, firstHistory.Content.GetValue())
+
+	t.Run("rollback_history_not_exist", func(t *testing.T) {
+		rsp := testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, &ConfigFileReleaseRollbackRequest{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  testFile,
+			HistoryId: firstHistory.Id.GetValue() + 100,
+		})
+		assert.Equal(t, uint32(apimodel.Code_NotFoundResource), rsp.Code.GetValue())
+	})
+
+	t.Run("rollback_other_file_history", func(t *testing.T) {
+		rsp := testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, &ConfigFileReleaseRollbackRequest{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  "other_file",
+			HistoryId: firstHistory.Id.GetValue(),
+		})
+		assert.Equal(t, uint32(apimodel.Code_NotFoundResource), rsp.Code.GetValue())
+	})
+
+	t.Run("rollback_success", func(t *testing.T) {
+		rsp := testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, &ConfigFileReleaseRollbackRequest{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  testFile,
+			HistoryId: firstHistory.Id.GetValue(),
+			Comment:   "rollback bad release",
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+		assert.Equal(t, uint64(3), rsp.ConfigFileRelease.Version.GetValue())
+		assert.Equal(t, firstContent, rsp.ConfigFileRelease.Content.GetValue())
+
+		releaseRsp := testSuit.testService.GetConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, releaseRsp.Code.GetValue())
+		assert.Equal(t, firstContent, releaseRsp.ConfigFileRelease.Content.GetValue())
+
+		// 回滚会记录一条回滚类型的发布历史，并关联回滚的来源
+		latestRsp := testSuit.testService.GetConfigFileLatestReleaseHistory(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, latestRsp.Code.GetValue())
+		assert.Equal(t, utils.ReleaseTypeRollback, latestRsp.ConfigFileReleaseHistory.Type.GetValue())
+		assert.Equal(t, utils.ReleaseStatusSuccess, latestRsp.ConfigFileReleaseHistory.Status.GetValue())
+		assert.Equal(t, firstContent, latestRsp.ConfigFileReleaseHistory.Content.GetValue())
+		assert.Equal(t, "rollback bad release", latestRsp.ConfigFileReleaseHistory.Comment.GetValue())
+		latest, err := testSuit.testServer.storage.GetLatestConfigFileReleaseHistory(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.Equal(t, firstHistory.Id.GetValue(), latest.SourceHistoryId)
+	})
+}
+
+// TestConfigFileContentValidate 测试按照配置格式以及配置分组的 JSON Schema 校验配置内容
+func TestConfigFileContentValidate(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	rsp := testSuit.testService.CreateConfigFileGroup(testSuit.defaultCtx, assembleConfigFileGroup())
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue())
+
+	t.Run("create_invalid_yaml", func(t *testing.T) {
+		configFile := assembleConfigFile()
+		configFile.Format = utils.NewStringValue(utils.FileFormatYaml)
+		configFile.Content = utils.NewStringValue("a: 1\nb:\n  c: 2\n d: 3\n")
+		rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, uint32(apimodel.Code_InvalidConfigFileFormat), rsp.Code.GetValue())
+		assert.Contains(t, rsp.Info.GetValue(), "line 3")
+	})
+
+	t.Run("set_invalid_schema", func(t *testing.T) {
+		rsp := testSuit.testService.UpdateConfigFileGroupSchema(testSuit.defaultCtx, &ConfigFileGroupSchema{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			Schema:    `{"type": "foo"}`,
+		})
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), rsp.Code)
+	})
+
+	t.Run("create_violate_schema", func(t *testing.T) {
+		rsp := testSuit.testService.UpdateConfigFileGroupSchema(testSuit.defaultCtx, &ConfigFileGroupSchema{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			Schema:    `{"type": "object", "required": ["port"]}`,
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code, rsp.Info)
+
+		getRsp := testSuit.testService.GetConfigFileGroupSchema(testSuit.defaultCtx, testNamespace, testGroup)
+		assert.Equal(t, api.ExecuteSuccess, getRsp.Code)
+		assert.Equal(t, `{"type": "object", "required": ["port"]}`, getRsp.Schema.Schema)
+
+		configFile := assembleConfigFile()
+		configFile.Format = utils.NewStringValue(utils.FileFormatYaml)
+		configFile.Content = utils.NewStringValue("name: polaris\n")
+		createRsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, uint32(apimodel.Code_InvalidParameter), createRsp.Code.GetValue())
+		assert.Contains(t, createRsp.Info.GetValue(), "json schema")
+
+		configFile.Content = utils.NewStringValue("port: 8080\n")
+		createRsp = testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, createRsp.Code.GetValue(), createRsp.Info.GetValue())
+
+		configFile.Content = utils.NewStringValue("name: polaris\n")
+		updateRsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, uint32(apimodel.Code_InvalidParameter), updateRsp.Code.GetValue())
+		assert.Contains(t, updateRsp.Info.GetValue(), "json schema")
+
+		publishRsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, publishRsp.Code.GetValue(), publishRsp.Info.GetValue())
+	})
+}
+
+// TestDiffConfigFileRelease 测试配置文件草稿与发布、发布历史之间的对比
+func TestDiffConfigFileRelease(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	configFile.Format = utils.NewStringValue(utils.FileFormatProperties)
+	configFile.Content = utils.NewStringValue("k1=v1\nk2=v2")
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+	t.Run("diff_without_release", func(t *testing.T) {
+		diffRsp := testSuit.testService.DiffConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, diffRsp.Code, diffRsp.Info)
+		assert.Equal(t, utils2.DiffModeKey, diffRsp.Mode)
+		assert.Equal(t, DiffVersionDraft, diffRsp.To.Type)
+		assert.Equal(t, 2, len(diffRsp.Keys))
+		for _, item := range diffRsp.Keys {
+			assert.Equal(t, utils2.DiffTypeAdd, item.Type)
+		}
+	})
+
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+	configFile.Content = utils.NewStringValue("k1=v1\nk2=v3\nk4=v4")
+	rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+	t.Run("diff_draft_and_release", func(t *testing.T) {
+		diffRsp := testSuit.testService.DiffConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, diffRsp.Code, diffRsp.Info)
+		assert.Equal(t, DiffVersionRelease, diffRsp.From.Type)
+		assert.Equal(t, uint64(1), diffRsp.From.Version)
+		assert.Equal(t, []*utils2.KeyDiff{
+			{Type: utils2.DiffTypeEqual, Key: "k1", FromValue: "v1", ToValue: "v1"},
+			{Type: utils2.DiffTypeModify, Key: "k2", FromValue: "v2", ToValue: "v3"},
+			{Type: utils2.DiffTypeAdd, Key: "k4", ToValue: "v4"},
+		}, diffRsp.Keys)
+	})
+
+	rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+	historyRsp := testSuit.testService.GetConfigFileReleaseHistory(testSuit.defaultCtx,
+		testNamespace, testGroup, testFile, 0, 10, 0)
+	assert.Equal(t, api.ExecuteSuccess, historyRsp.Code.GetValue())
+	assert.Equal(t, 2, len(historyRsp.ConfigFileReleaseHistories))
+	toId := historyRsp.ConfigFileReleaseHistories[0].Id.GetValue()
+	fromId := historyRsp.ConfigFileReleaseHistories[1].Id.GetValue()
+
+	t.Run("diff_release_history", func(t *testing.T) {
+		diffRsp := testSuit.testService.DiffConfigFileReleaseHistory(testSuit.defaultCtx,
+			testNamespace, testGroup, testFile, fromId, toId)
+		assert.Equal(t, api.ExecuteSuccess, diffRsp.Code, diffRsp.Info)
+		assert.Equal(t, fromId, diffRsp.From.Id)
+		assert.Equal(t, toId, diffRsp.To.Id)
+		assert.Equal(t, 3, len(diffRsp.Keys))
+	})
+
+	t.Run("diff_other_file_history", func(t *testing.T) {
+		diffRsp := testSuit.testService.DiffConfigFileReleaseHistory(testSuit.defaultCtx,
+			testNamespace, testGroup, "other_file", fromId, toId)
+		assert.Equal(t, uint32(apimodel.Code_NotFoundResource), diffRsp.Code)
+	})
+
+	t.Run("diff_text_by_line", func(t *testing.T) {
+		configFile.Format = utils.NewStringValue(utils.FileFormatText)
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+		diffRsp := testSuit.testService.DiffConfigFileRelease(testSuit.defaultCtx, testNamespace, testGroup, testFile)
+		assert.Equal(t, api.ExecuteSuccess, diffRsp.Code, diffRsp.Info)
+		assert.Equal(t, utils2.DiffModeLine, diffRsp.Mode)
+		assert.Equal(t, 3, len(diffRsp.Lines))
+		for _, item := range diffRsp.Lines {
+			assert.Equal(t, utils2.DiffTypeEqual, item.Type)
+		}
+	})
+}
+
+func TestConfigFileReleaseApproval(t *testing.T) {
+	testSuit, err := newConfigCenterTest(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		if err := testSuit.clearTestData(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	configFile := assembleConfigFile()
+	rsp := testSuit.testService.CreateConfigFile(testSuit.defaultCtx, configFile)
+	assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+	policyRsp := testSuit.testService.UpdateConfigApprovalPolicy(testSuit.defaultCtx, &ConfigApprovalPolicy{
+		Namespace:         testNamespace,
+		Group:             testGroup,
+		RequiredApprovals: 3,
+		Users:             []string{"approver1", "approver2"},
+	})
+	assert.Equal(t, uint32(apimodel.Code_BadRequest), policyRsp.Code, policyRsp.Info)
+
+	policyRsp = testSuit.testService.UpdateConfigApprovalPolicy(testSuit.defaultCtx, &ConfigApprovalPolicy{
+		Namespace:         testNamespace,
+		Group:             testGroup,
+		RequiredApprovals: 2,
+		Users:             []string{"approver1", "approver2"},
+	})
+	assert.Equal(t, api.ExecuteSuccess, policyRsp.Code, policyRsp.Info)
+
+	policyRsp = testSuit.testService.GetConfigApprovalPolicy(testSuit.defaultCtx, testNamespace, testGroup)
+	assert.Equal(t, api.ExecuteSuccess, policyRsp.Code, policyRsp.Info)
+	assert.Equal(t, 2, policyRsp.Policy.RequiredApprovals)
+
+	approver1Ctx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "approver1")
+	approver2Ctx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "approver2")
+
+	pendingRequest := func(t *testing.T) *ConfigReleaseRequestInfo {
+		queryRsp := testSuit.testService.QueryConfigReleaseRequests(testSuit.defaultCtx, testNamespace, testGroup,
+			testFile, model.ReleaseRequestStatusPending, 0, 10)
+		assert.Equal(t, api.ExecuteSuccess, queryRsp.Code, queryRsp.Info)
+		assert.Equal(t, 1, len(queryRsp.Requests))
+		return queryRsp.Requests[0]
+	}
+	review := func(request *ConfigReleaseRequestInfo) *ConfigReleaseRequestReview {
+		return &ConfigReleaseRequestReview{
+			Id:        request.Id,
+			Namespace: request.Namespace,
+			Group:     request.Group,
+			FileName:  request.FileName,
+		}
+	}
+
+	t.Run("publish_need_approval", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+		history, err := testSuit.testServer.storage.GetLatestConfigFileReleaseHistory(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.Nil(t, history)
+
+		request := pendingRequest(t)
+		assert.Equal(t, configFile.Content.GetValue(), request.Content)
+		assert.Equal(t, "polaris", request.CreateBy)
+
+		// 同一个配置文件同时只能有一个待审批的发布申请
+		rsp = testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, uint32(apimodel.Code_DataConflict), rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+	})
+
+	t.Run("cancel_by_creator", func(t *testing.T) {
+		request := pendingRequest(t)
+		reviewRsp := testSuit.testServer.CancelConfigReleaseRequest(approver1Ctx, review(request))
+		assert.Equal(t, uint32(apimodel.Code_NotAllowedAccess), reviewRsp.Code, reviewRsp.Info)
+
+		reviewRsp = testSuit.testServer.CancelConfigReleaseRequest(testSuit.defaultCtx, review(request))
+		assert.Equal(t, api.ExecuteSuccess, reviewRsp.Code, reviewRsp.Info)
+		assert.Equal(t, model.ReleaseRequestStatusCanceled, reviewRsp.Request.Status)
+	})
+
+	t.Run("reject_by_approver", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+		request := pendingRequest(t)
+		reviewReq := review(request)
+		reviewReq.Reason = "not ready"
+		reviewRsp := testSuit.testServer.RejectConfigReleaseRequest(approver1Ctx, reviewReq)
+		assert.Equal(t, api.ExecuteSuccess, reviewRsp.Code, reviewRsp.Info)
+		assert.Equal(t, model.ReleaseRequestStatusRejected, reviewRsp.Request.Status)
+		assert.Equal(t, "not ready", reviewRsp.Request.Reason)
+
+		reviewRsp = testSuit.testServer.ApproveConfigReleaseRequest(approver2Ctx, reviewReq)
+		assert.Equal(t, uint32(apimodel.Code_DataConflict), reviewRsp.Code, reviewRsp.Info)
+	})
+
+	t.Run("approve_and_publish", func(t *testing.T) {
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+		request := pendingRequest(t)
+
+		// 发布申请内容以申请时为准，之后对配置文件的修改不影响审批后的发布
+		releaseContent := configFile.Content.GetValue()
+		configFile.Content = utils.NewStringValue("k3=v3")
+		rsp = testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+		reviewRsp := testSuit.testServer.ApproveConfigReleaseRequest(testSuit.defaultCtx, review(request))
+		assert.Equal(t, uint32(apimodel.Code_NotAllowedAccess), reviewRsp.Code, reviewRsp.Info)
+
+		otherCtx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "other")
+		reviewRsp = testSuit.testServer.ApproveConfigReleaseRequest(otherCtx, review(request))
+		assert.Equal(t, uint32(apimodel.Code_NotAllowedAccess), reviewRsp.Code, reviewRsp.Info)
+
+		reviewRsp = testSuit.testServer.ApproveConfigReleaseRequest(approver1Ctx, review(request))
+		assert.Equal(t, api.ExecuteSuccess, reviewRsp.Code, reviewRsp.Info)
+		assert.Equal(t, model.ReleaseRequestStatusPending, reviewRsp.Request.Status)
+
+		reviewRsp = testSuit.testServer.ApproveConfigReleaseRequest(approver1Ctx, review(request))
+		assert.Equal(t, uint32(apimodel.Code_DataConflict), reviewRsp.Code, reviewRsp.Info)
+
+		reviewRsp = testSuit.testServer.ApproveConfigReleaseRequest(approver2Ctx, review(request))
+		assert.Equal(t, api.ExecuteSuccess, reviewRsp.Code, reviewRsp.Info)
+		assert.Equal(t, model.ReleaseRequestStatusApproved, reviewRsp.Request.Status)
+		assert.Equal(t, []string{"approver1", "approver2"}, reviewRsp.Request.Approvers)
+
+		history, err := testSuit.testServer.storage.GetLatestConfigFileReleaseHistory(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.NotNil(t, history)
+		assert.Equal(t, releaseContent, history.Content)
+		assert.Equal(t, "polaris", history.CreateBy)
+		assert.Equal(t, "approver1,approver2", history.Approvers)
+	})
+
+	t.Run("approve_by_user_group", func(t *testing.T) {
+		err := testSuit.storage.AddUser(&model.User{
+			ID:     "group_approver_id",
+			Name:   "group_approver",
+			Owner:  "polaris",
+			Source: "Polaris",
+			Type:   model.SubAccountUserRole,
+			Valid:  true,
+		})
+		assert.NoError(t, err)
+		err = testSuit.storage.AddGroup(&model.UserGroupDetail{
+			UserGroup: &model.UserGroup{
+				ID:    "release_approvers_id",
+				Name:  "release_approvers",
+				Owner: "polaris",
+				Valid: true,
+			},
+			UserIds: map[string]struct{}{"group_approver_id": {}},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, testSuit.testServer.caches.TestUpdate())
+
+		policyRsp := testSuit.testService.UpdateConfigApprovalPolicy(testSuit.defaultCtx, &ConfigApprovalPolicy{
+			Namespace:         testNamespace,
+			Group:             testGroup,
+			RequiredApprovals: 1,
+			Users:             []string{"approver1"},
+			UserGroups:        []string{"release_approvers"},
+		})
+		assert.Equal(t, api.ExecuteSuccess, policyRsp.Code, policyRsp.Info)
+
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+		request := pendingRequest(t)
+
+		// 只有用户名而不属于审批用户组的用户不能审批
+		otherCtx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "other")
+		otherCtx = context.WithValue(otherCtx, utils.ContextUserIDKey, "other_id")
+		reviewRsp := testSuit.testServer.ApproveConfigReleaseRequest(otherCtx, review(request))
+		assert.Equal(t, uint32(apimodel.Code_NotAllowedAccess), reviewRsp.Code, reviewRsp.Info)
+
+		groupCtx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "group_approver")
+		groupCtx = context.WithValue(groupCtx, utils.ContextUserIDKey, "group_approver_id")
+		reviewRsp = testSuit.testServer.ApproveConfigReleaseRequest(groupCtx, review(request))
+		assert.Equal(t, api.ExecuteSuccess, reviewRsp.Code, reviewRsp.Info)
+		assert.Equal(t, model.ReleaseRequestStatusApproved, reviewRsp.Request.Status)
+	})
+
+	t.Run("rollback_and_gray_need_approval", func(t *testing.T) {
+		history, err := testSuit.testServer.storage.GetLatestConfigFileReleaseHistory(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.NotNil(t, history)
+
+		// 回滚只创建发布申请，申请内容为回滚的目标内容
+		configFile.Content = utils.NewStringValue("k4=v4")
+		rsp := testSuit.testService.UpdateConfigFile(testSuit.defaultCtx, configFile)
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+		rsp = testSuit.testService.RollbackConfigFileRelease(testSuit.defaultCtx, &ConfigFileReleaseRollbackRequest{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  testFile,
+			HistoryId: history.Id,
+		})
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+		request := pendingRequest(t)
+		assert.Equal(t, history.Content, request.Content)
+		assert.Equal(t, history.Id, request.SourceHistoryId)
+
+		latest, err := testSuit.testServer.storage.GetLatestConfigFileReleaseHistory(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.Equal(t, history.Id, latest.Id)
+
+		// 审批通过后同样记录为回滚，并关联回滚到的历史发布
+		groupCtx := context.WithValue(testSuit.defaultCtx, utils.ContextUserNameKey, "group_approver")
+		groupCtx = context.WithValue(groupCtx, utils.ContextUserIDKey, "group_approver_id")
+		reviewRsp := testSuit.testServer.ApproveConfigReleaseRequest(groupCtx, review(request))
+		assert.Equal(t, api.ExecuteSuccess, reviewRsp.Code, reviewRsp.Info)
+		latest, err = testSuit.testServer.storage.GetLatestConfigFileReleaseHistory(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.Equal(t, utils.ReleaseTypeRollback, latest.Type)
+		assert.Equal(t, history.Id, latest.SourceHistoryId)
+		assert.Equal(t, history.Content, latest.Content)
+
+		grayRsp := testSuit.testService.PublishConfigFileGray(testSuit.defaultCtx, &ConfigFileGrayReleaseRequest{
+			Namespace: testNamespace,
+			Group:     testGroup,
+			FileName:  testFile,
+			Rule: &model.ConfigFileGrayRule{
+				Labels: map[string]string{"env": "canary"},
+			},
+		})
+		assert.Equal(t, uint32(apimodel.Code_NotAllowedAccess), grayRsp.Code, grayRsp.Info)
+	})
+
+	t.Run("delete_policy", func(t *testing.T) {
+		policyRsp := testSuit.testService.DeleteConfigApprovalPolicy(testSuit.defaultCtx, testNamespace, testGroup)
+		assert.Equal(t, api.ExecuteSuccess, policyRsp.Code, policyRsp.Info)
+
+		rsp := testSuit.testService.PublishConfigFile(testSuit.defaultCtx, assembleConfigFileRelease(configFile))
+		assert.Equal(t, api.ExecuteSuccess, rsp.Code.GetValue(), rsp.GetInfo().GetValue())
+
+		history, err := testSuit.testServer.storage.GetLatestConfigFileReleaseHistory(testNamespace, testGroup, testFile)
+		assert.NoError(t, err)
+		assert.Equal(t, "k4=v4", history.Content)
+		assert.Equal(t, "", history.Approvers)
+	})
+}
+
 func TestServer_encryptConfigFile(t *testing.T) {
 	testSuit, err := newConfigCenterTest(t)
 	if err != nil {
@@ -65,8 +65,8 @@ func NewConfigConnManager(ctx context.Context, watchCenter *watchCenter) *connMa
 	return cm
 }
 
-func (c *connManager) AddConn(
-	clientId string, files []*apiconfig.ClientConfigFileInfo) chan *apiconfig.ConfigClientResponse {
+func (c *connManager) AddConn(clientId, clientIP string,
+	files []*apiconfig.ClientConfigFileInfo) chan *apiconfig.ConfigClientResponse {
 
 	finishChan := make(chan *apiconfig.ConfigClientResponse)
 
@@ -76,7 +76,7 @@ func (c *connManager) AddConn(
 		watchConfigFiles: files,
 	})
 
-	c.watchCenter.AddWatcher(clientId, files, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
+	c.watchCenter.AddWatcher(clientId, clientIP, files, func(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
 		connObj, ok := cm.conns.Load(clientId)
 		if ok {
 			conn := connObj.(*connection)
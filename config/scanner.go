@@ -40,6 +40,8 @@ type releaseMessageScanner struct {
 
 	lastScannerTime time.Time
 
+	lastGrayScannerTime time.Time
+
 	scanInterval time.Duration
 
 	fileCache cache.FileCache
@@ -69,6 +71,7 @@ func initReleaseMessageScanner(ctx context.Context, storage store.Store, fileCac
 func (s *releaseMessageScanner) scanAtFirstTime() error {
 	t := time.Now().Add(FirstScanTimeOffset)
 	s.lastScannerTime = t
+	s.lastGrayScannerTime = t
 
 	releases, err := s.storage.FindConfigFileReleaseByModifyTimeAfter(t)
 	if err != nil {
@@ -113,6 +116,15 @@ func (s *releaseMessageScanner) startScanTask(ctx context.Context) {
 			if err != nil {
 				log.Error("[Config][Scanner] handler release message error.", zap.Error(err))
 			}
+
+			// 全量发布处理完之后再处理灰度发布，灰度结束时全量发布的刷新会一并清理灰度缓存
+			grayScanIdx := s.lastGrayScannerTime.Add(DefaultScanTimeOffset)
+			grayReleases, err := s.storage.FindConfigFileGrayReleaseByModifyTimeAfter(grayScanIdx)
+			if err != nil {
+				log.Error("[Config][Scanner] scan config file gray release error.", zap.Error(err))
+				continue
+			}
+			s.handlerGrayReleases(grayReleases)
 		}
 	}
 }
@@ -167,6 +179,48 @@ func (s *releaseMessageScanner) handlerReleases(firstTime bool, releases []*mode
 	return nil
 }
 
+func (s *releaseMessageScanner) handlerGrayReleases(grayReleases []*model.ConfigFileGrayRelease) {
+	maxModifyTime := s.lastGrayScannerTime
+	newReleaseCnt := 0
+	for _, grayRelease := range grayReleases {
+		if grayRelease.ModifyTime.After(maxModifyTime) {
+			maxModifyTime = grayRelease.ModifyTime
+			newReleaseCnt++
+		}
+
+		entry, ok := s.fileCache.Get(grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+		if grayRelease.Flag == 1 {
+			// 灰度已经结束，清理残留的灰度缓存，客户端的通知由全量发布的变更触发
+			if ok && entry.Gray != nil && entry.Gray.Version <= grayRelease.Version {
+				_, _ = s.fileCache.ReLoad(grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+			}
+			continue
+		}
+
+		// 缓存中的灰度版本落后于数据库才处理，保证重复扫描到的消息能够幂等处理
+		if ok && !entry.Empty && entry.Gray != nil && grayRelease.Version <= entry.Gray.Version {
+			continue
+		}
+		_, _ = s.fileCache.ReLoad(grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+
+		if !isExpireTime(grayRelease.ModifyTime) {
+			s.eventCenter.handleEvent(Event{
+				EventType: eventTypePublishConfigFileGray,
+				Message:   grayRelease,
+			})
+		}
+	}
+	s.lastGrayScannerTime = maxModifyTime
+
+	if newReleaseCnt > 0 {
+		log.Info("[Config][Scanner] scan config file gray release count. ", zap.Int("count", len(grayReleases)))
+	}
+}
+
 func isExpireMessage(release *model.ConfigFileRelease) bool {
-	return release.ModifyTime.Before(time.Now().Add(MessageExpireTime))
+	return isExpireTime(release.ModifyTime)
+}
+
+func isExpireTime(modifyTime time.Time) bool {
+	return modifyTime.Before(time.Now().Add(MessageExpireTime))
 }
@@ -37,8 +37,9 @@ import (
 var _ ConfigCenterServer = (*Server)(nil)
 
 const (
-	eventTypePublishConfigFile  = "PublishConfigFile"
-	defaultExpireTimeAfterWrite = 60 * 60 // expire after 1 hour
+	eventTypePublishConfigFile     = "PublishConfigFile"
+	eventTypePublishConfigFileGray = "PublishConfigFileGray"
+	defaultExpireTimeAfterWrite    = 60 * 60 // expire after 1 hour
 )
 
 var (
@@ -111,12 +111,17 @@ func (s *serverAuthability) collectConfigFileReleaseHistoryAuthContext(
 
 func (s *serverAuthability) collectConfigGroupAuthContext(ctx context.Context, req []*apiconfig.ConfigFileGroup,
 	op model.ResourceOperation, methodName string) *model.AcquireContext {
+	resources := s.queryConfigGroupResource(ctx, req)
+	// 创建配置分组时分组还不存在，和创建服务一样需要校验所属命名空间的权限
+	if op == model.Create && resources != nil && len(req) > 0 {
+		resources[apisecurity.ResourceType_Namespaces] = s.queryNamespaceResource(req[0].GetNamespace().GetValue())
+	}
 	return model.NewAcquireContext(
 		model.WithRequestContext(ctx),
 		model.WithModule(model.ConfigModule),
 		model.WithOperation(op),
 		model.WithMethod(methodName),
-		model.WithAccessResources(s.queryConfigGroupResource(ctx, req)),
+		model.WithAccessResources(resources),
 	)
 }
 
@@ -128,6 +133,39 @@ func (s *serverAuthability) collectConfigFileTemplateAuthContext(ctx context.Con
 	)
 }
 
+// collectConfigApprovalPolicyAuthContext 审批策略约束的是命名空间下所有的配置发布，修改审批策略需要命名空间的写权限
+func (s *serverAuthability) collectConfigApprovalPolicyAuthContext(ctx context.Context, namespace, group string,
+	op model.ResourceOperation, methodName string) *model.AcquireContext {
+	ret := map[apisecurity.ResourceType][]model.ResourceEntry{
+		apisecurity.ResourceType_Namespaces: s.queryNamespaceResource(namespace),
+	}
+	if group != "" {
+		groupRes := s.queryConfigGroupResource(ctx, []*apiconfig.ConfigFileGroup{{
+			Name: utils.NewStringValue(group), Namespace: utils.NewStringValue(namespace)}})
+		ret[apisecurity.ResourceType_ConfigGroups] = groupRes[apisecurity.ResourceType_ConfigGroups]
+	}
+	return model.NewAcquireContext(
+		model.WithRequestContext(ctx),
+		model.WithModule(model.ConfigModule),
+		model.WithOperation(op),
+		model.WithMethod(methodName),
+		model.WithAccessResources(ret),
+	)
+}
+
+// queryNamespaceResource 收集命名空间对应的 ResourceEntry
+func (s *serverAuthability) queryNamespaceResource(namespace string) []model.ResourceEntry {
+	entries := make([]model.ResourceEntry, 0, 1)
+	for _, ns := range s.targetServer.caches.Namespace().GetNamespacesByName([]string{namespace}) {
+		entries = append(entries, model.ResourceEntry{
+			ID:        ns.Name,
+			Owner:     ns.Owner,
+			Namespace: ns.Name,
+		})
+	}
+	return entries
+}
+
 func (s *serverAuthability) queryConfigGroupResource(ctx context.Context,
 	req []*apiconfig.ConfigFileGroup) map[apisecurity.ResourceType][]model.ResourceEntry {
 
@@ -251,8 +289,9 @@ func (s *serverAuthability) queryConfigGroupRsEntryByNames(ctx context.Context,
 	for index := range configFileGroups {
 		group := configFileGroups[index]
 		entries = append(entries, model.ResourceEntry{
-			ID:    strconv.FormatUint(group.Id, 10),
-			Owner: group.Owner,
+			ID:        strconv.FormatUint(group.Id, 10),
+			Owner:     group.Owner,
+			Namespace: group.Namespace,
 		})
 	}
 	return entries, nil
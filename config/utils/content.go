@@ -0,0 +1,397 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+var (
+	regYamlErrPrefix = regexp.MustCompile(`^yaml: (line (\d+): )?`)
+	regTomlErrPrefix = regexp.MustCompile(`^toml: line \d+( \(last key "[^"]*"\))?: `)
+)
+
+// ContentFormatError 配置内容与配置格式不匹配，Line 和 Column 从 1 开始，为 0 时表示无法定位
+type ContentFormatError struct {
+	Format string
+	Line   int
+	Column int
+	Reason string
+}
+
+// Error 实现 error 接口
+func (e *ContentFormatError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("invalid %s content at line %d, column %d: %s", e.Format, e.Line, e.Column, e.Reason)
+	case e.Line > 0:
+		return fmt.Sprintf("invalid %s content at line %d: %s", e.Format, e.Line, e.Reason)
+	default:
+		return fmt.Sprintf("invalid %s content: %s", e.Format, e.Reason)
+	}
+}
+
+// CheckContentFormat 按照配置文件格式解析配置内容，text、html 以及未知格式不做校验
+func CheckContentFormat(format, content string) error {
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	switch format {
+	case utils.FileFormatJson:
+		_, err := parseJsonContent(content)
+		return err
+	case utils.FileFormatYaml:
+		_, err := parseYamlContent(content)
+		return err
+	case utils.FileFormatToml:
+		_, err := parseTomlContent(content)
+		return err
+	case utils.FileFormatXml:
+		return checkXmlContent(content)
+	case utils.FileFormatProperties:
+		_, err := parsePropertiesContent(content)
+		return err
+	default:
+		return nil
+	}
+}
+
+// IsStructuredFormat 是否为可以转换为 JSON 数据结构的配置格式
+func IsStructuredFormat(format string) bool {
+	return format == utils.FileFormatJson || format == utils.FileFormatYaml || format == utils.FileFormatToml
+}
+
+// ParseStructuredContent 将 json、yaml、toml 格式的配置内容解析为通用的 JSON 数据结构，用于 JSON Schema 校验，
+// 不支持的格式返回 false
+func ParseStructuredContent(format, content string) (interface{}, bool, error) {
+	var (
+		data interface{}
+		err  error
+	)
+	switch format {
+	case utils.FileFormatJson:
+		data, err = parseJsonContent(content)
+	case utils.FileFormatYaml:
+		data, err = parseYamlContent(content)
+	case utils.FileFormatToml:
+		data, err = parseTomlContent(content)
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, true, err
+	}
+	return data, true, nil
+}
+
+func parseJsonContent(content string) (interface{}, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// Offset 为出错时已经读取的字节数
+			line, column := offsetToPosition(content, int(syntaxErr.Offset)-1)
+			return nil, &ContentFormatError{Format: utils.FileFormatJson, Line: line, Column: column,
+				Reason: syntaxErr.Error()}
+		}
+		return nil, &ContentFormatError{Format: utils.FileFormatJson, Reason: err.Error()}
+	}
+	return data, nil
+}
+
+func parseYamlContent(content string) (interface{}, error) {
+	var ret interface{}
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	// 一个 yaml 文件中可能包含多个文档，需要全部校验
+	for i := 0; ; i++ {
+		var data interface{}
+		err := decoder.Decode(&data)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			formatErr := &ContentFormatError{
+				Format: utils.FileFormatYaml,
+				Reason: regYamlErrPrefix.ReplaceAllString(err.Error(), ""),
+			}
+			if match := regYamlErrPrefix.FindStringSubmatch(err.Error()); len(match) == 3 && match[2] != "" {
+				formatErr.Line, _ = strconv.Atoi(match[2])
+			}
+			return nil, formatErr
+		}
+		if i == 0 {
+			ret = normalizeYamlValue(data)
+		}
+	}
+	return ret, nil
+}
+
+// normalizeYamlValue yaml 解析出来的 map 键为 interface{}，转换为与 JSON 一致的数据结构
+func normalizeYamlValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[interface{}]interface{}:
+		ret := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			ret[fmt.Sprintf("%v", key)] = normalizeYamlValue(item)
+		}
+		return ret
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeYamlValue(v[i])
+		}
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+func parseTomlContent(content string) (interface{}, error) {
+	data := map[string]interface{}{}
+	if _, err := toml.Decode(content, &data); err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			line, column := parseErr.Position.Line, 0
+			if parseErr.Position.Start > 0 {
+				line, column = offsetToPosition(content, parseErr.Position.Start)
+			}
+			return nil, &ContentFormatError{Format: utils.FileFormatToml, Line: line, Column: column,
+				Reason: regTomlErrPrefix.ReplaceAllString(parseErr.Error(), "")}
+		}
+		return nil, &ContentFormatError{Format: utils.FileFormatToml, Reason: err.Error()}
+	}
+	return normalizeTomlValue(data), nil
+}
+
+// normalizeTomlValue toml 解析出来的整数以及时间类型，转换为与 JSON 一致的数据结构
+func normalizeTomlValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeTomlValue(item)
+		}
+		return v
+	case []map[string]interface{}:
+		ret := make([]interface{}, 0, len(v))
+		for i := range v {
+			ret = append(ret, normalizeTomlValue(v[i]))
+		}
+		return ret
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeTomlValue(v[i])
+		}
+		return v
+	case int64:
+		return float64(v)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func checkXmlContent(content string) error {
+	decoder := xml.NewDecoder(strings.NewReader(content))
+	var (
+		hasRoot bool
+		depth   int
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			formatErr := &ContentFormatError{Format: utils.FileFormatXml, Reason: err.Error()}
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				formatErr.Reason = syntaxErr.Msg
+				formatErr.Line = syntaxErr.Line
+				_, formatErr.Column = offsetToPosition(content, int(decoder.InputOffset()))
+			}
+			return formatErr
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 && hasRoot {
+				line, column := offsetToPosition(content, int(decoder.InputOffset()))
+				return &ContentFormatError{Format: utils.FileFormatXml, Line: line, Column: column,
+					Reason: "multiple root elements"}
+			}
+			hasRoot = true
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			if depth == 0 && len(bytes.TrimSpace(t)) > 0 {
+				line, column := offsetToPosition(content, int(decoder.InputOffset()))
+				return &ContentFormatError{Format: utils.FileFormatXml, Line: line, Column: column,
+					Reason: "text outside the root element"}
+			}
+		}
+	}
+	if !hasRoot {
+		return &ContentFormatError{Format: utils.FileFormatXml, Reason: "no root element"}
+	}
+	return nil
+}
+
+// parsePropertiesContent 按照 java properties 的语法解析配置内容
+func parsePropertiesContent(content string) (map[string]string, error) {
+	ret := map[string]string{}
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		// 以奇数个反斜杠结尾的行需要与下一行拼接
+		for isContinuationLine(line) {
+			line = line[:len(line)-1]
+			if i+1 >= len(lines) {
+				break
+			}
+			i++
+			line += strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+		}
+
+		keyEnd, valueStart := splitPropertiesLine(line)
+		if keyEnd == 0 {
+			return nil, &ContentFormatError{Format: utils.FileFormatProperties, Line: lineNo, Column: 1,
+				Reason: "missing property key"}
+		}
+		key, err := unescapeProperties(line[:keyEnd])
+		if err != nil {
+			return nil, &ContentFormatError{Format: utils.FileFormatProperties, Line: lineNo, Column: 1,
+				Reason: err.Error()}
+		}
+		value, err := unescapeProperties(line[valueStart:])
+		if err != nil {
+			return nil, &ContentFormatError{Format: utils.FileFormatProperties, Line: lineNo,
+				Column: valueStart + 1, Reason: err.Error()}
+		}
+		ret[key] = value
+	}
+	return ret, nil
+}
+
+func isContinuationLine(line string) bool {
+	slashes := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		slashes++
+	}
+	return slashes%2 == 1
+}
+
+// splitPropertiesLine 返回 key 的结束位置以及 value 的起始位置，key 与 value 之间可以使用 '='、':' 或者空白字符分隔
+func splitPropertiesLine(line string) (int, int) {
+	keyEnd := len(line)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' {
+			i++
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			keyEnd = i
+			break
+		}
+	}
+	valueStart := keyEnd
+	for valueStart < len(line) && strings.IndexByte(" \t\f", line[valueStart]) >= 0 {
+		valueStart++
+	}
+	if valueStart < len(line) && (line[valueStart] == '=' || line[valueStart] == ':') {
+		valueStart++
+		for valueStart < len(line) && strings.IndexByte(" \t\f", line[valueStart]) >= 0 {
+			valueStart++
+		}
+	}
+	return keyEnd, valueStart
+}
+
+func unescapeProperties(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			buf.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			buf.WriteByte('\t')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", errors.New("malformed \\uxxxx encoding")
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", errors.New("malformed \\uxxxx encoding")
+			}
+			buf.WriteRune(rune(code))
+			i += 4
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.String(), nil
+}
+
+// offsetToPosition 将字节偏移量转换为行号以及列号，行号和列号均从 1 开始
+func offsetToPosition(content string, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := content[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := offset - strings.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,157 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func TestCheckContentFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		line    int
+		column  int
+	}{
+		{name: "valid json", format: utils.FileFormatJson, content: `{"a": 1, "b": [1, 2]}`},
+		{name: "invalid json", format: utils.FileFormatJson, content: "{\n  \"a\": 1,\n  \"b\": ,\n}", line: 3, column: 8},
+		{name: "valid yaml", format: utils.FileFormatYaml, content: "a: 1\nb:\n  c: 2\n"},
+		{name: "invalid yaml", format: utils.FileFormatYaml, content: "a: 1\nb:\n  c: 2\n d: 3\n", line: 3},
+		{name: "invalid yaml in second document", format: utils.FileFormatYaml, content: "a: 1\n---\nb: [1\n", line: 3},
+		{name: "valid toml", format: utils.FileFormatToml, content: "[server]\nport = 8080\n"},
+		{name: "invalid toml", format: utils.FileFormatToml, content: "a = 1\nb = \n", line: 2, column: 5},
+		{name: "valid xml", format: utils.FileFormatXml, content: "<?xml version=\"1.0\"?>\n<a x=\"1\">t</a>\n"},
+		{name: "mismatched xml tag", format: utils.FileFormatXml, content: "<a>\n  <b></c>\n</a>", line: 2, column: 10},
+		{name: "multiple xml root", format: utils.FileFormatXml, content: "<a/><b/>", line: 1, column: 9},
+		{name: "valid properties", format: utils.FileFormatProperties, content: "a=1\nb : 2\nc 3\n# comment\nd=1\\\n  2\n"},
+		{name: "properties missing key", format: utils.FileFormatProperties, content: "a=1\n=bad\n", line: 2, column: 1},
+		{name: "properties bad unicode", format: utils.FileFormatProperties, content: "a=\\u12\n", line: 1, column: 3},
+		{name: "text is not checked", format: utils.FileFormatText, content: "{ not json"},
+		{name: "empty content", format: utils.FileFormatJson, content: "  "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckContentFormat(tt.format, tt.content)
+			if tt.line == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var formatErr *ContentFormatError
+			assert.True(t, errors.As(err, &formatErr), "%v", err)
+			assert.Equal(t, tt.format, formatErr.Format)
+			assert.Equal(t, tt.line, formatErr.Line)
+			assert.Equal(t, tt.column, formatErr.Column)
+		})
+	}
+}
+
+func TestValidateContentBySchema(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["port"],
+		"properties": {
+			"port": {"type": "integer", "minimum": 1, "maximum": 65535},
+			"name": {"type": "string", "pattern": "^[a-z]+$"},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+		},
+		"additionalProperties": false
+	}`
+	tests := []struct {
+		name    string
+		format  string
+		content string
+		path    string
+	}{
+		{name: "valid yaml", format: utils.FileFormatYaml, content: "port: 8080\nname: abc\ntags: [a, b]\n"},
+		{name: "valid json", format: utils.FileFormatJson, content: `{"port": 80}`},
+		{name: "valid toml", format: utils.FileFormatToml, content: "port = 8080\n"},
+		{name: "properties is not checked", format: utils.FileFormatProperties, content: "port=abc"},
+		{name: "out of range", format: utils.FileFormatYaml, content: "port: 80800\n", path: "$.port"},
+		{name: "missing required", format: utils.FileFormatYaml, content: "name: abc\n", path: "$"},
+		{name: "not integer", format: utils.FileFormatJson, content: `{"port": 1.5}`, path: "$.port"},
+		{name: "additional property", format: utils.FileFormatToml, content: "port = 1\nother = 1\n", path: "$.other"},
+		{name: "array item type", format: utils.FileFormatYaml, content: "port: 1\ntags: [a, 1]\n", path: "$.tags[1]"},
+		{name: "too many items", format: utils.FileFormatYaml, content: "port: 1\ntags: [a, b, c]\n", path: "$.tags"},
+		{name: "pattern", format: utils.FileFormatJson, content: `{"port": 1, "name": "ABC"}`, path: "$.name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateContentBySchema(schema, tt.format, tt.content)
+			if tt.path == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var schemaErr *SchemaValidateError
+			assert.True(t, errors.As(err, &schemaErr), "%v", err)
+			assert.Equal(t, tt.path, schemaErr.Path)
+		})
+	}
+}
+
+func TestParseJsonSchema(t *testing.T) {
+	_, err := ParseJsonSchema(`{"type": "object", "properties": {"a": {"type": ["string", "null"]}}}`)
+	assert.NoError(t, err)
+
+	_, err = ParseJsonSchema(`{"type": "foo"}`)
+	assert.Error(t, err)
+
+	_, err = ParseJsonSchema(`{"minLength": -1}`)
+	assert.Error(t, err)
+
+	_, err = ParseJsonSchema(`{"pattern": "("}`)
+	assert.Error(t, err)
+
+	_, err = ParseJsonSchema(`not json`)
+	assert.Error(t, err)
+
+	_, err = ParseJsonSchema(`{"properties": {"a": false}, "items": true}`)
+	assert.NoError(t, err)
+
+	for _, schema := range []string{
+		`{"$ref": "#/definitions/a"}`,
+		`{"oneOf": [{"type": "string"}]}`,
+		`{"properties": {"a": {"allOf": [{"type": "string"}]}}}`,
+		`{"items": {"anyOf": [{"type": "string"}]}}`,
+		`{"not": {"type": "string"}}`,
+		`{"type": "string", "format": "email"}`,
+	} {
+		_, err = ParseJsonSchema(schema)
+		assert.Error(t, err, schema)
+	}
+}
+
+func TestValidateContentByBooleanSchema(t *testing.T) {
+	assert.NoError(t, ValidateContentBySchema(`true`, utils.FileFormatJson, `{"a": 1}`))
+
+	var schemaErr *SchemaValidateError
+	err := ValidateContentBySchema(`false`, utils.FileFormatJson, `{"a": 1}`)
+	assert.True(t, errors.As(err, &schemaErr), "%v", err)
+	assert.Equal(t, "$", schemaErr.Path)
+
+	schema := `{"properties": {"secret": false}}`
+	assert.NoError(t, ValidateContentBySchema(schema, utils.FileFormatJson, `{"a": 1}`))
+	err = ValidateContentBySchema(schema, utils.FileFormatJson, `{"secret": 1}`)
+	assert.True(t, errors.As(err, &schemaErr), "%v", err)
+	assert.Equal(t, "$.secret", schemaErr.Path)
+}
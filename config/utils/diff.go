@@ -0,0 +1,252 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// DiffTypeEqual 内容未变化
+	DiffTypeEqual = "equal"
+	// DiffTypeAdd 新增的内容
+	DiffTypeAdd = "add"
+	// DiffTypeDelete 删除的内容
+	DiffTypeDelete = "delete"
+	// DiffTypeModify 修改的内容，只有按 key 对比时才会出现
+	DiffTypeModify = "modify"
+
+	// DiffModeLine 按行对比
+	DiffModeLine = "line"
+	// DiffModeKey 按配置项对比
+	DiffModeKey = "key"
+
+	// maxLineDiffCells 按行对比时 LCS 矩阵的最大规模，超过后直接认为整体替换
+	maxLineDiffCells = 4 * 1024 * 1024
+)
+
+// LineDiff 按行对比的结果，FromLine 以及 ToLine 从 1 开始，为 0 表示该行在对应版本中不存在
+type LineDiff struct {
+	Type     string `json:"type"`
+	FromLine int    `json:"from_line,omitempty"`
+	ToLine   int    `json:"to_line,omitempty"`
+	Content  string `json:"content"`
+}
+
+// KeyDiff 按配置项对比的结果，嵌套的配置项使用 a.b[0].c 形式的 key 表示
+type KeyDiff struct {
+	Type      string `json:"type"`
+	Key       string `json:"key"`
+	FromValue string `json:"from_value,omitempty"`
+	ToValue   string `json:"to_value,omitempty"`
+}
+
+// DiffLines 按行对比两段文本
+func DiffLines(from, to string) []*LineDiff {
+	fromLines, toLines := splitLines(from), splitLines(to)
+
+	// 去掉相同的前缀以及后缀，缩小 LCS 矩阵的规模
+	prefix := 0
+	for prefix < len(fromLines) && prefix < len(toLines) && fromLines[prefix] == toLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(fromLines)-prefix && suffix < len(toLines)-prefix &&
+		fromLines[len(fromLines)-1-suffix] == toLines[len(toLines)-1-suffix] {
+		suffix++
+	}
+
+	ret := make([]*LineDiff, 0, len(fromLines)+len(toLines))
+	for i := 0; i < prefix; i++ {
+		ret = append(ret, &LineDiff{Type: DiffTypeEqual, FromLine: i + 1, ToLine: i + 1, Content: fromLines[i]})
+	}
+	ret = append(ret, diffMiddleLines(fromLines[prefix:len(fromLines)-suffix],
+		toLines[prefix:len(toLines)-suffix], prefix, prefix)...)
+	for i := suffix; i > 0; i-- {
+		fromIdx, toIdx := len(fromLines)-i, len(toLines)-i
+		ret = append(ret, &LineDiff{Type: DiffTypeEqual, FromLine: fromIdx + 1, ToLine: toIdx + 1,
+			Content: fromLines[fromIdx]})
+	}
+	return ret
+}
+
+func diffMiddleLines(from, to []string, fromOffset, toOffset int) []*LineDiff {
+	ret := make([]*LineDiff, 0, len(from)+len(to))
+	if len(from)*len(to) > maxLineDiffCells {
+		for i := range from {
+			ret = append(ret, &LineDiff{Type: DiffTypeDelete, FromLine: fromOffset + i + 1, Content: from[i]})
+		}
+		for i := range to {
+			ret = append(ret, &LineDiff{Type: DiffTypeAdd, ToLine: toOffset + i + 1, Content: to[i]})
+		}
+		return ret
+	}
+
+	// lcs[i][j] 表示 from[i:] 与 to[j:] 的最长公共子序列长度
+	lcs := make([][]int32, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case i < len(from) && j < len(to) && from[i] == to[j]:
+			ret = append(ret, &LineDiff{Type: DiffTypeEqual, FromLine: fromOffset + i + 1,
+				ToLine: toOffset + j + 1, Content: from[i]})
+			i++
+			j++
+		case j >= len(to) || (i < len(from) && lcs[i+1][j] >= lcs[i][j+1]):
+			ret = append(ret, &LineDiff{Type: DiffTypeDelete, FromLine: fromOffset + i + 1, Content: from[i]})
+			i++
+		default:
+			ret = append(ret, &LineDiff{Type: DiffTypeAdd, ToLine: toOffset + j + 1, Content: to[j]})
+			j++
+		}
+	}
+	return ret
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	// 忽略文件末尾的换行符
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// SupportKeyDiff 是否支持按配置项对比
+func SupportKeyDiff(format string) bool {
+	return IsStructuredFormat(format) || format == utils.FileFormatProperties
+}
+
+// DiffKeys 按配置项对比两份配置，只支持 json、yaml、toml 以及 properties 格式，返回的结果按 key 排序
+func DiffKeys(format, from, to string) ([]*KeyDiff, error) {
+	fromKeys, err := flattenContent(format, from)
+	if err != nil {
+		return nil, err
+	}
+	toKeys, err := flattenContent(format, to)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(fromKeys)+len(toKeys))
+	for key := range fromKeys {
+		keys = append(keys, key)
+	}
+	for key := range toKeys {
+		if _, ok := fromKeys[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	ret := make([]*KeyDiff, 0, len(keys))
+	for _, key := range keys {
+		fromVal, inFrom := fromKeys[key]
+		toVal, inTo := toKeys[key]
+		switch {
+		case inFrom && !inTo:
+			ret = append(ret, &KeyDiff{Type: DiffTypeDelete, Key: key, FromValue: fromVal})
+		case !inFrom && inTo:
+			ret = append(ret, &KeyDiff{Type: DiffTypeAdd, Key: key, ToValue: toVal})
+		case fromVal != toVal:
+			ret = append(ret, &KeyDiff{Type: DiffTypeModify, Key: key, FromValue: fromVal, ToValue: toVal})
+		default:
+			ret = append(ret, &KeyDiff{Type: DiffTypeEqual, Key: key, FromValue: fromVal, ToValue: toVal})
+		}
+	}
+	return ret, nil
+}
+
+// flattenContent 将配置内容展开为 key -> value 的形式
+func flattenContent(format, content string) (map[string]string, error) {
+	ret := map[string]string{}
+	if strings.TrimSpace(content) == "" {
+		return ret, nil
+	}
+	if format == utils.FileFormatProperties {
+		return parsePropertiesContent(content)
+	}
+	data, supported, err := ParseStructuredContent(format, content)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, fmt.Errorf("format %s does not support key diff", format)
+	}
+	flattenValue("", data, ret)
+	return ret, nil
+}
+
+func flattenValue(prefix string, val interface{}, ret map[string]string) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			ret[prefix] = "{}"
+			return
+		}
+		for key, item := range v {
+			if prefix == "" {
+				flattenValue(key, item, ret)
+			} else {
+				flattenValue(prefix+"."+key, item, ret)
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			ret[prefix] = "[]"
+			return
+		}
+		for i, item := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", prefix, i), item, ret)
+		}
+	case string:
+		ret[prefix] = v
+	case nil:
+		ret[prefix] = "null"
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			ret[prefix] = fmt.Sprintf("%v", v)
+			return
+		}
+		ret[prefix] = string(data)
+	}
+}
@@ -0,0 +1,89 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func TestDiffLines(t *testing.T) {
+	ret := DiffLines("a\nb\nc\nd\n", "a\nc\nx\nd\ne\n")
+	expect := []*LineDiff{
+		{Type: DiffTypeEqual, FromLine: 1, ToLine: 1, Content: "a"},
+		{Type: DiffTypeDelete, FromLine: 2, Content: "b"},
+		{Type: DiffTypeEqual, FromLine: 3, ToLine: 2, Content: "c"},
+		{Type: DiffTypeAdd, ToLine: 3, Content: "x"},
+		{Type: DiffTypeEqual, FromLine: 4, ToLine: 4, Content: "d"},
+		{Type: DiffTypeAdd, ToLine: 5, Content: "e"},
+	}
+	assert.Equal(t, expect, ret)
+
+	ret = DiffLines("", "a\r\nb")
+	assert.Equal(t, []*LineDiff{
+		{Type: DiffTypeAdd, ToLine: 1, Content: "a"},
+		{Type: DiffTypeAdd, ToLine: 2, Content: "b"},
+	}, ret)
+
+	assert.Empty(t, DiffLines("", ""))
+}
+
+func TestDiffKeys(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		ret, err := DiffKeys(utils.FileFormatYaml, "a: 1\nb:\n  c: [1, 2]\n  d: x\n", "a: 2\nb:\n  c: [1]\n  e: y1\n")
+		assert.NoError(t, err)
+		assert.Equal(t, []*KeyDiff{
+			{Type: DiffTypeModify, Key: "a", FromValue: "1", ToValue: "2"},
+			{Type: DiffTypeEqual, Key: "b.c[0]", FromValue: "1", ToValue: "1"},
+			{Type: DiffTypeDelete, Key: "b.c[1]", FromValue: "2"},
+			{Type: DiffTypeDelete, Key: "b.d", FromValue: "x"},
+			{Type: DiffTypeAdd, Key: "b.e", ToValue: "y1"},
+		}, ret)
+	})
+	t.Run("json", func(t *testing.T) {
+		ret, err := DiffKeys(utils.FileFormatJson, `{"a": {"b": true}, "c": []}`, `{"a": {}, "c": [null]}`)
+		assert.NoError(t, err)
+		assert.Equal(t, []*KeyDiff{
+			{Type: DiffTypeAdd, Key: "a", ToValue: "{}"},
+			{Type: DiffTypeDelete, Key: "a.b", FromValue: "true"},
+			{Type: DiffTypeDelete, Key: "c", FromValue: "[]"},
+			{Type: DiffTypeAdd, Key: "c[0]", ToValue: "null"},
+		}, ret)
+	})
+	t.Run("properties", func(t *testing.T) {
+		ret, err := DiffKeys(utils.FileFormatProperties, "a=1\nb=2", "a=1\nb=3\nc=4")
+		assert.NoError(t, err)
+		assert.Equal(t, []*KeyDiff{
+			{Type: DiffTypeEqual, Key: "a", FromValue: "1", ToValue: "1"},
+			{Type: DiffTypeModify, Key: "b", FromValue: "2", ToValue: "3"},
+			{Type: DiffTypeAdd, Key: "c", ToValue: "4"},
+		}, ret)
+	})
+	t.Run("invalid content", func(t *testing.T) {
+		_, err := DiffKeys(utils.FileFormatJson, `{"a": 1}`, `{"a": `)
+		assert.Error(t, err)
+	})
+	t.Run("unsupported format", func(t *testing.T) {
+		assert.False(t, SupportKeyDiff(utils.FileFormatText))
+		_, err := DiffKeys(utils.FileFormatText, "a", "b")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,395 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// JsonSchema 配置分组上设置的 JSON Schema，支持 draft-07 中常用的校验关键字:
+// type、properties、required、additionalProperties、items、enum、const、minimum、maximum、
+// exclusiveMinimum、exclusiveMaximum、minLength、maxLength、pattern、minItems、maxItems
+// 以及布尔类型的 schema，使用了其余校验关键字的 schema 在保存时直接拒绝，避免约束被静默忽略
+type JsonSchema struct {
+	// DenyAll 为 false 的布尔 schema，任何值都不满足约束
+	DenyAll              bool
+	Types                []string
+	Properties           map[string]*JsonSchema
+	Required             []string
+	AdditionalProperties *JsonSchema
+	DenyAdditional       bool
+	Items                *JsonSchema
+	Enum                 []interface{}
+	Const                interface{}
+	HasConst             bool
+	Minimum              *float64
+	Maximum              *float64
+	ExclusiveMinimum     *float64
+	ExclusiveMaximum     *float64
+	MinLength            *int
+	MaxLength            *int
+	Pattern              *regexp.Regexp
+	MinItems             *int
+	MaxItems             *int
+}
+
+// SchemaValidateError 配置内容不满足 JSON Schema 的约束
+type SchemaValidateError struct {
+	Path   string
+	Reason string
+}
+
+// Error 实现 error 接口
+func (e *SchemaValidateError) Error() string {
+	return fmt.Sprintf("schema validation failed at %s: %s", e.Path, e.Reason)
+}
+
+// unsupportedSchemaKeywords 暂不支持的校验关键字
+var unsupportedSchemaKeywords = []string{
+	"$ref", "allOf", "anyOf", "oneOf", "not", "if", "then", "else", "format", "patternProperties",
+	"dependencies", "propertyNames", "contains", "uniqueItems", "multipleOf", "minProperties", "maxProperties",
+	"additionalItems",
+}
+
+var schemaTypes = map[string]struct{}{
+	"object": {}, "array": {}, "string": {}, "number": {}, "integer": {}, "boolean": {}, "null": {},
+}
+
+// ParseJsonSchema 解析 JSON Schema 文本
+func ParseJsonSchema(text string) (*JsonSchema, error) {
+	var raw interface{}
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+	schema, err := compileJsonSchema(raw, "$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid json schema: %w", err)
+	}
+	return schema, nil
+}
+
+func compileJsonSchema(raw interface{}, path string) (*JsonSchema, error) {
+	// true 表示不做任何约束，false 表示任何值都不满足约束
+	if b, ok := raw.(bool); ok {
+		return &JsonSchema{DenyAll: !b}, nil
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", path)
+	}
+	for _, keyword := range unsupportedSchemaKeywords {
+		if _, ok := m[keyword]; ok {
+			return nil, fmt.Errorf("%s: unsupported keyword %s", path, keyword)
+		}
+	}
+
+	schema := &JsonSchema{}
+	var err error
+	if val, ok := m["type"]; ok {
+		if schema.Types, err = compileSchemaTypes(val, path); err != nil {
+			return nil, err
+		}
+	}
+	if val, ok := m["properties"]; ok {
+		props, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: properties must be an object", path)
+		}
+		schema.Properties = make(map[string]*JsonSchema, len(props))
+		for name, prop := range props {
+			if schema.Properties[name], err = compileJsonSchema(prop, path+".properties."+name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if val, ok := m["required"]; ok {
+		items, ok := val.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s: required must be an array of string", path)
+		}
+		for _, item := range items {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: required must be an array of string", path)
+			}
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	if val, ok := m["additionalProperties"]; ok {
+		if b, isBool := val.(bool); isBool {
+			schema.DenyAdditional = !b
+		} else if schema.AdditionalProperties, err = compileJsonSchema(val, path+".additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+	if val, ok := m["items"]; ok {
+		if schema.Items, err = compileJsonSchema(val, path+".items"); err != nil {
+			return nil, err
+		}
+	}
+	if val, ok := m["enum"]; ok {
+		if schema.Enum, ok = val.([]interface{}); !ok || len(schema.Enum) == 0 {
+			return nil, fmt.Errorf("%s: enum must be a non-empty array", path)
+		}
+	}
+	if val, ok := m["const"]; ok {
+		schema.Const, schema.HasConst = val, true
+	}
+	numbers := map[string]**float64{
+		"minimum":          &schema.Minimum,
+		"maximum":          &schema.Maximum,
+		"exclusiveMinimum": &schema.ExclusiveMinimum,
+		"exclusiveMaximum": &schema.ExclusiveMaximum,
+	}
+	for keyword, target := range numbers {
+		if val, ok := m[keyword]; ok {
+			num, ok := val.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s: %s must be a number", path, keyword)
+			}
+			*target = &num
+		}
+	}
+	counts := map[string]**int{
+		"minLength": &schema.MinLength,
+		"maxLength": &schema.MaxLength,
+		"minItems":  &schema.MinItems,
+		"maxItems":  &schema.MaxItems,
+	}
+	for keyword, target := range counts {
+		if val, ok := m[keyword]; ok {
+			num, ok := val.(float64)
+			if !ok || num < 0 || num != math.Trunc(num) {
+				return nil, fmt.Errorf("%s: %s must be a non-negative integer", path, keyword)
+			}
+			count := int(num)
+			*target = &count
+		}
+	}
+	if val, ok := m["pattern"]; ok {
+		pattern, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: pattern must be a string", path)
+		}
+		if schema.Pattern, err = regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+	}
+	return schema, nil
+}
+
+func compileSchemaTypes(val interface{}, path string) ([]string, error) {
+	var types []string
+	switch v := val.(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			t, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: type must be a string or an array of string", path)
+			}
+			types = append(types, t)
+		}
+	default:
+		return nil, fmt.Errorf("%s: type must be a string or an array of string", path)
+	}
+	for _, t := range types {
+		if _, ok := schemaTypes[t]; !ok {
+			return nil, fmt.Errorf("%s: unknown type %s", path, t)
+		}
+	}
+	return types, nil
+}
+
+// Validate 校验解析后的配置内容是否满足 Schema 约束，data 的数据结构与 encoding/json 解析的结果保持一致
+func (s *JsonSchema) Validate(data interface{}) error {
+	return s.validate(data, "$")
+}
+
+func (s *JsonSchema) validate(data interface{}, path string) error {
+	if s.DenyAll {
+		return &SchemaValidateError{Path: path, Reason: "no value is allowed"}
+	}
+	if len(s.Types) > 0 && !matchSchemaTypes(s.Types, data) {
+		return &SchemaValidateError{Path: path,
+			Reason: fmt.Sprintf("expected %s, but got %s", strings.Join(s.Types, " or "), jsonTypeOf(data))}
+	}
+	if s.HasConst && !reflect.DeepEqual(s.Const, data) {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must be equal to %v", s.Const)}
+	}
+	if len(s.Enum) > 0 {
+		matched := false
+		for _, item := range s.Enum {
+			if reflect.DeepEqual(item, data) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must be one of %v", s.Enum)}
+		}
+	}
+
+	switch v := data.(type) {
+	case map[string]interface{}:
+		return s.validateObject(v, path)
+	case []interface{}:
+		return s.validateArray(v, path)
+	case string:
+		return s.validateString(v, path)
+	case float64:
+		return s.validateNumber(v, path)
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateObject(data map[string]interface{}, path string) error {
+	for _, name := range s.Required {
+		if _, ok := data[name]; !ok {
+			return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("missing required property %s", name)}
+		}
+	}
+	// 按照属性名排序，保证多个属性不满足约束时返回的错误稳定
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		propPath := path + "." + name
+		if prop, ok := s.Properties[name]; ok {
+			if err := prop.validate(data[name], propPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if s.DenyAdditional {
+			return &SchemaValidateError{Path: propPath, Reason: "additional property is not allowed"}
+		}
+		if s.AdditionalProperties != nil {
+			if err := s.AdditionalProperties.validate(data[name], propPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateArray(data []interface{}, path string) error {
+	if s.MinItems != nil && len(data) < *s.MinItems {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must have at least %d items", *s.MinItems)}
+	}
+	if s.MaxItems != nil && len(data) > *s.MaxItems {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must have at most %d items", *s.MaxItems)}
+	}
+	if s.Items == nil {
+		return nil
+	}
+	for i := range data {
+		if err := s.Items.validate(data[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateString(data string, path string) error {
+	length := utf8.RuneCountInString(data)
+	if s.MinLength != nil && length < *s.MinLength {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("length must be >= %d", *s.MinLength)}
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("length must be <= %d", *s.MaxLength)}
+	}
+	if s.Pattern != nil && !s.Pattern.MatchString(data) {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must match pattern %s", s.Pattern.String())}
+	}
+	return nil
+}
+
+func (s *JsonSchema) validateNumber(data float64, path string) error {
+	if s.Minimum != nil && data < *s.Minimum {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must be >= %v", *s.Minimum)}
+	}
+	if s.Maximum != nil && data > *s.Maximum {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must be <= %v", *s.Maximum)}
+	}
+	if s.ExclusiveMinimum != nil && data <= *s.ExclusiveMinimum {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must be > %v", *s.ExclusiveMinimum)}
+	}
+	if s.ExclusiveMaximum != nil && data >= *s.ExclusiveMaximum {
+		return &SchemaValidateError{Path: path, Reason: fmt.Sprintf("must be < %v", *s.ExclusiveMaximum)}
+	}
+	return nil
+}
+
+func matchSchemaTypes(types []string, data interface{}) bool {
+	actual := jsonTypeOf(data)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonTypeOf(data interface{}) string {
+	switch v := data.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) && !math.IsInf(v, 0) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidateContentBySchema 使用 JSON Schema 校验配置内容，只对 json、yaml、toml 格式的配置生效
+func ValidateContentBySchema(schemaText, format, content string) error {
+	if strings.TrimSpace(schemaText) == "" || strings.TrimSpace(content) == "" {
+		return nil
+	}
+	data, supported, err := ParseStructuredContent(format, content)
+	if err != nil || !supported {
+		return err
+	}
+	schema, err := ParseJsonSchema(schemaText)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(data)
+}
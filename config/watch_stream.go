@@ -0,0 +1,184 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"sync"
+
+	apiconfig "github.com/polarismesh/specification/source/go/api/v1/config_manage"
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	utils2 "github.com/polarismesh/polaris/config/utils"
+)
+
+const (
+	// streamNotifyQueueSize 单个 stream 上等待推送的变更通知数量，超出后关闭 stream，由客户端重连后按照版本号重新同步
+	streamNotifyQueueSize = 128
+)
+
+// WatchStream 基于 stream 的配置订阅会话，客户端在同一个 stream 上调整订阅的配置文件，
+// 配置发布后由 watchCenter 主动推送变更通知，不再需要每次变更都重新发起长轮询
+type WatchStream struct {
+	server     *Server
+	clientId   string
+	clientIP   string
+	lock       sync.Mutex
+	closed     bool
+	watchFiles map[string]*apiconfig.ClientConfigFileInfo // fileId -> file
+	notifyChan chan *apiconfig.ConfigClientResponse
+	doneChan   chan struct{}
+	doneOnce   sync.Once
+}
+
+// OpenWatchStream 创建一个 stream 订阅会话，stream 结束时需要调用 Close 释放订阅
+func (s *Server) OpenWatchStream(ctx context.Context) *WatchStream {
+	return &WatchStream{
+		server:     s,
+		clientId:   utils.ParseClientAddress(ctx) + "@" + utils.NewUUID()[0:8],
+		clientIP:   utils.ParseClientIP(ctx),
+		watchFiles: map[string]*apiconfig.ClientConfigFileInfo{},
+		notifyChan: make(chan *apiconfig.ConfigClientResponse, streamNotifyQueueSize),
+		doneChan:   make(chan struct{}),
+	}
+}
+
+// Subscribe 更新 stream 订阅的配置文件，请求中携带客户端当前订阅的全部配置文件：
+// 新出现的配置文件新增订阅，不再出现的配置文件取消订阅，客户端版本落后的配置文件立即推送变更通知
+func (ws *WatchStream) Subscribe(ctx context.Context,
+	request *apiconfig.ClientWatchConfigFileRequest) *apiconfig.ConfigClientResponse {
+	watchFiles := make(map[string]*apiconfig.ClientConfigFileInfo, len(request.GetWatchFiles()))
+	for _, file := range request.GetWatchFiles() {
+		namespace := file.GetNamespace().GetValue()
+		group := file.GetGroup().GetValue()
+		fileName := file.GetFileName().GetValue()
+		if namespace == "" || group == "" || fileName == "" {
+			return api.NewConfigClientResponseWithMessage(apimodel.Code_BadRequest,
+				"namespace & group & fileName can not be empty")
+		}
+		watchFiles[utils.GenFileId(namespace, group, fileName)] = file
+	}
+
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	if ws.closed {
+		return api.NewConfigClientResponseWithMessage(apimodel.Code_ExecuteException, "watch stream is closed")
+	}
+
+	removed := make([]*apiconfig.ClientConfigFileInfo, 0, len(ws.watchFiles))
+	for fileId, file := range ws.watchFiles {
+		if _, ok := watchFiles[fileId]; !ok {
+			removed = append(removed, file)
+		}
+	}
+	ws.server.WatchCenter().RemoveWatcher(ws.clientId, removed)
+
+	added := make([]*apiconfig.ClientConfigFileInfo, 0, len(watchFiles))
+	for _, file := range watchFiles {
+		added = append(added, file)
+	}
+	// 先注册订阅再检查版本，避免检查和注册之间发布的配置丢失通知
+	ws.server.WatchCenter().AddWatcher(ws.clientId, ws.clientIP, added, ws.notify)
+	ws.watchFiles = watchFiles
+
+	log.Info("[Config][Service] watch stream subscribe config files.", utils.ZapRequestIDByCtx(ctx),
+		zap.String("client-id", ws.clientId), zap.Int("watch-files", len(watchFiles)),
+		zap.Int("removed-files", len(removed)))
+
+	if resp := ws.resync(ctx, added); resp != nil {
+		return resp
+	}
+	return api.NewConfigClientResponse(apimodel.Code_ExecuteSuccess, nil)
+}
+
+// resync 客户端重连后按照版本号重新同步，版本落后的配置文件推送变更通知
+func (ws *WatchStream) resync(ctx context.Context,
+	watchFiles []*apiconfig.ClientConfigFileInfo) *apiconfig.ConfigClientResponse {
+	for _, file := range watchFiles {
+		namespace := file.GetNamespace().GetValue()
+		group := file.GetGroup().GetValue()
+		fileName := file.GetFileName().GetValue()
+
+		entry, err := ws.server.fileCache.GetOrLoadIfAbsent(namespace, group, fileName)
+		if err != nil {
+			log.Error("[Config][Service] get or load config file from cache error.", utils.ZapRequestIDByCtx(ctx),
+				utils.ZapNamespace(namespace), utils.ZapGroup(group), utils.ZapFileName(fileName), zap.Error(err))
+			return api.NewConfigClientResponse(apimodel.Code_ExecuteException, nil)
+		}
+		entry = entry.Select(newGrayClient(ws.clientIP, file))
+		if compareByVersion(file, entry) {
+			ws.notify(ws.clientId, utils2.GenConfigFileResponse(namespace, group, fileName, "",
+				entry.Md5, entry.Version, false, ""))
+		}
+	}
+	return nil
+}
+
+// notify 放入待推送队列，队列满时关闭 stream，客户端重连后重新同步，不阻塞 watchCenter 的事件处理
+func (ws *WatchStream) notify(clientId string, rsp *apiconfig.ConfigClientResponse) bool {
+	select {
+	case <-ws.doneChan:
+		return false
+	default:
+	}
+	select {
+	case ws.notifyChan <- rsp:
+		return true
+	default:
+		log.Warn("[Config][Service] watch stream notify queue is full, close stream.",
+			zap.String("client-id", clientId))
+		ws.doneOnce.Do(func() {
+			close(ws.doneChan)
+		})
+		return false
+	}
+}
+
+// Notifications 待推送给客户端的配置变更通知
+func (ws *WatchStream) Notifications() <-chan *apiconfig.ConfigClientResponse {
+	return ws.notifyChan
+}
+
+// Done stream 关闭或者因为推送积压被服务端关闭时触发
+func (ws *WatchStream) Done() <-chan struct{} {
+	return ws.doneChan
+}
+
+// Close 取消 stream 上的全部订阅
+func (ws *WatchStream) Close() {
+	ws.lock.Lock()
+	defer ws.lock.Unlock()
+
+	if ws.closed {
+		return
+	}
+	ws.closed = true
+	files := make([]*apiconfig.ClientConfigFileInfo, 0, len(ws.watchFiles))
+	for _, file := range ws.watchFiles {
+		files = append(files, file)
+	}
+	ws.server.WatchCenter().RemoveWatcher(ws.clientId, files)
+	ws.watchFiles = nil
+	ws.doneOnce.Do(func() {
+		close(ws.doneChan)
+	})
+}
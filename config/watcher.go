@@ -37,6 +37,8 @@ type FileReleaseCallback func(clientId string, rsp *apiconfig.ConfigClientRespon
 type watchContext struct {
 	fileReleaseCb FileReleaseCallback
 	ClientVersion uint64
+	// client 参与灰度规则匹配的客户端信息
+	client *model.GrayClient
 }
 
 // watchCenter 处理客户端订阅配置请求，监听配置文件发布事件通知客户端
@@ -44,7 +46,7 @@ type watchCenter struct {
 	eventCenter         *Center
 	configFileWatchers  *sync.Map // fileId -> clientId -> watchContext
 	lock                *sync.Mutex
-	releaseMessageQueue chan *model.ConfigFileRelease
+	releaseMessageQueue chan Event
 }
 
 // NewWatchCenter 创建一个客户端监听配置发布的处理中心
@@ -53,11 +55,15 @@ func NewWatchCenter(eventCenter *Center) *watchCenter {
 		eventCenter:         eventCenter,
 		configFileWatchers:  new(sync.Map),
 		lock:                new(sync.Mutex),
-		releaseMessageQueue: make(chan *model.ConfigFileRelease, QueueSize),
+		releaseMessageQueue: make(chan Event, QueueSize),
 	}
 
 	eventCenter.WatchEvent(eventTypePublishConfigFile, func(event Event) bool {
-		wc.releaseMessageQueue <- event.Message.(*model.ConfigFileRelease)
+		wc.releaseMessageQueue <- event
+		return true
+	})
+	eventCenter.WatchEvent(eventTypePublishConfigFileGray, func(event Event) bool {
+		wc.releaseMessageQueue <- event
 		return true
 	})
 
@@ -66,8 +72,8 @@ func NewWatchCenter(eventCenter *Center) *watchCenter {
 	return wc
 }
 
-// AddWatcher 新增订阅者
-func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*apiconfig.ClientConfigFileInfo,
+// AddWatcher 新增订阅者, clientIP 以及配置文件上报的标签用于灰度规则匹配
+func (wc *watchCenter) AddWatcher(clientId, clientIP string, watchConfigFiles []*apiconfig.ClientConfigFileInfo,
 	fileReleaseCb FileReleaseCallback) {
 	if len(watchConfigFiles) == 0 {
 		return
@@ -88,6 +94,7 @@ func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*apiconfig
 				newWatchers.Store(clientId, &watchContext{
 					fileReleaseCb: fileReleaseCb,
 					ClientVersion: file.Version.GetValue(),
+					client:        newGrayClient(clientIP, file),
 				})
 				wc.configFileWatchers.Store(watchFileId, newWatchers)
 			}
@@ -100,6 +107,7 @@ func (wc *watchCenter) AddWatcher(clientId string, watchConfigFiles []*apiconfig
 		watcherMap.Store(clientId, &watchContext{
 			fileReleaseCb: fileReleaseCb,
 			ClientVersion: file.Version.GetValue(),
+			client:        newGrayClient(clientIP, file),
 		})
 	}
 }
@@ -130,8 +138,13 @@ func (wc *watchCenter) handleMessage() {
 			}
 		}()
 
-		for message := range wc.releaseMessageQueue {
-			wc.notifyToWatchers(message)
+		for event := range wc.releaseMessageQueue {
+			switch message := event.Message.(type) {
+			case *model.ConfigFileRelease:
+				wc.notifyToWatchers(message)
+			case *model.ConfigFileGrayRelease:
+				wc.notifyToGrayWatchers(message)
+			}
 		}
 	}()
 }
@@ -158,7 +171,10 @@ func (wc *watchCenter) notifyToWatchers(publishConfigFile *model.ConfigFileRelea
 				zap.String("file", watchFileId),
 				zap.String("clientId", clientId.(string)),
 				zap.Uint64("version", publishConfigFile.Version))
-			c.fileReleaseCb(clientId.(string), response)
+			// 长连接订阅的客户端在推送成功后需要记录已经通知的版本，避免重复推送
+			if c.fileReleaseCb(clientId.(string), response) {
+				c.ClientVersion = publishConfigFile.Version
+			}
 		} else {
 			log.Info("[Config][Watcher] notify to client ignore.",
 				zap.String("file", watchFileId),
@@ -169,3 +185,53 @@ func (wc *watchCenter) notifyToWatchers(publishConfigFile *model.ConfigFileRelea
 		return true
 	})
 }
+
+// notifyToGrayWatchers 灰度发布只通知命中灰度规则的客户端
+func (wc *watchCenter) notifyToGrayWatchers(grayRelease *model.ConfigFileGrayRelease) {
+	watchFileId := utils.GenFileId(grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+
+	log.Info("[Config][Watcher] received config file gray publish message.", zap.String("file", watchFileId))
+
+	watchers, ok := wc.configFileWatchers.Load(watchFileId)
+	if !ok {
+		return
+	}
+
+	rule, err := model.ParseConfigFileGrayRule(grayRelease.Rule)
+	if err != nil {
+		log.Error("[Config][Watcher] parse config file gray rule error.", zap.String("file", watchFileId),
+			zap.Error(err))
+		return
+	}
+
+	response := utils2.GenConfigFileResponse(grayRelease.Namespace, grayRelease.Group,
+		grayRelease.FileName, "", grayRelease.Md5, grayRelease.Version, false, "")
+
+	watcherMap := watchers.(*sync.Map)
+	watcherMap.Range(func(clientId, watchCtx interface{}) bool {
+		c := watchCtx.(*watchContext)
+		if c.ClientVersion < grayRelease.Version && rule.Match(c.client) {
+			log.Info("[Config][Watcher] notify gray release to client.",
+				zap.String("file", watchFileId),
+				zap.String("clientId", clientId.(string)),
+				zap.Uint64("version", grayRelease.Version))
+			if c.fileReleaseCb(clientId.(string), response) {
+				c.ClientVersion = grayRelease.Version
+			}
+		}
+		return true
+	})
+}
+
+// newGrayClient 根据客户端地址以及客户端上报的配置文件标签，构建参与灰度规则匹配的客户端信息
+func newGrayClient(clientIP string, file *apiconfig.ClientConfigFileInfo) *model.GrayClient {
+	client := &model.GrayClient{
+		IP:     clientIP,
+		Labels: map[string]string{},
+	}
+	for _, tag := range file.GetTags() {
+		client.Labels[tag.GetKey().GetValue()] = tag.GetValue().GetValue()
+	}
+	client.ClientId = client.Labels[model.GrayClientIdLabel]
+	return client
+}
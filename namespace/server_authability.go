@@ -78,8 +78,9 @@ func (svr *serverAuthAbility) queryNamespaceResource(
 	for index := range nsArr {
 		ns := nsArr[index]
 		temp = append(temp, model.ResourceEntry{
-			ID:    ns.Name,
-			Owner: ns.Owner,
+			ID:        ns.Name,
+			Owner:     ns.Owner,
+			Namespace: ns.Name,
 		})
 	}
 
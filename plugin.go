@@ -29,10 +29,13 @@ import (
 	_ "github.com/polarismesh/polaris/plugin/cmdb/memory"
 	_ "github.com/polarismesh/polaris/plugin/crypto/aes"
 	_ "github.com/polarismesh/polaris/plugin/discoverevent/local"
+	_ "github.com/polarismesh/polaris/plugin/discoverevent/webhook"
 	_ "github.com/polarismesh/polaris/plugin/healthchecker/leader"
 	_ "github.com/polarismesh/polaris/plugin/healthchecker/memory"
+	_ "github.com/polarismesh/polaris/plugin/healthchecker/probe"
 	_ "github.com/polarismesh/polaris/plugin/healthchecker/redis"
 	_ "github.com/polarismesh/polaris/plugin/history/logger"
+	_ "github.com/polarismesh/polaris/plugin/history/storage"
 	_ "github.com/polarismesh/polaris/plugin/password"
 	_ "github.com/polarismesh/polaris/plugin/ratelimit/lrurate"
 	_ "github.com/polarismesh/polaris/plugin/ratelimit/token"
@@ -0,0 +1,197 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+// EventConfigFilePublish 配置文件发布事件类型
+const EventConfigFilePublish = "ConfigFilePublish"
+
+// defaultEvents 未配置事件类型时默认推送的事件
+var defaultEvents = []string{
+	string(model.EventInstanceOnline),
+	string(model.EventInstanceOffline),
+	string(model.EventInstanceTurnHealth),
+	string(model.EventInstanceTurnUnHealth),
+	string(model.EventInstanceOpenIsolate),
+	string(model.EventInstanceCloseIsolate),
+	string(model.EventServiceNoHealthyInstance),
+	EventConfigFilePublish,
+}
+
+// Config webhook 插件配置
+type Config struct {
+	// QueueSize 事件队列长度，队列满时丢弃新的事件
+	QueueSize int `mapstructure:"queueSize"`
+	// MaxBatchCount 单次推送的最大事件数
+	MaxBatchCount int `mapstructure:"maxBatchCount"`
+	// WaitTime 最长多久推送一次
+	WaitTime time.Duration `mapstructure:"waitTime"`
+	// Timeout 单次 HTTP 请求的超时时间
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxRetries 推送失败后的最大重试次数
+	MaxRetries int `mapstructure:"maxRetries"`
+	// RetryBackoff 首次重试的退避时间，之后每次翻倍
+	RetryBackoff time.Duration `mapstructure:"retryBackoff"`
+	// MaxRetryBackoff 重试退避时间的上限
+	MaxRetryBackoff time.Duration `mapstructure:"maxRetryBackoff"`
+	// DeadLetterPath 重试耗尽后事件的落盘文件
+	DeadLetterPath string `mapstructure:"deadLetterPath"`
+	// Events 推送的事件类型，endpoint 未配置时使用
+	Events []string `mapstructure:"events"`
+	// Endpoints 推送的目标地址
+	Endpoints []*EndpointConfig `mapstructure:"endpoints"`
+}
+
+// EndpointConfig 推送目标配置
+type EndpointConfig struct {
+	// Name 名称，用于日志以及死信记录
+	Name string `mapstructure:"name"`
+	// URL 推送地址
+	URL string `mapstructure:"url"`
+	// Secret HMAC-SHA256 签名密钥，为空则不签名
+	Secret string `mapstructure:"secret"`
+	// Headers 额外的请求头
+	Headers map[string]string `mapstructure:"headers"`
+	// Events 推送的事件类型，为空时使用全局配置
+	Events []string `mapstructure:"events"`
+	// Namespaces 只推送这些命名空间的事件，为空表示不过滤
+	Namespaces []string `mapstructure:"namespaces"`
+	// Services 只推送这些服务的实例事件，为空表示不过滤
+	Services []string `mapstructure:"services"`
+	// Groups 只推送这些配置分组的配置事件，为空表示不过滤
+	Groups []string `mapstructure:"groups"`
+
+	events     map[string]struct{}
+	namespaces map[string]struct{}
+	services   map[string]struct{}
+	groups     map[string]struct{}
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		QueueSize:       1024,
+		MaxBatchCount:   64,
+		WaitTime:        time.Second,
+		Timeout:         3 * time.Second,
+		MaxRetries:      3,
+		RetryBackoff:    500 * time.Millisecond,
+		MaxRetryBackoff: 10 * time.Second,
+		DeadLetterPath:  "./discover-event/webhook-dead-letter.log",
+	}
+}
+
+func parseConfig(option map[string]interface{}) (*Config, error) {
+	conf := DefaultConfig()
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     conf,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(option); err != nil {
+		return nil, err
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	for i, endpoint := range conf.Endpoints {
+		if endpoint.Name == "" {
+			endpoint.Name = fmt.Sprintf("endpoint-%d", i)
+		}
+		events := endpoint.Events
+		if len(events) == 0 {
+			events = conf.Events
+		}
+		if len(events) == 0 {
+			events = defaultEvents
+		}
+		endpoint.events = toSet(events)
+		endpoint.namespaces = toSet(endpoint.Namespaces)
+		endpoint.services = toSet(endpoint.Services)
+		endpoint.groups = toSet(endpoint.Groups)
+	}
+	return conf, nil
+}
+
+// Validate 检查配置是否正确配置
+func (c *Config) Validate() error {
+	if c.QueueSize <= 0 {
+		return errors.New("QueueSize is <= 0")
+	}
+	if c.MaxBatchCount <= 0 {
+		return errors.New("MaxBatchCount is <= 0")
+	}
+	if c.WaitTime <= 0 {
+		return errors.New("WaitTime is <= 0")
+	}
+	if c.MaxRetries < 0 {
+		return errors.New("MaxRetries is < 0")
+	}
+	if c.DeadLetterPath == "" {
+		return errors.New("DeadLetterPath is empty")
+	}
+	if len(c.Endpoints) == 0 {
+		return errors.New("Endpoints is empty")
+	}
+	for _, endpoint := range c.Endpoints {
+		u, err := url.Parse(endpoint.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint url(%s) is invalid", endpoint.URL)
+		}
+	}
+	return nil
+}
+
+// match 判断事件是否需要推送给该 endpoint
+func (e *EndpointConfig) match(event *webhookEvent) bool {
+	if !contains(e.events, event.Type) {
+		return false
+	}
+	if len(e.namespaces) > 0 && !contains(e.namespaces, event.Namespace) {
+		return false
+	}
+	if event.Type == EventConfigFilePublish {
+		return len(e.groups) == 0 || contains(e.groups, event.Group)
+	}
+	return len(e.services) == 0 || contains(e.services, event.Service)
+}
+
+func toSet(values []string) map[string]struct{} {
+	ret := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		ret[v] = struct{}{}
+	}
+	return ret
+}
+
+func contains(set map[string]struct{}, value string) bool {
+	_, ok := set[value]
+	return ok
+}
@@ -0,0 +1,233 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderTimestamp 推送时间戳，秒级，参与签名计算
+	HeaderTimestamp = "X-Polaris-Timestamp"
+	// HeaderSignature 请求签名，格式为 sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+	HeaderSignature = "X-Polaris-Signature"
+	// senderQueueSize 每个 endpoint 待推送的批次数
+	senderQueueSize = 64
+)
+
+// errNotRetryable 不需要重试的推送错误，例如 4xx
+type errNotRetryable struct {
+	err error
+}
+
+func (e *errNotRetryable) Error() string {
+	return e.err.Error()
+}
+
+// endpointSender 每个 endpoint 独立的推送协程，避免慢的 endpoint 影响其他 endpoint
+type endpointSender struct {
+	endpoint   *EndpointConfig
+	conf       *Config
+	client     *http.Client
+	deadLetter *deadLetterWriter
+	queue      chan *webhookPayload
+	closeOnce  sync.Once
+}
+
+func newEndpointSender(endpoint *EndpointConfig, conf *Config, client *http.Client,
+	deadLetter *deadLetterWriter) *endpointSender {
+	return &endpointSender{
+		endpoint:   endpoint,
+		conf:       conf,
+		client:     client,
+		deadLetter: deadLetter,
+		queue:      make(chan *webhookPayload, senderQueueSize),
+	}
+}
+
+func (s *endpointSender) submit(payload *webhookPayload) {
+	select {
+	case s.queue <- payload:
+	default:
+		s.deadLetter.write(s.endpoint, payload, errors.New("endpoint queue is full"))
+	}
+}
+
+func (s *endpointSender) close() {
+	s.closeOnce.Do(func() {
+		close(s.queue)
+	})
+}
+
+func (s *endpointSender) run(ctx context.Context) {
+	for payload := range s.queue {
+		s.deliver(ctx, payload)
+	}
+}
+
+// deliver 推送一个批次，失败后按照指数退避重试，重试耗尽后写入死信文件
+func (s *endpointSender) deliver(ctx context.Context, payload *webhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Errorf("[Webhook] marshal payload for endpoint(%s) err: %s", s.endpoint.Name, err.Error())
+		return
+	}
+	backoff := s.conf.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err = s.send(body)
+		if err == nil {
+			return
+		}
+		log.Warnf("[Webhook] send %d events to endpoint(%s) attempt %d err: %s", len(payload.Events),
+			s.endpoint.Name, attempt+1, err.Error())
+
+		var notRetryable *errNotRetryable
+		if errors.As(err, &notRetryable) || attempt >= s.conf.MaxRetries {
+			s.deadLetter.write(s.endpoint, payload, err)
+			return
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			s.deadLetter.write(s.endpoint, payload, fmt.Errorf("server is shutting down, last err: %w", err))
+			return
+		}
+		backoff *= 2
+		if s.conf.MaxRetryBackoff > 0 && backoff > s.conf.MaxRetryBackoff {
+			backoff = s.conf.MaxRetryBackoff
+		}
+	}
+}
+
+func (s *endpointSender) send(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return &errNotRetryable{err: err}
+	}
+	for k, v := range s.endpoint.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if s.endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(s.endpoint.Secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return err
+	}
+	return &errNotRetryable{err: err}
+}
+
+// Sign 计算推送请求的签名，接收方可以使用相同的方式校验请求来源
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deadLetterRecord 死信文件中的一行记录
+type deadLetterRecord struct {
+	Time     time.Time       `json:"time"`
+	Endpoint string          `json:"endpoint"`
+	URL      string          `json:"url"`
+	Error    string          `json:"error"`
+	Payload  *webhookPayload `json:"payload"`
+}
+
+// deadLetterWriter 将推送失败的批次按行追加到文件中，便于人工排查以及补偿
+type deadLetterWriter struct {
+	path string
+	lock sync.Mutex
+	file *os.File
+}
+
+func newDeadLetterWriter(path string) *deadLetterWriter {
+	return &deadLetterWriter{path: path}
+}
+
+func (d *deadLetterWriter) write(endpoint *EndpointConfig, payload *webhookPayload, cause error) {
+	line, err := json.Marshal(&deadLetterRecord{
+		Time:     time.Now(),
+		Endpoint: endpoint.Name,
+		URL:      endpoint.URL,
+		Error:    cause.Error(),
+		Payload:  payload,
+	})
+	if err != nil {
+		log.Errorf("[Webhook] marshal dead letter err: %s", err.Error())
+		return
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.file == nil {
+		if err := os.MkdirAll(filepath.Dir(d.path), 0755); err != nil {
+			log.Errorf("[Webhook] create dead letter dir err: %s", err.Error())
+			return
+		}
+		file, err := os.OpenFile(d.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			log.Errorf("[Webhook] open dead letter file err: %s", err.Error())
+			return
+		}
+		d.file = file
+	}
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		log.Errorf("[Webhook] write dead letter err: %s", err.Error())
+	}
+}
+
+func (d *deadLetterWriter) close() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.file == nil {
+		return nil
+	}
+	err := d.file.Close()
+	d.file = nil
+	return err
+}
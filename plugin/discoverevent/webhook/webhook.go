@@ -0,0 +1,279 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/polarismesh/polaris/common/eventhub"
+	commonlog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	PluginName = "discoverEventWebhook"
+)
+
+var log = commonlog.RegisterScope(PluginName, "", 0)
+
+func init() {
+	d := &discoverEventWebhook{}
+	plugin.RegisterPlugin(d.Name(), d)
+}
+
+// webhookEvent 推送给 webhook 的事件，实例事件以及配置发布事件共用
+type webhookEvent struct {
+	Type        string            `json:"type"`
+	Namespace   string            `json:"namespace"`
+	Service     string            `json:"service,omitempty"`
+	InstanceID  string            `json:"instance_id,omitempty"`
+	Host        string            `json:"host,omitempty"`
+	Port        uint32            `json:"port,omitempty"`
+	Healthy     *bool             `json:"healthy,omitempty"`
+	Isolate     *bool             `json:"isolate,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Group       string            `json:"group,omitempty"`
+	FileName    string            `json:"file_name,omitempty"`
+	ReleaseName string            `json:"release_name,omitempty"`
+	ReleaseType string            `json:"release_type,omitempty"`
+	Md5         string            `json:"md5,omitempty"`
+	Operator    string            `json:"operator,omitempty"`
+	Time        time.Time         `json:"time"`
+}
+
+// webhookPayload 单次推送的请求体
+type webhookPayload struct {
+	Server string          `json:"server"`
+	Events []*webhookEvent `json:"events"`
+}
+
+type discoverEventWebhook struct {
+	conf       *Config
+	eventCh    chan *webhookEvent
+	senders    []*endpointSender
+	deadLetter *deadLetterWriter
+	cancel     context.CancelFunc
+	waitStop   sync.WaitGroup
+}
+
+// Name 插件名称
+func (w *discoverEventWebhook) Name() string {
+	return PluginName
+}
+
+// Initialize 执行插件初始化
+func (w *discoverEventWebhook) Initialize(conf *plugin.ConfigEntry) error {
+	config, err := parseConfig(conf.Option)
+	if err != nil {
+		return err
+	}
+	w.conf = config
+	w.eventCh = make(chan *webhookEvent, config.QueueSize)
+	w.deadLetter = newDeadLetterWriter(config.DeadLetterPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	client := &http.Client{Timeout: config.Timeout}
+	for _, endpoint := range config.Endpoints {
+		sender := newEndpointSender(endpoint, config, client, w.deadLetter)
+		w.senders = append(w.senders, sender)
+		w.waitStop.Add(1)
+		go func() {
+			defer w.waitStop.Done()
+			sender.run(ctx)
+		}()
+	}
+	w.waitStop.Add(1)
+	go func() {
+		defer w.waitStop.Done()
+		w.run(ctx)
+	}()
+
+	return eventhub.Subscribe(eventhub.ConfigFilePublishTopic, PluginName, &configEventHandler{webhook: w})
+}
+
+// Destroy 执行插件销毁，队列中剩余的事件会尽量推送出去
+func (w *discoverEventWebhook) Destroy() error {
+	eventhub.Unsubscribe(eventhub.ConfigFilePublishTopic, PluginName)
+	if w.cancel != nil {
+		w.cancel()
+		w.waitStop.Wait()
+	}
+	if w.deadLetter != nil {
+		return w.deadLetter.close()
+	}
+	return nil
+}
+
+// PublishEvent 发布一个服务事件
+func (w *discoverEventWebhook) PublishEvent(event model.InstanceEvent) {
+	w.publish(instanceEventToWebhook(event))
+}
+
+func (w *discoverEventWebhook) publish(event *webhookEvent) {
+	select {
+	case w.eventCh <- event:
+	default:
+		log.Warnf("[Webhook] event queue is full, drop event %s %s/%s", event.Type, event.Namespace,
+			event.Service)
+	}
+}
+
+// run 聚合事件，按照 MaxBatchCount 或者 WaitTime 批量分发给各个 endpoint
+func (w *discoverEventWebhook) run(ctx context.Context) {
+	ticker := time.NewTicker(w.conf.WaitTime)
+	defer ticker.Stop()
+
+	batch := make([]*webhookEvent, 0, w.conf.MaxBatchCount)
+	for {
+		select {
+		case event := <-w.eventCh:
+			if !w.subscribed(event) {
+				continue
+			}
+			batch = append(batch, event)
+			if len(batch) >= w.conf.MaxBatchCount {
+				w.dispatch(batch)
+				batch = make([]*webhookEvent, 0, w.conf.MaxBatchCount)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.dispatch(batch)
+				batch = make([]*webhookEvent, 0, w.conf.MaxBatchCount)
+			}
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-w.eventCh:
+					if w.subscribed(event) {
+						batch = append(batch, event)
+					}
+				default:
+					if len(batch) > 0 {
+						w.dispatch(batch)
+					}
+					for _, sender := range w.senders {
+						sender.close()
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// subscribed 没有任何 endpoint 关心的事件直接丢弃，例如心跳事件
+func (w *discoverEventWebhook) subscribed(event *webhookEvent) bool {
+	for _, sender := range w.senders {
+		if contains(sender.endpoint.events, event.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *discoverEventWebhook) dispatch(batch []*webhookEvent) {
+	for _, sender := range w.senders {
+		events := make([]*webhookEvent, 0, len(batch))
+		for _, event := range batch {
+			if sender.endpoint.match(event) {
+				events = append(events, event)
+			}
+		}
+		for len(events) > 0 {
+			size := len(events)
+			if size > w.conf.MaxBatchCount {
+				size = w.conf.MaxBatchCount
+			}
+			sender.submit(&webhookPayload{Server: utils.LocalHost, Events: events[:size]})
+			events = events[size:]
+		}
+	}
+}
+
+func instanceEventToWebhook(event model.InstanceEvent) *webhookEvent {
+	ret := &webhookEvent{
+		Type:       string(event.EType),
+		Namespace:  event.Namespace,
+		Service:    event.Service,
+		InstanceID: event.Id,
+		Metadata:   event.MetaData,
+		Time:       event.CreateTime,
+	}
+	if ins := event.Instance; ins != nil {
+		if ret.Namespace == "" {
+			ret.Namespace = ins.GetNamespace().GetValue()
+		}
+		if ret.Service == "" {
+			ret.Service = ins.GetService().GetValue()
+		}
+		ret.Host = ins.GetHost().GetValue()
+		ret.Port = ins.GetPort().GetValue()
+		if ins.GetHealthy() != nil {
+			healthy := ins.GetHealthy().GetValue()
+			ret.Healthy = &healthy
+		}
+		if ins.GetIsolate() != nil {
+			isolate := ins.GetIsolate().GetValue()
+			ret.Isolate = &isolate
+		}
+	}
+	if ret.Time.IsZero() {
+		ret.Time = time.Now()
+	}
+	return ret
+}
+
+// configEventHandler 订阅 eventhub 中的配置发布事件
+type configEventHandler struct {
+	webhook *discoverEventWebhook
+}
+
+// PreProcess do preprocess logic for event
+func (h *configEventHandler) PreProcess(_ context.Context, value any) any {
+	return value
+}
+
+// OnEvent event process logic
+func (h *configEventHandler) OnEvent(_ context.Context, value any) error {
+	event, ok := value.(*model.ConfigFilePublishEvent)
+	if !ok {
+		return nil
+	}
+	createTime := event.CreateTime
+	if createTime.IsZero() {
+		createTime = time.Now()
+	}
+	h.webhook.publish(&webhookEvent{
+		Type:        EventConfigFilePublish,
+		Namespace:   event.Namespace,
+		Group:       event.Group,
+		FileName:    event.FileName,
+		ReleaseName: event.ReleaseName,
+		ReleaseType: event.ReleaseType,
+		Md5:         event.Md5,
+		Operator:    event.Operator,
+		Time:        createTime,
+	})
+	return nil
+}
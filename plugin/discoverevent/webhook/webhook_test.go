@@ -0,0 +1,268 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+type receivedRequest struct {
+	header  http.Header
+	body    []byte
+	payload *webhookPayload
+}
+
+func newTestReceiver(t *testing.T, status func(count int32) int) (*httptest.Server, func() []*receivedRequest) {
+	var (
+		lock     sync.Mutex
+		requests []*receivedRequest
+		count    int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		payload := &webhookPayload{}
+		assert.NoError(t, json.Unmarshal(body, payload))
+
+		lock.Lock()
+		requests = append(requests, &receivedRequest{header: r.Header.Clone(), body: body, payload: payload})
+		lock.Unlock()
+		w.WriteHeader(status(atomic.AddInt32(&count, 1)))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []*receivedRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]*receivedRequest{}, requests...)
+	}
+}
+
+func newTestWebhook(t *testing.T, option map[string]interface{}) *discoverEventWebhook {
+	w := &discoverEventWebhook{}
+	assert.NoError(t, w.Initialize(&plugin.ConfigEntry{Name: PluginName, Option: option}))
+	return w
+}
+
+func mockInstanceEvent(service string, eventType model.InstanceEventType) model.InstanceEvent {
+	return model.InstanceEvent{
+		Id:        "ins-" + service,
+		Namespace: "default",
+		Service:   service,
+		EType:     eventType,
+		Instance: &apiservice.Instance{
+			Host:    &wrappers.StringValue{Value: "127.0.0.1"},
+			Port:    &wrappers.UInt32Value{Value: 8080},
+			Healthy: &wrappers.BoolValue{Value: false},
+		},
+	}
+}
+
+func Test_discoverEventWebhook_Publish(t *testing.T) {
+	server, received := newTestReceiver(t, func(int32) int { return http.StatusOK })
+	w := newTestWebhook(t, map[string]interface{}{
+		"waitTime":       "50ms",
+		"deadLetterPath": filepath.Join(t.TempDir(), "dead-letter.log"),
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"name":     "oncall",
+				"url":      server.URL,
+				"secret":   "polaris",
+				"services": []interface{}{"echo"},
+				"events": []interface{}{
+					string(model.EventInstanceTurnUnHealth),
+					string(model.EventServiceNoHealthyInstance),
+				},
+			},
+		},
+	})
+
+	w.PublishEvent(mockInstanceEvent("echo", model.EventInstanceTurnUnHealth))
+	w.PublishEvent(mockInstanceEvent("echo", model.EventServiceNoHealthyInstance))
+	// 不匹配服务过滤条件以及事件类型过滤条件的事件不推送
+	w.PublishEvent(mockInstanceEvent("other", model.EventInstanceTurnUnHealth))
+	w.PublishEvent(mockInstanceEvent("echo", model.EventInstanceSendHeartbeat))
+
+	assert.Eventually(t, func() bool {
+		return len(received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, w.Destroy())
+
+	req := received()[0]
+	assert.Equal(t, 2, len(req.payload.Events))
+	assert.Equal(t, string(model.EventInstanceTurnUnHealth), req.payload.Events[0].Type)
+	assert.Equal(t, string(model.EventServiceNoHealthyInstance), req.payload.Events[1].Type)
+	assert.Equal(t, "127.0.0.1", req.payload.Events[0].Host)
+	assert.Equal(t, uint32(8080), req.payload.Events[0].Port)
+
+	timestamp := req.header.Get(HeaderTimestamp)
+	assert.NotEmpty(t, timestamp)
+	assert.Equal(t, Sign("polaris", timestamp, req.body), req.header.Get(HeaderSignature))
+}
+
+func Test_discoverEventWebhook_ConfigEvent(t *testing.T) {
+	server, received := newTestReceiver(t, func(int32) int { return http.StatusOK })
+	w := newTestWebhook(t, map[string]interface{}{
+		"waitTime":       "50ms",
+		"deadLetterPath": filepath.Join(t.TempDir(), "dead-letter.log"),
+		"endpoints": []interface{}{
+			map[string]interface{}{
+				"url":        server.URL,
+				"namespaces": []interface{}{"default"},
+				"groups":     []interface{}{"app"},
+			},
+		},
+	})
+	handler := &configEventHandler{webhook: w}
+	assert.NoError(t, handler.OnEvent(context.Background(), &model.ConfigFilePublishEvent{
+		Namespace:   "default",
+		Group:       "app",
+		FileName:    "app.yaml",
+		ReleaseType: "normal",
+		Operator:    "polaris",
+	}))
+	assert.NoError(t, handler.OnEvent(context.Background(), &model.ConfigFilePublishEvent{
+		Namespace: "default",
+		Group:     "other",
+		FileName:  "app.yaml",
+	}))
+
+	assert.Eventually(t, func() bool {
+		return len(received()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, w.Destroy())
+
+	events := received()[0].payload.Events
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, EventConfigFilePublish, events[0].Type)
+	assert.Equal(t, "app.yaml", events[0].FileName)
+	assert.Equal(t, "polaris", events[0].Operator)
+	assert.Empty(t, received()[0].header.Get(HeaderSignature))
+}
+
+func Test_discoverEventWebhook_Retry(t *testing.T) {
+	t.Run("重试后推送成功", func(t *testing.T) {
+		server, received := newTestReceiver(t, func(count int32) int {
+			if count < 3 {
+				return http.StatusServiceUnavailable
+			}
+			return http.StatusOK
+		})
+		deadLetterPath := filepath.Join(t.TempDir(), "dead-letter.log")
+		w := newTestWebhook(t, map[string]interface{}{
+			"waitTime":       "10ms",
+			"retryBackoff":   "10ms",
+			"maxRetries":     3,
+			"deadLetterPath": deadLetterPath,
+			"endpoints":      []interface{}{map[string]interface{}{"url": server.URL}},
+		})
+		w.PublishEvent(mockInstanceEvent("echo", model.EventInstanceOffline))
+
+		assert.Eventually(t, func() bool {
+			return len(received()) == 3
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.NoError(t, w.Destroy())
+		_, err := os.Stat(deadLetterPath)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("重试耗尽写入死信文件", func(t *testing.T) {
+		server, received := newTestReceiver(t, func(int32) int { return http.StatusInternalServerError })
+		deadLetterPath := filepath.Join(t.TempDir(), "webhook", "dead-letter.log")
+		w := newTestWebhook(t, map[string]interface{}{
+			"waitTime":       "10ms",
+			"retryBackoff":   "10ms",
+			"maxRetries":     2,
+			"deadLetterPath": deadLetterPath,
+			"endpoints":      []interface{}{map[string]interface{}{"name": "oncall", "url": server.URL}},
+		})
+		w.PublishEvent(mockInstanceEvent("echo", model.EventInstanceOffline))
+
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(deadLetterPath)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.NoError(t, w.Destroy())
+		assert.Equal(t, 3, len(received()))
+
+		file, err := os.Open(deadLetterPath)
+		assert.NoError(t, err)
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		assert.True(t, scanner.Scan())
+		record := &deadLetterRecord{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), record))
+		assert.Equal(t, "oncall", record.Endpoint)
+		assert.Equal(t, 1, len(record.Payload.Events))
+	})
+
+	t.Run("4xx不重试", func(t *testing.T) {
+		server, received := newTestReceiver(t, func(int32) int { return http.StatusBadRequest })
+		deadLetterPath := filepath.Join(t.TempDir(), "dead-letter.log")
+		w := newTestWebhook(t, map[string]interface{}{
+			"waitTime":       "10ms",
+			"retryBackoff":   "10ms",
+			"deadLetterPath": deadLetterPath,
+			"endpoints":      []interface{}{map[string]interface{}{"url": server.URL}},
+		})
+		w.PublishEvent(mockInstanceEvent("echo", model.EventInstanceOffline))
+
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(deadLetterPath)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.NoError(t, w.Destroy())
+		assert.Equal(t, 1, len(received()))
+	})
+}
+
+func Test_parseConfig(t *testing.T) {
+	_, err := parseConfig(map[string]interface{}{})
+	assert.Error(t, err)
+
+	_, err = parseConfig(map[string]interface{}{
+		"endpoints": []interface{}{map[string]interface{}{"url": "ftp://127.0.0.1"}},
+	})
+	assert.Error(t, err)
+
+	conf, err := parseConfig(map[string]interface{}{
+		"events":    []interface{}{string(model.EventInstanceOffline)},
+		"endpoints": []interface{}{map[string]interface{}{"url": "http://127.0.0.1:8080/hook"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "endpoint-0", conf.Endpoints[0].Name)
+	assert.True(t, conf.Endpoints[0].match(&webhookEvent{Type: string(model.EventInstanceOffline)}))
+	assert.False(t, conf.Endpoints[0].match(&webhookEvent{Type: string(model.EventInstanceOnline)}))
+}
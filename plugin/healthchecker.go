@@ -36,6 +36,8 @@ type CheckRequest struct {
 	QueryRequest
 	ExpireDurationSec uint32
 	CurTimeSec        func() int64
+	// Metadata instance metadata, used by the checkers which probe the instance actively
+	Metadata map[string]string
 }
 
 // CheckResponse check heartbeat response
@@ -73,10 +75,13 @@ type HealthCheckType int32
 
 const (
 	HealthCheckerHeartbeat HealthCheckType = iota + 1
+	// HealthCheckerProbe the server probes the instances actively by http/tcp/grpc
+	HealthCheckerProbe
 )
 
 var (
-	healthCheckOnce = &sync.Once{}
+	healthCheckOnce = map[string]*sync.Once{}
+	healthCheckLock = &sync.Mutex{}
 )
 
 // HealthChecker health checker plugin interface
@@ -104,6 +109,16 @@ type HealthChecker interface {
 	DebugHandlers() []DebugHandler
 }
 
+// IntervalHealthChecker the health checker which decides the check interval of the instance by itself,
+// instead of the expire duration derived from heartbeat ttl
+type IntervalHealthChecker interface {
+	HealthChecker
+	// CheckIntervalSec return the check interval in seconds for the instance
+	CheckIntervalSec(metadata map[string]string) uint32
+	// IsCheckEnable whether the instance metadata declares a valid target for this checker
+	IsCheckEnable(metadata map[string]string) bool
+}
+
 // GetHealthChecker get the health checker by name
 func GetHealthChecker(name string, cfg *ConfigEntry) HealthChecker {
 	plugin, exist := pluginSet[name]
@@ -111,7 +126,15 @@ func GetHealthChecker(name string, cfg *ConfigEntry) HealthChecker {
 		return nil
 	}
 
-	healthCheckOnce.Do(func() {
+	healthCheckLock.Lock()
+	initOnce, ok := healthCheckOnce[name]
+	if !ok {
+		initOnce = &sync.Once{}
+		healthCheckOnce[name] = initOnce
+	}
+	healthCheckLock.Unlock()
+
+	initOnce.Do(func() {
 		if err := plugin.Initialize(cfg); err != nil {
 			log.Errorf("HealthChecker plugin init err: %s", err.Error())
 			os.Exit(-1)
@@ -0,0 +1,227 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	commonLog "github.com/polarismesh/polaris/common/log"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+const (
+	// PluginName plugin name
+	PluginName = "probe"
+)
+
+var log = commonLog.GetScopeOrDefaultByName(commonLog.HealthcheckLoggerName)
+
+// ProbeRecord record for the probe results of an instance
+type ProbeRecord struct {
+	// LastProbeSec last probe time in seconds
+	LastProbeSec int64
+	// LastSuccessSec last successful probe time in seconds
+	LastSuccessSec int64
+	// SuccessCount continuous success count
+	SuccessCount uint32
+	// FailureCount continuous failure count
+	FailureCount uint32
+}
+
+// ProbeHealthChecker health checker probes the instances by http/tcp/grpc actively,
+// the instance declares the probe options in its metadata
+type ProbeHealthChecker struct {
+	conf           *Config
+	records        *sync.Map
+	probers        map[string]prober
+	suspendTimeSec int64
+}
+
+// Name return plugin name
+func (r *ProbeHealthChecker) Name() string {
+	return PluginName
+}
+
+// Initialize initialize plugin
+func (r *ProbeHealthChecker) Initialize(c *plugin.ConfigEntry) error {
+	conf, err := unmarshal(c.Option)
+	if err != nil {
+		return err
+	}
+	r.conf = conf
+	r.records = &sync.Map{}
+	r.probers = probers
+	return nil
+}
+
+// Destroy plugin destruction
+func (r *ProbeHealthChecker) Destroy() error {
+	return nil
+}
+
+// Type for health check plugin, only one same type plugin is allowed
+func (r *ProbeHealthChecker) Type() plugin.HealthCheckType {
+	return plugin.HealthCheckerProbe
+}
+
+// IsCheckEnable whether the instance metadata declares a supported probe protocol, the instance must also
+// declare the health check type model.HealthCheckProbe
+func (r *ProbeHealthChecker) IsCheckEnable(metadata map[string]string) bool {
+	return isProbeDeclared(metadata)
+}
+
+// CheckIntervalSec return the probe interval in seconds for the instance
+func (r *ProbeHealthChecker) CheckIntervalSec(metadata map[string]string) uint32 {
+	t, err := parseTarget("", 0, metadata, r.conf)
+	if err != nil {
+		return uint32(r.conf.Interval.Seconds())
+	}
+	return uint32(t.interval.Seconds())
+}
+
+// Report heartbeat is not necessary for the instance probed actively, just ignore it
+func (r *ProbeHealthChecker) Report(ctx context.Context, request *plugin.ReportRequest) error {
+	return nil
+}
+
+// Query queries the last successful probe time
+func (r *ProbeHealthChecker) Query(ctx context.Context, request *plugin.QueryRequest) (*plugin.QueryResponse, error) {
+	value, ok := r.records.Load(request.InstanceId)
+	if !ok {
+		return &plugin.QueryResponse{
+			LastHeartbeatSec: 0,
+		}, nil
+	}
+	record := value.(ProbeRecord)
+	return &plugin.QueryResponse{
+		Exists:           true,
+		LastHeartbeatSec: record.LastSuccessSec,
+		Count:            int64(record.SuccessCount),
+	}, nil
+}
+
+func (r *ProbeHealthChecker) skipCheck(instanceId string, expireDurationSec int64) bool {
+	suspendTimeSec := r.SuspendTimeSec()
+	localCurTimeSec := commontime.CurrentMillisecond() / 1000
+	if suspendTimeSec > 0 && localCurTimeSec >= suspendTimeSec && localCurTimeSec-suspendTimeSec < expireDurationSec {
+		log.Infof("[Health Check][ProbeCheck]health check probe suspended, "+
+			"suspendTimeSec is %d, localCurTimeSec is %d, expireDurationSec is %d, instanceId %s",
+			suspendTimeSec, localCurTimeSec, expireDurationSec, instanceId)
+		return true
+	}
+	return false
+}
+
+// Check probe the instance, the health status changes only when the continuous success or failure count
+// reaches the threshold
+func (r *ProbeHealthChecker) Check(request *plugin.CheckRequest) (*plugin.CheckResponse, error) {
+	curTimeSec := request.CurTimeSec()
+	checkResp := &plugin.CheckResponse{
+		Healthy:              request.Healthy,
+		LastHeartbeatTimeSec: curTimeSec,
+		Regular:              true,
+	}
+	if r.skipCheck(request.InstanceId, int64(request.ExpireDurationSec)) {
+		checkResp.StayUnchanged = true
+		return checkResp, nil
+	}
+	t, err := parseTarget(request.Host, request.Port, request.Metadata, r.conf)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	probeErr := r.probers[t.protocol](ctx, t)
+	cancel()
+
+	var record ProbeRecord
+	if value, ok := r.records.Load(request.InstanceId); ok {
+		record = value.(ProbeRecord)
+	}
+	record.LastProbeSec = curTimeSec
+	if probeErr == nil {
+		record.LastSuccessSec = curTimeSec
+		record.SuccessCount++
+		record.FailureCount = 0
+	} else {
+		record.SuccessCount = 0
+		record.FailureCount++
+		log.Debugf("[Health Check][ProbeCheck]probe %s %s failed, instanceId %s, err %v",
+			t.protocol, t.address(), request.InstanceId, probeErr)
+	}
+	r.records.Store(request.InstanceId, record)
+
+	switch {
+	case !request.Healthy && record.SuccessCount >= t.healthyThreshold:
+		log.Infof("[Health Check][ProbeCheck]health check resumed, protocol %s, address %s, "+
+			"success count %d, instanceId %s", t.protocol, t.address(), record.SuccessCount, request.InstanceId)
+		checkResp.Healthy = true
+	case request.Healthy && record.FailureCount >= t.unhealthyThreshold:
+		log.Infof("[Health Check][ProbeCheck]health check failed, protocol %s, address %s, "+
+			"failure count %d, instanceId %s, err %v", t.protocol, t.address(), record.FailureCount,
+			request.InstanceId, probeErr)
+		checkResp.Healthy = false
+	default:
+		checkResp.StayUnchanged = true
+	}
+	return checkResp, nil
+}
+
+// AddToCheck add the instances to check procedure
+func (r *ProbeHealthChecker) AddToCheck(request *plugin.AddCheckRequest) error {
+	return nil
+}
+
+// RemoveFromCheck removes the instances from check procedure
+func (r *ProbeHealthChecker) RemoveFromCheck(request *plugin.AddCheckRequest) error {
+	for _, id := range request.Instances {
+		r.records.Delete(id)
+	}
+	return nil
+}
+
+// Delete delete the id
+func (r *ProbeHealthChecker) Delete(ctx context.Context, id string) error {
+	r.records.Delete(id)
+	return nil
+}
+
+// Suspend suspend the checker for entire expired duration
+func (r *ProbeHealthChecker) Suspend() {
+	curTimeSec := commontime.CurrentMillisecond() / 1000
+	log.Infof("[Health Check][ProbeCheck] suspend checker, start time %d", curTimeSec)
+	atomic.StoreInt64(&r.suspendTimeSec, curTimeSec)
+}
+
+// SuspendTimeSec get suspend time in seconds
+func (r *ProbeHealthChecker) SuspendTimeSec() int64 {
+	return atomic.LoadInt64(&r.suspendTimeSec)
+}
+
+// DebugHandlers return debug handlers
+func (r *ProbeHealthChecker) DebugHandlers() []plugin.DebugHandler {
+	return []plugin.DebugHandler{}
+}
+
+func init() {
+	d := &ProbeHealthChecker{}
+	plugin.RegisterPlugin(d.Name(), d)
+}
@@ -0,0 +1,200 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+func newTestChecker(t *testing.T, probeErr *error) *ProbeHealthChecker {
+	conf, err := unmarshal(map[string]interface{}{
+		"interval":           "3s",
+		"healthyThreshold":   2,
+		"unhealthyThreshold": 2,
+	})
+	assert.Nil(t, err)
+	return &ProbeHealthChecker{
+		conf:    conf,
+		records: &sync.Map{},
+		probers: map[string]prober{
+			ProtocolHTTP: func(ctx context.Context, t *target) error {
+				return *probeErr
+			},
+		},
+	}
+}
+
+func newCheckRequest(healthy bool) *plugin.CheckRequest {
+	return &plugin.CheckRequest{
+		QueryRequest: plugin.QueryRequest{
+			InstanceId: "key",
+			Host:       "127.0.0.1",
+			Port:       8080,
+			Healthy:    healthy,
+		},
+		CurTimeSec: func() int64 {
+			return time.Now().Unix()
+		},
+		ExpireDurationSec: 3,
+		Metadata: map[string]string{
+			model.MetaKeyHealthCheckProtocol: ProtocolHTTP,
+			model.MetaKeyHealthCheckPath:     "/health",
+		},
+	}
+}
+
+func TestProbeHealthChecker_Check(t *testing.T) {
+	var probeErr error
+	checker := newTestChecker(t, &probeErr)
+
+	// unhealthy instance turns healthy after 2 continuous success
+	resp, err := checker.Check(newCheckRequest(false))
+	assert.Nil(t, err)
+	assert.True(t, resp.StayUnchanged)
+	assert.False(t, resp.Healthy)
+	resp, err = checker.Check(newCheckRequest(false))
+	assert.Nil(t, err)
+	assert.False(t, resp.StayUnchanged)
+	assert.True(t, resp.Healthy)
+	assert.True(t, resp.Regular)
+
+	qr, err := checker.Query(context.Background(), &plugin.QueryRequest{InstanceId: "key"})
+	assert.Nil(t, err)
+	assert.True(t, qr.LastHeartbeatSec > 0)
+
+	// healthy instance turns unhealthy after 2 continuous failure
+	probeErr = errors.New("connection refused")
+	resp, err = checker.Check(newCheckRequest(true))
+	assert.Nil(t, err)
+	assert.True(t, resp.StayUnchanged)
+	assert.True(t, resp.Healthy)
+	resp, err = checker.Check(newCheckRequest(true))
+	assert.Nil(t, err)
+	assert.False(t, resp.StayUnchanged)
+	assert.False(t, resp.Healthy)
+
+	// success resets the failure count
+	probeErr = nil
+	resp, err = checker.Check(newCheckRequest(true))
+	assert.Nil(t, err)
+	assert.True(t, resp.StayUnchanged)
+	probeErr = errors.New("connection refused")
+	resp, err = checker.Check(newCheckRequest(true))
+	assert.Nil(t, err)
+	assert.True(t, resp.StayUnchanged)
+	assert.True(t, resp.Healthy)
+}
+
+func TestProbeHealthChecker_Suspend(t *testing.T) {
+	probeErr := errors.New("connection refused")
+	checker := newTestChecker(t, &probeErr)
+	checker.Suspend()
+	for i := 0; i < 3; i++ {
+		resp, err := checker.Check(newCheckRequest(true))
+		assert.Nil(t, err)
+		assert.True(t, resp.StayUnchanged)
+		assert.True(t, resp.Healthy)
+	}
+}
+
+func TestProbeHealthChecker_CheckIntervalSec(t *testing.T) {
+	var probeErr error
+	checker := newTestChecker(t, &probeErr)
+	metadata := map[string]string{model.MetaKeyHealthCheckProtocol: ProtocolTCP}
+	assert.True(t, checker.IsCheckEnable(metadata))
+	assert.Equal(t, uint32(3), checker.CheckIntervalSec(metadata))
+	metadata[model.MetaKeyHealthCheckInterval] = "10s"
+	assert.Equal(t, uint32(10), checker.CheckIntervalSec(metadata))
+	assert.False(t, checker.IsCheckEnable(map[string]string{model.MetaKeyHealthCheckProtocol: "udp"}))
+	assert.False(t, checker.IsCheckEnable(nil))
+}
+
+func TestParseTarget(t *testing.T) {
+	conf, err := unmarshal(map[string]interface{}{})
+	assert.Nil(t, err)
+	assert.Equal(t, defaultInterval, conf.Interval)
+
+	target, err := parseTarget("127.0.0.1", 8080, map[string]string{
+		model.MetaKeyHealthCheckProtocol:           "HTTP",
+		model.MetaKeyHealthCheckPort:               "9090",
+		model.MetaKeyHealthCheckPath:               "health",
+		model.MetaKeyHealthCheckTimeout:            "500ms",
+		model.MetaKeyHealthCheckHealthyThreshold:   "3",
+		model.MetaKeyHealthCheckUnhealthyThreshold: "5",
+	}, conf)
+	assert.Nil(t, err)
+	assert.Equal(t, ProtocolHTTP, target.protocol)
+	assert.Equal(t, "127.0.0.1:9090", target.address())
+	assert.Equal(t, "/health", target.path)
+	assert.Equal(t, 500*time.Millisecond, target.timeout)
+	assert.Equal(t, uint32(3), target.healthyThreshold)
+	assert.Equal(t, uint32(5), target.unhealthyThreshold)
+
+	_, err = parseTarget("127.0.0.1", 8080, map[string]string{
+		model.MetaKeyHealthCheckProtocol: ProtocolTCP,
+		model.MetaKeyHealthCheckPort:     "abc",
+	}, conf)
+	assert.NotNil(t, err)
+	_, err = parseTarget("127.0.0.1", 8080, map[string]string{
+		model.MetaKeyHealthCheckProtocol: ProtocolTCP,
+		model.MetaKeyHealthCheckInterval: "100ms",
+	}, conf)
+	assert.NotNil(t, err)
+}
+
+func TestProbeHTTPAndTCP(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer svr.Close()
+	u, err := url.Parse(svr.URL)
+	assert.Nil(t, err)
+	host, portStr, err := net.SplitHostPort(u.Host)
+	assert.Nil(t, err)
+	port, _ := strconv.Atoi(portStr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	target := &target{protocol: ProtocolHTTP, host: host, port: uint32(port), path: "/health"}
+	assert.Nil(t, probeHTTP(ctx, target))
+	target.path = "/down"
+	assert.NotNil(t, probeHTTP(ctx, target))
+
+	target.protocol = ProtocolTCP
+	assert.Nil(t, probeTCP(ctx, target))
+	svr.Close()
+	assert.NotNil(t, probeTCP(ctx, target))
+}
@@ -0,0 +1,79 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package probe
+
+import (
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	defaultInterval           = 5 * time.Second
+	defaultTimeout            = 2 * time.Second
+	defaultHealthyThreshold   = 1
+	defaultUnhealthyThreshold = 3
+)
+
+// Config probe health checker config, can be overwritten by the instance metadata
+type Config struct {
+	// Interval probe interval
+	Interval time.Duration `json:"interval"`
+	// Timeout timeout for a single probe
+	Timeout time.Duration `json:"timeout"`
+	// HealthyThreshold continuous success count to turn the instance healthy
+	HealthyThreshold uint32 `json:"healthyThreshold"`
+	// UnhealthyThreshold continuous failure count to turn the instance unhealthy
+	UnhealthyThreshold uint32 `json:"unhealthyThreshold"`
+}
+
+func (c *Config) setDefault() {
+	if c.Interval < time.Second {
+		c.Interval = defaultInterval
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.Timeout > c.Interval {
+		c.Timeout = c.Interval
+	}
+	if c.HealthyThreshold == 0 {
+		c.HealthyThreshold = defaultHealthyThreshold
+	}
+	if c.UnhealthyThreshold == 0 {
+		c.UnhealthyThreshold = defaultUnhealthyThreshold
+	}
+}
+
+func unmarshal(options map[string]interface{}) (*Config, error) {
+	config := &Config{}
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		Result:           config,
+		TagName:          "json",
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(options); err != nil {
+		return nil, err
+	}
+	config.setDefault()
+	return config, nil
+}
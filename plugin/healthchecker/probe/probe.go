@@ -0,0 +1,202 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	// ProtocolHTTP probe the instance by http get request, 2xx and 3xx are treated as success
+	ProtocolHTTP = "http"
+	// ProtocolTCP probe the instance by tcp connect
+	ProtocolTCP = "tcp"
+	// ProtocolGRPC probe the instance by grpc.health.v1.Health/Check
+	ProtocolGRPC = "grpc"
+)
+
+// target the probe target and options of an instance
+type target struct {
+	protocol           string
+	host               string
+	port               uint32
+	path               string
+	grpcService        string
+	interval           time.Duration
+	timeout            time.Duration
+	healthyThreshold   uint32
+	unhealthyThreshold uint32
+}
+
+func (t *target) address() string {
+	return net.JoinHostPort(t.host, strconv.FormatUint(uint64(t.port), 10))
+}
+
+// prober probes the target, return nil when the target is healthy
+type prober func(ctx context.Context, t *target) error
+
+var probers = map[string]prober{
+	ProtocolHTTP: probeHTTP,
+	ProtocolTCP:  probeTCP,
+	ProtocolGRPC: probeGRPC,
+}
+
+// isProbeDeclared whether the instance declares active probing in its metadata
+func isProbeDeclared(metadata map[string]string) bool {
+	protocol := strings.ToLower(metadata[model.MetaKeyHealthCheckProtocol])
+	_, ok := probers[protocol]
+	return ok
+}
+
+// parseTarget parse the probe target from instance address and metadata, use config as default value
+func parseTarget(host string, port uint32, metadata map[string]string, conf *Config) (*target, error) {
+	t := &target{
+		protocol:           strings.ToLower(metadata[model.MetaKeyHealthCheckProtocol]),
+		host:               host,
+		port:               port,
+		path:               metadata[model.MetaKeyHealthCheckPath],
+		grpcService:        metadata[model.MetaKeyHealthCheckGrpcService],
+		interval:           conf.Interval,
+		timeout:            conf.Timeout,
+		healthyThreshold:   conf.HealthyThreshold,
+		unhealthyThreshold: conf.UnhealthyThreshold,
+	}
+	if _, ok := probers[t.protocol]; !ok {
+		return nil, fmt.Errorf("unsupported probe protocol %q", t.protocol)
+	}
+	if value, ok := metadata[model.MetaKeyHealthCheckPort]; ok {
+		probePort, err := strconv.ParseUint(value, 10, 16)
+		if err != nil || probePort == 0 {
+			return nil, fmt.Errorf("invalid probe port %q", value)
+		}
+		t.port = uint32(probePort)
+	}
+	if len(t.path) == 0 {
+		t.path = "/"
+	}
+	if !strings.HasPrefix(t.path, "/") {
+		t.path = "/" + t.path
+	}
+	if value, ok := metadata[model.MetaKeyHealthCheckInterval]; ok {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < time.Second {
+			return nil, fmt.Errorf("invalid probe interval %q", value)
+		}
+		t.interval = interval
+	}
+	if value, ok := metadata[model.MetaKeyHealthCheckTimeout]; ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid probe timeout %q", value)
+		}
+		t.timeout = timeout
+	}
+	if t.timeout > t.interval {
+		t.timeout = t.interval
+	}
+	var err error
+	if t.healthyThreshold, err = parseThreshold(metadata,
+		model.MetaKeyHealthCheckHealthyThreshold, t.healthyThreshold); err != nil {
+		return nil, err
+	}
+	if t.unhealthyThreshold, err = parseThreshold(metadata,
+		model.MetaKeyHealthCheckUnhealthyThreshold, t.unhealthyThreshold); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func parseThreshold(metadata map[string]string, key string, defaultValue uint32) (uint32, error) {
+	value, ok := metadata[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	threshold, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || threshold == 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, value)
+	}
+	return uint32(threshold), nil
+}
+
+func probeHTTP(ctx context.Context, t *target) error {
+	url := fmt.Sprintf("http://%s%s", t.address(), t.path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "polaris-health-check")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("http probe %s return status code %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// httpClient do not follow the redirects, 3xx is treated as success
+var httpClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+	Transport: &http.Transport{
+		DisableKeepAlives: true,
+	},
+}
+
+func probeTCP(ctx context.Context, t *target) error {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", t.address())
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func probeGRPC(ctx context.Context, t *target) error {
+	conn, err := grpc.DialContext(ctx, t.address(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: t.grpcService,
+	})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("grpc probe %s return status %s", t.address(), resp.GetStatus())
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+
+	commonLog "github.com/polarismesh/polaris/common/log"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/store"
+)
+
+// 把操作记录持久化到存储层中，便于按照条件检索
+const (
+	// PluginName plugin name
+	PluginName = "HistoryStorage"
+)
+
+var log = commonLog.RegisterScope(PluginName, "", 0)
+
+// init 初始化注册函数
+func init() {
+	plugin.RegisterPlugin(PluginName, &HistoryStorage{})
+}
+
+// Config 插件配置
+type Config struct {
+	// QueueSize 待写入的操作记录队列长度，队列满时丢弃新的记录，避免阻塞业务请求
+	QueueSize int `mapstructure:"queueSize"`
+	// MaxBatchCount 每次批量写入的最大记录数
+	MaxBatchCount int `mapstructure:"maxBatchCount"`
+	// WaitTime 最长多久批量写入一次
+	WaitTime time.Duration `mapstructure:"waitTime"`
+}
+
+// DefaultConfig 默认的插件配置
+func DefaultConfig() *Config {
+	return &Config{
+		QueueSize:     10240,
+		MaxBatchCount: 128,
+		WaitTime:      time.Second,
+	}
+}
+
+// fixDefault 非法的配置值回退为默认值，避免创建 ticker 或队列时 panic
+func (c *Config) fixDefault() {
+	def := DefaultConfig()
+	if c.QueueSize <= 0 {
+		c.QueueSize = def.QueueSize
+	}
+	if c.MaxBatchCount <= 0 {
+		c.MaxBatchCount = def.MaxBatchCount
+	}
+	if c.WaitTime <= 0 {
+		c.WaitTime = def.WaitTime
+	}
+}
+
+// HistoryStorage 历史记录存储
+type HistoryStorage struct {
+	conf     *Config
+	storage  store.Store
+	entries  chan *model.RecordEntry
+	cancel   context.CancelFunc
+	waitStop sync.WaitGroup
+}
+
+// Name 返回插件名字
+func (h *HistoryStorage) Name() string {
+	return PluginName
+}
+
+// Initialize 插件初始化
+func (h *HistoryStorage) Initialize(c *plugin.ConfigEntry) error {
+	conf := DefaultConfig()
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.StringToTimeDurationHookFunc(),
+		Result:     conf,
+	})
+	if err != nil {
+		return err
+	}
+	if err := decoder.Decode(c.Option); err != nil {
+		return err
+	}
+	conf.fixDefault()
+	h.conf = conf
+
+	if h.storage == nil {
+		storage, err := store.GetStore()
+		if err != nil {
+			return err
+		}
+		h.storage = storage
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	h.entries = make(chan *model.RecordEntry, conf.QueueSize)
+	h.waitStop.Add(1)
+	go h.run(ctx)
+	return nil
+}
+
+// Destroy 销毁插件，退出前将队列中剩余的记录写入存储层
+func (h *HistoryStorage) Destroy() error {
+	if h.cancel != nil {
+		h.cancel()
+		h.waitStop.Wait()
+	}
+	return nil
+}
+
+// Record 将操作记录放入队列，异步批量写入存储层
+func (h *HistoryStorage) Record(entry *model.RecordEntry) {
+	entry.Server = utils.LocalHost
+	select {
+	case h.entries <- entry:
+	default:
+		log.Warnf("[History][Storage] queue is full, drop record: %s", entry.String())
+	}
+}
+
+func (h *HistoryStorage) run(ctx context.Context) {
+	defer h.waitStop.Done()
+
+	ticker := time.NewTicker(h.conf.WaitTime)
+	defer ticker.Stop()
+
+	batch := make([]*model.RecordEntry, 0, h.conf.MaxBatchCount)
+	for {
+		select {
+		case entry := <-h.entries:
+			batch = append(batch, entry)
+			if len(batch) >= h.conf.MaxBatchCount {
+				batch = h.flush(batch)
+			}
+		case <-ticker.C:
+			batch = h.flush(batch)
+		case <-ctx.Done():
+			for {
+				select {
+				case entry := <-h.entries:
+					batch = append(batch, entry)
+					if len(batch) >= h.conf.MaxBatchCount {
+						batch = h.flush(batch)
+					}
+				default:
+					h.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (h *HistoryStorage) flush(batch []*model.RecordEntry) []*model.RecordEntry {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := h.storage.AddOperationRecords(batch); err != nil {
+		log.Errorf("[History][Storage] save %d records err: %s", len(batch), err.Error())
+	}
+	return batch[:0]
+}
@@ -0,0 +1,115 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package storage
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+	"github.com/polarismesh/polaris/store/mock"
+)
+
+func TestHistoryStorage_Record(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		lock  sync.Mutex
+		saved []*model.RecordEntry
+	)
+	mockStore := mock.NewMockStore(ctrl)
+	mockStore.EXPECT().AddOperationRecords(gomock.Any()).DoAndReturn(func(records []*model.RecordEntry) error {
+		lock.Lock()
+		defer lock.Unlock()
+		assert.LessOrEqual(t, len(records), 2)
+		saved = append(saved, records...)
+		return nil
+	}).AnyTimes()
+
+	h := &HistoryStorage{storage: mockStore}
+	err := h.Initialize(&plugin.ConfigEntry{
+		Name: PluginName,
+		Option: map[string]interface{}{
+			"maxBatchCount": 2,
+			"waitTime":      "10ms",
+		},
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		h.Record(&model.RecordEntry{
+			ResourceType:  model.RRouting,
+			ResourceName:  "echo",
+			Namespace:     "default",
+			Operator:      "polaris",
+			OperationType: model.OUpdate,
+			HappenTime:    time.Now(),
+		})
+	}
+	assert.NoError(t, h.Destroy())
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(t, 5, len(saved))
+	for _, record := range saved {
+		assert.NotEmpty(t, record.Server)
+	}
+}
+
+func TestHistoryStorage_QueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := &HistoryStorage{
+		conf:    DefaultConfig(),
+		storage: mock.NewMockStore(ctrl),
+		entries: make(chan *model.RecordEntry, 1),
+	}
+	h.Record(&model.RecordEntry{ResourceType: model.RService})
+	// 队列已满时直接丢弃，不能阻塞调用方
+	h.Record(&model.RecordEntry{ResourceType: model.RService})
+	assert.Equal(t, 1, len(h.entries))
+}
+
+func TestHistoryStorage_InvalidConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := &HistoryStorage{storage: mock.NewMockStore(ctrl)}
+	err := h.Initialize(&plugin.ConfigEntry{
+		Name: PluginName,
+		Option: map[string]interface{}{
+			"queueSize":     -1,
+			"maxBatchCount": 0,
+			"waitTime":      "0s",
+		},
+	})
+	assert.NoError(t, err)
+	defer h.Destroy()
+
+	def := DefaultConfig()
+	assert.Equal(t, def.QueueSize, h.conf.QueueSize)
+	assert.Equal(t, def.MaxBatchCount, h.conf.MaxBatchCount)
+	assert.Equal(t, def.WaitTime, h.conf.WaitTime)
+}
@@ -0,0 +1,300 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// supportedAccessMethods 访问控制策略支持的 http 方法
+var supportedAccessMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodHead:    {},
+	http.MethodPost:    {},
+	http.MethodPut:     {},
+	http.MethodPatch:   {},
+	http.MethodDelete:  {},
+	http.MethodConnect: {},
+	http.MethodOptions: {},
+	http.MethodTrace:   {},
+}
+
+// AccessPolicy 访问控制策略
+type AccessPolicy struct {
+	ServicePolicy
+	DryRun bool                    `json:"dry_run"`
+	Spec   *model.AccessPolicySpec `json:"spec"`
+}
+
+// AccessPolicyResponse 访问控制策略操作结果
+type AccessPolicyResponse struct {
+	Code   uint32        `json:"code"`
+	Info   string        `json:"info"`
+	Policy *AccessPolicy `json:"policy,omitempty"`
+}
+
+// NewAccessPolicyResponse 创建访问控制策略操作结果
+func NewAccessPolicyResponse(code apimodel.Code, policy *model.AccessPolicy) *AccessPolicyResponse {
+	return &AccessPolicyResponse{
+		Code:   uint32(code),
+		Info:   api.Code2Info(uint32(code)),
+		Policy: accessPolicy2Api(policy),
+	}
+}
+
+// NewAccessPolicyResponseWithMessage 创建带有错误信息的访问控制策略操作结果
+func NewAccessPolicyResponseWithMessage(code apimodel.Code, message string) *AccessPolicyResponse {
+	return &AccessPolicyResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + message,
+	}
+}
+
+// AccessPolicyBatchResponse 访问控制策略查询结果
+type AccessPolicyBatchResponse struct {
+	Code     uint32          `json:"code"`
+	Info     string          `json:"info"`
+	Total    uint32          `json:"total"`
+	Size     uint32          `json:"size"`
+	Policies []*AccessPolicy `json:"policies"`
+}
+
+// NewAccessPolicyBatchResponse 创建访问控制策略查询结果
+func NewAccessPolicyBatchResponse(code apimodel.Code, total uint32,
+	policies []*model.AccessPolicy) *AccessPolicyBatchResponse {
+	ret := &AccessPolicyBatchResponse{
+		Code:     uint32(code),
+		Info:     api.Code2Info(uint32(code)),
+		Total:    total,
+		Size:     uint32(len(policies)),
+		Policies: make([]*AccessPolicy, 0, len(policies)),
+	}
+	for _, policy := range policies {
+		ret.Policies = append(ret.Policies, accessPolicy2Api(policy))
+	}
+	return ret
+}
+
+// CreateAccessPolicy 创建访问控制策略，同一个服务下的策略名称不能重复
+func (s *Server) CreateAccessPolicy(ctx context.Context, req *AccessPolicy) *AccessPolicyResponse {
+	if resp := s.checkAccessPolicy(req); resp != nil {
+		return resp
+	}
+	if resp := s.checkAccessPolicyConflict(ctx, req); resp != nil {
+		return resp
+	}
+
+	policy, err := api2AccessPolicy(req)
+	if err != nil {
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+	}
+	policy.ID = utils.NewUUID()
+	policy.Revision = utils.NewUUID()
+	if err := s.storage.CreateAccessPolicy(policy); err != nil {
+		log.Error("[Service][AccessPolicy] create access policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+
+	log.Info("[Service][AccessPolicy] create access policy", utils.ZapRequestIDByCtx(ctx),
+		zap.String("id", policy.ID), zap.String("namespace", policy.Namespace),
+		zap.String("service", policy.Service), zap.Bool("dry-run", policy.DryRun))
+	s.RecordHistory(ctx, accessPolicyRecordEntry(ctx, policy, model.OCreate))
+	return NewAccessPolicyResponse(apimodel.Code_ExecuteSuccess, policy)
+}
+
+// UpdateAccessPolicy 更新访问控制策略
+func (s *Server) UpdateAccessPolicy(ctx context.Context, req *AccessPolicy) *AccessPolicyResponse {
+	if req == nil || req.ID == "" {
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_BadRequest, "id is required")
+	}
+	if resp := s.checkAccessPolicy(req); resp != nil {
+		return resp
+	}
+	saved, err := s.storage.GetAccessPolicyWithID(req.ID)
+	if err != nil {
+		log.Error("[Service][AccessPolicy] get access policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+	if saved == nil {
+		return NewAccessPolicyResponse(apimodel.Code_NotFoundResource, nil)
+	}
+	if resp := s.checkAccessPolicyConflict(ctx, req); resp != nil {
+		return resp
+	}
+
+	policy, err := api2AccessPolicy(req)
+	if err != nil {
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+	}
+	policy.Revision = utils.NewUUID()
+	policy.CreateTime = saved.CreateTime
+	if err := s.storage.UpdateAccessPolicy(policy); err != nil {
+		log.Error("[Service][AccessPolicy] update access policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+
+	log.Info("[Service][AccessPolicy] update access policy", utils.ZapRequestIDByCtx(ctx),
+		zap.String("id", policy.ID))
+	s.RecordHistory(ctx, accessPolicyRecordEntry(ctx, policy, model.OUpdate))
+	return NewAccessPolicyResponse(apimodel.Code_ExecuteSuccess, policy)
+}
+
+// DeleteAccessPolicy 删除访问控制策略
+func (s *Server) DeleteAccessPolicy(ctx context.Context, id string) *AccessPolicyResponse {
+	if id == "" {
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_BadRequest, "id is required")
+	}
+	saved, err := s.storage.GetAccessPolicyWithID(id)
+	if err != nil {
+		log.Error("[Service][AccessPolicy] get access policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+	if saved == nil {
+		return NewAccessPolicyResponse(apimodel.Code_ExecuteSuccess, nil)
+	}
+	if err := s.storage.DeleteAccessPolicy(id); err != nil {
+		log.Error("[Service][AccessPolicy] delete access policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+
+	log.Info("[Service][AccessPolicy] delete access policy", utils.ZapRequestIDByCtx(ctx), zap.String("id", id))
+	s.RecordHistory(ctx, accessPolicyRecordEntry(ctx, saved, model.ODelete))
+	return NewAccessPolicyResponse(apimodel.Code_ExecuteSuccess, saved)
+}
+
+// GetAccessPolicies 查询访问控制策略，支持 id、name、namespace 以及 service 过滤
+func (s *Server) GetAccessPolicies(ctx context.Context, query map[string]string) *AccessPolicyBatchResponse {
+	filter, offset, limit, err := parseServicePolicyQuery(query)
+	if err != nil {
+		return &AccessPolicyBatchResponse{
+			Code: uint32(apimodel.Code_InvalidParameter),
+			Info: api.Code2Info(uint32(apimodel.Code_InvalidParameter)) + ":" + err.Error(),
+		}
+	}
+	total, policies, err := s.storage.GetAccessPolicies(filter, offset, limit)
+	if err != nil {
+		log.Error("[Service][AccessPolicy] get access policies", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewAccessPolicyBatchResponse(apimodel.Code_StoreLayerException, 0, nil)
+	}
+	for _, policy := range policies {
+		if err := policy.ParseSpec(); err != nil {
+			log.Error("[Service][AccessPolicy] parse access policy rule", zap.String("id", policy.ID),
+				zap.Error(err))
+		}
+	}
+	return NewAccessPolicyBatchResponse(apimodel.Code_ExecuteSuccess, total, policies)
+}
+
+// checkAccessPolicy 检查访问控制策略的参数，策略所属的服务需要存在
+func (s *Server) checkAccessPolicy(req *AccessPolicy) *AccessPolicyResponse {
+	if req == nil {
+		return NewAccessPolicyResponse(apimodel.Code_EmptyRequest, nil)
+	}
+	if code, message := s.checkServicePolicy(&req.ServicePolicy); code != apimodel.Code_ExecuteSuccess {
+		if message == "" {
+			return NewAccessPolicyResponse(code, nil)
+		}
+		return NewAccessPolicyResponseWithMessage(code, message)
+	}
+	if err := checkAccessPolicySpec(req.Spec); err != nil {
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_BadRequest, err.Error())
+	}
+	return nil
+}
+
+// checkAccessPolicyConflict 策略名称会作为 envoy rbac 的策略名，同一个服务下不允许重复
+func (s *Server) checkAccessPolicyConflict(ctx context.Context, req *AccessPolicy) *AccessPolicyResponse {
+	_, policies, err := s.storage.GetAccessPolicies(map[string]string{
+		"namespace": req.Namespace,
+		"service":   req.Service,
+	}, 0, utils.MaxBatchSize)
+	if err != nil {
+		log.Error("[Service][AccessPolicy] get access policies", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewAccessPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+	for _, policy := range policies {
+		if policy.ID != req.ID && policy.Name == req.Name {
+			return NewAccessPolicyResponseWithMessage(apimodel.Code_ExistedResource,
+				fmt.Sprintf("access policy %s already exists", policy.Name))
+		}
+	}
+	return nil
+}
+
+func checkAccessPolicySpec(spec *model.AccessPolicySpec) error {
+	if spec == nil || len(spec.Sources) == 0 {
+		return fmt.Errorf("sources is required")
+	}
+	for _, source := range spec.Sources {
+		if source == nil || source.Namespace == "" || source.Service == "" {
+			return fmt.Errorf("source namespace and service are required")
+		}
+		if source.Namespace == utils.MatchAll && source.Service != utils.MatchAll {
+			return fmt.Errorf("source service must be * when namespace is *")
+		}
+	}
+	for _, path := range spec.Paths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("path must start with /: %s", path)
+		}
+	}
+	for _, method := range spec.Methods {
+		if _, ok := supportedAccessMethods[method]; !ok {
+			return fmt.Errorf("unsupported method: %s", method)
+		}
+	}
+	return nil
+}
+
+func api2AccessPolicy(req *AccessPolicy) (*model.AccessPolicy, error) {
+	policy, err := req.ServicePolicy.toModel(req.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AccessPolicy{
+		ServicePolicy: policy,
+		DryRun:        req.DryRun,
+		Spec:          req.Spec,
+	}, nil
+}
+
+func accessPolicy2Api(policy *model.AccessPolicy) *AccessPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &AccessPolicy{
+		ServicePolicy: newServicePolicy(&policy.ServicePolicy),
+		DryRun:        policy.DryRun,
+		Spec:          policy.Spec,
+	}
+}
+
+func accessPolicyRecordEntry(ctx context.Context, policy *model.AccessPolicy,
+	opt model.OperationType) *model.RecordEntry {
+	return servicePolicyRecordEntry(ctx, model.RAccessPolicy, &policy.ServicePolicy, opt)
+}
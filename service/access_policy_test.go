@@ -0,0 +1,111 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service_test
+
+import (
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/service"
+)
+
+func buildAccessPolicy(svcName, namespace, name string) *service.AccessPolicy {
+	return &service.AccessPolicy{
+		ServicePolicy: service.ServicePolicy{
+			Name:      name,
+			Namespace: namespace,
+			Service:   svcName,
+			Enable:    true,
+		},
+		Spec: &model.AccessPolicySpec{
+			Sources: []*model.AccessSource{{Namespace: namespace, Service: "frontend"}},
+			Paths:   []string{"/api/*"},
+			Methods: []string{"GET", "POST"},
+		},
+	}
+}
+
+func TestAccessPolicy(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	_, svc := discoverSuit.createCommonService(t, 101)
+	defer discoverSuit.cleanServiceName(svc.GetName().GetValue(), svc.GetNamespace().GetValue())
+	svcName, namespace := svc.GetName().GetValue(), svc.GetNamespace().GetValue()
+
+	t.Run("创建、更新以及删除访问控制策略，返回成功", func(t *testing.T) {
+		resp := discoverSuit.DiscoverServer().CreateAccessPolicy(discoverSuit.DefaultCtx,
+			buildAccessPolicy(svcName, namespace, "allow-frontend"))
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.Code, resp.Info)
+		assert.NotEmpty(t, resp.Policy.ID)
+
+		// 同一个服务下策略名称不能重复
+		dresp := discoverSuit.DiscoverServer().CreateAccessPolicy(discoverSuit.DefaultCtx,
+			buildAccessPolicy(svcName, namespace, "allow-frontend"))
+		assert.Equal(t, uint32(apimodel.Code_ExistedResource), dresp.Code)
+
+		policy := buildAccessPolicy(svcName, namespace, "allow-frontend")
+		policy.ID = resp.Policy.ID
+		policy.DryRun = true
+		uresp := discoverSuit.DiscoverServer().UpdateAccessPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), uresp.Code, uresp.Info)
+
+		qresp := discoverSuit.DiscoverServer().GetAccessPolicies(discoverSuit.DefaultCtx, map[string]string{
+			"namespace": namespace,
+			"service":   svcName,
+		})
+		assert.Equal(t, uint32(1), qresp.Total)
+		assert.True(t, qresp.Policies[0].DryRun)
+		assert.Equal(t, "frontend", qresp.Policies[0].Spec.Sources[0].Service)
+
+		delResp := discoverSuit.DiscoverServer().DeleteAccessPolicy(discoverSuit.DefaultCtx, policy.ID)
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), delResp.Code, delResp.Info)
+		qresp = discoverSuit.DiscoverServer().GetAccessPolicies(discoverSuit.DefaultCtx, map[string]string{
+			"id": policy.ID,
+		})
+		assert.Equal(t, uint32(0), qresp.Total)
+	})
+
+	t.Run("创建访问控制策略，参数非法，返回错误", func(t *testing.T) {
+		policy := buildAccessPolicy(svcName, namespace, "bad")
+		policy.Spec.Sources = nil
+		resp := discoverSuit.DiscoverServer().CreateAccessPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), resp.Code)
+
+		policy = buildAccessPolicy(svcName, namespace, "bad")
+		policy.Spec.Sources = []*model.AccessSource{{Namespace: "*", Service: "frontend"}}
+		resp = discoverSuit.DiscoverServer().CreateAccessPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), resp.Code)
+
+		policy = buildAccessPolicy(svcName, namespace, "bad")
+		policy.Spec.Methods = []string{"get"}
+		resp = discoverSuit.DiscoverServer().CreateAccessPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), resp.Code)
+
+		policy = buildAccessPolicy(svcName, namespace, "bad")
+		policy.Spec.Paths = []string{"api"}
+		resp = discoverSuit.DiscoverServer().CreateAccessPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), resp.Code)
+	})
+}
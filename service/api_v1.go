@@ -239,6 +239,9 @@ type ClientServer interface {
 
 	// UpdateInstance update one instance by client
 	UpdateInstance(ctx context.Context, req *apiservice.Instance) *apiservice.Response
+
+	// OpenDiscoverStream Open a subscription session, the server pushes data to client when the cache revision changes
+	OpenDiscoverStream(ctx context.Context) *DiscoverStream
 }
 
 // L5OperateServer L5 related operations
@@ -283,6 +286,30 @@ type FaultDetectRuleOperateServer interface {
 	GetFaultDetectRules(ctx context.Context, query map[string]string) *apiservice.BatchQueryResponse
 }
 
+// TrafficPolicyOperateServer Traffic policy related operations
+type TrafficPolicyOperateServer interface {
+	// CreateTrafficPolicy create the traffic policy by request
+	CreateTrafficPolicy(ctx context.Context, req *TrafficPolicy) *TrafficPolicyResponse
+	// UpdateTrafficPolicy update the traffic policy by request
+	UpdateTrafficPolicy(ctx context.Context, req *TrafficPolicy) *TrafficPolicyResponse
+	// DeleteTrafficPolicy delete the traffic policy by id
+	DeleteTrafficPolicy(ctx context.Context, id string) *TrafficPolicyResponse
+	// GetTrafficPolicies get the traffic policies by query
+	GetTrafficPolicies(ctx context.Context, query map[string]string) *TrafficPolicyBatchResponse
+}
+
+// AccessPolicyOperateServer Access policy related operations
+type AccessPolicyOperateServer interface {
+	// CreateAccessPolicy create the access policy by request
+	CreateAccessPolicy(ctx context.Context, req *AccessPolicy) *AccessPolicyResponse
+	// UpdateAccessPolicy update the access policy by request
+	UpdateAccessPolicy(ctx context.Context, req *AccessPolicy) *AccessPolicyResponse
+	// DeleteAccessPolicy delete the access policy by id
+	DeleteAccessPolicy(ctx context.Context, id string) *AccessPolicyResponse
+	// GetAccessPolicies get the access policies by query
+	GetAccessPolicies(ctx context.Context, query map[string]string) *AccessPolicyBatchResponse
+}
+
 type DiscoverServerV1 interface {
 	// CircuitBreakerOperateServer Fuse rule operation interface definition
 	CircuitBreakerOperateServer
@@ -294,4 +321,8 @@ type DiscoverServerV1 interface {
 	RouterRuleOperateServer
 	// FaultDetectRuleOperateServer fault detect rules operation interface definition
 	FaultDetectRuleOperateServer
+	// TrafficPolicyOperateServer traffic policy operation interface definition
+	TrafficPolicyOperateServer
+	// AccessPolicyOperateServer access policy operation interface definition
+	AccessPolicyOperateServer
 }
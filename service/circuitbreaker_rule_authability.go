@@ -32,8 +32,7 @@ import (
 func (svr *serverAuthAbility) CreateCircuitBreakerRules(
 	ctx context.Context, request []*apifault.CircuitBreakerRule) *apiservice.BatchWriteResponse {
 
-	// TODO not support CircuitBreaker resource auth, so we set op is read
-	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Read, "CreateCircuitBreakerRules")
+	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Create, "CreateCircuitBreakerRules")
 
 	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
 	if err != nil {
@@ -48,7 +47,7 @@ func (svr *serverAuthAbility) CreateCircuitBreakerRules(
 func (svr *serverAuthAbility) DeleteCircuitBreakerRules(
 	ctx context.Context, request []*apifault.CircuitBreakerRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Read, "DeleteCircuitBreakerRules")
+	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Delete, "DeleteCircuitBreakerRules")
 
 	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
 	if err != nil {
@@ -63,7 +62,7 @@ func (svr *serverAuthAbility) DeleteCircuitBreakerRules(
 func (svr *serverAuthAbility) EnableCircuitBreakerRules(
 	ctx context.Context, request []*apifault.CircuitBreakerRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Read, "EnableCircuitBreakerRules")
+	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Modify, "EnableCircuitBreakerRules")
 
 	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
 	if err != nil {
@@ -78,7 +77,7 @@ func (svr *serverAuthAbility) EnableCircuitBreakerRules(
 func (svr *serverAuthAbility) UpdateCircuitBreakerRules(
 	ctx context.Context, request []*apifault.CircuitBreakerRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Read, "UpdateCircuitBreakerRules")
+	authCtx := svr.collectCircuitBreakerRuleV2AuthContext(ctx, request, model.Modify, "UpdateCircuitBreakerRules")
 
 	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
 	if err != nil {
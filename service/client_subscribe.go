@@ -0,0 +1,459 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"sync"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/cache"
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+const (
+	// discoverStreamNotifyQueueSize 单个 stream 上等待推送的数据数量，超出后关闭 stream，由客户端重连后重新订阅
+	discoverStreamNotifyQueueSize = 128
+)
+
+// subscribableTypes 支持订阅推送的资源类型
+var subscribableTypes = map[apiservice.DiscoverRequest_DiscoverRequestType]cache.CacheName{
+	apiservice.DiscoverRequest_INSTANCE:        cache.CacheNameInstance,
+	apiservice.DiscoverRequest_ROUTING:         cache.CacheNameRoutingConfig,
+	apiservice.DiscoverRequest_RATE_LIMIT:      cache.CacheNameRateLimit,
+	apiservice.DiscoverRequest_CIRCUIT_BREAKER: cache.CacheNameCircuitBreaker,
+}
+
+// discoverSubscription stream 上对一个服务某类资源的订阅
+type discoverSubscription struct {
+	stream  *DiscoverStream
+	reqType apiservice.DiscoverRequest_DiscoverRequestType
+	// service 客户端订阅的服务，可能是服务别名
+	service model.ServiceKey
+	// watchKey 监听变化的服务，服务别名的实例以源服务为准
+	watchKey model.ServiceKey
+	// revision 最后一次下发给客户端的数据版本
+	revision string
+}
+
+// subscribeCenter 管理客户端的服务订阅，服务实例的 revision 变化或者规则缓存更新后，
+// 重新计算订阅的数据，数据版本发生变化时推送给客户端
+type subscribeCenter struct {
+	caches *cache.CacheManager
+
+	lock sync.RWMutex
+	// subscriptions 资源类型 -> 服务 -> 订阅
+	subscriptions map[apiservice.DiscoverRequest_DiscoverRequestType]map[model.ServiceKey]map[*discoverSubscription]struct{}
+	// serviceKeys service id -> 服务，服务删除后缓存中查不到服务时使用
+	serviceKeys map[string]model.ServiceKey
+
+	dirtyLock     sync.Mutex
+	dirtyServices map[model.ServiceKey]struct{}
+	dirtyTypes    map[apiservice.DiscoverRequest_DiscoverRequestType]struct{}
+	signal        chan struct{}
+}
+
+func newSubscribeCenter(caches *cache.CacheManager) *subscribeCenter {
+	sc := &subscribeCenter{
+		caches:        caches,
+		subscriptions: map[apiservice.DiscoverRequest_DiscoverRequestType]map[model.ServiceKey]map[*discoverSubscription]struct{}{},
+		serviceKeys:   map[string]model.ServiceKey{},
+		dirtyServices: map[model.ServiceKey]struct{}{},
+		dirtyTypes:    map[apiservice.DiscoverRequest_DiscoverRequestType]struct{}{},
+		signal:        make(chan struct{}, 1),
+	}
+
+	caches.AddRevisionListener(sc.onRevisionChanged)
+	for reqType, cacheName := range subscribableTypes {
+		if reqType == apiservice.DiscoverRequest_INSTANCE {
+			continue
+		}
+		caches.AddListener(cacheName, []cache.Listener{&ruleUpdateListener{reqType: reqType, center: sc}})
+	}
+
+	go sc.run()
+	return sc
+}
+
+// subscribeCenter 第一次使用订阅推送时才开始监听缓存的变化，没有开启缓存时不支持订阅
+func (s *Server) subscribeCenter() *subscribeCenter {
+	if s.caches == nil {
+		return nil
+	}
+	s.subscribeOnce.Do(func() {
+		s.subscriber = newSubscribeCenter(s.caches)
+	})
+	return s.subscriber
+}
+
+// onRevisionChanged 服务实例 revision 的计算是异步的，以计算完成后的 revision 变化作为推送的依据
+func (sc *subscribeCenter) onRevisionChanged(serviceID string, _ string) {
+	var (
+		key model.ServiceKey
+		ok  bool
+	)
+	if svc := sc.caches.Service().GetServiceByID(serviceID); svc != nil {
+		key = model.ServiceKey{Namespace: svc.Namespace, Name: svc.Name}
+		ok = true
+	} else {
+		sc.lock.RLock()
+		key, ok = sc.serviceKeys[serviceID]
+		sc.lock.RUnlock()
+	}
+	if !ok {
+		return
+	}
+
+	sc.dirtyLock.Lock()
+	sc.dirtyServices[key] = struct{}{}
+	sc.dirtyLock.Unlock()
+	sc.wakeup()
+}
+
+// onRulesUpdated 规则缓存没有按照服务维度通知，规则更新后重新检查该类型的全部订阅
+func (sc *subscribeCenter) onRulesUpdated(reqType apiservice.DiscoverRequest_DiscoverRequestType) {
+	sc.dirtyLock.Lock()
+	sc.dirtyTypes[reqType] = struct{}{}
+	sc.dirtyLock.Unlock()
+	sc.wakeup()
+}
+
+func (sc *subscribeCenter) wakeup() {
+	select {
+	case sc.signal <- struct{}{}:
+	default:
+	}
+}
+
+// run 合并一段时间内的变更通知，依次重新计算受影响的订阅
+func (sc *subscribeCenter) run() {
+	for range sc.signal {
+		sc.dirtyLock.Lock()
+		services, types := sc.dirtyServices, sc.dirtyTypes
+		sc.dirtyServices = map[model.ServiceKey]struct{}{}
+		sc.dirtyTypes = map[apiservice.DiscoverRequest_DiscoverRequestType]struct{}{}
+		sc.dirtyLock.Unlock()
+
+		for _, sub := range sc.affectedSubscriptions(services, types) {
+			sub.stream.refresh(sub)
+		}
+	}
+}
+
+func (sc *subscribeCenter) affectedSubscriptions(services map[model.ServiceKey]struct{},
+	types map[apiservice.DiscoverRequest_DiscoverRequestType]struct{}) []*discoverSubscription {
+	sc.lock.RLock()
+	defer sc.lock.RUnlock()
+
+	ret := make([]*discoverSubscription, 0, 16)
+	for reqType := range types {
+		for _, subs := range sc.subscriptions[reqType] {
+			for sub := range subs {
+				ret = append(ret, sub)
+			}
+		}
+	}
+	instanceSubs := sc.subscriptions[apiservice.DiscoverRequest_INSTANCE]
+	for key := range services {
+		for sub := range instanceSubs[key] {
+			ret = append(ret, sub)
+		}
+	}
+	return ret
+}
+
+func (sc *subscribeCenter) addSubscription(sub *discoverSubscription, serviceID string) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	services, ok := sc.subscriptions[sub.reqType]
+	if !ok {
+		services = map[model.ServiceKey]map[*discoverSubscription]struct{}{}
+		sc.subscriptions[sub.reqType] = services
+	}
+	subs, ok := services[sub.watchKey]
+	if !ok {
+		subs = map[*discoverSubscription]struct{}{}
+		services[sub.watchKey] = subs
+	}
+	subs[sub] = struct{}{}
+	if serviceID != "" {
+		sc.serviceKeys[serviceID] = sub.watchKey
+	}
+}
+
+func (sc *subscribeCenter) removeSubscription(sub *discoverSubscription) {
+	sc.lock.Lock()
+	defer sc.lock.Unlock()
+
+	services := sc.subscriptions[sub.reqType]
+	subs := services[sub.watchKey]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(services, sub.watchKey)
+	}
+	for reqType := range sc.subscriptions {
+		if _, ok := sc.subscriptions[reqType][sub.watchKey]; ok {
+			return
+		}
+	}
+	// 服务已经没有订阅者，不再需要保留 service id 到服务的映射
+	for serviceID, key := range sc.serviceKeys {
+		if key == sub.watchKey {
+			delete(sc.serviceKeys, serviceID)
+		}
+	}
+}
+
+// ruleUpdateListener 监听规则缓存的批量更新
+type ruleUpdateListener struct {
+	reqType apiservice.DiscoverRequest_DiscoverRequestType
+	center  *subscribeCenter
+}
+
+// OnCreated callback when cache value created
+func (l *ruleUpdateListener) OnCreated(value interface{}) {}
+
+// OnUpdated callback when cache value updated
+func (l *ruleUpdateListener) OnUpdated(value interface{}) {}
+
+// OnDeleted callback when cache value deleted
+func (l *ruleUpdateListener) OnDeleted(value interface{}) {}
+
+// OnBatchCreated callback when cache value created
+func (l *ruleUpdateListener) OnBatchCreated(value interface{}) {}
+
+// OnBatchUpdated callback when cache value updated
+func (l *ruleUpdateListener) OnBatchUpdated(value interface{}) {
+	l.center.onRulesUpdated(l.reqType)
+}
+
+// OnBatchDeleted callback when cache value deleted
+func (l *ruleUpdateListener) OnBatchDeleted(value interface{}) {}
+
+// DiscoverStream 基于 stream 的服务订阅会话，客户端订阅服务的实例、路由、限流以及熔断规则后，
+// 服务端在缓存中数据版本变化时主动推送新的 DiscoverResponse，客户端不再需要定时轮询
+type DiscoverStream struct {
+	ctx    context.Context
+	center *subscribeCenter
+	// discoverSvr 计算订阅数据使用的 server，需要经过客户端鉴权
+	discoverSvr ClientServer
+
+	lock          sync.Mutex
+	closed        bool
+	subscriptions map[discoverSubscriptionKey]*discoverSubscription
+	notifyChan    chan *apiservice.DiscoverResponse
+	doneChan      chan struct{}
+	doneOnce      sync.Once
+}
+
+type discoverSubscriptionKey struct {
+	reqType apiservice.DiscoverRequest_DiscoverRequestType
+	service model.ServiceKey
+}
+
+// OpenDiscoverStream 创建一个服务订阅会话，stream 结束时需要调用 Close 释放订阅
+func (s *Server) OpenDiscoverStream(ctx context.Context) *DiscoverStream {
+	return s.newDiscoverStream(ctx, s)
+}
+
+func (s *Server) newDiscoverStream(ctx context.Context, discoverSvr ClientServer) *DiscoverStream {
+	return &DiscoverStream{
+		ctx:           ctx,
+		center:        s.subscribeCenter(),
+		discoverSvr:   discoverSvr,
+		subscriptions: map[discoverSubscriptionKey]*discoverSubscription{},
+		notifyChan:    make(chan *apiservice.DiscoverResponse, discoverStreamNotifyQueueSize),
+		doneChan:      make(chan struct{}),
+	}
+}
+
+// Subscribe 返回当前的数据并且订阅后续的变更，不支持订阅的资源类型只返回当前的数据
+func (ds *DiscoverStream) Subscribe(ctx context.Context, req *apiservice.DiscoverRequest) *apiservice.DiscoverResponse {
+	resp := discoverWithCache(ctx, ds.discoverSvr, req.GetType(), req.GetService())
+	if _, ok := subscribableTypes[req.GetType()]; !ok || ds.center == nil {
+		return resp
+	}
+	code := resp.GetCode().GetValue()
+	if code != api.ExecuteSuccess && code != api.DataNoChange && code != api.NotFoundResource {
+		return resp
+	}
+
+	key := model.ServiceKey{
+		Namespace: req.GetService().GetNamespace().GetValue(),
+		Name:      req.GetService().GetName().GetValue(),
+	}
+	revision := req.GetService().GetRevision().GetValue()
+	switch code {
+	case api.ExecuteSuccess:
+		revision = resp.GetService().GetRevision().GetValue()
+	case api.NotFoundResource:
+		revision = ""
+	}
+
+	// 服务别名的实例变化以源服务的 revision 变化为准
+	var serviceID string
+	watchKey := key
+	if req.GetType() == apiservice.DiscoverRequest_INSTANCE {
+		if svc := ds.center.caches.Service().GetServiceByName(key.Name, key.Namespace); svc != nil {
+			serviceID = svc.ID
+			if svc.IsAlias() {
+				serviceID = svc.Reference
+				if source := ds.center.caches.Service().GetServiceByID(svc.Reference); source != nil {
+					watchKey = model.ServiceKey{Namespace: source.Namespace, Name: source.Name}
+				}
+			}
+		}
+	}
+
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	if ds.closed {
+		return resp
+	}
+	subKey := discoverSubscriptionKey{reqType: req.GetType(), service: key}
+	if sub, ok := ds.subscriptions[subKey]; ok {
+		sub.revision = revision
+		return resp
+	}
+	sub := &discoverSubscription{
+		stream:   ds,
+		reqType:  req.GetType(),
+		service:  key,
+		watchKey: watchKey,
+		revision: revision,
+	}
+	ds.subscriptions[subKey] = sub
+	ds.center.addSubscription(sub, serviceID)
+
+	log.Info("[Server][Discover] subscribe service", utils.ZapRequestIDByCtx(ctx),
+		zap.String("type", apiservice.DiscoverRequest_DiscoverRequestType_name[int32(req.GetType())]),
+		zap.String("namespace", key.Namespace), zap.String("service", key.Name))
+	return resp
+}
+
+// refresh 重新计算订阅的数据，版本发生变化时推送给客户端
+func (ds *DiscoverStream) refresh(sub *discoverSubscription) {
+	ds.lock.Lock()
+	if ds.closed {
+		ds.lock.Unlock()
+		return
+	}
+	revision := sub.revision
+	ds.lock.Unlock()
+
+	resp := discoverWithCache(ds.ctx, ds.discoverSvr, sub.reqType, &apiservice.Service{
+		Namespace: utils.NewStringValue(sub.service.Namespace),
+		Name:      utils.NewStringValue(sub.service.Name),
+		Revision:  utils.NewStringValue(revision),
+	})
+	switch resp.GetCode().GetValue() {
+	case api.ExecuteSuccess:
+		// 规则为空时不会返回 revision，此时仅在规则被全部删除时推送一次
+		newRevision := resp.GetService().GetRevision().GetValue()
+		if newRevision == revision {
+			return
+		}
+		revision = newRevision
+	case api.NotFoundResource:
+		// 服务被删除，只通知一次
+		if revision == "" {
+			return
+		}
+		revision = ""
+	default:
+		return
+	}
+
+	ds.lock.Lock()
+	sub.revision = revision
+	ds.lock.Unlock()
+	ds.notify(resp)
+}
+
+// notify 放入待推送队列，队列满时关闭 stream，避免阻塞其他订阅者的推送
+func (ds *DiscoverStream) notify(resp *apiservice.DiscoverResponse) {
+	select {
+	case <-ds.doneChan:
+		return
+	default:
+	}
+	select {
+	case ds.notifyChan <- resp:
+	default:
+		log.Warn("[Server][Discover] discover stream notify queue is full, close stream",
+			utils.ZapRequestIDByCtx(ds.ctx))
+		ds.doneOnce.Do(func() {
+			close(ds.doneChan)
+		})
+	}
+}
+
+// Notifications 待推送给客户端的数据
+func (ds *DiscoverStream) Notifications() <-chan *apiservice.DiscoverResponse {
+	return ds.notifyChan
+}
+
+// Done stream 关闭或者因为推送积压被服务端关闭时触发
+func (ds *DiscoverStream) Done() <-chan struct{} {
+	return ds.doneChan
+}
+
+// Close 取消 stream 上的全部订阅
+func (ds *DiscoverStream) Close() {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+
+	if ds.closed {
+		return
+	}
+	ds.closed = true
+	for _, sub := range ds.subscriptions {
+		ds.center.removeSubscription(sub)
+	}
+	ds.subscriptions = nil
+	ds.doneOnce.Do(func() {
+		close(ds.doneChan)
+	})
+}
+
+// discoverWithCache 按照资源类型从缓存中获取数据
+func discoverWithCache(ctx context.Context, svr ClientServer, reqType apiservice.DiscoverRequest_DiscoverRequestType,
+	req *apiservice.Service) *apiservice.DiscoverResponse {
+	switch reqType {
+	case apiservice.DiscoverRequest_INSTANCE:
+		return svr.ServiceInstancesCache(ctx, req)
+	case apiservice.DiscoverRequest_ROUTING:
+		return svr.GetRoutingConfigWithCache(ctx, req)
+	case apiservice.DiscoverRequest_RATE_LIMIT:
+		return svr.GetRateLimitWithCache(ctx, req)
+	case apiservice.DiscoverRequest_CIRCUIT_BREAKER:
+		return svr.GetCircuitBreakerWithCache(ctx, req)
+	case apiservice.DiscoverRequest_SERVICES:
+		return svr.GetServiceWithCache(ctx, req)
+	case apiservice.DiscoverRequest_FAULT_DETECTOR:
+		return svr.GetFaultDetectWithCache(ctx, req)
+	default:
+		return api.NewDiscoverRoutingResponse(apimodel.Code_InvalidDiscoverResource, req)
+	}
+}
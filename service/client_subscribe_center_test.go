@@ -0,0 +1,54 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func TestSubscribeCenterRemoveSubscription(t *testing.T) {
+	sc := &subscribeCenter{
+		subscriptions: map[apiservice.DiscoverRequest_DiscoverRequestType]map[model.ServiceKey]map[*discoverSubscription]struct{}{},
+		serviceKeys:   map[string]model.ServiceKey{},
+	}
+	key := model.ServiceKey{Namespace: DefaultNamespace, Name: "testSvc"}
+	instanceSub := &discoverSubscription{reqType: apiservice.DiscoverRequest_INSTANCE, service: key, watchKey: key}
+	routingSub := &discoverSubscription{reqType: apiservice.DiscoverRequest_ROUTING, service: key, watchKey: key}
+	other := model.ServiceKey{Namespace: DefaultNamespace, Name: "otherSvc"}
+	otherSub := &discoverSubscription{reqType: apiservice.DiscoverRequest_INSTANCE, service: other, watchKey: other}
+
+	sc.addSubscription(instanceSub, "svc-1")
+	sc.addSubscription(routingSub, "svc-1")
+	sc.addSubscription(otherSub, "svc-2")
+	assert.Len(t, sc.serviceKeys, 2)
+
+	// 服务还有其他类型的订阅
+	sc.removeSubscription(instanceSub)
+	assert.Equal(t, key, sc.serviceKeys["svc-1"])
+
+	sc.removeSubscription(routingSub)
+	_, ok := sc.serviceKeys["svc-1"]
+	assert.False(t, ok)
+	assert.Equal(t, other, sc.serviceKeys["svc-2"])
+	assert.Empty(t, sc.subscriptions[apiservice.DiscoverRequest_ROUTING])
+}
@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/utils"
+	"github.com/polarismesh/polaris/service"
+)
+
+func waitDiscoverPush(t *testing.T, stream *service.DiscoverStream,
+	reqType apiservice.DiscoverResponse_DiscoverResponseType) *apiservice.DiscoverResponse {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case resp := <-stream.Notifications():
+			if resp.GetType() == reqType {
+				return resp
+			}
+		case <-timeout:
+			t.Fatalf("wait discover push %s timeout", reqType.String())
+			return nil
+		}
+	}
+}
+
+func TestDiscoverStreamSubscribe(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	_, serviceResp := discoverSuit.createCommonService(t, 201)
+	defer discoverSuit.cleanServiceName(serviceResp.GetName().GetValue(), serviceResp.GetNamespace().GetValue())
+	defer discoverSuit.cleanRateLimitRevision(serviceResp.GetName().GetValue(), serviceResp.GetNamespace().GetValue())
+	_ = discoverSuit.DiscoverServer().Cache().TestUpdate()
+
+	stream := discoverSuit.DiscoverServer().OpenDiscoverStream(discoverSuit.DefaultCtx)
+	defer stream.Close()
+
+	subscribeReq := func(reqType apiservice.DiscoverRequest_DiscoverRequestType) *apiservice.DiscoverRequest {
+		return &apiservice.DiscoverRequest{
+			Type: reqType,
+			Service: &apiservice.Service{
+				Name:      utils.NewStringValue(serviceResp.GetName().GetValue()),
+				Namespace: utils.NewStringValue(serviceResp.GetNamespace().GetValue()),
+			},
+		}
+	}
+
+	t.Run("实例变化后推送", func(t *testing.T) {
+		resp := stream.Subscribe(discoverSuit.DefaultCtx, subscribeReq(apiservice.DiscoverRequest_INSTANCE))
+		assert.Equal(t, apiv1.ExecuteSuccess, resp.GetCode().GetValue(), resp.GetInfo().GetValue())
+		assert.Equal(t, 0, len(resp.GetInstances()))
+		oldRevision := resp.GetService().GetRevision().GetValue()
+
+		_, instanceResp := discoverSuit.createCommonInstance(t, serviceResp, 1)
+		defer discoverSuit.cleanInstance(instanceResp.GetId().GetValue())
+		_ = discoverSuit.DiscoverServer().Cache().TestUpdate()
+
+		push := waitDiscoverPush(t, stream, apiservice.DiscoverResponse_INSTANCE)
+		assert.Equal(t, apiv1.ExecuteSuccess, push.GetCode().GetValue())
+		assert.Equal(t, 1, len(push.GetInstances()))
+		assert.NotEqual(t, oldRevision, push.GetService().GetRevision().GetValue())
+	})
+
+	t.Run("限流规则变化后推送", func(t *testing.T) {
+		resp := stream.Subscribe(discoverSuit.DefaultCtx, subscribeReq(apiservice.DiscoverRequest_RATE_LIMIT))
+		assert.Equal(t, apiv1.ExecuteSuccess, resp.GetCode().GetValue(), resp.GetInfo().GetValue())
+
+		_, rateLimitResp := discoverSuit.createCommonRateLimit(t, serviceResp, 1)
+		defer discoverSuit.cleanRateLimit(rateLimitResp.GetId().GetValue())
+		_ = discoverSuit.DiscoverServer().Cache().TestUpdate()
+
+		push := waitDiscoverPush(t, stream, apiservice.DiscoverResponse_RATE_LIMIT)
+		assert.Equal(t, apiv1.ExecuteSuccess, push.GetCode().GetValue())
+		assert.Equal(t, 1, len(push.GetRateLimit().GetRules()))
+	})
+
+	t.Run("关闭后不再推送", func(t *testing.T) {
+		stream.Close()
+		<-stream.Done()
+
+		_, instanceResp := discoverSuit.createCommonInstance(t, serviceResp, 2)
+		defer discoverSuit.cleanInstance(instanceResp.GetId().GetValue())
+		_ = discoverSuit.DiscoverServer().Cache().TestUpdate()
+
+		select {
+		case resp := <-stream.Notifications():
+			assert.NotEqual(t, 2, len(resp.GetInstances()), "unexpected push after close")
+		case <-time.After(2 * time.Second):
+		}
+	})
+}
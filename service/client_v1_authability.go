@@ -196,3 +196,8 @@ func (svr *serverAuthAbility) UpdateInstance(ctx context.Context, req *apiservic
 
 	return svr.targetServer.UpdateInstance(ctx, req)
 }
+
+// OpenDiscoverStream is the interface for subscribing services, pushed data is checked by client permission
+func (svr *serverAuthAbility) OpenDiscoverStream(ctx context.Context) *DiscoverStream {
+	return svr.targetServer.newDiscoverStream(ctx, svr)
+}
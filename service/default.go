@@ -129,6 +129,9 @@ type PluginInstanceEventHandler struct {
 func (p *PluginInstanceEventHandler) OnEvent(ctx context.Context, any2 any) error {
 	e := any2.(model.InstanceEvent)
 	p.subscriber.PublishEvent(e)
+	if noHealthyEvent, ok := p.buildNoHealthyInstanceEvent(e); ok {
+		p.subscriber.PublishEvent(noHealthyEvent)
+	}
 	return nil
 }
 
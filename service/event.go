@@ -19,6 +19,7 @@ package service
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/polarismesh/polaris/common/model"
@@ -26,16 +27,36 @@ import (
 
 type serviceNameResolver func(string) *model.Service
 
+type instancesResolver func(string) []*model.Instance
+
 const maxRetryGetServiceName = 5
 
+// transitionExpireTime 实例状态变更在事件流中保留的时间，超过该时间认为实例缓存已经刷新
+const transitionExpireTime = 10 * time.Second
+
 type BaseInstanceEventHandler struct {
 	namingServer DiscoverServer
 	svcResolver  serviceNameResolver
+	insResolver  instancesResolver
+
+	lock sync.Mutex
+	// transitions 服务 ID -> 实例 ID -> 实例缓存尚未反映的状态变更
+	transitions map[string]map[string]*instanceTransition
+	lastSweep   time.Time
+}
+
+// instanceTransition 从事件流中得到的实例状态，字段为 nil 时以实例缓存为准
+type instanceTransition struct {
+	healthy  *bool
+	isolate  *bool
+	offline  bool
+	updateAt time.Time
 }
 
 func NewBaseInstanceEventHandler(namingServer DiscoverServer) *BaseInstanceEventHandler {
 	eventHandler := &BaseInstanceEventHandler{namingServer: namingServer}
 	eventHandler.svcResolver = eventHandler.resolveService
+	eventHandler.insResolver = eventHandler.resolveInstances
 	return eventHandler
 }
 
@@ -43,6 +64,10 @@ func (b *BaseInstanceEventHandler) resolveService(svcId string) *model.Service {
 	return b.namingServer.Cache().Service().GetServiceByID(svcId)
 }
 
+func (b *BaseInstanceEventHandler) resolveInstances(svcId string) []*model.Instance {
+	return b.namingServer.Cache().Instance().GetInstancesByServiceID(svcId)
+}
+
 // PreProcess do preprocess logic for event
 func (b *BaseInstanceEventHandler) PreProcess(ctx context.Context, value any) any {
 	instEvent, ok := value.(model.InstanceEvent)
@@ -67,3 +92,132 @@ func (b *BaseInstanceEventHandler) resolveServiceName(event *model.InstanceEvent
 		}
 	}
 }
+
+// buildNoHealthyInstanceEvent 实例变为不健康、隔离或者下线后，检查服务是否已经没有健康可用的实例
+// 实例缓存是异步刷新的，同一个刷新周期内多个实例的状态变更都需要叠加到缓存之上再判断
+func (b *BaseInstanceEventHandler) buildNoHealthyInstanceEvent(event model.InstanceEvent) (model.InstanceEvent, bool) {
+	switch event.EType {
+	case model.EventInstanceOnline, model.EventInstanceTurnHealth, model.EventInstanceCloseIsolate,
+		model.EventInstanceTurnUnHealth, model.EventInstanceOpenIsolate, model.EventInstanceOffline:
+	default:
+		return model.InstanceEvent{}, false
+	}
+
+	svcID := event.SvcId
+	if svcID == "" {
+		svc := b.namingServer.Cache().Service().GetServiceByName(event.Service, event.Namespace)
+		if svc == nil {
+			return model.InstanceEvent{}, false
+		}
+		svcID = svc.ID
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	transitions := b.recordTransition(svcID, event)
+	switch event.EType {
+	case model.EventInstanceTurnUnHealth, model.EventInstanceOpenIsolate, model.EventInstanceOffline:
+	default:
+		return model.InstanceEvent{}, false
+	}
+
+	for _, ins := range b.insResolver(svcID) {
+		healthy, isolate := ins.Healthy(), ins.Isolate()
+		if item, ok := transitions[ins.ID()]; ok {
+			if item.offline {
+				continue
+			}
+			if item.healthy != nil {
+				healthy = *item.healthy
+			}
+			if item.isolate != nil {
+				isolate = *item.isolate
+			}
+		}
+		if healthy && !isolate {
+			return model.InstanceEvent{}, false
+		}
+	}
+	// 刚上线的实例可能还不在缓存中
+	for _, item := range transitions {
+		if !item.offline && item.healthy != nil && *item.healthy && item.isolate != nil && !*item.isolate {
+			return model.InstanceEvent{}, false
+		}
+	}
+	return model.InstanceEvent{
+		Id:         event.Id,
+		SvcId:      svcID,
+		Namespace:  event.Namespace,
+		Service:    event.Service,
+		Instance:   event.Instance,
+		EType:      model.EventServiceNoHealthyInstance,
+		CreateTime: time.Now(),
+		MetaData:   event.MetaData,
+	}, true
+}
+
+// recordTransition 记录实例的状态变更，并清理已经过期的状态变更，返回服务下仍然有效的状态变更
+func (b *BaseInstanceEventHandler) recordTransition(svcID string,
+	event model.InstanceEvent) map[string]*instanceTransition {
+	now := time.Now()
+	if b.transitions == nil {
+		b.transitions = map[string]map[string]*instanceTransition{}
+	}
+	if now.Sub(b.lastSweep) > transitionExpireTime {
+		for id, items := range b.transitions {
+			b.expireTransitions(id, items, now)
+		}
+		b.lastSweep = now
+	}
+
+	items, ok := b.transitions[svcID]
+	if !ok {
+		items = map[string]*instanceTransition{}
+		b.transitions[svcID] = items
+	}
+	item, ok := items[event.Id]
+	if !ok || item.offline {
+		item = &instanceTransition{}
+		items[event.Id] = item
+	}
+	updateAt := event.CreateTime
+	if updateAt.IsZero() {
+		updateAt = now
+	}
+	item.updateAt = updateAt
+
+	healthy, isolate := true, false
+	switch event.EType {
+	case model.EventInstanceOnline:
+		if event.Instance != nil {
+			healthy, isolate = event.Instance.GetHealthy().GetValue(), event.Instance.GetIsolate().GetValue()
+			item.healthy, item.isolate = &healthy, &isolate
+		}
+	case model.EventInstanceTurnHealth:
+		item.healthy = &healthy
+	case model.EventInstanceTurnUnHealth:
+		healthy = false
+		item.healthy = &healthy
+	case model.EventInstanceCloseIsolate:
+		item.isolate = &isolate
+	case model.EventInstanceOpenIsolate:
+		isolate = true
+		item.isolate = &isolate
+	case model.EventInstanceOffline:
+		item.offline = true
+	}
+	b.expireTransitions(svcID, items, now)
+	return items
+}
+
+func (b *BaseInstanceEventHandler) expireTransitions(svcID string, items map[string]*instanceTransition,
+	now time.Time) {
+	for id, item := range items {
+		if now.Sub(item.updateAt) > transitionExpireTime {
+			delete(items, id)
+		}
+	}
+	if len(items) == 0 {
+		delete(b.transitions, svcID)
+	}
+}
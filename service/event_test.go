@@ -20,7 +20,10 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes/wrappers"
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/polarismesh/polaris/common/model"
@@ -52,3 +55,98 @@ func TestPreProcess(t *testing.T) {
 	assert.Equal(t, "", eventNext.Service)
 
 }
+
+func TestBuildNoHealthyInstanceEvent(t *testing.T) {
+	newInstance := func(id string, healthy, isolate bool) *model.Instance {
+		return &model.Instance{Proto: &apiservice.Instance{
+			Id:      &wrappers.StringValue{Value: id},
+			Healthy: &wrappers.BoolValue{Value: healthy},
+			Isolate: &wrappers.BoolValue{Value: isolate},
+		}}
+	}
+	instances := []*model.Instance{
+		// 缓存尚未刷新，触发事件的实例仍然是健康的
+		newInstance("ins-1", true, false),
+		newInstance("ins-2", false, false),
+		newInstance("ins-3", true, true),
+	}
+	svr := &BaseInstanceEventHandler{insResolver: func(s string) []*model.Instance {
+		return instances
+	}}
+
+	event := model.InstanceEvent{Id: "ins-1", SvcId: "1234", Namespace: DefaultNamespace, Service: "testSvc",
+		EType: model.EventInstanceTurnUnHealth}
+	noHealthyEvent, ok := svr.buildNoHealthyInstanceEvent(event)
+	assert.True(t, ok)
+	assert.Equal(t, model.EventServiceNoHealthyInstance, noHealthyEvent.EType)
+	assert.Equal(t, "testSvc", noHealthyEvent.Service)
+
+	// 服务还有其他健康实例
+	instances = append(instances, newInstance("ins-4", true, false))
+	_, ok = svr.buildNoHealthyInstanceEvent(event)
+	assert.False(t, ok)
+
+	// 只关心实例变为不可用的事件
+	event.EType = model.EventInstanceOnline
+	instances = instances[:3]
+	_, ok = svr.buildNoHealthyInstanceEvent(event)
+	assert.False(t, ok)
+}
+
+func TestBuildNoHealthyInstanceEventWithinRefreshWindow(t *testing.T) {
+	newInstance := func(id string, healthy, isolate bool) *model.Instance {
+		return &model.Instance{Proto: &apiservice.Instance{
+			Id:      &wrappers.StringValue{Value: id},
+			Healthy: &wrappers.BoolValue{Value: healthy},
+			Isolate: &wrappers.BoolValue{Value: isolate},
+		}}
+	}
+	// 两个实例在同一个缓存刷新周期内先后变为不可用，缓存中两个实例仍然是健康的
+	instances := []*model.Instance{
+		newInstance("ins-1", true, false),
+		newInstance("ins-2", true, false),
+	}
+	svr := &BaseInstanceEventHandler{insResolver: func(s string) []*model.Instance {
+		return instances
+	}}
+	newEvent := func(id string, etype model.InstanceEventType) model.InstanceEvent {
+		return model.InstanceEvent{Id: id, SvcId: "1234", Namespace: DefaultNamespace, Service: "testSvc",
+			EType: etype, CreateTime: time.Now()}
+	}
+
+	_, ok := svr.buildNoHealthyInstanceEvent(newEvent("ins-1", model.EventInstanceTurnUnHealth))
+	assert.False(t, ok)
+	noHealthyEvent, ok := svr.buildNoHealthyInstanceEvent(newEvent("ins-2", model.EventInstanceOffline))
+	assert.True(t, ok)
+	assert.Equal(t, model.EventServiceNoHealthyInstance, noHealthyEvent.EType)
+	assert.Equal(t, "ins-2", noHealthyEvent.Id)
+
+	// 恢复健康的实例同样叠加到缓存之上
+	_, ok = svr.buildNoHealthyInstanceEvent(newEvent("ins-1", model.EventInstanceTurnHealth))
+	assert.False(t, ok)
+	_, ok = svr.buildNoHealthyInstanceEvent(newEvent("ins-3", model.EventInstanceOpenIsolate))
+	assert.False(t, ok)
+
+	// 尚未进入缓存的新实例
+	online := newEvent("ins-4", model.EventInstanceOnline)
+	online.Instance = &apiservice.Instance{
+		Healthy: &wrappers.BoolValue{Value: true},
+		Isolate: &wrappers.BoolValue{Value: false},
+	}
+	_, ok = svr.buildNoHealthyInstanceEvent(online)
+	assert.False(t, ok)
+	_, ok = svr.buildNoHealthyInstanceEvent(newEvent("ins-1", model.EventInstanceOpenIsolate))
+	assert.False(t, ok)
+
+	// 过期的状态变更以缓存为准
+	svr = &BaseInstanceEventHandler{insResolver: func(s string) []*model.Instance {
+		return instances
+	}}
+	expired := newEvent("ins-1", model.EventInstanceTurnUnHealth)
+	expired.CreateTime = time.Now().Add(-2 * transitionExpireTime)
+	_, ok = svr.buildNoHealthyInstanceEvent(expired)
+	assert.False(t, ok)
+	_, ok = svr.buildNoHealthyInstanceEvent(newEvent("ins-2", model.EventInstanceOffline))
+	assert.False(t, ok)
+	assert.Len(t, svr.transitions["1234"], 1)
+}
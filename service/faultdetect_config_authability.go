@@ -31,7 +31,7 @@ import (
 func (svr *serverAuthAbility) CreateFaultDetectRules(
 	ctx context.Context, request []*apifault.FaultDetectRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectFaultDetectAuthContext(ctx, request, model.Read, "CreateFaultDetectRules")
+	authCtx := svr.collectFaultDetectAuthContext(ctx, request, model.Create, "CreateFaultDetectRules")
 	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
 	}
@@ -43,7 +43,7 @@ func (svr *serverAuthAbility) CreateFaultDetectRules(
 func (svr *serverAuthAbility) DeleteFaultDetectRules(
 	ctx context.Context, request []*apifault.FaultDetectRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectFaultDetectAuthContext(ctx, request, model.Read, "DeleteFaultDetectRules")
+	authCtx := svr.collectFaultDetectAuthContext(ctx, request, model.Delete, "DeleteFaultDetectRules")
 	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
 	}
@@ -55,7 +55,7 @@ func (svr *serverAuthAbility) DeleteFaultDetectRules(
 func (svr *serverAuthAbility) UpdateFaultDetectRules(
 	ctx context.Context, request []*apifault.FaultDetectRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectFaultDetectAuthContext(ctx, request, model.Read, "UpdateFaultDetectRules")
+	authCtx := svr.collectFaultDetectAuthContext(ctx, request, model.Modify, "UpdateFaultDetectRules")
 	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
 	}
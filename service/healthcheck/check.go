@@ -235,20 +235,22 @@ func (c *CheckScheduler) upsertInstanceChecker(instanceWithChecker *InstanceWith
 	defer c.rwMutex.Unlock()
 	instance := instanceWithChecker.instance
 	ttl := instance.HealthCheck().GetHeartbeat().GetTtl().GetValue()
+	expireDurationSec := getExpireDurationSec(instance.Proto, instanceWithChecker.checker)
 	var (
 		instValue *itemValue
 		exist     bool
 	)
 	instValue, exist = c.scheduledInstances[instance.ID()]
 	if exist {
-		if ttl == instValue.ttlDurationSec {
+		if ttl == instValue.ttlDurationSec && expireDurationSec == instValue.expireDurationSec &&
+			instanceWithChecker.checker == instValue.checker {
 			return true, instValue
 		}
 		// force update check info
 		instValue.mutex.Lock()
 		oldTtl := instValue.ttlDurationSec
 		instValue.checker = instanceWithChecker.checker
-		instValue.expireDurationSec = getExpireDurationSec(instance.Proto)
+		instValue.expireDurationSec = expireDurationSec
 		instValue.ttlDurationSec = ttl
 		instValue.mutex.Unlock()
 		if log.DebugEnabled() {
@@ -261,7 +263,7 @@ func (c *CheckScheduler) upsertInstanceChecker(instanceWithChecker *InstanceWith
 			host:              instance.Host(),
 			port:              instance.Port(),
 			id:                instance.ID(),
-			expireDurationSec: getExpireDurationSec(instance.Proto),
+			expireDurationSec: expireDurationSec,
 			checker:           instanceWithChecker.checker,
 			ttlDurationSec:    ttl,
 		}
@@ -335,7 +337,10 @@ func (c *CheckScheduler) AddClient(clientWithChecker *ClientWithChecker) {
 		client.Proto().GetId().GetValue(), client.Proto().GetHost(), 0)
 }
 
-func getExpireDurationSec(instance *apiservice.Instance) uint32 {
+func getExpireDurationSec(instance *apiservice.Instance, checker plugin.HealthChecker) uint32 {
+	if intervalChecker, ok := checker.(plugin.IntervalHealthChecker); ok {
+		return intervalChecker.CheckIntervalSec(instance.GetMetadata())
+	}
 	ttlValue := instance.GetHealthCheck().GetHeartbeat().GetTtl().GetValue()
 	return expireTtlCount * ttlValue
 }
@@ -467,6 +472,7 @@ func (c *CheckScheduler) checkCallbackInstance(value interface{}) {
 		},
 		CurTimeSec:        currentTimeSec,
 		ExpireDurationSec: instanceValue.expireDurationSec,
+		Metadata:          cachedInstance.Metadata(),
 	}
 	checkResp, err = instanceValue.checker.Check(request)
 	if err != nil {
@@ -121,7 +121,8 @@ func (handler *LeaderChangeEventHandler) doCheckSelfServiceInstance(cachedInstan
 			Healthy:    cachedInstance.Healthy(),
 		},
 		CurTimeSec:        currentTimeSec,
-		ExpireDurationSec: getExpireDurationSec(cachedInstance.Proto),
+		ExpireDurationSec: getExpireDurationSec(cachedInstance.Proto, checker),
+		Metadata:          cachedInstance.Metadata(),
 	}
 	checkResp, err := checker.Check(request)
 	if err != nil {
@@ -74,7 +74,7 @@ func (s *Server) getHealthChecker(id string) plugin.HealthChecker {
 	if insCache == nil {
 		return s.defaultChecker
 	}
-	checker, ok := s.checkers[int32(insCache.HealthCheck().GetType())]
+	checker, ok := s.findChecker(insCache.Proto)
 	if !ok {
 		return s.defaultChecker
 	}
@@ -99,10 +99,13 @@ func initialize(ctx context.Context, hcOpt *Config, cacheOpen bool, bc *batch.Co
 				return fmt.Errorf("[healthcheck]duplicate healthchecker %s, checkType %d", entry.Name, checker.Type())
 			}
 			server.checkers[int32(checker.Type())] = checker
-			if nil == server.defaultChecker {
+			if nil == server.defaultChecker && checker.Type() == plugin.HealthCheckerHeartbeat {
 				server.defaultChecker = checker
 			}
 		}
+		if nil == server.defaultChecker {
+			server.defaultChecker = plugin.GetHealthChecker(hcOpt.Checkers[0].Name, &hcOpt.Checkers[0])
+		}
 	} else {
 		return fmt.Errorf("[healthcheck]no checker config")
 	}
@@ -239,7 +242,7 @@ func (s *Server) GetLastHeartbeat(req *apiservice.Instance) *apiservice.Response
 	if insCache == nil {
 		return api.NewInstanceResponse(apimodel.Code_NotFoundResource, req)
 	}
-	checker, ok := s.checkers[int32(insCache.HealthCheck().GetType())]
+	checker, ok := s.findChecker(insCache.Proto)
 	if !ok {
 		return api.NewInstanceResponse(apimodel.Code_HeartbeatTypeNotFound, req)
 	}
@@ -275,7 +278,7 @@ func (s *Server) handleInstanceEventWorker(ctx context.Context) {
 					log.Errorf("[Health Check] cannot get instance from cache, instance id is %s", event.Id)
 					break
 				}
-				checker, ok := s.checkers[int32(insCache.HealthCheck().GetType())]
+				checker, ok := s.findChecker(insCache.Proto)
 				if !ok {
 					log.Errorf("[Health Check]heart beat type not found checkType %d",
 						int32(insCache.HealthCheck().GetType()))
@@ -295,6 +298,20 @@ func (s *Server) handleInstanceEventWorker(ctx context.Context) {
 	}
 }
 
+// findChecker find the health checker by the health check type declared by the instance, the instance
+// declares active probing must also carry a valid probe target in its metadata
+func (s *Server) findChecker(instance *apiservice.Instance) (plugin.HealthChecker, bool) {
+	checker, ok := s.checkers[int32(instance.GetHealthCheck().GetType())]
+	if !ok {
+		return nil, false
+	}
+	if intervalChecker, ok := checker.(plugin.IntervalHealthChecker); ok &&
+		!intervalChecker.IsCheckEnable(instance.GetMetadata()) {
+		return nil, false
+	}
+	return checker, true
+}
+
 // Checkers get all health checker, for test only
 func (s *Server) Checkers() map[int32]plugin.HealthChecker {
 	return s.checkers
@@ -0,0 +1,93 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package healthcheck
+
+import (
+	"testing"
+
+	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/plugin"
+)
+
+type mockHealthChecker struct {
+	plugin.HealthChecker
+	checkType plugin.HealthCheckType
+}
+
+func (m *mockHealthChecker) Type() plugin.HealthCheckType {
+	return m.checkType
+}
+
+type mockProbeChecker struct {
+	mockHealthChecker
+}
+
+func (m *mockProbeChecker) CheckIntervalSec(metadata map[string]string) uint32 {
+	return 5
+}
+
+func (m *mockProbeChecker) IsCheckEnable(metadata map[string]string) bool {
+	return metadata[model.MetaKeyHealthCheckProtocol] == "tcp"
+}
+
+func TestServerFindChecker(t *testing.T) {
+	heartbeat := &mockHealthChecker{checkType: plugin.HealthCheckerHeartbeat}
+	probe := &mockProbeChecker{mockHealthChecker{checkType: plugin.HealthCheckerProbe}}
+	svr := &Server{checkers: map[int32]plugin.HealthChecker{
+		int32(plugin.HealthCheckerHeartbeat): heartbeat,
+		int32(plugin.HealthCheckerProbe):     probe,
+	}}
+	newInstance := func(checkType apiservice.HealthCheck_HealthCheckType,
+		metadata map[string]string) *apiservice.Instance {
+		return &apiservice.Instance{
+			HealthCheck: &apiservice.HealthCheck{Type: checkType},
+			Metadata:    metadata,
+		}
+	}
+
+	t.Run("未声明探测协议的心跳实例", func(t *testing.T) {
+		checker, ok := svr.findChecker(newInstance(apiservice.HealthCheck_HEARTBEAT, nil))
+		assert.True(t, ok)
+		assert.Equal(t, heartbeat, checker)
+	})
+
+	t.Run("心跳实例携带探测元数据", func(t *testing.T) {
+		checker, ok := svr.findChecker(newInstance(apiservice.HealthCheck_HEARTBEAT,
+			map[string]string{model.MetaKeyHealthCheckProtocol: "tcp"}))
+		assert.True(t, ok)
+		assert.Equal(t, heartbeat, checker)
+	})
+
+	t.Run("主动探测的实例", func(t *testing.T) {
+		checker, ok := svr.findChecker(newInstance(model.HealthCheckProbe,
+			map[string]string{model.MetaKeyHealthCheckProtocol: "tcp"}))
+		assert.True(t, ok)
+		assert.Equal(t, probe, checker)
+	})
+
+	t.Run("主动探测的实例未声明合法的探测协议", func(t *testing.T) {
+		_, ok := svr.findChecker(newInstance(model.HealthCheckProbe, nil))
+		assert.False(t, ok)
+		_, ok = svr.findChecker(newInstance(model.HealthCheckProbe,
+			map[string]string{model.MetaKeyHealthCheckProtocol: "udp"}))
+		assert.False(t, ok)
+	})
+}
@@ -694,7 +694,7 @@ func updateHealthCheck(req *apiservice.Instance, instance *model.Instance) bool
 	needUpdate := false
 	insProto := instance.Proto
 	// health Check，healthCheck不能为空，且没有把enable_health_check置为false
-	if req.GetHealthCheck().GetHeartbeat() != nil &&
+	if model.IsHealthCheckDeclared(req.GetHealthCheck()) &&
 		(req.GetEnableHealthCheck() == nil || req.GetEnableHealthCheck().GetValue()) {
 		// 如果数据库中实例原有是不打开健康检查，
 		// 那么一旦打开，status需置为false，等待一次心跳成功才能变成true
@@ -713,12 +713,16 @@ func updateHealthCheck(req *apiservice.Instance, instance *model.Instance) bool
 			// ttl有变更
 			needUpdate = true
 		}
-		if apiservice.HealthCheck_HEARTBEAT != instance.HealthCheck().GetType() {
+		checkType := model.NormalizeHealthCheckType(req.GetHealthCheck().GetType())
+		if checkType != instance.HealthCheck().GetType() {
 			// health check type有变更
 			needUpdate = true
 		}
 		insProto.HealthCheck = req.GetHealthCheck()
-		insProto.HealthCheck.Type = apiservice.HealthCheck_HEARTBEAT
+		insProto.HealthCheck.Type = checkType
+		if insProto.HealthCheck.Heartbeat == nil {
+			insProto.HealthCheck.Heartbeat = &apiservice.HeartbeatHealthCheck{}
+		}
 		if insProto.HealthCheck.Heartbeat.Ttl == nil {
 			insProto.HealthCheck.Heartbeat.Ttl = utils.NewUInt32Value(0)
 		}
@@ -1057,6 +1061,11 @@ func checkCreateInstance(req *apiservice.Instance) (string, *apiservice.Response
 	if err := checkMetadata(req.GetMetadata()); err != nil {
 		return "", api.NewInstanceResponse(apimodel.Code_InvalidMetadata, req)
 	}
+	// 主动探测的实例需要在 metadata 中声明探测协议
+	if req.GetHealthCheck().GetType() == model.HealthCheckProbe &&
+		req.GetMetadata()[model.MetaKeyHealthCheckProtocol] == "" {
+		return "", api.NewInstanceResponse(apimodel.Code_InvalidMetadata, req)
+	}
 
 	// 检查字段长度是否大于DB中对应字段长
 	err, notOk := CheckDbInstanceFieldLen(req)
@@ -80,7 +80,7 @@ func (svr *serverAuthAbility) UpdateRateLimits(
 // EnableRateLimits 启用限流规则
 func (svr *serverAuthAbility) EnableRateLimits(
 	ctx context.Context, reqs []*apitraffic.Rule) *apiservice.BatchWriteResponse {
-	authCtx := svr.collectRateLimitAuthContext(ctx, nil, model.Read, "EnableRateLimits")
+	authCtx := svr.collectRateLimitAuthContext(ctx, reqs, model.Modify, "EnableRateLimits")
 
 	_, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx)
 	if err != nil {
@@ -32,8 +32,7 @@ import (
 func (svr *serverAuthAbility) CreateRoutingConfigsV2(ctx context.Context,
 	req []*apitraffic.RouteRule) *apiservice.BatchWriteResponse {
 
-	// TODO not support RouteRuleV2 resource auth, so we set op is read
-	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Read, "CreateRoutingConfigsV2")
+	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Create, "CreateRoutingConfigsV2")
 	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
 	}
@@ -46,7 +45,7 @@ func (svr *serverAuthAbility) CreateRoutingConfigsV2(ctx context.Context,
 func (svr *serverAuthAbility) DeleteRoutingConfigsV2(ctx context.Context,
 	req []*apitraffic.RouteRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Read, "DeleteRoutingConfigsV2")
+	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Delete, "DeleteRoutingConfigsV2")
 	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
 	}
@@ -59,7 +58,7 @@ func (svr *serverAuthAbility) DeleteRoutingConfigsV2(ctx context.Context,
 func (svr *serverAuthAbility) UpdateRoutingConfigsV2(ctx context.Context,
 	req []*apitraffic.RouteRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Read, "UpdateRoutingConfigsV2")
+	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Modify, "UpdateRoutingConfigsV2")
 	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
 	}
@@ -72,7 +71,7 @@ func (svr *serverAuthAbility) UpdateRoutingConfigsV2(ctx context.Context,
 func (svr *serverAuthAbility) EnableRoutings(ctx context.Context,
 	req []*apitraffic.RouteRule) *apiservice.BatchWriteResponse {
 
-	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Read, "EnableRoutings")
+	authCtx := svr.collectRouteRuleV2AuthContext(ctx, req, model.Modify, "EnableRoutings")
 	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
 		return api.NewBatchWriteResponse(convertToErrCode(err))
 	}
@@ -19,6 +19,7 @@ package service
 
 import (
 	"context"
+	"sync"
 
 	apiservice "github.com/polarismesh/specification/source/go/api/v1/service_manage"
 	"golang.org/x/sync/singleflight"
@@ -56,6 +57,9 @@ type Server struct {
 	hooks []ResourceHook
 
 	polarisServiceSet map[model.ServiceKey]struct{}
+
+	subscribeOnce sync.Once
+	subscriber    *subscribeCenter
 }
 
 // HealthServer 健康检查Server
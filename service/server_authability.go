@@ -203,6 +203,7 @@ func (svr *serverAuthAbility) collectRateLimitAuthContext(ctx context.Context, r
 		model.WithModule(model.DiscoverModule),
 		model.WithMethod(methodName),
 		model.WithAccessResources(svr.queryRateLimitConfigResource(req)),
+		model.WithRuleResources(svr.queryRateLimitRuleResource(req)),
 	)
 }
 
@@ -214,11 +215,11 @@ func (svr *serverAuthAbility) collectRouteRuleV2AuthContext(ctx context.Context,
 		model.WithOperation(resourceOp),
 		model.WithModule(model.DiscoverModule),
 		model.WithMethod(methodName),
-		model.WithAccessResources(map[apisecurity.ResourceType][]model.ResourceEntry{}),
+		model.WithRuleResources(svr.queryRouteRuleV2Resource(req)),
 	)
 }
 
-// collectRouteRuleV2AuthContext 收集熔断v2规则
+// collectCircuitBreakerRuleV2AuthContext 收集熔断v2规则
 func (svr *serverAuthAbility) collectCircuitBreakerRuleV2AuthContext(ctx context.Context,
 	req []*apifault.CircuitBreakerRule,
 	resourceOp model.ResourceOperation, methodName string) *model.AcquireContext {
@@ -227,11 +228,11 @@ func (svr *serverAuthAbility) collectCircuitBreakerRuleV2AuthContext(ctx context
 		model.WithOperation(resourceOp),
 		model.WithModule(model.DiscoverModule),
 		model.WithMethod(methodName),
-		model.WithAccessResources(map[apisecurity.ResourceType][]model.ResourceEntry{}),
+		model.WithRuleResources(svr.queryCircuitBreakerRuleResource(req)),
 	)
 }
 
-// collectRouteRuleV2AuthContext 收集主动探测规则
+// collectFaultDetectAuthContext 收集主动探测规则
 func (svr *serverAuthAbility) collectFaultDetectAuthContext(ctx context.Context,
 	req []*apifault.FaultDetectRule,
 	resourceOp model.ResourceOperation, methodName string) *model.AcquireContext {
@@ -240,7 +241,20 @@ func (svr *serverAuthAbility) collectFaultDetectAuthContext(ctx context.Context,
 		model.WithOperation(resourceOp),
 		model.WithModule(model.DiscoverModule),
 		model.WithMethod(methodName),
-		model.WithAccessResources(map[apisecurity.ResourceType][]model.ResourceEntry{}),
+		model.WithRuleResources(svr.queryFaultDetectRuleResource(req)),
+	)
+}
+
+// collectServicePolicyAuthContext 收集挂载在服务上的策略，按照策略所属的服务进行鉴权
+func (svr *serverAuthAbility) collectServicePolicyAuthContext(ctx context.Context, req *ServicePolicy,
+	saved func(id string) *model.ServicePolicy, resourceOp model.ResourceOperation,
+	methodName string) *model.AcquireContext {
+	return model.NewAcquireContext(
+		model.WithRequestContext(ctx),
+		model.WithOperation(resourceOp),
+		model.WithModule(model.DiscoverModule),
+		model.WithMethod(methodName),
+		model.WithAccessResources(svr.queryServicePolicyResource(req, saved)),
 	)
 }
 
@@ -447,6 +461,121 @@ func (svr *serverAuthAbility) queryRateLimitConfigResource(
 	return ret
 }
 
+// queryRateLimitRuleResource 根据所给的限流规则信息，收集对应的治理规则 ResourceEntry 列表
+func (svr *serverAuthAbility) queryRateLimitRuleResource(
+	req []*apitraffic.Rule) map[model.RuleResourceType][]model.ResourceEntry {
+	rules := make([]model.ResourceEntry, 0, len(req))
+	for index := range req {
+		rules = append(rules, svr.ruleResourceEntries(req[index].GetId().GetValue(),
+			req[index].GetName().GetValue(), req[index].GetNamespace().GetValue(), func(id string) string {
+				if data, err := svr.targetServer.storage.GetRateLimitWithID(id); err == nil && data != nil {
+					return data.Name
+				}
+				return ""
+			})...)
+	}
+	return map[model.RuleResourceType][]model.ResourceEntry{
+		model.ResourceTypeRateLimitRules: rules,
+	}
+}
+
+// ruleResourceEntries 更新、删除规则时以存储中的规则名称为准，修改了名称时新名称同样需要有权限，
+// 避免通过修改规则名称绕过按照名称匹配的鉴权策略
+func (svr *serverAuthAbility) ruleResourceEntries(id, name, namespace string,
+	queryName func(id string) string) []model.ResourceEntry {
+	entries := make([]model.ResourceEntry, 0, 2)
+	if id != "" {
+		if saved := queryName(id); saved != "" {
+			entries = append(entries, model.ResourceEntry{ID: id, Name: saved, Namespace: namespace})
+			if name == "" || name == saved {
+				return entries
+			}
+		}
+	}
+	return append(entries, model.ResourceEntry{ID: id, Name: name, Namespace: namespace})
+}
+
+// queryRouteRuleV2Resource 根据所给的路由规则信息，收集对应的治理规则 ResourceEntry 列表
+func (svr *serverAuthAbility) queryRouteRuleV2Resource(
+	req []*apitraffic.RouteRule) map[model.RuleResourceType][]model.ResourceEntry {
+	rules := make([]model.ResourceEntry, 0, len(req))
+	for index := range req {
+		rules = append(rules, svr.ruleResourceEntries(req[index].GetId(), req[index].GetName(), "",
+			func(id string) string {
+				if data, err := svr.targetServer.storage.GetRoutingConfigV2WithID(id); err == nil && data != nil {
+					return data.Name
+				}
+				return ""
+			})...)
+	}
+	return map[model.RuleResourceType][]model.ResourceEntry{
+		model.ResourceTypeRouteRules: rules,
+	}
+}
+
+// queryCircuitBreakerRuleResource 根据所给的熔断规则信息，收集对应的治理规则 ResourceEntry 列表
+func (svr *serverAuthAbility) queryCircuitBreakerRuleResource(
+	req []*apifault.CircuitBreakerRule) map[model.RuleResourceType][]model.ResourceEntry {
+	rules := make([]model.ResourceEntry, 0, len(req))
+	for index := range req {
+		rules = append(rules, svr.ruleResourceEntries(req[index].GetId(), req[index].GetName(), "",
+			func(id string) string {
+				_, data, err := svr.targetServer.storage.GetCircuitBreakerRules(map[string]string{"id": id}, 0, 1)
+				if err == nil && len(data) > 0 {
+					return data[0].Name
+				}
+				return ""
+			})...)
+	}
+	return map[model.RuleResourceType][]model.ResourceEntry{
+		model.ResourceTypeCircuitBreakerRules: rules,
+	}
+}
+
+// queryFaultDetectRuleResource 根据所给的主动探测规则信息，收集对应的治理规则 ResourceEntry 列表
+func (svr *serverAuthAbility) queryFaultDetectRuleResource(
+	req []*apifault.FaultDetectRule) map[model.RuleResourceType][]model.ResourceEntry {
+	rules := make([]model.ResourceEntry, 0, len(req))
+	for index := range req {
+		rules = append(rules, svr.ruleResourceEntries(req[index].GetId(), req[index].GetName(), "",
+			func(id string) string {
+				_, data, err := svr.targetServer.storage.GetFaultDetectRules(map[string]string{"id": id}, 0, 1)
+				if err == nil && len(data) > 0 {
+					return data[0].Name
+				}
+				return ""
+			})...)
+	}
+	return map[model.RuleResourceType][]model.ResourceEntry{
+		model.ResourceTypeFaultDetectRules: rules,
+	}
+}
+
+// queryServicePolicyResource 根据所给的策略信息，收集策略所属服务的 ResourceEntry 列表
+// saved 用于查询存储中的策略，更新、删除操作时以存储中的归属服务为准，避免通过修改归属服务绕过鉴权
+func (svr *serverAuthAbility) queryServicePolicyResource(req *ServicePolicy,
+	saved func(id string) *model.ServicePolicy) map[apisecurity.ResourceType][]model.ResourceEntry {
+	names := utils.NewStringSet()
+	svcSet := model.NewServiceSet()
+	if req.ID != "" {
+		if data := saved(req.ID); data != nil {
+			if svc := svr.Cache().Service().GetServiceByName(data.Service, data.Namespace); svc != nil {
+				svcSet.Add(svc)
+			}
+		}
+	}
+	if req.Service != "" {
+		if svc := svr.Cache().Service().GetServiceByName(req.Service, req.Namespace); svc != nil {
+			svcSet.Add(svc)
+		}
+	}
+	ret := svr.convertToDiscoverResourceEntryMaps(names, svcSet)
+	if authLog.DebugEnabled() {
+		authLog.Debug("[Auth][Server] collect service-policy access res", zap.Any("res", ret))
+	}
+	return ret
+}
+
 // convertToDiscoverResourceEntryMaps 通用方法，进行转换为期望的、服务相关的 ResourceEntry
 func (svr *serverAuthAbility) convertToDiscoverResourceEntryMaps(nsSet utils.StringSet,
 	svcSet *model.ServiceSet) map[apisecurity.ResourceType][]model.ResourceEntry {
@@ -458,8 +587,9 @@ func (svr *serverAuthAbility) convertToDiscoverResourceEntryMaps(nsSet utils.Str
 	for index := range nsArr {
 		ns := nsArr[index]
 		nsRet = append(nsRet, model.ResourceEntry{
-			ID:    ns.Name,
-			Owner: ns.Owner,
+			ID:        ns.Name,
+			Owner:     ns.Owner,
+			Namespace: ns.Name,
 		})
 	}
 
@@ -468,8 +598,9 @@ func (svr *serverAuthAbility) convertToDiscoverResourceEntryMaps(nsSet utils.Str
 	for index := range svcParam {
 		svc := svcParam[index]
 		svcRet = append(svcRet, model.ResourceEntry{
-			ID:    svc.ID,
-			Owner: svc.Owner,
+			ID:        svc.ID,
+			Owner:     svc.Owner,
+			Namespace: svc.Namespace,
 		})
 	}
 
@@ -73,7 +73,7 @@ func (svr *serverAuthAbility) DeleteServices(
 	return resp
 }
 
-// UpdateServices 对于服务修改来说，只针对服务本身，而不需要检查命名空间
+// UpdateServices 对于服务修改来说，只针对服务本身，而不需要检查命名空间的授权，命名空间上的拒绝策略仍然生效
 func (svr *serverAuthAbility) UpdateServices(
 	ctx context.Context, reqs []*apiservice.Service) *apiservice.BatchWriteResponse {
 	authCtx := svr.collectServiceAuthContext(ctx, reqs, model.Modify, "UpdateServices")
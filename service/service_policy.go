@@ -0,0 +1,124 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+
+	"github.com/polarismesh/polaris/common/model"
+	commontime "github.com/polarismesh/polaris/common/time"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// ServicePolicy 挂载在服务上的策略的公共字段，各类策略共用
+type ServicePolicy struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Service     string `json:"service"`
+	Description string `json:"description"`
+	Enable      bool   `json:"enable"`
+	Revision    string `json:"revision,omitempty"`
+	CreateTime  string `json:"ctime,omitempty"`
+	ModifyTime  string `json:"mtime,omitempty"`
+}
+
+// newServicePolicy 将存储层的策略公共字段转换为 API 对象
+func newServicePolicy(policy *model.ServicePolicy) ServicePolicy {
+	return ServicePolicy{
+		ID:          policy.ID,
+		Name:        policy.Name,
+		Namespace:   policy.Namespace,
+		Service:     policy.Service,
+		Description: policy.Description,
+		Enable:      policy.Enable,
+		Revision:    policy.Revision,
+		CreateTime:  commontime.Time2String(policy.CreateTime),
+		ModifyTime:  commontime.Time2String(policy.ModifyTime),
+	}
+}
+
+// toModel 将 API 对象转换为存储层的策略公共字段，spec 序列化之后作为策略内容
+func (p *ServicePolicy) toModel(spec interface{}) (model.ServicePolicy, error) {
+	rule, err := json.Marshal(spec)
+	if err != nil {
+		return model.ServicePolicy{}, err
+	}
+	return model.ServicePolicy{
+		ID:          p.ID,
+		Name:        p.Name,
+		Namespace:   p.Namespace,
+		Service:     p.Service,
+		Description: p.Description,
+		Enable:      p.Enable,
+		Rule:        string(rule),
+	}, nil
+}
+
+// checkServicePolicy 检查策略的公共参数，策略所属的服务需要存在，message 为空时使用错误码默认的描述
+func (s *Server) checkServicePolicy(req *ServicePolicy) (apimodel.Code, string) {
+	if req.Name == "" || utils.CheckDbRawStrFieldLen(req.Name, MaxRuleName) != nil {
+		return apimodel.Code_BadRequest, "invalid name"
+	}
+	if req.Namespace == "" || utils.CheckDbRawStrFieldLen(req.Namespace, MaxDbServiceNamespaceLength) != nil {
+		return apimodel.Code_InvalidNamespaceName, ""
+	}
+	if req.Service == "" || utils.CheckDbRawStrFieldLen(req.Service, MaxDbServiceNameLength) != nil {
+		return apimodel.Code_InvalidServiceName, ""
+	}
+	if utils.CheckDbRawStrFieldLen(req.Description, MaxCommentLength) != nil {
+		return apimodel.Code_InvalidServiceComment, ""
+	}
+	svc, resp := s.loadService(req.Namespace, req.Service)
+	if resp != nil {
+		return apimodel.Code(resp.GetCode().GetValue()), resp.GetInfo().GetValue()
+	}
+	if svc == nil {
+		return apimodel.Code_NotFoundService, ""
+	}
+	return apimodel.Code_ExecuteSuccess, ""
+}
+
+// parseServicePolicyQuery 解析策略的查询参数，返回过滤条件以及分页参数
+func parseServicePolicyQuery(query map[string]string) (map[string]string, uint32, uint32, error) {
+	filter := make(map[string]string, len(query))
+	for k, v := range query {
+		filter[k] = v
+	}
+	offset, limit, err := utils.ParseOffsetAndLimit(filter)
+	return filter, offset, limit, err
+}
+
+// servicePolicyRecordEntry 构建策略的操作记录
+func servicePolicyRecordEntry(ctx context.Context, resourceType model.Resource, policy *model.ServicePolicy,
+	opt model.OperationType) *model.RecordEntry {
+	return &model.RecordEntry{
+		ResourceType:  resourceType,
+		ResourceName:  fmt.Sprintf("%s(%s)", policy.Name, policy.ID),
+		Namespace:     policy.Namespace,
+		OperationType: opt,
+		Operator:      utils.ParseOperator(ctx),
+		Detail:        policy.Rule,
+		HappenTime:    time.Now(),
+	}
+}
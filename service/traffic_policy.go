@@ -0,0 +1,319 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"go.uber.org/zap"
+
+	api "github.com/polarismesh/polaris/common/api/v1"
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+// supportedRetryOn envoy 支持的重试条件
+var supportedRetryOn = map[string]struct{}{
+	"5xx":                        {},
+	"gateway-error":              {},
+	"reset":                      {},
+	"connect-failure":            {},
+	"envoy-ratelimited":          {},
+	"retriable-4xx":              {},
+	"refused-stream":             {},
+	"retriable-status-codes":     {},
+	"retriable-headers":          {},
+	"http3-post-connect-failure": {},
+	"cancelled":                  {},
+	"deadline-exceeded":          {},
+	"internal":                   {},
+	"resource-exhausted":         {},
+	"unavailable":                {},
+}
+
+// TrafficPolicy 流量策略
+type TrafficPolicy struct {
+	ServicePolicy
+	RouteName string                   `json:"route_name"`
+	Spec      *model.TrafficPolicySpec `json:"spec"`
+}
+
+// TrafficPolicyResponse 流量策略操作结果
+type TrafficPolicyResponse struct {
+	Code   uint32         `json:"code"`
+	Info   string         `json:"info"`
+	Policy *TrafficPolicy `json:"policy,omitempty"`
+}
+
+// NewTrafficPolicyResponse 创建流量策略操作结果
+func NewTrafficPolicyResponse(code apimodel.Code, policy *model.TrafficPolicy) *TrafficPolicyResponse {
+	return &TrafficPolicyResponse{
+		Code:   uint32(code),
+		Info:   api.Code2Info(uint32(code)),
+		Policy: trafficPolicy2Api(policy),
+	}
+}
+
+// NewTrafficPolicyResponseWithMessage 创建带有错误信息的流量策略操作结果
+func NewTrafficPolicyResponseWithMessage(code apimodel.Code, message string) *TrafficPolicyResponse {
+	return &TrafficPolicyResponse{
+		Code: uint32(code),
+		Info: api.Code2Info(uint32(code)) + ":" + message,
+	}
+}
+
+// TrafficPolicyBatchResponse 流量策略查询结果
+type TrafficPolicyBatchResponse struct {
+	Code     uint32           `json:"code"`
+	Info     string           `json:"info"`
+	Total    uint32           `json:"total"`
+	Size     uint32           `json:"size"`
+	Policies []*TrafficPolicy `json:"policies"`
+}
+
+// NewTrafficPolicyBatchResponse 创建流量策略查询结果
+func NewTrafficPolicyBatchResponse(code apimodel.Code, total uint32,
+	policies []*model.TrafficPolicy) *TrafficPolicyBatchResponse {
+	ret := &TrafficPolicyBatchResponse{
+		Code:     uint32(code),
+		Info:     api.Code2Info(uint32(code)),
+		Total:    total,
+		Size:     uint32(len(policies)),
+		Policies: make([]*TrafficPolicy, 0, len(policies)),
+	}
+	for _, policy := range policies {
+		ret.Policies = append(ret.Policies, trafficPolicy2Api(policy))
+	}
+	return ret
+}
+
+// CreateTrafficPolicy 创建流量策略，同一个服务的同一个路由只能有一个流量策略
+func (s *Server) CreateTrafficPolicy(ctx context.Context, req *TrafficPolicy) *TrafficPolicyResponse {
+	if resp := s.checkTrafficPolicy(req); resp != nil {
+		return resp
+	}
+	if resp := s.checkTrafficPolicyConflict(ctx, req); resp != nil {
+		return resp
+	}
+
+	policy, err := api2TrafficPolicy(req)
+	if err != nil {
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+	}
+	policy.ID = utils.NewUUID()
+	policy.Revision = utils.NewUUID()
+	if err := s.storage.CreateTrafficPolicy(policy); err != nil {
+		log.Error("[Service][TrafficPolicy] create traffic policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+
+	log.Info("[Service][TrafficPolicy] create traffic policy", utils.ZapRequestIDByCtx(ctx),
+		zap.String("id", policy.ID), zap.String("namespace", policy.Namespace),
+		zap.String("service", policy.Service), zap.String("route", policy.RouteName))
+	s.RecordHistory(ctx, trafficPolicyRecordEntry(ctx, policy, model.OCreate))
+	return NewTrafficPolicyResponse(apimodel.Code_ExecuteSuccess, policy)
+}
+
+// UpdateTrafficPolicy 更新流量策略
+func (s *Server) UpdateTrafficPolicy(ctx context.Context, req *TrafficPolicy) *TrafficPolicyResponse {
+	if req == nil || req.ID == "" {
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_BadRequest, "id is required")
+	}
+	if resp := s.checkTrafficPolicy(req); resp != nil {
+		return resp
+	}
+	saved, err := s.storage.GetTrafficPolicyWithID(req.ID)
+	if err != nil {
+		log.Error("[Service][TrafficPolicy] get traffic policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+	if saved == nil {
+		return NewTrafficPolicyResponse(apimodel.Code_NotFoundResource, nil)
+	}
+	if resp := s.checkTrafficPolicyConflict(ctx, req); resp != nil {
+		return resp
+	}
+
+	policy, err := api2TrafficPolicy(req)
+	if err != nil {
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_ParseException, err.Error())
+	}
+	policy.Revision = utils.NewUUID()
+	policy.CreateTime = saved.CreateTime
+	if err := s.storage.UpdateTrafficPolicy(policy); err != nil {
+		log.Error("[Service][TrafficPolicy] update traffic policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+
+	log.Info("[Service][TrafficPolicy] update traffic policy", utils.ZapRequestIDByCtx(ctx),
+		zap.String("id", policy.ID))
+	s.RecordHistory(ctx, trafficPolicyRecordEntry(ctx, policy, model.OUpdate))
+	return NewTrafficPolicyResponse(apimodel.Code_ExecuteSuccess, policy)
+}
+
+// DeleteTrafficPolicy 删除流量策略
+func (s *Server) DeleteTrafficPolicy(ctx context.Context, id string) *TrafficPolicyResponse {
+	if id == "" {
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_BadRequest, "id is required")
+	}
+	saved, err := s.storage.GetTrafficPolicyWithID(id)
+	if err != nil {
+		log.Error("[Service][TrafficPolicy] get traffic policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+	if saved == nil {
+		return NewTrafficPolicyResponse(apimodel.Code_ExecuteSuccess, nil)
+	}
+	if err := s.storage.DeleteTrafficPolicy(id); err != nil {
+		log.Error("[Service][TrafficPolicy] delete traffic policy", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+
+	log.Info("[Service][TrafficPolicy] delete traffic policy", utils.ZapRequestIDByCtx(ctx), zap.String("id", id))
+	s.RecordHistory(ctx, trafficPolicyRecordEntry(ctx, saved, model.ODelete))
+	return NewTrafficPolicyResponse(apimodel.Code_ExecuteSuccess, saved)
+}
+
+// GetTrafficPolicies 查询流量策略，支持 id、name、namespace、service 以及 route_name 过滤
+func (s *Server) GetTrafficPolicies(ctx context.Context, query map[string]string) *TrafficPolicyBatchResponse {
+	filter, offset, limit, err := parseServicePolicyQuery(query)
+	if err != nil {
+		return &TrafficPolicyBatchResponse{
+			Code: uint32(apimodel.Code_InvalidParameter),
+			Info: api.Code2Info(uint32(apimodel.Code_InvalidParameter)) + ":" + err.Error(),
+		}
+	}
+	total, policies, err := s.storage.GetTrafficPolicies(filter, offset, limit)
+	if err != nil {
+		log.Error("[Service][TrafficPolicy] get traffic policies", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewTrafficPolicyBatchResponse(apimodel.Code_StoreLayerException, 0, nil)
+	}
+	for _, policy := range policies {
+		if err := policy.ParseSpec(); err != nil {
+			log.Error("[Service][TrafficPolicy] parse traffic policy rule", zap.String("id", policy.ID),
+				zap.Error(err))
+		}
+	}
+	return NewTrafficPolicyBatchResponse(apimodel.Code_ExecuteSuccess, total, policies)
+}
+
+// checkTrafficPolicy 检查流量策略的参数，策略所属的服务需要存在
+func (s *Server) checkTrafficPolicy(req *TrafficPolicy) *TrafficPolicyResponse {
+	if req == nil {
+		return NewTrafficPolicyResponse(apimodel.Code_EmptyRequest, nil)
+	}
+	if code, message := s.checkServicePolicy(&req.ServicePolicy); code != apimodel.Code_ExecuteSuccess {
+		if message == "" {
+			return NewTrafficPolicyResponse(code, nil)
+		}
+		return NewTrafficPolicyResponseWithMessage(code, message)
+	}
+	if utils.CheckDbRawStrFieldLen(req.RouteName, MaxRuleName) != nil {
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_BadRequest, "invalid route_name")
+	}
+	if err := checkTrafficPolicySpec(req.Spec); err != nil {
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_BadRequest, err.Error())
+	}
+	return nil
+}
+
+// checkTrafficPolicyConflict 同一个服务的同一个路由只允许存在一个流量策略，否则下发时无法确定使用哪一个
+func (s *Server) checkTrafficPolicyConflict(ctx context.Context, req *TrafficPolicy) *TrafficPolicyResponse {
+	_, policies, err := s.storage.GetTrafficPolicies(map[string]string{
+		"namespace": req.Namespace,
+		"service":   req.Service,
+	}, 0, utils.MaxBatchSize)
+	if err != nil {
+		log.Error("[Service][TrafficPolicy] get traffic policies", utils.ZapRequestIDByCtx(ctx), zap.Error(err))
+		return NewTrafficPolicyResponseWithMessage(apimodel.Code_StoreLayerException, err.Error())
+	}
+	for _, policy := range policies {
+		if policy.ID != req.ID && policy.RouteName == req.RouteName {
+			return NewTrafficPolicyResponseWithMessage(apimodel.Code_ExistedResource,
+				fmt.Sprintf("traffic policy %s already exists for route %q", policy.Name, req.RouteName))
+		}
+	}
+	return nil
+}
+
+func checkTrafficPolicySpec(spec *model.TrafficPolicySpec) error {
+	if spec == nil {
+		return fmt.Errorf("spec is required")
+	}
+	if retry := spec.Retry; retry != nil {
+		for _, cond := range retry.RetryOn {
+			if _, ok := supportedRetryOn[cond]; !ok {
+				return fmt.Errorf("unsupported retry_on condition: %s", cond)
+			}
+		}
+		if spec.Timeout > 0 && retry.PerTryTimeout > spec.Timeout {
+			return fmt.Errorf("per_try_timeout can not be greater than timeout")
+		}
+		if retry.Budget != nil && (retry.Budget.BudgetPercent < 0 || retry.Budget.BudgetPercent > 100) {
+			return fmt.Errorf("budget_percent must be between 0 and 100")
+		}
+	}
+	if fault := spec.Fault; fault != nil {
+		if fault.Delay != nil && !validPercentage(fault.Delay.Percentage) {
+			return fmt.Errorf("delay percentage must be between 0 and 100")
+		}
+		if fault.Abort != nil {
+			if !validPercentage(fault.Abort.Percentage) {
+				return fmt.Errorf("abort percentage must be between 0 and 100")
+			}
+			if fault.Abort.HttpStatus < 200 || fault.Abort.HttpStatus >= 600 {
+				return fmt.Errorf("abort http_status must be between 200 and 599")
+			}
+		}
+	}
+	return nil
+}
+
+func validPercentage(percent float64) bool {
+	return percent >= 0 && percent <= 100
+}
+
+func api2TrafficPolicy(req *TrafficPolicy) (*model.TrafficPolicy, error) {
+	policy, err := req.ServicePolicy.toModel(req.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TrafficPolicy{
+		ServicePolicy: policy,
+		RouteName:     req.RouteName,
+		Spec:          req.Spec,
+	}, nil
+}
+
+func trafficPolicy2Api(policy *model.TrafficPolicy) *TrafficPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &TrafficPolicy{
+		ServicePolicy: newServicePolicy(&policy.ServicePolicy),
+		RouteName:     policy.RouteName,
+		Spec:          policy.Spec,
+	}
+}
+
+func trafficPolicyRecordEntry(ctx context.Context, policy *model.TrafficPolicy,
+	opt model.OperationType) *model.RecordEntry {
+	return servicePolicyRecordEntry(ctx, model.RTrafficPolicy, &policy.ServicePolicy, opt)
+}
@@ -0,0 +1,72 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service
+
+import (
+	"context"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/common/utils"
+)
+
+func (svr *serverAuthAbility) CreateTrafficPolicy(ctx context.Context, req *TrafficPolicy) *TrafficPolicyResponse {
+	authCtx := svr.collectServicePolicyAuthContext(ctx, &req.ServicePolicy, svr.savedTrafficPolicy,
+		model.Create, "CreateTrafficPolicy")
+	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewTrafficPolicyResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return svr.targetServer.CreateTrafficPolicy(ctx, req)
+}
+
+func (svr *serverAuthAbility) UpdateTrafficPolicy(ctx context.Context, req *TrafficPolicy) *TrafficPolicyResponse {
+	authCtx := svr.collectServicePolicyAuthContext(ctx, &req.ServicePolicy, svr.savedTrafficPolicy,
+		model.Modify, "UpdateTrafficPolicy")
+	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewTrafficPolicyResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return svr.targetServer.UpdateTrafficPolicy(ctx, req)
+}
+
+func (svr *serverAuthAbility) DeleteTrafficPolicy(ctx context.Context, id string) *TrafficPolicyResponse {
+	authCtx := svr.collectServicePolicyAuthContext(ctx, &ServicePolicy{ID: id}, svr.savedTrafficPolicy,
+		model.Delete, "DeleteTrafficPolicy")
+	if _, err := svr.strategyMgn.GetAuthChecker().CheckConsolePermission(authCtx); err != nil {
+		return NewTrafficPolicyResponseWithMessage(convertToErrCode(err), err.Error())
+	}
+	ctx = authCtx.GetRequestContext()
+	ctx = context.WithValue(ctx, utils.ContextAuthContextKey, authCtx)
+	return svr.targetServer.DeleteTrafficPolicy(ctx, id)
+}
+
+func (svr *serverAuthAbility) GetTrafficPolicies(
+	ctx context.Context, query map[string]string) *TrafficPolicyBatchResponse {
+	return svr.targetServer.GetTrafficPolicies(ctx, query)
+}
+
+// savedTrafficPolicy 查询存储中的流量策略，用于鉴权时确定策略所属的服务
+func (svr *serverAuthAbility) savedTrafficPolicy(id string) *model.ServicePolicy {
+	data, err := svr.targetServer.storage.GetTrafficPolicyWithID(id)
+	if err != nil || data == nil {
+		return nil
+	}
+	return &data.ServicePolicy
+}
@@ -0,0 +1,137 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package service_test
+
+import (
+	"testing"
+
+	apimodel "github.com/polarismesh/specification/source/go/api/v1/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/service"
+)
+
+func buildTrafficPolicy(svcName, namespace, routeName string) *service.TrafficPolicy {
+	return &service.TrafficPolicy{
+		ServicePolicy: service.ServicePolicy{
+			Name:      "test-traffic-policy-" + routeName,
+			Namespace: namespace,
+			Service:   svcName,
+			Enable:    true,
+		},
+		RouteName: routeName,
+		Spec: &model.TrafficPolicySpec{
+			Timeout: 3000,
+			Retry: &model.RetryPolicy{
+				RetryOn:       []string{"5xx", "connect-failure"},
+				NumRetries:    2,
+				PerTryTimeout: 1000,
+			},
+			Fault: &model.FaultInjection{
+				Abort: &model.FaultAbort{Percentage: 10, HttpStatus: 503},
+			},
+		},
+	}
+}
+
+func TestTrafficPolicy(t *testing.T) {
+	discoverSuit := &DiscoverTestSuit{}
+	if err := discoverSuit.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	defer discoverSuit.Destroy()
+
+	_, svc := discoverSuit.createCommonService(t, 100)
+	defer discoverSuit.cleanServiceName(svc.GetName().GetValue(), svc.GetNamespace().GetValue())
+	svcName, namespace := svc.GetName().GetValue(), svc.GetNamespace().GetValue()
+
+	t.Run("创建流量策略，返回成功", func(t *testing.T) {
+		resp := discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx,
+			buildTrafficPolicy(svcName, namespace, ""))
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.Code, resp.Info)
+		assert.NotEmpty(t, resp.Policy.ID)
+		defer discoverSuit.DiscoverServer().DeleteTrafficPolicy(discoverSuit.DefaultCtx, resp.Policy.ID)
+
+		qresp := discoverSuit.DiscoverServer().GetTrafficPolicies(discoverSuit.DefaultCtx, map[string]string{
+			"namespace": namespace,
+			"service":   svcName,
+		})
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), qresp.Code, qresp.Info)
+		assert.Equal(t, uint32(1), qresp.Total)
+		assert.Equal(t, uint32(2), qresp.Policies[0].Spec.Retry.NumRetries)
+
+		// 同一个服务的服务级策略只能有一个
+		dresp := discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx,
+			buildTrafficPolicy(svcName, namespace, ""))
+		assert.Equal(t, uint32(apimodel.Code_ExistedResource), dresp.Code)
+
+		// 路由级策略可以和服务级策略共存
+		rresp := discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx,
+			buildTrafficPolicy(svcName, namespace, "route-1"))
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), rresp.Code, rresp.Info)
+		defer discoverSuit.DiscoverServer().DeleteTrafficPolicy(discoverSuit.DefaultCtx, rresp.Policy.ID)
+	})
+
+	t.Run("创建流量策略，参数非法，返回错误", func(t *testing.T) {
+		policy := buildTrafficPolicy(svcName, namespace, "")
+		policy.Spec.Retry.RetryOn = []string{"unknown"}
+		resp := discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), resp.Code)
+
+		policy = buildTrafficPolicy(svcName, namespace, "")
+		policy.Spec.Retry.PerTryTimeout = 5000
+		resp = discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), resp.Code)
+
+		policy = buildTrafficPolicy(svcName, namespace, "")
+		policy.Spec.Fault.Abort.Percentage = 120
+		resp = discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_BadRequest), resp.Code)
+
+		policy = buildTrafficPolicy("not-exist-service", namespace, "")
+		resp = discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx, policy)
+		assert.NotEqual(t, uint32(apimodel.Code_ExecuteSuccess), resp.Code)
+	})
+
+	t.Run("更新、删除流量策略，返回成功", func(t *testing.T) {
+		resp := discoverSuit.DiscoverServer().CreateTrafficPolicy(discoverSuit.DefaultCtx,
+			buildTrafficPolicy(svcName, namespace, ""))
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), resp.Code, resp.Info)
+
+		policy := buildTrafficPolicy(svcName, namespace, "")
+		policy.ID = resp.Policy.ID
+		policy.Spec.Timeout = 5000
+		uresp := discoverSuit.DiscoverServer().UpdateTrafficPolicy(discoverSuit.DefaultCtx, policy)
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), uresp.Code, uresp.Info)
+		assert.NotEqual(t, resp.Policy.Revision, uresp.Policy.Revision)
+
+		qresp := discoverSuit.DiscoverServer().GetTrafficPolicies(discoverSuit.DefaultCtx, map[string]string{
+			"id": policy.ID,
+		})
+		assert.Equal(t, uint32(1), qresp.Total)
+		assert.Equal(t, uint32(5000), qresp.Policies[0].Spec.Timeout)
+
+		dresp := discoverSuit.DiscoverServer().DeleteTrafficPolicy(discoverSuit.DefaultCtx, policy.ID)
+		assert.Equal(t, uint32(apimodel.Code_ExecuteSuccess), dresp.Code, dresp.Info)
+		qresp = discoverSuit.DiscoverServer().GetTrafficPolicies(discoverSuit.DefaultCtx, map[string]string{
+			"id": policy.ID,
+		})
+		assert.Equal(t, uint32(0), qresp.Total)
+	})
+}
@@ -26,6 +26,7 @@ import (
 const (
 	ElectionKeySelfServiceChecker = "polaris.checker"
 	ElectionKeyMaintainJobPrefix  = "MaintainJob."
+	ElectionKeyAuthLDAPSync       = "polaris.auth.ldap"
 )
 
 type AdminStore interface {
@@ -51,6 +52,31 @@ type AdminStore interface {
 	BatchCleanDeletedClients(timeout time.Duration, batchSize uint32) (uint32, error)
 }
 
+// OperationRecordStore 操作记录存储接口
+type OperationRecordStore interface {
+	// AddOperationRecords 批量保存操作记录
+	AddOperationRecords(records []*model.RecordEntry) error
+
+	// GetOperationRecords 根据过滤条件以及时间范围查询操作记录，按照发生时间倒序返回
+	GetOperationRecords(filter map[string]string, startTime, endTime time.Time,
+		offset, limit uint32) (uint32, []*model.RecordEntry, error)
+
+	// BatchCleanOperationRecords 批量清理指定时间之前的操作记录
+	BatchCleanOperationRecords(before time.Time, batchSize uint32) (uint32, error)
+}
+
+// CertificateAuthorityStore xDS 内置 CA 存储接口
+type CertificateAuthorityStore interface {
+	// GetCertificateAuthority 根据 ID 获取 CA，不存在时返回 nil
+	GetCertificateAuthority(id string) (*model.CertificateAuthority, error)
+
+	// CreateCertificateAuthority 创建 CA，已经存在时不做修改，多个节点并发创建时需要重新读取最终生效的记录
+	CreateCertificateAuthority(ca *model.CertificateAuthority) error
+
+	// SaveCertificateAuthority 创建或者覆盖 CA
+	SaveCertificateAuthority(ca *model.CertificateAuthority) error
+}
+
 // LeaderChangeEvent
 type LeaderChangeEvent struct {
 	Key        string
@@ -54,6 +54,12 @@ type Store interface {
 
 	// AdminStore Maintain inteface
 	AdminStore
+
+	// OperationRecordStore Operation record storage interface
+	OperationRecordStore
+
+	// CertificateAuthorityStore xDS certificate authority storage interface
+	CertificateAuthorityStore
 }
 
 // NamespaceStore Namespace storage interface
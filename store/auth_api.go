@@ -81,6 +81,17 @@ type GroupStore interface {
 	GetGroupsForCache(mtime time.Time, firstUpdate bool) ([]*model.UserGroupDetail, error)
 }
 
+// RevokedTokenStore Revocation list storage interface of scoped tokens
+type RevokedTokenStore interface {
+
+	// AddRevokedToken Add a revoked token, expired ones are cleaned at the same time
+	AddRevokedToken(token *model.RevokedToken) error
+
+	// GetRevokedTokensForCache Get revoked tokens which not expired for cache
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetRevokedTokensForCache(mtime time.Time, firstUpdate bool) ([]*model.RevokedToken, error)
+}
+
 // StrategyStore Authentication policy related storage operation interface
 type StrategyStore interface {
 
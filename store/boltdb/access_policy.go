@@ -0,0 +1,136 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	tblAccessPolicy string = "access_policy"
+
+	AccessPolicyFieldDryRun string = "DryRun"
+)
+
+// accessPolicyObject 访问控制策略的存储对象，Spec 为解析后的内容不需要持久化
+type accessPolicyObject struct {
+	ID          string
+	Name        string
+	Namespace   string
+	Service     string
+	Description string
+	Enable      bool
+	DryRun      bool
+	Rule        string
+	Revision    string
+	Valid       bool
+	CreateTime  time.Time
+	ModifyTime  time.Time
+}
+
+var accessPolicyTable = &servicePolicyTable[*model.AccessPolicy]{
+	name: tblAccessPolicy,
+	newObject: func() interface{} {
+		return &accessPolicyObject{}
+	},
+	toObject: func(policy *model.AccessPolicy) interface{} {
+		return toAccessPolicyObject(policy)
+	},
+	toModel: func(object interface{}) *model.AccessPolicy {
+		return toAccessPolicyModel(object.(*accessPolicyObject))
+	},
+	extraFields: func(policy *model.AccessPolicy) map[string]interface{} {
+		return map[string]interface{}{
+			AccessPolicyFieldDryRun: policy.DryRun,
+		}
+	},
+}
+
+type accessPolicyStore struct {
+	handler BoltHandler
+}
+
+// CreateAccessPolicy 新增访问控制策略
+func (t *accessPolicyStore) CreateAccessPolicy(policy *model.AccessPolicy) error {
+	return accessPolicyTable.create(t.handler, policy)
+}
+
+// UpdateAccessPolicy 更新访问控制策略
+func (t *accessPolicyStore) UpdateAccessPolicy(policy *model.AccessPolicy) error {
+	return accessPolicyTable.update(t.handler, policy)
+}
+
+// DeleteAccessPolicy 删除访问控制策略
+func (t *accessPolicyStore) DeleteAccessPolicy(id string) error {
+	return accessPolicyTable.delete(t.handler, id)
+}
+
+// GetAccessPolicyWithID 根据ID获取访问控制策略
+func (t *accessPolicyStore) GetAccessPolicyWithID(id string) (*model.AccessPolicy, error) {
+	return accessPolicyTable.getWithID(t.handler, id)
+}
+
+// GetAccessPolicies 根据过滤条件查询访问控制策略，name 为模糊查询
+func (t *accessPolicyStore) GetAccessPolicies(filter map[string]string,
+	offset uint32, limit uint32) (uint32, []*model.AccessPolicy, error) {
+	return accessPolicyTable.list(t.handler, filter, offset, limit)
+}
+
+// GetAccessPoliciesForCache 根据修改时间拉取增量访问控制策略
+func (t *accessPolicyStore) GetAccessPoliciesForCache(mtime time.Time,
+	firstUpdate bool) ([]*model.AccessPolicy, error) {
+	return accessPolicyTable.listForCache(t.handler, mtime, firstUpdate)
+}
+
+func toAccessPolicyObject(policy *model.AccessPolicy) *accessPolicyObject {
+	return &accessPolicyObject{
+		ID:          policy.ID,
+		Name:        policy.Name,
+		Namespace:   policy.Namespace,
+		Service:     policy.Service,
+		Description: policy.Description,
+		Enable:      policy.Enable,
+		DryRun:      policy.DryRun,
+		Rule:        policy.Rule,
+		Revision:    policy.Revision,
+		Valid:       policy.Valid,
+		CreateTime:  policy.CreateTime,
+		ModifyTime:  policy.ModifyTime,
+	}
+}
+
+func toAccessPolicyModel(item *accessPolicyObject) *model.AccessPolicy {
+	return &model.AccessPolicy{
+		ServicePolicy: model.ServicePolicy{
+			ID:          item.ID,
+			Name:        item.Name,
+			Namespace:   item.Namespace,
+			Service:     item.Service,
+			Description: item.Description,
+			Enable:      item.Enable,
+			Rule:        item.Rule,
+			Revision:    item.Revision,
+			Valid:       item.Valid,
+			CreateTime:  item.CreateTime,
+			ModifyTime:  item.ModifyTime,
+		},
+		DryRun: item.DryRun,
+	}
+}
@@ -0,0 +1,111 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func Test_accessPolicyStore(t *testing.T) {
+	CreateTableDBHandlerAndRun(t, tblAccessPolicy, func(t *testing.T, handler BoltHandler) {
+		s := &accessPolicyStore{handler: handler}
+
+		start := time.Now()
+		policies := []*model.AccessPolicy{
+			{
+				ServicePolicy: model.ServicePolicy{
+					ID:        "policy-1",
+					Name:      "allow-frontend",
+					Namespace: "default",
+					Service:   "echo",
+					Enable:    true,
+					Rule:      `{"sources":[{"namespace":"default","service":"frontend"}]}`,
+					Revision:  "rev-1",
+				},
+			},
+			{
+				ServicePolicy: model.ServicePolicy{
+					ID:        "policy-2",
+					Name:      "allow-ops",
+					Namespace: "default",
+					Service:   "echo",
+					Enable:    true,
+					Rule:      `{"sources":[{"namespace":"ops","service":"*"}],"paths":["/admin/*"]}`,
+					Revision:  "rev-2",
+				},
+				DryRun: true,
+			},
+		}
+		for i := range policies {
+			assert.NoError(t, s.CreateAccessPolicy(policies[i]))
+		}
+
+		t.Run("按照ID以及服务查询", func(t *testing.T) {
+			ret, err := s.GetAccessPolicyWithID("policy-2")
+			assert.NoError(t, err)
+			assert.Equal(t, "allow-ops", ret.Name)
+			assert.True(t, ret.DryRun)
+			assert.Equal(t, policies[1].Rule, ret.Rule)
+
+			total, list, err := s.GetAccessPolicies(map[string]string{
+				"namespace": "default",
+				"service":   "echo",
+			}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), total)
+			assert.Equal(t, 2, len(list))
+
+			total, _, err = s.GetAccessPolicies(map[string]string{"name": "ops"}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+		})
+
+		t.Run("关闭演练模式", func(t *testing.T) {
+			update := *policies[1]
+			update.DryRun = false
+			update.Revision = "rev-3"
+			assert.NoError(t, s.UpdateAccessPolicy(&update))
+
+			ret, err := s.GetAccessPolicyWithID("policy-2")
+			assert.NoError(t, err)
+			assert.False(t, ret.DryRun)
+			assert.Equal(t, "rev-3", ret.Revision)
+		})
+
+		t.Run("删除后增量拉取缓存数据", func(t *testing.T) {
+			assert.NoError(t, s.DeleteAccessPolicy("policy-1"))
+
+			ret, err := s.GetAccessPolicyWithID("policy-1")
+			assert.NoError(t, err)
+			assert.Nil(t, ret)
+
+			list, err := s.GetAccessPoliciesForCache(time.Time{}, true)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(list))
+
+			list, err = s.GetAccessPoliciesForCache(start, false)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(list))
+		})
+	})
+}
@@ -0,0 +1,116 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblCertificateAuthority string = "xds_certificate_authority"
+)
+
+type certificateAuthorityObject struct {
+	ID         string
+	CertPEM    string
+	KeyPEM     string
+	ChainPEM   string
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+type certificateAuthorityStore struct {
+	handler BoltHandler
+}
+
+// GetCertificateAuthority 根据 ID 获取 CA，不存在时返回 nil
+func (cs *certificateAuthorityStore) GetCertificateAuthority(id string) (*model.CertificateAuthority, error) {
+	ret, err := cs.handler.LoadValues(tblCertificateAuthority, []string{id}, &certificateAuthorityObject{})
+	if err != nil {
+		log.Error("[Store][CA] get certificate authority", zap.String("id", id), zap.Error(err))
+		return nil, err
+	}
+	val, ok := ret[id]
+	if !ok {
+		return nil, nil
+	}
+	item := val.(*certificateAuthorityObject)
+	return &model.CertificateAuthority{
+		ID:         item.ID,
+		CertPEM:    item.CertPEM,
+		KeyPEM:     item.KeyPEM,
+		ChainPEM:   item.ChainPEM,
+		CreateTime: item.CreateTime,
+		ModifyTime: item.ModifyTime,
+	}, nil
+}
+
+// CreateCertificateAuthority 创建 CA，已经存在时不做修改
+func (cs *certificateAuthorityStore) CreateCertificateAuthority(ca *model.CertificateAuthority) error {
+	if ca.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "certificate authority id is empty")
+	}
+	return cs.handler.Execute(true, func(tx *bolt.Tx) error {
+		values := make(map[string]interface{})
+		if err := loadValues(tx, tblCertificateAuthority, []string{ca.ID}, &certificateAuthorityObject{},
+			values); err != nil {
+			return err
+		}
+		if _, ok := values[ca.ID]; ok {
+			return nil
+		}
+		return saveValue(tx, tblCertificateAuthority, ca.ID, newCertificateAuthorityObject(ca, time.Now()))
+	})
+}
+
+// SaveCertificateAuthority 创建或者覆盖 CA
+func (cs *certificateAuthorityStore) SaveCertificateAuthority(ca *model.CertificateAuthority) error {
+	if ca.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "certificate authority id is empty")
+	}
+	return cs.handler.Execute(true, func(tx *bolt.Tx) error {
+		values := make(map[string]interface{})
+		if err := loadValues(tx, tblCertificateAuthority, []string{ca.ID}, &certificateAuthorityObject{},
+			values); err != nil {
+			return err
+		}
+		tn := time.Now()
+		obj := newCertificateAuthorityObject(ca, tn)
+		if old, ok := values[ca.ID]; ok {
+			obj.CreateTime = old.(*certificateAuthorityObject).CreateTime
+		}
+		return saveValue(tx, tblCertificateAuthority, ca.ID, obj)
+	})
+}
+
+func newCertificateAuthorityObject(ca *model.CertificateAuthority, tn time.Time) *certificateAuthorityObject {
+	return &certificateAuthorityObject{
+		ID:         ca.ID,
+		CertPEM:    ca.CertPEM,
+		KeyPEM:     ca.KeyPEM,
+		ChainPEM:   ca.ChainPEM,
+		CreateTime: tn,
+		ModifyTime: tn,
+	}
+}
@@ -0,0 +1,287 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblConfigFileApprovalPolicy   string = "ConfigFileApprovalPolicy"
+	tblConfigFileApprovalPolicyID string = "ConfigFileApprovalPolicyID"
+	tblConfigFileReleaseRequest   string = "ConfigFileReleaseRequest"
+	tblConfigFileReleaseRequestID string = "ConfigFileReleaseRequestID"
+
+	FileApprovalFieldRequiredApprovals string = "RequiredApprovals"
+	FileApprovalFieldUsers             string = "Users"
+	FileApprovalFieldUserGroups        string = "UserGroups"
+	FileApprovalFieldModifyTime        string = "ModifyTime"
+	FileApprovalFieldModifyBy          string = "ModifyBy"
+
+	FileReleaseRequestFieldId         string = "Id"
+	FileReleaseRequestFieldNamespace  string = "Namespace"
+	FileReleaseRequestFieldGroup      string = "Group"
+	FileReleaseRequestFieldFileName   string = "FileName"
+	FileReleaseRequestFieldStatus     string = "Status"
+	FileReleaseRequestFieldApprovers  string = "Approvers"
+	FileReleaseRequestFieldReason     string = "Reason"
+	FileReleaseRequestFieldRevision   string = "Revision"
+	FileReleaseRequestFieldModifyBy   string = "ModifyBy"
+	FileReleaseRequestFieldModifyTime string = "ModifyTime"
+)
+
+type configFileApprovalStore struct {
+	policyId  uint64
+	requestId uint64
+	handler   BoltHandler
+}
+
+func newConfigFileApprovalStore(handler BoltHandler) (*configFileApprovalStore, error) {
+	s := &configFileApprovalStore{handler: handler}
+	ret, err := handler.LoadValues(tblConfigFileApprovalPolicyID, []string{tblConfigFileApprovalPolicyID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) > 0 {
+		s.policyId = ret[tblConfigFileApprovalPolicyID].(*IDHolder).ID
+	}
+	ret, err = handler.LoadValues(tblConfigFileReleaseRequestID, []string{tblConfigFileReleaseRequestID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) > 0 {
+		s.requestId = ret[tblConfigFileReleaseRequestID].(*IDHolder).ID
+	}
+	return s, nil
+}
+
+// SaveConfigFileApprovalPolicy 保存配置发布审批策略，已存在则覆盖
+func (cs *configFileApprovalStore) SaveConfigFileApprovalPolicy(policy *model.ConfigFileApprovalPolicy) error {
+	_, err := DoTransactionIfNeed(nil, cs.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		key := approvalPolicyKey(policy.Namespace, policy.Group)
+		values := make(map[string]interface{})
+		if err := loadValues(tx, tblConfigFileApprovalPolicy, []string{key}, &model.ConfigFileApprovalPolicy{},
+			values); err != nil {
+			return nil, err
+		}
+
+		tN := time.Now()
+		if len(values) == 0 {
+			cs.policyId++
+			policy.Id = cs.policyId
+			policy.Valid = true
+			policy.CreateTime = tN
+			policy.ModifyTime = tN
+			if err := saveValue(tx, tblConfigFileApprovalPolicyID, tblConfigFileApprovalPolicyID, &IDHolder{
+				ID: cs.policyId,
+			}); err != nil {
+				log.Error("[ConfigFileApprovalPolicy] save auto_increment id", zap.Error(err))
+				return nil, err
+			}
+			if err := saveValue(tx, tblConfigFileApprovalPolicy, key, policy); err != nil {
+				log.Error("[ConfigFileApprovalPolicy] save info", zap.Error(err))
+				return nil, err
+			}
+			return nil, nil
+		}
+
+		properties := make(map[string]interface{})
+		properties[FileApprovalFieldRequiredApprovals] = policy.RequiredApprovals
+		properties[FileApprovalFieldUsers] = policy.Users
+		properties[FileApprovalFieldUserGroups] = policy.UserGroups
+		properties[FileApprovalFieldModifyTime] = tN
+		properties[FileApprovalFieldModifyBy] = policy.ModifyBy
+		if err := updateValue(tx, tblConfigFileApprovalPolicy, key, properties); err != nil {
+			log.Error("[ConfigFileApprovalPolicy] update info", zap.Error(err))
+			return nil, err
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// GetConfigFileApprovalPolicy 获取配置发布审批策略
+func (cs *configFileApprovalStore) GetConfigFileApprovalPolicy(namespace,
+	group string) (*model.ConfigFileApprovalPolicy, error) {
+	key := approvalPolicyKey(namespace, group)
+	ret, err := cs.handler.LoadValues(tblConfigFileApprovalPolicy, []string{key}, &model.ConfigFileApprovalPolicy{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+	return ret[key].(*model.ConfigFileApprovalPolicy), nil
+}
+
+// DeleteConfigFileApprovalPolicy 删除配置发布审批策略
+func (cs *configFileApprovalStore) DeleteConfigFileApprovalPolicy(namespace, group string) error {
+	return cs.handler.DeleteValues(tblConfigFileApprovalPolicy, []string{approvalPolicyKey(namespace, group)})
+}
+
+// CreateConfigFileReleaseRequest 创建配置发布申请
+func (cs *configFileApprovalStore) CreateConfigFileReleaseRequest(proxyTx store.Tx,
+	request *model.ConfigFileReleaseRequest) (*model.ConfigFileReleaseRequest, error) {
+	_, err := DoTransactionIfNeed(proxyTx, cs.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		cs.requestId++
+		request.Id = cs.requestId
+		request.Valid = true
+		request.CreateTime = time.Now()
+		request.ModifyTime = request.CreateTime
+
+		if err := saveValue(tx, tblConfigFileReleaseRequestID, tblConfigFileReleaseRequestID, &IDHolder{
+			ID: cs.requestId,
+		}); err != nil {
+			log.Error("[ConfigFileReleaseRequest] save auto_increment id", zap.Error(err))
+			return nil, err
+		}
+		if err := saveValue(tx, tblConfigFileReleaseRequest, strconv.FormatUint(request.Id, 10),
+			request); err != nil {
+			log.Error("[ConfigFileReleaseRequest] save info", zap.Error(err))
+			return nil, err
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// UpdateConfigFileReleaseRequest 更新配置发布申请的状态以及审批人
+func (cs *configFileApprovalStore) UpdateConfigFileReleaseRequest(proxyTx store.Tx,
+	request *model.ConfigFileReleaseRequest, preRevision string) error {
+	_, err := DoTransactionIfNeed(proxyTx, cs.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		saved, err := cs.getConfigFileReleaseRequest(tx, request.Id)
+		if err != nil {
+			return nil, err
+		}
+		if saved == nil {
+			return nil, store.NewStatusError(store.DataConflictErr, "release request not found")
+		}
+		if saved.Revision != preRevision {
+			return nil, store.NewStatusError(store.DataConflictErr, "release request has been modified")
+		}
+
+		properties := make(map[string]interface{})
+		properties[FileReleaseRequestFieldStatus] = request.Status
+		properties[FileReleaseRequestFieldApprovers] = request.Approvers
+		properties[FileReleaseRequestFieldReason] = request.Reason
+		properties[FileReleaseRequestFieldRevision] = request.Revision
+		properties[FileReleaseRequestFieldModifyBy] = request.ModifyBy
+		properties[FileReleaseRequestFieldModifyTime] = time.Now()
+		if err := updateValue(tx, tblConfigFileReleaseRequest, strconv.FormatUint(request.Id, 10),
+			properties); err != nil {
+			log.Error("[ConfigFileReleaseRequest] update info", zap.Error(err))
+			return nil, err
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// GetConfigFileReleaseRequest 根据 ID 获取配置发布申请
+func (cs *configFileApprovalStore) GetConfigFileReleaseRequest(proxyTx store.Tx,
+	id uint64) (*model.ConfigFileReleaseRequest, error) {
+	ret, err := DoTransactionIfNeed(proxyTx, cs.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		request, err := cs.getConfigFileReleaseRequest(tx, id)
+		if err != nil || request == nil {
+			return nil, err
+		}
+		return []interface{}{request}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+	return ret[0].(*model.ConfigFileReleaseRequest), nil
+}
+
+func (cs *configFileApprovalStore) getConfigFileReleaseRequest(tx *bolt.Tx,
+	id uint64) (*model.ConfigFileReleaseRequest, error) {
+	key := strconv.FormatUint(id, 10)
+	values := make(map[string]interface{})
+	if err := loadValues(tx, tblConfigFileReleaseRequest, []string{key}, &model.ConfigFileReleaseRequest{},
+		values); err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values[key].(*model.ConfigFileReleaseRequest), nil
+}
+
+// QueryConfigFileReleaseRequests 查询配置发布申请
+func (cs *configFileApprovalStore) QueryConfigFileReleaseRequests(namespace, group, fileName, status string,
+	offset, limit uint32) (uint32, []*model.ConfigFileReleaseRequest, error) {
+	fields := []string{FileReleaseRequestFieldNamespace, FileReleaseRequestFieldGroup,
+		FileReleaseRequestFieldFileName, FileReleaseRequestFieldStatus}
+	ret, err := cs.handler.LoadValuesByFilter(tblConfigFileReleaseRequest, fields, &model.ConfigFileReleaseRequest{},
+		func(m map[string]interface{}) bool {
+			saveNs, _ := m[FileReleaseRequestFieldNamespace].(string)
+			saveGroup, _ := m[FileReleaseRequestFieldGroup].(string)
+			saveFileName, _ := m[FileReleaseRequestFieldFileName].(string)
+			saveStatus, _ := m[FileReleaseRequestFieldStatus].(string)
+			if namespace != "" && namespace != saveNs {
+				return false
+			}
+			if group != "" && group != saveGroup {
+				return false
+			}
+			if fileName != "" && fileName != saveFileName {
+				return false
+			}
+			return status == "" || status == saveStatus
+		})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	requests := make([]*model.ConfigFileReleaseRequest, 0, len(ret))
+	for _, v := range ret {
+		requests = append(requests, v.(*model.ConfigFileReleaseRequest))
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].Id > requests[j].Id
+	})
+
+	total := uint32(len(requests))
+	if offset >= total {
+		return total, []*model.ConfigFileReleaseRequest{}, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return total, requests[offset:end], nil
+}
+
+func approvalPolicyKey(namespace, group string) string {
+	return fmt.Sprintf("%s@@%s", namespace, group)
+}
@@ -0,0 +1,140 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+func mockConfigFileReleaseRequest(fileName string) *model.ConfigFileReleaseRequest {
+	return &model.ConfigFileReleaseRequest{
+		Namespace:         "config-file-approval",
+		Group:             "config-file-approval",
+		FileName:          fileName,
+		ReleaseName:       "config-file-approval",
+		Content:           "k1=v1",
+		Md5:               "config-file-approval",
+		Status:            model.ReleaseRequestStatusPending,
+		RequiredApprovals: 2,
+		Revision:          "revision-1",
+		CreateBy:          "polaris",
+		ModifyBy:          "polaris",
+	}
+}
+
+func Test_configFileApprovalStore(t *testing.T) {
+	t.Run("保存审批策略", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileApprovalPolicy, func(t *testing.T, handler BoltHandler) {
+			s, err := newConfigFileApprovalStore(handler)
+			assert.NoError(t, err)
+
+			err = s.SaveConfigFileApprovalPolicy(&model.ConfigFileApprovalPolicy{
+				Namespace:         "config-file-approval",
+				Group:             "config-file-approval",
+				RequiredApprovals: 1,
+				Users:             "user-a",
+				CreateBy:          "polaris",
+				ModifyBy:          "polaris",
+			})
+			assert.NoError(t, err)
+
+			// 再次保存，覆盖之前的审批策略
+			err = s.SaveConfigFileApprovalPolicy(&model.ConfigFileApprovalPolicy{
+				Namespace:         "config-file-approval",
+				Group:             "config-file-approval",
+				RequiredApprovals: 2,
+				Users:             "user-a,user-b",
+				UserGroups:        "ops",
+				ModifyBy:          "admin",
+			})
+			assert.NoError(t, err)
+
+			policy, err := s.GetConfigFileApprovalPolicy("config-file-approval", "config-file-approval")
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), policy.Id)
+			assert.Equal(t, 2, policy.RequiredApprovals)
+			assert.Equal(t, []string{"user-a", "user-b"}, policy.ApproverUsers())
+			assert.Equal(t, []string{"ops"}, policy.ApproverUserGroups())
+			assert.Equal(t, "polaris", policy.CreateBy)
+			assert.Equal(t, "admin", policy.ModifyBy)
+
+			policy, err = s.GetConfigFileApprovalPolicy("config-file-approval", "")
+			assert.NoError(t, err)
+			assert.Nil(t, policy)
+
+			err = s.DeleteConfigFileApprovalPolicy("config-file-approval", "config-file-approval")
+			assert.NoError(t, err)
+			policy, err = s.GetConfigFileApprovalPolicy("config-file-approval", "config-file-approval")
+			assert.NoError(t, err)
+			assert.Nil(t, policy)
+		})
+	})
+
+	t.Run("配置发布申请", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileReleaseRequest, func(t *testing.T, handler BoltHandler) {
+			s, err := newConfigFileApprovalStore(handler)
+			assert.NoError(t, err)
+
+			first, err := s.CreateConfigFileReleaseRequest(nil, mockConfigFileReleaseRequest("file-1"))
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), first.Id)
+			second, err := s.CreateConfigFileReleaseRequest(nil, mockConfigFileReleaseRequest("file-2"))
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(2), second.Id)
+
+			first.Approvers = "user-a"
+			first.Revision = "revision-2"
+			first.ModifyBy = "user-a"
+			err = s.UpdateConfigFileReleaseRequest(nil, first, "revision-1")
+			assert.NoError(t, err)
+
+			// 版本号不一致时不允许更新
+			first.Status = model.ReleaseRequestStatusApproved
+			err = s.UpdateConfigFileReleaseRequest(nil, first, "revision-1")
+			assert.Error(t, err)
+			assert.Equal(t, store.DataConflictErr, store.Code(err))
+
+			saved, err := s.GetConfigFileReleaseRequest(nil, first.Id)
+			assert.NoError(t, err)
+			assert.Equal(t, model.ReleaseRequestStatusPending, saved.Status)
+			assert.Equal(t, []string{"user-a"}, saved.ApproverList())
+			assert.Equal(t, "revision-2", saved.Revision)
+			assert.Equal(t, "k1=v1", saved.Content)
+
+			total, requests, err := s.QueryConfigFileReleaseRequests("config-file-approval", "", "",
+				model.ReleaseRequestStatusPending, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), total)
+			assert.Equal(t, second.Id, requests[0].Id)
+
+			total, requests, err = s.QueryConfigFileReleaseRequests("", "", "file-1", "", 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+			assert.Equal(t, first.Id, requests[0].Id)
+
+			saved, err = s.GetConfigFileReleaseRequest(nil, 100)
+			assert.NoError(t, err)
+			assert.Nil(t, saved)
+		})
+	})
+}
@@ -0,0 +1,200 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblConfigFileGrayRelease   string = "ConfigFileGrayRelease"
+	tblConfigFileGrayReleaseID string = "ConfigFileGrayReleaseID"
+
+	FileGrayReleaseFieldRule string = "Rule"
+)
+
+// SaveConfigFileGrayRelease 保存配置文件灰度发布，已存在则覆盖
+func (cfr *configFileReleaseStore) SaveConfigFileGrayRelease(proxyTx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	ret, err := DoTransactionIfNeed(proxyTx, cfr.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		key := fmt.Sprintf("%s@@%s@@%s", grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+		saved, err := cfr.getConfigFileGrayReleaseByFlag(tx, grayRelease.Namespace, grayRelease.Group,
+			grayRelease.FileName, true)
+		if err != nil {
+			return nil, err
+		}
+
+		tN := time.Now()
+		if saved == nil {
+			cfr.grayId++
+			grayRelease.Id = cfr.grayId
+			grayRelease.Valid = true
+			grayRelease.Flag = 0
+			grayRelease.CreateTime = tN
+			grayRelease.ModifyTime = tN
+
+			if err := saveValue(tx, tblConfigFileGrayReleaseID, tblConfigFileGrayReleaseID, &IDHolder{
+				ID: cfr.grayId,
+			}); err != nil {
+				log.Error("[ConfigFileGrayRelease] save auto_increment id", zap.Error(err))
+				return nil, err
+			}
+			if err := saveValue(tx, tblConfigFileGrayRelease, key, grayRelease); err != nil {
+				log.Error("[ConfigFileGrayRelease] save info", zap.Error(err))
+				return nil, err
+			}
+		} else {
+			properties := make(map[string]interface{})
+			properties[FileReleaseFieldName] = grayRelease.Name
+			properties[FileReleaseFieldContent] = grayRelease.Content
+			properties[FileReleaseFieldComment] = grayRelease.Comment
+			properties[FileReleaseFieldMd5] = grayRelease.Md5
+			properties[FileReleaseFieldVersion] = grayRelease.Version
+			properties[FileGrayReleaseFieldRule] = grayRelease.Rule
+			properties[FileReleaseFieldValid] = true
+			properties[FileReleaseFieldFlag] = 0
+			properties[FileReleaseFieldModifyTime] = tN
+			properties[FileReleaseFieldModifyBy] = grayRelease.ModifyBy
+			if !saved.Valid {
+				// 之前的灰度已经结束，重新开始一轮灰度
+				properties[FileReleaseFieldCreateTime] = tN
+				properties[FileReleaseFieldCreateBy] = grayRelease.CreateBy
+			}
+
+			if err := updateValue(tx, tblConfigFileGrayRelease, key, properties); err != nil {
+				log.Error("[ConfigFileGrayRelease] update info", zap.Error(err))
+				return nil, err
+			}
+		}
+
+		data, err := cfr.getConfigFileGrayReleaseByFlag(tx, grayRelease.Namespace, grayRelease.Group,
+			grayRelease.FileName, false)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			return nil, nil
+		}
+		return []interface{}{data}, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+// GetConfigFileGrayRelease 获取配置文件灰度发布，只获取 flag=0 的记录
+func (cfr *configFileReleaseStore) GetConfigFileGrayRelease(proxyTx store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileGrayRelease, error) {
+	ret, err := DoTransactionIfNeed(proxyTx, cfr.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		data, err := cfr.getConfigFileGrayReleaseByFlag(tx, namespace, group, fileName, false)
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			return nil, nil
+		}
+		return []interface{}{data}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+	return ret[0].(*model.ConfigFileGrayRelease), nil
+}
+
+func (cfr *configFileReleaseStore) getConfigFileGrayReleaseByFlag(tx *bolt.Tx, namespace, group, fileName string,
+	withAllFlag bool) (*model.ConfigFileGrayRelease, error) {
+	var (
+		key = fmt.Sprintf("%s@@%s@@%s", namespace, group, fileName)
+		ret = make(map[string]interface{})
+	)
+	if err := loadValues(tx, tblConfigFileGrayRelease, []string{key}, &model.ConfigFileGrayRelease{}, ret); err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	grayRelease := ret[key].(*model.ConfigFileGrayRelease)
+	if !withAllFlag && !grayRelease.Valid {
+		return nil, nil
+	}
+	return grayRelease, nil
+}
+
+// DeleteConfigFileGrayRelease 删除配置文件灰度发布，仅标记删除，便于发布扫描感知到灰度结束
+func (cfr *configFileReleaseStore) DeleteConfigFileGrayRelease(proxyTx store.Tx, namespace, group,
+	fileName, deleteBy string) error {
+	_, err := DoTransactionIfNeed(proxyTx, cfr.handler, func(tx *bolt.Tx) ([]interface{}, error) {
+		grayRelease, err := cfr.getConfigFileGrayReleaseByFlag(tx, namespace, group, fileName, false)
+		if err != nil {
+			return nil, err
+		}
+		if grayRelease == nil {
+			return nil, nil
+		}
+
+		properties := make(map[string]interface{})
+		properties[FileReleaseFieldValid] = false
+		properties[FileReleaseFieldFlag] = 1
+		properties[FileReleaseFieldModifyTime] = time.Now()
+		properties[FileReleaseFieldModifyBy] = deleteBy
+
+		key := fmt.Sprintf("%s@@%s@@%s", namespace, group, fileName)
+		if err := updateValue(tx, tblConfigFileGrayRelease, key, properties); err != nil {
+			log.Error("[ConfigFileGrayRelease] delete info", zap.Error(err))
+			return nil, err
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// FindConfigFileGrayReleaseByModifyTimeAfter 获取最后更新时间大于某个时间点的灰度发布，包含 Flag = 1 的记录
+func (cfr *configFileReleaseStore) FindConfigFileGrayReleaseByModifyTimeAfter(
+	modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error) {
+	fields := []string{FileReleaseFieldModifyTime}
+	ret, err := cfr.handler.LoadValuesByFilter(tblConfigFileGrayRelease, fields, &model.ConfigFileGrayRelease{},
+		func(m map[string]interface{}) bool {
+			saveMt, _ := m[FileReleaseFieldModifyTime].(time.Time)
+			return !saveMt.Before(modifyTime)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	grayReleases := make([]*model.ConfigFileGrayRelease, 0, len(ret))
+	for _, v := range ret {
+		grayReleases = append(grayReleases, v.(*model.ConfigFileGrayRelease))
+	}
+	return grayReleases, nil
+}
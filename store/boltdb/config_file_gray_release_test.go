@@ -0,0 +1,113 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func mockConfigFileGrayRelease() *model.ConfigFileGrayRelease {
+	return &model.ConfigFileGrayRelease{
+		Name:      "config-file-gray-release",
+		Namespace: "config-file-gray-release",
+		Group:     "config-file-gray-release",
+		FileName:  "config-file-gray-release",
+		Content:   "config-file-gray-release",
+		Md5:       "config-file-gray-release",
+		Version:   2,
+		Rule:      `{"client_ips":["127.0.0.1"]}`,
+		CreateBy:  "polaris",
+		ModifyBy:  "polaris",
+	}
+}
+
+func Test_configFileGrayReleaseStore(t *testing.T) {
+	t.Run("保存灰度Release", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGrayRelease, func(t *testing.T, handler BoltHandler) {
+			s := &configFileReleaseStore{handler: handler}
+
+			gray := mockConfigFileGrayRelease()
+			saved, err := s.SaveConfigFileGrayRelease(nil, gray)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), saved.Id)
+			assert.Equal(t, gray.Rule, saved.Rule)
+			assert.True(t, saved.Valid)
+
+			// 再次保存，覆盖之前的灰度内容
+			update := mockConfigFileGrayRelease()
+			update.Content = "update gray content"
+			update.Version = 3
+			update.Rule = `{"client_ids":["client-a"]}`
+			saved, err = s.SaveConfigFileGrayRelease(nil, update)
+			assert.NoError(t, err)
+			assert.Equal(t, uint64(1), saved.Id)
+			assert.Equal(t, "update gray content", saved.Content)
+			assert.Equal(t, uint64(3), saved.Version)
+			assert.Equal(t, update.Rule, saved.Rule)
+		})
+	})
+
+	t.Run("删除灰度Release", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGrayRelease, func(t *testing.T, handler BoltHandler) {
+			s := &configFileReleaseStore{handler: handler}
+
+			gray := mockConfigFileGrayRelease()
+			_, err := s.SaveConfigFileGrayRelease(nil, gray)
+			assert.NoError(t, err)
+
+			err = s.DeleteConfigFileGrayRelease(nil, gray.Namespace, gray.Group, gray.FileName, "polaris")
+			assert.NoError(t, err)
+
+			saved, err := s.GetConfigFileGrayRelease(nil, gray.Namespace, gray.Group, gray.FileName)
+			assert.NoError(t, err)
+			assert.Nil(t, saved)
+
+			// 删除的记录能够被扫描到
+			result, err := s.FindConfigFileGrayReleaseByModifyTimeAfter(time.Time{})
+			assert.NoError(t, err)
+			assert.Len(t, result, 1)
+			assert.Equal(t, 1, result[0].Flag)
+			assert.False(t, result[0].Valid)
+
+			// 重新开始灰度
+			saved, err = s.SaveConfigFileGrayRelease(nil, mockConfigFileGrayRelease())
+			assert.NoError(t, err)
+			assert.NotNil(t, saved)
+			assert.Equal(t, 0, saved.Flag)
+			assert.Equal(t, uint64(1), saved.Id)
+		})
+	})
+
+	t.Run("查询灰度Release-用于刷新Cache缓存", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGrayRelease, func(t *testing.T, handler BoltHandler) {
+			s := &configFileReleaseStore{handler: handler}
+
+			_, err := s.SaveConfigFileGrayRelease(nil, mockConfigFileGrayRelease())
+			assert.NoError(t, err)
+
+			result, err := s.FindConfigFileGrayReleaseByModifyTimeAfter(time.Now().Add(time.Hour))
+			assert.NoError(t, err)
+			assert.Empty(t, result)
+		})
+	})
+}
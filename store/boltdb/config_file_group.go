@@ -44,6 +44,7 @@ const (
 	FileGroupFieldCreateTime string = "CreateTime"
 	FileGroupFieldModifyTime string = "ModifyTime"
 	FileGroupFieldValid      string = "Valid"
+	FileGroupFieldJsonSchema string = "JsonSchema"
 )
 
 var (
@@ -239,6 +240,25 @@ func (fg *configFileGroupStore) UpdateConfigFileGroup(
 	return nil, nil
 }
 
+// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema
+func (fg *configFileGroupStore) UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy string) error {
+	if namespace == "" || name == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "ConfigFileGroup miss some param")
+	}
+
+	key := fmt.Sprintf("%s@@%s", namespace, name)
+	properties := make(map[string]interface{})
+	properties[FileGroupFieldJsonSchema] = schema
+	properties[FileGroupFieldModifyBy] = modifyBy
+	properties[FileGroupFieldModifyTime] = time.Now()
+
+	if err := fg.handler.UpdateValue(tblConfigFileGroup, key, properties); err != nil {
+		log.Error("[ConfigFileGroup] do update schema", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
 // FindConfigFileGroups 查询配置文件组
 func (fg *configFileGroupStore) FindConfigFileGroups(namespace string,
 	names []string) ([]*model.ConfigFileGroup, error) {
@@ -188,4 +188,35 @@ func Test_configFileGroupStore(t *testing.T) {
 			}
 		})
 	})
+	t.Run("配置分组更新-JSON Schema", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileGroup, func(t *testing.T, handler BoltHandler) {
+			store, err := newConfigFileGroupStore(handler)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mockGroup := mockConfigFileGroup(1)[0]
+			if _, err := store.CreateConfigFileGroup(mockGroup); err != nil {
+				t.Fatal(err)
+			}
+
+			schema := `{"type":"object"}`
+			if err := store.UpdateConfigFileGroupSchema(mockGroup.Namespace, mockGroup.Name,
+				schema, "polaris"); err != nil {
+				t.Fatal(err)
+			}
+			val, err := store.GetConfigFileGroup(mockGroup.Namespace, mockGroup.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, schema, val.JsonSchema)
+			assert.Equal(t, "polaris", val.ModifyBy)
+
+			if err := store.UpdateConfigFileGroupSchema(mockGroup.Namespace, mockGroup.Name,
+				"", "polaris"); err != nil {
+				t.Fatal(err)
+			}
+			val, err = store.GetConfigFileGroup(mockGroup.Namespace, mockGroup.Name)
+			assert.NoError(t, err)
+			assert.Equal(t, "", val.JsonSchema)
+		})
+	})
 }
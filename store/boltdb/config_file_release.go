@@ -58,6 +58,7 @@ var (
 
 type configFileReleaseStore struct {
 	id      uint64
+	grayId  uint64
 	handler BoltHandler
 }
 
@@ -67,11 +68,17 @@ func newConfigFileReleaseStore(handler BoltHandler) (*configFileReleaseStore, er
 	if err != nil {
 		return nil, err
 	}
-	if len(ret) == 0 {
-		return s, nil
+	if len(ret) != 0 {
+		s.id = ret[tblConfigFileReleaseID].(*IDHolder).ID
+	}
+
+	ret, err = handler.LoadValues(tblConfigFileGrayReleaseID, []string{tblConfigFileGrayReleaseID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) != 0 {
+		s.grayId = ret[tblConfigFileGrayReleaseID].(*IDHolder).ID
 	}
-	val := ret[tblConfigFileReleaseID].(*IDHolder)
-	s.id = val.ID
 	return s, nil
 }
 
@@ -173,6 +173,24 @@ func (rh *configFileReleaseHistoryStore) GetLatestConfigFileReleaseHistory(names
 	return histories[0], nil
 }
 
+// GetConfigFileReleaseHistory 根据 ID 获取发布记录
+func (rh *configFileReleaseHistoryStore) GetConfigFileReleaseHistory(
+	id uint64) (*model.ConfigFileReleaseHistory, error) {
+	ret, err := rh.handler.LoadValues(tblConfigFileReleaseHistory, []string{strconv.FormatUint(id, 10)},
+		&model.ConfigFileReleaseHistory{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, nil
+	}
+	history := ret[strconv.FormatUint(id, 10)].(*model.ConfigFileReleaseHistory)
+	if !history.Valid {
+		return nil, nil
+	}
+	return history, nil
+}
+
 // doConfigFileGroupPage 进行分页
 func doConfigFileHistoryPage(ret map[string]interface{}, offset, limit uint32) []*model.ConfigFileReleaseHistory {
 	var (
@@ -129,4 +129,36 @@ func Test_configFileReleaseHistoryStore(t *testing.T) {
 			assert.Equal(t, uint64(total), copyVal.Id)
 		})
 	})
+	t.Run("配置发布历史根据ID查询", func(t *testing.T) {
+		CreateTableDBHandlerAndRun(t, tblConfigFileReleaseHistory, func(t *testing.T, handler BoltHandler) {
+			store, err := newConfigFileReleaseHistoryStore(handler)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			total := 5
+			mockHistories := mockConfigFileHistory(total, "")
+
+			for i := 0; i < total; i++ {
+				if err := store.CreateConfigFileReleaseHistory(nil, mockHistories[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i := 0; i < total; i++ {
+				mockVal := mockHistories[i]
+				val, err := store.GetConfigFileReleaseHistory(mockVal.Id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				assert.NotNil(t, val)
+				assert.Equal(t, mockVal.Id, val.Id)
+				assert.Equal(t, mockVal.Content, val.Content)
+			}
+
+			val, err := store.GetConfigFileReleaseHistory(uint64(total + 1))
+			assert.NoError(t, err)
+			assert.Nil(t, val)
+		})
+	})
 }
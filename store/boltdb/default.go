@@ -70,6 +70,8 @@ type boltStore struct {
 	*rateLimitStore
 	*circuitBreakerStore
 	*faultDetectStore
+	*trafficPolicyStore
+	*accessPolicyStore
 
 	// 工具
 	*toolStore
@@ -78,6 +80,7 @@ type boltStore struct {
 	*userStore
 	*groupStore
 	*strategyStore
+	*revokedTokenStore
 
 	// 配置中心stores
 	*configFileGroupStore
@@ -86,6 +89,7 @@ type boltStore struct {
 	*configFileReleaseHistoryStore
 	*configFileTagStore
 	*configFileTemplateStore
+	*configFileApprovalStore
 
 	// v2 存储
 	*routingStoreV2
@@ -93,6 +97,12 @@ type boltStore struct {
 	// adminStore store
 	*adminStore
 
+	// operationRecordStore store
+	*operationRecordStore
+
+	// certificateAuthorityStore store
+	*certificateAuthorityStore
+
 	handler BoltHandler
 	start   bool
 }
@@ -310,6 +320,9 @@ func (m *boltStore) newDiscoverModuleStore() error {
 
 	m.faultDetectStore = &faultDetectStore{handler: m.handler}
 
+	m.trafficPolicyStore = &trafficPolicyStore{handler: m.handler}
+	m.accessPolicyStore = &accessPolicyStore{handler: m.handler}
+
 	m.routingStoreV2 = &routingStoreV2{handler: m.handler}
 
 	return nil
@@ -322,6 +335,8 @@ func (m *boltStore) newAuthModuleStore() error {
 
 	m.groupStore = &groupStore{handler: m.handler}
 
+	m.revokedTokenStore = &revokedTokenStore{handler: m.handler}
+
 	return nil
 }
 
@@ -358,12 +373,25 @@ func (m *boltStore) newConfigModuleStore() error {
 		return err
 	}
 
+	m.configFileApprovalStore, err = newConfigFileApprovalStore(m.handler)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (m *boltStore) newMaintainModuleStore() error {
 	m.adminStore = &adminStore{handler: m.handler, leMap: make(map[string]bool)}
 
+	m.certificateAuthorityStore = &certificateAuthorityStore{handler: m.handler}
+
+	var err error
+	m.operationRecordStore, err = newOperationRecordStore(m.handler)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
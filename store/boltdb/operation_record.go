@@ -0,0 +1,206 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	tblOperationRecord   string = "OperationRecord"
+	tblOperationRecordID string = "OperationRecordID"
+
+	OperationRecordFieldResourceType  string = "ResourceType"
+	OperationRecordFieldResourceName  string = "ResourceName"
+	OperationRecordFieldNamespace     string = "Namespace"
+	OperationRecordFieldOperator      string = "Operator"
+	OperationRecordFieldOperationType string = "OperationType"
+	OperationRecordFieldHappenTime    string = "HappenTime"
+)
+
+// operationRecordFilterFields 查询参数与存储字段的映射
+var operationRecordFilterFields = map[string]string{
+	"resource_type":  OperationRecordFieldResourceType,
+	"resource_name":  OperationRecordFieldResourceName,
+	"namespace":      OperationRecordFieldNamespace,
+	"operator":       OperationRecordFieldOperator,
+	"operation_type": OperationRecordFieldOperationType,
+}
+
+type operationRecordObject struct {
+	ID            uint64
+	ResourceType  string
+	ResourceName  string
+	Namespace     string
+	Operator      string
+	OperationType string
+	Detail        string
+	Server        string
+	HappenTime    time.Time
+}
+
+type operationRecordStore struct {
+	id      uint64
+	handler BoltHandler
+}
+
+func newOperationRecordStore(handler BoltHandler) (*operationRecordStore, error) {
+	s := &operationRecordStore{handler: handler}
+	ret, err := handler.LoadValues(tblOperationRecordID, []string{tblOperationRecordID}, &IDHolder{})
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) > 0 {
+		s.id = ret[tblOperationRecordID].(*IDHolder).ID
+	}
+	return s, nil
+}
+
+// AddOperationRecords 批量保存操作记录
+func (rs *operationRecordStore) AddOperationRecords(records []*model.RecordEntry) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return rs.handler.Execute(true, func(tx *bolt.Tx) error {
+		for _, record := range records {
+			rs.id++
+			saveVal := &operationRecordObject{
+				ID:            rs.id,
+				ResourceType:  string(record.ResourceType),
+				ResourceName:  record.ResourceName,
+				Namespace:     record.Namespace,
+				Operator:      record.Operator,
+				OperationType: string(record.OperationType),
+				Detail:        record.Detail,
+				Server:        record.Server,
+				HappenTime:    record.HappenTime,
+			}
+			if err := saveValue(tx, tblOperationRecord, strconv.FormatUint(rs.id, 10), saveVal); err != nil {
+				log.Error("[OperationRecord] save info", zap.Error(err))
+				return err
+			}
+		}
+		if err := saveValue(tx, tblOperationRecordID, tblOperationRecordID, &IDHolder{ID: rs.id}); err != nil {
+			log.Error("[OperationRecord] save auto_increment id", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
+
+// GetOperationRecords 根据过滤条件以及时间范围查询操作记录，按照发生时间倒序返回
+func (rs *operationRecordStore) GetOperationRecords(filter map[string]string, startTime, endTime time.Time,
+	offset, limit uint32) (uint32, []*model.RecordEntry, error) {
+	conditions := make(map[string]string, len(filter))
+	for key, value := range filter {
+		field, ok := operationRecordFilterFields[key]
+		if !ok || value == "" {
+			continue
+		}
+		conditions[field] = value
+	}
+	fields := []string{OperationRecordFieldResourceType, OperationRecordFieldResourceName,
+		OperationRecordFieldNamespace, OperationRecordFieldOperator, OperationRecordFieldOperationType,
+		OperationRecordFieldHappenTime}
+	ret, err := rs.handler.LoadValuesByFilter(tblOperationRecord, fields, &operationRecordObject{},
+		func(m map[string]interface{}) bool {
+			for field, value := range conditions {
+				saveVal, _ := m[field].(string)
+				if saveVal != value {
+					return false
+				}
+			}
+			happenTime, _ := m[OperationRecordFieldHappenTime].(time.Time)
+			if !startTime.IsZero() && happenTime.Before(startTime) {
+				return false
+			}
+			return endTime.IsZero() || !happenTime.After(endTime)
+		})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	objects := make([]*operationRecordObject, 0, len(ret))
+	for _, v := range ret {
+		objects = append(objects, v.(*operationRecordObject))
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].HappenTime.Equal(objects[j].HappenTime) {
+			return objects[i].ID > objects[j].ID
+		}
+		return objects[i].HappenTime.After(objects[j].HappenTime)
+	})
+
+	total := uint32(len(objects))
+	if offset >= total {
+		return total, []*model.RecordEntry{}, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	records := make([]*model.RecordEntry, 0, end-offset)
+	for _, object := range objects[offset:end] {
+		records = append(records, &model.RecordEntry{
+			ResourceType:  model.Resource(object.ResourceType),
+			ResourceName:  object.ResourceName,
+			Namespace:     object.Namespace,
+			Operator:      object.Operator,
+			OperationType: model.OperationType(object.OperationType),
+			Detail:        object.Detail,
+			Server:        object.Server,
+			HappenTime:    object.HappenTime,
+		})
+	}
+	return total, records, nil
+}
+
+// BatchCleanOperationRecords 批量清理指定时间之前的操作记录
+func (rs *operationRecordStore) BatchCleanOperationRecords(before time.Time, batchSize uint32) (uint32, error) {
+	fields := []string{OperationRecordFieldHappenTime}
+	values, err := rs.handler.LoadValuesByFilter(tblOperationRecord, fields, &operationRecordObject{},
+		func(m map[string]interface{}) bool {
+			happenTime, ok := m[OperationRecordFieldHappenTime].(time.Time)
+			return ok && happenTime.Before(before)
+		})
+	if err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, batchSize)
+	for k := range values {
+		keys = append(keys, k)
+		if uint32(len(keys)) >= batchSize {
+			break
+		}
+	}
+	if err := rs.handler.DeleteValues(tblOperationRecord, keys); err != nil {
+		return 0, err
+	}
+	return uint32(len(keys)), nil
+}
@@ -0,0 +1,104 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func Test_operationRecordStore(t *testing.T) {
+	CreateTableDBHandlerAndRun(t, tblOperationRecord, func(t *testing.T, handler BoltHandler) {
+		s, err := newOperationRecordStore(handler)
+		assert.NoError(t, err)
+
+		now := time.Now()
+		records := []*model.RecordEntry{
+			{
+				ResourceType:  model.RRouting,
+				ResourceName:  "echo",
+				Namespace:     "default",
+				Operator:      "user-a",
+				OperationType: model.OUpdate,
+				Detail:        "update routing",
+				Server:        "127.0.0.1",
+				HappenTime:    now.Add(-2 * time.Hour),
+			},
+			{
+				ResourceType:  model.RRouting,
+				ResourceName:  "echo",
+				Namespace:     "default",
+				Operator:      "user-b",
+				OperationType: model.ODelete,
+				HappenTime:    now.Add(-time.Hour),
+			},
+			{
+				ResourceType:  model.RService,
+				ResourceName:  "echo",
+				Namespace:     "test",
+				Operator:      "user-a",
+				OperationType: model.OCreate,
+				HappenTime:    now,
+			},
+		}
+		assert.NoError(t, s.AddOperationRecords(records))
+
+		t.Run("按照资源查询", func(t *testing.T) {
+			total, ret, err := s.GetOperationRecords(map[string]string{
+				"resource_type": string(model.RRouting),
+				"resource_name": "echo",
+			}, time.Time{}, time.Time{}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), total)
+			assert.Equal(t, "user-b", ret[0].Operator)
+			assert.Equal(t, "user-a", ret[1].Operator)
+			assert.Equal(t, "update routing", ret[1].Detail)
+		})
+
+		t.Run("按照操作人以及时间范围查询", func(t *testing.T) {
+			total, ret, err := s.GetOperationRecords(map[string]string{
+				"operator": "user-a",
+			}, now.Add(-90*time.Minute), time.Time{}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+			assert.Equal(t, model.RService, ret[0].ResourceType)
+		})
+
+		t.Run("分页查询", func(t *testing.T) {
+			total, ret, err := s.GetOperationRecords(map[string]string{}, time.Time{}, time.Time{}, 1, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(3), total)
+			assert.Equal(t, 1, len(ret))
+			assert.Equal(t, "user-b", ret[0].Operator)
+		})
+
+		t.Run("清理过期的操作记录", func(t *testing.T) {
+			count, err := s.BatchCleanOperationRecords(now.Add(-30*time.Minute), 100)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), count)
+
+			total, _, err := s.GetOperationRecords(map[string]string{}, time.Time{}, time.Time{}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+		})
+	})
+}
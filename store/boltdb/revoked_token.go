@@ -0,0 +1,121 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	tblRevokedToken string = "revoked_token"
+
+	RevokedTokenFieldExpireTime string = "ExpireTime"
+	RevokedTokenFieldModifyTime string = "ModifyTime"
+
+	// revokedTokenRetention 吊销记录在 token 过期之后继续保留的时间
+	revokedTokenRetention = time.Hour
+)
+
+type revokedTokenObject struct {
+	ID         string
+	Principal  string
+	Owner      string
+	ExpireTime time.Time
+	CreateTime time.Time
+	ModifyTime time.Time
+}
+
+type revokedTokenStore struct {
+	handler BoltHandler
+}
+
+// AddRevokedToken 保存吊销的 token，同时清理已经过期的吊销记录
+func (rs *revokedTokenStore) AddRevokedToken(token *model.RevokedToken) error {
+	if token.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "revoked token id is empty")
+	}
+	expired, err := rs.handler.LoadValuesByFilter(tblRevokedToken, []string{RevokedTokenFieldExpireTime},
+		&revokedTokenObject{}, func(m map[string]interface{}) bool {
+			expireTime, _ := m[RevokedTokenFieldExpireTime].(time.Time)
+			return expireTime.Before(time.Now().Add(-revokedTokenRetention))
+		})
+	if err != nil {
+		log.Error("[Store][RevokedToken] load expired revoked token", zap.Error(err))
+		return err
+	}
+
+	return rs.handler.Execute(true, func(tx *bolt.Tx) error {
+		if len(expired) > 0 {
+			keys := make([]string, 0, len(expired))
+			for key := range expired {
+				keys = append(keys, key)
+			}
+			if err := deleteValues(tx, tblRevokedToken, keys); err != nil {
+				return err
+			}
+		}
+		tn := time.Now()
+		return saveValue(tx, tblRevokedToken, token.ID, &revokedTokenObject{
+			ID:         token.ID,
+			Principal:  token.Principal,
+			Owner:      token.Owner,
+			ExpireTime: token.ExpireTime,
+			CreateTime: tn,
+			ModifyTime: tn,
+		})
+	})
+}
+
+// GetRevokedTokensForCache 获取还未过期的吊销记录
+func (rs *revokedTokenStore) GetRevokedTokensForCache(mtime time.Time,
+	firstUpdate bool) ([]*model.RevokedToken, error) {
+	fields := []string{RevokedTokenFieldExpireTime, RevokedTokenFieldModifyTime}
+	ret, err := rs.handler.LoadValuesByFilter(tblRevokedToken, fields, &revokedTokenObject{},
+		func(m map[string]interface{}) bool {
+			expireTime, _ := m[RevokedTokenFieldExpireTime].(time.Time)
+			modifyTime, _ := m[RevokedTokenFieldModifyTime].(time.Time)
+			if !expireTime.After(time.Now()) {
+				return false
+			}
+			return firstUpdate || !modifyTime.Before(mtime)
+		})
+	if err != nil {
+		log.Error("[Store][RevokedToken] get revoked tokens for cache", zap.Error(err))
+		return nil, err
+	}
+
+	tokens := make([]*model.RevokedToken, 0, len(ret))
+	for _, val := range ret {
+		item := val.(*revokedTokenObject)
+		tokens = append(tokens, &model.RevokedToken{
+			ID:         item.ID,
+			Principal:  item.Principal,
+			Owner:      item.Owner,
+			ExpireTime: item.ExpireTime,
+			CreateTime: item.CreateTime,
+			ModifyTime: item.ModifyTime,
+		})
+	}
+	return tokens, nil
+}
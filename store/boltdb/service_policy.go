@@ -0,0 +1,214 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"go.uber.org/zap"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+const (
+	ServicePolicyFieldService string = "Service"
+	ServicePolicyFieldRule    string = "Rule"
+)
+
+// servicePolicyTable 挂载在服务上的策略的存储表，各类策略共用增删改查的逻辑
+type servicePolicyTable[T model.ServicePolicyRule] struct {
+	name string
+	// newObject 创建空的存储对象，用于反序列化
+	newObject func() interface{}
+	toObject  func(policy T) interface{}
+	toModel   func(object interface{}) T
+	// extraFields 具体策略特有的需要持久化以及支持精确查询的字段
+	extraFields func(policy T) map[string]interface{}
+	// exactFilters 具体策略特有的精确查询条件，key 为查询参数，value 为存储对象的字段名
+	exactFilters map[string]string
+}
+
+// create 新增策略
+func (tbl *servicePolicyTable[T]) create(handler BoltHandler, item T) error {
+	policy := item.GetServicePolicy()
+	if policy.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, tbl.name+" id is empty")
+	}
+	tn := time.Now()
+	policy.Valid = true
+	policy.CreateTime = tn
+	policy.ModifyTime = tn
+	if err := handler.SaveValue(tbl.name, policy.ID, tbl.toObject(item)); err != nil {
+		log.Error("[Store][ServicePolicy] create policy", zap.String("table", tbl.name),
+			zap.String("id", policy.ID), zap.Error(err))
+		return store.Error(err)
+	}
+	return nil
+}
+
+// update 更新策略
+func (tbl *servicePolicyTable[T]) update(handler BoltHandler, item T) error {
+	policy := item.GetServicePolicy()
+	if policy.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, tbl.name+" id is empty")
+	}
+	properties := map[string]interface{}{
+		CommonFieldName:           policy.Name,
+		CommonFieldNamespace:      policy.Namespace,
+		ServicePolicyFieldService: policy.Service,
+		CommonFieldDescription:    policy.Description,
+		CommonFieldEnable:         policy.Enable,
+		ServicePolicyFieldRule:    policy.Rule,
+		CommonFieldRevision:       policy.Revision,
+		CommonFieldModifyTime:     time.Now(),
+	}
+	for field, value := range tbl.extraFields(item) {
+		properties[field] = value
+	}
+	if err := handler.UpdateValue(tbl.name, policy.ID, properties); err != nil {
+		log.Error("[Store][ServicePolicy] update policy", zap.String("table", tbl.name),
+			zap.String("id", policy.ID), zap.Error(err))
+		return store.Error(err)
+	}
+	return nil
+}
+
+// delete 删除策略，只做逻辑删除，便于缓存增量感知
+func (tbl *servicePolicyTable[T]) delete(handler BoltHandler, id string) error {
+	return handler.Execute(true, func(tx *bolt.Tx) error {
+		properties := map[string]interface{}{
+			CommonFieldValid:      false,
+			CommonFieldModifyTime: time.Now(),
+		}
+		if err := updateValue(tx, tbl.name, id, properties); err != nil {
+			log.Error("[Store][ServicePolicy] delete policy", zap.String("table", tbl.name),
+				zap.String("id", id), zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
+
+// getWithID 根据ID获取策略，策略不存在或者已经删除时返回空
+func (tbl *servicePolicyTable[T]) getWithID(handler BoltHandler, id string) (T, error) {
+	var empty T
+	if id == "" {
+		return empty, ErrBadParam
+	}
+	result, err := handler.LoadValues(tbl.name, []string{id}, tbl.newObject())
+	if err != nil {
+		log.Error("[Store][ServicePolicy] get policy", zap.String("table", tbl.name),
+			zap.String("id", id), zap.Error(err))
+		return empty, err
+	}
+	val, ok := result[id]
+	if !ok {
+		return empty, nil
+	}
+	policy := tbl.toModel(val)
+	if !policy.GetServicePolicy().Valid {
+		return empty, nil
+	}
+	return policy, nil
+}
+
+// list 根据过滤条件查询策略，name 为模糊查询，按照修改时间倒序
+func (tbl *servicePolicyTable[T]) list(handler BoltHandler, filter map[string]string,
+	offset uint32, limit uint32) (uint32, []T, error) {
+	exactFilters := map[string]string{
+		"id":        CommonFieldID,
+		"namespace": CommonFieldNamespace,
+		"service":   ServicePolicyFieldService,
+	}
+	fields := []string{CommonFieldID, CommonFieldName, CommonFieldNamespace, ServicePolicyFieldService,
+		CommonFieldValid}
+	for key, field := range tbl.exactFilters {
+		exactFilters[key] = field
+		fields = append(fields, field)
+	}
+	ret, err := handler.LoadValuesByFilter(tbl.name, fields, tbl.newObject(),
+		func(m map[string]interface{}) bool {
+			if valid, _ := m[CommonFieldValid].(bool); !valid {
+				return false
+			}
+			for key, field := range exactFilters {
+				if value, ok := filter[key]; ok && value != "" && value != m[field].(string) {
+					return false
+				}
+			}
+			if name := filter["name"]; name != "" && !strings.Contains(m[CommonFieldName].(string), name) {
+				return false
+			}
+			return true
+		})
+	if err != nil {
+		log.Error("[Store][ServicePolicy] get policies", zap.String("table", tbl.name), zap.Error(err))
+		return 0, nil, err
+	}
+
+	policies := make([]T, 0, len(ret))
+	for _, val := range ret {
+		policies = append(policies, tbl.toModel(val))
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		left, right := policies[i].GetServicePolicy(), policies[j].GetServicePolicy()
+		if !left.ModifyTime.Equal(right.ModifyTime) {
+			return left.ModifyTime.After(right.ModifyTime)
+		}
+		return left.ID < right.ID
+	})
+
+	total := uint32(len(policies))
+	if offset >= total {
+		return total, []T{}, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return total, policies[offset:end], nil
+}
+
+// listForCache 根据修改时间拉取增量策略
+func (tbl *servicePolicyTable[T]) listForCache(handler BoltHandler, mtime time.Time,
+	firstUpdate bool) ([]T, error) {
+	fields := []string{CommonFieldModifyTime, CommonFieldValid}
+	ret, err := handler.LoadValuesByFilter(tbl.name, fields, tbl.newObject(),
+		func(m map[string]interface{}) bool {
+			if firstUpdate {
+				valid, _ := m[CommonFieldValid].(bool)
+				return valid
+			}
+			modifyTime, _ := m[CommonFieldModifyTime].(time.Time)
+			return !modifyTime.Before(mtime)
+		})
+	if err != nil {
+		log.Error("[Store][ServicePolicy] get policies for cache", zap.String("table", tbl.name), zap.Error(err))
+		return nil, err
+	}
+
+	policies := make([]T, 0, len(ret))
+	for _, val := range ret {
+		policies = append(policies, tbl.toModel(val))
+	}
+	return policies, nil
+}
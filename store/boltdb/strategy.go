@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -39,6 +40,7 @@ const (
 	StrategyFieldID              string = "ID"
 	StrategyFieldName            string = "Name"
 	StrategyFieldAction          string = "Action"
+	StrategyFieldEffect          string = "Effect"
 	StrategyFieldComment         string = "Comment"
 	StrategyFieldUsersPrincipal  string = "Users"
 	StrategyFieldGroupsPrincipal string = "Groups"
@@ -47,6 +49,7 @@ const (
 	StrategyFieldNsResources     string = "NsResources"
 	StrategyFieldSvcResources    string = "SvcResources"
 	StrategyFieldCfgResources    string = "CfgResources"
+	StrategyFieldRuleResources   string = "RuleResources"
 	StrategyFieldValid           string = "Valid"
 	StrategyFieldRevision        string = "Revision"
 	StrategyFieldCreateTime      string = "CreateTime"
@@ -62,6 +65,7 @@ type strategyForStore struct {
 	ID           string
 	Name         string
 	Action       string
+	Effect       string
 	Comment      string
 	Users        map[string]string
 	Groups       map[string]string
@@ -70,10 +74,12 @@ type strategyForStore struct {
 	NsResources  map[string]string
 	SvcResources map[string]string
 	CfgResources map[string]string
-	Valid        bool
-	Revision     string
-	CreateTime   time.Time
-	ModifyTime   time.Time
+	// RuleResources 治理规则资源，key 格式为 {res_type}/{res_id}
+	RuleResources map[string]string
+	Valid         bool
+	Revision      string
+	CreateTime    time.Time
+	ModifyTime    time.Time
 }
 
 // StrategyStore
@@ -159,6 +165,7 @@ func (ss *strategyStore) updateStrategy(tx *bolt.Tx, modify *model.ModifyStrateg
 	saveVal *strategyForStore) error {
 
 	saveVal.Action = modify.Action
+	saveVal.Effect = modify.Effect
 	saveVal.Comment = modify.Comment
 	saveVal.Revision = utils.NewUUID()
 
@@ -231,7 +238,43 @@ func computeResources(remove bool, resources []model.StrategyResource, saveVal *
 			}
 			continue
 		}
+		if model.IsRuleResourceType(resource.ResType) {
+			if saveVal.RuleResources == nil {
+				saveVal.RuleResources = make(map[string]string, 4)
+			}
+			if remove {
+				delete(saveVal.RuleResources, buildRuleResourceKey(resource))
+			} else {
+				saveVal.RuleResources[buildRuleResourceKey(resource)] = ""
+			}
+			continue
+		}
+	}
+}
+
+func buildRuleResourceKey(resource model.StrategyResource) string {
+	return fmt.Sprintf("%d/%s", resource.ResType, resource.ResID)
+}
+
+// parseRuleResources 解析治理规则资源，格式不合法的数据直接忽略
+func parseRuleResources(strategyId string, ruleResources map[string]string) []model.StrategyResource {
+	ret := make([]model.StrategyResource, 0, len(ruleResources))
+	for key := range ruleResources {
+		items := strings.SplitN(key, "/", 2)
+		if len(items) != 2 {
+			continue
+		}
+		resType, err := strconv.ParseInt(items[0], 10, 32)
+		if err != nil {
+			continue
+		}
+		ret = append(ret, model.StrategyResource{
+			StrategyID: strategyId,
+			ResType:    int32(resType),
+			ResID:      items[1],
+		})
 	}
+	return ret
 }
 
 // DeleteStrategy delete a strategy
@@ -447,6 +490,7 @@ func collectStrategyResources(rule *strategyForStore) []model.StrategyResource {
 		})
 	}
 
+	ret = append(ret, parseRuleResources(rule.ID, rule.RuleResources)...)
 	return ret
 }
 
@@ -833,6 +877,7 @@ func convertForStrategyStore(strategy *model.StrategyDetail) *strategyForStore {
 	ns := make(map[string]string, 4)
 	svc := make(map[string]string, 4)
 	cfg := make(map[string]string, 4)
+	rules := make(map[string]string, 4)
 
 	resources := strategy.Resources
 
@@ -845,25 +890,31 @@ func convertForStrategyStore(strategy *model.StrategyDetail) *strategyForStore {
 			svc[res.ResID] = ""
 		case int32(apisecurity.ResourceType_ConfigGroups):
 			cfg[res.ResID] = ""
+		default:
+			if model.IsRuleResourceType(res.ResType) {
+				rules[buildRuleResourceKey(res)] = ""
+			}
 		}
 	}
 
 	return &strategyForStore{
-		ID:           strategy.ID,
-		Name:         strategy.Name,
-		Action:       strategy.Action,
-		Comment:      strategy.Comment,
-		Users:        users,
-		Groups:       groups,
-		Default:      strategy.Default,
-		Owner:        strategy.Owner,
-		NsResources:  ns,
-		SvcResources: svc,
-		CfgResources: cfg,
-		Valid:        strategy.Valid,
-		Revision:     strategy.Revision,
-		CreateTime:   strategy.CreateTime,
-		ModifyTime:   strategy.ModifyTime,
+		ID:            strategy.ID,
+		Name:          strategy.Name,
+		Action:        strategy.Action,
+		Effect:        strategy.Effect,
+		Comment:       strategy.Comment,
+		Users:         users,
+		Groups:        groups,
+		Default:       strategy.Default,
+		Owner:         strategy.Owner,
+		NsResources:   ns,
+		SvcResources:  svc,
+		CfgResources:  cfg,
+		RuleResources: rules,
+		Valid:         strategy.Valid,
+		Revision:      strategy.Revision,
+		CreateTime:    strategy.CreateTime,
+		ModifyTime:    strategy.ModifyTime,
 	}
 }
 
@@ -905,11 +956,13 @@ func convertForStrategyDetail(strategy *strategyForStore) *model.StrategyDetail
 	resources = append(resources, fillRes(strategy.NsResources, apisecurity.ResourceType_Namespaces)...)
 	resources = append(resources, fillRes(strategy.SvcResources, apisecurity.ResourceType_Services)...)
 	resources = append(resources, fillRes(strategy.CfgResources, apisecurity.ResourceType_ConfigGroups)...)
+	resources = append(resources, parseRuleResources(strategy.ID, strategy.RuleResources)...)
 
 	return &model.StrategyDetail{
 		ID:         strategy.ID,
 		Name:       strategy.Name,
 		Action:     strategy.Action,
+		Effect:     strategy.Effect,
 		Comment:    strategy.Comment,
 		Principals: principals,
 		Resources:  resources,
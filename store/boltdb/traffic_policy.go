@@ -0,0 +1,139 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+const (
+	tblTrafficPolicy string = "traffic_policy"
+
+	TrafficPolicyFieldRouteName string = "RouteName"
+)
+
+// trafficPolicyObject 流量策略的存储对象，Spec 为解析后的内容不需要持久化
+type trafficPolicyObject struct {
+	ID          string
+	Name        string
+	Namespace   string
+	Service     string
+	RouteName   string
+	Description string
+	Enable      bool
+	Rule        string
+	Revision    string
+	Valid       bool
+	CreateTime  time.Time
+	ModifyTime  time.Time
+}
+
+var trafficPolicyTable = &servicePolicyTable[*model.TrafficPolicy]{
+	name: tblTrafficPolicy,
+	newObject: func() interface{} {
+		return &trafficPolicyObject{}
+	},
+	toObject: func(policy *model.TrafficPolicy) interface{} {
+		return toTrafficPolicyObject(policy)
+	},
+	toModel: func(object interface{}) *model.TrafficPolicy {
+		return toTrafficPolicyModel(object.(*trafficPolicyObject))
+	},
+	extraFields: func(policy *model.TrafficPolicy) map[string]interface{} {
+		return map[string]interface{}{
+			TrafficPolicyFieldRouteName: policy.RouteName,
+		}
+	},
+	exactFilters: map[string]string{
+		"route_name": TrafficPolicyFieldRouteName,
+	},
+}
+
+type trafficPolicyStore struct {
+	handler BoltHandler
+}
+
+// CreateTrafficPolicy 新增流量策略
+func (t *trafficPolicyStore) CreateTrafficPolicy(policy *model.TrafficPolicy) error {
+	return trafficPolicyTable.create(t.handler, policy)
+}
+
+// UpdateTrafficPolicy 更新流量策略
+func (t *trafficPolicyStore) UpdateTrafficPolicy(policy *model.TrafficPolicy) error {
+	return trafficPolicyTable.update(t.handler, policy)
+}
+
+// DeleteTrafficPolicy 删除流量策略
+func (t *trafficPolicyStore) DeleteTrafficPolicy(id string) error {
+	return trafficPolicyTable.delete(t.handler, id)
+}
+
+// GetTrafficPolicyWithID 根据ID获取流量策略
+func (t *trafficPolicyStore) GetTrafficPolicyWithID(id string) (*model.TrafficPolicy, error) {
+	return trafficPolicyTable.getWithID(t.handler, id)
+}
+
+// GetTrafficPolicies 根据过滤条件查询流量策略，name 为模糊查询
+func (t *trafficPolicyStore) GetTrafficPolicies(filter map[string]string,
+	offset uint32, limit uint32) (uint32, []*model.TrafficPolicy, error) {
+	return trafficPolicyTable.list(t.handler, filter, offset, limit)
+}
+
+// GetTrafficPoliciesForCache 根据修改时间拉取增量流量策略
+func (t *trafficPolicyStore) GetTrafficPoliciesForCache(mtime time.Time,
+	firstUpdate bool) ([]*model.TrafficPolicy, error) {
+	return trafficPolicyTable.listForCache(t.handler, mtime, firstUpdate)
+}
+
+func toTrafficPolicyObject(policy *model.TrafficPolicy) *trafficPolicyObject {
+	return &trafficPolicyObject{
+		ID:          policy.ID,
+		Name:        policy.Name,
+		Namespace:   policy.Namespace,
+		Service:     policy.Service,
+		RouteName:   policy.RouteName,
+		Description: policy.Description,
+		Enable:      policy.Enable,
+		Rule:        policy.Rule,
+		Revision:    policy.Revision,
+		Valid:       policy.Valid,
+		CreateTime:  policy.CreateTime,
+		ModifyTime:  policy.ModifyTime,
+	}
+}
+
+func toTrafficPolicyModel(item *trafficPolicyObject) *model.TrafficPolicy {
+	return &model.TrafficPolicy{
+		ServicePolicy: model.ServicePolicy{
+			ID:          item.ID,
+			Name:        item.Name,
+			Namespace:   item.Namespace,
+			Service:     item.Service,
+			Description: item.Description,
+			Enable:      item.Enable,
+			Rule:        item.Rule,
+			Revision:    item.Revision,
+			Valid:       item.Valid,
+			CreateTime:  item.CreateTime,
+			ModifyTime:  item.ModifyTime,
+		},
+		RouteName: item.RouteName,
+	}
+}
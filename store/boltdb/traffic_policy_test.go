@@ -0,0 +1,128 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/polarismesh/polaris/common/model"
+)
+
+func Test_trafficPolicyStore(t *testing.T) {
+	CreateTableDBHandlerAndRun(t, tblTrafficPolicy, func(t *testing.T, handler BoltHandler) {
+		s := &trafficPolicyStore{handler: handler}
+
+		start := time.Now()
+		policies := []*model.TrafficPolicy{
+			{
+				ServicePolicy: model.ServicePolicy{
+					ID:        "policy-1",
+					Name:      "echo-retry",
+					Namespace: "default",
+					Service:   "echo",
+					Enable:    true,
+					Rule:      `{"timeout":3000,"retry":{"retry_on":["5xx"],"num_retries":2}}`,
+					Revision:  "rev-1",
+				},
+			},
+			{
+				ServicePolicy: model.ServicePolicy{
+					ID:        "policy-2",
+					Name:      "echo-fault",
+					Namespace: "default",
+					Service:   "echo",
+					Rule:      `{"fault":{"abort":{"percentage":10,"http_status":503}}}`,
+					Revision:  "rev-2",
+				},
+				RouteName: "gray",
+			},
+		}
+		for i := range policies {
+			assert.NoError(t, s.CreateTrafficPolicy(policies[i]))
+		}
+
+		t.Run("按照ID查询", func(t *testing.T) {
+			ret, err := s.GetTrafficPolicyWithID("policy-1")
+			assert.NoError(t, err)
+			assert.Equal(t, "echo-retry", ret.Name)
+			assert.True(t, ret.Enable)
+			assert.Equal(t, policies[0].Rule, ret.Rule)
+		})
+
+		t.Run("按照服务以及路由查询", func(t *testing.T) {
+			total, ret, err := s.GetTrafficPolicies(map[string]string{
+				"namespace": "default",
+				"service":   "echo",
+			}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(2), total)
+			assert.Equal(t, 2, len(ret))
+
+			total, ret, err = s.GetTrafficPolicies(map[string]string{"route_name": "gray"}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+			assert.Equal(t, "policy-2", ret[0].ID)
+
+			total, _, err = s.GetTrafficPolicies(map[string]string{"name": "fault"}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+		})
+
+		t.Run("更新流量策略", func(t *testing.T) {
+			update := *policies[1]
+			update.Enable = true
+			update.Revision = "rev-3"
+			assert.NoError(t, s.UpdateTrafficPolicy(&update))
+
+			ret, err := s.GetTrafficPolicyWithID("policy-2")
+			assert.NoError(t, err)
+			assert.True(t, ret.Enable)
+			assert.Equal(t, "rev-3", ret.Revision)
+		})
+
+		t.Run("删除流量策略", func(t *testing.T) {
+			assert.NoError(t, s.DeleteTrafficPolicy("policy-1"))
+
+			ret, err := s.GetTrafficPolicyWithID("policy-1")
+			assert.NoError(t, err)
+			assert.Nil(t, ret)
+
+			total, _, err := s.GetTrafficPolicies(map[string]string{}, 0, 10)
+			assert.NoError(t, err)
+			assert.Equal(t, uint32(1), total)
+		})
+
+		t.Run("增量拉取缓存数据", func(t *testing.T) {
+			ret, err := s.GetTrafficPoliciesForCache(time.Time{}, true)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, len(ret))
+
+			ret, err = s.GetTrafficPoliciesForCache(start, false)
+			assert.NoError(t, err)
+			assert.Equal(t, 2, len(ret))
+			for i := range ret {
+				if ret[i].ID == "policy-1" {
+					assert.False(t, ret[i].Valid)
+				}
+			}
+		})
+	})
+}
@@ -31,6 +31,7 @@ type ConfigFileModuleStore interface {
 	ConfigFileReleaseHistoryStore
 	ConfigFileTagStore
 	ConfigFileTemplateStore
+	ConfigFileApprovalStore
 }
 
 // ConfigFileGroupStore 配置文件组存储接口
@@ -59,6 +60,9 @@ type ConfigFileGroupStore interface {
 
 	// CountConfigGroupEachNamespace 统计 namespace 下的配置分组数量
 	CountGroupEachNamespace() (map[string]int64, error)
+
+	// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema，schema 为空表示清除
+	UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy string) error
 }
 
 // ConfigFileStore 配置文件存储接口
@@ -113,6 +117,18 @@ type ConfigFileReleaseStore interface {
 
 	// CountConfigFileReleaseEachGroup 统计 namespace.group 下的已发布配置文件数量
 	CountConfigFileReleaseEachGroup() (map[string]map[string]int64, error)
+
+	// SaveConfigFileGrayRelease 保存配置文件灰度发布，已存在则覆盖
+	SaveConfigFileGrayRelease(tx Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error)
+
+	// GetConfigFileGrayRelease 获取配置文件灰度发布，只获取 flag=0 的记录
+	GetConfigFileGrayRelease(tx Tx, namespace, group, fileName string) (*model.ConfigFileGrayRelease, error)
+
+	// DeleteConfigFileGrayRelease 删除配置文件灰度发布
+	DeleteConfigFileGrayRelease(tx Tx, namespace, group, fileName, deleteBy string) error
+
+	// FindConfigFileGrayReleaseByModifyTimeAfter 获取最近更新的配置文件灰度发布，包含已删除的记录
+	FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error)
 }
 
 // ConfigFileReleaseHistoryStore 配置文件发布历史存储接口
@@ -127,6 +143,37 @@ type ConfigFileReleaseHistoryStore interface {
 
 	// GetLatestConfigFileReleaseHistory 获取配置文件最后一次发布
 	GetLatestConfigFileReleaseHistory(namespace, group, fileName string) (*model.ConfigFileReleaseHistory, error)
+
+	// GetConfigFileReleaseHistory 根据 ID 获取配置文件发布历史记录
+	GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error)
+}
+
+// ConfigFileApprovalStore 配置发布审批存储接口
+type ConfigFileApprovalStore interface {
+
+	// SaveConfigFileApprovalPolicy 保存配置发布审批策略，同一个命名空间、分组下已存在则覆盖
+	SaveConfigFileApprovalPolicy(policy *model.ConfigFileApprovalPolicy) error
+
+	// GetConfigFileApprovalPolicy 获取配置发布审批策略，group 为空时获取命名空间级别的策略
+	GetConfigFileApprovalPolicy(namespace, group string) (*model.ConfigFileApprovalPolicy, error)
+
+	// DeleteConfigFileApprovalPolicy 删除配置发布审批策略
+	DeleteConfigFileApprovalPolicy(namespace, group string) error
+
+	// CreateConfigFileReleaseRequest 创建配置发布申请
+	CreateConfigFileReleaseRequest(tx Tx,
+		request *model.ConfigFileReleaseRequest) (*model.ConfigFileReleaseRequest, error)
+
+	// UpdateConfigFileReleaseRequest 更新配置发布申请的状态以及审批人，只有当前 Revision 与 preRevision
+	// 一致时才会更新成功，否则返回 DataConflictErr
+	UpdateConfigFileReleaseRequest(tx Tx, request *model.ConfigFileReleaseRequest, preRevision string) error
+
+	// GetConfigFileReleaseRequest 根据 ID 获取配置发布申请
+	GetConfigFileReleaseRequest(tx Tx, id uint64) (*model.ConfigFileReleaseRequest, error)
+
+	// QueryConfigFileReleaseRequests 查询配置发布申请，参数为空时不作为过滤条件，按照 ID 倒序返回
+	QueryConfigFileReleaseRequests(namespace, group, fileName, status string, offset,
+		limit uint32) (uint32, []*model.ConfigFileReleaseRequest, error)
 }
 
 type ConfigFileTagStore interface {
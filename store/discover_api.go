@@ -43,12 +43,18 @@ type NamingModuleStore interface {
 	UserStore
 	// GroupStore 用户组接口
 	GroupStore
+	// RevokedTokenStore 临时 token 吊销列表接口
+	RevokedTokenStore
 	// StrategyStore 鉴权策略接口
 	StrategyStore
 	// RoutingConfigStoreV2 路由策略 v2 接口
 	RoutingConfigStoreV2
 	// FaultDetectRuleStore fault detect rule interface
 	FaultDetectRuleStore
+	// TrafficPolicyStore 流量策略接口
+	TrafficPolicyStore
+	// AccessPolicyStore 访问控制策略接口
+	AccessPolicyStore
 }
 
 // ServiceStore 服务存储接口
@@ -387,3 +393,47 @@ type FaultDetectRuleStore interface {
 	// GetFaultDetectRulesForCache get increment fault detect rules
 	GetFaultDetectRulesForCache(mtime time.Time, firstUpdate bool) ([]*model.FaultDetectRule, error)
 }
+
+// TrafficPolicyStore 流量策略的存储接口
+type TrafficPolicyStore interface {
+	// CreateTrafficPolicy 新增流量策略
+	CreateTrafficPolicy(policy *model.TrafficPolicy) error
+
+	// UpdateTrafficPolicy 更新流量策略
+	UpdateTrafficPolicy(policy *model.TrafficPolicy) error
+
+	// DeleteTrafficPolicy 删除流量策略
+	DeleteTrafficPolicy(id string) error
+
+	// GetTrafficPolicyWithID 根据ID获取流量策略
+	GetTrafficPolicyWithID(id string) (*model.TrafficPolicy, error)
+
+	// GetTrafficPolicies 根据过滤条件查询流量策略
+	GetTrafficPolicies(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.TrafficPolicy, error)
+
+	// GetTrafficPoliciesForCache 根据修改时间拉取增量流量策略
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetTrafficPoliciesForCache(mtime time.Time, firstUpdate bool) ([]*model.TrafficPolicy, error)
+}
+
+// AccessPolicyStore 访问控制策略的存储接口
+type AccessPolicyStore interface {
+	// CreateAccessPolicy 新增访问控制策略
+	CreateAccessPolicy(policy *model.AccessPolicy) error
+
+	// UpdateAccessPolicy 更新访问控制策略
+	UpdateAccessPolicy(policy *model.AccessPolicy) error
+
+	// DeleteAccessPolicy 删除访问控制策略
+	DeleteAccessPolicy(id string) error
+
+	// GetAccessPolicyWithID 根据ID获取访问控制策略
+	GetAccessPolicyWithID(id string) (*model.AccessPolicy, error)
+
+	// GetAccessPolicies 根据过滤条件查询访问控制策略
+	GetAccessPolicies(filter map[string]string, offset uint32, limit uint32) (uint32, []*model.AccessPolicy, error)
+
+	// GetAccessPoliciesForCache 根据修改时间拉取增量访问控制策略
+	// 此方法用于 cache 增量更新，需要注意 mtime 应为数据库时间戳
+	GetAccessPoliciesForCache(mtime time.Time, firstUpdate bool) ([]*model.AccessPolicy, error)
+}
@@ -9,7 +9,6 @@ import (
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-
 	model "github.com/polarismesh/polaris/common/model"
 	store "github.com/polarismesh/polaris/store"
 )
@@ -79,6 +78,34 @@ func (mr *MockStoreMockRecorder) AddNamespace(namespace interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNamespace", reflect.TypeOf((*MockStore)(nil).AddNamespace), namespace)
 }
 
+// AddOperationRecords mocks base method.
+func (m *MockStore) AddOperationRecords(records []*model.RecordEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOperationRecords", records)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOperationRecords indicates an expected call of AddOperationRecords.
+func (mr *MockStoreMockRecorder) AddOperationRecords(records interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOperationRecords", reflect.TypeOf((*MockStore)(nil).AddOperationRecords), records)
+}
+
+// AddRevokedToken mocks base method.
+func (m *MockStore) AddRevokedToken(token *model.RevokedToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRevokedToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRevokedToken indicates an expected call of AddRevokedToken.
+func (mr *MockStoreMockRecorder) AddRevokedToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRevokedToken", reflect.TypeOf((*MockStore)(nil).AddRevokedToken), token)
+}
+
 // AddService mocks base method.
 func (m *MockStore) AddService(service *model.Service) error {
 	m.ctrl.T.Helper()
@@ -193,6 +220,21 @@ func (mr *MockStoreMockRecorder) BatchCleanDeletedInstances(timeout, batchSize i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCleanDeletedInstances", reflect.TypeOf((*MockStore)(nil).BatchCleanDeletedInstances), timeout, batchSize)
 }
 
+// BatchCleanOperationRecords mocks base method.
+func (m *MockStore) BatchCleanOperationRecords(before time.Time, batchSize uint32) (uint32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchCleanOperationRecords", before, batchSize)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchCleanOperationRecords indicates an expected call of BatchCleanOperationRecords.
+func (mr *MockStoreMockRecorder) BatchCleanOperationRecords(before, batchSize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCleanOperationRecords", reflect.TypeOf((*MockStore)(nil).BatchCleanOperationRecords), before, batchSize)
+}
+
 // BatchDeleteClients mocks base method.
 func (m *MockStore) BatchDeleteClients(ids []string) error {
 	m.ctrl.T.Helper()
@@ -352,6 +394,34 @@ func (mr *MockStoreMockRecorder) CountGroupEachNamespace() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountGroupEachNamespace", reflect.TypeOf((*MockStore)(nil).CountGroupEachNamespace))
 }
 
+// CreateAccessPolicy mocks base method.
+func (m *MockStore) CreateAccessPolicy(policy *model.AccessPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccessPolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAccessPolicy indicates an expected call of CreateAccessPolicy.
+func (mr *MockStoreMockRecorder) CreateAccessPolicy(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessPolicy", reflect.TypeOf((*MockStore)(nil).CreateAccessPolicy), policy)
+}
+
+// CreateCertificateAuthority mocks base method.
+func (m *MockStore) CreateCertificateAuthority(ca *model.CertificateAuthority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCertificateAuthority", ca)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCertificateAuthority indicates an expected call of CreateCertificateAuthority.
+func (mr *MockStoreMockRecorder) CreateCertificateAuthority(ca interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCertificateAuthority", reflect.TypeOf((*MockStore)(nil).CreateCertificateAuthority), ca)
+}
+
 // CreateCircuitBreaker mocks base method.
 func (m *MockStore) CreateCircuitBreaker(circuitBreaker *model.CircuitBreaker) error {
 	m.ctrl.T.Helper()
@@ -439,6 +509,21 @@ func (mr *MockStoreMockRecorder) CreateConfigFileReleaseHistory(tx, fileReleaseH
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileReleaseHistory", reflect.TypeOf((*MockStore)(nil).CreateConfigFileReleaseHistory), tx, fileReleaseHistory)
 }
 
+// CreateConfigFileReleaseRequest mocks base method.
+func (m *MockStore) CreateConfigFileReleaseRequest(tx store.Tx, request *model.ConfigFileReleaseRequest) (*model.ConfigFileReleaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConfigFileReleaseRequest", tx, request)
+	ret0, _ := ret[0].(*model.ConfigFileReleaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateConfigFileReleaseRequest indicates an expected call of CreateConfigFileReleaseRequest.
+func (mr *MockStoreMockRecorder) CreateConfigFileReleaseRequest(tx, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConfigFileReleaseRequest", reflect.TypeOf((*MockStore)(nil).CreateConfigFileReleaseRequest), tx, request)
+}
+
 // CreateConfigFileTag mocks base method.
 func (m *MockStore) CreateConfigFileTag(tx store.Tx, fileTag *model.ConfigFileTag) error {
 	m.ctrl.T.Helper()
@@ -538,6 +623,20 @@ func (mr *MockStoreMockRecorder) CreateRoutingConfigV2Tx(tx, conf interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRoutingConfigV2Tx", reflect.TypeOf((*MockStore)(nil).CreateRoutingConfigV2Tx), tx, conf)
 }
 
+// CreateTrafficPolicy mocks base method.
+func (m *MockStore) CreateTrafficPolicy(policy *model.TrafficPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTrafficPolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTrafficPolicy indicates an expected call of CreateTrafficPolicy.
+func (mr *MockStoreMockRecorder) CreateTrafficPolicy(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTrafficPolicy", reflect.TypeOf((*MockStore)(nil).CreateTrafficPolicy), policy)
+}
+
 // CreateTransaction mocks base method.
 func (m *MockStore) CreateTransaction() (store.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -553,6 +652,20 @@ func (mr *MockStoreMockRecorder) CreateTransaction() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockStore)(nil).CreateTransaction))
 }
 
+// DeleteAccessPolicy mocks base method.
+func (m *MockStore) DeleteAccessPolicy(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccessPolicy", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccessPolicy indicates an expected call of DeleteAccessPolicy.
+func (mr *MockStoreMockRecorder) DeleteAccessPolicy(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccessPolicy", reflect.TypeOf((*MockStore)(nil).DeleteAccessPolicy), id)
+}
+
 // DeleteCircuitBreakerRule mocks base method.
 func (m *MockStore) DeleteCircuitBreakerRule(id string) error {
 	m.ctrl.T.Helper()
@@ -581,6 +694,34 @@ func (mr *MockStoreMockRecorder) DeleteConfigFile(tx, namespace, group, name int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFile", reflect.TypeOf((*MockStore)(nil).DeleteConfigFile), tx, namespace, group, name)
 }
 
+// DeleteConfigFileApprovalPolicy mocks base method.
+func (m *MockStore) DeleteConfigFileApprovalPolicy(namespace, group string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigFileApprovalPolicy", namespace, group)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigFileApprovalPolicy indicates an expected call of DeleteConfigFileApprovalPolicy.
+func (mr *MockStoreMockRecorder) DeleteConfigFileApprovalPolicy(namespace, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileApprovalPolicy", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileApprovalPolicy), namespace, group)
+}
+
+// DeleteConfigFileGrayRelease mocks base method.
+func (m *MockStore) DeleteConfigFileGrayRelease(tx store.Tx, namespace, group, fileName, deleteBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteConfigFileGrayRelease", tx, namespace, group, fileName, deleteBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteConfigFileGrayRelease indicates an expected call of DeleteConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) DeleteConfigFileGrayRelease(tx, namespace, group, fileName, deleteBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).DeleteConfigFileGrayRelease), tx, namespace, group, fileName, deleteBy)
+}
+
 // DeleteConfigFileGroup mocks base method.
 func (m *MockStore) DeleteConfigFileGroup(namespace, name string) error {
 	m.ctrl.T.Helper()
@@ -805,6 +946,20 @@ func (mr *MockStoreMockRecorder) DeleteTagCircuitBreaker(id, version interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTagCircuitBreaker", reflect.TypeOf((*MockStore)(nil).DeleteTagCircuitBreaker), id, version)
 }
 
+// DeleteTrafficPolicy mocks base method.
+func (m *MockStore) DeleteTrafficPolicy(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTrafficPolicy", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTrafficPolicy indicates an expected call of DeleteTrafficPolicy.
+func (mr *MockStoreMockRecorder) DeleteTrafficPolicy(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTrafficPolicy", reflect.TypeOf((*MockStore)(nil).DeleteTrafficPolicy), id)
+}
+
 // DeleteUser mocks base method.
 func (m *MockStore) DeleteUser(user *model.User) error {
 	m.ctrl.T.Helper()
@@ -875,6 +1030,21 @@ func (mr *MockStoreMockRecorder) EnableRouting(conf interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableRouting", reflect.TypeOf((*MockStore)(nil).EnableRouting), conf)
 }
 
+// FindConfigFileGrayReleaseByModifyTimeAfter mocks base method.
+func (m *MockStore) FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindConfigFileGrayReleaseByModifyTimeAfter", modifyTime)
+	ret0, _ := ret[0].([]*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindConfigFileGrayReleaseByModifyTimeAfter indicates an expected call of FindConfigFileGrayReleaseByModifyTimeAfter.
+func (mr *MockStoreMockRecorder) FindConfigFileGrayReleaseByModifyTimeAfter(modifyTime interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindConfigFileGrayReleaseByModifyTimeAfter", reflect.TypeOf((*MockStore)(nil).FindConfigFileGrayReleaseByModifyTimeAfter), modifyTime)
+}
+
 // FindConfigFileGroups mocks base method.
 func (m *MockStore) FindConfigFileGroups(namespace string, names []string) ([]*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -920,6 +1090,67 @@ func (mr *MockStoreMockRecorder) GenNextL5Sid(layoutID interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenNextL5Sid", reflect.TypeOf((*MockStore)(nil).GenNextL5Sid), layoutID)
 }
 
+// GetAccessPolicies mocks base method.
+func (m *MockStore) GetAccessPolicies(filter map[string]string, offset, limit uint32) (uint32, []*model.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessPolicies", filter, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.AccessPolicy)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAccessPolicies indicates an expected call of GetAccessPolicies.
+func (mr *MockStoreMockRecorder) GetAccessPolicies(filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessPolicies", reflect.TypeOf((*MockStore)(nil).GetAccessPolicies), filter, offset, limit)
+}
+
+// GetAccessPoliciesForCache mocks base method.
+func (m *MockStore) GetAccessPoliciesForCache(mtime time.Time, firstUpdate bool) ([]*model.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessPoliciesForCache", mtime, firstUpdate)
+	ret0, _ := ret[0].([]*model.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessPoliciesForCache indicates an expected call of GetAccessPoliciesForCache.
+func (mr *MockStoreMockRecorder) GetAccessPoliciesForCache(mtime, firstUpdate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessPoliciesForCache", reflect.TypeOf((*MockStore)(nil).GetAccessPoliciesForCache), mtime, firstUpdate)
+}
+
+// GetAccessPolicyWithID mocks base method.
+func (m *MockStore) GetAccessPolicyWithID(id string) (*model.AccessPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccessPolicyWithID", id)
+	ret0, _ := ret[0].(*model.AccessPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccessPolicyWithID indicates an expected call of GetAccessPolicyWithID.
+func (mr *MockStoreMockRecorder) GetAccessPolicyWithID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccessPolicyWithID", reflect.TypeOf((*MockStore)(nil).GetAccessPolicyWithID), id)
+}
+
+// GetCertificateAuthority mocks base method.
+func (m *MockStore) GetCertificateAuthority(id string) (*model.CertificateAuthority, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCertificateAuthority", id)
+	ret0, _ := ret[0].(*model.CertificateAuthority)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCertificateAuthority indicates an expected call of GetCertificateAuthority.
+func (mr *MockStoreMockRecorder) GetCertificateAuthority(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCertificateAuthority", reflect.TypeOf((*MockStore)(nil).GetCertificateAuthority), id)
+}
+
 // GetCircuitBreaker mocks base method.
 func (m *MockStore) GetCircuitBreaker(id, version string) (*model.CircuitBreaker, error) {
 	m.ctrl.T.Helper()
@@ -1056,6 +1287,36 @@ func (mr *MockStoreMockRecorder) GetConfigFile(tx, namespace, group, name interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFile", reflect.TypeOf((*MockStore)(nil).GetConfigFile), tx, namespace, group, name)
 }
 
+// GetConfigFileApprovalPolicy mocks base method.
+func (m *MockStore) GetConfigFileApprovalPolicy(namespace, group string) (*model.ConfigFileApprovalPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileApprovalPolicy", namespace, group)
+	ret0, _ := ret[0].(*model.ConfigFileApprovalPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileApprovalPolicy indicates an expected call of GetConfigFileApprovalPolicy.
+func (mr *MockStoreMockRecorder) GetConfigFileApprovalPolicy(namespace, group interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileApprovalPolicy", reflect.TypeOf((*MockStore)(nil).GetConfigFileApprovalPolicy), namespace, group)
+}
+
+// GetConfigFileGrayRelease mocks base method.
+func (m *MockStore) GetConfigFileGrayRelease(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileGrayRelease", tx, namespace, group, fileName)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileGrayRelease indicates an expected call of GetConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) GetConfigFileGrayRelease(tx, namespace, group, fileName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).GetConfigFileGrayRelease), tx, namespace, group, fileName)
+}
+
 // GetConfigFileGroup mocks base method.
 func (m *MockStore) GetConfigFileGroup(namespace, name string) (*model.ConfigFileGroup, error) {
 	m.ctrl.T.Helper()
@@ -1101,6 +1362,36 @@ func (mr *MockStoreMockRecorder) GetConfigFileRelease(tx, namespace, group, file
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileRelease", reflect.TypeOf((*MockStore)(nil).GetConfigFileRelease), tx, namespace, group, fileName)
 }
 
+// GetConfigFileReleaseHistory mocks base method.
+func (m *MockStore) GetConfigFileReleaseHistory(id uint64) (*model.ConfigFileReleaseHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileReleaseHistory", id)
+	ret0, _ := ret[0].(*model.ConfigFileReleaseHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileReleaseHistory indicates an expected call of GetConfigFileReleaseHistory.
+func (mr *MockStoreMockRecorder) GetConfigFileReleaseHistory(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileReleaseHistory", reflect.TypeOf((*MockStore)(nil).GetConfigFileReleaseHistory), id)
+}
+
+// GetConfigFileReleaseRequest mocks base method.
+func (m *MockStore) GetConfigFileReleaseRequest(tx store.Tx, id uint64) (*model.ConfigFileReleaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConfigFileReleaseRequest", tx, id)
+	ret0, _ := ret[0].(*model.ConfigFileReleaseRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConfigFileReleaseRequest indicates an expected call of GetConfigFileReleaseRequest.
+func (mr *MockStoreMockRecorder) GetConfigFileReleaseRequest(tx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConfigFileReleaseRequest", reflect.TypeOf((*MockStore)(nil).GetConfigFileReleaseRequest), tx, id)
+}
+
 // GetConfigFileReleaseWithAllFlag mocks base method.
 func (m *MockStore) GetConfigFileReleaseWithAllFlag(tx store.Tx, namespace, group, fileName string) (*model.ConfigFileRelease, error) {
 	m.ctrl.T.Helper()
@@ -1526,6 +1817,22 @@ func (mr *MockStoreMockRecorder) GetNamespaces(filter, offset, limit interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNamespaces", reflect.TypeOf((*MockStore)(nil).GetNamespaces), filter, offset, limit)
 }
 
+// GetOperationRecords mocks base method.
+func (m *MockStore) GetOperationRecords(filter map[string]string, startTime, endTime time.Time, offset, limit uint32) (uint32, []*model.RecordEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperationRecords", filter, startTime, endTime, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.RecordEntry)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOperationRecords indicates an expected call of GetOperationRecords.
+func (mr *MockStoreMockRecorder) GetOperationRecords(filter, startTime, endTime, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperationRecords", reflect.TypeOf((*MockStore)(nil).GetOperationRecords), filter, startTime, endTime, offset, limit)
+}
+
 // GetRateLimitWithID mocks base method.
 func (m *MockStore) GetRateLimitWithID(id string) (*model.RateLimit, error) {
 	m.ctrl.T.Helper()
@@ -1556,6 +1863,21 @@ func (mr *MockStoreMockRecorder) GetRateLimitsForCache(mtime, firstUpdate interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRateLimitsForCache", reflect.TypeOf((*MockStore)(nil).GetRateLimitsForCache), mtime, firstUpdate)
 }
 
+// GetRevokedTokensForCache mocks base method.
+func (m *MockStore) GetRevokedTokensForCache(mtime time.Time, firstUpdate bool) ([]*model.RevokedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevokedTokensForCache", mtime, firstUpdate)
+	ret0, _ := ret[0].([]*model.RevokedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevokedTokensForCache indicates an expected call of GetRevokedTokensForCache.
+func (mr *MockStoreMockRecorder) GetRevokedTokensForCache(mtime, firstUpdate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevokedTokensForCache", reflect.TypeOf((*MockStore)(nil).GetRevokedTokensForCache), mtime, firstUpdate)
+}
+
 // GetRoutingConfigV2WithID mocks base method.
 func (m *MockStore) GetRoutingConfigV2WithID(id string) (*model.RouterConfig, error) {
 	m.ctrl.T.Helper()
@@ -1860,6 +2182,52 @@ func (mr *MockStoreMockRecorder) GetSystemServices() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemServices", reflect.TypeOf((*MockStore)(nil).GetSystemServices))
 }
 
+// GetTrafficPolicies mocks base method.
+func (m *MockStore) GetTrafficPolicies(filter map[string]string, offset, limit uint32) (uint32, []*model.TrafficPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrafficPolicies", filter, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.TrafficPolicy)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTrafficPolicies indicates an expected call of GetTrafficPolicies.
+func (mr *MockStoreMockRecorder) GetTrafficPolicies(filter, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrafficPolicies", reflect.TypeOf((*MockStore)(nil).GetTrafficPolicies), filter, offset, limit)
+}
+
+// GetTrafficPoliciesForCache mocks base method.
+func (m *MockStore) GetTrafficPoliciesForCache(mtime time.Time, firstUpdate bool) ([]*model.TrafficPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrafficPoliciesForCache", mtime, firstUpdate)
+	ret0, _ := ret[0].([]*model.TrafficPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrafficPoliciesForCache indicates an expected call of GetTrafficPoliciesForCache.
+func (mr *MockStoreMockRecorder) GetTrafficPoliciesForCache(mtime, firstUpdate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrafficPoliciesForCache", reflect.TypeOf((*MockStore)(nil).GetTrafficPoliciesForCache), mtime, firstUpdate)
+}
+
+// GetTrafficPolicyWithID mocks base method.
+func (m *MockStore) GetTrafficPolicyWithID(id string) (*model.TrafficPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTrafficPolicyWithID", id)
+	ret0, _ := ret[0].(*model.TrafficPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTrafficPolicyWithID indicates an expected call of GetTrafficPolicyWithID.
+func (mr *MockStoreMockRecorder) GetTrafficPolicyWithID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrafficPolicyWithID", reflect.TypeOf((*MockStore)(nil).GetTrafficPolicyWithID), id)
+}
+
 // GetUnHealthyInstances mocks base method.
 func (m *MockStore) GetUnHealthyInstances(timeout time.Duration, limit uint32) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -2224,6 +2592,22 @@ func (mr *MockStoreMockRecorder) QueryConfigFileReleaseHistories(namespace, grou
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileReleaseHistories", reflect.TypeOf((*MockStore)(nil).QueryConfigFileReleaseHistories), namespace, group, fileName, offset, limit, endId)
 }
 
+// QueryConfigFileReleaseRequests mocks base method.
+func (m *MockStore) QueryConfigFileReleaseRequests(namespace, group, fileName, status string, offset, limit uint32) (uint32, []*model.ConfigFileReleaseRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryConfigFileReleaseRequests", namespace, group, fileName, status, offset, limit)
+	ret0, _ := ret[0].(uint32)
+	ret1, _ := ret[1].([]*model.ConfigFileReleaseRequest)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// QueryConfigFileReleaseRequests indicates an expected call of QueryConfigFileReleaseRequests.
+func (mr *MockStoreMockRecorder) QueryConfigFileReleaseRequests(namespace, group, fileName, status, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryConfigFileReleaseRequests", reflect.TypeOf((*MockStore)(nil).QueryConfigFileReleaseRequests), namespace, group, fileName, status, offset, limit)
+}
+
 // QueryConfigFiles mocks base method.
 func (m *MockStore) QueryConfigFiles(namespace, group, name string, offset, limit uint32) (uint32, []*model.ConfigFile, error) {
 	m.ctrl.T.Helper()
@@ -2313,6 +2697,49 @@ func (mr *MockStoreMockRecorder) RemoveStrategyResources(resources interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveStrategyResources", reflect.TypeOf((*MockStore)(nil).RemoveStrategyResources), resources)
 }
 
+// SaveCertificateAuthority mocks base method.
+func (m *MockStore) SaveCertificateAuthority(ca *model.CertificateAuthority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCertificateAuthority", ca)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCertificateAuthority indicates an expected call of SaveCertificateAuthority.
+func (mr *MockStoreMockRecorder) SaveCertificateAuthority(ca interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCertificateAuthority", reflect.TypeOf((*MockStore)(nil).SaveCertificateAuthority), ca)
+}
+
+// SaveConfigFileApprovalPolicy mocks base method.
+func (m *MockStore) SaveConfigFileApprovalPolicy(policy *model.ConfigFileApprovalPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveConfigFileApprovalPolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveConfigFileApprovalPolicy indicates an expected call of SaveConfigFileApprovalPolicy.
+func (mr *MockStoreMockRecorder) SaveConfigFileApprovalPolicy(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveConfigFileApprovalPolicy", reflect.TypeOf((*MockStore)(nil).SaveConfigFileApprovalPolicy), policy)
+}
+
+// SaveConfigFileGrayRelease mocks base method.
+func (m *MockStore) SaveConfigFileGrayRelease(tx store.Tx, grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveConfigFileGrayRelease", tx, grayRelease)
+	ret0, _ := ret[0].(*model.ConfigFileGrayRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveConfigFileGrayRelease indicates an expected call of SaveConfigFileGrayRelease.
+func (mr *MockStoreMockRecorder) SaveConfigFileGrayRelease(tx, grayRelease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveConfigFileGrayRelease", reflect.TypeOf((*MockStore)(nil).SaveConfigFileGrayRelease), tx, grayRelease)
+}
+
 // SetInstanceHealthStatus mocks base method.
 func (m *MockStore) SetInstanceHealthStatus(instanceID string, flag int, revision string) error {
 	m.ctrl.T.Helper()
@@ -2399,6 +2826,20 @@ func (mr *MockStoreMockRecorder) UnbindCircuitBreaker(serviceID, ruleID, ruleVer
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbindCircuitBreaker", reflect.TypeOf((*MockStore)(nil).UnbindCircuitBreaker), serviceID, ruleID, ruleVersion)
 }
 
+// UpdateAccessPolicy mocks base method.
+func (m *MockStore) UpdateAccessPolicy(policy *model.AccessPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccessPolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAccessPolicy indicates an expected call of UpdateAccessPolicy.
+func (mr *MockStoreMockRecorder) UpdateAccessPolicy(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccessPolicy", reflect.TypeOf((*MockStore)(nil).UpdateAccessPolicy), policy)
+}
+
 // UpdateCircuitBreaker mocks base method.
 func (m *MockStore) UpdateCircuitBreaker(circuitBraker *model.CircuitBreaker) error {
 	m.ctrl.T.Helper()
@@ -2457,6 +2898,20 @@ func (mr *MockStoreMockRecorder) UpdateConfigFileGroup(fileGroup interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileGroup", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileGroup), fileGroup)
 }
 
+// UpdateConfigFileGroupSchema mocks base method.
+func (m *MockStore) UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileGroupSchema", namespace, name, schema, modifyBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigFileGroupSchema indicates an expected call of UpdateConfigFileGroupSchema.
+func (mr *MockStoreMockRecorder) UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileGroupSchema", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileGroupSchema), namespace, name, schema, modifyBy)
+}
+
 // UpdateConfigFileRelease mocks base method.
 func (m *MockStore) UpdateConfigFileRelease(tx store.Tx, fileRelease *model.ConfigFileRelease) (*model.ConfigFileRelease, error) {
 	m.ctrl.T.Helper()
@@ -2472,6 +2927,20 @@ func (mr *MockStoreMockRecorder) UpdateConfigFileRelease(tx, fileRelease interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileRelease", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileRelease), tx, fileRelease)
 }
 
+// UpdateConfigFileReleaseRequest mocks base method.
+func (m *MockStore) UpdateConfigFileReleaseRequest(tx store.Tx, request *model.ConfigFileReleaseRequest, preRevision string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateConfigFileReleaseRequest", tx, request, preRevision)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateConfigFileReleaseRequest indicates an expected call of UpdateConfigFileReleaseRequest.
+func (mr *MockStoreMockRecorder) UpdateConfigFileReleaseRequest(tx, request, preRevision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateConfigFileReleaseRequest", reflect.TypeOf((*MockStore)(nil).UpdateConfigFileReleaseRequest), tx, request, preRevision)
+}
+
 // UpdateFaultDetectRule mocks base method.
 func (m *MockStore) UpdateFaultDetectRule(conf *model.FaultDetectRule) error {
 	m.ctrl.T.Helper()
@@ -2654,6 +3123,20 @@ func (mr *MockStoreMockRecorder) UpdateStrategy(strategy interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStrategy", reflect.TypeOf((*MockStore)(nil).UpdateStrategy), strategy)
 }
 
+// UpdateTrafficPolicy mocks base method.
+func (m *MockStore) UpdateTrafficPolicy(policy *model.TrafficPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTrafficPolicy", policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTrafficPolicy indicates an expected call of UpdateTrafficPolicy.
+func (mr *MockStoreMockRecorder) UpdateTrafficPolicy(policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTrafficPolicy", reflect.TypeOf((*MockStore)(nil).UpdateTrafficPolicy), policy)
+}
+
 // UpdateUser mocks base method.
 func (m *MockStore) UpdateUser(user *model.User) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,82 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+var _ store.AccessPolicyStore = (*accessPolicyStore)(nil)
+
+type accessPolicyStore struct {
+	master *BaseDB
+	slave  *BaseDB
+}
+
+var accessPolicyTable = &servicePolicyTable[*model.AccessPolicy]{
+	table:        "access_policy",
+	label:        "AccessPolicy",
+	extraColumns: []string{"dry_run"},
+	extraValues: func(policy *model.AccessPolicy) []interface{} {
+		return []interface{}{boolToInt(policy.DryRun)}
+	},
+	newRow: func() (*model.AccessPolicy, []interface{}, func()) {
+		var (
+			policy = &model.AccessPolicy{}
+			dryRun int
+		)
+		return policy, []interface{}{&dryRun}, func() {
+			policy.DryRun = dryRun == 1
+		}
+	},
+}
+
+// CreateAccessPolicy 新增访问控制策略
+func (t *accessPolicyStore) CreateAccessPolicy(policy *model.AccessPolicy) error {
+	return accessPolicyTable.create(t.master, policy)
+}
+
+// UpdateAccessPolicy 更新访问控制策略
+func (t *accessPolicyStore) UpdateAccessPolicy(policy *model.AccessPolicy) error {
+	return accessPolicyTable.update(t.master, policy)
+}
+
+// DeleteAccessPolicy 删除访问控制策略
+func (t *accessPolicyStore) DeleteAccessPolicy(id string) error {
+	return accessPolicyTable.delete(t.master, id)
+}
+
+// GetAccessPolicyWithID 根据ID获取访问控制策略
+func (t *accessPolicyStore) GetAccessPolicyWithID(id string) (*model.AccessPolicy, error) {
+	return accessPolicyTable.getWithID(t.master, id)
+}
+
+// GetAccessPolicies 根据过滤条件查询访问控制策略
+func (t *accessPolicyStore) GetAccessPolicies(filter map[string]string,
+	offset uint32, limit uint32) (uint32, []*model.AccessPolicy, error) {
+	return accessPolicyTable.list(t.master, filter, offset, limit)
+}
+
+// GetAccessPoliciesForCache 根据修改时间拉取增量访问控制策略
+func (t *accessPolicyStore) GetAccessPoliciesForCache(mtime time.Time,
+	firstUpdate bool) ([]*model.AccessPolicy, error) {
+	return accessPolicyTable.listForCache(t.slave, mtime, firstUpdate)
+}
@@ -0,0 +1,80 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+type certificateAuthorityStore struct {
+	master *BaseDB
+}
+
+// GetCertificateAuthority 根据 ID 获取 CA，不存在时返回 nil
+func (cs *certificateAuthorityStore) GetCertificateAuthority(id string) (*model.CertificateAuthority, error) {
+	querySql := "SELECT id, cert, `key`, IFNULL(chain, ''), UNIX_TIMESTAMP(ctime), UNIX_TIMESTAMP(mtime) " +
+		" FROM xds_certificate_authority WHERE id = ?"
+	var (
+		ca           = &model.CertificateAuthority{}
+		ctime, mtime int64
+	)
+	err := cs.master.DB.QueryRow(querySql, id).Scan(&ca.ID, &ca.CertPEM, &ca.KeyPEM, &ca.ChainPEM, &ctime, &mtime)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		log.Errorf("[Store][CA] get certificate authority(%s) err: %s", id, err.Error())
+		return nil, store.Error(err)
+	}
+	ca.CreateTime = time.Unix(ctime, 0)
+	ca.ModifyTime = time.Unix(mtime, 0)
+	return ca, nil
+}
+
+// CreateCertificateAuthority 创建 CA，已经存在时不做修改
+func (cs *certificateAuthorityStore) CreateCertificateAuthority(ca *model.CertificateAuthority) error {
+	if ca.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "certificate authority id is empty")
+	}
+	insertSql := "INSERT IGNORE INTO xds_certificate_authority(id, cert, `key`, chain, ctime, mtime) " +
+		" VALUES (?, ?, ?, ?, sysdate(), sysdate())"
+	if _, err := cs.master.Exec(insertSql, ca.ID, ca.CertPEM, ca.KeyPEM, ca.ChainPEM); err != nil {
+		log.Errorf("[Store][CA] create certificate authority(%s) err: %s", ca.ID, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
+
+// SaveCertificateAuthority 创建或者覆盖 CA
+func (cs *certificateAuthorityStore) SaveCertificateAuthority(ca *model.CertificateAuthority) error {
+	if ca.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "certificate authority id is empty")
+	}
+	insertSql := "INSERT INTO xds_certificate_authority(id, cert, `key`, chain, ctime, mtime) " +
+		" VALUES (?, ?, ?, ?, sysdate(), sysdate()) " +
+		" ON DUPLICATE KEY UPDATE cert = VALUES(cert), `key` = VALUES(`key`), chain = VALUES(chain), mtime = sysdate()"
+	if _, err := cs.master.Exec(insertSql, ca.ID, ca.CertPEM, ca.KeyPEM, ca.ChainPEM); err != nil {
+		log.Errorf("[Store][CA] save certificate authority(%s) err: %s", ca.ID, err.Error())
+		return store.Error(err)
+	}
+	return nil
+}
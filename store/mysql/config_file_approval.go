@@ -0,0 +1,236 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+type configFileApprovalStore struct {
+	master *BaseDB
+	slave  *BaseDB
+}
+
+// SaveConfigFileApprovalPolicy 保存配置发布审批策略，已存在则覆盖
+func (cs *configFileApprovalStore) SaveConfigFileApprovalPolicy(policy *model.ConfigFileApprovalPolicy) error {
+	s := "insert into config_file_approval_policy(namespace, `group`, required_approvals, users, user_groups, " +
+		" create_time, create_by, modify_time, modify_by) values (?,?,?,?,?,sysdate(),?,sysdate(),?) " +
+		" on duplicate key update required_approvals = ?, users = ?, user_groups = ?, modify_time = sysdate(), " +
+		" modify_by = ?"
+	_, err := cs.master.Exec(s, policy.Namespace, policy.Group, policy.RequiredApprovals, policy.Users,
+		policy.UserGroups, policy.CreateBy, policy.ModifyBy,
+		policy.RequiredApprovals, policy.Users, policy.UserGroups, policy.ModifyBy)
+	return store.Error(err)
+}
+
+// GetConfigFileApprovalPolicy 获取配置发布审批策略
+func (cs *configFileApprovalStore) GetConfigFileApprovalPolicy(namespace,
+	group string) (*model.ConfigFileApprovalPolicy, error) {
+	s := "select id, namespace, `group`, required_approvals, users, user_groups, UNIX_TIMESTAMP(create_time), " +
+		" IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') from config_file_approval_policy " +
+		" where namespace = ? and `group` = ?"
+	rows, err := cs.master.Query(s, namespace, group)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	defer rows.Close()
+
+	var policy *model.ConfigFileApprovalPolicy
+	for rows.Next() {
+		policy = &model.ConfigFileApprovalPolicy{}
+		var ctime, mtime int64
+		if err := rows.Scan(&policy.Id, &policy.Namespace, &policy.Group, &policy.RequiredApprovals,
+			&policy.Users, &policy.UserGroups, &ctime, &policy.CreateBy, &mtime, &policy.ModifyBy); err != nil {
+			return nil, err
+		}
+		policy.CreateTime = time.Unix(ctime, 0)
+		policy.ModifyTime = time.Unix(mtime, 0)
+		policy.Valid = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeleteConfigFileApprovalPolicy 删除配置发布审批策略
+func (cs *configFileApprovalStore) DeleteConfigFileApprovalPolicy(namespace, group string) error {
+	s := "delete from config_file_approval_policy where namespace = ? and `group` = ?"
+	_, err := cs.master.Exec(s, namespace, group)
+	return store.Error(err)
+}
+
+// CreateConfigFileReleaseRequest 创建配置发布申请
+func (cs *configFileApprovalStore) CreateConfigFileReleaseRequest(tx store.Tx,
+	request *model.ConfigFileReleaseRequest) (*model.ConfigFileReleaseRequest, error) {
+	s := "insert into config_file_release_request(namespace, `group`, file_name, release_name, comment, content, " +
+		" md5, status, required_approvals, approvers, reason, source_history_id, revision, create_time, create_by, " +
+		" modify_time, modify_by) values (?,?,?,?,?,?,?,?,?,?,?,?,?,sysdate(),?,sysdate(),?)"
+	args := []interface{}{
+		request.Namespace, request.Group, request.FileName, request.ReleaseName, request.Comment, request.Content,
+		request.Md5, request.Status, request.RequiredApprovals, request.Approvers, request.Reason,
+		request.SourceHistoryId, request.Revision, request.CreateBy, request.ModifyBy,
+	}
+	var (
+		result sql.Result
+		err    error
+	)
+	if tx != nil {
+		result, err = tx.GetDelegateTx().(*BaseTx).Exec(s, args...)
+	} else {
+		result, err = cs.master.Exec(s, args...)
+	}
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cs.GetConfigFileReleaseRequest(tx, uint64(id))
+}
+
+// UpdateConfigFileReleaseRequest 更新配置发布申请的状态以及审批人，通过 revision 实现乐观锁
+func (cs *configFileApprovalStore) UpdateConfigFileReleaseRequest(tx store.Tx,
+	request *model.ConfigFileReleaseRequest, preRevision string) error {
+	s := "update config_file_release_request set status = ?, approvers = ?, reason = ?, revision = ?, " +
+		" modify_time = sysdate(), modify_by = ? where id = ? and revision = ?"
+	args := []interface{}{
+		request.Status, request.Approvers, request.Reason, request.Revision, request.ModifyBy, request.Id,
+		preRevision,
+	}
+	var (
+		result sql.Result
+		err    error
+	)
+	if tx != nil {
+		result, err = tx.GetDelegateTx().(*BaseTx).Exec(s, args...)
+	} else {
+		result, err = cs.master.Exec(s, args...)
+	}
+	if err != nil {
+		return store.Error(err)
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return store.Error(err)
+	} else if affected == 0 {
+		return store.NewStatusError(store.DataConflictErr, "release request has been modified")
+	}
+	return nil
+}
+
+// GetConfigFileReleaseRequest 根据 ID 获取配置发布申请
+func (cs *configFileApprovalStore) GetConfigFileReleaseRequest(tx store.Tx,
+	id uint64) (*model.ConfigFileReleaseRequest, error) {
+	s := cs.baseRequestQuerySql() + " where id = ?"
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if tx != nil {
+		rows, err = tx.GetDelegateTx().(*BaseTx).Query(s, id)
+	} else {
+		rows, err = cs.master.Query(s, id)
+	}
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	requests, err := cs.transferRequestRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, nil
+	}
+	return requests[0], nil
+}
+
+// QueryConfigFileReleaseRequests 查询配置发布申请
+func (cs *configFileApprovalStore) QueryConfigFileReleaseRequests(namespace, group, fileName, status string,
+	offset, limit uint32) (uint32, []*model.ConfigFileReleaseRequest, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	columns := []string{"namespace", "`group`", "file_name", "status"}
+	for i, value := range []string{namespace, group, fileName, status} {
+		if value != "" {
+			conditions = append(conditions, columns[i]+" = ?")
+			args = append(args, value)
+		}
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " where " + strings.Join(conditions, " and ")
+	}
+
+	var total uint32
+	if err := cs.slave.QueryRow("select count(*) from config_file_release_request"+where,
+		args...).Scan(&total); err != nil {
+		return 0, nil, store.Error(err)
+	}
+	rows, err := cs.slave.Query(cs.baseRequestQuerySql()+where+" order by id desc limit ?, ?",
+		append(args, offset, limit)...)
+	if err != nil {
+		return 0, nil, store.Error(err)
+	}
+	requests, err := cs.transferRequestRows(rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	return total, requests, nil
+}
+
+func (cs *configFileApprovalStore) baseRequestQuerySql() string {
+	return "select id, namespace, `group`, file_name, release_name, IFNULL(comment, ''), content, md5, status, " +
+		" required_approvals, approvers, IFNULL(reason, ''), source_history_id, revision, UNIX_TIMESTAMP(create_time), " +
+		" IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, '') from config_file_release_request "
+}
+
+func (cs *configFileApprovalStore) transferRequestRows(rows *sql.Rows) ([]*model.ConfigFileReleaseRequest, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var requests []*model.ConfigFileReleaseRequest
+	for rows.Next() {
+		request := &model.ConfigFileReleaseRequest{}
+		var ctime, mtime int64
+		err := rows.Scan(&request.Id, &request.Namespace, &request.Group, &request.FileName, &request.ReleaseName,
+			&request.Comment, &request.Content, &request.Md5, &request.Status, &request.RequiredApprovals,
+			&request.Approvers, &request.Reason, &request.SourceHistoryId, &request.Revision, &ctime, &request.CreateBy, &mtime,
+			&request.ModifyBy)
+		if err != nil {
+			return nil, err
+		}
+		request.CreateTime = time.Unix(ctime, 0)
+		request.ModifyTime = time.Unix(mtime, 0)
+		request.Valid = true
+		requests = append(requests, request)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
@@ -0,0 +1,144 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// SaveConfigFileGrayRelease 保存配置文件灰度发布，已存在则覆盖
+func (cfr *configFileReleaseStore) SaveConfigFileGrayRelease(tx store.Tx,
+	grayRelease *model.ConfigFileGrayRelease) (*model.ConfigFileGrayRelease, error) {
+	s := "insert into config_file_gray_release(name, namespace, `group`, file_name, content, comment, md5, " +
+		" version, rule, flag, create_time, create_by, modify_time, modify_by) values " +
+		" (?,?,?,?,?,?,?,?,?,0, sysdate(),?,sysdate(),?) on duplicate key update " +
+		" name = ?, content = ?, comment = ?, md5 = ?, version = ?, rule = ?, " +
+		" create_time = if(flag = 1, sysdate(), create_time), create_by = if(flag = 1, ?, create_by), " +
+		" flag = 0, modify_time = sysdate(), modify_by = ?"
+	args := []interface{}{
+		grayRelease.Name, grayRelease.Namespace, grayRelease.Group, grayRelease.FileName, grayRelease.Content,
+		grayRelease.Comment, grayRelease.Md5, grayRelease.Version, grayRelease.Rule, grayRelease.CreateBy,
+		grayRelease.ModifyBy,
+		grayRelease.Name, grayRelease.Content, grayRelease.Comment, grayRelease.Md5, grayRelease.Version,
+		grayRelease.Rule, grayRelease.CreateBy, grayRelease.ModifyBy,
+	}
+	var err error
+	if tx != nil {
+		_, err = tx.GetDelegateTx().(*BaseTx).Exec(s, args...)
+	} else {
+		_, err = cfr.db.Exec(s, args...)
+	}
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return cfr.GetConfigFileGrayRelease(tx, grayRelease.Namespace, grayRelease.Group, grayRelease.FileName)
+}
+
+// GetConfigFileGrayRelease 获取配置文件灰度发布，只返回 flag=0 的记录
+func (cfr *configFileReleaseStore) GetConfigFileGrayRelease(tx store.Tx, namespace, group,
+	fileName string) (*model.ConfigFileGrayRelease, error) {
+	querySql := cfr.baseGrayQuerySql() + "where namespace = ? and `group` = ? and file_name = ? and flag = 0"
+
+	var (
+		rows *sql.Rows
+		err  error
+	)
+	if tx != nil {
+		rows, err = tx.GetDelegateTx().(*BaseTx).Query(querySql, namespace, group, fileName)
+	} else {
+		rows, err = cfr.db.Query(querySql, namespace, group, fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	grayReleases, err := cfr.transferGrayRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(grayReleases) > 0 {
+		return grayReleases[0], nil
+	}
+	return nil, nil
+}
+
+// DeleteConfigFileGrayRelease 删除配置文件灰度发布，仅标记删除，便于发布扫描感知到灰度结束
+func (cfr *configFileReleaseStore) DeleteConfigFileGrayRelease(tx store.Tx, namespace, group,
+	fileName, deleteBy string) error {
+	s := "update config_file_gray_release set flag = 1, modify_time = sysdate(), modify_by = ? " +
+		" where namespace = ? and `group` = ? and file_name = ? and flag = 0"
+	var err error
+	if tx != nil {
+		_, err = tx.GetDelegateTx().(*BaseTx).Exec(s, deleteBy, namespace, group, fileName)
+	} else {
+		_, err = cfr.db.Exec(s, deleteBy, namespace, group, fileName)
+	}
+	if err != nil {
+		return store.Error(err)
+	}
+	return nil
+}
+
+// FindConfigFileGrayReleaseByModifyTimeAfter 获取最后更新时间大于某个时间点的灰度发布，包含 flag = 1 的记录
+func (cfr *configFileReleaseStore) FindConfigFileGrayReleaseByModifyTimeAfter(
+	modifyTime time.Time) ([]*model.ConfigFileGrayRelease, error) {
+	s := cfr.baseGrayQuerySql() + " where modify_time > FROM_UNIXTIME(?)"
+	rows, err := cfr.slave.Query(s, timeToTimestamp(modifyTime))
+	if err != nil {
+		return nil, err
+	}
+	return cfr.transferGrayRows(rows)
+}
+
+func (cfr *configFileReleaseStore) baseGrayQuerySql() string {
+	return "select id, name, namespace, `group`, file_name, content, IFNULL(comment, ''), md5, version, rule, " +
+		" UNIX_TIMESTAMP(create_time), IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), IFNULL(modify_by, ''), " +
+		" flag from config_file_gray_release "
+}
+
+func (cfr *configFileReleaseStore) transferGrayRows(rows *sql.Rows) ([]*model.ConfigFileGrayRelease, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var grayReleases []*model.ConfigFileGrayRelease
+	for rows.Next() {
+		grayRelease := &model.ConfigFileGrayRelease{}
+		var ctime, mtime int64
+		err := rows.Scan(&grayRelease.Id, &grayRelease.Name, &grayRelease.Namespace, &grayRelease.Group,
+			&grayRelease.FileName, &grayRelease.Content, &grayRelease.Comment, &grayRelease.Md5,
+			&grayRelease.Version, &grayRelease.Rule, &ctime, &grayRelease.CreateBy, &mtime,
+			&grayRelease.ModifyBy, &grayRelease.Flag)
+		if err != nil {
+			return nil, err
+		}
+		grayRelease.CreateTime = time.Unix(ctime, 0)
+		grayRelease.ModifyTime = time.Unix(mtime, 0)
+		grayRelease.Valid = grayRelease.Flag == 0
+
+		grayReleases = append(grayReleases, grayRelease)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return grayReleases, nil
+}
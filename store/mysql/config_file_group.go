@@ -135,6 +135,16 @@ func (fg *configFileGroupStore) UpdateConfigFileGroup(
 	return fg.GetConfigFileGroup(fileGroup.Namespace, fileGroup.Name)
 }
 
+// UpdateConfigFileGroupSchema 更新配置文件组的 JSON Schema
+func (fg *configFileGroupStore) UpdateConfigFileGroupSchema(namespace, name, schema, modifyBy string) error {
+	updateSql := "update config_file_group set json_schema = ?, modify_time = sysdate(), modify_by = ? " +
+		" where namespace = ? and name = ?"
+	if _, err := fg.master.Exec(updateSql, schema, modifyBy, namespace, name); err != nil {
+		return store.Error(err)
+	}
+	return nil
+}
+
 // FindConfigFileGroups 获取一组配置文件组信息
 func (fg *configFileGroupStore) FindConfigFileGroups(namespace string,
 	names []string) ([]*model.ConfigFileGroup, error) {
@@ -215,7 +225,8 @@ func (fg *configFileGroupStore) CountGroupEachNamespace() (map[string]int64, err
 
 func (fg *configFileGroupStore) genConfigFileGroupSelectSql() string {
 	return "select id,name,namespace,IFNULL(comment,''),UNIX_TIMESTAMP(create_time),IFNULL(create_by,'')," +
-		"UNIX_TIMESTAMP(modify_time),IFNULL(modify_by,''),IFNULL(owner,'') from config_file_group"
+		"UNIX_TIMESTAMP(modify_time),IFNULL(modify_by,''),IFNULL(owner,''),IFNULL(json_schema,'') " +
+		"from config_file_group"
 }
 
 func (fg *configFileGroupStore) transferRows(rows *sql.Rows) ([]*model.ConfigFileGroup, error) {
@@ -230,7 +241,7 @@ func (fg *configFileGroupStore) transferRows(rows *sql.Rows) ([]*model.ConfigFil
 		fileGroup := &model.ConfigFileGroup{}
 		var ctime, mtime int64
 		err := rows.Scan(&fileGroup.Id, &fileGroup.Name, &fileGroup.Namespace, &fileGroup.Comment, &ctime,
-			&fileGroup.CreateBy, &mtime, &fileGroup.ModifyBy, &fileGroup.Owner)
+			&fileGroup.CreateBy, &mtime, &fileGroup.ModifyBy, &fileGroup.Owner, &fileGroup.JsonSchema)
 		if err != nil {
 			return nil, err
 		}
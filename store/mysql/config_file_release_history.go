@@ -33,22 +33,24 @@ type configFileReleaseHistoryStore struct {
 func (rh *configFileReleaseHistoryStore) CreateConfigFileReleaseHistory(tx store.Tx,
 	fileReleaseHistory *model.ConfigFileReleaseHistory) error {
 	s := "insert into config_file_release_history(name, namespace, `group`, file_name, content, comment, " +
-		" md5, type, status, format, tags, " +
+		" md5, type, status, format, tags, approvers, source_history_id, " +
 		"create_time, create_by, modify_time, modify_by) values " +
-		"(?,?,?,?,?,?,?,?,?,?,?,sysdate(),?,sysdate(),?)"
+		"(?,?,?,?,?,?,?,?,?,?,?,?,?,sysdate(),?,sysdate(),?)"
 	var err error
 	if tx != nil {
 		_, err = tx.GetDelegateTx().(*BaseTx).Exec(s, fileReleaseHistory.Name, fileReleaseHistory.Namespace,
 			fileReleaseHistory.Group, fileReleaseHistory.FileName, fileReleaseHistory.Content,
 			fileReleaseHistory.Comment, fileReleaseHistory.Md5,
 			fileReleaseHistory.Type, fileReleaseHistory.Status, fileReleaseHistory.Format, fileReleaseHistory.Tags,
-			fileReleaseHistory.CreateBy, fileReleaseHistory.ModifyBy)
+			fileReleaseHistory.Approvers, fileReleaseHistory.SourceHistoryId, fileReleaseHistory.CreateBy,
+			fileReleaseHistory.ModifyBy)
 	} else {
 		_, err = rh.db.Exec(s, fileReleaseHistory.Name, fileReleaseHistory.Namespace,
 			fileReleaseHistory.Group, fileReleaseHistory.FileName, fileReleaseHistory.Content,
 			fileReleaseHistory.Comment, fileReleaseHistory.Md5,
 			fileReleaseHistory.Type, fileReleaseHistory.Status, fileReleaseHistory.Format, fileReleaseHistory.Tags,
-			fileReleaseHistory.CreateBy, fileReleaseHistory.ModifyBy)
+			fileReleaseHistory.Approvers, fileReleaseHistory.SourceHistoryId, fileReleaseHistory.CreateBy,
+			fileReleaseHistory.ModifyBy)
 	}
 	if err != nil {
 		return store.Error(err)
@@ -120,9 +122,31 @@ func (rh *configFileReleaseHistoryStore) GetLatestConfigFileReleaseHistory(names
 	return fileReleaseHistories[0], nil
 }
 
+// GetConfigFileReleaseHistory 根据 ID 获取发布记录
+func (rh *configFileReleaseHistoryStore) GetConfigFileReleaseHistory(
+	id uint64) (*model.ConfigFileReleaseHistory, error) {
+	s := rh.genSelectSql() + "where id = ?"
+	rows, err := rh.db.Query(s, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fileReleaseHistories, err := rh.transferRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fileReleaseHistories) == 0 {
+		return nil, nil
+	}
+
+	return fileReleaseHistories[0], nil
+}
+
 func (rh *configFileReleaseHistoryStore) genSelectSql() string {
 	return "select id, name, namespace, `group`, file_name, content, IFNULL(comment, ''), md5, format, tags, type, " +
-		" status, UNIX_TIMESTAMP(create_time), IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), " +
+		" status, IFNULL(approvers, ''), IFNULL(source_history_id, 0), UNIX_TIMESTAMP(create_time), " +
+		" IFNULL(create_by, ''), UNIX_TIMESTAMP(modify_time), " +
 		"IFNULL(modify_by, '') from config_file_release_history "
 }
 
@@ -142,8 +166,8 @@ func (rh *configFileReleaseHistoryStore) transferRows(rows *sql.Rows) ([]*model.
 			&fileReleaseHistory.FileName, &fileReleaseHistory.Content,
 			&fileReleaseHistory.Comment, &fileReleaseHistory.Md5, &fileReleaseHistory.Format,
 			&fileReleaseHistory.Tags,
-			&fileReleaseHistory.Type, &fileReleaseHistory.Status,
-			&ctime, &fileReleaseHistory.CreateBy, &mtime, &fileReleaseHistory.ModifyBy)
+			&fileReleaseHistory.Type, &fileReleaseHistory.Status, &fileReleaseHistory.Approvers,
+			&fileReleaseHistory.SourceHistoryId, &ctime, &fileReleaseHistory.CreateBy, &mtime, &fileReleaseHistory.ModifyBy)
 		if err != nil {
 			return nil, err
 		}
@@ -58,6 +58,8 @@ type stableStore struct {
 	*groupStore
 	*strategyStore
 	*faultDetectRuleStore
+	*trafficPolicyStore
+	*accessPolicyStore
 
 	// 配置中心stores
 	*configFileGroupStore
@@ -66,6 +68,7 @@ type stableStore struct {
 	*configFileReleaseHistoryStore
 	*configFileTagStore
 	*configFileTemplateStore
+	*configFileApprovalStore
 
 	// client info stores
 	*clientStore
@@ -76,6 +79,15 @@ type stableStore struct {
 	// maintain store
 	*adminStore
 
+	// 操作记录 store
+	*operationRecordStore
+
+	// 临时 token 吊销列表 store
+	*revokedTokenStore
+
+	// xDS 内置 CA store
+	*certificateAuthorityStore
+
 	// 主数据库，可以进行读写
 	master *BaseDB
 	// 对主数据库的事务操作，可读写
@@ -266,10 +278,15 @@ func (s *stableStore) newStore() {
 
 	s.groupStore = &groupStore{master: s.master, slave: s.slave}
 
+	s.revokedTokenStore = &revokedTokenStore{master: s.master}
+
 	s.strategyStore = &strategyStore{master: s.master, slave: s.slave}
 
 	s.faultDetectRuleStore = &faultDetectRuleStore{master: s.master, slave: s.slave}
 
+	s.trafficPolicyStore = &trafficPolicyStore{master: s.master, slave: s.slave}
+	s.accessPolicyStore = &accessPolicyStore{master: s.master, slave: s.slave}
+
 	s.configFileGroupStore = &configFileGroupStore{master: s.master, slave: s.slave}
 
 	s.configFileStore = &configFileStore{master: s.master, slave: s.slave}
@@ -282,11 +299,17 @@ func (s *stableStore) newStore() {
 
 	s.configFileTemplateStore = &configFileTemplateStore{db: s.master}
 
+	s.configFileApprovalStore = &configFileApprovalStore{master: s.master, slave: s.slave}
+
 	s.clientStore = &clientStore{master: s.master, slave: s.slave}
 
 	s.routingConfigStoreV2 = &routingConfigStoreV2{master: s.master, slave: s.slave}
 
 	s.adminStore = newAdminStore(s.master)
+
+	s.operationRecordStore = &operationRecordStore{master: s.master, slave: s.slave}
+
+	s.certificateAuthorityStore = &certificateAuthorityStore{master: s.master}
 }
 
 func buildEtimeStr(enable bool) string {
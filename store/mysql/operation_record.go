@@ -0,0 +1,149 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// operationRecordFilterColumns 查询参数与数据库字段的映射
+var operationRecordFilterColumns = map[string]string{
+	"resource_type":  "resource_type",
+	"resource_name":  "resource_name",
+	"namespace":      "namespace",
+	"operator":       "operator",
+	"operation_type": "operation_type",
+}
+
+type operationRecordStore struct {
+	master *BaseDB
+	slave  *BaseDB
+}
+
+// AddOperationRecords 批量保存操作记录
+func (rs *operationRecordStore) AddOperationRecords(records []*model.RecordEntry) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var (
+		placeholders []string
+		args         []interface{}
+	)
+	for _, record := range records {
+		placeholders = append(placeholders, "(?,?,?,?,?,?,?,FROM_UNIXTIME(?))")
+		args = append(args, string(record.ResourceType), record.ResourceName, record.Namespace, record.Operator,
+			string(record.OperationType), record.Detail, record.Server, record.HappenTime.Unix())
+	}
+	s := "insert into operation_record(resource_type, resource_name, namespace, operator, operation_type, " +
+		" detail, server, happen_time) values " + strings.Join(placeholders, ",")
+	_, err := rs.master.Exec(s, args...)
+	return store.Error(err)
+}
+
+// GetOperationRecords 根据过滤条件以及时间范围查询操作记录，按照发生时间倒序返回
+func (rs *operationRecordStore) GetOperationRecords(filter map[string]string, startTime, endTime time.Time,
+	offset, limit uint32) (uint32, []*model.RecordEntry, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	for key, value := range filter {
+		column, ok := operationRecordFilterColumns[key]
+		if !ok || value == "" {
+			continue
+		}
+		conditions = append(conditions, column+" = ?")
+		args = append(args, value)
+	}
+	if !startTime.IsZero() {
+		conditions = append(conditions, "happen_time >= FROM_UNIXTIME(?)")
+		args = append(args, startTime.Unix())
+	}
+	if !endTime.IsZero() {
+		conditions = append(conditions, "happen_time <= FROM_UNIXTIME(?)")
+		args = append(args, endTime.Unix())
+	}
+	where := ""
+	if len(conditions) > 0 {
+		where = " where " + strings.Join(conditions, " and ")
+	}
+
+	var total uint32
+	if err := rs.slave.QueryRow("select count(*) from operation_record"+where, args...).Scan(&total); err != nil {
+		return 0, nil, store.Error(err)
+	}
+	s := "select resource_type, resource_name, namespace, operator, operation_type, IFNULL(detail, ''), server, " +
+		" UNIX_TIMESTAMP(happen_time) from operation_record" + where + " order by happen_time desc, id desc limit ?, ?"
+	rows, err := rs.slave.Query(s, append(args, offset, limit)...)
+	if err != nil {
+		return 0, nil, store.Error(err)
+	}
+	records, err := rs.transferRows(rows)
+	if err != nil {
+		return 0, nil, store.Error(err)
+	}
+	return total, records, nil
+}
+
+// BatchCleanOperationRecords 批量清理指定时间之前的操作记录
+func (rs *operationRecordStore) BatchCleanOperationRecords(before time.Time, batchSize uint32) (uint32, error) {
+	result, err := rs.master.Exec("delete from operation_record where happen_time < FROM_UNIXTIME(?) limit ?",
+		before.Unix(), batchSize)
+	if err != nil {
+		log.Errorf("[Store][database] batch clean operation records(%d), err: %s", batchSize, err.Error())
+		return 0, store.Error(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, store.Error(err)
+	}
+	return uint32(rows), nil
+}
+
+func (rs *operationRecordStore) transferRows(rows *sql.Rows) ([]*model.RecordEntry, error) {
+	if rows == nil {
+		return nil, nil
+	}
+	defer rows.Close()
+
+	var records []*model.RecordEntry
+	for rows.Next() {
+		var (
+			record                      = &model.RecordEntry{}
+			resourceType, operationType string
+			happenTime                  int64
+		)
+		if err := rows.Scan(&resourceType, &record.ResourceName, &record.Namespace, &record.Operator,
+			&operationType, &record.Detail, &record.Server, &happenTime); err != nil {
+			return nil, err
+		}
+		record.ResourceType = model.Resource(resourceType)
+		record.OperationType = model.OperationType(operationType)
+		record.HappenTime = time.Unix(happenTime, 0)
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
@@ -0,0 +1,91 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// revokedTokenRetention 吊销记录在 token 过期之后继续保留的时间，避免各节点缓存还未同步时被提前清理
+const revokedTokenRetention = time.Hour
+
+type revokedTokenStore struct {
+	master *BaseDB
+}
+
+// AddRevokedToken 保存吊销的 token，同时清理已经过期的吊销记录
+func (rs *revokedTokenStore) AddRevokedToken(token *model.RevokedToken) error {
+	if token.ID == "" {
+		return store.NewStatusError(store.EmptyParamsErr, "revoked token id is empty")
+	}
+	insertSql := "INSERT INTO revoked_token(id, principal, owner, expire_time, ctime, mtime) " +
+		" VALUES (?, ?, ?, FROM_UNIXTIME(?), sysdate(), sysdate()) " +
+		" ON DUPLICATE KEY UPDATE mtime = sysdate()"
+	if _, err := rs.master.Exec(insertSql, token.ID, token.Principal, token.Owner,
+		timeToTimestamp(token.ExpireTime)); err != nil {
+		log.Errorf("[Store][RevokedToken] add revoked token(%s) err: %s", token.ID, err.Error())
+		return store.Error(err)
+	}
+	if _, err := rs.master.Exec("DELETE FROM revoked_token WHERE expire_time < FROM_UNIXTIME(?)",
+		time.Now().Add(-revokedTokenRetention).Unix()); err != nil {
+		log.Errorf("[Store][RevokedToken] clean expired revoked token err: %s", err.Error())
+	}
+	return nil
+}
+
+// GetRevokedTokensForCache 获取还未过期的吊销记录
+func (rs *revokedTokenStore) GetRevokedTokensForCache(mtime time.Time,
+	firstUpdate bool) ([]*model.RevokedToken, error) {
+	querySql := "SELECT id, principal, owner, UNIX_TIMESTAMP(expire_time), UNIX_TIMESTAMP(ctime), " +
+		" UNIX_TIMESTAMP(mtime) FROM revoked_token WHERE expire_time > sysdate() "
+	args := make([]interface{}, 0, 1)
+	if !firstUpdate {
+		querySql += " AND mtime >= FROM_UNIXTIME(?)"
+		args = append(args, timeToTimestamp(mtime))
+	}
+	rows, err := rs.master.Query(querySql, args...)
+	if err != nil {
+		log.Errorf("[Store][RevokedToken] get revoked tokens for cache err: %s", err.Error())
+		return nil, store.Error(err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	tokens := make([]*model.RevokedToken, 0)
+	for rows.Next() {
+		var (
+			token                    = &model.RevokedToken{}
+			expireTime, ctime, mtime int64
+		)
+		if err := rows.Scan(&token.ID, &token.Principal, &token.Owner, &expireTime, &ctime, &mtime); err != nil {
+			return nil, store.Error(err)
+		}
+		token.ExpireTime = time.Unix(expireTime, 0)
+		token.CreateTime = time.Unix(ctime, 0)
+		token.ModifyTime = time.Unix(mtime, 0)
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, store.Error(err)
+	}
+	return tokens, nil
+}
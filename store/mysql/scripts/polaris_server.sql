@@ -590,6 +590,7 @@ CREATE TABLE `config_file_group`
     `namespace`   varchar(64)     NOT NULL COMMENT '所属的namespace',
     `comment`     varchar(512)             DEFAULT NULL COMMENT '备注信息',
     `owner`       varchar(1024)            DEFAULT NULL COMMENT '负责人',
+    `json_schema` text                     DEFAULT NULL COMMENT '配置内容的 JSON Schema 约束',
     `create_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `create_by`   varchar(32)              DEFAULT NULL COMMENT '创建人',
     `modify_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
@@ -625,6 +626,126 @@ CREATE TABLE `config_file_release`
 ) ENGINE = InnoDB
   AUTO_INCREMENT = 1 COMMENT = '配置文件发布表';
 
+-- --------------------------------------------------------
+--
+-- Table structure `config_file_gray_release`
+--
+CREATE TABLE `config_file_gray_release`
+(
+    `id`          bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `name`        varchar(128)             DEFAULT NULL COMMENT '发布标题',
+    `namespace`   varchar(64)     NOT NULL COMMENT '所属的namespace',
+    `group`       varchar(128)    NOT NULL COMMENT '所属的文件组',
+    `file_name`   varchar(128)    NOT NULL COMMENT '配置文件名',
+    `content`     longtext        NOT NULL COMMENT '文件内容',
+    `comment`     varchar(512)             DEFAULT NULL COMMENT '备注信息',
+    `md5`         varchar(128)    NOT NULL COMMENT 'content的md5值',
+    `version`     int(11)         NOT NULL COMMENT '灰度版本号，大于正式发布的版本号',
+    `rule`        text            NOT NULL COMMENT '灰度客户端选择规则',
+    `flag`        tinyint(4)      NOT NULL DEFAULT '0' COMMENT '是否被删除',
+    `create_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by`   varchar(32)              DEFAULT NULL COMMENT '创建人',
+    `modify_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by`   varchar(32)              DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_file` (`namespace`, `group`, `file_name`),
+    KEY `idx_modify_time` (`modify_time`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '配置文件灰度发布表';
+
+-- --------------------------------------------------------
+--
+-- Table structure `config_file_approval_policy`
+--
+CREATE TABLE `config_file_approval_policy`
+(
+    `id`                 bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `namespace`          varchar(64)     NOT NULL COMMENT '所属的namespace',
+    `group`              varchar(128)    NOT NULL DEFAULT '' COMMENT '所属的文件组，为空表示对整个namespace生效',
+    `required_approvals` int(11)         NOT NULL COMMENT '发布前需要的审批人数',
+    `users`              varchar(2048)   NOT NULL DEFAULT '' COMMENT '可以审批的用户名，逗号分隔',
+    `user_groups`        varchar(2048)   NOT NULL DEFAULT '' COMMENT '可以审批的用户组名称，逗号分隔',
+    `create_time`        timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by`          varchar(32)              DEFAULT NULL COMMENT '创建人',
+    `modify_time`        timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by`          varchar(32)              DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uk_policy` (`namespace`, `group`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '配置发布审批策略表';
+
+-- --------------------------------------------------------
+--
+-- Table structure `config_file_release_request`
+--
+CREATE TABLE `config_file_release_request`
+(
+    `id`                 bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `namespace`          varchar(64)     NOT NULL COMMENT '所属的namespace',
+    `group`              varchar(128)    NOT NULL COMMENT '所属的文件组',
+    `file_name`          varchar(128)    NOT NULL COMMENT '配置文件名',
+    `release_name`       varchar(128)             DEFAULT '' COMMENT '发布标题',
+    `comment`            varchar(512)             DEFAULT NULL COMMENT '备注信息',
+    `content`            longtext        NOT NULL COMMENT '待发布的文件内容',
+    `md5`                varchar(128)    NOT NULL COMMENT 'content的md5值',
+    `status`             varchar(16)     NOT NULL COMMENT '申请状态，pending、approved、rejected、canceled、failed',
+    `required_approvals` int(11)         NOT NULL COMMENT '发布前需要的审批人数',
+    `approvers`          varchar(1024)   NOT NULL DEFAULT '' COMMENT '已经同意发布的审批人，逗号分隔',
+    `reason`             varchar(512)             DEFAULT NULL COMMENT '驳回、撤销或者发布失败的原因',
+    `source_history_id`  bigint unsigned NOT NULL DEFAULT 0 COMMENT '回滚申请回滚到的历史发布 ID',
+    `revision`           varchar(64)     NOT NULL COMMENT '版本标识，用于并发审批时的冲突检测',
+    `create_time`        timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    `create_by`          varchar(32)              DEFAULT NULL COMMENT '创建人',
+    `modify_time`        timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
+    `modify_by`          varchar(32)              DEFAULT NULL COMMENT '最后更新人',
+    PRIMARY KEY (`id`),
+    KEY `idx_file` (`namespace`, `group`, `file_name`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '配置发布申请表';
+
+CREATE TABLE `operation_record`
+(
+    `id`             bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键',
+    `resource_type`  varchar(64)     NOT NULL COMMENT '操作的资源类型',
+    `resource_name`  varchar(256)    NOT NULL DEFAULT '' COMMENT '操作的资源名称',
+    `namespace`      varchar(64)     NOT NULL DEFAULT '' COMMENT '资源所属的namespace',
+    `operator`       varchar(128)    NOT NULL DEFAULT '' COMMENT '操作人',
+    `operation_type` varchar(32)     NOT NULL COMMENT '操作类型',
+    `detail`         longtext                 DEFAULT NULL COMMENT '操作详情',
+    `server`         varchar(128)    NOT NULL DEFAULT '' COMMENT '处理该操作的server节点',
+    `happen_time`    timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '操作发生时间',
+    PRIMARY KEY (`id`),
+    KEY `idx_happen_time` (`happen_time`),
+    KEY `idx_resource` (`resource_type`, `resource_name`),
+    KEY `idx_namespace` (`namespace`),
+    KEY `idx_operator` (`operator`)
+) ENGINE = InnoDB
+  AUTO_INCREMENT = 1 COMMENT = '操作记录表';
+
+CREATE TABLE `revoked_token`
+(
+    `id`          varchar(64)  NOT NULL COMMENT '被吊销的临时token的唯一标识',
+    `principal`   varchar(128) NOT NULL COMMENT 'token对应的用户ID',
+    `owner`       varchar(128) NOT NULL DEFAULT '' COMMENT '用户对应的主账户ID',
+    `expire_time` timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'token的过期时间，过期之后吊销记录会被清理',
+    `ctime`       timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Create time',
+    `mtime`       timestamp    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last updated time',
+    PRIMARY KEY (`id`),
+    KEY `mtime` (`mtime`),
+    KEY `expire_time` (`expire_time`)
+) ENGINE = InnoDB COMMENT = '临时token吊销列表';
+
+CREATE TABLE `xds_certificate_authority`
+(
+    `id`        varchar(32) NOT NULL COMMENT 'CA记录标识，root为内置根CA，intermediate为导入的中间CA',
+    `cert`      text        NOT NULL COMMENT 'CA证书，PEM格式',
+    `key`       text        NOT NULL COMMENT 'CA私钥，PEM格式',
+    `chain`     text                 DEFAULT NULL COMMENT '上级证书链，PEM格式',
+    `ctime`     timestamp   NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Create time',
+    `mtime`     timestamp   NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last updated time',
+    PRIMARY KEY (`id`)
+) ENGINE = InnoDB COMMENT = 'xDS工作负载证书签发CA';
+
 -- --------------------------------------------------------
 --
 -- Table structure `config_file_release_history`
@@ -643,6 +764,8 @@ CREATE TABLE `config_file_release_history`
     `md5`         varchar(128)    NOT NULL COMMENT 'content的md5值',
     `type`        varchar(32)     NOT NULL COMMENT '发布类型，例如全量发布、灰度发布',
     `status`      varchar(16)     NOT NULL DEFAULT 'success' COMMENT '发布状态，success表示成功，fail 表示失败',
+    `approvers`   varchar(1024)            DEFAULT '' COMMENT '审批通过本次发布的审批人，逗号分隔',
+    `source_history_id` bigint unsigned    DEFAULT 0 COMMENT '回滚发布时回滚到的历史发布 ID',
     `create_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     `create_by`   varchar(32)              DEFAULT NULL COMMENT '创建人',
     `modify_time` timestamp       NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '最后更新时间',
@@ -743,6 +866,7 @@ CREATE TABLE `auth_strategy`
     `id`       VARCHAR(128) NOT NULL comment 'Strategy ID',
     `name`     VARCHAR(100) NOT NULL comment 'Policy name',
     `action`   VARCHAR(32)  NOT NULL comment 'Read and write permission for this policy, only_read = 0, read_write = 1',
+    `effect`   VARCHAR(8)   NOT NULL DEFAULT 'ALLOW' comment 'Effect of this policy, ALLOW or DENY, DENY overrides ALLOW',
     `owner`    VARCHAR(128) NOT NULL comment 'The account ID to which this policy is',
     `comment`  VARCHAR(255) NOT NULL comment 'describe',
     `default`  tinyint(4)   NOT NULL DEFAULT '0',
@@ -989,4 +1113,41 @@ CREATE TABLE `fault_detect_rule`
     PRIMARY KEY (`id`),
     KEY `name` (`name`),
     KEY `mtime` (`mtime`)
-) engine = innodb;
\ No newline at end of file
+) engine = innodb;
+CREATE TABLE `traffic_policy`
+(
+    `id`          varchar(128)  NOT NULL COMMENT '流量策略ID',
+    `name`        varchar(64)   NOT NULL COMMENT '流量策略名称',
+    `namespace`   varchar(64)   NOT NULL COMMENT '策略所属服务的命名空间',
+    `service`     varchar(128)  NOT NULL COMMENT '策略所属服务的名称',
+    `route_name`  varchar(64)   NOT NULL DEFAULT '' COMMENT '生效的路由规则名称，为空时对服务的全部路由生效',
+    `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述',
+    `enable`      tinyint(4)    NOT NULL DEFAULT '0' COMMENT '是否启用',
+    `rule`        text COMMENT '超时、重试以及故障注入配置，json格式',
+    `revision`    varchar(40)   NOT NULL COMMENT '版本号',
+    `flag`        tinyint(4)    NOT NULL DEFAULT '0' COMMENT '是否被删除',
+    `ctime`       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Create time',
+    `mtime`       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last updated time',
+    PRIMARY KEY (`id`),
+    KEY `service` (`namespace`, `service`),
+    KEY `mtime` (`mtime`)
+) ENGINE = InnoDB COMMENT = '服务流量策略';
+
+CREATE TABLE `access_policy`
+(
+    `id`          varchar(128)  NOT NULL COMMENT '访问控制策略ID',
+    `name`        varchar(64)   NOT NULL COMMENT '访问控制策略名称',
+    `namespace`   varchar(64)   NOT NULL COMMENT '策略所属服务的命名空间',
+    `service`     varchar(128)  NOT NULL COMMENT '策略所属服务的名称',
+    `description` varchar(1024) NOT NULL DEFAULT '' COMMENT '描述',
+    `enable`      tinyint(4)    NOT NULL DEFAULT '0' COMMENT '是否启用',
+    `dry_run`     tinyint(4)    NOT NULL DEFAULT '0' COMMENT '是否只记录会被拒绝的请求，不做拦截',
+    `rule`        text COMMENT '允许访问的主调服务、路径以及方法，json格式',
+    `revision`    varchar(40)   NOT NULL COMMENT '版本号',
+    `flag`        tinyint(4)    NOT NULL DEFAULT '0' COMMENT '是否被删除',
+    `ctime`       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT 'Create time',
+    `mtime`       timestamp     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT 'Last updated time',
+    PRIMARY KEY (`id`),
+    KEY `service` (`namespace`, `service`),
+    KEY `mtime` (`mtime`)
+) ENGINE = InnoDB COMMENT = '服务访问控制策略';
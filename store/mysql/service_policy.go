@@ -0,0 +1,229 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+// servicePolicyColumns 挂载在服务上的策略的公共列，顺序与 servicePolicyTable 生成的 sql 保持一致
+var servicePolicyColumns = []string{"id", "name", "namespace", "service", "description", "enable", "rule",
+	"revision"}
+
+// servicePolicyTable 挂载在服务上的策略的存储表，各类策略共用增删改查的逻辑
+type servicePolicyTable[T model.ServicePolicyRule] struct {
+	table string
+	// label 事务的标签，同时用于日志
+	label string
+	// extraColumns 具体策略特有的列，追加在公共列之后
+	extraColumns []string
+	// extraValues 具体策略特有列的值，顺序与 extraColumns 一致
+	extraValues func(policy T) []interface{}
+	// newRow 创建空的策略以及特有列的扫描目标，after 在扫描完成之后回填特有字段
+	newRow func() (policy T, dest []interface{}, after func())
+	// extraFilters 具体策略特有的过滤条件以及对应的列
+	extraFilters map[string]string
+}
+
+func (tbl *servicePolicyTable[T]) insertSQL() string {
+	columns := append(append([]string{}, servicePolicyColumns...), tbl.extraColumns...)
+	return "insert into " + tbl.table + "(" + strings.Join(columns, ", ") + ", ctime, mtime) values(" +
+		PlaceholdersN(len(columns)) + ", sysdate(), sysdate())"
+}
+
+func (tbl *servicePolicyTable[T]) updateSQL() string {
+	columns := append(append([]string{}, servicePolicyColumns[1:]...), tbl.extraColumns...)
+	return "update " + tbl.table + " set " + strings.Join(columns, " = ?, ") +
+		" = ?, mtime = sysdate() where id = ?"
+}
+
+func (tbl *servicePolicyTable[T]) querySQL() string {
+	columns := append(append([]string{}, servicePolicyColumns...), tbl.extraColumns...)
+	return "select " + strings.Join(columns, ", ") + ", flag, unix_timestamp(ctime), unix_timestamp(mtime) from " +
+		tbl.table
+}
+
+func (tbl *servicePolicyTable[T]) values(item T) []interface{} {
+	policy := item.GetServicePolicy()
+	return append([]interface{}{policy.Name, policy.Namespace, policy.Service, policy.Description,
+		boolToInt(policy.Enable), policy.Rule, policy.Revision}, tbl.extraValues(item)...)
+}
+
+// create 新增策略
+func (tbl *servicePolicyTable[T]) create(master *BaseDB, item T) error {
+	policy := item.GetServicePolicy()
+	label := "create" + tbl.label
+	args := append([]interface{}{policy.ID}, tbl.values(item)...)
+	return tbl.exec(master, label, policy.ID, tbl.insertSQL(), args...)
+}
+
+// update 更新策略
+func (tbl *servicePolicyTable[T]) update(master *BaseDB, item T) error {
+	policy := item.GetServicePolicy()
+	label := "update" + tbl.label
+	args := append(tbl.values(item), policy.ID)
+	return tbl.exec(master, label, policy.ID, tbl.updateSQL(), args...)
+}
+
+// delete 删除策略，只做逻辑删除，便于缓存增量感知
+func (tbl *servicePolicyTable[T]) delete(master *BaseDB, id string) error {
+	label := "delete" + tbl.label
+	return tbl.exec(master, label, id, "update "+tbl.table+" set flag = 1, mtime = sysdate() where id = ?", id)
+}
+
+func (tbl *servicePolicyTable[T]) exec(master *BaseDB, label string, id string, str string,
+	args ...interface{}) error {
+	err := RetryTransaction(label, func() error {
+		return master.processWithTransaction(label, func(tx *BaseTx) error {
+			if _, err := tx.Exec(str, args...); err != nil {
+				log.Errorf("[Store][database] fail to %s exec sql, policy(%s), err: %s", label, id, err.Error())
+				return err
+			}
+			return tx.Commit()
+		})
+	})
+	return store.Error(err)
+}
+
+// getWithID 根据ID获取策略，策略不存在或者已经删除时返回空
+func (tbl *servicePolicyTable[T]) getWithID(master *BaseDB, id string) (T, error) {
+	var empty T
+	rows, err := master.Query(tbl.querySQL()+" where flag = 0 and id = ?", id)
+	if err != nil {
+		log.Errorf("[Store][database] query %s(%s) err: %s", tbl.table, id, err.Error())
+		return empty, store.Error(err)
+	}
+	out, err := tbl.fetchRows(rows)
+	if err != nil {
+		return empty, store.Error(err)
+	}
+	if len(out) == 0 {
+		return empty, nil
+	}
+	return out[0], nil
+}
+
+// list 根据过滤条件查询策略，name 为模糊查询，按照修改时间倒序
+func (tbl *servicePolicyTable[T]) list(master *BaseDB, filter map[string]string,
+	offset uint32, limit uint32) (uint32, []T, error) {
+	where, args := tbl.genFilterSQL(filter)
+
+	var total uint32
+	if err := master.QueryRow("select count(*) from "+tbl.table+" where flag = 0"+where, args...).
+		Scan(&total); err != nil && err != sql.ErrNoRows {
+		log.Errorf("[Store][database] get %s count err: %s", tbl.table, err.Error())
+		return 0, nil, store.Error(err)
+	}
+
+	args = append(args, offset, limit)
+	rows, err := master.Query(tbl.querySQL()+" where flag = 0"+where+" order by mtime desc limit ?, ?", args...)
+	if err != nil {
+		log.Errorf("[Store][database] query %s err: %s", tbl.table, err.Error())
+		return 0, nil, store.Error(err)
+	}
+	out, err := tbl.fetchRows(rows)
+	if err != nil {
+		return 0, nil, store.Error(err)
+	}
+	return total, out, nil
+}
+
+// listForCache 根据修改时间拉取增量策略
+func (tbl *servicePolicyTable[T]) listForCache(slave *BaseDB, mtime time.Time, firstUpdate bool) ([]T, error) {
+	str := tbl.querySQL() + " where mtime > FROM_UNIXTIME(?)"
+	if firstUpdate {
+		str += " and flag != 1"
+	}
+	rows, err := slave.Query(str, timeToTimestamp(mtime))
+	if err != nil {
+		log.Errorf("[Store][database] query %s with mtime err: %s", tbl.table, err.Error())
+		return nil, store.Error(err)
+	}
+	out, err := tbl.fetchRows(rows)
+	if err != nil {
+		return nil, store.Error(err)
+	}
+	return out, nil
+}
+
+func (tbl *servicePolicyTable[T]) genFilterSQL(filter map[string]string) (string, []interface{}) {
+	str := ""
+	args := make([]interface{}, 0, len(filter))
+	for key, value := range filter {
+		column, ok := tbl.extraFilters[key]
+		switch key {
+		case "id", "name", "namespace", "service":
+			column, ok = key, true
+		}
+		if !ok || value == "" {
+			continue
+		}
+		if key == "name" {
+			str += " and name like ?"
+			args = append(args, "%"+value+"%")
+			continue
+		}
+		str += " and " + column + " = ?"
+		args = append(args, value)
+	}
+	return str, args
+}
+
+func (tbl *servicePolicyTable[T]) fetchRows(rows *sql.Rows) ([]T, error) {
+	defer func() {
+		_ = rows.Close()
+	}()
+	out := make([]T, 0, 8)
+	for rows.Next() {
+		var (
+			enable, flag         int
+			ctime, mtime         int64
+			description, ruleStr sql.NullString
+		)
+		item, extra, after := tbl.newRow()
+		policy := item.GetServicePolicy()
+		dest := []interface{}{&policy.ID, &policy.Name, &policy.Namespace, &policy.Service, &description,
+			&enable, &ruleStr, &policy.Revision}
+		dest = append(dest, extra...)
+		dest = append(dest, &flag, &ctime, &mtime)
+		if err := rows.Scan(dest...); err != nil {
+			log.Errorf("[Store][database] fetch %s scan err: %s", tbl.table, err.Error())
+			return nil, err
+		}
+		policy.Description = description.String
+		policy.Rule = ruleStr.String
+		policy.Enable = enable == 1
+		policy.Valid = flag == 0
+		policy.CreateTime = time.Unix(ctime, 0)
+		policy.ModifyTime = time.Unix(mtime, 0)
+		if after != nil {
+			after()
+		}
+		out = append(out, item)
+	}
+	if err := rows.Err(); err != nil {
+		log.Errorf("[Store][database] fetch %s next err: %s", tbl.table, err.Error())
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// TestServicePolicyTableSQL 挂载在服务上的策略共用的 sql 构造测试
+func TestServicePolicyTableSQL(t *testing.T) {
+	Convey("特有列追加在公共列之后", t, func() {
+		So(trafficPolicyTable.insertSQL(), ShouldEqual, "insert into traffic_policy(id, name, namespace, service, "+
+			"description, enable, rule, revision, route_name, ctime, mtime) values(?,?,?,?,?,?,?,?,?, sysdate(), sysdate())")
+		So(trafficPolicyTable.updateSQL(), ShouldEqual, "update traffic_policy set name = ?, namespace = ?, "+
+			"service = ?, description = ?, enable = ?, rule = ?, revision = ?, route_name = ?, mtime = sysdate() where id = ?")
+		So(accessPolicyTable.insertSQL(), ShouldEqual, "insert into access_policy(id, name, namespace, service, "+
+			"description, enable, rule, revision, dry_run, ctime, mtime) values(?,?,?,?,?,?,?,?,?, sysdate(), sysdate())")
+		So(accessPolicyTable.updateSQL(), ShouldEqual, "update access_policy set name = ?, namespace = ?, "+
+			"service = ?, description = ?, enable = ?, rule = ?, revision = ?, dry_run = ?, mtime = sysdate() where id = ?")
+		So(trafficPolicyTable.querySQL(), ShouldEqual, "select id, name, namespace, service, description, enable, "+
+			"rule, revision, route_name, flag, unix_timestamp(ctime), unix_timestamp(mtime) from traffic_policy")
+	})
+	Convey("只有支持的过滤条件才会生成 sql", t, func() {
+		where, args := trafficPolicyTable.genFilterSQL(map[string]string{"name": "echo", "route_name": "gray",
+			"unknown": "x"})
+		So(where, ShouldContainSubstring, " and name like ?")
+		So(where, ShouldContainSubstring, " and route_name = ?")
+		So(len(args), ShouldEqual, 2)
+
+		where, args = accessPolicyTable.genFilterSQL(map[string]string{"route_name": "gray"})
+		So(where, ShouldBeEmpty)
+		So(len(args), ShouldEqual, 0)
+	})
+}
@@ -92,12 +92,12 @@ func (s *strategyStore) addStrategy(strategy *model.StrategyDetail) error {
 	}
 
 	// 保存策略主信息
-	saveMainSql := "INSERT INTO auth_strategy(`id`, `name`, `action`, `owner`, `comment`, `flag`, " +
-		" `default`, `revision`) VALUES (?,?,?,?,?,?,?,?)"
+	saveMainSql := "INSERT INTO auth_strategy(`id`, `name`, `action`, `effect`, `owner`, `comment`, `flag`, " +
+		" `default`, `revision`) VALUES (?,?,?,?,?,?,?,?,?)"
 	if _, err = tx.Exec(saveMainSql,
 		[]interface{}{
-			strategy.ID, strategy.Name, strategy.Action, strategy.Owner, strategy.Comment,
-			0, isDefault, strategy.Revision}...,
+			strategy.ID, strategy.Name, strategy.Action, strategyEffect(strategy.Effect), strategy.Owner,
+			strategy.Comment, 0, isDefault, strategy.Revision}...,
 	); err != nil {
 		log.Error("[Store][Strategy] add auth_strategy main info", zap.Error(err))
 		return err
@@ -152,8 +152,9 @@ func (s *strategyStore) updateStrategy(strategy *model.ModifyStrategyDetail) err
 	}
 
 	// 保存策略主信息
-	saveMainSql := "UPDATE auth_strategy SET action = ?, comment = ?, mtime = sysdate() WHERE id = ?"
-	if _, err = tx.Exec(saveMainSql, []interface{}{strategy.Action, strategy.Comment, strategy.ID}...); err != nil {
+	saveMainSql := "UPDATE auth_strategy SET action = ?, effect = ?, comment = ?, mtime = sysdate() WHERE id = ?"
+	if _, err = tx.Exec(saveMainSql, []interface{}{strategy.Action, strategyEffect(strategy.Effect),
+		strategy.Comment, strategy.ID}...); err != nil {
 		log.Error("[Store][Strategy] update strategy main info", zap.Error(err))
 		return err
 	}
@@ -385,7 +386,8 @@ func (s *strategyStore) GetStrategyDetail(id string) (*model.StrategyDetail, err
 			"get auth_strategy missing some params, id is %s", id))
 	}
 
-	querySql := "SELECT ag.id, ag.name, ag.action, ag.owner, ag.default, ag.comment, ag.revision, ag.flag, " +
+	querySql := "SELECT ag.id, ag.name, ag.action, ag.effect, ag.owner, ag.default, ag.comment, ag.revision, " +
+		" ag.flag, " +
 		" UNIX_TIMESTAMP(ag.ctime), UNIX_TIMESTAMP(ag.mtime) FROM auth_strategy AS ag WHERE ag.flag = 0 AND ag.id = ?"
 
 	row := s.master.QueryRow(querySql, id)
@@ -403,7 +405,7 @@ func (s *strategyStore) GetDefaultStrategyDetailByPrincipal(principalId string,
 	}
 
 	querySql := `
-	 SELECT ag.id, ag.name, ag.action, ag.owner, ag.default
+	 SELECT ag.id, ag.name, ag.action, ag.effect, ag.owner, ag.default
 		 , ag.comment, ag.revision, ag.flag, UNIX_TIMESTAMP(ag.ctime)
 		 , UNIX_TIMESTAMP(ag.mtime)
 	 FROM auth_strategy ag
@@ -428,7 +430,7 @@ func (s *strategyStore) getStrategyDetail(row *sql.Row) (*model.StrategyDetail,
 		isDefault, flag int16
 	)
 	ret := new(model.StrategyDetail)
-	if err := row.Scan(&ret.ID, &ret.Name, &ret.Action, &ret.Owner, &isDefault, &ret.Comment,
+	if err := row.Scan(&ret.ID, &ret.Name, &ret.Action, &ret.Effect, &ret.Owner, &isDefault, &ret.Comment,
 		&ret.Revision, &flag, &ctime, &mtime); err != nil {
 		switch err {
 		case sql.ErrNoRows:
@@ -477,6 +479,7 @@ func (s *strategyStore) listStrategies(filters map[string]string, offset uint32,
 			 ag.id,
 			 ag.name,
 			 ag.action,
+			 ag.effect,
 			 ag.owner,
 			 ag.comment,
 			 ag.default,
@@ -628,8 +631,8 @@ func (s *strategyStore) GetStrategyDetailsForCache(mtime time.Time,
 	defer func() { _ = tx.Commit() }()
 
 	args := make([]interface{}, 0)
-	querySql := "SELECT ag.id, ag.name, ag.action, ag.owner, ag.comment, ag.default, ag.revision, ag.flag, " +
-		" UNIX_TIMESTAMP(ag.ctime), UNIX_TIMESTAMP(ag.mtime) FROM auth_strategy ag "
+	querySql := "SELECT ag.id, ag.name, ag.action, ag.effect, ag.owner, ag.comment, ag.default, ag.revision, " +
+		" ag.flag, UNIX_TIMESTAMP(ag.ctime), UNIX_TIMESTAMP(ag.mtime) FROM auth_strategy ag "
 
 	if !firstUpdate {
 		querySql += " WHERE ag.mtime >= FROM_UNIXTIME(?)"
@@ -673,7 +676,8 @@ func (s *strategyStore) GetStrategyDetailsForCache(mtime time.Time,
 func (s *strategyStore) GetStrategyResources(principalId string,
 	principalRole model.PrincipalType) ([]model.StrategyResource, error) {
 
-	querySql := "SELECT res_id, res_type FROM auth_strategy_resource WHERE strategy_id IN (SELECT DISTINCT " +
+	querySql := "SELECT strategy_id, res_id, res_type FROM auth_strategy_resource WHERE strategy_id IN " +
+		" (SELECT DISTINCT " +
 		" ap.strategy_id FROM auth_principal ap join auth_strategy ar ON ap.strategy_id = ar.id WHERE ar.flag = 0 " +
 		" AND ap.principal_id = ? AND ap.principal_role = ? )"
 
@@ -695,7 +699,7 @@ func (s *strategyStore) GetStrategyResources(principalId string,
 
 	for rows.Next() {
 		res := new(model.StrategyResource)
-		if err := rows.Scan(&res.ResID, &res.ResType); err != nil {
+		if err := rows.Scan(&res.StrategyID, &res.ResID, &res.ResType); err != nil {
 			return nil, store.Error(err)
 		}
 		resArr = append(resArr, *res)
@@ -767,8 +771,8 @@ func fetchRown2StrategyDetail(rows *sql.Rows) (*model.StrategyDetail, error) {
 		Resources: make([]model.StrategyResource, 0),
 	}
 
-	if err := rows.Scan(&ret.ID, &ret.Name, &ret.Action, &ret.Owner, &ret.Comment, &isDefault, &ret.Revision, &flag,
-		&ctime, &mtime); err != nil {
+	if err := rows.Scan(&ret.ID, &ret.Name, &ret.Action, &ret.Effect, &ret.Owner, &ret.Comment, &isDefault,
+		&ret.Revision, &flag, &ctime, &mtime); err != nil {
 		return nil, store.Error(err)
 	}
 
@@ -866,3 +870,11 @@ func cleanLinkStrategy(tx *BaseTx, role model.PrincipalType, principalId, owner
 
 	return nil
 }
+
+// strategyEffect 历史数据以及未指定生效方式的策略均为授权策略
+func strategyEffect(effect string) string {
+	if effect == "" {
+		return model.StrategyEffectAllow
+	}
+	return effect
+}
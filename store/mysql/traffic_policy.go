@@ -0,0 +1,80 @@
+/**
+ * Tencent is pleased to support the open source community by making Polaris available.
+ *
+ * Copyright (C) 2019 THL A29 Limited, a Tencent company. All rights reserved.
+ *
+ * Licensed under the BSD 3-Clause License (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * https://opensource.org/licenses/BSD-3-Clause
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed
+ * under the License is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR
+ * CONDITIONS OF ANY KIND, either express or implied. See the License for the
+ * specific language governing permissions and limitations under the License.
+ */
+
+package sqldb
+
+import (
+	"time"
+
+	"github.com/polarismesh/polaris/common/model"
+	"github.com/polarismesh/polaris/store"
+)
+
+var _ store.TrafficPolicyStore = (*trafficPolicyStore)(nil)
+
+type trafficPolicyStore struct {
+	master *BaseDB
+	slave  *BaseDB
+}
+
+var trafficPolicyTable = &servicePolicyTable[*model.TrafficPolicy]{
+	table:        "traffic_policy",
+	label:        "TrafficPolicy",
+	extraColumns: []string{"route_name"},
+	extraValues: func(policy *model.TrafficPolicy) []interface{} {
+		return []interface{}{policy.RouteName}
+	},
+	newRow: func() (*model.TrafficPolicy, []interface{}, func()) {
+		policy := &model.TrafficPolicy{}
+		return policy, []interface{}{&policy.RouteName}, nil
+	},
+	extraFilters: map[string]string{
+		"route_name": "route_name",
+	},
+}
+
+// CreateTrafficPolicy 新增流量策略
+func (t *trafficPolicyStore) CreateTrafficPolicy(policy *model.TrafficPolicy) error {
+	return trafficPolicyTable.create(t.master, policy)
+}
+
+// UpdateTrafficPolicy 更新流量策略
+func (t *trafficPolicyStore) UpdateTrafficPolicy(policy *model.TrafficPolicy) error {
+	return trafficPolicyTable.update(t.master, policy)
+}
+
+// DeleteTrafficPolicy 删除流量策略
+func (t *trafficPolicyStore) DeleteTrafficPolicy(id string) error {
+	return trafficPolicyTable.delete(t.master, id)
+}
+
+// GetTrafficPolicyWithID 根据ID获取流量策略
+func (t *trafficPolicyStore) GetTrafficPolicyWithID(id string) (*model.TrafficPolicy, error) {
+	return trafficPolicyTable.getWithID(t.master, id)
+}
+
+// GetTrafficPolicies 根据过滤条件查询流量策略
+func (t *trafficPolicyStore) GetTrafficPolicies(filter map[string]string,
+	offset uint32, limit uint32) (uint32, []*model.TrafficPolicy, error) {
+	return trafficPolicyTable.list(t.master, filter, offset, limit)
+}
+
+// GetTrafficPoliciesForCache 根据修改时间拉取增量流量策略
+func (t *trafficPolicyStore) GetTrafficPoliciesForCache(mtime time.Time,
+	firstUpdate bool) ([]*model.TrafficPolicy, error) {
+	return trafficPolicyTable.listForCache(t.slave, mtime, firstUpdate)
+}